@@ -178,6 +178,11 @@ GO_DEPENDENCIES = {
             "tag": "v1.2",
             "importpath": "github.com/gin-gonic/gin",
         },
+        "github.com/miekg/dns": {
+            "name": "com_github_miekg_dns",
+            "tag": "v1.0.14",
+            "importpath": "github.com/miekg/dns",
+        },
         "github.com/olekukonko/tablewriter": {
             "name": "com_github_olekukonko_tablewriter",
             "commit": "be2c049b30ccd4d3fd795d6bf7dce74e42eeedaa",
@@ -15,11 +15,12 @@ import (
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	kuberesolver "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/definition/component/resolver"
+	kubesecretprovider "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/secretprovider"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 	"github.com/mlab-lattice/lattice/pkg/util/cli"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
 
-	kubeinformers "k8s.io/client-go/informers"
 	kubeclientset "k8s.io/client-go/kubernetes"
 	kuberest "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -41,6 +42,9 @@ func Command() *cli.RootCommand {
 	var workDirectory string
 	var port int32
 	var tokenAuthFile string
+	var secretProvider string
+
+	secretProviderFlag, secretProviderOptions := secretprovider.Flag(&secretProvider)
 
 	command := &cli.RootCommand{
 		Name: "api-server",
@@ -69,6 +73,11 @@ func Command() *cli.RootCommand {
 					Usage:  "path for token file for bearer token authenticator",
 					Target: &tokenAuthFile,
 				},
+				"secret-provider": &flags.String{
+					Usage:  "external secret provider to use in addition to kubernetes",
+					Target: &secretProvider,
+				},
+				"secret-provider-var": secretProviderFlag,
 			},
 			Run: func(args []string, flags cli.Flags) error {
 				// https://github.com/kubernetes/kubernetes/issues/17162#issuecomment-225596212
@@ -97,12 +106,16 @@ func Command() *cli.RootCommand {
 
 				setupSSH()
 
-				backend := backend.NewKubernetesBackend(namespacePrefix, kubeClient, latticeClient)
+				secretProviders, err := kubesecretprovider.NewProviders(namespacePrefix, kubeClient, secretProviderOptions)
+				if err != nil {
+					return err
+				}
+
+				backend := backend.NewKubernetesBackend(namespacePrefix, config, kubeClient, latticeClient, secretProviders)
 
 				latticeInformers := latticeinformers.NewSharedInformerFactory(latticeClient, time.Duration(12*time.Hour))
-				kubeInformers := kubeinformers.NewSharedInformerFactory(kubeClient, time.Duration(12*time.Hour))
 				templateStore := kuberesolver.NewKubernetesTemplateStore(namespacePrefix, latticeClient, latticeInformers, nil)
-				secretStore := kuberesolver.NewKubernetesSecretStore(namespacePrefix, kubeInformers, nil)
+				secretStore := resolver.NewProviderSecretStore(secretProviders)
 				gitResolver, err := git.NewResolver(workDirectory, false)
 				if err != nil {
 					return err
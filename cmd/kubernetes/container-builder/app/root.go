@@ -36,6 +36,11 @@ func Command() *cli.RootCommand {
 		dockerTag              string
 		dockerPush             bool
 
+		imageBuilder string
+
+		cacheDirectory        string
+		dockerCacheRepository string
+
 		kubeconfig               string
 		containerBuildDefinition string
 	)
@@ -90,6 +95,19 @@ func Command() *cli.RootCommand {
 					Default: false,
 					Target:  &dockerPush,
 				},
+				"image-builder": &flags.String{
+					Usage:   "image builder to use for docker image and command builds, docker or daemonless",
+					Default: string(containerbuilder.ImageBuilderDocker),
+					Target:  &imageBuilder,
+				},
+				"cache-directory": &flags.String{
+					Usage:  "path to the directory persisted between builds to cache git repositories in",
+					Target: &cacheDirectory,
+				},
+				"docker-cache-repository": &flags.String{
+					Usage:  "repository to store the images used to cache docker layers between builds in",
+					Target: &dockerCacheRepository,
+				},
 				"kubeconfig": &flags.String{
 					Usage:  "path to kubeconfig",
 					Target: &kubeconfig,
@@ -132,6 +150,14 @@ func Command() *cli.RootCommand {
 					}
 				}
 
+				var cacheOptions *containerbuilder.CacheOptions
+				if cacheDirectory != "" || dockerCacheRepository != "" {
+					cacheOptions = &containerbuilder.CacheOptions{
+						Directory:        cacheDirectory,
+						DockerRepository: dockerCacheRepository,
+					}
+				}
+
 				setupSSH()
 
 				builder, err := containerbuilder.NewBuilder(
@@ -140,6 +166,8 @@ func Command() *cli.RootCommand {
 					workDirectory,
 					dockerOptions,
 					gitResolverOptions,
+					cacheOptions,
+					containerbuilder.ImageBuilderType(imageBuilder),
 					statusUpdater,
 				)
 				if err != nil {
@@ -14,12 +14,17 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cloudprovider"
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	kuberesolver "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/definition/component/resolver"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider"
+	kubesecretprovider "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/secretprovider"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 	"github.com/mlab-lattice/lattice/pkg/util/cli"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
 	"github.com/mlab-lattice/lattice/pkg/util/git"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -30,6 +35,8 @@ import (
 
 const (
 	sshAuthSockEnvVarName = "SSH_AUTH_SOCK"
+
+	leaderElectionLockName = "controller-manager"
 )
 
 func Command() *cli.RootCommand {
@@ -48,11 +55,18 @@ func Command() *cli.RootCommand {
 
 		enabledControllers []string
 
-		cloudProvider string
-		serviceMesh   string
+		leaderElect          bool
+		leaderElectNamespace string
+
+		cloudProvider  string
+		dnsProvider    string
+		secretProvider string
+		serviceMesh    string
 	)
 
 	cloudProviderFlag, cloudProviderOptions := cloudprovider.Flag(&cloudProvider)
+	dnsProviderFlag, dnsProviderOptions := dnsprovider.Flag(&dnsProvider)
+	secretProviderFlag, secretProviderOptions := secretprovider.Flag(&secretProvider)
 	serviceMeshFlag, serviceMeshOptions := servicemesh.Flag(&serviceMesh)
 
 	command := &cli.RootCommand{
@@ -88,6 +102,14 @@ func Command() *cli.RootCommand {
 					Default: []string{"*"},
 					Target:  &enabledControllers,
 				},
+				"leader-elect": &flags.Bool{
+					Usage:  "only run controllers while holding the leader lease, so that multiple replicas can be run",
+					Target: &leaderElect,
+				},
+				"leader-elect-namespace": &flags.String{
+					Usage:  "namespace of the config map storing the leader lease (defaults to the lattice's internal namespace)",
+					Target: &leaderElectNamespace,
+				},
 
 				"cloud-provider": &flags.String{
 					Required: true,
@@ -96,6 +118,18 @@ func Command() *cli.RootCommand {
 				},
 				"cloud-provider-var": cloudProviderFlag,
 
+				"dns-provider": &flags.String{
+					Target: &dnsProvider,
+					Usage:  "dns provider to use instead of the cloud provider's",
+				},
+				"dns-provider-var": dnsProviderFlag,
+
+				"secret-provider": &flags.String{
+					Target: &secretProvider,
+					Usage:  "external secret provider to use in addition to kubernetes",
+				},
+				"secret-provider-var": secretProviderFlag,
+
 				"service-mesh": &flags.String{
 					Required: true,
 					Target:   &serviceMesh,
@@ -117,7 +151,6 @@ func Command() *cli.RootCommand {
 
 				setupSSH()
 
-				// TODO: setting stop as nil for now, won't actually need it until leader-election is used
 				ctx, err := createControllerContext(
 					namespacePrefix,
 					workDirectory,
@@ -125,21 +158,40 @@ func Command() *cli.RootCommand {
 					internalDNSDomain,
 					config,
 					cloudProviderOptions,
+					dnsProviderOptions,
+					secretProviderOptions,
 					serviceMeshOptions,
-					nil,
 				)
 				if err != nil {
 					return err
 				}
 
-				glog.V(1).Info("Starting enabled controllers")
-				startControllers(ctx, enabledControllers)
+				run := func(stop <-chan struct{}) {
+					ctx.Stop = stop
+
+					glog.V(1).Info("Starting enabled controllers")
+					startControllers(ctx, enabledControllers)
+
+					glog.V(4).Info("Starting informer factory kubeinformers")
+					ctx.KubeInformerFactory.Start(ctx.Stop)
+					ctx.LatticeInformerFactory.Start(ctx.Stop)
+				}
+
+				if !leaderElect {
+					run(wait.NeverStop)
+					select {}
+				}
 
-				glog.V(4).Info("Starting informer factory kubeinformers")
-				ctx.KubeInformerFactory.Start(ctx.Stop)
-				ctx.LatticeInformerFactory.Start(ctx.Stop)
+				if leaderElectNamespace == "" {
+					leaderElectNamespace = kubeutil.InternalNamespace(namespacePrefix)
+				}
 
-				select {}
+				return kubeutil.RunWithLeaderElection(
+					ctx.KubeClientBuilder.ClientOrDie("controller-manager-leader-election"),
+					leaderElectNamespace,
+					leaderElectionLockName,
+					run,
+				)
 			},
 		},
 	}
@@ -154,8 +206,9 @@ func createControllerContext(
 	internalDNSDomain string,
 	kubeconfig *rest.Config,
 	cloudProviderOptions *cloudprovider.Options,
+	dnsProviderOptions *dnsprovider.Options,
+	secretProviderOptions *secretprovider.Options,
 	serviceMeshOptions *servicemesh.Options,
-	stop <-chan struct{},
 ) (controllers.Context, error) {
 	kcb := controllers.KubeClientBuilder{
 		Kubeconfig: kubeconfig,
@@ -171,7 +224,16 @@ func createControllerContext(
 	latticeInformers := latticeinformers.NewSharedInformerFactory(versionedLatticeClient, time.Duration(12*time.Hour))
 
 	templateStore := kuberesolver.NewKubernetesTemplateStore(namespacePrefix, lcb.ClientOrDie("controller-manager-component-resolver"), latticeInformers, nil)
-	secretStore := kuberesolver.NewKubernetesSecretStore(namespacePrefix, kubeInformers, nil)
+	secretProviders, err := kubesecretprovider.NewProviders(
+		namespacePrefix,
+		kcb.ClientOrDie("controller-manager-secret-providers"),
+		secretProviderOptions,
+	)
+	if err != nil {
+		return controllers.Context{}, err
+	}
+
+	secretStore := resolver.NewProviderSecretStore(secretProviders)
 	gitResolver, err := git.NewResolver(workDirectory, false)
 	if err != nil {
 		return controllers.Context{}, err
@@ -183,18 +245,20 @@ func createControllerContext(
 		LatticeID:       latticeID,
 
 		ComponentResolver: r,
+		GitResolver:       gitResolver,
 
 		InternalDNSDomain: internalDNSDomain,
 
 		CloudProviderOptions: cloudProviderOptions,
+		DNSProviderOptions:   dnsProviderOptions,
 		ServiceMeshOptions:   serviceMeshOptions,
 
+		SecretProviders: secretProviders,
+
 		KubeInformerFactory:    kubeInformers,
 		LatticeInformerFactory: latticeInformers,
 		KubeClientBuilder:      kcb,
 		LatticeClientBuilder:   lcb,
-
-		Stop: stop,
 	}
 	return ctx, nil
 }
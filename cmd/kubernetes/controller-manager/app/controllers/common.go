@@ -5,8 +5,11 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cloudprovider"
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
 
 	kubeinformers "k8s.io/client-go/informers"
 	kubeclientset "k8s.io/client-go/kubernetes"
@@ -50,9 +53,17 @@ type Context struct {
 
 	ComponentResolver resolver.Interface
 
+	// GitResolver is the git resolver used by the ComponentResolver.
+	GitResolver *git.Resolver
+
 	CloudProviderOptions *cloudprovider.Options
+	DNSProviderOptions   *dnsprovider.Options
 	ServiceMeshOptions   *servicemesh.Options
 
+	// SecretProviders are the providers that secrets referenced by
+	// workloads are retrieved from.
+	SecretProviders *secretprovider.Providers
+
 	// KubeInformerFactory gives access to base kubernetes kubeinformers.
 	KubeInformerFactory kubeinformers.SharedInformerFactory
 
@@ -35,6 +35,7 @@ func initializeAddressController(ctx Context) {
 		ctx.NamespacePrefix,
 		ctx.LatticeID,
 		ctx.CloudProviderOptions,
+		ctx.DNSProviderOptions,
 		ctx.ServiceMeshOptions,
 		ctx.KubeClientBuilder.ClientOrDie(controllerName(AddressController)),
 		ctx.LatticeClientBuilder.ClientOrDie(controllerName(AddressController)),
@@ -48,6 +49,8 @@ func initializeBuildController(ctx Context) {
 		ctx.NamespacePrefix,
 		ctx.LatticeClientBuilder.ClientOrDie(controllerName(BuildController)),
 		ctx.ComponentResolver,
+		ctx.SecretProviders,
+		ctx.GitResolver,
 		ctx.LatticeInformerFactory.Lattice().V1().Systems(),
 		ctx.LatticeInformerFactory.Lattice().V1().Builds(),
 		ctx.LatticeInformerFactory.Lattice().V1().ContainerBuilds(),
@@ -58,6 +61,7 @@ func initializeContainerBuildController(ctx Context) {
 	go containerbuild.NewController(
 		ctx.NamespacePrefix,
 		ctx.CloudProviderOptions,
+		ctx.SecretProviders,
 		ctx.KubeClientBuilder.ClientOrDie(controllerName(ContainerBuildController)),
 		ctx.LatticeClientBuilder.ClientOrDie(controllerName(ContainerBuildController)),
 		ctx.KubeInformerFactory,
@@ -72,6 +76,7 @@ func initializeJobController(ctx Context) {
 		ctx.InternalDNSDomain,
 		ctx.CloudProviderOptions,
 		ctx.ServiceMeshOptions,
+		ctx.SecretProviders,
 		ctx.KubeClientBuilder.ClientOrDie(controllerName(ContainerBuildController)),
 		ctx.LatticeClientBuilder.ClientOrDie(controllerName(ContainerBuildController)),
 		ctx.KubeInformerFactory,
@@ -98,6 +103,7 @@ func initializeServiceController(ctx Context) {
 		ctx.InternalDNSDomain,
 		ctx.CloudProviderOptions,
 		ctx.ServiceMeshOptions,
+		ctx.SecretProviders,
 		ctx.KubeClientBuilder.ClientOrDie(controllerName(ServiceController)),
 		ctx.LatticeClientBuilder.ClientOrDie(controllerName(ServiceController)),
 		ctx.KubeInformerFactory,
@@ -123,6 +129,7 @@ func initializeSystemLifecycleController(ctx Context) {
 		ctx.NamespacePrefix,
 		ctx.KubeClientBuilder.ClientOrDie(controllerName(SystemLifecycleController)),
 		ctx.LatticeClientBuilder.ClientOrDie(controllerName(SystemLifecycleController)),
+		ctx.SecretProviders,
 		ctx.LatticeInformerFactory.Lattice().V1().Deploys(),
 		ctx.LatticeInformerFactory.Lattice().V1().Teardowns(),
 		ctx.LatticeInformerFactory.Lattice().V1().Systems(),
@@ -27,6 +27,10 @@ const (
 	envVarXDSAPIHost              = "XDS_API_HOST"
 	envVarXDSAPIPort              = "XDS_API_PORT"
 
+	// optional, if set envoy will report traces to the collector
+	envVarTracingCollectorAddress  = "TRACING_COLLECTOR_ADDRESS"
+	envVarTracingCollectorEndpoint = "TRACING_COLLECTOR_ENDPOINT"
+
 	// XXX: needed for V2 config (`--service-cluster` and `--service-node` do not set this appropriately)
 	envVarServiceCluster = "SERVICE_CLUSTER"
 	envVarServiceNode    = "SERVICE_NODE"
@@ -35,6 +39,9 @@ const (
 	DefaultXDSClusterRefreshDelayMS   = 10000
 	DefaultXDSClusterConnectTimeoutMS = 250
 	DefaultXDSClusterConnectTimeout   = "0.25s"
+
+	DefaultTracingClusterName = "tracing-collector"
+	DefaultTracingDriverName  = "envoy.zipkin"
 )
 
 var envVars = []string{
@@ -107,6 +114,15 @@ func parseEnv() (map[string]string, error) {
 		}
 	}
 
+	// tracing is optional, but if a collector is configured the endpoint must be as well
+	if _, ok := os.LookupEnv(envVarTracingCollectorAddress); ok {
+		for _, envVar := range []string{envVarTracingCollectorAddress, envVarTracingCollectorEndpoint} {
+			if err := getEnvVar(envVar); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return env, nil
 }
 
@@ -275,6 +291,21 @@ type XDSV2BootstrapConfig struct {
 	Admin            XDSV2Admin            `json:"admin"`
 	StaticResources  XDSV2StaticResources  `json:"static_resources"`
 	DynamicResources XDSV2DynamicResources `json:"dynamic_resources"`
+	Tracing          *XDSV2Tracing         `json:"tracing,omitempty"`
+}
+
+type XDSV2Tracing struct {
+	HTTP XDSV2TracingHTTP `json:"http"`
+}
+
+type XDSV2TracingHTTP struct {
+	Name   string                 `json:"name"`
+	Config XDSV2TracingHTTPConfig `json:"config"`
+}
+
+type XDSV2TracingHTTPConfig struct {
+	CollectorCluster  string `json:"collector_cluster"`
+	CollectorEndpoint string `json:"collector_endpoint"`
 }
 
 type XDSV2Node struct {
@@ -296,7 +327,7 @@ type XDSV2Cluster struct {
 	ConnectTimeout       string      `json:"connect_timeout"`
 	Type                 string      `json:"type"`
 	LBPolicy             string      `json:"lb_policy"`
-	HTTP2ProtocolOptions struct{}    `json:"http2_protocol_options"`
+	HTTP2ProtocolOptions *struct{}   `json:"http2_protocol_options,omitempty"`
 	Hosts                []XDSV2Host `json:"hosts"`
 }
 
@@ -413,63 +444,115 @@ func outputEnvoyConfig(env map[string]string) error {
 			},
 		}, "", "  ")
 	case "2":
-		contents, err = json.MarshalIndent(XDSV2BootstrapConfig{
-			Node: XDSV2Node{
-				Id:      env[envVarServiceNode],
-				Cluster: env[envVarServiceCluster],
-			},
-			Admin: XDSV2Admin{
-				AccessLogPath: "/dev/null",
-				Address: XDSV2Address{
-					SocketAddress: XDSV2SocketAddress{
-						Address:   "0.0.0.0",
-						PortValue: adminPort,
-					},
+		var bootstrapConfig *XDSV2BootstrapConfig
+		bootstrapConfig, err = xdsV2BootstrapConfig(env, adminPort, xdsAPIPort)
+		if err != nil {
+			break
+		}
+
+		contents, err = json.MarshalIndent(bootstrapConfig, "", "  ")
+	default:
+		err = fmt.Errorf("unknown envoy boostrap config version: %v", env[envVarXDSAPIVersion])
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(configFilename, contents, 0644)
+}
+
+func xdsV2BootstrapConfig(env map[string]string, adminPort, xdsAPIPort int) (*XDSV2BootstrapConfig, error) {
+	config := &XDSV2BootstrapConfig{
+		Node: XDSV2Node{
+			Id:      env[envVarServiceNode],
+			Cluster: env[envVarServiceCluster],
+		},
+		Admin: XDSV2Admin{
+			AccessLogPath: "/dev/null",
+			Address: XDSV2Address{
+				SocketAddress: XDSV2SocketAddress{
+					Address:   "0.0.0.0",
+					PortValue: adminPort,
 				},
 			},
-			StaticResources: XDSV2StaticResources{
-				Clusters: []XDSV2Cluster{
-					{
-						Name:                 DefaultXDSClusterName,
-						ConnectTimeout:       DefaultXDSClusterConnectTimeout,
-						Type:                 "STATIC",
-						LBPolicy:             "ROUND_ROBIN",
-						HTTP2ProtocolOptions: struct{}{},
-						Hosts: []XDSV2Host{
-							{
-								SocketAddress: XDSV2SocketAddress{
-									Address:   env[envVarXDSAPIHost],
-									PortValue: xdsAPIPort,
-								},
+		},
+		StaticResources: XDSV2StaticResources{
+			Clusters: []XDSV2Cluster{
+				{
+					Name:                 DefaultXDSClusterName,
+					ConnectTimeout:       DefaultXDSClusterConnectTimeout,
+					Type:                 "STATIC",
+					LBPolicy:             "ROUND_ROBIN",
+					HTTP2ProtocolOptions: &struct{}{},
+					Hosts: []XDSV2Host{
+						{
+							SocketAddress: XDSV2SocketAddress{
+								Address:   env[envVarXDSAPIHost],
+								PortValue: xdsAPIPort,
 							},
 						},
 					},
 				},
 			},
-			DynamicResources: XDSV2DynamicResources{
-				ADSConfig: XDSV2ADSConfig{
-					APIType: "GRPC",
-					GRPCServices: []XDSV2GRPCService{
-						{
-							EnvoyGRPC: XDSV2EnvoyGRPC{
-								ClusterName: DefaultXDSClusterName,
-							},
+		},
+		DynamicResources: XDSV2DynamicResources{
+			ADSConfig: XDSV2ADSConfig{
+				APIType: "GRPC",
+				GRPCServices: []XDSV2GRPCService{
+					{
+						EnvoyGRPC: XDSV2EnvoyGRPC{
+							ClusterName: DefaultXDSClusterName,
 						},
 					},
 				},
-				LDSConfig: XDSV2LDSConfig{
-					ADS: struct{}{},
-				},
-				CDSConfig: XDSV2CDSConfig{
-					ADS: struct{}{},
-				},
 			},
-		}, "", "  ")
-	default:
-		err = fmt.Errorf("unknown envoy boostrap config version: %v", env[envVarXDSAPIVersion])
+			LDSConfig: XDSV2LDSConfig{
+				ADS: struct{}{},
+			},
+			CDSConfig: XDSV2CDSConfig{
+				ADS: struct{}{},
+			},
+		},
 	}
+
+	collectorAddress, ok := env[envVarTracingCollectorAddress]
+	if !ok {
+		return config, nil
+	}
+
+	collectorHost, collectorPortStr, err := net.SplitHostPort(collectorAddress)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid tracing collector address %v: %v", collectorAddress, err)
 	}
-	return ioutil.WriteFile(configFilename, contents, 0644)
+
+	collectorPort, err := strconv.Atoi(collectorPortStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing collector port %v: %v", collectorPortStr, err)
+	}
+
+	// the collector is likely addressed by a DNS name, so resolve it via STRICT_DNS
+	config.StaticResources.Clusters = append(config.StaticResources.Clusters, XDSV2Cluster{
+		Name:           DefaultTracingClusterName,
+		ConnectTimeout: DefaultXDSClusterConnectTimeout,
+		Type:           "STRICT_DNS",
+		LBPolicy:       "ROUND_ROBIN",
+		Hosts: []XDSV2Host{
+			{
+				SocketAddress: XDSV2SocketAddress{
+					Address:   collectorHost,
+					PortValue: collectorPort,
+				},
+			},
+		},
+	})
+	config.Tracing = &XDSV2Tracing{
+		HTTP: XDSV2TracingHTTP{
+			Name: DefaultTracingDriverName,
+			Config: XDSV2TracingHTTPConfig{
+				CollectorCluster:  DefaultTracingClusterName,
+				CollectorEndpoint: env[envVarTracingCollectorEndpoint],
+			},
+		},
+	}
+
+	return config, nil
 }
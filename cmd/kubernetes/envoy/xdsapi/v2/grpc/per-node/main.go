@@ -2,16 +2,28 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"net"
 
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/util"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/backend/pernode"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/grpc"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/golang/glog"
 )
 
 var (
 	kubeconfig        string
 	redirectCIDRBlock string
+
+	leaderElect          bool
+	leaderElectNamespace string
+	nodeName             string
 )
 
 // FIXME(kevindrosendahl): convert this to pkg/util/cli
@@ -19,6 +31,9 @@ func init() {
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "path to kubeconfig file")
 	// XXX <GEB>: should we be using cli here?
 	flag.StringVar(&redirectCIDRBlock, "redirect-cidr-block", "", "overlay network CIDR block")
+	flag.BoolVar(&leaderElect, "leader-elect", false, "only serve while holding the leader lease for the node, so that replacement pods don't serve alongside the pods they replace")
+	flag.StringVar(&leaderElectNamespace, "leader-elect-namespace", "", "namespace of the config map storing the leader lease")
+	flag.StringVar(&nodeName, "node-name", "", "name of the node the xds-api is serving")
 	flag.Parse()
 }
 
@@ -30,10 +45,50 @@ func main() {
 
 	stopCh := util.SetupSignalHandler()
 
-	backend, err := pernode.NewKubernetesPerNodeBackend(kubeconfig, net, stopCh)
+	run := func(stop <-chan struct{}) {
+		backend, err := pernode.NewKubernetesPerNodeBackend(kubeconfig, net, stop)
+		if err != nil {
+			panic(err)
+		}
+
+		grpc.RunNewGRPCServer(backend, 8080, stop)
+	}
+
+	if !leaderElect {
+		run(stopCh)
+		return
+	}
+
+	if leaderElectNamespace == "" || nodeName == "" {
+		panic("-leader-elect-namespace and -node-name are required when -leader-elect is set")
+	}
+
+	var config *rest.Config
+	if kubeconfig == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	rest.AddUserAgent(config, "envoy-api-leader-election")
+	kubeClient, err := kubeclientset.NewForConfig(config)
 	if err != nil {
 		panic(err)
 	}
 
-	grpc.RunNewGRPCServer(backend, 8080, stopCh)
+	// the xds-api serves the envoys on its own node, so each node has its own lease
+	go func() {
+		err := kubeutil.RunWithLeaderElection(
+			kubeClient,
+			leaderElectNamespace,
+			fmt.Sprintf("service-mesh-envoy-xds-api-%v", nodeName),
+			run,
+		)
+		glog.Fatal(err)
+	}()
+
+	<-stopCh
 }
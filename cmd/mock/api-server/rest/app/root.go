@@ -1,13 +1,20 @@
 package app
 
 import (
+	"encoding/json"
 	goflag "flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/mlab-lattice/lattice/pkg/api/server/authentication/authenticator/token/tokenfile"
 	"github.com/mlab-lattice/lattice/pkg/api/server/rest"
 	mockbackend "github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend"
 	mockresolver "github.com/mlab-lattice/lattice/pkg/backend/mock/definition/component/resolver"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider/encryptedfile"
 	"github.com/mlab-lattice/lattice/pkg/util/cli"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
 	"github.com/mlab-lattice/lattice/pkg/util/git"
@@ -20,11 +27,17 @@ func Command() *cli.RootCommand {
 	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
 
 	var (
-		port          int32
-		tokenAuthFile string
-		workDirectory string
+		port              int32
+		tokenAuthFile     string
+		workDirectory     string
+		secretsFile       string
+		secretsPassphrase string
+		secretProvider    string
+		costModelFile     string
 	)
 
+	secretProviderFlag, secretProviderOptions := secretprovider.Flag(&secretProvider)
+
 	command := &cli.RootCommand{
 		Name: "api-server",
 		Command: &cli.Command{
@@ -44,17 +57,49 @@ func Command() *cli.RootCommand {
 					Default: "/tmp/lattice/mock/api-server",
 					Target:  &workDirectory,
 				},
+				"secrets-file": &flags.String{
+					Usage:  "path of the encrypted file to store secrets in, defaults to a file in the work directory",
+					Target: &secretsFile,
+				},
+				"secrets-passphrase": &flags.String{
+					Usage:   "passphrase used to encrypt the secrets file",
+					Default: "lattice-mock-insecure",
+					Target:  &secretsPassphrase,
+				},
+				"secret-provider": &flags.String{
+					Usage:  "additional secret provider to use",
+					Target: &secretProvider,
+				},
+				"secret-provider-var": secretProviderFlag,
+				"cost-model-file": &flags.String{
+					Usage:  "path of a JSON file with the hourly prices of instance types used to estimate costs",
+					Target: &costModelFile,
+				},
 			},
 			Run: func(args []string, flags cli.Flags) error {
+				if secretsFile == "" {
+					secretsFile = filepath.Join(workDirectory, "secrets")
+				}
+
+				secretProviders, err := newSecretProviders(secretsFile, secretsPassphrase, secretProviderOptions)
+				if err != nil {
+					return err
+				}
+
 				templateStore := mockresolver.NewMemoryTemplateStore()
-				secretStore := mockresolver.NewMemorySecretStore()
+				secretStore := resolver.NewProviderSecretStore(secretProviders)
 				gitResolver, err := git.NewResolver(workDirectory, false)
 				if err != nil {
 					return err
 				}
 
+				costModel, err := newCostModel(costModelFile)
+				if err != nil {
+					return err
+				}
+
 				r := resolver.NewComponentResolver(gitResolver, templateStore, secretStore)
-				backend := mockbackend.NewMockBackend(r)
+				backend := mockbackend.NewMockBackend(r, gitResolver, secretProviders, costModel)
 				// construct server options
 				options := createServerOptions(tokenAuthFile)
 				rest.RunNewRestServer(backend, r, port, options)
@@ -66,6 +111,53 @@ func Command() *cli.RootCommand {
 	return command
 }
 
+// newSecretProviders returns the mock's secret providers. Secrets are stored in an
+// encrypted file by default, and can additionally be stored in an external provider.
+func newSecretProviders(
+	secretsFile, secretsPassphrase string,
+	options *secretprovider.Options,
+) (*secretprovider.Providers, error) {
+	providers, err := secretprovider.NewSecretProviders(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := providers[secretprovider.EncryptedFile]; !ok {
+		encryptedFileOptions := &encryptedfile.Options{
+			Path:       secretsFile,
+			Passphrase: secretsPassphrase,
+		}
+		provider, err := encryptedfile.NewSecretProvider(encryptedFileOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		providers[secretprovider.EncryptedFile] = provider
+	}
+
+	return secretprovider.NewProviders(secretprovider.EncryptedFile, providers)
+}
+
+// newCostModel returns the cost model in the file, or a model without any prices
+// if no file is given.
+func newCostModel(path string) (*cost.Model, error) {
+	if path == "" {
+		return &cost.Model{}, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &cost.Model{}
+	if err := json.Unmarshal(data, model); err != nil {
+		return nil, fmt.Errorf("invalid cost model file %v: %v", path, err)
+	}
+
+	return model, nil
+}
+
 func createServerOptions(tokenAuthFile string) *rest.ServerOptions {
 	options := rest.NewServerOptions()
 
@@ -7,6 +7,7 @@ LATTICE_ROOT=${DIR}/../../..
 
 declare -a packages=(
     "api/v1"
+    "cost"
     "definition/v1"
     "definition/tree"
     "definition/resolver"
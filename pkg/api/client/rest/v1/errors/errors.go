@@ -11,7 +11,7 @@ import (
 
 func HandleErrorStatusCode(statusCode int, body io.Reader) error {
 	switch statusCode {
-	case http.StatusBadRequest, http.StatusConflict, http.StatusNotFound:
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusNotFound:
 		v1Err := &v1.Error{}
 		if err := rest.UnmarshalBodyJSON(body, v1Err); err != nil {
 			return err
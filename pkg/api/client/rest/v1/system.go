@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	urlutil "net/url"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/client/rest/v1/errors"
 	"github.com/mlab-lattice/lattice/pkg/api/client/rest/v1/system"
 	clientv1 "github.com/mlab-lattice/lattice/pkg/api/client/v1"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	"github.com/mlab-lattice/lattice/pkg/util/rest"
 )
 
@@ -101,6 +104,118 @@ func (c *SystemClient) Delete(id v1.SystemID) error {
 	return errors.HandleErrorStatusCode(statusCode, body)
 }
 
+func (c *SystemClient) SetQuota(id v1.SystemID, quota *v1.SystemQuota) (*v1.System, error) {
+	requestJSON, err := json.Marshal(quota)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemQuotaPathFormat, id))
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		system := &v1.System{}
+		err = rest.UnmarshalBodyJSON(body, system)
+		return system, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *SystemClient) SetContainerResources(
+	id v1.SystemID,
+	resources *v1.SystemContainerResources,
+) (*v1.System, error) {
+	requestJSON, err := json.Marshal(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemContainerResourcesPathFormat, id))
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		system := &v1.System{}
+		err = rest.UnmarshalBodyJSON(body, system)
+		return system, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *SystemClient) Cost(id v1.SystemID, path tree.Path, since time.Time) (*v1.CostEstimate, error) {
+	query := urlutil.Values{}
+	query.Set("path", path.String())
+	query.Set("since", since.Format(time.RFC3339))
+
+	url := fmt.Sprintf("%v%v?%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemCostPathFormat, id), query.Encode())
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		estimate := &v1.CostEstimate{}
+		err = rest.UnmarshalBodyJSON(body, estimate)
+		return estimate, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *SystemClient) SetTracing(id v1.SystemID, tracing *v1.SystemTracing) (*v1.System, error) {
+	requestJSON, err := json.Marshal(tracing)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemTracingPathFormat, id))
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		system := &v1.System{}
+		err = rest.UnmarshalBodyJSON(body, system)
+		return system, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *SystemClient) SetImagePolicy(id v1.SystemID, policy *v1.ImagePolicy) (*v1.System, error) {
+	requestJSON, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemImagePolicyPathFormat, id))
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		system := &v1.System{}
+		err = rest.UnmarshalBodyJSON(body, system)
+		return system, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
 func (c *SystemClient) Versions(id v1.SystemID) ([]v1.Version, error) {
 	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.VersionsPathFormat, id))
 	body, statusCode, err := c.restClient.Get(url).Body()
@@ -126,6 +241,10 @@ func (c *SystemClient) Deploys(id v1.SystemID) clientv1.SystemDeployClient {
 	return system.NewDeployClient(c.restClient, c.apiServerURL, id)
 }
 
+func (c *SystemClient) NodePools(id v1.SystemID) clientv1.SystemNodePoolClient {
+	return system.NewNodePoolClient(c.restClient, c.apiServerURL, id)
+}
+
 func (c *SystemClient) Services(id v1.SystemID) clientv1.SystemServiceClient {
 	return system.NewServiceClient(c.restClient, c.apiServerURL, id)
 }
@@ -141,3 +260,7 @@ func (c *SystemClient) Secrets(id v1.SystemID) clientv1.SystemSecretClient {
 func (c *SystemClient) Teardowns(id v1.SystemID) clientv1.SystemTeardownClient {
 	return system.NewTeardownClient(c.restClient, c.apiServerURL, id)
 }
+
+func (c *SystemClient) Webhook(id v1.SystemID) clientv1.SystemWebhookClient {
+	return system.NewWebhookClient(c.restClient, c.apiServerURL, id)
+}
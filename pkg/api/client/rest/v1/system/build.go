@@ -31,18 +31,22 @@ func NewBuildClient(c rest.Client, apiServerURL string, systemID v1.SystemID) *B
 }
 
 func (c *BuildClient) CreateFromPath(path tree.Path) (*v1.Build, error) {
-	return c.create(&path, nil)
+	return c.create(&v1rest.BuildRequest{Path: &path})
 }
 
 func (c *BuildClient) CreateFromVersion(version v1.Version) (*v1.Build, error) {
-	return c.create(nil, &version)
+	return c.create(&v1rest.BuildRequest{Version: &version})
 }
 
-func (c *BuildClient) create(path *tree.Path, version *v1.Version) (*v1.Build, error) {
-	request := &v1rest.BuildRequest{
-		Path:    path,
-		Version: version,
-	}
+func (c *BuildClient) CreateFromSource(id v1.BuildSourceID) (*v1.Build, error) {
+	return c.create(&v1rest.BuildRequest{Source: &id})
+}
+
+func (c *BuildClient) CreateFromCommit(commit string) (*v1.Build, error) {
+	return c.create(&v1rest.BuildRequest{Commit: &commit})
+}
+
+func (c *BuildClient) create(request *v1rest.BuildRequest) (*v1.Build, error) {
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
@@ -64,6 +68,24 @@ func (c *BuildClient) create(path *tree.Path, version *v1.Version) (*v1.Build, e
 	return nil, errors.HandleErrorStatusCode(statusCode, body)
 }
 
+// UploadSource uploads the tar archive of a directory so that it can be built.
+func (c *BuildClient) UploadSource(archive io.Reader) (*v1.BuildSource, error) {
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.BuildSourcesPathFormat, c.systemID))
+	body, statusCode, err := c.restClient.Post(url, v1rest.BuildSourceContentType, archive).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusCreated {
+		source := &v1.BuildSource{}
+		err = rest.UnmarshalBodyJSON(body, &source)
+		return source, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
 func (c *BuildClient) List() ([]v1.Build, error) {
 	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.BuildsPathFormat, c.systemID))
 	body, statusCode, err := c.restClient.Get(url).Body()
@@ -97,6 +119,23 @@ func (c *BuildClient) Get(id v1.BuildID) (*v1.Build, error) {
 	return nil, errors.HandleErrorStatusCode(statusCode, body)
 }
 
+func (c *BuildClient) Provenance(id v1.BuildID) (*v1.BuildProvenance, error) {
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.BuildProvenancePathFormat, c.systemID, id))
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		provenance := &v1.BuildProvenance{}
+		err = rest.UnmarshalBodyJSON(body, &provenance)
+		return provenance, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
 func (c *BuildClient) Logs(
 	id v1.BuildID,
 	path tree.Path,
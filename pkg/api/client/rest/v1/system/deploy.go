@@ -100,3 +100,74 @@ func (c *DeployClient) Get(id v1.DeployID) (*v1.Deploy, error) {
 
 	return nil, errors.HandleErrorStatusCode(statusCode, body)
 }
+
+func (c *DeployClient) Approve(id v1.DeployID, comment string) (*v1.Deploy, error) {
+	return c.decide(v1rest.DeployApprovePathFormat, id, comment)
+}
+
+func (c *DeployClient) Reject(id v1.DeployID, comment string) (*v1.Deploy, error) {
+	return c.decide(v1rest.DeployRejectPathFormat, id, comment)
+}
+
+func (c *DeployClient) decide(pathFormat string, id v1.DeployID, comment string) (*v1.Deploy, error) {
+	request := v1rest.DeployApprovalRequest{
+		Comment: comment,
+	}
+
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(pathFormat, c.systemID, id))
+	body, statusCode, err := c.restClient.PostJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		deploy := &v1.Deploy{}
+		err = rest.UnmarshalBodyJSON(body, &deploy)
+		return deploy, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *DeployClient) Policy() (*v1.DeployPolicy, error) {
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemDeployPolicyPathFormat, c.systemID))
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		var policy *v1.DeployPolicy
+		err = rest.UnmarshalBodyJSON(body, &policy)
+		return policy, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *DeployClient) SetPolicy(policy *v1.DeployPolicy) error {
+	requestJSON, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemDeployPolicyPathFormat, c.systemID))
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		return nil
+	}
+
+	return errors.HandleErrorStatusCode(statusCode, body)
+}
@@ -0,0 +1,106 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	urlutil "net/url"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client/rest/v1/errors"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/rest"
+)
+
+type NodePoolClient struct {
+	restClient   rest.Client
+	apiServerURL string
+	systemID     v1.SystemID
+}
+
+func NewNodePoolClient(c rest.Client, apiServerURL string, systemID v1.SystemID) *NodePoolClient {
+	return &NodePoolClient{
+		restClient:   c,
+		apiServerURL: apiServerURL,
+		systemID:     systemID,
+	}
+}
+
+func (c *NodePoolClient) List() ([]v1.NodePool, error) {
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.NodePoolsPathFormat, c.systemID))
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		var nodePools []v1.NodePool
+		err = rest.UnmarshalBodyJSON(body, &nodePools)
+		return nodePools, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *NodePoolClient) Get(path tree.PathSubcomponent) (*v1.NodePool, error) {
+	body, statusCode, err := c.restClient.Get(c.url(v1rest.NodePoolPathFormat, path)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		nodePool := &v1.NodePool{}
+		err = rest.UnmarshalBodyJSON(body, &nodePool)
+		return nodePool, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *NodePoolClient) Override(path tree.PathSubcomponent, numInstances int32) (*v1.NodePool, error) {
+	request := &v1rest.OverrideNodePoolRequest{NumInstances: numInstances}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.url(v1rest.NodePoolOverridePathFormat, path)
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		nodePool := &v1.NodePool{}
+		err = rest.UnmarshalBodyJSON(body, &nodePool)
+		return nodePool, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *NodePoolClient) ClearOverride(path tree.PathSubcomponent) (*v1.NodePool, error) {
+	body, statusCode, err := c.restClient.Delete(c.url(v1rest.NodePoolOverridePathFormat, path)).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		nodePool := &v1.NodePool{}
+		err = rest.UnmarshalBodyJSON(body, &nodePool)
+		return nodePool, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *NodePoolClient) url(format string, path tree.PathSubcomponent) string {
+	escapedPath := urlutil.PathEscape(path.String())
+	return fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(format, c.systemID, escapedPath))
+}
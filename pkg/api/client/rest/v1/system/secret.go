@@ -45,9 +45,50 @@ func (c *SecretClient) List() ([]v1.Secret, error) {
 	return nil, errors.HandleErrorStatusCode(statusCode, body)
 }
 
-func (c *SecretClient) Get(path tree.PathSubcomponent) (*v1.Secret, error) {
-	escapedPath := urlutil.PathEscape(path.String())
-	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemSecretPathFormat, c.systemID, escapedPath))
+func (c *SecretClient) Get(path tree.PathSubcomponent, provider string) (*v1.Secret, error) {
+	url := c.secretURL(path, provider)
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		secret := &v1.Secret{}
+		err = rest.UnmarshalBodyJSON(body, &secret)
+		return secret, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *SecretClient) Versions(path tree.PathSubcomponent, provider string) ([]v1.Secret, error) {
+	url := c.url(v1rest.SystemSecretVersionsPathFormat, path, provider)
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		var secrets []v1.Secret
+		err = rest.UnmarshalBodyJSON(body, &secrets)
+		return secrets, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *SecretClient) Reveal(path tree.PathSubcomponent, provider string, version int32) (*v1.Secret, error) {
+	url := c.url(v1rest.SystemSecretRevealPathFormat, path, provider)
+	if version != 0 {
+		separator := "?"
+		if provider != "" {
+			separator = "&"
+		}
+		url = fmt.Sprintf("%v%vversion=%v", url, separator, version)
+	}
+
 	body, statusCode, err := c.restClient.Get(url).Body()
 	if err != nil {
 		return nil, err
@@ -63,9 +104,11 @@ func (c *SecretClient) Get(path tree.PathSubcomponent) (*v1.Secret, error) {
 	return nil, errors.HandleErrorStatusCode(statusCode, body)
 }
 
-func (c *SecretClient) Set(path tree.PathSubcomponent, value string) error {
+func (c *SecretClient) Set(path tree.PathSubcomponent, provider, value string, restart bool) error {
 	request := &v1rest.SetSecretRequest{
-		Value: value,
+		Value:    value,
+		Provider: provider,
+		Restart:  restart,
 	}
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
@@ -87,9 +130,8 @@ func (c *SecretClient) Set(path tree.PathSubcomponent, value string) error {
 	return errors.HandleErrorStatusCode(statusCode, body)
 }
 
-func (c *SecretClient) Unset(path tree.PathSubcomponent) error {
-	escapedPath := urlutil.PathEscape(string(path))
-	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemSecretPathFormat, c.systemID, escapedPath))
+func (c *SecretClient) Unset(path tree.PathSubcomponent, provider string) error {
+	url := c.secretURL(path, provider)
 	body, statusCode, err := c.restClient.Delete(url).Body()
 	if err != nil {
 		return err
@@ -102,3 +144,19 @@ func (c *SecretClient) Unset(path tree.PathSubcomponent) error {
 
 	return errors.HandleErrorStatusCode(statusCode, body)
 }
+
+func (c *SecretClient) secretURL(path tree.PathSubcomponent, provider string) string {
+	return c.url(v1rest.SystemSecretPathFormat, path, provider)
+}
+
+// url returns the url for the secret using the path format,
+// which must take the system ID and the secret's path.
+func (c *SecretClient) url(format string, path tree.PathSubcomponent, provider string) string {
+	escapedPath := urlutil.PathEscape(path.String())
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(format, c.systemID, escapedPath))
+	if provider != "" {
+		url = fmt.Sprintf("%v?provider=%v", url, urlutil.QueryEscape(provider))
+	}
+
+	return url
+}
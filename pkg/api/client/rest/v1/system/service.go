@@ -1,10 +1,14 @@
 package system
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	urlutil "net/url"
+	"strconv"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/client/rest/v1/errors"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
@@ -119,3 +123,105 @@ func (c *ServiceClient) Logs(
 
 	return nil, errors.HandleErrorStatusCode(statusCode, body)
 }
+
+func (c *ServiceClient) InjectFault(
+	id v1.ServiceID,
+	delay *v1.ServiceFaultDelay,
+	abort *v1.ServiceFaultAbort,
+	percentage int32,
+	source *tree.Path,
+	expiry time.Duration,
+) error {
+	request := &v1rest.InjectServiceFaultRequest{
+		Delay:      delay,
+		Abort:      abort,
+		Percentage: percentage,
+		Source:     source,
+		Expiry:     expiry.String(),
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.ServiceFaultPathFormat, c.systemID, id))
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		return nil
+	}
+
+	return errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *ServiceClient) ClearFault(id v1.ServiceID) error {
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.ServiceFaultPathFormat, c.systemID, id))
+	body, statusCode, err := c.restClient.Delete(url).Body()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		return nil
+	}
+
+	return errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *ServiceClient) Exec(
+	id v1.ServiceID,
+	instance, sidecar *string,
+	command []string,
+	tty bool,
+	streams *v1.ContainerExecStreams,
+) error {
+	query := urlutil.Values{}
+	for _, arg := range command {
+		query.Add(v1rest.StreamQueryCommand, arg)
+	}
+	query.Set(v1rest.StreamQueryStdin, strconv.FormatBool(streams.Stdin != nil))
+	query.Set(v1rest.StreamQueryTTY, strconv.FormatBool(tty))
+
+	if instance != nil {
+		query.Set(v1rest.StreamQueryInstance, *instance)
+	}
+
+	if sidecar != nil {
+		query.Set(v1rest.StreamQuerySidecar, *sidecar)
+	}
+
+	url := fmt.Sprintf(
+		"%v%v?%v",
+		c.apiServerURL,
+		fmt.Sprintf(v1rest.ServiceExecPathFormat, c.systemID, id),
+		query.Encode(),
+	)
+	return streamConn(c.restClient, url, streams)
+}
+
+func (c *ServiceClient) PortForward(id v1.ServiceID, instance *string, port int32, conn io.ReadWriter) error {
+	query := urlutil.Values{}
+	query.Set(v1rest.StreamQueryPort, strconv.Itoa(int(port)))
+
+	if instance != nil {
+		query.Set(v1rest.StreamQueryInstance, *instance)
+	}
+
+	url := fmt.Sprintf(
+		"%v%v?%v",
+		c.apiServerURL,
+		fmt.Sprintf(v1rest.ServicePortForwardPathFormat, c.systemID, id),
+		query.Encode(),
+	)
+
+	streams := &v1.ContainerExecStreams{
+		Stdin:  conn,
+		Stdout: conn,
+	}
+	return streamConn(c.restClient, url, streams)
+}
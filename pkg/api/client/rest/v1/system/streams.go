@@ -0,0 +1,81 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/util/rest"
+	"github.com/mlab-lattice/lattice/pkg/util/stream"
+)
+
+// streamConn opens a websocket to the url and copies the streams over it
+// until the server sends a message on the error channel.
+func streamConn(restClient rest.Client, url string, streams *v1.ContainerExecStreams) error {
+	ws, err := restClient.Websocket(url)
+	if err != nil {
+		return err
+	}
+
+	conn := stream.NewConn(ws)
+	defer conn.Close()
+
+	if streams.Stdin != nil {
+		go func() {
+			io.Copy(conn.Writer(v1rest.StreamChannelStdin), streams.Stdin)
+
+			// an empty message closes stdin
+			conn.Write(v1rest.StreamChannelStdin, nil)
+		}()
+	}
+
+	if streams.Resize != nil {
+		go func() {
+			for size := range streams.Resize {
+				data, err := json.Marshal(size)
+				if err != nil {
+					continue
+				}
+
+				if err := conn.Write(v1rest.StreamChannelResize, data); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		channel, data, err := conn.Read()
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("stream closed unexpectedly")
+			}
+			return err
+		}
+
+		switch channel {
+		case v1rest.StreamChannelStdout:
+			if _, err := streams.Stdout.Write(data); err != nil {
+				return err
+			}
+
+		case v1rest.StreamChannelStderr:
+			if _, err := streams.Stderr.Write(data); err != nil {
+				return err
+			}
+
+		case v1rest.StreamChannelError:
+			if len(data) == 0 {
+				return nil
+			}
+
+			v1err := &v1.Error{}
+			if err := json.Unmarshal(data, v1err); err != nil {
+				return fmt.Errorf("invalid error received: %v", string(data))
+			}
+			return v1err
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client/rest/v1/errors"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/util/rest"
+)
+
+type WebhookClient struct {
+	restClient   rest.Client
+	apiServerURL string
+	systemID     v1.SystemID
+}
+
+func NewWebhookClient(c rest.Client, apiServerURL string, systemID v1.SystemID) *WebhookClient {
+	return &WebhookClient{
+		restClient:   c,
+		apiServerURL: apiServerURL,
+		systemID:     systemID,
+	}
+}
+
+func (c *WebhookClient) Config() (*v1.WebhookConfig, error) {
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemWebhookPathFormat, c.systemID))
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		config := &v1.WebhookConfig{}
+		err = rest.UnmarshalBodyJSON(body, &config)
+		return config, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *WebhookClient) SetConfig(config *v1.WebhookConfig) error {
+	requestJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemWebhookPathFormat, c.systemID))
+	body, statusCode, err := c.restClient.PutJSON(url, bytes.NewReader(requestJSON)).Body()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		return nil
+	}
+
+	return errors.HandleErrorStatusCode(statusCode, body)
+}
+
+func (c *WebhookClient) Events() ([]v1.WebhookEvent, error) {
+	url := fmt.Sprintf("%v%v", c.apiServerURL, fmt.Sprintf(v1rest.SystemWebhookEventsPathFormat, c.systemID))
+	body, statusCode, err := c.restClient.Get(url).Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		var events []v1.WebhookEvent
+		err = rest.UnmarshalBodyJSON(body, &events)
+		return events, err
+	}
+
+	return nil, errors.HandleErrorStatusCode(statusCode, body)
+}
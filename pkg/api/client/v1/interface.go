@@ -2,6 +2,7 @@ package v1
 
 import (
 	"io"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
@@ -17,22 +18,33 @@ type SystemClient interface {
 	List() ([]v1.System, error)
 	Get(v1.SystemID) (*v1.System, error)
 	Delete(v1.SystemID) error
+	SetQuota(id v1.SystemID, quota *v1.SystemQuota) (*v1.System, error)
+	SetContainerResources(id v1.SystemID, resources *v1.SystemContainerResources) (*v1.System, error)
+	SetTracing(id v1.SystemID, tracing *v1.SystemTracing) (*v1.System, error)
+	SetImagePolicy(id v1.SystemID, policy *v1.ImagePolicy) (*v1.System, error)
+	Cost(id v1.SystemID, path tree.Path, since time.Time) (*v1.CostEstimate, error)
 
 	Builds(v1.SystemID) SystemBuildClient
 	Deploys(v1.SystemID) SystemDeployClient
 	Jobs(v1.SystemID) SystemJobClient
+	NodePools(v1.SystemID) SystemNodePoolClient
 	Secrets(v1.SystemID) SystemSecretClient
 	Services(v1.SystemID) SystemServiceClient
 	Teardowns(v1.SystemID) SystemTeardownClient
 	Versions(v1.SystemID) ([]v1.Version, error)
+	Webhook(v1.SystemID) SystemWebhookClient
 }
 
 type SystemBuildClient interface {
 	CreateFromVersion(v1.Version) (*v1.Build, error)
 	CreateFromPath(path tree.Path) (*v1.Build, error)
+	CreateFromSource(v1.BuildSourceID) (*v1.Build, error)
+	CreateFromCommit(commit string) (*v1.Build, error)
+	UploadSource(archive io.Reader) (*v1.BuildSource, error)
 	List() ([]v1.Build, error)
 	Get(v1.BuildID) (*v1.Build, error)
 	Logs(id v1.BuildID, path tree.Path, sidecar *string, options *v1.ContainerLogOptions) (io.ReadCloser, error)
+	Provenance(v1.BuildID) (*v1.BuildProvenance, error)
 }
 
 type SystemDeployClient interface {
@@ -41,6 +53,10 @@ type SystemDeployClient interface {
 	CreateFromVersion(v1.Version) (*v1.Deploy, error)
 	List() ([]v1.Deploy, error)
 	Get(v1.DeployID) (*v1.Deploy, error)
+	Approve(id v1.DeployID, comment string) (*v1.Deploy, error)
+	Reject(id v1.DeployID, comment string) (*v1.Deploy, error)
+	Policy() (*v1.DeployPolicy, error)
+	SetPolicy(policy *v1.DeployPolicy) error
 }
 
 type SystemTeardownClient interface {
@@ -49,11 +65,37 @@ type SystemTeardownClient interface {
 	Get(v1.TeardownID) (*v1.Teardown, error)
 }
 
+// SystemNodePoolClient manages the node pools of a system. Overrides change a
+// node pool outside of a deploy, and are removed by the system's next deploy.
+type SystemNodePoolClient interface {
+	List() ([]v1.NodePool, error)
+	Get(path tree.PathSubcomponent) (*v1.NodePool, error)
+	Override(path tree.PathSubcomponent, numInstances int32) (*v1.NodePool, error)
+	ClearOverride(path tree.PathSubcomponent) (*v1.NodePool, error)
+}
+
 type SystemServiceClient interface {
 	List() ([]v1.Service, error)
 	Get(id v1.ServiceID) (*v1.Service, error)
 	GetByPath(path tree.Path) (*v1.Service, error)
 	Logs(id v1.ServiceID, instance, sidecar *string, options *v1.ContainerLogOptions) (io.ReadCloser, error)
+	InjectFault(
+		id v1.ServiceID,
+		delay *v1.ServiceFaultDelay,
+		abort *v1.ServiceFaultAbort,
+		percentage int32,
+		source *tree.Path,
+		expiry time.Duration,
+	) error
+	ClearFault(id v1.ServiceID) error
+	Exec(
+		id v1.ServiceID,
+		instance, sidecar *string,
+		command []string,
+		tty bool,
+		streams *v1.ContainerExecStreams,
+	) error
+	PortForward(id v1.ServiceID, instance *string, port int32, conn io.ReadWriter) error
 }
 
 type SystemJobClient interface {
@@ -63,9 +105,20 @@ type SystemJobClient interface {
 	Logs(id v1.JobID, sidecar *string, options *v1.ContainerLogOptions) (io.ReadCloser, error)
 }
 
+// SystemSecretClient manages the versioned secrets of a system. An empty provider
+// refers to the lattice's default secret provider. Only Reveal returns the values
+// of secrets, and a zero version refers to the secret's current version.
 type SystemSecretClient interface {
 	List() ([]v1.Secret, error)
-	Get(path tree.PathSubcomponent) (*v1.Secret, error)
-	Set(path tree.PathSubcomponent, value string) error
-	Unset(path tree.PathSubcomponent) error
+	Get(path tree.PathSubcomponent, provider string) (*v1.Secret, error)
+	Versions(path tree.PathSubcomponent, provider string) ([]v1.Secret, error)
+	Reveal(path tree.PathSubcomponent, provider string, version int32) (*v1.Secret, error)
+	Set(path tree.PathSubcomponent, provider, value string, restart bool) error
+	Unset(path tree.PathSubcomponent, provider string) error
+}
+
+type SystemWebhookClient interface {
+	Config() (*v1.WebhookConfig, error)
+	SetConfig(config *v1.WebhookConfig) error
+	Events() ([]v1.WebhookEvent, error)
 }
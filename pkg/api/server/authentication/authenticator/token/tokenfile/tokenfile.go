@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/mlab-lattice/lattice/pkg/api/server/authentication/user"
 )
@@ -23,7 +24,9 @@ func New(tokens map[string]user.User) *TokenAuthenticator {
 	}
 }
 
-// NewFromCSV creates a new TokenAuthenticator from tokens read from a csv file
+// NewFromCSV creates a new TokenAuthenticator from tokens read from a csv file.
+// Each line holds a token, the name of its user and optionally a comma separated
+// list of the user's roles.
 func NewFromCSV(path string) (*TokenAuthenticator, error) {
 	csvFile, err := os.Open(path)
 	if err != nil {
@@ -31,6 +34,7 @@ func NewFromCSV(path string) (*TokenAuthenticator, error) {
 	}
 
 	reader := csv.NewReader(bufio.NewReader(csvFile))
+	reader.FieldsPerRecord = -1
 	tokens := make(map[string]user.User)
 
 	for {
@@ -42,13 +46,18 @@ func NewFromCSV(path string) (*TokenAuthenticator, error) {
 			return nil, err
 		}
 
-		if len(line) != 2 {
+		if len(line) != 2 && len(line) != 3 {
 			return nil, fmt.Errorf("bad token values")
 		}
 
 		token := line[0]
 		name := line[1]
-		tokens[token] = user.NewDefaultUser(name)
+
+		var roles []string
+		if len(line) == 3 && line[2] != "" {
+			roles = strings.Split(line[2], ",")
+		}
+		tokens[token] = user.NewDefaultUser(name, roles...)
 	}
 	return New(tokens), nil
 }
@@ -2,18 +2,26 @@ package user
 
 type User interface {
 	Name() string
+	// Roles are used to decide which users can approve deploys.
+	Roles() []string
 }
 
 type DefaultUser struct {
-	name string
+	name  string
+	roles []string
 }
 
-func NewDefaultUser(name string) User {
+func NewDefaultUser(name string, roles ...string) User {
 	return &DefaultUser{
-		name: name,
+		name:  name,
+		roles: roles,
 	}
 }
 
 func (u *DefaultUser) Name() string {
 	return u.name
 }
+
+func (u *DefaultUser) Roles() []string {
+	return u.roles
+}
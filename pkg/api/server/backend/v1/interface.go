@@ -2,6 +2,7 @@ package v1
 
 import (
 	"io"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
@@ -15,8 +16,18 @@ type Interface interface {
 type SystemBackend interface {
 	Create(id v1.SystemID, url string) (*v1.System, error)
 	List() ([]v1.System, error)
+	// Get returns the system along with its usage of the resources limited
+	// by its quota.
 	Get(v1.SystemID) (*v1.System, error)
 	Delete(v1.SystemID) error
+	SetQuota(id v1.SystemID, quota *v1.SystemQuota) (*v1.System, error)
+	SetContainerResources(id v1.SystemID, resources *v1.SystemContainerResources) (*v1.System, error)
+	SetTracing(id v1.SystemID, tracing *v1.SystemTracing) (*v1.System, error)
+	// SetImagePolicy removes the system's image policy if the policy is empty.
+	SetImagePolicy(id v1.SystemID, policy *v1.ImagePolicy) (*v1.System, error)
+	// Cost returns the estimated cost of the system's services and node pools
+	// under path, including their cost since since.
+	Cost(id v1.SystemID, path tree.Path, since time.Time) (*v1.CostEstimate, error)
 
 	Builds(v1.SystemID) SystemBuildBackend
 	Deploys(v1.SystemID) SystemDeployBackend
@@ -25,22 +36,37 @@ type SystemBackend interface {
 	Secrets(v1.SystemID) SystemSecretBackend
 	Services(v1.SystemID) SystemServiceBackend
 	Teardowns(v1.SystemID) SystemTeardownBackend
+	Webhooks(v1.SystemID) SystemWebhookBackend
 }
 
 type SystemBuildBackend interface {
 	CreateFromPath(tree.Path) (*v1.Build, error)
 	CreateFromVersion(v1.Version) (*v1.Build, error)
+	CreateFromSource(v1.BuildSourceID) (*v1.Build, error)
+	CreateFromCommit(commit string) (*v1.Build, error)
+	UploadSource(archive io.Reader) (*v1.BuildSource, error)
 	List() ([]v1.Build, error)
 	Get(v1.BuildID) (*v1.Build, error)
 	Logs(id v1.BuildID, path tree.Path, sidecar *string, options *v1.ContainerLogOptions) (io.ReadCloser, error)
+	Provenance(v1.BuildID) (*v1.BuildProvenance, error)
 }
 
+// SystemDeployBackend manages the deploys of a system. Deploys record the user
+// that created them, who cannot approve them if the system's deploy policy
+// requires them to be approved.
 type SystemDeployBackend interface {
-	CreateFromBuild(v1.BuildID) (*v1.Deploy, error)
-	CreateFromPath(tree.Path) (*v1.Deploy, error)
-	CreateFromVersion(v1.Version) (*v1.Deploy, error)
+	CreateFromBuild(id v1.BuildID, creator string) (*v1.Deploy, error)
+	CreateFromPath(path tree.Path, creator string) (*v1.Deploy, error)
+	CreateFromVersion(version v1.Version, creator string) (*v1.Deploy, error)
 	List() ([]v1.Deploy, error)
 	Get(v1.DeployID) (*v1.Deploy, error)
+	// Approve and Reject record the decision of the approver, who has the given
+	// roles, about a deploy that is awaiting approval.
+	Approve(id v1.DeployID, approver string, roles []string, comment string) (*v1.Deploy, error)
+	Reject(id v1.DeployID, approver string, roles []string, comment string) (*v1.Deploy, error)
+	// Policy returns nil if the system does not have a deploy policy.
+	Policy() (*v1.DeployPolicy, error)
+	SetPolicy(policy *v1.DeployPolicy) error
 }
 
 type SystemJobBackend interface {
@@ -50,18 +76,34 @@ type SystemJobBackend interface {
 	Logs(id v1.JobID, sidecar *string, options *v1.ContainerLogOptions) (io.ReadCloser, error)
 }
 
+// SystemNodePoolBackend manages the node pools of a system. Overrides change a
+// node pool outside of a deploy, and are removed by the system's next deploy.
 type SystemNodePoolBackend interface {
 	List() ([]v1.NodePool, error)
 	Get(path tree.PathSubcomponent) (*v1.NodePool, error)
+	Override(path tree.PathSubcomponent, numInstances int32) (*v1.NodePool, error)
+	ClearOverride(path tree.PathSubcomponent) (*v1.NodePool, error)
 }
 
+// SystemSecretBackend manages the versioned secrets of a system. List returns the
+// current version of the secrets from every configured secret provider. The other
+// methods take the name of the provider to use, with the empty string meaning the
+// default provider. Only Reveal returns the values of secrets.
 type SystemSecretBackend interface {
 	List() ([]v1.Secret, error)
-	Get(tree.PathSubcomponent) (*v1.Secret, error)
-	Set(path tree.PathSubcomponent, value string) error
-	Unset(tree.PathSubcomponent) error
+	Get(path tree.PathSubcomponent, provider string) (*v1.Secret, error)
+	Versions(path tree.PathSubcomponent, provider string) ([]v1.Secret, error)
+	// Reveal returns the version of the secret with its value. If version is zero
+	// the current version is returned.
+	Reveal(path tree.PathSubcomponent, provider string, version int32) (*v1.Secret, error)
+	// Set stores the value as a new version of the secret. If restart is true, the
+	// services and running jobs referencing the secret's current version are restarted.
+	Set(path tree.PathSubcomponent, provider, value, author string, restart bool) error
+	Unset(path tree.PathSubcomponent, provider string) error
 }
 
+// SystemServiceBackend manages the services of a system. Exec and PortForward
+// block until the command exits or the forwarded connection is closed.
 type SystemServiceBackend interface {
 	List() ([]v1.Service, error)
 	Get(v1.ServiceID) (*v1.Service, error)
@@ -72,6 +114,17 @@ type SystemServiceBackend interface {
 		instance string,
 		options *v1.ContainerLogOptions,
 	) (io.ReadCloser, error)
+	InjectFault(id v1.ServiceID, fault *v1.ServiceFault) error
+	ClearFault(id v1.ServiceID) error
+	Exec(
+		id v1.ServiceID,
+		sidecar *string,
+		instance string,
+		command []string,
+		tty bool,
+		streams *v1.ContainerExecStreams,
+	) error
+	PortForward(id v1.ServiceID, instance string, port int32, conn io.ReadWriter) error
 }
 
 type SystemTeardownBackend interface {
@@ -79,3 +132,14 @@ type SystemTeardownBackend interface {
 	List() ([]v1.Teardown, error)
 	Get(v1.TeardownID) (*v1.Teardown, error)
 }
+
+// SystemWebhookBackend stores how a system reacts to webhooks for pushes to its
+// definition repository, and the events that webhooks were received for.
+type SystemWebhookBackend interface {
+	// Config returns nil if the system has not been configured to react to webhooks.
+	Config() (*v1.WebhookConfig, error)
+	SetConfig(config *v1.WebhookConfig) error
+	// ListEvents returns the most recently received events, newest first.
+	ListEvents() ([]v1.WebhookEvent, error)
+	RecordEvent(event *v1.WebhookEvent) error
+}
@@ -0,0 +1,24 @@
+package authenticator
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mlab-lattice/lattice/pkg/api/server/authentication/user"
+)
+
+const currentUserContextKey = "CURRENT_USER"
+
+// SetCurrentUser attaches the authenticated user to the request's context.
+func SetCurrentUser(c *gin.Context, u user.User) {
+	c.Set(currentUserContextKey, u)
+}
+
+// CurrentUser returns the user that the request was authenticated as, if any.
+func CurrentUser(c *gin.Context) (user.User, bool) {
+	v, ok := c.Get(currentUserContextKey)
+	if !ok {
+		return nil, false
+	}
+
+	u, ok := v.(user.User)
+	return u, ok
+}
@@ -14,10 +14,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	currentUserContextKey = "CURRENT_USER"
-)
-
 type restServer struct {
 	router         *gin.Engine
 	backend        backend.Interface
@@ -60,6 +56,9 @@ func (r *restServer) mountHandlers(options *ServerOptions) {
 		c.String(http.StatusOK, "")
 	})
 
+	// webhooks are authenticated by their signatures rather than the authenticators
+	restv1.MountWebhookHandlers(r.router.Group("/"), r.backend.V1(), r.resolver)
+
 	routerGroup := r.router.Group("/")
 	r.setupAuthentication(routerGroup)
 
@@ -88,7 +87,7 @@ func (r *restServer) authenticateRequest() gin.HandlerFunc {
 			} else if ok { // Auth Success!
 				fmt.Printf("User %v successfully authenticated\n", userObject.Name())
 				// Attach user to current context
-				c.Set(currentUserContextKey, userObject)
+				authenticator.SetCurrentUser(c, userObject)
 				return
 			}
 
@@ -2,8 +2,10 @@ package rest
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -15,13 +17,18 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/api/server/authentication/authenticator/token/tokenfile"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
 	mockbackend "github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend"
 	mockresolver "github.com/mlab-lattice/lattice/pkg/backend/mock/definition/component/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider/encryptedfile"
 	"github.com/mlab-lattice/lattice/pkg/util/git"
+	"github.com/mlab-lattice/lattice/pkg/util/tar"
+	"github.com/mlab-lattice/lattice/pkg/webhook"
 )
 
 const (
@@ -36,6 +43,8 @@ const (
 	mockBearerToken   = "123"
 	mockTokenAuthFile = "/tmp/lattice/test/api/server/rest/auth-tokens.csv"
 	mockTokensCSV     = "123,test"
+
+	mockSecretsFile = "/tmp/lattice/test/api/server/rest/secrets"
 )
 
 var (
@@ -43,6 +52,9 @@ var (
 
 	mockSystemDefURL = fmt.Sprintf("file://%v", mockRepoPath)
 
+	// mockSystemCommit is the commit of the mock system's definition
+	mockSystemCommit string
+
 	mockSystem = &definitionv1.System{
 		Description: "Mock System",
 		NodePools: map[string]definitionv1.NodePool{
@@ -104,6 +116,8 @@ func happyPathTest(t *testing.T) {
 	runJob(t)
 	testSecrets(t)
 	checkSystemHealth(t)
+	buildSource(t)
+	receiveWebhooks(t)
 	teardownSystem(t)
 	deleteSystem(t)
 }
@@ -282,6 +296,21 @@ func buildAndDeploy(t *testing.T) {
 		t.Fatal("Failed to get build logs")
 	}
 
+	// test build provenance
+	fmt.Println("Test Build provenance")
+	provenance, err := latticeClient.Systems().Builds(mockSystemID).Provenance(build.ID)
+	checkErr(err, t)
+
+	if len(provenance.Workloads) != len(build.Status.Workloads) {
+		t.Fatal("bad # of workloads in build provenance")
+	}
+
+	for path, workload := range provenance.Workloads {
+		if workload.ID != build.Status.Workloads[path].ID || workload.BuilderVersion == "" {
+			t.Fatalf("bad build provenance for %v", path)
+		}
+	}
+
 	// test service logs
 	fmt.Println("Test Service logs")
 	services, err := latticeClient.Systems().Services(mockSystemID).List()
@@ -307,6 +336,149 @@ func buildAndDeploy(t *testing.T) {
 	}
 }
 
+func buildSource(t *testing.T) {
+	fmt.Println("Test Build Source")
+	archive, err := tar.ArchiveDirectory(mockRepoPath)
+	checkErr(err, t)
+
+	source, err := latticeClient.Systems().Builds(mockSystemID).UploadSource(archive)
+	checkErr(err, t)
+
+	if source.ID == "" || source.Digest == "" {
+		t.Fatal("bad build source returned from upload")
+	}
+
+	build, err := latticeClient.Systems().Builds(mockSystemID).CreateFromSource(source.ID)
+	checkErr(err, t)
+
+	if build.Source == nil || build.Source.ID != source.ID {
+		t.Fatal("build is not labelled with its build source")
+	}
+
+	waitFor(func() bool {
+		build, err = latticeClient.Systems().Builds(mockSystemID).Get(build.ID)
+		checkErr(err, t)
+		return build.Status.State == v1.BuildStateSucceeded || build.Status.State == v1.BuildStateFailed
+	}, t)
+
+	if build.Status.State != v1.BuildStateSucceeded {
+		t.Fatalf("build of build source did not succeed: %v", build.Status.Message)
+	}
+
+	if build.Status.Version == nil || *build.Status.Version != v1.BuildSourceVersion {
+		t.Fatal("build of build source has bad version")
+	}
+
+	if len(build.Status.Workloads) != len(mockSystem.Components) {
+		t.Fatal("bad # of workloads in build of build source")
+	}
+
+	_, err = latticeClient.Systems().Builds(mockSystemID).CreateFromSource(v1.BuildSourceID("bad-source"))
+	if err == nil || !strings.Contains(err.Error(), string(v1.ErrorCodeInvalidBuildSourceID)) {
+		t.Fatalf("expected invalid build source error, got: %v", err)
+	}
+
+	fmt.Println("Build of build source succeeded!")
+}
+
+func receiveWebhooks(t *testing.T) {
+	fmt.Println("Test Webhooks")
+	secretPath, _ := tree.NewPathSubcomponent("/:webhook-secret")
+	err := latticeClient.Systems().Secrets(mockSystemID).Set(secretPath, "", "webhook-secret", false)
+	checkErr(err, t)
+
+	branch := "master"
+	tag := "1.*"
+	config := &v1.WebhookConfig{
+		Secret: secretPath,
+		Rules: []v1.WebhookRule{
+			{Branch: &branch, Action: v1.WebhookActionBuild},
+			{Tag: &tag, Action: v1.WebhookActionDeploy},
+		},
+	}
+	err = latticeClient.Systems().Webhook(mockSystemID).SetConfig(config)
+	checkErr(err, t)
+
+	url := fmt.Sprintf("%v%v", mockAPIServerURL, fmt.Sprintf(v1rest.WebhookPathFormat, v1.WebhookProviderGitea))
+	deliver := func(ref, secret string) (int, []v1.WebhookEvent) {
+		payload := fmt.Sprintf(
+			`{"ref": %q, "after": %q, "repository": {"clone_url": %q}}`,
+			ref,
+			mockSystemCommit,
+			mockSystemDefURL,
+		)
+
+		// webhooks are not authenticated with the bearer token
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(payload))
+		checkErr(err, t)
+		req.Header.Set("X-Gitea-Event", "push")
+		req.Header.Set("X-Gitea-Signature", hex.EncodeToString(webhook.Sign([]byte(payload), []byte(secret))))
+
+		resp, err := http.DefaultClient.Do(req)
+		checkErr(err, t)
+		defer resp.Body.Close()
+
+		var events []v1.WebhookEvent
+		if resp.StatusCode == http.StatusOK {
+			checkErr(json.NewDecoder(resp.Body).Decode(&events), t)
+		}
+		return resp.StatusCode, events
+	}
+
+	fmt.Println("deliver branch push")
+	status, events := deliver("refs/heads/master", "webhook-secret")
+	if status != http.StatusOK || len(events) != 1 || events[0].Build == nil || events[0].Deploy != nil {
+		t.Fatalf("bad response to branch push webhook: %v %v", status, events)
+	}
+
+	build, err := latticeClient.Systems().Builds(mockSystemID).Get(*events[0].Build)
+	checkErr(err, t)
+	if build.Commit == nil || *build.Commit != mockSystemCommit {
+		t.Fatal("build triggered by branch push is not of the pushed commit")
+	}
+
+	waitFor(func() bool {
+		build, err = latticeClient.Systems().Builds(mockSystemID).Get(build.ID)
+		checkErr(err, t)
+		return build.Status.State == v1.BuildStateSucceeded || build.Status.State == v1.BuildStateFailed
+	}, t)
+
+	if build.Status.State != v1.BuildStateSucceeded {
+		t.Fatalf("build triggered by branch push did not succeed: %v", build.Status.Message)
+	}
+
+	fmt.Println("deliver tag push")
+	status, events = deliver("refs/tags/"+string(mockSystemVersion), "webhook-secret")
+	if status != http.StatusOK || len(events) != 1 || events[0].Build == nil || events[0].Deploy == nil {
+		t.Fatalf("bad response to tag push webhook: %v %v", status, events)
+	}
+
+	deploy := &v1.Deploy{ID: *events[0].Deploy}
+	waitFor(func() bool {
+		deploy, err = latticeClient.Systems().Deploys(mockSystemID).Get(deploy.ID)
+		checkErr(err, t)
+		return deploy.Status.State == v1.DeployStateSucceeded || deploy.Status.State == v1.DeployStateFailed
+	}, t)
+
+	if deploy.Status.State != v1.DeployStateSucceeded {
+		t.Fatalf("deploy triggered by tag push did not succeed: %v", deploy.Status.Message)
+	}
+
+	fmt.Println("deliver push with bad signature")
+	status, _ = deliver("refs/heads/master", "bad-secret")
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected webhook with bad signature to be rejected, got status %v", status)
+	}
+
+	events, err = latticeClient.Systems().Webhook(mockSystemID).Events()
+	checkErr(err, t)
+	if len(events) != 2 || events[0].Tag == nil || events[1].Branch == nil {
+		t.Fatalf("bad webhook events: %v", events)
+	}
+
+	fmt.Println("Webhooks succeeded!")
+}
+
 func ensureSingleDeploy(t *testing.T) {
 	fmt.Println("Ensure that system can have one accepted/running deploy at time")
 	build, err := latticeClient.Systems().Builds(mockSystemID).CreateFromVersion(mockSystemVersion)
@@ -420,7 +592,7 @@ func testSecrets(t *testing.T) {
 	fmt.Println("Testing secrets...")
 	path, _ := tree.NewPathSubcomponent("/test:x")
 	fmt.Println("set secret")
-	err := latticeClient.Systems().Secrets(mockSystemID).Set(path, "1")
+	err := latticeClient.Systems().Secrets(mockSystemID).Set(path, "", "1", false)
 	checkErr(err, t)
 	secrets, err := latticeClient.Systems().Secrets(mockSystemID).List()
 	checkErr(err, t)
@@ -430,15 +602,53 @@ func testSecrets(t *testing.T) {
 		t.Fatal("Wrong number of secrets.")
 	}
 
+	if secrets[0].Value != "" {
+		t.Fatal("Secret value was not redacted.")
+	}
+
 	fmt.Println("get secret")
-	secret, err := latticeClient.Systems().Secrets(mockSystemID).Get(path)
+	secret, err := latticeClient.Systems().Secrets(mockSystemID).Get(path, "")
+	checkErr(err, t)
+	if secret.Value != "" || secret.Version != 1 {
+		t.Fatal("Bad secret.")
+	}
+
+	fmt.Println("reveal secret")
+	secret, err = latticeClient.Systems().Secrets(mockSystemID).Reveal(path, "", 0)
 	checkErr(err, t)
 	if secret.Value != "1" {
 		t.Fatal("Bad secret.")
 	}
 
+	fmt.Println("rotate secret")
+	err = latticeClient.Systems().Secrets(mockSystemID).Set(path, "", "2", true)
+	checkErr(err, t)
+
+	versions, err := latticeClient.Systems().Secrets(mockSystemID).Versions(path, "")
+	checkErr(err, t)
+	if len(versions) != 2 || versions[1].Version != 2 {
+		t.Fatal("Wrong secret versions.")
+	}
+
+	secret, err = latticeClient.Systems().Secrets(mockSystemID).Reveal(path, "", 1)
+	checkErr(err, t)
+	if secret.Value != "1" {
+		t.Fatal("Bad secret version.")
+	}
+
+	_, err = latticeClient.Systems().Secrets(mockSystemID).Reveal(path, "", 3)
+	if err == nil {
+		t.Fatal("Expected error revealing unknown secret version.")
+	}
+
+	fmt.Println("get secret from unknown provider")
+	_, err = latticeClient.Systems().Secrets(mockSystemID).Get(path, "unknown")
+	if err == nil {
+		t.Fatal("Expected error getting secret from unknown provider.")
+	}
+
 	fmt.Println("unset secret")
-	err = latticeClient.Systems().Secrets(mockSystemID).Unset(path)
+	err = latticeClient.Systems().Secrets(mockSystemID).Unset(path, "")
 	checkErr(err, t)
 
 	secrets, err = latticeClient.Systems().Secrets(mockSystemID).List()
@@ -668,6 +878,8 @@ func setupMockTest() {
 		panic(err)
 	}
 
+	mockSystemCommit = hash.String()
+
 	err = git.Tag(mockRepoPath, hash, string(mockSystemVersion))
 	if err != nil {
 		panic(err)
@@ -679,13 +891,33 @@ func setupMockTest() {
 		panic(err)
 	}
 
+	// start with an empty secrets file
+	os.Remove(mockSecretsFile)
+	secretProvider, err := encryptedfile.NewSecretProvider(&encryptedfile.Options{
+		Path:       mockSecretsFile,
+		Passphrase: "test",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	secretProviders, err := secretprovider.NewProviders(
+		secretprovider.EncryptedFile,
+		map[string]secretprovider.Interface{
+			secretprovider.EncryptedFile: secretProvider,
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
 	r := resolver.NewComponentResolver(
 		gitResolver,
 		mockresolver.NewMemoryTemplateStore(),
-		mockresolver.NewMemorySecretStore(),
+		resolver.NewProviderSecretStore(secretProviders),
 	)
 
-	b := mockbackend.NewMockBackend(r)
+	b := mockbackend.NewMockBackend(r, gitResolver, secretProviders, nil)
 	options := NewServerOptions()
 
 	// setup bearer token
@@ -44,5 +44,6 @@ func (api *LatticeAPI) setupAPI() {
 	api.setupTeardownEndpoints()
 	api.setupSecretsEndpoints()
 	api.setupVersionsEndpoints()
+	api.setupWebhookEndpoints()
 	api.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
@@ -16,6 +16,8 @@ var (
 	buildsPath                   = fmt.Sprintf(v1rest.BuildsPathFormat, systemIdentifierPathComponent)
 	buildPath                    = fmt.Sprintf(v1rest.BuildPathFormat, systemIdentifierPathComponent, buildIdentifierPathComponent)
 	buildsLogPath                = fmt.Sprintf(v1rest.BuildLogsPathFormat, systemIdentifierPathComponent, buildIdentifierPathComponent)
+	buildProvenancePath          = fmt.Sprintf(v1rest.BuildProvenancePathFormat, systemIdentifierPathComponent, buildIdentifierPathComponent)
+	buildSourcesPath             = fmt.Sprintf(v1rest.BuildSourcesPathFormat, systemIdentifierPathComponent)
 )
 
 func (api *LatticeAPI) setupBuildEndpoints() {
@@ -31,6 +33,11 @@ func (api *LatticeAPI) setupBuildEndpoints() {
 	// get-build-logs
 	api.router.GET(buildsLogPath, api.handleGetBuildLogs)
 
+	// get-build-provenance
+	api.router.GET(buildProvenancePath, api.handleGetBuildProvenance)
+
+	// upload-build-source
+	api.router.POST(buildSourcesPath, api.handleUploadBuildSource)
 }
 
 // handleBuildSystem handler for build-system
@@ -75,6 +82,12 @@ func (api *LatticeAPI) handleBuildSystem(c *gin.Context) {
 
 	case req.Version != nil:
 		build, err = api.backend.Systems().Builds(systemID).CreateFromVersion(*req.Version)
+
+	case req.Source != nil:
+		build, err = api.backend.Systems().Builds(systemID).CreateFromSource(*req.Source)
+
+	case req.Commit != nil:
+		build, err = api.backend.Systems().Builds(systemID).CreateFromCommit(*req.Commit)
 	}
 
 	if err != nil {
@@ -85,10 +98,10 @@ func (api *LatticeAPI) handleBuildSystem(c *gin.Context) {
 		}
 
 		switch v1err.Code {
-		case v1.ErrorCodeInvalidSystemID:
+		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidBuildSourceID:
 			c.JSON(http.StatusNotFound, v1err)
 
-		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending, v1.ErrorCodeSystemQuotaExceeded:
 			c.JSON(http.StatusConflict, v1err)
 
 		default:
@@ -258,3 +271,89 @@ func (api *LatticeAPI) handleGetBuildLogs(c *gin.Context) {
 
 	serveLogFile(log, c)
 }
+
+// handleGetBuildProvenance handler for get-build-provenance
+// @ID get-build-provenance
+// @Summary Get build provenance
+// @Description Gets the provenance document of the build's successful container builds
+// @Router /systems/{system}/builds/{id}/provenance [get]
+// @Security ApiKeyAuth
+// @Tags builds
+// @Param system path string true "System ID"
+// @Param id path string true "Build ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.BuildProvenance
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleGetBuildProvenance(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	buildID := v1.BuildID(c.Param(buildIdentifier))
+
+	provenance, err := api.backend.Systems().Builds(systemID).Provenance(buildID)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidBuildID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, provenance)
+}
+
+// handleUploadBuildSource handler for upload-build-source
+// @ID upload-build-source
+// @Summary Upload build source
+// @Description Uploads a tar archive of a directory containing a system definition, so that it can be built
+// @Router /systems/{system}/build-sources [post]
+// @Security ApiKeyAuth
+// @Tags builds
+// @Param system path string true "System ID"
+// @Param archive body string true "Tar archive of the directory"
+// @Accept  application/x-tar
+// @Produce  json
+// @Success 201 {object} v1.BuildSource
+// @Failure 400 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleUploadBuildSource(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	if c.ContentType() != v1rest.BuildSourceContentType {
+		c.Status(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	source, err := api.backend.Systems().Builds(systemID).UploadSource(c.Request.Body)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, source)
+}
@@ -6,8 +6,10 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mlab-lattice/lattice/pkg/api/server/rest/authentication/authenticator"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/deploypolicy"
 	reflectutil "github.com/mlab-lattice/lattice/pkg/util/reflect"
 )
 
@@ -16,6 +18,9 @@ const deployIdentifier = "deploy_id"
 var (
 	deployIdentifierPathComponent = fmt.Sprintf(":%v", deployIdentifier)
 	deployPath                    = fmt.Sprintf(v1rest.DeployPathFormat, systemIdentifierPathComponent, deployIdentifierPathComponent)
+	deployApprovePath             = fmt.Sprintf(v1rest.DeployApprovePathFormat, systemIdentifierPathComponent, deployIdentifierPathComponent)
+	deployRejectPath              = fmt.Sprintf(v1rest.DeployRejectPathFormat, systemIdentifierPathComponent, deployIdentifierPathComponent)
+	deployPolicyPath              = fmt.Sprintf(v1rest.SystemDeployPolicyPathFormat, systemIdentifierPathComponent)
 )
 
 func (api *LatticeAPI) setupDeployEndpoints() {
@@ -29,6 +34,18 @@ func (api *LatticeAPI) setupDeployEndpoints() {
 	// get-deploy
 	api.router.GET(deployPath, api.handleGetDeploy)
 
+	// approve-deploy
+	api.router.POST(deployApprovePath, api.handleApproveDeploy)
+
+	// reject-deploy
+	api.router.POST(deployRejectPath, api.handleRejectDeploy)
+
+	// get-deploy-policy
+	api.router.GET(deployPolicyPath, api.handleGetDeployPolicy)
+
+	// set-deploy-policy
+	api.router.PUT(deployPolicyPath, api.handleSetDeployPolicy)
+
 }
 
 // handleDeploySystem handler for deploy-system
@@ -65,16 +82,21 @@ func (api *LatticeAPI) handleDeploySystem(c *gin.Context) {
 		return
 	}
 
+	var creator string
+	if u, ok := authenticator.CurrentUser(c); ok {
+		creator = u.Name()
+	}
+
 	var deploy *v1.Deploy
 	switch {
 	case req.BuildID != nil:
-		deploy, err = api.backend.Systems().Deploys(systemID).CreateFromBuild(*req.BuildID)
+		deploy, err = api.backend.Systems().Deploys(systemID).CreateFromBuild(*req.BuildID, creator)
 
 	case req.Path != nil:
-		deploy, err = api.backend.Systems().Deploys(systemID).CreateFromPath(*req.Path)
+		deploy, err = api.backend.Systems().Deploys(systemID).CreateFromPath(*req.Path, creator)
 
 	case req.Version != nil:
-		deploy, err = api.backend.Systems().Deploys(systemID).CreateFromVersion(*req.Version)
+		deploy, err = api.backend.Systems().Deploys(systemID).CreateFromVersion(*req.Version, creator)
 	}
 
 	if err != nil {
@@ -178,3 +200,183 @@ func (api *LatticeAPI) handleGetDeploy(c *gin.Context) {
 
 	c.JSON(http.StatusOK, deploy)
 }
+
+// handleApproveDeploy handler for approve-deploy
+// @ID approve-deploy
+// @Summary Approve deploy
+// @Description Approves a deploy that is awaiting approval
+// @Router /systems/{system}/deploys/{id}/approve [post]
+// @Security ApiKeyAuth
+// @Tags deploys
+// @Param system path string true "System ID"
+// @Param id path string true "Deploy ID"
+// @Param approval body rest.DeployApprovalRequest true "Approval"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.Deploy
+// @Failure 401 {object} v1.ErrorResponse
+// @Failure 403 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+// @Failure 409 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleApproveDeploy(c *gin.Context) {
+	api.handleDeployDecision(c, v1.DeployApprovalDecisionApproved)
+}
+
+// handleRejectDeploy handler for reject-deploy
+// @ID reject-deploy
+// @Summary Reject deploy
+// @Description Rejects a deploy that is awaiting approval, failing it
+// @Router /systems/{system}/deploys/{id}/reject [post]
+// @Security ApiKeyAuth
+// @Tags deploys
+// @Param system path string true "System ID"
+// @Param id path string true "Deploy ID"
+// @Param rejection body rest.DeployApprovalRequest true "Rejection"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.Deploy
+// @Failure 401 {object} v1.ErrorResponse
+// @Failure 403 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+// @Failure 409 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleRejectDeploy(c *gin.Context) {
+	api.handleDeployDecision(c, v1.DeployApprovalDecisionRejected)
+}
+
+func (api *LatticeAPI) handleDeployDecision(c *gin.Context, decision v1.DeployApprovalDecision) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	deployID := v1.DeployID(c.Param(deployIdentifier))
+
+	var req v1rest.DeployApprovalRequest
+	if err := c.BindJSON(&req); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	// approvals are counted by approver, so they can only be made by
+	// authenticated users
+	u, ok := authenticator.CurrentUser(c)
+	if !ok || u.Name() == "" {
+		c.JSON(http.StatusUnauthorized, v1.NewUnauthenticatedError())
+		return
+	}
+
+	approver := u.Name()
+	roles := u.Roles()
+
+	backend := api.backend.Systems().Deploys(systemID)
+
+	var deploy *v1.Deploy
+	var err error
+	switch decision {
+	case v1.DeployApprovalDecisionApproved:
+		deploy, err = backend.Approve(deployID, approver, roles, req.Comment)
+
+	case v1.DeployApprovalDecisionRejected:
+		deploy, err = backend.Reject(deployID, approver, roles, req.Comment)
+	}
+
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidDeployID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeDeployApprovalForbidden:
+			c.JSON(http.StatusForbidden, v1err)
+
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending, v1.ErrorCodeDeployNotAwaitingApproval, v1.ErrorCodeConflict:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, deploy)
+}
+
+// handleGetDeployPolicy handler for get-deploy-policy
+// @ID get-deploy-policy
+// @Summary Get deploy policy
+// @Description Gets the system's deploy policy, or null if it does not have one
+// @Router /systems/{system}/deploy-policy [get]
+// @Security ApiKeyAuth
+// @Tags deploys
+// @Param system path string true "System ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.DeployPolicy
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleGetDeployPolicy(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	policy, err := api.backend.Systems().Deploys(systemID).Policy()
+	if err != nil {
+		handleDeployPolicyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// handleSetDeployPolicy handler for set-deploy-policy
+// @ID set-deploy-policy
+// @Summary Set deploy policy
+// @Description Sets the approvals, change windows and freezes of the system's deploys
+// @Router /systems/{system}/deploy-policy [put]
+// @Security ApiKeyAuth
+// @Tags deploys
+// @Param system path string true "System ID"
+// @Param deployPolicy body v1.DeployPolicy true "Deploy policy"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.DeployPolicy
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleSetDeployPolicy(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	var policy v1.DeployPolicy
+	if err := c.BindJSON(&policy); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	if err := deploypolicy.Validate(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidDeployPolicyError())
+		return
+	}
+
+	if err := api.backend.Systems().Deploys(systemID).SetPolicy(&policy); err != nil {
+		handleDeployPolicyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func handleDeployPolicyError(c *gin.Context, err error) {
+	v1err, ok := err.(*v1.Error)
+	if !ok {
+		handleInternalError(c, err)
+		return
+	}
+
+	switch v1err.Code {
+	case v1.ErrorCodeInvalidSystemID:
+		c.JSON(http.StatusNotFound, v1err)
+
+	case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+		c.JSON(http.StatusConflict, v1err)
+
+	default:
+		handleInternalError(c, err)
+	}
+}
@@ -11,3 +11,11 @@ func MountHandlers(router *gin.RouterGroup, backend backendv1.Interface, resolve
 	api := newLatticeAPI(router, backend, resolver)
 	api.setupAPI()
 }
+
+// MountWebhookHandlers mounts the handler that git providers deliver webhooks to.
+// Webhooks are authenticated by their signatures, so the router should not
+// require the lattice's authentication.
+func MountWebhookHandlers(router *gin.RouterGroup, backend backendv1.Interface, resolver resolver.Interface) {
+	api := newLatticeAPI(router, backend, resolver)
+	api.setupWebhookReceiverEndpoints()
+}
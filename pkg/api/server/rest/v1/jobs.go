@@ -69,7 +69,7 @@ func (api *LatticeAPI) handleRunJob(c *gin.Context) {
 		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidPath:
 			c.JSON(http.StatusNotFound, v1err)
 
-		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending, v1.ErrorCodeSystemQuotaExceeded:
 			c.JSON(http.StatusConflict, v1err)
 
 		default:
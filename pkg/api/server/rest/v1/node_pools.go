@@ -18,6 +18,7 @@ var (
 	nodePoolIdentifierPathComponent = fmt.Sprintf(":%v", nodePoolIdentifier)
 	nodePoolPath                    = fmt.Sprintf(v1rest.NodePoolPathFormat, systemIdentifierPathComponent, nodePoolIdentifierPathComponent)
 	nodePoolsPath                   = fmt.Sprintf(v1rest.NodePoolsPathFormat, systemIdentifierPathComponent)
+	nodePoolOverridePath            = fmt.Sprintf(v1rest.NodePoolOverridePathFormat, systemIdentifierPathComponent, nodePoolIdentifierPathComponent)
 )
 
 func (api *LatticeAPI) setupNoodPoolEndpoints() {
@@ -27,6 +28,11 @@ func (api *LatticeAPI) setupNoodPoolEndpoints() {
 	// get-node-pool
 	api.router.GET(nodePoolPath, api.handleGetNodePool)
 
+	// override-node-pool
+	api.router.PUT(nodePoolOverridePath, api.handleOverrideNodePool)
+
+	// clear-node-pool-override
+	api.router.DELETE(nodePoolOverridePath, api.handleClearNodePoolOverride)
 }
 
 // handleListNodePools handler for list-node-pools
@@ -82,15 +88,8 @@ func (api *LatticeAPI) handleListNodePools(c *gin.Context) {
 // @Failure 404 {object} v1.ErrorResponse
 func (api *LatticeAPI) handleGetNodePool(c *gin.Context) {
 	systemID := v1.SystemID(c.Param(systemIdentifier))
-	escapedNodePoolPath := c.Param(nodePoolIdentifier)
 
-	nodePoolPathString, err := url.PathUnescape(escapedNodePoolPath)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
-		return
-	}
-
-	path, err := tree.NewPathSubcomponent(nodePoolPathString)
+	path, err := requestedNodePoolPath(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
 		return
@@ -119,3 +118,109 @@ func (api *LatticeAPI) handleGetNodePool(c *gin.Context) {
 
 	c.JSON(http.StatusOK, nodePool)
 }
+
+// handleOverrideNodePool handler for override-node-pool
+// @ID override-node-pool
+// @Summary Override node pool
+// @Description Overrides the number of instances of the node pool until the system's next deploy
+// @Router /systems/{system}/node-pools/{id}/override [put]
+// @Security ApiKeyAuth
+// @Tags node-pools
+// @Param system path string true "System ID"
+// @Param id path string true "NodePool ID"
+// @Param overrideRequest body rest.OverrideNodePoolRequest true "Override node pool"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.NodePool
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleOverrideNodePool(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	path, err := requestedNodePoolPath(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
+		return
+	}
+
+	var req v1rest.OverrideNodePoolRequest
+	if err := c.BindJSON(&req); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	if req.NumInstances < 1 {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidNodePoolOverrideError())
+		return
+	}
+
+	nodePool, err := api.backend.Systems().NodePools(systemID).Override(path, req.NumInstances)
+	if err != nil {
+		handleNodePoolOverrideError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nodePool)
+}
+
+// handleClearNodePoolOverride handler for clear-node-pool-override
+// @ID clear-node-pool-override
+// @Summary Clear node pool override
+// @Description Removes the node pool's override, returning it to the system's definition
+// @Router /systems/{system}/node-pools/{id}/override [delete]
+// @Security ApiKeyAuth
+// @Tags node-pools
+// @Param system path string true "System ID"
+// @Param id path string true "NodePool ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.NodePool
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleClearNodePoolOverride(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	path, err := requestedNodePoolPath(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
+		return
+	}
+
+	nodePool, err := api.backend.Systems().NodePools(systemID).ClearOverride(path)
+	if err != nil {
+		handleNodePoolOverrideError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nodePool)
+}
+
+func handleNodePoolOverrideError(c *gin.Context, err error) {
+	v1err, ok := err.(*v1.Error)
+	if !ok {
+		handleInternalError(c, err)
+		return
+	}
+
+	switch v1err.Code {
+	case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidPath:
+		c.JSON(http.StatusNotFound, v1err)
+
+	case v1.ErrorCodeInvalidNodePoolOverride:
+		c.JSON(http.StatusBadRequest, v1err)
+
+	case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending, v1.ErrorCodeConflict:
+		c.JSON(http.StatusConflict, v1err)
+
+	default:
+		handleInternalError(c, err)
+	}
+}
+
+func requestedNodePoolPath(c *gin.Context) (tree.PathSubcomponent, error) {
+	path, err := url.PathUnescape(c.Param(nodePoolIdentifier))
+	if err != nil {
+		return "", err
+	}
+
+	return tree.NewPathSubcomponent(path)
+}
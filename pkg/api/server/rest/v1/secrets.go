@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 
+	"github.com/mlab-lattice/lattice/pkg/api/server/rest/authentication/authenticator"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
@@ -18,6 +20,8 @@ var (
 	secretIdentifierPathComponent = fmt.Sprintf(":%v", secretIdentifier)
 	secretPath                    = fmt.Sprintf(v1rest.SystemSecretPathFormat, systemIdentifierPathComponent, secretIdentifierPathComponent)
 	secretsPath                   = fmt.Sprintf(v1rest.SystemSecretsPathFormat, systemIdentifierPathComponent)
+	secretVersionsPath            = fmt.Sprintf(v1rest.SystemSecretVersionsPathFormat, systemIdentifierPathComponent, secretIdentifierPathComponent)
+	secretRevealPath              = fmt.Sprintf(v1rest.SystemSecretRevealPathFormat, systemIdentifierPathComponent, secretIdentifierPathComponent)
 )
 
 func (api *LatticeAPI) setupSecretsEndpoints() {
@@ -34,12 +38,18 @@ func (api *LatticeAPI) setupSecretsEndpoints() {
 	// unset-secret
 	api.router.DELETE(secretPath, api.handleUnsetSecret)
 
+	// list-secret-versions
+	api.router.GET(secretVersionsPath, api.handleListSecretVersions)
+
+	// reveal-secret
+	api.router.GET(secretRevealPath, api.handleRevealSecret)
+
 }
 
 // handleSetSecret handler for set-secret
 // @ID set-secret
 // @Summary set secret
-// @Description Sets a new secret
+// @Description Sets a new version of a secret
 // @Router /systems/{system}/secrets [post]
 // @Security ApiKeyAuth
 // @Tags secrets
@@ -71,7 +81,12 @@ func (api *LatticeAPI) handleSetSecret(c *gin.Context) {
 		return
 	}
 
-	err = api.backend.Systems().Secrets(systemID).Set(path, req.Value)
+	var author string
+	if u, ok := authenticator.CurrentUser(c); ok {
+		author = u.Name()
+	}
+
+	err = api.backend.Systems().Secrets(systemID).Set(path, req.Provider, req.Value, author, req.Restart)
 	if err != nil {
 		v1err, ok := err.(*v1.Error)
 		if !ok {
@@ -89,6 +104,9 @@ func (api *LatticeAPI) handleSetSecret(c *gin.Context) {
 		case v1.ErrorCodeConflict:
 			c.JSON(http.StatusConflict, v1err)
 
+		case v1.ErrorCodeInvalidSecretProvider:
+			c.JSON(http.StatusBadRequest, v1err)
+
 		default:
 			handleInternalError(c, err)
 		}
@@ -101,7 +119,7 @@ func (api *LatticeAPI) handleSetSecret(c *gin.Context) {
 // handleListSecrets handler for list-secrets
 // @ID list-secrets
 // @Summary Lists secrets
-// @Description Lists all secrets
+// @Description Lists the current version of all secrets, without their values
 // @Router /systems/{system}/secrets [get]
 // @Security ApiKeyAuth
 // @Tags secrets
@@ -139,12 +157,13 @@ func (api *LatticeAPI) handleListSecrets(c *gin.Context) {
 // handleGetSecret handler for get-secret
 // @ID get-secret
 // @Summary Get secret
-// @Description Gets the secret object
+// @Description Gets the current version of the secret, without its value
 // @Router /systems/{system}/secrets/{secret} [get]
 // @Security ApiKeyAuth
 // @Tags secrets
 // @Param system path string true "System ID"
 // @Param secret path string true "Secret Path"
+// @Param provider query string false "Secret provider"
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} v1.Secret
@@ -165,7 +184,9 @@ func (api *LatticeAPI) handleGetSecret(c *gin.Context) {
 		return
 	}
 
-	secret, err := api.backend.Systems().Secrets(systemID).Get(path)
+	provider := c.Query("provider")
+
+	secret, err := api.backend.Systems().Secrets(systemID).Get(path, provider)
 	if err != nil {
 		v1err, ok := err.(*v1.Error)
 		if !ok {
@@ -180,6 +201,9 @@ func (api *LatticeAPI) handleGetSecret(c *gin.Context) {
 		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
 			c.JSON(http.StatusConflict, v1err)
 
+		case v1.ErrorCodeInvalidSecretProvider:
+			c.JSON(http.StatusBadRequest, v1err)
+
 		default:
 			handleInternalError(c, err)
 		}
@@ -200,6 +224,7 @@ func (api *LatticeAPI) handleGetSecret(c *gin.Context) {
 // @Produce  json
 // @Param system path string true "System ID"
 // @Param secret path string true "Secret Path"
+// @Param provider query string false "Secret provider"
 // @Success 200 {object} v1.Result
 // @Failure 404 {object} v1.ErrorResponse
 func (api *LatticeAPI) handleUnsetSecret(c *gin.Context) {
@@ -217,7 +242,9 @@ func (api *LatticeAPI) handleUnsetSecret(c *gin.Context) {
 		return
 	}
 
-	err = api.backend.Systems().Secrets(systemID).Unset(path)
+	provider := c.Query("provider")
+
+	err = api.backend.Systems().Secrets(systemID).Unset(path, provider)
 	if err != nil {
 		v1err, ok := err.(*v1.Error)
 		if !ok {
@@ -235,6 +262,9 @@ func (api *LatticeAPI) handleUnsetSecret(c *gin.Context) {
 		case v1.ErrorCodeConflict:
 			c.JSON(http.StatusConflict, v1err)
 
+		case v1.ErrorCodeInvalidSecretProvider:
+			c.JSON(http.StatusBadRequest, v1err)
+
 		default:
 			handleInternalError(c, err)
 		}
@@ -244,3 +274,128 @@ func (api *LatticeAPI) handleUnsetSecret(c *gin.Context) {
 	c.Status(http.StatusOK)
 
 }
+
+// handleListSecretVersions handler for list-secret-versions
+// @ID list-secret-versions
+// @Summary List secret versions
+// @Description Lists the versions of the secret, oldest first, without their values
+// @Router /systems/{system}/secrets/{secret}/versions [get]
+// @Security ApiKeyAuth
+// @Tags secrets
+// @Param system path string true "System ID"
+// @Param secret path string true "Secret Path"
+// @Param provider query string false "Secret provider"
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} v1.Secret
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleListSecretVersions(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	escapedSecretPath := c.Param(secretIdentifier)
+
+	secretPathString, err := url.PathUnescape(escapedSecretPath)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
+		return
+	}
+
+	path, err := tree.NewPathSubcomponent(secretPathString)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
+		return
+	}
+
+	provider := c.Query("provider")
+
+	secrets, err := api.backend.Systems().Secrets(systemID).Versions(path, provider)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidSecret:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+			c.JSON(http.StatusConflict, v1err)
+
+		case v1.ErrorCodeInvalidSecretProvider:
+			c.JSON(http.StatusBadRequest, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, secrets)
+}
+
+// handleRevealSecret handler for reveal-secret
+// @ID reveal-secret
+// @Summary Reveal secret
+// @Description Gets a version of the secret including its value
+// @Router /systems/{system}/secrets/{secret}/reveal [get]
+// @Security ApiKeyAuth
+// @Tags secrets
+// @Param system path string true "System ID"
+// @Param secret path string true "Secret Path"
+// @Param provider query string false "Secret provider"
+// @Param version query integer false "Secret version, defaults to the current version"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.Secret
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleRevealSecret(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	escapedSecretPath := c.Param(secretIdentifier)
+
+	secretPathString, err := url.PathUnescape(escapedSecretPath)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
+		return
+	}
+
+	path, err := tree.NewPathSubcomponent(secretPathString)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
+		return
+	}
+
+	provider := c.Query("provider")
+
+	version, err := strconv.ParseInt(c.DefaultQuery("version", "0"), 10, 32)
+	if err != nil || version < 0 {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidSecretVersionError())
+		return
+	}
+
+	secret, err := api.backend.Systems().Secrets(systemID).Reveal(path, provider, int32(version))
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidSecret, v1.ErrorCodeInvalidSecretVersion:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+			c.JSON(http.StatusConflict, v1err)
+
+		case v1.ErrorCodeInvalidSecretProvider:
+			c.JSON(http.StatusBadRequest, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, secret)
+}
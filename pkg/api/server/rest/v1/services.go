@@ -2,12 +2,15 @@ package v1
 
 import (
 	"fmt"
-
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +22,9 @@ var (
 	servicesPath                   = fmt.Sprintf(v1rest.ServicesPathFormat, systemIdentifierPathComponent)
 	servicePath                    = fmt.Sprintf(v1rest.ServicePathFormat, systemIdentifierPathComponent, serviceIdentifierPathComponent)
 	serviceLogPath                 = fmt.Sprintf(v1rest.ServiceLogsPathFormat, systemIdentifierPathComponent, serviceIdentifierPathComponent)
+	serviceFaultPath               = fmt.Sprintf(v1rest.ServiceFaultPathFormat, systemIdentifierPathComponent, serviceIdentifierPathComponent)
+	serviceExecPath                = fmt.Sprintf(v1rest.ServiceExecPathFormat, systemIdentifierPathComponent, serviceIdentifierPathComponent)
+	servicePortForwardPath         = fmt.Sprintf(v1rest.ServicePortForwardPathFormat, systemIdentifierPathComponent, serviceIdentifierPathComponent)
 )
 
 func (api *LatticeAPI) setupServicesEndpoints() {
@@ -31,6 +37,17 @@ func (api *LatticeAPI) setupServicesEndpoints() {
 	// service component log path
 	api.router.GET(serviceLogPath, api.handleGetServiceLogs)
 
+	// inject-service-fault
+	api.router.PUT(serviceFaultPath, api.handleInjectServiceFault)
+
+	// clear-service-fault
+	api.router.DELETE(serviceFaultPath, api.handleClearServiceFault)
+
+	// exec-service
+	api.router.GET(serviceExecPath, api.handleExecService)
+
+	// port-forward-service
+	api.router.GET(servicePortForwardPath, api.handlePortForwardService)
 }
 
 // handleListServices handler for list-services
@@ -207,3 +224,225 @@ func (api *LatticeAPI) handleGetServiceLogs(c *gin.Context) {
 
 	serveLogFile(log, c)
 }
+
+// handleInjectServiceFault handler for inject-service-fault
+// @ID inject-service-fault
+// @Summary Inject service fault
+// @Description Injects a delay or abort fault into the traffic sent to the service
+// @Router /systems/{system}/services/{id}/fault [put]
+// @Security ApiKeyAuth
+// @Tags services
+// @Param system path string true "System ID"
+// @Param id path string true "Service ID"
+// @Param faultRequest body rest.InjectServiceFaultRequest true "Inject fault"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.Result
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleInjectServiceFault(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	serviceID := v1.ServiceID(c.Param(serviceIdentifier))
+
+	var req v1rest.InjectServiceFaultRequest
+	if err := c.BindJSON(&req); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	fault, v1err := serviceFault(&req)
+	if v1err != nil {
+		c.JSON(http.StatusBadRequest, v1err)
+		return
+	}
+
+	err := api.backend.Systems().Services(systemID).InjectFault(serviceID, fault)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidServiceID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeInvalidServiceFault:
+			c.JSON(http.StatusBadRequest, v1err)
+
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending, v1.ErrorCodeConflict:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// handleClearServiceFault handler for clear-service-fault
+// @ID clear-service-fault
+// @Summary Clear service fault
+// @Description Removes any fault injected into the traffic sent to the service
+// @Router /systems/{system}/services/{id}/fault [delete]
+// @Security ApiKeyAuth
+// @Tags services
+// @Param system path string true "System ID"
+// @Param id path string true "Service ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.Result
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleClearServiceFault(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	serviceID := v1.ServiceID(c.Param(serviceIdentifier))
+
+	err := api.backend.Systems().Services(systemID).ClearFault(serviceID)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID, v1.ErrorCodeInvalidServiceID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending, v1.ErrorCodeConflict:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// handleExecService handler for exec-service
+// @ID exec-service
+// @Summary Exec into service instance
+// @Description Runs a command in a service instance, streaming its input and output over a websocket
+// @Router /systems/{system}/services/{id}/exec [get]
+// @Security ApiKeyAuth
+// @Tags services
+// @Param system path string true "System ID"
+// @Param id path string true "Service ID"
+// @Param instance query string true "Instance"
+// @Param sidecar query string false "Sidecar"
+// @Param command query []string true "Command"
+// @Param stdin query boolean false "Stdin"
+// @Param tty query boolean false "TTY"
+// @Success 101 {string} string "websocket stream"
+// @Failure 400 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleExecService(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	serviceID := v1.ServiceID(c.Param(serviceIdentifier))
+	instance := c.Query(v1rest.StreamQueryInstance)
+
+	sidecarQuery, sidecarSet := c.GetQuery(v1rest.StreamQuerySidecar)
+	var sidecar *string
+	if sidecarSet {
+		sidecar = &sidecarQuery
+	}
+
+	command := c.QueryArray(v1rest.StreamQueryCommand)
+	if len(command) == 0 {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidStreamOptionsError())
+		return
+	}
+
+	stdin, err := strconv.ParseBool(c.DefaultQuery(v1rest.StreamQueryStdin, "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidStreamOptionsError())
+		return
+	}
+
+	tty, err := strconv.ParseBool(c.DefaultQuery(v1rest.StreamQueryTTY, "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidStreamOptionsError())
+		return
+	}
+
+	backend := api.backend.Systems().Services(systemID)
+	serveStreams(c, stdin, tty, func(streams *v1.ContainerExecStreams) error {
+		return backend.Exec(serviceID, sidecar, instance, command, tty, streams)
+	})
+}
+
+// handlePortForwardService handler for port-forward-service
+// @ID port-forward-service
+// @Summary Port forward to service instance
+// @Description Forwards a connection to a port of a service instance over a websocket
+// @Router /systems/{system}/services/{id}/port-forward [get]
+// @Security ApiKeyAuth
+// @Tags services
+// @Param system path string true "System ID"
+// @Param id path string true "Service ID"
+// @Param instance query string true "Instance"
+// @Param port query integer true "Port"
+// @Success 101 {string} string "websocket stream"
+// @Failure 400 {object} v1.ErrorResponse
+func (api *LatticeAPI) handlePortForwardService(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+	serviceID := v1.ServiceID(c.Param(serviceIdentifier))
+	instance := c.Query(v1rest.StreamQueryInstance)
+
+	port, err := strconv.ParseInt(c.Query(v1rest.StreamQueryPort), 10, 32)
+	if err != nil || port <= 0 || port > 65535 {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidStreamOptionsError())
+		return
+	}
+
+	backend := api.backend.Systems().Services(systemID)
+	serveStreams(c, true, false, func(streams *v1.ContainerExecStreams) error {
+		conn := struct {
+			io.Reader
+			io.Writer
+		}{streams.Stdin, streams.Stdout}
+		return backend.PortForward(serviceID, instance, int32(port), conn)
+	})
+}
+
+// serviceFault validates an InjectServiceFaultRequest and converts it into
+// the ServiceFault that should be applied.
+func serviceFault(req *v1rest.InjectServiceFaultRequest) (*v1.ServiceFault, *v1.Error) {
+	// exactly one of delay or abort must be set
+	if (req.Delay == nil) == (req.Abort == nil) {
+		return nil, v1.NewInvalidServiceFaultError()
+	}
+
+	if req.Percentage <= 0 || req.Percentage > 100 {
+		return nil, v1.NewInvalidServiceFaultError()
+	}
+
+	if req.Delay != nil {
+		delay, err := time.ParseDuration(req.Delay.Duration)
+		if err != nil || delay <= 0 {
+			return nil, v1.NewInvalidServiceFaultError()
+		}
+	}
+
+	if req.Abort != nil && (req.Abort.HTTPStatus < 200 || req.Abort.HTTPStatus > 599) {
+		return nil, v1.NewInvalidServiceFaultError()
+	}
+
+	expiry, err := time.ParseDuration(req.Expiry)
+	if err != nil || expiry <= 0 {
+		return nil, v1.NewInvalidServiceFaultError()
+	}
+
+	fault := &v1.ServiceFault{
+		Delay:      req.Delay,
+		Abort:      req.Abort,
+		Percentage: req.Percentage,
+		Source:     req.Source,
+		Expires:    *timeutil.New(time.Now().Add(expiry)),
+	}
+	return fault, nil
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/util/stream"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// serveStreams upgrades the request to a websocket and calls run with the
+// streams multiplexed over it. Once run returns its error is sent on the
+// error channel and the websocket is closed.
+func serveStreams(c *gin.Context, stdin, resize bool, run func(*v1.ContainerExecStreams) error) {
+	server := websocket.Server{
+		Handshake: checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			conn := stream.NewConn(ws)
+			defer conn.Close()
+
+			streams := &v1.ContainerExecStreams{
+				Stdout: conn.Writer(v1rest.StreamChannelStdout),
+				Stderr: conn.Writer(v1rest.StreamChannelStderr),
+			}
+
+			var stdinWriter *io.PipeWriter
+			if stdin {
+				stdinReader, writer := io.Pipe()
+				defer stdinReader.Close()
+
+				streams.Stdin = stdinReader
+				stdinWriter = writer
+			}
+
+			var resizes chan v1.TerminalSize
+			if resize {
+				resizes = make(chan v1.TerminalSize, 1)
+				streams.Resize = resizes
+			}
+
+			go readStreams(conn, stdinWriter, resizes)
+
+			conn.Write(v1rest.StreamChannelError, streamError(run(streams)))
+		},
+	}
+
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin rejects websocket handshakes sent by browsers from other origins,
+// which could otherwise open streams with the credentials the browser holds for
+// the API. Clients that aren't browsers don't have to send an origin.
+func checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+
+	if origin != nil && origin.Host != req.Host {
+		return fmt.Errorf("origin %v is not allowed", origin)
+	}
+
+	config.Origin = origin
+	return nil
+}
+
+// readStreams dispatches the messages read from the websocket until it is
+// closed. Messages for nil streams are dropped.
+func readStreams(conn *stream.Conn, stdin *io.PipeWriter, resize chan v1.TerminalSize) {
+	stdinClosed := stdin == nil
+	defer func() {
+		if !stdinClosed {
+			stdin.Close()
+		}
+		if resize != nil {
+			close(resize)
+		}
+	}()
+
+	for {
+		channel, data, err := conn.Read()
+		if err != nil {
+			return
+		}
+
+		switch channel {
+		case v1rest.StreamChannelStdin:
+			if stdinClosed {
+				continue
+			}
+
+			if len(data) == 0 {
+				stdin.Close()
+				stdinClosed = true
+				continue
+			}
+
+			if _, err := stdin.Write(data); err != nil {
+				stdinClosed = true
+			}
+
+		case v1rest.StreamChannelResize:
+			if resize == nil {
+				continue
+			}
+
+			var size v1.TerminalSize
+			if err := json.Unmarshal(data, &size); err != nil {
+				continue
+			}
+
+			// only the latest size matters, so drop a pending resize
+			// rather than blocking on it
+			select {
+			case <-resize:
+			default:
+			}
+			resize <- size
+		}
+	}
+}
+
+func streamError(err error) []byte {
+	if err == nil {
+		return nil
+	}
+
+	v1err, ok := err.(*v1.Error)
+	if !ok {
+		v1err = v1.NewUnknownError()
+	}
+
+	data, _ := json.Marshal(v1err)
+	return data
+}
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	backendv1 "github.com/mlab-lattice/lattice/pkg/api/server/backend/v1"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/util/stream"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// testBackend only implements the exec and port forward methods of the
+// services of a system with a single service, "api".
+type testBackend struct {
+	backendv1.Interface
+}
+
+func (b *testBackend) Systems() backendv1.SystemBackend {
+	return &testSystemBackend{}
+}
+
+type testSystemBackend struct {
+	backendv1.SystemBackend
+}
+
+func (b *testSystemBackend) Services(v1.SystemID) backendv1.SystemServiceBackend {
+	return &testServiceBackend{}
+}
+
+type testServiceBackend struct {
+	backendv1.SystemServiceBackend
+}
+
+func (b *testServiceBackend) Exec(
+	id v1.ServiceID,
+	sidecar *string,
+	instance string,
+	command []string,
+	tty bool,
+	streams *v1.ContainerExecStreams,
+) error {
+	if id != "api" {
+		return v1.NewInvalidServiceIDError()
+	}
+
+	// echo stdin back if there is any, otherwise pretend to run the command
+	if streams.Stdin != nil {
+		_, err := io.Copy(streams.Stdout, streams.Stdin)
+		return err
+	}
+
+	_, err := fmt.Fprintf(streams.Stdout, "ran %v", strings.Join(command, " "))
+	return err
+}
+
+func (b *testServiceBackend) PortForward(id v1.ServiceID, instance string, port int32, conn io.ReadWriter) error {
+	if id != "api" {
+		return v1.NewInvalidServiceIDError()
+	}
+
+	_, err := io.Copy(conn, conn)
+	return err
+}
+
+func testServer() *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	MountHandlers(router.Group("/"), &testBackend{}, nil)
+	return httptest.NewServer(router)
+}
+
+func execURL(server *httptest.Server, service string, query url.Values) string {
+	path := fmt.Sprintf(v1rest.ServiceExecPathFormat, "test", service)
+	return fmt.Sprintf("%v%v?%v", server.URL, path, query.Encode())
+}
+
+// dial opens a websocket to the http URL with the origin.
+func dial(t *testing.T, httpURL, origin string) (*stream.Conn, error) {
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(httpURL, "http"), origin)
+	require.NoError(t, err)
+
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.NewConn(ws), nil
+}
+
+// readOutput returns the output written to stdout and the error sent on the
+// error channel of the stream.
+func readOutput(t *testing.T, conn *stream.Conn) (string, []byte) {
+	var stdout string
+	for {
+		channel, data, err := conn.Read()
+		require.NoError(t, err)
+
+		switch channel {
+		case v1rest.StreamChannelStdout:
+			stdout += string(data)
+
+		case v1rest.StreamChannelError:
+			return stdout, data
+		}
+	}
+}
+
+func TestExecServiceInvalidOptions(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	portForwardPath := fmt.Sprintf(v1rest.ServicePortForwardPathFormat, "test", "api")
+	urls := []string{
+		execURL(server, "api", url.Values{}),
+		execURL(server, "api", url.Values{v1rest.StreamQueryCommand: {"ls"}, v1rest.StreamQueryStdin: {"maybe"}}),
+		execURL(server, "api", url.Values{v1rest.StreamQueryCommand: {"ls"}, v1rest.StreamQueryTTY: {"maybe"}}),
+		fmt.Sprintf("%v%v?%v=0", server.URL, portForwardPath, v1rest.StreamQueryPort),
+	}
+
+	for _, u := range urls {
+		resp, err := http.Get(u)
+		require.NoError(t, err)
+
+		var v1err v1.Error
+		err = json.NewDecoder(resp.Body).Decode(&v1err)
+		resp.Body.Close()
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, u)
+		require.Equal(t, v1.ErrorCodeInvalidStreamOptions, v1err.Code, u)
+	}
+}
+
+func TestExecService(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	// run a command without stdin
+	query := url.Values{v1rest.StreamQueryCommand: {"echo", "hello"}}
+	conn, err := dial(t, execURL(server, "api", query), server.URL)
+	require.NoError(t, err)
+
+	stdout, streamErr := readOutput(t, conn)
+	conn.Close()
+	require.Equal(t, "ran echo hello", stdout)
+	require.Empty(t, streamErr)
+
+	// stdin is streamed to the command until it is closed by an empty message
+	query.Set(v1rest.StreamQueryStdin, "true")
+	conn, err = dial(t, execURL(server, "api", query), server.URL)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.Write(v1rest.StreamChannelStdin, []byte("hello")))
+	require.NoError(t, conn.Write(v1rest.StreamChannelStdin, nil))
+
+	stdout, streamErr = readOutput(t, conn)
+	conn.Close()
+	require.Equal(t, "hello", stdout)
+	require.Empty(t, streamErr)
+
+	// errors returned by the backend are sent on the error channel
+	query.Del(v1rest.StreamQueryStdin)
+	conn, err = dial(t, execURL(server, "worker", query), server.URL)
+	require.NoError(t, err)
+
+	_, streamErr = readOutput(t, conn)
+	conn.Close()
+
+	var v1err v1.Error
+	require.NoError(t, json.Unmarshal(streamErr, &v1err))
+	require.Equal(t, v1.ErrorCodeInvalidServiceID, v1err.Code)
+}
+
+func TestExecServiceOrigin(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	u := execURL(server, "api", url.Values{v1rest.StreamQueryCommand: {"ls"}})
+	_, err := dial(t, u, "http://example.com")
+	require.Error(t, err)
+
+	conn, err := dial(t, u, server.URL)
+	require.NoError(t, err)
+	conn.Close()
+}
@@ -5,9 +5,15 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/containerresources"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/imagepolicy"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+	"github.com/mlab-lattice/lattice/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,6 +26,11 @@ const (
 var (
 	systemIdentifierPathComponent = fmt.Sprintf(":%v", systemIdentifier)
 	systemPath                    = fmt.Sprintf(v1rest.SystemPathFormat, systemIdentifierPathComponent)
+	systemQuotaPath               = fmt.Sprintf(v1rest.SystemQuotaPathFormat, systemIdentifierPathComponent)
+	systemContainerResourcesPath  = fmt.Sprintf(v1rest.SystemContainerResourcesPathFormat, systemIdentifierPathComponent)
+	systemTracingPath             = fmt.Sprintf(v1rest.SystemTracingPathFormat, systemIdentifierPathComponent)
+	systemImagePolicyPath         = fmt.Sprintf(v1rest.SystemImagePolicyPathFormat, systemIdentifierPathComponent)
+	systemCostPath                = fmt.Sprintf(v1rest.SystemCostPathFormat, systemIdentifierPathComponent)
 )
 
 func (api *LatticeAPI) setupSystemEndpoints() {
@@ -34,6 +45,21 @@ func (api *LatticeAPI) setupSystemEndpoints() {
 
 	// delete-system
 	api.router.DELETE(systemPath, api.handleDeleteSystem)
+
+	// set-system-quota
+	api.router.PUT(systemQuotaPath, api.handleSetSystemQuota)
+
+	// set-system-container-resources
+	api.router.PUT(systemContainerResourcesPath, api.handleSetSystemContainerResources)
+
+	// set-system-tracing
+	api.router.PUT(systemTracingPath, api.handleSetSystemTracing)
+
+	// set-system-image-policy
+	api.router.PUT(systemImagePolicyPath, api.handleSetSystemImagePolicy)
+
+	// get-system-cost
+	api.router.GET(systemCostPath, api.handleGetSystemCost)
 }
 
 // handleCreateSystem handler for create-system
@@ -111,7 +137,7 @@ func (api *LatticeAPI) handleListSystems(c *gin.Context) {
 // handleGetSystem handler for get-system
 // @ID get-system
 // @Summary Get system
-// @Description get system
+// @Description Gets the system, including its usage of the resources limited by its quota
 // @Router /systems/{system} [get]
 // @Security ApiKeyAuth
 // @Tags systems
@@ -190,6 +216,273 @@ func (api *LatticeAPI) handleDeleteSystem(c *gin.Context) {
 
 }
 
+// handleSetSystemQuota handler for set-system-quota
+// @ID set-system-quota
+// @Summary Set system quota
+// @Description Sets the limits on the resources the system can use
+// @Router /systems/{system}/quota [put]
+// @Security ApiKeyAuth
+// @Tags systems
+// @Param system path string true "System ID"
+// @Param quota body v1.SystemQuota true "System quota"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.System
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleSetSystemQuota(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	var systemQuota v1.SystemQuota
+	if err := c.BindJSON(&systemQuota); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	if err := quota.Validate(&systemQuota); err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidSystemQuotaError())
+		return
+	}
+
+	system, err := api.backend.Systems().SetQuota(systemID, &systemQuota)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeConflict, v1.ErrorCodeSystemDeleting:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, system)
+}
+
+// handleSetSystemContainerResources handler for set-system-container-resources
+// @ID set-system-container-resources
+// @Summary Set system container resources
+// @Description Sets the default resources of the system's containers and the most they can use
+// @Router /systems/{system}/container-resources [put]
+// @Security ApiKeyAuth
+// @Tags systems
+// @Param system path string true "System ID"
+// @Param resources body v1.SystemContainerResources true "System container resources"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.System
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleSetSystemContainerResources(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	var resources v1.SystemContainerResources
+	if err := c.BindJSON(&resources); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	if err := containerresources.ValidateSystem(&resources); err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidContainerResourcesError())
+		return
+	}
+
+	system, err := api.backend.Systems().SetContainerResources(systemID, &resources)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeConflict, v1.ErrorCodeSystemDeleting:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, system)
+}
+
+// handleGetSystemCost handler for get-system-cost
+// @ID get-system-cost
+// @Summary Get system cost
+// @Description Gets the estimated cost of the system's services and node pools under a path
+// @Router /systems/{system}/cost [get]
+// @Security ApiKeyAuth
+// @Tags systems
+// @Param system path string true "System ID"
+// @Param path query string false "Path prefix, defaults to the root of the system"
+// @Param since query string false "RFC3339 time to estimate the cost since, defaults to the system's creation"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.CostEstimate
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleGetSystemCost(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	path := tree.RootPath()
+	if pathStr := c.Query("path"); pathStr != "" {
+		var err error
+		path, err = tree.NewPath(pathStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, v1.NewInvalidPathError())
+			return
+		}
+	}
+
+	var since time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, v1.NewInvalidCostOptionsError())
+			return
+		}
+	}
+
+	estimate, err := api.backend.Systems().Cost(systemID, path, since)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// handleSetSystemTracing handler for set-system-tracing
+// @ID set-system-tracing
+// @Summary Set system tracing
+// @Description Sets the fraction of requests within the system that the service mesh traces
+// @Router /systems/{system}/tracing [put]
+// @Security ApiKeyAuth
+// @Tags systems
+// @Param system path string true "System ID"
+// @Param tracing body v1.SystemTracing true "System tracing"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.System
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleSetSystemTracing(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	var systemTracing v1.SystemTracing
+	if err := c.BindJSON(&systemTracing); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	if err := tracing.ValidateSystem(&systemTracing); err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidSystemTracingError())
+		return
+	}
+
+	system, err := api.backend.Systems().SetTracing(systemID, &systemTracing)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeConflict, v1.ErrorCodeSystemDeleting:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, system)
+}
+
+// handleSetSystemImagePolicy handler for set-system-image-policy
+// @ID set-system-image-policy
+// @Summary Set system image policy
+// @Description Sets the registries, digests and signatures required of the images the system's workloads use. An empty policy removes it.
+// @Router /systems/{system}/image-policy [put]
+// @Security ApiKeyAuth
+// @Tags systems
+// @Param system path string true "System ID"
+// @Param imagePolicy body v1.ImagePolicy true "Image policy"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.System
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleSetSystemImagePolicy(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	var policy v1.ImagePolicy
+	if err := c.BindJSON(&policy); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	if err := imagepolicy.Validate(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidImagePolicyError())
+		return
+	}
+
+	system, err := api.backend.Systems().SetImagePolicy(systemID, &policy)
+	if err != nil {
+		v1err, ok := err.(*v1.Error)
+		if !ok {
+			handleInternalError(c, err)
+			return
+		}
+
+		switch v1err.Code {
+		case v1.ErrorCodeInvalidSystemID:
+			c.JSON(http.StatusNotFound, v1err)
+
+		case v1.ErrorCodeConflict, v1.ErrorCodeSystemDeleting:
+			c.JSON(http.StatusConflict, v1err)
+
+		default:
+			handleInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, system)
+}
+
 // requestedLogOptions
 func requestedLogOptions(c *gin.Context) (*v1.ContainerLogOptions, error) {
 	// follow
@@ -0,0 +1,309 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	v1rest "github.com/mlab-lattice/lattice/pkg/api/v1/rest"
+	"github.com/mlab-lattice/lattice/pkg/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	webhookProviderIdentifier = "webhook_provider"
+
+	// maxWebhookPayloadSize is larger than the payloads git providers send
+	// for pushes of large numbers of commits.
+	maxWebhookPayloadSize = 25 * 1024 * 1024
+)
+
+var (
+	webhookProviderIdentifierPathComponent = fmt.Sprintf(":%v", webhookProviderIdentifier)
+	webhookPath                            = fmt.Sprintf(v1rest.WebhookPathFormat, webhookProviderIdentifierPathComponent)
+	systemWebhookPath                      = fmt.Sprintf(v1rest.SystemWebhookPathFormat, systemIdentifierPathComponent)
+	systemWebhookEventsPath                = fmt.Sprintf(v1rest.SystemWebhookEventsPathFormat, systemIdentifierPathComponent)
+)
+
+func (api *LatticeAPI) setupWebhookEndpoints() {
+
+	// get-webhook-config
+	api.router.GET(systemWebhookPath, api.handleGetWebhookConfig)
+
+	// set-webhook-config
+	api.router.PUT(systemWebhookPath, api.handleSetWebhookConfig)
+
+	// list-webhook-events
+	api.router.GET(systemWebhookEventsPath, api.handleListWebhookEvents)
+
+}
+
+func (api *LatticeAPI) setupWebhookReceiverEndpoints() {
+
+	// receive-webhook
+	api.router.POST(webhookPath, api.handleReceiveWebhook)
+
+}
+
+// handleGetWebhookConfig handler for get-webhook-config
+// @ID get-webhook-config
+// @Summary Get webhook config
+// @Description Gets how the system reacts to webhooks, or null if it does not
+// @Router /systems/{system}/webhook [get]
+// @Security ApiKeyAuth
+// @Tags webhooks
+// @Param system path string true "System ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.WebhookConfig
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleGetWebhookConfig(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	config, err := api.backend.Systems().Webhooks(systemID).Config()
+	if err != nil {
+		handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, config)
+}
+
+// handleSetWebhookConfig handler for set-webhook-config
+// @ID set-webhook-config
+// @Summary Set webhook config
+// @Description Sets how the system reacts to webhooks
+// @Router /systems/{system}/webhook [put]
+// @Security ApiKeyAuth
+// @Tags webhooks
+// @Param system path string true "System ID"
+// @Param webhookConfig body v1.WebhookConfig true "Webhook config"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} v1.WebhookConfig
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleSetWebhookConfig(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	var config v1.WebhookConfig
+	if err := c.BindJSON(&config); err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	if err := webhook.ValidateConfig(&config); err != nil {
+		c.JSON(http.StatusBadRequest, v1.NewInvalidWebhookConfigError())
+		return
+	}
+
+	if err := api.backend.Systems().Webhooks(systemID).SetConfig(&config); err != nil {
+		handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, config)
+}
+
+// handleListWebhookEvents handler for list-webhook-events
+// @ID list-webhook-events
+// @Summary List webhook events
+// @Description Lists the most recent pushes webhooks were received for, and the builds and deploys they triggered
+// @Router /systems/{system}/webhook/events [get]
+// @Security ApiKeyAuth
+// @Tags webhooks
+// @Param system path string true "System ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} v1.WebhookEvent
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleListWebhookEvents(c *gin.Context) {
+	systemID := v1.SystemID(c.Param(systemIdentifier))
+
+	events, err := api.backend.Systems().Webhooks(systemID).ListEvents()
+	if err != nil {
+		handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func handleWebhookError(c *gin.Context, err error) {
+	v1err, ok := err.(*v1.Error)
+	if !ok {
+		handleInternalError(c, err)
+		return
+	}
+
+	switch v1err.Code {
+	case v1.ErrorCodeInvalidSystemID:
+		c.JSON(http.StatusNotFound, v1err)
+
+	case v1.ErrorCodeSystemDeleting, v1.ErrorCodeSystemPending:
+		c.JSON(http.StatusConflict, v1err)
+
+	default:
+		handleInternalError(c, err)
+	}
+}
+
+// handleReceiveWebhook handler for receive-webhook
+// @ID receive-webhook
+// @Summary Receive webhook
+// @Description Triggers builds and deploys of the systems whose definition repository a push was made to
+// @Router /webhooks/{provider} [post]
+// @Tags webhooks
+// @Param provider path string true "Git provider (github, gitlab or gitea)"
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} v1.WebhookEvent
+// @Failure 400 {object} v1.ErrorResponse
+// @Failure 401 {object} v1.ErrorResponse
+// @Failure 404 {object} v1.ErrorResponse
+func (api *LatticeAPI) handleReceiveWebhook(c *gin.Context) {
+	provider := v1.WebhookProvider(c.Param(webhookProviderIdentifier))
+	if !webhook.IsSupportedProvider(provider) {
+		c.JSON(http.StatusNotFound, v1.NewInvalidWebhookProviderError())
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayloadSize))
+	if err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	push, err := webhook.Parse(provider, c.Request.Header, body)
+	if err != nil {
+		handleBadRequestBody(c)
+		return
+	}
+
+	// pings and deleted branches and tags don't trigger anything
+	events := make([]v1.WebhookEvent, 0)
+	if push == nil {
+		c.JSON(http.StatusOK, events)
+		return
+	}
+
+	systems, err := api.backend.Systems().List()
+	if err != nil {
+		handleInternalError(c, err)
+		return
+	}
+
+	configured := false
+	for _, system := range systems {
+		if !webhook.MatchesRepository(system.DefinitionURL, push) {
+			continue
+		}
+
+		config, err := api.backend.Systems().Webhooks(system.ID).Config()
+		if err != nil {
+			handleInternalError(c, err)
+			return
+		}
+
+		if config == nil {
+			continue
+		}
+		configured = true
+
+		// systems share the endpoint, so only the systems whose secret the
+		// webhook was signed with react to it
+		secret, err := api.backend.Systems().Secrets(system.ID).Reveal(config.Secret, "", 0)
+		if err != nil {
+			if v1err, ok := err.(*v1.Error); ok && v1err.Code == v1.ErrorCodeInvalidSecret {
+				continue
+			}
+
+			handleInternalError(c, err)
+			return
+		}
+
+		if err := webhook.Verify(provider, c.Request.Header, body, []byte(secret.Value)); err != nil {
+			continue
+		}
+
+		event, err := api.triggerWebhookActions(&system, config, provider, push)
+		if err != nil {
+			handleInternalError(c, err)
+			return
+		}
+
+		events = append(events, *event)
+	}
+
+	if configured && len(events) == 0 {
+		c.JSON(http.StatusUnauthorized, v1.NewInvalidWebhookSignatureError())
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// triggerWebhookActions builds or deploys the pushed commit according to the
+// first of the system's rules matching the push, and records the event.
+// Tags are built as versions, and branches are built from the pushed commit.
+func (api *LatticeAPI) triggerWebhookActions(
+	system *v1.System,
+	config *v1.WebhookConfig,
+	provider v1.WebhookProvider,
+	push *webhook.Push,
+) (*v1.WebhookEvent, error) {
+	event := &v1.WebhookEvent{
+		Provider:      provider,
+		RepositoryURL: system.DefinitionURL,
+		Branch:        push.Branch,
+		Tag:           push.Tag,
+		Commit:        push.Commit,
+	}
+
+	rule := webhook.MatchRule(config.Rules, push)
+	if rule == nil {
+		event.Message = "push did not match any rule"
+	} else {
+		action := rule.Action
+		event.Action = &action
+
+		var build *v1.Build
+		var err error
+		if push.Tag != nil {
+			build, err = api.backend.Systems().Builds(system.ID).CreateFromVersion(v1.Version(*push.Tag))
+		} else {
+			build, err = api.backend.Systems().Builds(system.ID).CreateFromCommit(push.Commit)
+		}
+
+		if err == nil {
+			event.Build = &build.ID
+
+			if action == v1.WebhookActionDeploy {
+				var deploy *v1.Deploy
+				deploy, err = api.backend.Systems().Deploys(system.ID).CreateFromBuild(build.ID, "")
+				if err == nil {
+					event.Deploy = &deploy.ID
+				}
+			}
+		}
+
+		if err != nil {
+			// errors such as the system being deleted are recorded on the event
+			// rather than failing the webhook for other systems
+			v1err, ok := err.(*v1.Error)
+			if !ok {
+				return nil, err
+			}
+			event.Message = fmt.Sprintf("error triggering %v: %v", action, v1err.Code)
+		}
+	}
+
+	if err := api.backend.Systems().Webhooks(system.ID).RecordEvent(event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
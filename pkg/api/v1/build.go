@@ -6,8 +6,9 @@ import (
 )
 
 type (
-	BuildID    string
-	BuildState string
+	BuildID       string
+	BuildState    string
+	BuildSourceID string
 )
 
 const (
@@ -19,15 +20,36 @@ const (
 	BuildStateFailed    BuildState = "failed"
 )
 
+// BuildSourceVersion is the version of builds of a build source.
+const BuildSourceVersion Version = "local"
+
 type Build struct {
 	ID BuildID `json:"id"`
 
 	Path    *tree.Path `json:"path,omitempty"`
 	Version *Version   `json:"version,omitempty"`
 
+	// Source is set if the build is of a snapshot of a local directory rather
+	// than of the system's definition repository. These builds include uncommitted
+	// changes, so they cannot be reproduced.
+	Source *BuildSource `json:"source,omitempty"`
+
+	// Commit is set if the build is of a commit of the system's definition
+	// repository, for example one pushed to a branch.
+	Commit *string `json:"commit,omitempty"`
+
 	Status BuildStatus `json:"status"`
 }
 
+// BuildSource is an uploaded snapshot of a directory that can be built
+// in place of the system's definition repository.
+type BuildSource struct {
+	ID BuildSourceID `json:"id"`
+
+	// Digest of the stored snapshot.
+	Digest string `json:"digest"`
+}
+
 type BuildStatus struct {
 	State   BuildState `json:"state"`
 	Message string     `json:"message,omitempty"`
@@ -49,9 +71,10 @@ type WorkloadBuild struct {
 }
 
 type (
-	ContainerBuildState string
-	ContainerBuildPhase string
-	ContainerBuildID    string
+	ContainerBuildState       string
+	ContainerBuildPhase       string
+	ContainerBuildID          string
+	ContainerBuildCacheResult string
 )
 
 const (
@@ -65,14 +88,26 @@ const (
 	ContainerBuildStateRunning   ContainerBuildState = "running"
 	ContainerBuildStateSucceeded ContainerBuildState = "succeeded"
 	ContainerBuildStateFailed    ContainerBuildState = "failed"
+
+	ContainerBuildCacheHit  ContainerBuildCacheResult = "hit"
+	ContainerBuildCacheMiss ContainerBuildCacheResult = "miss"
 )
 
 type ContainerBuild struct {
 	ID ContainerBuildID `json:"id"`
 
+	// Reuse explains why the build reused a container build from a previous
+	// build or ran a new one.
+	Reuse *ContainerBuildReuse `json:"reuse,omitempty"`
+
 	Status ContainerBuildStatus `json:"status"`
 }
 
+type ContainerBuildReuse struct {
+	Reused bool   `json:"reused"`
+	Reason string `json:"reason"`
+}
+
 type ContainerBuildStatus struct {
 	State ContainerBuildState `json:"state"`
 
@@ -81,6 +116,98 @@ type ContainerBuildStatus struct {
 
 	LastObservedPhase *ContainerBuildPhase `json:"lastObservedPhase,omitempty"`
 	FailureMessage    *string              `json:"failureMessage,omitempty"`
+
+	Cache *ContainerBuildCacheInfo `json:"cache,omitempty"`
+
+	Artifacts  *ContainerBuildArtifacts  `json:"artifacts,omitempty"`
+	Provenance *ContainerBuildProvenance `json:"provenance,omitempty"`
+}
+
+// ContainerBuildArtifacts describes the image produced by a successful build.
+type ContainerBuildArtifacts struct {
+	DockerImageFQN string `json:"dockerImageFqn"`
+
+	// DockerImageDigest is the digest of the image's manifest. It is only
+	// set if the image was pushed to a registry.
+	DockerImageDigest string `json:"dockerImageDigest,omitempty"`
+}
+
+// ContainerBuildProvenance describes how a container build's image was produced.
+type ContainerBuildProvenance struct {
+	// BuilderVersion is the image of the builder that ran the build.
+	BuilderVersion string `json:"builderVersion,omitempty"`
+	ImageBuilder   string `json:"imageBuilder,omitempty"`
+
+	DockerImageDigest string `json:"dockerImageDigest,omitempty"`
+
+	BaseImage *ContainerBuildProvenanceImage   `json:"baseImage,omitempty"`
+	Sources   []ContainerBuildProvenanceSource `json:"sources,omitempty"`
+	BuildArgs map[string]string                `json:"buildArgs,omitempty"`
+
+	// SBOM can be large, so it is stored separately from the build and is
+	// only included in the build's provenance document. SBOMDigest is the
+	// digest of the stored SBOM.
+	SBOM       *ContainerBuildSBOM `json:"sbom,omitempty"`
+	SBOMDigest string              `json:"sbomDigest,omitempty"`
+}
+
+type ContainerBuildProvenanceImage struct {
+	DockerImageFQN string `json:"dockerImageFqn"`
+	Digest         string `json:"digest,omitempty"`
+}
+
+// ContainerBuildProvenanceSource is a git repository retrieved by the build,
+// and the commit that the requested reference resolved to.
+type ContainerBuildProvenanceSource struct {
+	GitRepositoryURL string `json:"gitRepositoryUrl"`
+	Commit           string `json:"commit"`
+}
+
+// ContainerBuildSBOM lists the OS packages installed in a build's image.
+type ContainerBuildSBOM struct {
+	Packages []ContainerBuildPackage `json:"packages"`
+}
+
+type ContainerBuildPackage struct {
+	// Type is the package manager that installed the package, e.g. deb or apk.
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// BuildProvenance is the provenance document of a build, containing the
+// provenance of each of its successful container builds.
+type BuildProvenance struct {
+	ID BuildID `json:"id"`
+
+	Path    *tree.Path `json:"path,omitempty"`
+	Version *Version   `json:"version,omitempty"`
+
+	Workloads map[tree.Path]WorkloadBuildProvenance `json:"workloads"`
+}
+
+type WorkloadBuildProvenance struct {
+	ContainerBuildProvenanceDocument
+	Sidecars map[string]ContainerBuildProvenanceDocument `json:"sidecars,omitempty"`
+}
+
+type ContainerBuildProvenanceDocument struct {
+	ID             ContainerBuildID `json:"id"`
+	DockerImageFQN string           `json:"dockerImageFqn"`
+
+	ContainerBuildProvenance
+}
+
+// ContainerBuildCacheInfo describes which parts of the build cache
+// the build was able to reuse.
+type ContainerBuildCacheInfo struct {
+	// GitRepository is whether the build's git repositories were
+	// already mirrored in the build cache.
+	GitRepository ContainerBuildCacheResult `json:"gitRepository,omitempty"`
+
+	// DockerLayers is whether the layers of a previous build of
+	// the container were available to the build.
+	DockerLayers ContainerBuildCacheResult `json:"dockerLayers,omitempty"`
 }
 
 type ContainerBuildFailureInfo struct {
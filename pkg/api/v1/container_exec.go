@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"io"
+)
+
+// TerminalSize is the size of the terminal of a command run with a TTY.
+type TerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// ContainerExecStreams are the streams of a command run in a container.
+// Stdin is nil if the command does not read from stdin, and Resize is nil
+// if the command is not run with a TTY. Commands run with a TTY write both
+// stdout and stderr to Stdout.
+// +k8s:deepcopy-gen=false
+type ContainerExecStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Resize <-chan TerminalSize
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/time"
+)
+
+// CostEstimate is the estimated cost of the services and node pools of a
+// system under a path.
+type CostEstimate struct {
+	Path tree.Path `json:"path"`
+
+	// HourlyCost is the cost of the path at its current instances, and
+	// ProjectedMonthlyCost is that cost over a month.
+	HourlyCost           float64 `json:"hourlyCost"`
+	ProjectedMonthlyCost float64 `json:"projectedMonthlyCost"`
+
+	// Cost is the cost of the path since Since, from the hours its node pools'
+	// and services' instances ran for. Hours older than the backend keeps, and
+	// those of node pools and services that have since been deleted, may not
+	// be included.
+	Since time.Time `json:"since"`
+	Cost  float64   `json:"cost"`
+
+	// Services are the hourly cost allocated to each service under the path
+	// from the node pools it runs on.
+	Services map[tree.Path]float64 `json:"services"`
+
+	// NodePools are the hourly cost of each node pool under the path that
+	// is not running any services.
+	NodePools map[tree.PathSubcomponent]float64 `json:"nodePools"`
+
+	// UnpricedInstanceTypes are the instance types that do not have a price,
+	// whose instances are not included in the estimate.
+	UnpricedInstanceTypes []string `json:"unpricedInstanceTypes,omitempty"`
+}
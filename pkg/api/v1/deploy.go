@@ -11,11 +11,13 @@ type (
 )
 
 const (
-	DeployStatePending    DeployState = "pending"
-	DeployStateAccepted   DeployState = "accepted"
-	DeployStateInProgress DeployState = "in progress"
-	DeployStateSucceeded  DeployState = "succeeded"
-	DeployStateFailed     DeployState = "failed"
+	DeployStatePending          DeployState = "pending"
+	DeployStateAwaitingApproval DeployState = "awaiting approval"
+	DeployStateQueued           DeployState = "queued"
+	DeployStateAccepted         DeployState = "accepted"
+	DeployStateInProgress       DeployState = "in progress"
+	DeployStateSucceeded        DeployState = "succeeded"
+	DeployStateFailed           DeployState = "failed"
 )
 
 type Deploy struct {
@@ -25,6 +27,23 @@ type Deploy struct {
 	Path    *tree.Path `json:"path,omitempty"`
 	Version *Version   `json:"version,omitempty"`
 
+	// Source is set if the deploy's build is of a build source, in
+	// which case the deploy cannot be reproduced.
+	Source *BuildSource `json:"source,omitempty"`
+
+	// CreatedBy is the user that created the deploy, if it was created
+	// by an authenticated user.
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	// Approvals are the decisions made about the deploy by approvers
+	// while it was awaiting approval, in the order they were made.
+	Approvals []DeployApproval `json:"approvals,omitempty"`
+
+	// MonthlyCostDelta is the projected change in the system's monthly cost
+	// from the deploy's definition. It is estimated once the deploy's build has
+	// resolved its definition, and fixed once the deploy has been applied.
+	MonthlyCostDelta *float64 `json:"monthlyCostDelta,omitempty"`
+
 	Status DeployStatus `json:"status"`
 }
 
@@ -36,6 +55,10 @@ type DeployStatus struct {
 	Path    *tree.Path `json:"path,omitempty"`
 	Version *Version   `json:"version,omitempty"`
 
+	// QueuePosition is set while the deploy is queued behind deploys
+	// to overlapping paths, starting at 1 for the next deploy to run.
+	QueuePosition *int32 `json:"queuePosition,omitempty"`
+
 	StartTimestamp      *time.Time `json:"startTimestamp,omitempty"`
 	CompletionTimestamp *time.Time `json:"completionTimestamp,omitempty"`
 }
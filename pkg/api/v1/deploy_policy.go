@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/util/time"
+)
+
+type DeployApprovalDecision string
+
+const (
+	DeployApprovalDecisionApproved DeployApprovalDecision = "approved"
+	DeployApprovalDecisionRejected DeployApprovalDecision = "rejected"
+)
+
+// DeployPolicy restricts when the deploys of a system can run without being
+// approved. Deploys created outside of the policy wait in the awaiting approval
+// state until they are approved or rejected.
+type DeployPolicy struct {
+	// Approval configures who can approve deploys, and whether every deploy
+	// has to be approved.
+	Approval *DeployApprovalPolicy `json:"approval,omitempty"`
+
+	// ChangeWindows are the times during which deploys can be created without
+	// being approved. If there are none deploys can be created at any time
+	// that is not frozen.
+	ChangeWindows []DeployChangeWindow `json:"changeWindows,omitempty"`
+
+	// Freezes are periods during which every deploy has to be approved.
+	Freezes []DeployFreeze `json:"freezes,omitempty"`
+}
+
+// DeployApprovalPolicy configures who can approve deploys. The user that
+// created a deploy can never approve it.
+type DeployApprovalPolicy struct {
+	// Always requires every deploy to be approved, not only those created
+	// outside of change windows or during freezes.
+	Always bool `json:"always,omitempty"`
+
+	// Approvals is the number of approvals a deploy needs, defaulting to 1.
+	Approvals int32 `json:"approvals,omitempty"`
+
+	// Users and Roles are the users, and the roles of users, that can approve
+	// or reject deploys. If both are empty any user can.
+	Users []string `json:"users,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// DeployChangeWindow is a recurring window of time during which deploys can
+// be created without being approved.
+type DeployChangeWindow struct {
+	// Schedule is a cron expression (minute, hour, day of month, month and
+	// day of week) of when the window opens, e.g. "0 9 * * 1-4".
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open after it opens, e.g. "6h".
+	Duration string `json:"duration"`
+
+	// Timezone is the IANA time zone the schedule is in, defaulting to UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// DeployFreeze is a period of time during which every deploy has to be approved.
+type DeployFreeze struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+type DeployApproval struct {
+	User      string                 `json:"user"`
+	Decision  DeployApprovalDecision `json:"decision"`
+	Comment   string                 `json:"comment,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
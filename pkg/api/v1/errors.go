@@ -3,18 +3,28 @@ package v1
 type ErrorCode string
 
 const (
-	ErrorCodeUnknown  ErrorCode = "UNKNOWN"
-	ErrorCodeConflict ErrorCode = "CONFLICT"
+	ErrorCodeUnknown         ErrorCode = "UNKNOWN"
+	ErrorCodeConflict        ErrorCode = "CONFLICT"
+	ErrorCodeUnauthenticated ErrorCode = "UNAUTHENTICATED"
 
-	ErrorCodeInvalidBuildID ErrorCode = "INVALID_BUILD_ID"
+	ErrorCodeInvalidBuildID       ErrorCode = "INVALID_BUILD_ID"
+	ErrorCodeInvalidBuildSourceID ErrorCode = "INVALID_BUILD_SOURCE_ID"
 
-	ErrorCodeInvalidDeployID ErrorCode = "INVALID_DEPLOY_ID"
+	ErrorCodeInvalidDeployID           ErrorCode = "INVALID_DEPLOY_ID"
+	ErrorCodeInvalidDeployPolicy       ErrorCode = "INVALID_DEPLOY_POLICY"
+	ErrorCodeDeployNotAwaitingApproval ErrorCode = "DEPLOY_NOT_AWAITING_APPROVAL"
+	ErrorCodeDeployApprovalForbidden   ErrorCode = "DEPLOY_APPROVAL_FORBIDDEN"
 
 	ErrorCodeInvalidJobID ErrorCode = "INVALID_JOB_ID"
 
-	ErrorCodeInvalidSecret ErrorCode = "INVALID_SECRET"
+	ErrorCodeInvalidNodePoolOverride ErrorCode = "INVALID_NODE_POOL_OVERRIDE"
 
-	ErrorCodeInvalidServiceID ErrorCode = "INVALID_SERVICE_ID"
+	ErrorCodeInvalidSecret         ErrorCode = "INVALID_SECRET"
+	ErrorCodeInvalidSecretProvider ErrorCode = "INVALID_SECRET_PROVIDER"
+	ErrorCodeInvalidSecretVersion  ErrorCode = "INVALID_SECRET_VERSION"
+
+	ErrorCodeInvalidServiceID    ErrorCode = "INVALID_SERVICE_ID"
+	ErrorCodeInvalidServiceFault ErrorCode = "INVALID_SERVICE_FAULT"
 
 	ErrorCodeSystemAlreadyExists  ErrorCode = "SYSTEM_ALREADY_EXISTS"
 	ErrorCodeInvalidSystemID      ErrorCode = "INVALID_SYSTEM_ID"
@@ -22,13 +32,28 @@ const (
 	ErrorCodeSystemFailed         ErrorCode = "SYSTEM_FAILED"
 	ErrorCodeSystemPending        ErrorCode = "SYSTEM_PENDING"
 	ErrorCodeInvalidSystemOptions ErrorCode = "INVALID_SYSTEM_OPTIONS"
+	ErrorCodeInvalidSystemQuota   ErrorCode = "INVALID_SYSTEM_QUOTA"
+	ErrorCodeSystemQuotaExceeded  ErrorCode = "SYSTEM_QUOTA_EXCEEDED"
+
+	ErrorCodeInvalidContainerResources ErrorCode = "INVALID_CONTAINER_RESOURCES"
+
+	ErrorCodeInvalidSystemTracing ErrorCode = "INVALID_SYSTEM_TRACING"
+
+	ErrorCodeInvalidImagePolicy ErrorCode = "INVALID_IMAGE_POLICY"
+
+	ErrorCodeInvalidCostOptions ErrorCode = "INVALID_COST_OPTIONS"
 
 	ErrorCodeInvalidTeardownID ErrorCode = "INVALID_TEARDOWN_ID"
 
-	ErrorCodeInvalidInstance ErrorCode = "INVALID_INSTANCE"
-	ErrorCodeInvalidPath     ErrorCode = "INVALID_PATH"
-	ErrorCodeInvalidSidecar  ErrorCode = "INVALID_SIDECAR"
-	ErrorCodeInvalidVersion  ErrorCode = "INVALID_VERSION"
+	ErrorCodeInvalidWebhookConfig    ErrorCode = "INVALID_WEBHOOK_CONFIG"
+	ErrorCodeInvalidWebhookProvider  ErrorCode = "INVALID_WEBHOOK_PROVIDER"
+	ErrorCodeInvalidWebhookSignature ErrorCode = "INVALID_WEBHOOK_SIGNATURE"
+
+	ErrorCodeInvalidInstance      ErrorCode = "INVALID_INSTANCE"
+	ErrorCodeInvalidPath          ErrorCode = "INVALID_PATH"
+	ErrorCodeInvalidSidecar       ErrorCode = "INVALID_SIDECAR"
+	ErrorCodeInvalidStreamOptions ErrorCode = "INVALID_STREAM_OPTIONS"
+	ErrorCodeInvalidVersion       ErrorCode = "INVALID_VERSION"
 )
 
 type Error struct {
@@ -51,26 +76,62 @@ func NewConflictError() *Error {
 	return NewError(ErrorCodeConflict)
 }
 
+func NewUnauthenticatedError() *Error {
+	return NewError(ErrorCodeUnauthenticated)
+}
+
 func NewInvalidBuildIDError() *Error {
 	return NewError(ErrorCodeInvalidBuildID)
 }
 
+func NewInvalidBuildSourceIDError() *Error {
+	return NewError(ErrorCodeInvalidBuildSourceID)
+}
+
 func NewInvalidDeployIDError() *Error {
 	return NewError(ErrorCodeInvalidDeployID)
 }
 
+func NewInvalidDeployPolicyError() *Error {
+	return NewError(ErrorCodeInvalidDeployPolicy)
+}
+
+func NewDeployNotAwaitingApprovalError() *Error {
+	return NewError(ErrorCodeDeployNotAwaitingApproval)
+}
+
+func NewDeployApprovalForbiddenError() *Error {
+	return NewError(ErrorCodeDeployApprovalForbidden)
+}
+
 func NewInvalidJobIDError() *Error {
 	return NewError(ErrorCodeInvalidJobID)
 }
 
+func NewInvalidNodePoolOverrideError() *Error {
+	return NewError(ErrorCodeInvalidNodePoolOverride)
+}
+
 func NewInvalidSecretError() *Error {
 	return NewError(ErrorCodeInvalidSecret)
 }
 
+func NewInvalidSecretProviderError() *Error {
+	return NewError(ErrorCodeInvalidSecretProvider)
+}
+
+func NewInvalidSecretVersionError() *Error {
+	return NewError(ErrorCodeInvalidSecretVersion)
+}
+
 func NewInvalidServiceIDError() *Error {
 	return NewError(ErrorCodeInvalidServiceID)
 }
 
+func NewInvalidServiceFaultError() *Error {
+	return NewError(ErrorCodeInvalidServiceFault)
+}
+
 func NewSystemAlreadyExistsError() *Error {
 	return NewError(ErrorCodeSystemAlreadyExists)
 }
@@ -95,6 +156,30 @@ func NewInvalidSystemOptionsError() *Error {
 	return NewError(ErrorCodeInvalidSystemOptions)
 }
 
+func NewInvalidSystemQuotaError() *Error {
+	return NewError(ErrorCodeInvalidSystemQuota)
+}
+
+func NewSystemQuotaExceededError() *Error {
+	return NewError(ErrorCodeSystemQuotaExceeded)
+}
+
+func NewInvalidContainerResourcesError() *Error {
+	return NewError(ErrorCodeInvalidContainerResources)
+}
+
+func NewInvalidCostOptionsError() *Error {
+	return NewError(ErrorCodeInvalidCostOptions)
+}
+
+func NewInvalidSystemTracingError() *Error {
+	return NewError(ErrorCodeInvalidSystemTracing)
+}
+
+func NewInvalidImagePolicyError() *Error {
+	return NewError(ErrorCodeInvalidImagePolicy)
+}
+
 func NewInvalidVersionError() *Error {
 	return NewError(ErrorCodeInvalidVersion)
 }
@@ -103,6 +188,18 @@ func NewInvalidTeardownIDError() *Error {
 	return NewError(ErrorCodeInvalidTeardownID)
 }
 
+func NewInvalidWebhookConfigError() *Error {
+	return NewError(ErrorCodeInvalidWebhookConfig)
+}
+
+func NewInvalidWebhookProviderError() *Error {
+	return NewError(ErrorCodeInvalidWebhookProvider)
+}
+
+func NewInvalidWebhookSignatureError() *Error {
+	return NewError(ErrorCodeInvalidWebhookSignature)
+}
+
 func NewInvalidInstanceError() *Error {
 	return NewError(ErrorCodeInvalidInstance)
 }
@@ -114,3 +211,7 @@ func NewInvalidPathError() *Error {
 func NewInvalidSidecarError() *Error {
 	return NewError(ErrorCodeInvalidSidecar)
 }
+
+func NewInvalidStreamOptionsError() *Error {
+	return NewError(ErrorCodeInvalidStreamOptions)
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+)
+
+// ImagePolicy restricts the images that a system's workloads can be built from
+// and run. Builds and deploys of images that do not satisfy it fail.
+type ImagePolicy struct {
+	// AllowedRegistries are the registries that images can be pulled from. An
+	// entry starting with "*." allows any subdomain. If empty, any registry is
+	// allowed.
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// RequireDigest requires images to be referenced by digest rather than by tag.
+	RequireDigest bool `json:"requireDigest,omitempty"`
+
+	// SignatureKeys are the keys that images can be signed with. If there are
+	// any, images must have a cosign signature made by one of them.
+	SignatureKeys []ImagePolicySignatureKey `json:"signatureKeys,omitempty"`
+}
+
+// ImagePolicySignatureKey references the system secret holding a PEM encoded
+// ECDSA public key.
+type ImagePolicySignatureKey struct {
+	Secret tree.PathSubcomponent `json:"secret"`
+
+	// Provider is the name of the secret provider the secret is stored in. If
+	// empty, the system's default secret provider is used.
+	Provider string `json:"provider,omitempty"`
+}
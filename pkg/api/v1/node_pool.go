@@ -25,16 +25,65 @@ type NodePool struct {
 	InstanceType string `json:"instanceType"`
 	NumInstances int32  `json:"numInstances"`
 
+	// InstanceTypes are all of the instance types the node pool's instances
+	// may be launched as.
+	InstanceTypes []string      `json:"instanceTypes,omitempty"`
+	Spot          *NodePoolSpot `json:"spot,omitempty"`
+
+	Autoscaling *NodePoolAutoscaling `json:"autoscaling,omitempty"`
+	Override    *NodePoolOverride    `json:"override,omitempty"`
+
 	Status NodePoolStatus `json:"status"`
 }
 
+// NodePoolAutoscaling bounds the number of instances of a node pool that is
+// scaled to fit the workloads running on it. Autoscaling is paused while the
+// node pool is overridden.
+type NodePoolAutoscaling struct {
+	MinInstances int32 `json:"minInstances"`
+	MaxInstances int32 `json:"maxInstances"`
+}
+
+// NodePoolSpot is the mix of on-demand and spot instances in a node pool.
+type NodePoolSpot struct {
+	OnDemandBaseInstances int32  `json:"onDemandBaseInstances"`
+	OnDemandPercentage    int32  `json:"onDemandPercentage"`
+	Fallback              string `json:"fallback"`
+
+	// FallbackTimestamp is set while the node pool is launching on-demand
+	// instances in place of spot instances because spot capacity is unavailable.
+	FallbackTimestamp *time.Time `json:"fallbackTimestamp,omitempty"`
+}
+
+// NodePoolOverride is a change made to a node pool outside of a deploy. The
+// node pool drifts from the system's definition until the next deploy, which
+// removes the override.
+type NodePoolOverride struct {
+	NumInstances int32 `json:"numInstances"`
+	// DefinitionNumInstances is the number of instances the system's definition
+	// specifies for the node pool.
+	DefinitionNumInstances int32     `json:"definitionNumInstances"`
+	Timestamp              time.Time `json:"timestamp"`
+}
+
 type NodePoolStatus struct {
 	State       NodePoolState        `json:"state"`
 	FailureInfo *NodePoolFailureInfo `json:"failureInfo,omitempty"`
 
-	// FIXME: how to deal with epochs?
 	InstanceType string `json:"instanceType"`
 	NumInstances int32  `json:"numInstances"`
+
+	// Epochs are the manifestations of the node pool that currently have
+	// instances, oldest first. Changing a node pool's instance type creates a
+	// new epoch, and the older epochs are drained once the new one is ready.
+	Epochs []NodePoolEpochStatus `json:"epochs,omitempty"`
+}
+
+type NodePoolEpochStatus struct {
+	Epoch        int64         `json:"epoch"`
+	State        NodePoolState `json:"state"`
+	InstanceType string        `json:"instanceType"`
+	NumInstances int32         `json:"numInstances"`
 }
 
 type NodePoolFailureInfo struct {
@@ -6,30 +6,57 @@ const (
 	SystemsPath      = RootPath + "/systems"
 	SystemPathFormat = SystemsPath + "/%v"
 
-	BuildsPathFormat    = SystemPathFormat + "/builds"
-	BuildPathFormat     = BuildsPathFormat + "/%v"
-	BuildLogsPathFormat = BuildPathFormat + "/logs"
+	SystemQuotaPathFormat              = SystemPathFormat + "/quota"
+	SystemContainerResourcesPathFormat = SystemPathFormat + "/container-resources"
+	SystemTracingPathFormat            = SystemPathFormat + "/tracing"
+	SystemImagePolicyPathFormat        = SystemPathFormat + "/image-policy"
+	SystemCostPathFormat               = SystemPathFormat + "/cost"
 
-	DeploysPathFormat = SystemPathFormat + "/deploys"
-	DeployPathFormat  = DeploysPathFormat + "/%v"
+	BuildsPathFormat          = SystemPathFormat + "/builds"
+	BuildPathFormat           = BuildsPathFormat + "/%v"
+	BuildLogsPathFormat       = BuildPathFormat + "/logs"
+	BuildProvenancePathFormat = BuildPathFormat + "/provenance"
 
-	NodePoolsPathFormat = SystemPathFormat + "/node-pools"
-	NodePoolPathFormat  = NodePoolsPathFormat + "/%v"
+	BuildSourcesPathFormat = SystemPathFormat + "/build-sources"
 
-	SystemSecretsPathFormat = SystemPathFormat + "/secrets"
-	SystemSecretPathFormat  = SystemSecretsPathFormat + "/%v"
+	DeploysPathFormat       = SystemPathFormat + "/deploys"
+	DeployPathFormat        = DeploysPathFormat + "/%v"
+	DeployApprovePathFormat = DeployPathFormat + "/approve"
+	DeployRejectPathFormat  = DeployPathFormat + "/reject"
+
+	SystemDeployPolicyPathFormat = SystemPathFormat + "/deploy-policy"
+
+	NodePoolsPathFormat        = SystemPathFormat + "/node-pools"
+	NodePoolPathFormat         = NodePoolsPathFormat + "/%v"
+	NodePoolOverridePathFormat = NodePoolPathFormat + "/override"
+
+	SystemSecretsPathFormat        = SystemPathFormat + "/secrets"
+	SystemSecretPathFormat         = SystemSecretsPathFormat + "/%v"
+	SystemSecretVersionsPathFormat = SystemSecretPathFormat + "/versions"
+	SystemSecretRevealPathFormat   = SystemSecretPathFormat + "/reveal"
 
 	JobsPathFormat    = SystemPathFormat + "/jobs"
 	JobPathFormat     = JobsPathFormat + "/%v"
 	JobLogsPathFormat = JobPathFormat + "/logs"
 
-	ServicesPathFormat    = SystemPathFormat + "/services"
-	ServicePathFormat     = ServicesPathFormat + "/%v"
-	ServiceLogsPathFormat = ServicePathFormat + "/logs"
+	ServicesPathFormat           = SystemPathFormat + "/services"
+	ServicePathFormat            = ServicesPathFormat + "/%v"
+	ServiceLogsPathFormat        = ServicePathFormat + "/logs"
+	ServiceFaultPathFormat       = ServicePathFormat + "/fault"
+	ServiceExecPathFormat        = ServicePathFormat + "/exec"
+	ServicePortForwardPathFormat = ServicePathFormat + "/port-forward"
 
 	TeardownsPathFormat = SystemPathFormat + "/teardowns"
 	TeardownPathFormat  = TeardownsPathFormat + "/%v"
 
 	VersionsPathFormat = SystemPathFormat + "/versions"
 	VersionPathFormat  = VersionsPathFormat + "/%v"
+
+	SystemWebhookPathFormat       = SystemPathFormat + "/webhook"
+	SystemWebhookEventsPathFormat = SystemWebhookPathFormat + "/events"
+
+	// WebhookPathFormat is the path that git providers deliver webhooks to.
+	// Requests to it are authenticated by the payload's signature rather than
+	// the lattice's authenticators.
+	WebhookPathFormat = RootPath + "/webhooks/%v"
 )
@@ -12,16 +12,28 @@ type CreateSystemRequest struct {
 }
 
 type BuildRequest struct {
-	Path    *tree.Path  `json:"path,omitempty"`
-	Version *v1.Version `json:"version,omitempty"`
+	Path    *tree.Path        `json:"path,omitempty"`
+	Version *v1.Version       `json:"version,omitempty"`
+	Source  *v1.BuildSourceID `json:"source,omitempty"`
+	Commit  *string           `json:"commit,omitempty"`
 }
 
+// BuildSourceContentType is the content type of the tar archives
+// of directories uploaded to be built.
+const BuildSourceContentType = "application/x-tar"
+
 type DeployRequest struct {
 	BuildID *v1.BuildID `json:"buildId,omitempty"`
 	Path    *tree.Path  `json:"path,omitempty"`
 	Version *v1.Version `json:"version,omitempty"`
 }
 
+// DeployApprovalRequest is the body of requests approving or rejecting
+// deploys that are awaiting approval.
+type DeployApprovalRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
 type RunJobRequest struct {
 	Path        tree.Path                             `json:"path"`
 	Command     []string                              `json:"command,omitempty"`
@@ -29,5 +41,22 @@ type RunJobRequest struct {
 }
 
 type SetSecretRequest struct {
-	Value string `json:"value"`
+	Value    string `json:"value"`
+	Provider string `json:"provider,omitempty"`
+
+	// Restart causes the services and running jobs referencing the current
+	// version of the secret to be restarted so that they use the new value.
+	Restart bool `json:"restart,omitempty"`
+}
+
+type OverrideNodePoolRequest struct {
+	NumInstances int32 `json:"numInstances"`
+}
+
+type InjectServiceFaultRequest struct {
+	Delay      *v1.ServiceFaultDelay `json:"delay,omitempty"`
+	Abort      *v1.ServiceFaultAbort `json:"abort,omitempty"`
+	Percentage int32                 `json:"percentage"`
+	Source     *tree.Path            `json:"source,omitempty"`
+	Expiry     string                `json:"expiry"`
 }
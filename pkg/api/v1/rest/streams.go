@@ -0,0 +1,30 @@
+package rest
+
+// Requests to exec into and port forward to service instances are upgraded
+// to websockets. Each binary message sent over the websocket starts with the
+// channel of the stream it belongs to.
+//
+// Port forwards send the data of the forwarded connection on the stdin and
+// stdout channels. A message on the stdin channel without any data closes
+// stdin. Once the command exits or the forwarded connection is closed the
+// server sends a message on the error channel, which is empty on success
+// and otherwise holds a JSON encoded v1.Error, and closes the websocket.
+const (
+	StreamChannelStdin  byte = 0
+	StreamChannelStdout byte = 1
+	StreamChannelStderr byte = 2
+	StreamChannelError  byte = 3
+	// Messages on the resize channel hold a JSON encoded v1.TerminalSize.
+	StreamChannelResize byte = 4
+)
+
+// Query parameters of exec and port forward requests. The command parameter
+// is repeated for each of the command's arguments.
+const (
+	StreamQueryInstance = "instance"
+	StreamQuerySidecar  = "sidecar"
+	StreamQueryCommand  = "command"
+	StreamQueryStdin    = "stdin"
+	StreamQueryTTY      = "tty"
+	StreamQueryPort     = "port"
+)
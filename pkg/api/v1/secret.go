@@ -2,9 +2,21 @@ package v1
 
 import (
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/time"
 )
 
 type Secret struct {
-	Path  tree.PathSubcomponent `json:"path"`
-	Value string                `json:"value"`
+	Path tree.PathSubcomponent `json:"path"`
+
+	// Value is only populated when the secret is explicitly revealed.
+	Value string `json:"value,omitempty"`
+
+	// Provider is the name of the secret provider the secret is stored in.
+	Provider string `json:"provider,omitempty"`
+
+	// Version is the version of the secret's value, starting at 1 and increasing
+	// each time the secret is set.
+	Version           int32     `json:"version"`
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+	Author            string    `json:"author,omitempty"`
 }
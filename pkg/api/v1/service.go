@@ -25,6 +25,8 @@ type Service struct {
 
 	Path tree.Path `json:"path"`
 
+	Fault *ServiceFault `json:"fault,omitempty"`
+
 	Status ServiceStatus `json:"status"`
 }
 
@@ -47,3 +49,26 @@ type ServiceFailureInfo struct {
 	Time    time.Time `json:"time"`
 	Message string    `json:"message"`
 }
+
+// ServiceFault describes a fault that is injected into the traffic sent to a service
+// by the service mesh. Exactly one of Delay or Abort should be set.
+type ServiceFault struct {
+	Delay *ServiceFaultDelay `json:"delay,omitempty"`
+	Abort *ServiceFaultAbort `json:"abort,omitempty"`
+
+	// Percentage is the percentage of requests the fault should be applied to.
+	Percentage int32 `json:"percentage"`
+
+	// Source, if set, limits the fault to requests originating from the service at the path.
+	Source *tree.Path `json:"source,omitempty"`
+
+	Expires time.Time `json:"expires"`
+}
+
+type ServiceFaultDelay struct {
+	Duration string `json:"duration"`
+}
+
+type ServiceFaultAbort struct {
+	HTTPStatus int32 `json:"httpStatus"`
+}
@@ -24,9 +24,92 @@ type System struct {
 
 	DefinitionURL string `json:"definitionUrl"`
 
+	// Quota is nil if the system does not have a quota.
+	Quota *SystemQuota `json:"quota,omitempty"`
+
+	// ContainerResources is nil if the system does not have default or
+	// maximum container resources.
+	ContainerResources *SystemContainerResources `json:"containerResources,omitempty"`
+
+	// Tracing is nil if the system uses the service mesh's default tracing.
+	Tracing *SystemTracing `json:"tracing,omitempty"`
+
+	// ImagePolicy is nil if the system's workloads can use any image.
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
 	Status SystemStatus `json:"status"`
 }
 
+// SystemQuota limits the resources a system can use. Limits that are zero are
+// unlimited.
+//
+// The CPU, memory, instances and node pool instances of the system's definition
+// are checked when it is deployed. Job runs are checked when they are run, and
+// count the CPU and memory of the other running job runs as well as that of the
+// system's services. Builds are checked when they are created.
+type SystemQuota struct {
+	// MilliCPU is the CPU requested by the system's containers, in thousandths
+	// of a core, and MemoryMiB the memory requested by them, in mebibytes.
+	MilliCPU  int64 `json:"milliCpu,omitempty"`
+	MemoryMiB int64 `json:"memoryMiB,omitempty"`
+
+	// Instances are the instances of the system's services, and
+	// NodePoolInstances the instances of its node pools. Autoscaled node
+	// pools count their maximum number of instances.
+	Instances         int32 `json:"instances,omitempty"`
+	NodePoolInstances int32 `json:"nodePoolInstances,omitempty"`
+
+	// JobRuns and Builds are the number of job runs and builds that can be
+	// running at the same time.
+	JobRuns int32 `json:"jobRuns,omitempty"`
+	Builds  int32 `json:"builds,omitempty"`
+}
+
+// SystemUsage is the amount of each of the resources limited by a SystemQuota
+// that a system is using.
+type SystemUsage struct {
+	MilliCPU  int64 `json:"milliCpu"`
+	MemoryMiB int64 `json:"memoryMiB"`
+
+	Instances         int32 `json:"instances"`
+	NodePoolInstances int32 `json:"nodePoolInstances"`
+
+	JobRuns int32 `json:"jobRuns"`
+	Builds  int32 `json:"builds"`
+}
+
+// SystemContainerResources are the resources given to the system's containers
+// that don't specify them, and the most resources a container can have. They
+// are applied and checked when the system is deployed.
+type SystemContainerResources struct {
+	// DefaultRequests and DefaultLimits are applied to each resource that a
+	// container does not request or limit itself.
+	DefaultRequests *ContainerResourceList `json:"defaultRequests,omitempty"`
+	DefaultLimits   *ContainerResourceList `json:"defaultLimits,omitempty"`
+
+	// Max is the most of each resource that a container can request or be
+	// limited to.
+	Max *ContainerResourceList `json:"max,omitempty"`
+}
+
+// SystemTracing configures how the service mesh traces requests within the
+// system.
+type SystemTracing struct {
+	// SamplingRate is the fraction (0-1) of requests that are traced. If it
+	// is nil the service mesh's default sampling rate is used.
+	SamplingRate *float64 `json:"samplingRate,omitempty"`
+}
+
+// ContainerResourceList is an amount of each resource a container can use.
+// CPU, Memory and EphemeralStorage are kubernetes style quantities, for example
+// "500m" or "512Mi". Resources that are not set are not applied or limited.
+type ContainerResourceList struct {
+	CPU              string `json:"cpu,omitempty"`
+	Memory           string `json:"memory,omitempty"`
+	GPU              int32  `json:"gpu,omitempty"`
+	EphemeralStorage string `json:"ephemeralStorage,omitempty"`
+}
+
 type SystemStatus struct {
 	State SystemState `json:"state"`
 
@@ -34,4 +117,7 @@ type SystemStatus struct {
 
 	CreationTimestamp time.Time  `json:"creationTimestamp"`
 	DeletionTimestamp *time.Time `json:"deletionTimestamp,omitempty"`
+
+	// Usage is only set when getting a single system.
+	Usage *SystemUsage `json:"usage,omitempty"`
 }
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/time"
+)
+
+type (
+	WebhookProvider string
+	WebhookAction   string
+	WebhookEventID  string
+)
+
+const (
+	WebhookProviderGitHub WebhookProvider = "github"
+	WebhookProviderGitLab WebhookProvider = "gitlab"
+	WebhookProviderGitea  WebhookProvider = "gitea"
+)
+
+const (
+	WebhookActionBuild  WebhookAction = "build"
+	WebhookActionDeploy WebhookAction = "deploy"
+)
+
+// WebhookConfig configures how a system reacts to pushes to its definition
+// repository that are delivered by webhooks.
+type WebhookConfig struct {
+	// Secret is the system secret holding the key that webhook payloads are
+	// signed with. GitLab does not sign payloads, so for GitLab webhooks the
+	// secret must be the webhook's secret token.
+	Secret tree.PathSubcomponent `json:"secret"`
+
+	// Rules are checked in order, and the first rule matching the pushed
+	// branch or tag decides the action taken. Pushes that do not match any
+	// rule are recorded but do not trigger anything.
+	Rules []WebhookRule `json:"rules"`
+}
+
+// WebhookRule matches pushes to branches or tags whose names match a glob
+// pattern (e.g. "v*"). Exactly one of Branch or Tag must be set.
+type WebhookRule struct {
+	Branch *string `json:"branch,omitempty"`
+	Tag    *string `json:"tag,omitempty"`
+
+	Action WebhookAction `json:"action"`
+}
+
+type WebhookEvent struct {
+	ID WebhookEventID `json:"id"`
+
+	Provider      WebhookProvider `json:"provider"`
+	RepositoryURL string          `json:"repositoryUrl"`
+
+	Branch *string `json:"branch,omitempty"`
+	Tag    *string `json:"tag,omitempty"`
+	Commit string  `json:"commit"`
+
+	// Action is the action of the rule the push matched, if any.
+	Action *WebhookAction `json:"action,omitempty"`
+	Build  *BuildID       `json:"build,omitempty"`
+	Deploy *DeployID      `json:"deploy,omitempty"`
+
+	// Message describes why the event did not trigger a build or deploy.
+	Message string `json:"message,omitempty"`
+
+	ReceivedTimestamp time.Time `json:"receivedTimestamp"`
+}
@@ -29,6 +29,24 @@ func (in *Build) DeepCopyInto(out *Build) {
 			**out = **in
 		}
 	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(BuildSource)
+			**out = **in
+		}
+	}
+	if in.Commit != nil {
+		in, out := &in.Commit, &out.Commit
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -44,24 +62,8 @@ func (in *Build) DeepCopy() *Build {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
+func (in *BuildProvenance) DeepCopyInto(out *BuildProvenance) {
 	*out = *in
-	if in.StartTimestamp != nil {
-		in, out := &in.StartTimestamp, &out.StartTimestamp
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = (*in).DeepCopy()
-		}
-	}
-	if in.CompletionTimestamp != nil {
-		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = (*in).DeepCopy()
-		}
-	}
 	if in.Path != nil {
 		in, out := &in.Path, &out.Path
 		if *in == nil {
@@ -82,9 +84,9 @@ func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 	}
 	if in.Workloads != nil {
 		in, out := &in.Workloads, &out.Workloads
-		*out = make(map[tree.Path]WorkloadBuild, len(*in))
+		*out = make(map[tree.Path]WorkloadBuildProvenance, len(*in))
 		for key, val := range *in {
-			newVal := new(WorkloadBuild)
+			newVal := new(WorkloadBuildProvenance)
 			val.DeepCopyInto(newVal)
 			(*out)[key] = *newVal
 		}
@@ -92,51 +94,34 @@ func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStatus.
-func (in *BuildStatus) DeepCopy() *BuildStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(BuildStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerBuild) DeepCopyInto(out *ContainerBuild) {
-	*out = *in
-	in.Status.DeepCopyInto(&out.Status)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuild.
-func (in *ContainerBuild) DeepCopy() *ContainerBuild {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildProvenance.
+func (in *BuildProvenance) DeepCopy() *BuildProvenance {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerBuild)
+	out := new(BuildProvenance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerBuildFailureInfo) DeepCopyInto(out *ContainerBuildFailureInfo) {
+func (in *BuildSource) DeepCopyInto(out *BuildSource) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildFailureInfo.
-func (in *ContainerBuildFailureInfo) DeepCopy() *ContainerBuildFailureInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildSource.
+func (in *BuildSource) DeepCopy() *BuildSource {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerBuildFailureInfo)
+	out := new(BuildSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerBuildStatus) DeepCopyInto(out *ContainerBuildStatus) {
+func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 	*out = *in
 	if in.StartTimestamp != nil {
 		in, out := &in.StartTimestamp, &out.StartTimestamp
@@ -154,335 +139,1081 @@ func (in *ContainerBuildStatus) DeepCopyInto(out *ContainerBuildStatus) {
 			*out = (*in).DeepCopy()
 		}
 	}
-	if in.LastObservedPhase != nil {
-		in, out := &in.LastObservedPhase, &out.LastObservedPhase
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(ContainerBuildPhase)
+			*out = new(tree.Path)
 			**out = **in
 		}
 	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(string)
+			*out = new(Version)
 			**out = **in
 		}
 	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make(map[tree.Path]WorkloadBuild, len(*in))
+		for key, val := range *in {
+			newVal := new(WorkloadBuild)
+			val.DeepCopyInto(newVal)
+			(*out)[key] = *newVal
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildStatus.
-func (in *ContainerBuildStatus) DeepCopy() *ContainerBuildStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStatus.
+func (in *BuildStatus) DeepCopy() *BuildStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerBuildStatus)
+	out := new(BuildStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerLogOptions) DeepCopyInto(out *ContainerLogOptions) {
+func (in *ContainerBuild) DeepCopyInto(out *ContainerBuild) {
 	*out = *in
-	if in.Tail != nil {
-		in, out := &in.Tail, &out.Tail
+	if in.Reuse != nil {
+		in, out := &in.Reuse, &out.Reuse
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(int64)
+			*out = new(ContainerBuildReuse)
 			**out = **in
 		}
 	}
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerLogOptions.
-func (in *ContainerLogOptions) DeepCopy() *ContainerLogOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuild.
+func (in *ContainerBuild) DeepCopy() *ContainerBuild {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerLogOptions)
+	out := new(ContainerBuild)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Deploy) DeepCopyInto(out *Deploy) {
+func (in *ContainerBuildArtifacts) DeepCopyInto(out *ContainerBuildArtifacts) {
 	*out = *in
-	if in.Build != nil {
-		in, out := &in.Build, &out.Build
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(BuildID)
-			**out = **in
-		}
-	}
-	if in.Path != nil {
-		in, out := &in.Path, &out.Path
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(tree.Path)
-			**out = **in
-		}
-	}
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(Version)
-			**out = **in
-		}
-	}
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Deploy.
-func (in *Deploy) DeepCopy() *Deploy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildArtifacts.
+func (in *ContainerBuildArtifacts) DeepCopy() *ContainerBuildArtifacts {
 	if in == nil {
 		return nil
 	}
-	out := new(Deploy)
+	out := new(ContainerBuildArtifacts)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeployStatus) DeepCopyInto(out *DeployStatus) {
+func (in *ContainerBuildCacheInfo) DeepCopyInto(out *ContainerBuildCacheInfo) {
 	*out = *in
-	if in.Build != nil {
-		in, out := &in.Build, &out.Build
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(BuildID)
-			**out = **in
-		}
-	}
-	if in.Path != nil {
-		in, out := &in.Path, &out.Path
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(tree.Path)
-			**out = **in
-		}
-	}
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(Version)
-			**out = **in
-		}
-	}
-	if in.StartTimestamp != nil {
-		in, out := &in.StartTimestamp, &out.StartTimestamp
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = (*in).DeepCopy()
-		}
-	}
-	if in.CompletionTimestamp != nil {
-		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = (*in).DeepCopy()
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployStatus.
-func (in *DeployStatus) DeepCopy() *DeployStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildCacheInfo.
+func (in *ContainerBuildCacheInfo) DeepCopy() *ContainerBuildCacheInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(DeployStatus)
+	out := new(ContainerBuildCacheInfo)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Error) DeepCopyInto(out *Error) {
+func (in *ContainerBuildFailureInfo) DeepCopyInto(out *ContainerBuildFailureInfo) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Error.
-func (in *Error) DeepCopy() *Error {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildFailureInfo.
+func (in *ContainerBuildFailureInfo) DeepCopy() *ContainerBuildFailureInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(Error)
+	out := new(ContainerBuildFailureInfo)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Job) DeepCopyInto(out *Job) {
+func (in *ContainerBuildPackage) DeepCopyInto(out *ContainerBuildPackage) {
 	*out = *in
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Job.
-func (in *Job) DeepCopy() *Job {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildPackage.
+func (in *ContainerBuildPackage) DeepCopy() *ContainerBuildPackage {
 	if in == nil {
 		return nil
 	}
-	out := new(Job)
+	out := new(ContainerBuildPackage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JobStatus) DeepCopyInto(out *JobStatus) {
+func (in *ContainerBuildProvenance) DeepCopyInto(out *ContainerBuildProvenance) {
 	*out = *in
-	if in.StartTimestamp != nil {
-		in, out := &in.StartTimestamp, &out.StartTimestamp
+	if in.BaseImage != nil {
+		in, out := &in.BaseImage, &out.BaseImage
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = (*in).DeepCopy()
+			*out = new(ContainerBuildProvenanceImage)
+			**out = **in
 		}
 	}
-	if in.CompletionTimestamp != nil {
-		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]ContainerBuildProvenanceSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.BuildArgs != nil {
+		in, out := &in.BuildArgs, &out.BuildArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SBOM != nil {
+		in, out := &in.SBOM, &out.SBOM
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = (*in).DeepCopy()
+			*out = new(ContainerBuildSBOM)
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
-func (in *JobStatus) DeepCopy() *JobStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildProvenance.
+func (in *ContainerBuildProvenance) DeepCopy() *ContainerBuildProvenance {
 	if in == nil {
 		return nil
 	}
-	out := new(JobStatus)
+	out := new(ContainerBuildProvenance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePool) DeepCopyInto(out *NodePool) {
+func (in *ContainerBuildProvenanceDocument) DeepCopyInto(out *ContainerBuildProvenanceDocument) {
 	*out = *in
-	in.Status.DeepCopyInto(&out.Status)
+	in.ContainerBuildProvenance.DeepCopyInto(&out.ContainerBuildProvenance)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
-func (in *NodePool) DeepCopy() *NodePool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildProvenanceDocument.
+func (in *ContainerBuildProvenanceDocument) DeepCopy() *ContainerBuildProvenanceDocument {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePool)
+	out := new(ContainerBuildProvenanceDocument)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePoolFailureInfo) DeepCopyInto(out *NodePoolFailureInfo) {
+func (in *ContainerBuildProvenanceImage) DeepCopyInto(out *ContainerBuildProvenanceImage) {
 	*out = *in
-	in.Time.DeepCopyInto(&out.Time)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolFailureInfo.
-func (in *NodePoolFailureInfo) DeepCopy() *NodePoolFailureInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildProvenanceImage.
+func (in *ContainerBuildProvenanceImage) DeepCopy() *ContainerBuildProvenanceImage {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePoolFailureInfo)
+	out := new(ContainerBuildProvenanceImage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
+func (in *ContainerBuildProvenanceSource) DeepCopyInto(out *ContainerBuildProvenanceSource) {
 	*out = *in
-	if in.FailureInfo != nil {
-		in, out := &in.FailureInfo, &out.FailureInfo
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(NodePoolFailureInfo)
-			(*in).DeepCopyInto(*out)
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
-func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildProvenanceSource.
+func (in *ContainerBuildProvenanceSource) DeepCopy() *ContainerBuildProvenanceSource {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePoolStatus)
+	out := new(ContainerBuildProvenanceSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Secret) DeepCopyInto(out *Secret) {
+func (in *ContainerBuildReuse) DeepCopyInto(out *ContainerBuildReuse) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Secret.
-func (in *Secret) DeepCopy() *Secret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildReuse.
+func (in *ContainerBuildReuse) DeepCopy() *ContainerBuildReuse {
 	if in == nil {
 		return nil
 	}
-	out := new(Secret)
+	out := new(ContainerBuildReuse)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Service) DeepCopyInto(out *Service) {
+func (in *ContainerBuildSBOM) DeepCopyInto(out *ContainerBuildSBOM) {
 	*out = *in
-	in.Status.DeepCopyInto(&out.Status)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
-func (in *Service) DeepCopy() *Service {
-	if in == nil {
-		return nil
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make([]ContainerBuildPackage, len(*in))
+		copy(*out, *in)
 	}
-	out := new(Service)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceFailureInfo) DeepCopyInto(out *ServiceFailureInfo) {
-	*out = *in
-	in.Time.DeepCopyInto(&out.Time)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFailureInfo.
-func (in *ServiceFailureInfo) DeepCopy() *ServiceFailureInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildSBOM.
+func (in *ContainerBuildSBOM) DeepCopy() *ContainerBuildSBOM {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceFailureInfo)
+	out := new(ContainerBuildSBOM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerBuildStatus) DeepCopyInto(out *ContainerBuildStatus) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.CompletionTimestamp != nil {
+		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.LastObservedPhase != nil {
+		in, out := &in.LastObservedPhase, &out.LastObservedPhase
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerBuildPhase)
+			**out = **in
+		}
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerBuildCacheInfo)
+			**out = **in
+		}
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerBuildArtifacts)
+			**out = **in
+		}
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerBuildProvenance)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerBuildStatus.
+func (in *ContainerBuildStatus) DeepCopy() *ContainerBuildStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerBuildStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerResourceList) DeepCopyInto(out *ContainerResourceList) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResourceList.
+func (in *ContainerResourceList) DeepCopy() *ContainerResourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerResourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerLogOptions) DeepCopyInto(out *ContainerLogOptions) {
+	*out = *in
+	if in.Tail != nil {
+		in, out := &in.Tail, &out.Tail
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int64)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerLogOptions.
+func (in *ContainerLogOptions) DeepCopy() *ContainerLogOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerLogOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make(map[tree.Path]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make(map[tree.PathSubcomponent]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UnpricedInstanceTypes != nil {
+		in, out := &in.UnpricedInstanceTypes, &out.UnpricedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Deploy) DeepCopyInto(out *Deploy) {
+	*out = *in
+	if in.Build != nil {
+		in, out := &in.Build, &out.Build
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(BuildID)
+			**out = **in
+		}
+	}
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(tree.Path)
+			**out = **in
+		}
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(Version)
+			**out = **in
+		}
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(BuildSource)
+			**out = **in
+		}
+	}
+	if in.Approvals != nil {
+		in, out := &in.Approvals, &out.Approvals
+		*out = make([]DeployApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MonthlyCostDelta != nil {
+		in, out := &in.MonthlyCostDelta, &out.MonthlyCostDelta
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(float64)
+			**out = **in
+		}
+	}
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Deploy.
+func (in *Deploy) DeepCopy() *Deploy {
+	if in == nil {
+		return nil
+	}
+	out := new(Deploy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployApproval) DeepCopyInto(out *DeployApproval) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployApproval.
+func (in *DeployApproval) DeepCopy() *DeployApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployApprovalPolicy) DeepCopyInto(out *DeployApprovalPolicy) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployApprovalPolicy.
+func (in *DeployApprovalPolicy) DeepCopy() *DeployApprovalPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployApprovalPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployChangeWindow) DeepCopyInto(out *DeployChangeWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployChangeWindow.
+func (in *DeployChangeWindow) DeepCopy() *DeployChangeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployChangeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployFreeze) DeepCopyInto(out *DeployFreeze) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployFreeze.
+func (in *DeployFreeze) DeepCopy() *DeployFreeze {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployFreeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployPolicy) DeepCopyInto(out *DeployPolicy) {
+	*out = *in
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(DeployApprovalPolicy)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.ChangeWindows != nil {
+		in, out := &in.ChangeWindows, &out.ChangeWindows
+		*out = make([]DeployChangeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Freezes != nil {
+		in, out := &in.Freezes, &out.Freezes
+		*out = make([]DeployFreeze, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployPolicy.
+func (in *DeployPolicy) DeepCopy() *DeployPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployStatus) DeepCopyInto(out *DeployStatus) {
+	*out = *in
+	if in.Build != nil {
+		in, out := &in.Build, &out.Build
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(BuildID)
+			**out = **in
+		}
+	}
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(tree.Path)
+			**out = **in
+		}
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(Version)
+			**out = **in
+		}
+	}
+	if in.QueuePosition != nil {
+		in, out := &in.QueuePosition, &out.QueuePosition
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int32)
+			**out = **in
+		}
+	}
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.CompletionTimestamp != nil {
+		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployStatus.
+func (in *DeployStatus) DeepCopy() *DeployStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Error) DeepCopyInto(out *Error) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Error.
+func (in *Error) DeepCopy() *Error {
+	if in == nil {
+		return nil
+	}
+	out := new(Error)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicy) DeepCopyInto(out *ImagePolicy) {
+	*out = *in
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SignatureKeys != nil {
+		in, out := &in.SignatureKeys, &out.SignatureKeys
+		*out = make([]ImagePolicySignatureKey, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicy.
+func (in *ImagePolicy) DeepCopy() *ImagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicySignatureKey) DeepCopyInto(out *ImagePolicySignatureKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicySignatureKey.
+func (in *ImagePolicySignatureKey) DeepCopy() *ImagePolicySignatureKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicySignatureKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Job) DeepCopyInto(out *Job) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Job.
+func (in *Job) DeepCopy() *Job {
+	if in == nil {
+		return nil
+	}
+	out := new(Job)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobStatus) DeepCopyInto(out *JobStatus) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.CompletionTimestamp != nil {
+		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
+func (in *JobStatus) DeepCopy() *JobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.InstanceTypes != nil {
+		in, out := &in.InstanceTypes, &out.InstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Spot != nil {
+		in, out := &in.Spot, &out.Spot
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(NodePoolSpot)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(NodePoolAutoscaling)
+			**out = **in
+		}
+	}
+	if in.Override != nil {
+		in, out := &in.Override, &out.Override
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(NodePoolOverride)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolAutoscaling) DeepCopyInto(out *NodePoolAutoscaling) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolAutoscaling.
+func (in *NodePoolAutoscaling) DeepCopy() *NodePoolAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolEpochStatus) DeepCopyInto(out *NodePoolEpochStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolEpochStatus.
+func (in *NodePoolEpochStatus) DeepCopy() *NodePoolEpochStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolEpochStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolFailureInfo) DeepCopyInto(out *NodePoolFailureInfo) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolFailureInfo.
+func (in *NodePoolFailureInfo) DeepCopy() *NodePoolFailureInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolFailureInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolOverride) DeepCopyInto(out *NodePoolOverride) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolOverride.
+func (in *NodePoolOverride) DeepCopy() *NodePoolOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolSpot) DeepCopyInto(out *NodePoolSpot) {
+	*out = *in
+	if in.FallbackTimestamp != nil {
+		in, out := &in.FallbackTimestamp, &out.FallbackTimestamp
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpot.
+func (in *NodePoolSpot) DeepCopy() *NodePoolSpot {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolSpot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
+	*out = *in
+	if in.FailureInfo != nil {
+		in, out := &in.FailureInfo, &out.FailureInfo
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(NodePoolFailureInfo)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Epochs != nil {
+		in, out := &in.Epochs, &out.Epochs
+		*out = make([]NodePoolEpochStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
+func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Secret) DeepCopyInto(out *Secret) {
+	*out = *in
+	in.CreationTimestamp.DeepCopyInto(&out.CreationTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Secret.
+func (in *Secret) DeepCopy() *Secret {
+	if in == nil {
+		return nil
+	}
+	out := new(Secret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Service) DeepCopyInto(out *Service) {
+	*out = *in
+	if in.Fault != nil {
+		in, out := &in.Fault, &out.Fault
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ServiceFault)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
+func (in *Service) DeepCopy() *Service {
+	if in == nil {
+		return nil
+	}
+	out := new(Service)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceFailureInfo) DeepCopyInto(out *ServiceFailureInfo) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFailureInfo.
+func (in *ServiceFailureInfo) DeepCopy() *ServiceFailureInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceFailureInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceFault) DeepCopyInto(out *ServiceFault) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ServiceFaultDelay)
+			**out = **in
+		}
+	}
+	if in.Abort != nil {
+		in, out := &in.Abort, &out.Abort
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ServiceFaultAbort)
+			**out = **in
+		}
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(tree.Path)
+			**out = **in
+		}
+	}
+	in.Expires.DeepCopyInto(&out.Expires)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFault.
+func (in *ServiceFault) DeepCopy() *ServiceFault {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceFault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceFaultAbort) DeepCopyInto(out *ServiceFaultAbort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFaultAbort.
+func (in *ServiceFaultAbort) DeepCopy() *ServiceFaultAbort {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceFaultAbort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceFaultDelay) DeepCopyInto(out *ServiceFaultDelay) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFaultDelay.
+func (in *ServiceFaultDelay) DeepCopy() *ServiceFaultDelay {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceFaultDelay)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -536,6 +1267,42 @@ func (in *ServiceStatus) DeepCopy() *ServiceStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *System) DeepCopyInto(out *System) {
 	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SystemQuota)
+			**out = **in
+		}
+	}
+	if in.ContainerResources != nil {
+		in, out := &in.ContainerResources, &out.ContainerResources
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SystemContainerResources)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SystemTracing)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.ImagePolicy != nil {
+		in, out := &in.ImagePolicy, &out.ImagePolicy
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ImagePolicy)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -550,6 +1317,65 @@ func (in *System) DeepCopy() *System {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemContainerResources) DeepCopyInto(out *SystemContainerResources) {
+	*out = *in
+	if in.DefaultRequests != nil {
+		in, out := &in.DefaultRequests, &out.DefaultRequests
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerResourceList)
+			**out = **in
+		}
+	}
+	if in.DefaultLimits != nil {
+		in, out := &in.DefaultLimits, &out.DefaultLimits
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerResourceList)
+			**out = **in
+		}
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerResourceList)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemContainerResources.
+func (in *SystemContainerResources) DeepCopy() *SystemContainerResources {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemContainerResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemQuota) DeepCopyInto(out *SystemQuota) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemQuota.
+func (in *SystemQuota) DeepCopy() *SystemQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemStatus) DeepCopyInto(out *SystemStatus) {
 	*out = *in
@@ -571,6 +1397,15 @@ func (in *SystemStatus) DeepCopyInto(out *SystemStatus) {
 			*out = (*in).DeepCopy()
 		}
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SystemUsage)
+			**out = **in
+		}
+	}
 	return
 }
 
@@ -584,6 +1419,47 @@ func (in *SystemStatus) DeepCopy() *SystemStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemTracing) DeepCopyInto(out *SystemTracing) {
+	*out = *in
+	if in.SamplingRate != nil {
+		in, out := &in.SamplingRate, &out.SamplingRate
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(float64)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemTracing.
+func (in *SystemTracing) DeepCopy() *SystemTracing {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemTracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemUsage) DeepCopyInto(out *SystemUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemUsage.
+func (in *SystemUsage) DeepCopy() *SystemUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Teardown) DeepCopyInto(out *Teardown) {
 	*out = *in
@@ -633,6 +1509,141 @@ func (in *TeardownStatus) DeepCopy() *TeardownStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerminalSize) DeepCopyInto(out *TerminalSize) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerminalSize.
+func (in *TerminalSize) DeepCopy() *TerminalSize {
+	if in == nil {
+		return nil
+	}
+	out := new(TerminalSize)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]WebhookRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfig.
+func (in *WebhookConfig) DeepCopy() *WebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookEvent) DeepCopyInto(out *WebhookEvent) {
+	*out = *in
+	if in.Branch != nil {
+		in, out := &in.Branch, &out.Branch
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	if in.Tag != nil {
+		in, out := &in.Tag, &out.Tag
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(WebhookAction)
+			**out = **in
+		}
+	}
+	if in.Build != nil {
+		in, out := &in.Build, &out.Build
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(BuildID)
+			**out = **in
+		}
+	}
+	if in.Deploy != nil {
+		in, out := &in.Deploy, &out.Deploy
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(DeployID)
+			**out = **in
+		}
+	}
+	in.ReceivedTimestamp.DeepCopyInto(&out.ReceivedTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookEvent.
+func (in *WebhookEvent) DeepCopy() *WebhookEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookRule) DeepCopyInto(out *WebhookRule) {
+	*out = *in
+	if in.Branch != nil {
+		in, out := &in.Branch, &out.Branch
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	if in.Tag != nil {
+		in, out := &in.Tag, &out.Tag
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookRule.
+func (in *WebhookRule) DeepCopy() *WebhookRule {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadBuild) DeepCopyInto(out *WorkloadBuild) {
 	*out = *in
@@ -658,3 +1669,29 @@ func (in *WorkloadBuild) DeepCopy() *WorkloadBuild {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadBuildProvenance) DeepCopyInto(out *WorkloadBuildProvenance) {
+	*out = *in
+	in.ContainerBuildProvenanceDocument.DeepCopyInto(&out.ContainerBuildProvenanceDocument)
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make(map[string]ContainerBuildProvenanceDocument, len(*in))
+		for key, val := range *in {
+			newVal := new(ContainerBuildProvenanceDocument)
+			val.DeepCopyInto(newVal)
+			(*out)[key] = *newVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadBuildProvenance.
+func (in *WorkloadBuildProvenance) DeepCopy() *WorkloadBuildProvenance {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadBuildProvenance)
+	in.DeepCopyInto(out)
+	return out
+}
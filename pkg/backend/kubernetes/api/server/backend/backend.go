@@ -4,17 +4,21 @@ import (
 	serverv1 "github.com/mlab-lattice/lattice/pkg/api/server/backend/v1"
 	backendv1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/api/server/backend/v1"
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 
 	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 func NewKubernetesBackend(
 	namespacePrefix string,
+	kubeConfig *rest.Config,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
+	secretProviders *secretprovider.Providers,
 ) *KubernetesBackend {
 	return &KubernetesBackend{
-		v1: backendv1.NewBackend(namespacePrefix, kubeClient, latticeClient),
+		v1: backendv1.NewBackend(namespacePrefix, kubeConfig, kubeClient, latticeClient, secretProviders),
 	}
 }
 
@@ -4,8 +4,10 @@ import (
 	serverv1 "github.com/mlab-lattice/lattice/pkg/api/server/backend/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/api/server/backend/v1/system"
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 
 	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 type Backend struct {
@@ -14,11 +16,13 @@ type Backend struct {
 
 func NewBackend(
 	namespacePrefix string,
+	kubeConfig *rest.Config,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
+	secretProviders *secretprovider.Providers,
 ) *Backend {
 	return &Backend{
-		systems: system.NewBackend(namespacePrefix, kubeClient, latticeClient),
+		systems: system.NewBackend(namespacePrefix, kubeConfig, kubeClient, latticeClient, secretProviders),
 	}
 }
 
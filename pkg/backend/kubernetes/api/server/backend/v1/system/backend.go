@@ -8,25 +8,31 @@ import (
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 	"github.com/mlab-lattice/lattice/pkg/util/time"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 func NewBackend(
 	namespacePrefix string,
+	kubeConfig *rest.Config,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
+	secretProviders *secretprovider.Providers,
 ) *Backend {
-	return &Backend{namespacePrefix, kubeClient, latticeClient}
+	return &Backend{namespacePrefix, kubeConfig, kubeClient, latticeClient, secretProviders}
 }
 
 type Backend struct {
 	namespacePrefix string
+	kubeConfig      *rest.Config
 	kubeClient      kubeclientset.Interface
 	latticeClient   latticeclientset.Interface
+	secretProviders *secretprovider.Providers
 }
 
 func (b *Backend) Create(id v1.SystemID, definitionURL string) (*v1.System, error) {
@@ -81,7 +87,23 @@ func (b *Backend) Get(id v1.SystemID) (*v1.System, error) {
 		return nil, err
 	}
 
-	return b.transformSystem(system)
+	externalSystem, err := b.transformSystem(system)
+	if err != nil {
+		return nil, err
+	}
+
+	// the system's resources only exist once it has been created
+	if externalSystem.Status.State == v1.SystemStatePending || externalSystem.Status.State == v1.SystemStateFailed {
+		return externalSystem, nil
+	}
+
+	usage, err := b.systemUsage(system)
+	if err != nil {
+		return nil, err
+	}
+
+	externalSystem.Status.Usage = usage
+	return externalSystem, nil
 }
 
 func (b *Backend) Delete(id v1.SystemID) error {
@@ -124,9 +146,38 @@ func (b *Backend) transformSystem(system *latticev1.System) (*v1.System, error)
 		deletionTimestamp = time.New((*system.DeletionTimestamp).Time)
 	}
 
+	quota, err := system.QuotaAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	containerResources, err := system.ContainerResourcesAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	var tracing *v1.SystemTracing
+	samplingRate, err := system.TracingSamplingRateAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	if samplingRate != nil {
+		tracing = &v1.SystemTracing{SamplingRate: samplingRate}
+	}
+
+	imagePolicy, err := system.ImagePolicyAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
 	externalSystem := &v1.System{
-		ID:            v1.SystemID(system.Name),
-		DefinitionURL: system.Spec.DefinitionURL,
+		ID:                 v1.SystemID(system.Name),
+		DefinitionURL:      system.Spec.DefinitionURL,
+		Quota:              quota,
+		ContainerResources: containerResources,
+		Tracing:            tracing,
+		ImagePolicy:        imagePolicy,
 		Status: v1.SystemStatus{
 			State: state,
 
@@ -173,7 +224,13 @@ func getSystemState(state latticev1.SystemState, updateProcessed bool) (v1.Syste
 }
 
 func (b *Backend) ensureSystemCreated(id v1.SystemID) (*v1.System, error) {
-	system, err := b.Get(id)
+	// don't use Get, which also computes the system's usage
+	latticeSystem, err := b.getSystem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	system, err := b.transformSystem(latticeSystem)
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +249,20 @@ func (b *Backend) ensureSystemCreated(id v1.SystemID) (*v1.System, error) {
 	}
 }
 
+// getSystem returns the system's custom resource, which holds the system's
+// configuration annotations.
+func (b *Backend) getSystem(id v1.SystemID) (*latticev1.System, error) {
+	system, err := b.latticeClient.LatticeV1().Systems(b.internalNamespace()).Get(string(id), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, v1.NewInvalidSystemIDError()
+		}
+		return nil, err
+	}
+
+	return system, nil
+}
+
 func (b *Backend) systemNamespace(systemID v1.SystemID) string {
 	return kubeutil.SystemNamespace(b.namespacePrefix, systemID)
 }
@@ -248,3 +319,10 @@ func (b *Backend) Teardowns(system v1.SystemID) serverv1.SystemTeardownBackend {
 		system:  system,
 	}
 }
+
+func (b *Backend) Webhooks(system v1.SystemID) serverv1.SystemWebhookBackend {
+	return &webhookBackend{
+		backend: b,
+		system:  system,
+	}
+}
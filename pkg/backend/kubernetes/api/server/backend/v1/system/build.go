@@ -5,8 +5,10 @@ import (
 	"io"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/buildsource"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/sbom"
 	time "github.com/mlab-lattice/lattice/pkg/util/time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -23,20 +25,65 @@ type buildBackend struct {
 }
 
 func (b *buildBackend) CreateFromVersion(version v1.Version) (*v1.Build, error) {
-	return b.createBuild(&version, nil)
+	return b.createBuild(&latticev1.BuildSpec{Version: &version})
 }
 
 func (b *buildBackend) CreateFromPath(path tree.Path) (*v1.Build, error) {
-	return b.createBuild(nil, &path)
+	return b.createBuild(&latticev1.BuildSpec{Path: &path})
 }
 
-func (b *buildBackend) createBuild(version *v1.Version, path *tree.Path) (*v1.Build, error) {
+func (b *buildBackend) CreateFromCommit(commit string) (*v1.Build, error) {
+	return b.createBuild(&latticev1.BuildSpec{Commit: &commit})
+}
+
+func (b *buildBackend) CreateFromSource(id v1.BuildSourceID) (*v1.Build, error) {
 	// ensure the system exists
 	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
 		return nil, err
 	}
 
-	build, err := newBuild(version, path)
+	store, err := buildsource.NewStore(b.backend.latticeClient, b.backend.namespacePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if source == nil {
+		return nil, v1.NewInvalidBuildSourceIDError()
+	}
+
+	return b.createBuild(&latticev1.BuildSpec{Source: source})
+}
+
+func (b *buildBackend) UploadSource(archive io.Reader) (*v1.BuildSource, error) {
+	// ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
+	}
+
+	store, err := buildsource.NewStore(b.backend.latticeClient, b.backend.namespacePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Push(v1.BuildSourceID(uuid.NewV4().String()), archive)
+}
+
+func (b *buildBackend) createBuild(spec *latticev1.BuildSpec) (*v1.Build, error) {
+	// ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
+	}
+
+	if err := b.backend.checkBuildQuota(b.system); err != nil {
+		return nil, err
+	}
+
+	build, err := newBuild(spec)
 	if err != nil {
 		return nil, err
 	}
@@ -55,16 +102,13 @@ func (b *buildBackend) createBuild(version *v1.Version, path *tree.Path) (*v1.Bu
 	return &externalBuild, nil
 }
 
-func newBuild(version *v1.Version, path *tree.Path) (*latticev1.Build, error) {
+func newBuild(spec *latticev1.BuildSpec) (*latticev1.Build, error) {
 	build := &latticev1.Build{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   uuid.NewV4().String(),
 			Labels: map[string]string{},
 		},
-		Spec: latticev1.BuildSpec{
-			Version: version,
-			Path:    path,
-		},
+		Spec: *spec,
 	}
 
 	return build, nil
@@ -120,6 +164,115 @@ func (b *buildBackend) Get(id v1.BuildID) (*v1.Build, error) {
 	return &externalBuild, nil
 }
 
+func (b *buildBackend) Provenance(id v1.BuildID) (*v1.BuildProvenance, error) {
+	// Ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
+	}
+
+	namespace := b.backend.systemNamespace(b.system)
+	build, err := b.backend.latticeClient.LatticeV1().Builds(namespace).Get(string(id), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, v1.NewInvalidBuildIDError()
+		}
+
+		return nil, err
+	}
+
+	provenance := &v1.BuildProvenance{
+		ID: id,
+
+		Path:    build.Spec.Path,
+		Version: build.Spec.Version,
+
+		Workloads: make(map[tree.Path]v1.WorkloadBuildProvenance),
+	}
+
+	for path, workload := range build.Status.Workloads {
+		// only workloads whose container builds have all succeeded have provenance
+		mainContainer, ok, err := b.containerBuildProvenance(namespace, workload.MainContainer, build.Status.ContainerBuildStatuses)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		workloadProvenance := v1.WorkloadBuildProvenance{
+			ContainerBuildProvenanceDocument: mainContainer,
+			Sidecars:                         make(map[string]v1.ContainerBuildProvenanceDocument),
+		}
+
+		complete := true
+		for sidecar, containerBuildID := range workload.Sidecars {
+			sidecarProvenance, ok, err := b.containerBuildProvenance(namespace, containerBuildID, build.Status.ContainerBuildStatuses)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				complete = false
+				break
+			}
+
+			workloadProvenance.Sidecars[sidecar] = sidecarProvenance
+		}
+
+		if complete {
+			provenance.Workloads[path] = workloadProvenance
+		}
+	}
+
+	return provenance, nil
+}
+
+func (b *buildBackend) containerBuildProvenance(
+	namespace string,
+	id v1.ContainerBuildID,
+	containerBuildStatuses map[v1.ContainerBuildID]latticev1.ContainerBuildStatus,
+) (v1.ContainerBuildProvenanceDocument, bool, error) {
+	status, ok := containerBuildStatuses[id]
+	if !ok || status.State != latticev1.ContainerBuildStateSucceeded || status.Artifacts == nil {
+		return v1.ContainerBuildProvenanceDocument{}, false, nil
+	}
+
+	document := v1.ContainerBuildProvenanceDocument{
+		ID:             id,
+		DockerImageFQN: status.Artifacts.DockerImageFQN,
+	}
+
+	// container builds that succeeded before provenance was recorded have none
+	if status.Provenance == nil {
+		return document, true, nil
+	}
+
+	document.ContainerBuildProvenance = *status.Provenance.DeepCopy()
+	if status.Provenance.SBOMDigest == "" {
+		return document, true, nil
+	}
+
+	name := latticev1.ContainerBuildSBOMConfigMapName(id)
+	configMap, err := b.backend.kubeClient.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		// the SBOM is removed along with the container build
+		if errors.IsNotFound(err) {
+			return document, true, nil
+		}
+
+		return v1.ContainerBuildProvenanceDocument{}, false, err
+	}
+
+	data := configMap.Data[latticev1.ContainerBuildSBOMConfigMapKey]
+	document.SBOM, err = sbom.Decode([]byte(data), status.Provenance.SBOMDigest)
+	if err != nil {
+		return v1.ContainerBuildProvenanceDocument{}, false, fmt.Errorf("invalid SBOM for container build %v: %v", id, err)
+	}
+
+	return document, true, nil
+}
+
 func (b *buildBackend) Logs(
 	id v1.BuildID,
 	path tree.Path,
@@ -208,6 +361,8 @@ func (b *buildBackend) transformBuild(build *latticev1.Build) (v1.Build, error)
 
 		Path:    build.Spec.Path,
 		Version: build.Spec.Version,
+		Source:  build.Spec.Source,
+		Commit:  build.Spec.Commit,
 
 		Status: v1.BuildStatus{
 			State:   state,
@@ -283,6 +438,8 @@ func transformWorkloadBuild(
 		return v1.WorkloadBuild{}, err
 	}
 
+	externalContainerBuild.Reuse = workload.MainContainerReuse
+
 	externalBuild := v1.WorkloadBuild{
 		ContainerBuild: externalContainerBuild,
 		Sidecars:       make(map[string]v1.ContainerBuild),
@@ -305,6 +462,10 @@ func transformWorkloadBuild(
 			return v1.WorkloadBuild{}, err
 		}
 
+		if reuse, ok := workload.SidecarReuse[sidecar]; ok {
+			externalContainerBuild.Reuse = &reuse
+		}
+
 		externalBuild.Sidecars[sidecar] = externalContainerBuild
 	}
 
@@ -338,6 +499,19 @@ func transformContainerBuild(id v1.ContainerBuildID, status latticev1.ContainerB
 		completionTimestamp = time.New(status.CompletionTimestamp.Time)
 	}
 
+	var artifacts *v1.ContainerBuildArtifacts
+	if status.Artifacts != nil {
+		artifacts = &v1.ContainerBuildArtifacts{
+			DockerImageFQN:    status.Artifacts.DockerImageFQN,
+			DockerImageDigest: status.Artifacts.DockerImageDigest,
+		}
+	}
+
+	var provenance *v1.ContainerBuildProvenance
+	if status.Provenance != nil {
+		provenance = status.Provenance.DeepCopy()
+	}
+
 	externalBuild := v1.ContainerBuild{
 		ID: id,
 
@@ -349,6 +523,11 @@ func transformContainerBuild(id v1.ContainerBuildID, status latticev1.ContainerB
 
 			LastObservedPhase: phase,
 			FailureMessage:    failureMessage,
+
+			Cache: status.Cache,
+
+			Artifacts:  artifacts,
+			Provenance: provenance,
 		},
 	}
 
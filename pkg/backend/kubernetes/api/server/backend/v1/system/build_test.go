@@ -0,0 +1,87 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	fakelattice "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned/fake"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/sbom"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildProvenanceSBOM(t *testing.T) {
+	namespace := kubeutil.SystemNamespace(testNamespacePrefix, testSystemID)
+	packages := &v1.ContainerBuildSBOM{
+		Packages: []v1.ContainerBuildPackage{{Type: sbom.PackageTypeDeb, Name: "bash", Version: "4.4-5"}},
+	}
+	data, digest, err := sbom.Encode(packages)
+	require.NoError(t, err)
+
+	containerBuildStatus := func() latticev1.ContainerBuildStatus {
+		return latticev1.ContainerBuildStatus{
+			State:      latticev1.ContainerBuildStateSucceeded,
+			Artifacts:  &latticev1.ContainerBuildArtifacts{DockerImageFQN: "registry/image:tag"},
+			Provenance: &v1.ContainerBuildProvenance{SBOMDigest: digest},
+		}
+	}
+
+	// the SBOM of the api's container build was stored, the SBOM of the
+	// worker's has been removed
+	api := tree.RootPath().Child("api")
+	worker := tree.RootPath().Child("worker")
+	build := &latticev1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "build",
+			Namespace: namespace,
+		},
+		Status: latticev1.BuildStatus{
+			State: latticev1.BuildStateSucceeded,
+			Workloads: map[tree.Path]latticev1.BuildStatusWorkload{
+				api:    {MainContainer: "api"},
+				worker: {MainContainer: "worker"},
+			},
+			ContainerBuildStatuses: map[v1.ContainerBuildID]latticev1.ContainerBuildStatus{
+				"api":    containerBuildStatus(),
+				"worker": containerBuildStatus(),
+			},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      latticev1.ContainerBuildSBOMConfigMapName("api"),
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			latticev1.ContainerBuildSBOMConfigMapKey: string(data),
+		},
+	}
+
+	backend := NewBackend(
+		testNamespacePrefix,
+		nil,
+		fake.NewSimpleClientset(configMap),
+		fakelattice.NewSimpleClientset(testSystem(), build),
+		nil,
+	)
+
+	provenance, err := backend.Builds(testSystemID).Provenance("build")
+	require.NoError(t, err)
+	require.Equal(t, packages, provenance.Workloads[api].SBOM)
+	require.Equal(t, digest, provenance.Workloads[api].SBOMDigest)
+	require.Nil(t, provenance.Workloads[worker].SBOM)
+
+	// the build itself only includes the SBOM's digest
+	externalBuild, err := backend.Builds(testSystemID).Get("build")
+	require.NoError(t, err)
+	require.Nil(t, externalBuild.Status.Workloads[api].Status.Provenance.SBOM)
+	require.Equal(t, digest, externalBuild.Status.Workloads[api].Status.Provenance.SBOMDigest)
+}
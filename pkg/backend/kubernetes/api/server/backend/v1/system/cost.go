@@ -0,0 +1,137 @@
+package system
+
+import (
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	kubecost "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cost"
+	"github.com/mlab-lattice/lattice/pkg/cost"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (b *Backend) Cost(id v1.SystemID, path tree.Path, since time.Time) (*v1.CostEstimate, error) {
+	// ensure the system exists
+	system, err := b.ensureSystemCreated(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the system didn't cost anything before it was created
+	if since.Before(system.Status.CreationTimestamp.Time) {
+		since = system.Status.CreationTimestamp.Time
+	}
+
+	model, err := kubecost.Model(b.latticeClient, b.namespacePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	nodePools, err := b.costNodePools(id, since, now)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := b.costServices(id, since, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.Estimate(nodePools, services).CostEstimate(path, since), nil
+}
+
+// costNodePools returns the instances of each instance type in the system's
+// node pools and the hours they ran for since since, as recorded by the node
+// pool controller.
+func (b *Backend) costNodePools(id v1.SystemID, since, now time.Time) ([]cost.NodePool, error) {
+	namespace := b.systemNamespace(id)
+	nodePools, err := b.latticeClient.LatticeV1().NodePools(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodePoolBackend := &nodePoolBackend{backend: b, system: id}
+
+	var costNodePools []cost.NodePool
+	for i := range nodePools.Items {
+		nodePool := &nodePools.Items[i]
+		path, err := nodePoolBackend.getNodePoolPath(nodePool)
+		if err != nil {
+			return nil, err
+		}
+
+		// the node pool's service was deleted, so it is being torn down
+		if path == "" {
+			continue
+		}
+
+		instances := make(map[string]int32)
+		for _, epoch := range nodePool.Status.Epochs.Epochs() {
+			status := nodePool.Status.Epochs[epoch].Status
+			instances[status.InstanceType] += status.NumInstances
+		}
+
+		costNodePools = append(costNodePools, cost.NodePool{
+			ID:            nodePool.Name,
+			Path:          path,
+			Instances:     instances,
+			InstanceHours: nodePool.Status.InstanceHours.Since(since, now),
+		})
+	}
+
+	return costNodePools, nil
+}
+
+// costServices returns the system's services, the node pools in the system
+// that the service controller has scheduled them on, and the hours they ran
+// for on each of the node pools since since.
+func (b *Backend) costServices(id v1.SystemID, since, now time.Time) ([]cost.Service, error) {
+	namespace := b.systemNamespace(id)
+	services, err := b.latticeClient.LatticeV1().Services(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var costServices []cost.Service
+	for i := range services.Items {
+		service := &services.Items[i]
+		path, err := service.PathLabel()
+		if err != nil {
+			return nil, err
+		}
+
+		annotation, err := service.NodePoolAnnotation()
+		if err != nil {
+			return nil, err
+		}
+
+		// shared node pools live in the system's namespace, so node pools
+		// in other namespaces aren't part of the system's cost
+		var nodePools []string
+		namespaceNodePools, _ := annotation.NodePools(namespace)
+		for nodePool := range namespaceNodePools {
+			nodePools = append(nodePools, nodePool)
+		}
+
+		// the node pools' cost is shared by the services' running instances,
+		// which can differ from their definitions' during a rollout or scale
+		instances := service.Status.UpdatedInstances + service.Status.StaleInstances
+
+		costService, err := cost.NewService(
+			path,
+			&service.Spec.Definition,
+			instances,
+			nodePools,
+			service.Status.InstanceHours.Since(since, now),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		costServices = append(costServices, costService)
+	}
+
+	return costServices, nil
+}
@@ -1,11 +1,14 @@
 package system
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	kubecost "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cost"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/deploypolicy"
 	"github.com/mlab-lattice/lattice/pkg/util/time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -19,44 +22,55 @@ type deployBackend struct {
 	system  v1.SystemID
 }
 
-func (b *deployBackend) CreateFromBuild(id v1.BuildID) (*v1.Deploy, error) {
+func (b *deployBackend) CreateFromBuild(id v1.BuildID, creator string) (*v1.Deploy, error) {
 	// this ensures the system and build exist
-	_, err := b.backend.Builds(b.system).Get(id)
+	build, err := b.backend.Builds(b.system).Get(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return b.createDeploy(&id, nil, nil)
+	deploy := newDeploy(&id, nil, nil, creator)
+
+	// deploys of builds of local snapshots are labelled with the snapshot
+	// so that they can be told apart from reproducible deploys
+	if build.Source != nil {
+		data, err := json.Marshal(build.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		deploy.Annotations[latticev1.DeployBuildSourceAnnotationKey] = string(data)
+	}
+
+	return b.createDeploy(deploy)
 }
 
-func (b *deployBackend) CreateFromPath(path tree.Path) (*v1.Deploy, error) {
+func (b *deployBackend) CreateFromPath(path tree.Path, creator string) (*v1.Deploy, error) {
 	_, err := b.backend.ensureSystemCreated(b.system)
 	if err != nil {
 		return nil, err
 	}
 
-	return b.createDeploy(nil, &path, nil)
+	return b.createDeploy(newDeploy(nil, &path, nil, creator))
 }
 
-func (b *deployBackend) CreateFromVersion(version v1.Version) (*v1.Deploy, error) {
+func (b *deployBackend) CreateFromVersion(version v1.Version, creator string) (*v1.Deploy, error) {
 	_, err := b.backend.ensureSystemCreated(b.system)
 	if err != nil {
 		return nil, err
 	}
 
-	return b.createDeploy(nil, nil, &version)
+	return b.createDeploy(newDeploy(nil, nil, &version, creator))
 }
 
-func (b *deployBackend) createDeploy(build *v1.BuildID, path *tree.Path, version *v1.Version) (*v1.Deploy, error) {
-	deploy := newDeploy(build, path, version)
-
+func (b *deployBackend) createDeploy(deploy *latticev1.Deploy) (*v1.Deploy, error) {
 	namespace := b.backend.systemNamespace(b.system)
 	result, err := b.backend.latticeClient.LatticeV1().Deploys(namespace).Create(deploy)
 	if err != nil {
 		return nil, err
 	}
 
-	externalDeploy, err := transformDeploy(result)
+	externalDeploy, err := b.externalDeploy(result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -64,10 +78,11 @@ func (b *deployBackend) createDeploy(build *v1.BuildID, path *tree.Path, version
 	return &externalDeploy, nil
 }
 
-func newDeploy(build *v1.BuildID, path *tree.Path, version *v1.Version) *latticev1.Deploy {
-	return &latticev1.Deploy{
+func newDeploy(build *v1.BuildID, path *tree.Path, version *v1.Version, creator string) *latticev1.Deploy {
+	deploy := &latticev1.Deploy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: uuid.NewV4().String(),
+			Name:        uuid.NewV4().String(),
+			Annotations: make(map[string]string),
 		},
 		Spec: latticev1.DeploySpec{
 			Build:   build,
@@ -75,6 +90,13 @@ func newDeploy(build *v1.BuildID, path *tree.Path, version *v1.Version) *lattice
 			Version: version,
 		},
 	}
+
+	// the creator is recorded so that they cannot approve the deploy
+	if creator != "" {
+		deploy.Annotations[latticev1.DeployCreatorAnnotationKey] = creator
+	}
+
+	return deploy
 }
 
 func (b *deployBackend) List() ([]v1.Deploy, error) {
@@ -88,11 +110,13 @@ func (b *deployBackend) List() ([]v1.Deploy, error) {
 		return nil, err
 	}
 
+	queue := deployQueue(deploys)
+
 	// need to actually allocate the slice here so we return a slice instead of nil
 	// if deploys.Items is empty
 	externalDeploys := make([]v1.Deploy, 0)
 	for _, deploy := range deploys.Items {
-		externalDeploy, err := transformDeploy(&deploy)
+		externalDeploy, err := b.externalDeploy(&deploy, queue)
 		if err != nil {
 			return nil, err
 		}
@@ -118,7 +142,109 @@ func (b *deployBackend) Get(id v1.DeployID) (*v1.Deploy, error) {
 		return nil, err
 	}
 
-	externalDeploy, err := transformDeploy(deploy)
+	// the deploy's position in the queue depends on the other deploys
+	var queue []*latticev1.Deploy
+	if deploy.Status.State == latticev1.DeployStateQueued {
+		deploys, err := b.backend.latticeClient.LatticeV1().Deploys(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		queue = deployQueue(deploys)
+	}
+
+	externalDeploy, err := b.externalDeploy(deploy, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalDeploy, nil
+}
+
+func (b *deployBackend) Approve(id v1.DeployID, approver string, roles []string, comment string) (*v1.Deploy, error) {
+	return b.decide(id, v1.DeployApprovalDecisionApproved, approver, roles, comment)
+}
+
+func (b *deployBackend) Reject(id v1.DeployID, approver string, roles []string, comment string) (*v1.Deploy, error) {
+	return b.decide(id, v1.DeployApprovalDecisionRejected, approver, roles, comment)
+}
+
+// decide records the approver's decision on the deploy. The controller
+// decides whether the deploy has been approved or rejected.
+func (b *deployBackend) decide(
+	id v1.DeployID,
+	decision v1.DeployApprovalDecision,
+	approver string,
+	roles []string,
+	comment string,
+) (*v1.Deploy, error) {
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
+	}
+
+	policy, err := b.Policy()
+	if err != nil {
+		return nil, err
+	}
+
+	// the system's policy may have been removed since the deploy was created
+	if policy == nil {
+		policy = &v1.DeployPolicy{}
+	}
+
+	namespace := b.backend.systemNamespace(b.system)
+	deploy, err := b.backend.latticeClient.LatticeV1().Deploys(namespace).Get(string(id), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, v1.NewInvalidDeployIDError()
+		}
+
+		return nil, err
+	}
+
+	if deploy.Status.State != latticev1.DeployStateAwaitingApproval {
+		return nil, v1.NewDeployNotAwaitingApprovalError()
+	}
+
+	approvals, err := deploy.ApprovalsAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	err = deploypolicy.CanDecide(policy, deploy.CreatorAnnotation(), approvals, approver, roles)
+	if err != nil {
+		return nil, v1.NewDeployApprovalForbiddenError()
+	}
+
+	approvals = append(approvals, v1.DeployApproval{
+		User:      approver,
+		Decision:  decision,
+		Comment:   comment,
+		Timestamp: *time.New(metav1.Now().Time),
+	})
+
+	data, err := json.Marshal(approvals)
+	if err != nil {
+		return nil, err
+	}
+
+	deploy = deploy.DeepCopy()
+	if deploy.Annotations == nil {
+		deploy.Annotations = make(map[string]string)
+	}
+	deploy.Annotations[latticev1.DeployApprovalsAnnotationKey] = string(data)
+
+	// updates are rejected if another approver decided on the deploy concurrently
+	result, err := b.backend.latticeClient.LatticeV1().Deploys(namespace).Update(deploy)
+	if err != nil {
+		if errors.IsConflict(err) {
+			return nil, v1.NewConflictError()
+		}
+
+		return nil, err
+	}
+
+	externalDeploy, err := b.externalDeploy(result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -126,12 +252,126 @@ func (b *deployBackend) Get(id v1.DeployID) (*v1.Deploy, error) {
 	return &externalDeploy, nil
 }
 
-func transformDeploy(deploy *latticev1.Deploy) (v1.Deploy, error) {
+func (b *deployBackend) Policy() (*v1.DeployPolicy, error) {
+	system, err := b.backend.getSystem(b.system)
+	if err != nil {
+		return nil, err
+	}
+
+	return system.DeployPolicyAnnotation()
+}
+
+func (b *deployBackend) SetPolicy(policy *v1.DeployPolicy) error {
+	system, err := b.backend.getSystem(b.system)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	system = system.DeepCopy()
+	if system.Annotations == nil {
+		system.Annotations = make(map[string]string)
+	}
+	system.Annotations[latticev1.SystemDeployPolicyAnnotationKey] = string(data)
+
+	_, err = b.backend.latticeClient.LatticeV1().Systems(b.backend.internalNamespace()).Update(system)
+	return err
+}
+
+func deployQueue(deploys *latticev1.DeployList) []*latticev1.Deploy {
+	var queue []*latticev1.Deploy
+	for i := range deploys.Items {
+		if deploys.Items[i].Status.State == latticev1.DeployStateQueued {
+			queue = append(queue, &deploys.Items[i])
+		}
+	}
+
+	return queue
+}
+
+// externalDeploy transforms the deploy into its external representation. If the
+// controller hasn't recorded the deploy's monthly cost delta yet, which it does
+// when the deploy's definition replaces the system's, the delta is estimated
+// from the definition resolved by the deploy's build so that approvers can see
+// it before approving the deploy.
+func (b *deployBackend) externalDeploy(deploy *latticev1.Deploy, queue []*latticev1.Deploy) (v1.Deploy, error) {
+	externalDeploy, err := transformDeploy(deploy, queue)
+	if err != nil {
+		return v1.Deploy{}, err
+	}
+
+	if externalDeploy.MonthlyCostDelta != nil {
+		return externalDeploy, nil
+	}
+
+	switch deploy.Status.State {
+	case latticev1.DeployStatePending, latticev1.DeployStateAwaitingApproval,
+		latticev1.DeployStateQueued, latticev1.DeployStateAccepted:
+	default:
+		return externalDeploy, nil
+	}
+
+	buildID := deploy.Spec.Build
+	if buildID == nil {
+		buildID = deploy.Status.Build
+	}
+
+	if buildID == nil {
+		return externalDeploy, nil
+	}
+
+	build, err := b.backend.latticeClient.LatticeV1().Builds(deploy.Namespace).Get(string(*buildID), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return externalDeploy, nil
+		}
+
+		return v1.Deploy{}, err
+	}
+
+	// the build hasn't resolved its definition yet
+	if build.Status.Definition == nil || build.Status.Path == nil {
+		return externalDeploy, nil
+	}
+
+	model, err := kubecost.Model(b.backend.latticeClient, b.backend.namespacePrefix)
+	if err != nil {
+		return v1.Deploy{}, err
+	}
+
+	if len(model.InstanceTypePrices) == 0 {
+		return externalDeploy, nil
+	}
+
+	system, err := b.backend.getSystem(b.system)
+	if err != nil {
+		return v1.Deploy{}, err
+	}
+
+	delta := model.MonthlyCostDelta(system.Spec.Definition, *build.Status.Path, build.Status.Definition)
+	externalDeploy.MonthlyCostDelta = &delta
+	return externalDeploy, nil
+}
+
+// transformDeploy transforms the deploy into its external representation.
+// queue holds the system's queued deploys, which determine the deploy's
+// position in the queue if it is queued.
+func transformDeploy(deploy *latticev1.Deploy, queue []*latticev1.Deploy) (v1.Deploy, error) {
 	state, err := getDeployState(deploy.Status.State)
 	if err != nil {
 		return v1.Deploy{}, err
 	}
 
+	var queuePosition *int32
+	if deploy.Status.State == latticev1.DeployStateQueued && deploy.Status.Path != nil {
+		position := int32(len(deploy.QueuedAhead(*deploy.Status.Path, queue)) + 1)
+		queuePosition = &position
+	}
+
 	var startTimestamp *time.Time
 	if deploy.Status.StartTimestamp != nil {
 		startTimestamp = time.New(deploy.Status.StartTimestamp.Time)
@@ -142,12 +382,33 @@ func transformDeploy(deploy *latticev1.Deploy) (v1.Deploy, error) {
 		completionTimestamp = time.New(deploy.Status.CompletionTimestamp.Time)
 	}
 
+	source, err := deploy.BuildSourceAnnotation()
+	if err != nil {
+		return v1.Deploy{}, err
+	}
+
+	approvals, err := deploy.ApprovalsAnnotation()
+	if err != nil {
+		return v1.Deploy{}, err
+	}
+
+	monthlyCostDelta, err := deploy.MonthlyCostDeltaAnnotation()
+	if err != nil {
+		return v1.Deploy{}, err
+	}
+
 	externalDeploy := v1.Deploy{
 		ID: v1.DeployID(deploy.Name),
 
 		Build:   deploy.Spec.Build,
 		Path:    deploy.Spec.Path,
 		Version: deploy.Spec.Version,
+		Source:  source,
+
+		CreatedBy: deploy.CreatorAnnotation(),
+		Approvals: approvals,
+
+		MonthlyCostDelta: monthlyCostDelta,
 
 		Status: v1.DeployStatus{
 			State:   state,
@@ -157,6 +418,8 @@ func transformDeploy(deploy *latticev1.Deploy) (v1.Deploy, error) {
 			Path:    deploy.Status.Path,
 			Version: deploy.Status.Version,
 
+			QueuePosition: queuePosition,
+
 			StartTimestamp:      startTimestamp,
 			CompletionTimestamp: completionTimestamp,
 		},
@@ -169,6 +432,10 @@ func getDeployState(state latticev1.DeployState) (v1.DeployState, error) {
 	switch state {
 	case latticev1.DeployStatePending:
 		return v1.DeployStatePending, nil
+	case latticev1.DeployStateAwaitingApproval:
+		return v1.DeployStateAwaitingApproval, nil
+	case latticev1.DeployStateQueued:
+		return v1.DeployStateQueued, nil
 	case latticev1.DeployStateAccepted:
 		return v1.DeployStateAccepted, nil
 	case latticev1.DeployStateInProgress:
@@ -0,0 +1,15 @@
+package system
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/imagepolicy"
+)
+
+func (b *Backend) SetImagePolicy(id v1.SystemID, policy *v1.ImagePolicy) (*v1.System, error) {
+	if imagepolicy.IsEmpty(policy) {
+		return b.setSystemAnnotation(id, latticev1.SystemImagePolicyAnnotationKey, nil)
+	}
+
+	return b.setSystemAnnotation(id, latticev1.SystemImagePolicyAnnotationKey, policy)
+}
@@ -42,6 +42,10 @@ func (b *jobBackend) Run(
 		return nil, err
 	}
 
+	if err := b.backend.checkJobRunQuota(b.system, &job.Spec.Definition); err != nil {
+		return nil, err
+	}
+
 	jobRun := &latticev1.JobRun{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: uuid.NewV4().String(),
@@ -1,12 +1,14 @@
 package system
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
-	"github.com/mlab-lattice/lattice/pkg/util/time"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,10 +57,127 @@ func (b *nodePoolBackend) Get(path tree.PathSubcomponent) (*v1.NodePool, error)
 		return nil, err
 	}
 
+	nodePool, err := b.getNodePool(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if nodePool == nil {
+		return nil, nil
+	}
+
+	externalNodePool, err := b.transformNodePool(nodePool.Name, path, nodePool)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalNodePool, nil
+}
+
+func (b *nodePoolBackend) Override(path tree.PathSubcomponent, numInstances int32) (*v1.NodePool, error) {
+	// ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
+	}
+
+	nodePool, err := b.getNodePool(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if nodePool == nil {
+		return nil, v1.NewInvalidPathError()
+	}
+
+	override, err := nodePool.OverrideAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	// if the node pool was already overridden its spec no longer reflects
+	// the system's definition
+	definitionNumInstances := nodePool.Spec.NumInstances
+	if override != nil {
+		definitionNumInstances = override.DefinitionNumInstances
+	}
+
+	override = &v1.NodePoolOverride{
+		NumInstances:           numInstances,
+		DefinitionNumInstances: definitionNumInstances,
+		Timestamp:              *timeutil.New(time.Now()),
+	}
+	data, err := json.Marshal(override)
+	if err != nil {
+		return nil, err
+	}
+
+	if nodePool.Annotations == nil {
+		nodePool.Annotations = make(map[string]string)
+	}
+	nodePool.Annotations[latticev1.NodePoolOverrideAnnotationKey] = string(data)
+	nodePool.Spec.NumInstances = numInstances
+
+	return b.updateNodePool(path, nodePool)
+}
+
+func (b *nodePoolBackend) ClearOverride(path tree.PathSubcomponent) (*v1.NodePool, error) {
+	// ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
+	}
+
+	nodePool, err := b.getNodePool(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if nodePool == nil {
+		return nil, v1.NewInvalidPathError()
+	}
+
+	override, err := nodePool.OverrideAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	if override == nil {
+		externalNodePool, err := b.transformNodePool(nodePool.Name, path, nodePool)
+		if err != nil {
+			return nil, err
+		}
+
+		return &externalNodePool, nil
+	}
+
+	delete(nodePool.Annotations, latticev1.NodePoolOverrideAnnotationKey)
+	nodePool.Spec.NumInstances = override.DefinitionNumInstances
+
+	return b.updateNodePool(path, nodePool)
+}
+
+func (b *nodePoolBackend) updateNodePool(path tree.PathSubcomponent, nodePool *latticev1.NodePool) (*v1.NodePool, error) {
+	result, err := b.backend.latticeClient.LatticeV1().NodePools(nodePool.Namespace).Update(nodePool)
+	if err != nil {
+		if errors.IsConflict(err) {
+			return nil, v1.NewConflictError()
+		}
+
+		return nil, err
+	}
+
+	externalNodePool, err := b.transformNodePool(result.Name, path, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalNodePool, nil
+}
+
+// getNodePool returns the node pool at path, or nil if there is not one.
+func (b *nodePoolBackend) getNodePool(path tree.PathSubcomponent) (*latticev1.NodePool, error) {
 	namespace := b.backend.systemNamespace(b.system)
-	var selector labels.Selector
+	selector := labels.NewSelector()
 	if path.Subcomponent() == "" {
-		selector = labels.NewSelector()
 		requirement, err := labels.NewRequirement(
 			latticev1.ServicePathLabelKey,
 			selection.Equals,
@@ -132,13 +251,7 @@ func (b *nodePoolBackend) Get(path tree.PathSubcomponent) (*v1.NodePool, error)
 		return nil, nil
 	}
 
-	nodePool := &nodePools.Items[0]
-	externalNodePool, err := b.transformNodePool(nodePool.Name, path, nodePool)
-	if err != nil {
-		return nil, err
-	}
-
-	return &externalNodePool, nil
+	return &nodePools.Items[0], nil
 }
 
 func (b *nodePoolBackend) getNodePoolPath(nodePool *latticev1.NodePool) (tree.PathSubcomponent, error) {
@@ -187,7 +300,7 @@ func (b *nodePoolBackend) transformNodePool(id string, path tree.PathSubcomponen
 	var failureInfo *v1.NodePoolFailureInfo
 	if nodePool.Status.FailureInfo != nil {
 		failureInfo = &v1.NodePoolFailureInfo{
-			Time:    *time.New(nodePool.Status.FailureInfo.Timestamp.Time),
+			Time:    *timeutil.New(nodePool.Status.FailureInfo.Timestamp.Time),
 			Message: nodePool.Status.FailureInfo.Message,
 		}
 	}
@@ -204,8 +317,53 @@ func (b *nodePoolBackend) transformNodePool(id string, path tree.PathSubcomponen
 	}
 
 	var numInstances int32 = 0
-	for _, epoch := range nodePool.Status.Epochs {
-		numInstances += epoch.Status.NumInstances
+	var epochs []v1.NodePoolEpochStatus
+	for _, epoch := range nodePool.Status.Epochs.Epochs() {
+		status := nodePool.Status.Epochs[epoch].Status
+		numInstances += status.NumInstances
+
+		epochState, err := getNodePoolState(status.State)
+		if err != nil {
+			return v1.NodePool{}, err
+		}
+
+		epochs = append(epochs, v1.NodePoolEpochStatus{
+			Epoch:        int64(epoch),
+			State:        epochState,
+			InstanceType: status.InstanceType,
+			NumInstances: status.NumInstances,
+		})
+	}
+
+	override, err := nodePool.OverrideAnnotation()
+	if err != nil {
+		return v1.NodePool{}, err
+	}
+
+	var autoscaling *v1.NodePoolAutoscaling
+	if nodePool.Spec.Autoscaled() {
+		autoscaling = &v1.NodePoolAutoscaling{
+			MinInstances: nodePool.Spec.MinInstances,
+			MaxInstances: nodePool.Spec.MaxInstances,
+		}
+	}
+
+	var spot *v1.NodePoolSpot
+	if nodePool.Spec.Spot != nil {
+		spot = &v1.NodePoolSpot{
+			OnDemandBaseInstances: nodePool.Spec.Spot.OnDemandBaseInstances,
+			OnDemandPercentage:    nodePool.Spec.Spot.OnDemandPercentage,
+			Fallback:              string(nodePool.Spec.SpotFallback()),
+		}
+
+		fallbackTimestamp, ok, err := nodePool.SpotFallbackAnnotation()
+		if err != nil {
+			return v1.NodePool{}, err
+		}
+
+		if ok {
+			spot.FallbackTimestamp = timeutil.New(fallbackTimestamp)
+		}
 	}
 
 	externalNodePool := v1.NodePool{
@@ -215,12 +373,19 @@ func (b *nodePoolBackend) transformNodePool(id string, path tree.PathSubcomponen
 		InstanceType: nodePool.Spec.InstanceType,
 		NumInstances: nodePool.Spec.NumInstances,
 
+		InstanceTypes: nodePool.Spec.AllInstanceTypes(),
+		Spot:          spot,
+
+		Autoscaling: autoscaling,
+		Override:    override,
+
 		Status: v1.NodePoolStatus{
 			State:       state,
 			FailureInfo: failureInfo,
 
 			InstanceType: instanceType,
 			NumInstances: numInstances,
+			Epochs:       epochs,
 		},
 	}
 	return externalNodePool, nil
@@ -0,0 +1,89 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	fakelattice "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned/fake"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNamespacePrefix = "lattice"
+	testSystemID        = v1.SystemID("system")
+)
+
+func testSystem() *latticev1.System {
+	return &latticev1.System{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      string(testSystemID),
+			Namespace: kubeutil.InternalNamespace(testNamespacePrefix),
+		},
+		Status: latticev1.SystemStatus{
+			State: latticev1.SystemStateStable,
+		},
+	}
+}
+
+func TestNodePoolOverride(t *testing.T) {
+	path, err := tree.NewPathSubcomponent("/a:pool")
+	require.NoError(t, err)
+
+	nodePool := &latticev1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: kubeutil.SystemNamespace(testNamespacePrefix, testSystemID),
+			Labels: map[string]string{
+				latticev1.NodePoolSystemSharedPathLabelKey: path.Path().ToDomain(),
+				latticev1.NodePoolSystemSharedNameLabelKey: path.Subcomponent(),
+			},
+		},
+		Spec: latticev1.NodePoolSpec{
+			NumInstances: 2,
+			InstanceType: "t2.small",
+		},
+		Status: latticev1.NodePoolStatus{
+			State: latticev1.NodePoolStateStable,
+		},
+	}
+
+	backend := NewBackend(testNamespacePrefix, nil, nil, fakelattice.NewSimpleClientset(testSystem(), nodePool), nil)
+	nodePools := backend.NodePools(testSystemID)
+
+	// clearing a node pool that isn't overridden leaves it as it is
+	result, err := nodePools.ClearOverride(path)
+	require.NoError(t, err)
+	require.Nil(t, result.Override)
+	require.Equal(t, int32(2), result.NumInstances)
+
+	result, err = nodePools.Override(path, 5)
+	require.NoError(t, err)
+	require.NotNil(t, result.Override)
+	require.Equal(t, int32(5), result.NumInstances)
+	require.Equal(t, int32(5), result.Override.NumInstances)
+	require.Equal(t, int32(2), result.Override.DefinitionNumInstances)
+
+	// overriding again keeps the number of instances in the definition
+	result, err = nodePools.Override(path, 3)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), result.NumInstances)
+	require.Equal(t, int32(2), result.Override.DefinitionNumInstances)
+
+	result, err = nodePools.ClearOverride(path)
+	require.NoError(t, err)
+	require.Nil(t, result.Override)
+	require.Equal(t, int32(2), result.NumInstances)
+
+	// node pools that don't exist can't be overridden
+	missing, err := tree.NewPathSubcomponent("/b:pool")
+	require.NoError(t, err)
+
+	_, err = nodePools.Override(missing, 5)
+	require.Equal(t, v1.NewInvalidPathError(), err)
+}
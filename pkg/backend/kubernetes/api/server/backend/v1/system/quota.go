@@ -0,0 +1,227 @@
+package system
+
+import (
+	"encoding/json"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (b *Backend) SetQuota(id v1.SystemID, systemQuota *v1.SystemQuota) (*v1.System, error) {
+	return b.setSystemAnnotation(id, latticev1.SystemQuotaAnnotationKey, systemQuota)
+}
+
+func (b *Backend) SetContainerResources(
+	id v1.SystemID,
+	resources *v1.SystemContainerResources,
+) (*v1.System, error) {
+	return b.setSystemAnnotation(id, latticev1.SystemContainerResourcesAnnotationKey, resources)
+}
+
+// setSystemAnnotation sets the annotation of the system to the JSON encoded value.
+// If the value is nil the annotation is removed.
+func (b *Backend) setSystemAnnotation(id v1.SystemID, key string, value interface{}) (*v1.System, error) {
+	system, err := b.getSystem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if system.DeletionTimestamp != nil {
+		return nil, v1.NewSystemDeletingError()
+	}
+
+	system = system.DeepCopy()
+	if value == nil {
+		delete(system.Annotations, key)
+	} else {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if system.Annotations == nil {
+			system.Annotations = make(map[string]string)
+		}
+		system.Annotations[key] = string(data)
+	}
+
+	_, err = b.latticeClient.LatticeV1().Systems(b.internalNamespace()).Update(system)
+	if err != nil {
+		if errors.IsConflict(err) {
+			return nil, v1.NewConflictError()
+		}
+
+		return nil, err
+	}
+
+	return b.Get(id)
+}
+
+// systemUsage returns the resources used by the system's definition and by its
+// running job runs and builds.
+func (b *Backend) systemUsage(system *latticev1.System) (*v1.SystemUsage, error) {
+	usage, err := quota.DefinitionUsage(system.Spec.Definition)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := b.systemNamespace(system.V1ID())
+	jobRuns, err := b.jobRunsUsage(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := b.runningBuilds(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	usage = quota.Add(usage, jobRuns)
+	usage.Builds = builds
+	return &usage, nil
+}
+
+// checkJobRunQuota returns a quota exceeded error if running the job would
+// exceed the CPU, memory or job runs limits of the system's quota, or if the
+// job's containers don't request the CPU or memory the quota limits.
+func (b *Backend) checkJobRunQuota(id v1.SystemID, job *definitionv1.Job) error {
+	system, err := b.getSystem(id)
+	if err != nil {
+		return err
+	}
+
+	systemQuota, err := system.QuotaAnnotation()
+	if err != nil {
+		return err
+	}
+
+	if systemQuota == nil {
+		return nil
+	}
+
+	usage, err := quota.DefinitionUsage(system.Spec.Definition)
+	if err != nil {
+		return err
+	}
+
+	jobRuns, err := b.jobRunsUsage(b.systemNamespace(id))
+	if err != nil {
+		return err
+	}
+
+	if err := quota.CheckRequests(systemQuota, job); err != nil {
+		if _, ok := err.(*quota.UnrequestedError); ok {
+			return v1.NewSystemQuotaExceededError()
+		}
+
+		return err
+	}
+
+	jobRun, err := quota.WorkloadUsage(job)
+	if err != nil {
+		return err
+	}
+
+	// services' instances don't change when running a job, so only check the
+	// limits that the job run counts towards
+	usage = v1.SystemUsage{
+		MilliCPU:  usage.MilliCPU + jobRuns.MilliCPU + jobRun.MilliCPU,
+		MemoryMiB: usage.MemoryMiB + jobRuns.MemoryMiB + jobRun.MemoryMiB,
+		JobRuns:   jobRuns.JobRuns + 1,
+	}
+	if err := quota.Check(systemQuota, usage); err != nil {
+		if _, ok := err.(*quota.ExceededError); ok {
+			return v1.NewSystemQuotaExceededError()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// checkBuildQuota returns a quota exceeded error if creating another build would
+// exceed the builds limit of the system's quota.
+func (b *Backend) checkBuildQuota(id v1.SystemID) error {
+	system, err := b.getSystem(id)
+	if err != nil {
+		return err
+	}
+
+	systemQuota, err := system.QuotaAnnotation()
+	if err != nil {
+		return err
+	}
+
+	if systemQuota == nil {
+		return nil
+	}
+
+	builds, err := b.runningBuilds(b.systemNamespace(id))
+	if err != nil {
+		return err
+	}
+
+	if err := quota.Check(systemQuota, v1.SystemUsage{Builds: builds + 1}); err != nil {
+		if _, ok := err.(*quota.ExceededError); ok {
+			return v1.NewSystemQuotaExceededError()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// jobRunsUsage returns the CPU and memory requested by the job runs in the
+// namespace that have not finished, and the number of them.
+func (b *Backend) jobRunsUsage(namespace string) (v1.SystemUsage, error) {
+	jobRuns, err := b.latticeClient.LatticeV1().JobRuns(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return v1.SystemUsage{}, err
+	}
+
+	var usage v1.SystemUsage
+	for i := range jobRuns.Items {
+		jobRun := &jobRuns.Items[i]
+		switch jobRun.Status.State {
+		case latticev1.JobRunStatePending, latticev1.JobRunStateQueued, latticev1.JobRunStateRunning:
+		default:
+			continue
+		}
+
+		jobRunUsage, err := quota.WorkloadUsage(&jobRun.Spec.Definition)
+		if err != nil {
+			return v1.SystemUsage{}, err
+		}
+
+		usage = quota.Add(usage, jobRunUsage)
+		usage.JobRuns++
+	}
+
+	return usage, nil
+}
+
+// runningBuilds returns the number of builds in the namespace that have not
+// finished.
+func (b *Backend) runningBuilds(namespace string) (int32, error) {
+	builds, err := b.latticeClient.LatticeV1().Builds(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	var running int32
+	for _, build := range builds.Items {
+		switch build.Status.State {
+		case latticev1.BuildStatePending, latticev1.BuildStateAccepted, latticev1.BuildStateRunning:
+			running++
+		}
+	}
+
+	return running, nil
+}
@@ -1,16 +1,18 @@
 package system
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
-	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+	kubesecretprovider "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/secretprovider"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
-	corev1 "k8s.io/api/core/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/selection"
 )
 
 type secretBackend struct {
@@ -24,186 +26,241 @@ func (b *secretBackend) List() ([]v1.Secret, error) {
 		return nil, err
 	}
 
-	// There are secrets in the namespace that are not secrets set for lattice.
-	// Don't expose those in ListSystemSecrets
-	selector := labels.NewSelector()
-	requirement, err := labels.NewRequirement(latticev1.SecretPathLabelKey, selection.Exists, nil)
-	if err != nil {
-		return nil, err
-	}
-	selector = selector.Add(*requirement)
-
-	namespace := b.backend.systemNamespace(b.system)
-	secrets, err := b.backend.kubeClient.CoreV1().Secrets(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
-	if err != nil {
-		return nil, err
-	}
-
-	externalSecrets := make([]v1.Secret, 0)
-	for _, secret := range secrets.Items {
-		path, err := tree.NewPathFromDomain(secret.Labels[latticev1.SecretPathLabelKey])
+	secrets := make([]v1.Secret, 0)
+	for _, name := range b.backend.secretProviders.Names() {
+		provider, err := b.backend.secretProviders.Provider(name)
 		if err != nil {
 			return nil, err
 		}
 
-		for name, value := range secret.Data {
-			subcomponent, err := tree.NewPathSubcomponentFromParts(path, name)
-			if err != nil {
-				return nil, err
-			}
-
-			externalSecrets = append(externalSecrets, v1.Secret{
-				Path:  subcomponent,
-				Value: string(value),
-			})
+		providerSecrets, err := provider.List(b.system)
+		if err != nil {
+			return nil, err
 		}
+
+		secrets = append(secrets, providerSecrets...)
 	}
 
-	return externalSecrets, nil
+	return secrets, nil
 }
 
-func (b *secretBackend) Get(subcomponent tree.PathSubcomponent) (*v1.Secret, error) {
-	// ensure the system exists
-	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+func (b *secretBackend) Get(subcomponent tree.PathSubcomponent, providerName string) (*v1.Secret, error) {
+	provider, err := b.provider(providerName)
+	if err != nil {
 		return nil, err
 	}
 
-	kubeSecretName, err := kubeSecretName(subcomponent.Path())
+	return provider.Get(b.system, subcomponent)
+}
+
+func (b *secretBackend) Versions(subcomponent tree.PathSubcomponent, providerName string) ([]v1.Secret, error) {
+	provider, err := b.provider(providerName)
 	if err != nil {
 		return nil, err
 	}
 
-	namespace := b.backend.systemNamespace(b.system)
-	secret, err := b.backend.kubeClient.CoreV1().Secrets(namespace).Get(kubeSecretName, metav1.GetOptions{})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil, v1.NewInvalidSecretError()
-		}
+	return provider.Versions(b.system, subcomponent)
+}
 
+func (b *secretBackend) Reveal(subcomponent tree.PathSubcomponent, providerName string, version int32) (*v1.Secret, error) {
+	provider, err := b.provider(providerName)
+	if err != nil {
 		return nil, err
 	}
 
-	value, ok := secret.Data[subcomponent.Subcomponent()]
-	if !ok {
-		return nil, v1.NewInvalidSecretError()
-	}
-
-	externalSecret := &v1.Secret{
-		Path:  subcomponent,
-		Value: string(value),
-	}
-	return externalSecret, nil
+	return provider.Reveal(b.system, subcomponent, version)
 }
 
-func (b *secretBackend) Set(subcomponent tree.PathSubcomponent, value string) error {
-	// ensure the system exists
-	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+func (b *secretBackend) Set(
+	subcomponent tree.PathSubcomponent,
+	providerName, value, author string,
+	restart bool,
+) error {
+	provider, err := b.provider(providerName)
+	if err != nil {
 		return err
 	}
 
-	kubeSecretName, err := kubeSecretName(subcomponent.Path())
-	if err != nil {
+	if err := provider.Set(b.system, subcomponent, value, author); err != nil {
 		return err
 	}
 
-	namespace := b.backend.systemNamespace(b.system)
-	secret, err := b.backend.kubeClient.CoreV1().Secrets(namespace).Get(kubeSecretName, metav1.GetOptions{})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return b.createSecret(subcomponent, value)
-		}
+	if providerName == "" {
+		providerName = b.backend.secretProviders.Default()
+	}
+	return b.updateReferencingWorkloads(subcomponent, providerName, restart)
+}
 
+func (b *secretBackend) Unset(subcomponent tree.PathSubcomponent, providerName string) error {
+	provider, err := b.provider(providerName)
+	if err != nil {
 		return err
 	}
 
-	secret.StringData = map[string]string{
-		subcomponent.Subcomponent(): value,
-	}
-	_, err = b.backend.kubeClient.CoreV1().Secrets(namespace).Update(secret)
-	if err == nil {
-		return nil
-	}
+	return provider.Unset(b.system, subcomponent)
+}
 
-	// if there was a conflict or the secret no longer exists (i.e. it was deleted since we found it)
-	// return a conflict error
-	if errors.IsConflict(err) || errors.IsNotFound(err) {
-		return v1.NewConflictError()
+// provider ensures the system exists and returns the named secret provider.
+func (b *secretBackend) provider(name string) (secretprovider.Interface, error) {
+	// ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
 	}
 
-	return err
+	return b.backend.secretProviders.Provider(name)
 }
 
-func (b *secretBackend) createSecret(subcomponent tree.PathSubcomponent, value string) error {
-	kubeSecretName, err := kubeSecretName(subcomponent.Path())
+// updateReferencingWorkloads updates the files of the services and running jobs whose files
+// reference the current version of the secret and restarts them, since kubernetes does not
+// update files mounted with a subPath. If restart is true, it also restarts the services and
+// running jobs that reference the secret at all. Services are restarted by updating their
+// rotated annotation, which the service controller copies to the pods it creates. Running jobs
+// are restarted by deleting their pods, which kubernetes will then recreate.
+func (b *secretBackend) updateReferencingWorkloads(
+	subcomponent tree.PathSubcomponent,
+	providerName string,
+	restart bool,
+) error {
+	namespace := b.backend.systemNamespace(b.system)
+	rotated := time.Now().UTC().Format(time.RFC3339Nano)
+
+	services, err := b.backend.latticeClient.LatticeV1().Services(namespace).List(metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
 
-	namespace := b.backend.systemNamespace(b.system)
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: kubeSecretName,
-			Labels: map[string]string{
-				latticev1.SecretPathLabelKey: subcomponent.Path().ToDomain(),
-			},
-		},
-		StringData: map[string]string{
-			subcomponent.Subcomponent(): value,
-		},
-	}
+	for _, service := range services.Items {
+		refs := kubesecretprovider.WorkloadSecretRefs(&service.Spec.Definition)
+		fileRefs := kubesecretprovider.WorkloadFileSecretRefs(&service.Spec.Definition)
 
-	_, err = b.backend.kubeClient.CoreV1().Secrets(namespace).Create(secret)
-	return err
-}
+		updateFiles := b.referencesSecret(fileRefs, subcomponent, providerName)
+		restartService := restart && b.referencesSecret(refs, subcomponent, providerName) || updateFiles
+		if !restartService {
+			continue
+		}
 
-func (b *secretBackend) Unset(subcomponent tree.PathSubcomponent) error {
-	// ensure the system exists
-	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
-		return err
+		if err := b.mirrorSecrets(refs); err != nil {
+			return err
+		}
+
+		if updateFiles {
+			owner := metav1.NewControllerRef(&service, latticev1.ServiceKind)
+			if err := b.updateFiles(service.Name, owner, &service.Spec.Definition); err != nil {
+				return err
+			}
+		}
+
+		s := service.DeepCopy()
+		if s.Annotations == nil {
+			s.Annotations = make(map[string]string)
+		}
+		s.Annotations[latticev1.SecretsRotatedAnnotationKey] = rotated
+
+		_, err := b.backend.latticeClient.LatticeV1().Services(namespace).Update(s)
+		if err != nil && !errors.IsNotFound(err) {
+			if errors.IsConflict(err) {
+				return v1.NewConflictError()
+			}
+
+			return err
+		}
 	}
 
-	kubeSecretName, err := kubeSecretName(subcomponent.Path())
+	jobRuns, err := b.backend.latticeClient.LatticeV1().JobRuns(namespace).List(metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
 
-	namespace := b.backend.systemNamespace(b.system)
-	secret, err := b.backend.kubeClient.CoreV1().Secrets(namespace).Get(kubeSecretName, metav1.GetOptions{})
-	if err != nil {
-		// If we can't find the secret, then it is unset
-		if errors.IsNotFound(err) {
-			return nil
+	for _, jobRun := range jobRuns.Items {
+		if jobRun.Status.State != latticev1.JobRunStateRunning {
+			continue
 		}
 
-		return err
-	}
+		refs := kubesecretprovider.WorkloadSecretRefs(&jobRun.Spec.Definition)
+		for _, v := range jobRun.Spec.Environment {
+			if v.SecretRef != nil {
+				refs = append(refs, *v.SecretRef)
+			}
+		}
+		fileRefs := kubesecretprovider.WorkloadFileSecretRefs(&jobRun.Spec.Definition)
 
-	delete(secret.Data, subcomponent.Subcomponent())
-	if len(secret.Data) == 0 {
-		err = b.backend.kubeClient.CoreV1().Secrets(namespace).Delete(secret.Name, nil)
-		if err != nil {
-			return nil
+		updateFiles := b.referencesSecret(fileRefs, subcomponent, providerName)
+		restartJobRun := restart && b.referencesSecret(refs, subcomponent, providerName) || updateFiles
+		if !restartJobRun {
+			continue
 		}
 
-		if errors.IsConflict(err) {
-			return v1.NewConflictError()
+		if err := b.mirrorSecrets(refs); err != nil {
+			return err
 		}
 
-		return err
-	}
+		if updateFiles {
+			owner := metav1.NewControllerRef(&jobRun, latticev1.JobRunKind)
+			if err := b.updateFiles(jobRun.Name, owner, &jobRun.Spec.Definition); err != nil {
+				return err
+			}
+		}
 
-	_, err = b.backend.kubeClient.CoreV1().Secrets(namespace).Update(secret)
-	if err == nil {
-		return nil
+		selector := fmt.Sprintf("%v=%v", latticev1.JobRunIDLabelKey, jobRun.Name)
+		err := b.backend.kubeClient.CoreV1().Pods(namespace).DeleteCollection(nil, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return err
+		}
 	}
 
-	if errors.IsConflict(err) {
-		return v1.NewConflictError()
+	return nil
+}
+
+func (b *secretBackend) referencesSecret(
+	refs []definitionv1.SecretRef,
+	subcomponent tree.PathSubcomponent,
+	providerName string,
+) bool {
+	for _, ref := range refs {
+		// references pinned to a version are unaffected by new versions
+		if ref.Value != subcomponent || ref.Version != 0 {
+			continue
+		}
+
+		provider := ref.Provider
+		if provider == "" {
+			provider = b.backend.secretProviders.Default()
+		}
+
+		if provider == providerName {
+			return true
+		}
 	}
 
-	return err
+	return false
+}
+
+// mirrorSecrets updates the mirrored values of the secrets so that the restarted
+// pods use the new version.
+func (b *secretBackend) mirrorSecrets(refs []definitionv1.SecretRef) error {
+	return kubesecretprovider.EnsureSecretsMirrored(
+		b.backend.namespacePrefix,
+		b.backend.kubeClient,
+		b.backend.secretProviders,
+		b.system,
+		refs,
+	)
 }
 
-func kubeSecretName(path tree.Path) (string, error) {
-	return latticeutil.HashPath(path)
+// updateFiles re-renders the workload's templated files so that they use the
+// new version of the secret.
+func (b *secretBackend) updateFiles(
+	name string,
+	owner *metav1.OwnerReference,
+	workload definitionv1.Workload,
+) error {
+	return kubesecretprovider.EnsureWorkloadFiles(
+		b.backend.namespacePrefix,
+		b.backend.kubeClient,
+		b.backend.secretProviders,
+		b.system,
+		name,
+		*owner,
+		workload,
+	)
 }
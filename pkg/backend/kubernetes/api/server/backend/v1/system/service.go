@@ -234,11 +234,18 @@ func (b *serviceBackend) transformService(
 		return v1.Service{}, err
 	}
 
+	fault, err := transformServiceFault(service)
+	if err != nil {
+		return v1.Service{}, err
+	}
+
 	externalService := v1.Service{
 		ID: v1.ServiceID(id),
 
 		Path: path,
 
+		Fault: fault,
+
 		Status: v1.ServiceStatus{
 			State:       state,
 			Message:     message,
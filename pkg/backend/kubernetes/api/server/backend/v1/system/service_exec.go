@@ -0,0 +1,213 @@
+package system
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+func (b *serviceBackend) Exec(
+	id v1.ServiceID,
+	sidecar *string,
+	instance string,
+	command []string,
+	tty bool,
+	streams *v1.ContainerExecStreams,
+) error {
+	namespace, pod, err := b.instancePod(id, instance)
+	if err != nil {
+		return err
+	}
+
+	container := kubeutil.UserMainContainerName
+	if sidecar != nil {
+		container = kubeutil.UserSidecarContainerName(*sidecar)
+	}
+
+	// with a TTY stderr is written to stdout
+	options := &corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     streams.Stdin != nil,
+		Stdout:    true,
+		Stderr:    !tty,
+		TTY:       tty,
+	}
+
+	req := b.backend.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(options, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(b.backend.kubeConfig, http.MethodPost, req.URL())
+	if err != nil {
+		return err
+	}
+
+	streamOptions := remotecommand.StreamOptions{
+		Stdin:  streams.Stdin,
+		Stdout: streams.Stdout,
+		Tty:    tty,
+	}
+
+	if !tty {
+		streamOptions.Stderr = streams.Stderr
+	}
+
+	if tty && streams.Resize != nil {
+		streamOptions.TerminalSizeQueue = terminalSizeQueue(streams.Resize)
+	}
+
+	return executor.Stream(streamOptions)
+}
+
+func (b *serviceBackend) PortForward(id v1.ServiceID, instance string, port int32, conn io.ReadWriter) error {
+	namespace, pod, err := b.instancePod(id, instance)
+	if err != nil {
+		return err
+	}
+
+	req := b.backend.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(b.backend.kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+	streamConn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return fmt.Errorf("error dialing pod %v/%v: %v", namespace, pod.Name, err)
+	}
+	defer streamConn.Close()
+
+	// each forwarded connection needs an error and a data stream that share
+	// a request ID, see k8s.io/client-go/tools/portforward
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, corev1.StreamTypeError)
+	headers.Set(corev1.PortHeader, strconv.Itoa(int(port)))
+	headers.Set(corev1.PortForwardRequestIDHeader, "0")
+	errorStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("error creating error stream for pod %v/%v: %v", namespace, pod.Name, err)
+	}
+	// the error stream is only read from
+	errorStream.Close()
+
+	errorChan := make(chan error, 1)
+	go func() {
+		message, err := ioutil.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			errorChan <- fmt.Errorf("error reading from error stream for port %v: %v", port, err)
+
+		case len(message) > 0:
+			errorChan <- fmt.Errorf("error forwarding port %v: %v", port, string(message))
+		}
+		close(errorChan)
+	}()
+
+	headers.Set(corev1.StreamType, corev1.StreamTypeData)
+	dataStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("error creating data stream for pod %v/%v: %v", namespace, pod.Name, err)
+	}
+
+	remoteDone := make(chan struct{})
+	go func() {
+		// copy from the pod until it closes the stream
+		io.Copy(conn, dataStream)
+		close(remoteDone)
+	}()
+
+	localDone := make(chan struct{})
+	go func() {
+		// inform the pod that the connection is done sending data
+		defer dataStream.Close()
+		io.Copy(dataStream, conn)
+		close(localDone)
+	}()
+
+	// the forward is done once the pod closes the data stream, or the
+	// connection is closed and the pod has had a chance to respond
+	select {
+	case <-remoteDone:
+	case <-localDone:
+		<-remoteDone
+	}
+
+	return <-errorChan
+}
+
+// instancePod returns the namespace of the system and the pod of the
+// service's instance. If no instance is specified the service must have
+// exactly one pod.
+func (b *serviceBackend) instancePod(id v1.ServiceID, instance string) (string, *corev1.Pod, error) {
+	// ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return "", nil, err
+	}
+
+	namespace := b.backend.systemNamespace(b.system)
+	_, err := b.backend.latticeClient.LatticeV1().Services(namespace).Get(string(id), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil, v1.NewInvalidServiceIDError()
+		}
+
+		return "", nil, err
+	}
+
+	if instance == "" {
+		pod, err := b.findServicePod(id, instance, namespace)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return namespace, pod, nil
+	}
+
+	name := toServiceInstanceFullID(id, instance)
+	pod, err := b.backend.kubeClient.CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil, v1.NewInvalidInstanceError()
+		}
+
+		return "", nil, err
+	}
+
+	return namespace, pod, nil
+}
+
+// terminalSizeQueue adapts a channel of terminal sizes to a
+// remotecommand.TerminalSizeQueue.
+type terminalSizeQueue <-chan v1.TerminalSize
+
+func (q terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+
+	return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+}
@@ -0,0 +1,147 @@
+package system
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (b *serviceBackend) InjectFault(id v1.ServiceID, fault *v1.ServiceFault) error {
+	internalFault, err := toInternalServiceFault(fault)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(internalFault)
+	if err != nil {
+		return err
+	}
+
+	service, err := b.getService(id)
+	if err != nil {
+		return err
+	}
+
+	if service.Annotations == nil {
+		service.Annotations = make(map[string]string)
+	}
+	service.Annotations[latticev1.ServiceFaultAnnotationKey] = string(data)
+
+	return b.updateService(service)
+}
+
+func (b *serviceBackend) ClearFault(id v1.ServiceID) error {
+	service, err := b.getService(id)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := service.Annotations[latticev1.ServiceFaultAnnotationKey]; !ok {
+		return nil
+	}
+
+	delete(service.Annotations, latticev1.ServiceFaultAnnotationKey)
+	return b.updateService(service)
+}
+
+func (b *serviceBackend) getService(id v1.ServiceID) (*latticev1.Service, error) {
+	// ensure the system exists
+	if _, err := b.backend.ensureSystemCreated(b.system); err != nil {
+		return nil, err
+	}
+
+	namespace := b.backend.systemNamespace(b.system)
+	service, err := b.backend.latticeClient.LatticeV1().Services(namespace).Get(string(id), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, v1.NewInvalidServiceIDError()
+		}
+
+		return nil, err
+	}
+
+	return service, nil
+}
+
+func (b *serviceBackend) updateService(service *latticev1.Service) error {
+	_, err := b.backend.latticeClient.LatticeV1().Services(service.Namespace).Update(service)
+	if err != nil {
+		if errors.IsConflict(err) {
+			return v1.NewConflictError()
+		}
+
+		if errors.IsNotFound(err) {
+			return v1.NewInvalidServiceIDError()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func toInternalServiceFault(fault *v1.ServiceFault) (*latticev1.ServiceFault, error) {
+	internalFault := &latticev1.ServiceFault{
+		Percentage: fault.Percentage,
+		Source:     fault.Source,
+		Expires:    metav1.NewTime(fault.Expires.Time),
+	}
+
+	if fault.Delay != nil {
+		duration, err := time.ParseDuration(fault.Delay.Duration)
+		if err != nil {
+			return nil, v1.NewInvalidServiceFaultError()
+		}
+
+		internalFault.Delay = &latticev1.ServiceFaultDelay{
+			Duration: metav1.Duration{Duration: duration},
+		}
+	}
+
+	if fault.Abort != nil {
+		internalFault.Abort = &latticev1.ServiceFaultAbort{
+			HTTPStatus: fault.Abort.HTTPStatus,
+		}
+	}
+
+	return internalFault, nil
+}
+
+// transformServiceFault returns the external representation of the service's fault,
+// or nil if the service does not have a fault or its fault has expired.
+func transformServiceFault(service *latticev1.Service) (*v1.ServiceFault, error) {
+	fault, err := service.FaultAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	if fault == nil || fault.Expired(time.Now()) {
+		return nil, nil
+	}
+
+	externalFault := &v1.ServiceFault{
+		Percentage: fault.Percentage,
+		Source:     fault.Source,
+		Expires:    *timeutil.New(fault.Expires.Time),
+	}
+
+	if fault.Delay != nil {
+		externalFault.Delay = &v1.ServiceFaultDelay{
+			Duration: fault.Delay.Duration.Duration.String(),
+		}
+	}
+
+	if fault.Abort != nil {
+		externalFault.Abort = &v1.ServiceFaultAbort{
+			HTTPStatus: fault.Abort.HTTPStatus,
+		}
+	}
+
+	return externalFault, nil
+}
@@ -0,0 +1,63 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	fakelattice "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned/fake"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceFaultExpiry(t *testing.T) {
+	service := &latticev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "service",
+			Namespace: kubeutil.SystemNamespace(testNamespacePrefix, testSystemID),
+		},
+	}
+	latticeClient := fakelattice.NewSimpleClientset(testSystem(), service)
+	backend := NewBackend(testNamespacePrefix, nil, nil, latticeClient, nil)
+	services := backend.Services(testSystemID)
+
+	// storedFault returns the fault reported for the service as it is stored
+	storedFault := func() *v1.ServiceFault {
+		result, err := latticeClient.LatticeV1().Services(service.Namespace).Get(service.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		fault, err := transformServiceFault(result)
+		require.NoError(t, err)
+		return fault
+	}
+
+	fault := &v1.ServiceFault{
+		Delay:      &v1.ServiceFaultDelay{Duration: "2s"},
+		Percentage: 25,
+		// faults are stored with second precision
+		Expires: *timeutil.New(time.Now().Add(time.Hour).Truncate(time.Second)),
+	}
+	require.NoError(t, services.InjectFault("service", fault))
+
+	result := storedFault()
+	require.NotNil(t, result)
+	require.Equal(t, "2s", result.Delay.Duration)
+	require.Equal(t, int32(25), result.Percentage)
+	require.True(t, fault.Expires.Equal(result.Expires.Time))
+
+	// expired faults are no longer reported, even though they are still stored
+	fault.Expires = *timeutil.New(time.Now().Add(-time.Second))
+	require.NoError(t, services.InjectFault("service", fault))
+	require.Nil(t, storedFault())
+
+	require.NoError(t, services.ClearFault("service"))
+	require.Nil(t, storedFault())
+
+	fault.Delay.Duration = "soon"
+	require.Equal(t, v1.NewInvalidServiceFaultError(), services.InjectFault("service", fault))
+}
@@ -0,0 +1,14 @@
+package system
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+)
+
+func (b *Backend) SetTracing(id v1.SystemID, tracing *v1.SystemTracing) (*v1.System, error) {
+	if tracing.SamplingRate == nil {
+		return b.setSystemAnnotation(id, latticev1.SystemTracingSamplingRateAnnotationKey, nil)
+	}
+
+	return b.setSystemAnnotation(id, latticev1.SystemTracingSamplingRateAnnotationKey, *tracing.SamplingRate)
+}
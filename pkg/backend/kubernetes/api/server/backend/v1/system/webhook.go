@@ -0,0 +1,167 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/satori/go.uuid"
+)
+
+const (
+	webhookEventsConfigMapKey = "events"
+
+	// maxWebhookEvents is the number of events kept for each system
+	// so that the config map stays well below the size limit.
+	maxWebhookEvents = 100
+
+	// webhookEventsUpdateAttempts is the number of times recording an event
+	// is attempted when webhooks for the system are received concurrently.
+	webhookEventsUpdateAttempts = 5
+)
+
+type webhookBackend struct {
+	backend *Backend
+	system  v1.SystemID
+}
+
+func (b *webhookBackend) Config() (*v1.WebhookConfig, error) {
+	system, err := b.backend.getSystem(b.system)
+	if err != nil {
+		return nil, err
+	}
+
+	return system.WebhookAnnotation()
+}
+
+func (b *webhookBackend) SetConfig(config *v1.WebhookConfig) error {
+	system, err := b.backend.getSystem(b.system)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	system = system.DeepCopy()
+	if system.Annotations == nil {
+		system.Annotations = make(map[string]string)
+	}
+	system.Annotations[latticev1.SystemWebhookAnnotationKey] = string(data)
+
+	_, err = b.backend.latticeClient.LatticeV1().Systems(b.backend.internalNamespace()).Update(system)
+	return err
+}
+
+func (b *webhookBackend) ListEvents() ([]v1.WebhookEvent, error) {
+	if _, err := b.backend.getSystem(b.system); err != nil {
+		return nil, err
+	}
+
+	configMap, err := b.backend.kubeClient.CoreV1().ConfigMaps(b.backend.internalNamespace()).Get(
+		b.eventsConfigMapName(),
+		metav1.GetOptions{},
+	)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return make([]v1.WebhookEvent, 0), nil
+		}
+		return nil, err
+	}
+
+	return webhookEvents(configMap)
+}
+
+func (b *webhookBackend) RecordEvent(event *v1.WebhookEvent) error {
+	system, err := b.backend.getSystem(b.system)
+	if err != nil {
+		return err
+	}
+
+	event.ID = v1.WebhookEventID(uuid.NewV4().String())
+	event.ReceivedTimestamp = *timeutil.New(time.Now())
+
+	for i := 0; i < webhookEventsUpdateAttempts; i++ {
+		err = b.recordEvent(system, event)
+		if err == nil || !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (b *webhookBackend) recordEvent(system *latticev1.System, event *v1.WebhookEvent) error {
+	configMaps := b.backend.kubeClient.CoreV1().ConfigMaps(b.backend.internalNamespace())
+	configMap, err := configMaps.Get(b.eventsConfigMapName(), metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	exists := err == nil
+	if !exists {
+		// the config map is owned by the system so that it is deleted with it
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            b.eventsConfigMapName(),
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(system, latticev1.SystemKind)},
+			},
+		}
+	}
+
+	events, err := webhookEvents(configMap)
+	if err != nil {
+		return err
+	}
+
+	events = append([]v1.WebhookEvent{*event}, events...)
+	if len(events) > maxWebhookEvents {
+		events = events[:maxWebhookEvents]
+	}
+
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	configMap = configMap.DeepCopy()
+	configMap.Data = map[string]string{
+		webhookEventsConfigMapKey: string(data),
+	}
+
+	if exists {
+		_, err = configMaps.Update(configMap)
+		return err
+	}
+
+	_, err = configMaps.Create(configMap)
+	return err
+}
+
+func (b *webhookBackend) eventsConfigMapName() string {
+	return fmt.Sprintf("%v-webhook-events", b.system)
+}
+
+func webhookEvents(configMap *corev1.ConfigMap) ([]v1.WebhookEvent, error) {
+	events := make([]v1.WebhookEvent, 0)
+	data, ok := configMap.Data[webhookEventsConfigMapKey]
+	if !ok {
+		return events, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		return nil, fmt.Errorf("invalid webhook events in config map %v: %v", configMap.Name, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,107 @@
+package buildsource
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/constants"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/buildsource"
+	"github.com/mlab-lattice/lattice/pkg/util/aws"
+	"github.com/mlab-lattice/lattice/pkg/util/docker"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultRepository = "lattice-build-sources"
+	tagPrefix         = "build-source-"
+)
+
+// Store stores build sources in the registry that container build images are pushed to.
+type Store struct {
+	config *latticev1.ConfigComponentBuildDockerArtifact
+}
+
+// NewStore returns a store configured by the lattice's config.
+func NewStore(latticeClient latticeclientset.Interface, namespacePrefix string) (*Store, error) {
+	config, err := latticeClient.LatticeV1().Configs(kubeutil.InternalNamespace(namespacePrefix)).Get(
+		constants.ConfigGlobal,
+		metav1.GetOptions{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact := config.Spec.ContainerBuild.DockerArtifact
+	if !artifact.Push {
+		return nil, fmt.Errorf("build sources require container build images to be pushed to a registry")
+	}
+
+	return &Store{&artifact}, nil
+}
+
+// Push stores the tar archive as the build source with the id.
+func (s *Store) Push(id v1.BuildSourceID, archive io.Reader) (*v1.BuildSource, error) {
+	client := registry.NewClient(s.config.Registry, s.credentials())
+	digest, err := buildsource.Push(client, s.repository(), tagPrefix+string(id), archive)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &v1.BuildSource{
+		ID:     id,
+		Digest: digest,
+	}
+	return source, nil
+}
+
+// Get returns the build source with the id, or nil if it does not exist.
+func (s *Store) Get(id v1.BuildSourceID) (*v1.BuildSource, error) {
+	client := registry.NewClient(s.config.Registry, s.credentials())
+	digest, err := client.ManifestDigest(s.repository(), tagPrefix+string(id))
+	if err != nil {
+		if _, ok := err.(*registry.NotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	source := &v1.BuildSource{
+		ID:     id,
+		Digest: digest,
+	}
+	return source, nil
+}
+
+// Pull extracts the build source with the repository URL into the directory.
+func (s *Store) Pull(url, directory string) error {
+	return buildsource.Pull(s.credentials(), url, directory)
+}
+
+// URL returns the repository URL that the build source is resolved from.
+func (s *Store) URL(source *v1.BuildSource) string {
+	return buildsource.URL(s.config.Registry, s.repository(), source.Digest)
+}
+
+// repository returns the repository that build sources are stored in, which is the repository
+// container build images are stored in if they share one, otherwise lattice-build-sources.
+func (s *Store) repository() string {
+	if s.config.RepositoryPerImage || s.config.Repository == "" {
+		return defaultRepository
+	}
+
+	return s.config.Repository
+}
+
+func (s *Store) credentials() docker.RegistryLoginProvider {
+	if s.config.RegistryAuthType != nil && *s.config.RegistryAuthType == aws.EC2RoleDockerRegistryAuth {
+		return &aws.ECRRegistryAuthProvider{}
+	}
+
+	return nil
+}
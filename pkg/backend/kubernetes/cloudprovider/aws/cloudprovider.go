@@ -24,6 +24,7 @@ import (
 )
 
 const (
+	buildCacheVolumeHostPath          = "/var/lib/component-builder-cache"
 	workDirectoryVolumeHostPathPrefix = "/var/lib/component-builder"
 
 	AnnotationKeyLoadBalancerDNSName = "load-balancer.aws.cloud-provider.lattice.mlab.com/dns-name"
@@ -211,6 +212,14 @@ func (cp *DefaultAWSCloudProvider) ComponentBuildWorkDirectoryVolumeSource(jobNa
 	}
 }
 
+func (cp *DefaultAWSCloudProvider) ComponentBuildCacheVolumeSource() corev1.VolumeSource {
+	return corev1.VolumeSource{
+		HostPath: &corev1.HostPathVolumeSource{
+			Path: buildCacheVolumeHostPath,
+		},
+	}
+}
+
 func (cp *DefaultAWSCloudProvider) TransformPodTemplateSpec(spec *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
 	// nothing to do
 	return spec
@@ -3,6 +3,7 @@ package aws
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	kubetf "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cloudprovider/aws/terraform"
@@ -10,6 +11,10 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/util/terraform"
 	awstfprovider "github.com/mlab-lattice/lattice/pkg/util/terraform/provider/aws"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -26,6 +31,13 @@ const (
 	terraformOutputNodePoolSecurityGroupID                 = "security_group_id"
 )
 
+// spotInterruptionStatusCodes are the status codes of spot instance requests whose
+// instances have been sent an interruption notice.
+var spotInterruptionStatusCodes = map[string]bool{
+	"marked-for-stop":        true,
+	"marked-for-termination": true,
+}
+
 func (cp *DefaultAWSCloudProvider) NodePoolNeedsNewEpoch(nodePool *latticev1.NodePool) (bool, error) {
 	current, ok := nodePool.Status.Epochs.CurrentEpoch()
 
@@ -66,6 +78,30 @@ func (cp *DefaultAWSCloudProvider) NodePoolEpochStatus(
 	epoch latticev1.NodePoolEpoch,
 	epochSpec *latticev1.NodePoolSpec,
 ) (*latticev1.NodePoolStatusEpochStatus, error) {
+	nodes, err := cp.NodePoolEpochNodes(latticeID, nodePool, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := kubernetes.NumReadyNodes(nodes)
+	status := &latticev1.NodePoolStatusEpochStatus{
+		NumInstances: ready,
+		InstanceType: epochSpec.InstanceType,
+		State:        latticev1.NodePoolStateScaling,
+	}
+
+	if ready == epochSpec.NumInstances {
+		status.State = latticev1.NodePoolStateStable
+	}
+
+	return status, nil
+}
+
+func (cp *DefaultAWSCloudProvider) NodePoolEpochNodes(
+	latticeID v1.LatticeID,
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+) ([]corev1.Node, error) {
 	selector := labels.NewSelector()
 	requirement, err := labels.NewRequirement(latticev1.NodePoolIDLabelKey, selection.Equals, []string{nodePool.ID(epoch)})
 	if err != nil {
@@ -83,28 +119,169 @@ func (cp *DefaultAWSCloudProvider) NodePoolEpochStatus(
 		n = append(n, *node)
 	}
 
-	ready := kubernetes.NumReadyNodes(n)
-	status := &latticev1.NodePoolStatusEpochStatus{
-		NumInstances: ready,
-		InstanceType: epochSpec.InstanceType,
-		State:        latticev1.NodePoolStateScaling,
+	return n, nil
+}
+
+func (cp *DefaultAWSCloudProvider) RemoveNodePoolEpochNode(
+	latticeID v1.LatticeID,
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+	node *corev1.Node,
+) error {
+	instanceID, err := nodeInstanceID(node)
+	if err != nil {
+		return err
 	}
 
-	if ready == epochSpec.NumInstances {
-		status.State = latticev1.NodePoolStateStable
+	// Terminate the instance and decrement the autoscaling group's desired capacity at the
+	// same time so that the autoscaling group doesn't replace the instance, or pick a different
+	// instance to terminate once the node pool's number of instances is decremented.
+	input := &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(instanceID),
+		ShouldDecrementDesiredCapacity: aws.Bool(true),
+	}
+	_, err = autoscaling.New(cp.session).TerminateInstanceInAutoScalingGroup(input)
+	if err != nil {
+		return fmt.Errorf(
+			"error terminating instance %v of %v epoch %v: %v",
+			instanceID,
+			nodePool.Description(cp.namespacePrefix),
+			epoch,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (cp *DefaultAWSCloudProvider) NodePoolEpochSpotStatus(
+	latticeID v1.LatticeID,
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+) (*latticev1.NodePoolSpotStatus, error) {
+	capacityUnavailable, err := cp.nodePoolSpotCapacityUnavailable(nodePool)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error getting spot capacity of %v epoch %v: %v",
+			nodePool.Description(cp.namespacePrefix),
+			epoch,
+			err,
+		)
 	}
 
+	interruptedNodes, err := cp.nodePoolEpochInterruptedNodes(latticeID, nodePool, epoch)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error getting interrupted nodes of %v epoch %v: %v",
+			nodePool.Description(cp.namespacePrefix),
+			epoch,
+			err,
+		)
+	}
+
+	status := &latticev1.NodePoolSpotStatus{
+		CapacityUnavailable: capacityUnavailable,
+		InterruptedNodes:    interruptedNodes,
+	}
 	return status, nil
 }
 
+// nodePoolSpotCapacityUnavailable returns whether the most recent scaling activity
+// of the node pool's autoscaling group failed to launch spot instances.
+func (cp *DefaultAWSCloudProvider) nodePoolSpotCapacityUnavailable(nodePool *latticev1.NodePool) (bool, error) {
+	// The annotation is for the node pool's current epoch, which is the only
+	// epoch new instances are launched for.
+	name, ok := nodePool.Annotations[AnnotationKeyNodePoolAutoscalingGroupName]
+	if !ok {
+		return false, nil
+	}
+
+	input := &autoscaling.DescribeScalingActivitiesInput{
+		AutoScalingGroupName: aws.String(name),
+		MaxRecords:           aws.Int64(1),
+	}
+	output, err := autoscaling.New(cp.session).DescribeScalingActivities(input)
+	if err != nil {
+		return false, err
+	}
+
+	if len(output.Activities) == 0 {
+		return false, nil
+	}
+
+	activity := output.Activities[0]
+	switch aws.StringValue(activity.StatusCode) {
+	case autoscaling.ScalingActivityStatusCodeFailed, autoscaling.ScalingActivityStatusCodeCancelled:
+		return strings.Contains(aws.StringValue(activity.StatusMessage), "Spot"), nil
+	default:
+		return false, nil
+	}
+}
+
+func (cp *DefaultAWSCloudProvider) nodePoolEpochInterruptedNodes(
+	latticeID v1.LatticeID,
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+) ([]string, error) {
+	nodes, err := cp.NodePoolEpochNodes(latticeID, nodePool, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeNames := make(map[string]string)
+	var instanceIDs []*string
+	for i := range nodes {
+		instanceID, err := nodeInstanceID(&nodes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		nodeNames[instanceID] = nodes[i].Name
+		instanceIDs = append(instanceIDs, aws.String(instanceID))
+	}
+
+	if len(instanceIDs) == 0 {
+		return nil, nil
+	}
+
+	input := &ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: instanceIDs,
+			},
+		},
+	}
+	output, err := ec2.New(cp.session).DescribeSpotInstanceRequests(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var interrupted []string
+	for _, request := range output.SpotInstanceRequests {
+		if request.Status == nil || !spotInterruptionStatusCodes[aws.StringValue(request.Status.Code)] {
+			continue
+		}
+
+		if name, ok := nodeNames[aws.StringValue(request.InstanceId)]; ok {
+			interrupted = append(interrupted, name)
+		}
+	}
+
+	return interrupted, nil
+}
+
 func (cp *DefaultAWSCloudProvider) EnsureNodePoolEpoch(
 	latticeID v1.LatticeID,
 	nodePool *latticev1.NodePool,
 	epoch latticev1.NodePoolEpoch,
 ) error {
-	module := cp.nodePoolTerraformModule(latticeID, nodePool, epoch)
-	config := cp.nodePoolTerraformConfig(latticeID, nodePool, epoch, module)
+	module, err := cp.nodePoolTerraformModule(latticeID, nodePool, epoch)
+	if err != nil {
+		return err
+	}
 
+	config := cp.nodePoolTerraformConfig(latticeID, nodePool, epoch, module)
 	result, _, err := terraform.Plan(nodePoolWorkDirectory(nodePool.ID(epoch)), config, false)
 	if err != nil {
 		return fmt.Errorf(
@@ -172,7 +349,11 @@ func (cp *DefaultAWSCloudProvider) nodePoolEpochInfo(
 		terraformOutputNodePoolSecurityGroupID,
 	}
 
-	module := cp.nodePoolTerraformModule(latticeID, nodePool, epoch)
+	module, err := cp.nodePoolTerraformModule(latticeID, nodePool, epoch)
+	if err != nil {
+		return nodePoolInfo{}, err
+	}
+
 	config := cp.nodePoolTerraformConfig(latticeID, nodePool, epoch, module)
 	values, err := terraform.Output(nodePoolWorkDirectory(nodePool.ID(epoch)), config, outputVars)
 	if err != nil {
@@ -241,10 +422,44 @@ func (cp *DefaultAWSCloudProvider) nodePoolTerraformModule(
 	latticeID v1.LatticeID,
 	nodePool *latticev1.NodePool,
 	epoch latticev1.NodePoolEpoch,
-) *kubetf.NodePool {
+) (*kubetf.NodePool, error) {
 	nodePoolID := nodePool.ID(epoch)
 
-	return &kubetf.NodePool{
+	// The autoscaling group's bounds have to include its desired capacity, which
+	// may be outside of the autoscaling bounds until the node pool controller
+	// scales it back within them.
+	minInstances := nodePool.Spec.NumInstances
+	maxInstances := nodePool.Spec.NumInstances
+	if nodePool.Spec.Autoscaled() {
+		if nodePool.Spec.MinInstances < minInstances {
+			minInstances = nodePool.Spec.MinInstances
+		}
+		if nodePool.Spec.MaxInstances > maxInstances {
+			maxInstances = nodePool.Spec.MaxInstances
+		}
+	}
+
+	// Node pools without spot instances are entirely on-demand, as are node pools
+	// falling back to on-demand instances while spot capacity is unavailable.
+	onDemandBaseInstances := int32(0)
+	onDemandPercentage := int32(100)
+	if nodePool.Spec.Spot != nil {
+		onDemandBaseInstances = nodePool.Spec.Spot.OnDemandBaseInstances
+		onDemandPercentage = nodePool.Spec.Spot.OnDemandPercentage
+
+		// a malformed annotation would otherwise silently send the node pool
+		// back to spot instances while spot capacity is unavailable
+		_, fallingBack, err := nodePool.SpotFallbackAnnotation()
+		if err != nil {
+			return nil, fmt.Errorf("error getting spot fallback of %v: %v", nodePool.Description(cp.namespacePrefix), err)
+		}
+
+		if fallingBack {
+			onDemandPercentage = 100
+		}
+	}
+
+	module := &kubetf.NodePool{
 		Source: cp.terraformModulePath + kubetf.ModulePathNodePool,
 
 		AWSAccountID: cp.accountID,
@@ -259,8 +474,15 @@ func (cp *DefaultAWSCloudProvider) nodePoolTerraformModule(
 
 		Name:         nodePoolID,
 		NumInstances: nodePool.Spec.NumInstances,
+		MinInstances: minInstances,
+		MaxInstances: maxInstances,
 		InstanceType: nodePool.Spec.InstanceType,
+
+		InstanceTypes:         nodePool.Spec.AllInstanceTypes(),
+		OnDemandBaseInstances: onDemandBaseInstances,
+		OnDemandPercentage:    onDemandPercentage,
 	}
+	return module, nil
 }
 
 type nodePoolInfo struct {
@@ -273,3 +495,15 @@ type nodePoolInfo struct {
 func nodePoolWorkDirectory(nodePoolID string) string {
 	return workDirectory("node-pool", nodePoolID)
 }
+
+// nodeInstanceID returns the ID of the EC2 instance a node is running on.
+func nodeInstanceID(node *corev1.Node) (string, error) {
+	// provider IDs of EC2 instances look like aws:///us-east-1a/i-0123456789abcdef0
+	providerID := node.Spec.ProviderID
+	instanceID := providerID[strings.LastIndex(providerID, "/")+1:]
+	if !strings.HasPrefix(instanceID, "i-") {
+		return "", fmt.Errorf("could not get instance ID of node %v from provider ID %v", node.Name, providerID)
+	}
+
+	return instanceID, nil
+}
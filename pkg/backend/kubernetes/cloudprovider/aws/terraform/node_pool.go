@@ -22,7 +22,16 @@ type NodePool struct {
 
 	Name         string
 	NumInstances int32
+	MinInstances int32
+	MaxInstances int32
 	InstanceType string
+
+	// InstanceTypes are all of the instance types the node pool's instances may be
+	// launched as. OnDemandBaseInstances and OnDemandPercentage determine how many
+	// of the instances are on-demand rather than spot instances.
+	InstanceTypes         []string
+	OnDemandBaseInstances int32
+	OnDemandPercentage    int32
 }
 
 func (np *NodePool) MarshalJSON() ([]byte, error) {
@@ -41,7 +50,13 @@ func (np *NodePool) MarshalJSON() ([]byte, error) {
 
 		Name:         np.Name,
 		NumInstances: np.NumInstances,
+		MinInstances: np.MinInstances,
+		MaxInstances: np.MaxInstances,
 		InstanceType: np.InstanceType,
+
+		InstanceTypes:         strings.Join(np.InstanceTypes, ","),
+		OnDemandBaseInstances: np.OnDemandBaseInstances,
+		OnDemandPercentage:    np.OnDemandPercentage,
 	}
 	return json.Marshal(&encoder)
 }
@@ -61,5 +76,11 @@ type nodePoolEncoder struct {
 
 	Name         string `json:"name"`
 	NumInstances int32  `json:"num_instances"`
+	MinInstances int32  `json:"min_instances"`
+	MaxInstances int32  `json:"max_instances"`
 	InstanceType string `json:"instance_type"`
+
+	InstanceTypes         string `json:"instance_types"`
+	OnDemandBaseInstances int32  `json:"on_demand_base_instances"`
+	OnDemandPercentage    int32  `json:"on_demand_percentage"`
 }
@@ -33,6 +33,10 @@ type Interface interface {
 
 	ComponentBuildWorkDirectoryVolumeSource(jobName string) corev1.VolumeSource
 
+	// ComponentBuildCacheVolumeSource returns the volume that persists between
+	// builds on a build node, used to cache git repositories.
+	ComponentBuildCacheVolumeSource() corev1.VolumeSource
+
 	// TransformServicePodTemplateSpec takes in the DeploymentSpec generated for a Service, and applies any cloud provider
 	// related transforms necessary to a copy of the DeploymentSpec, and returns it.
 	TransformPodTemplateSpec(*corev1.PodTemplateSpec) *corev1.PodTemplateSpec
@@ -86,6 +90,17 @@ type NodePool interface {
 		epochSpec *latticev1.NodePoolSpec,
 	) (*latticev1.NodePoolStatusEpochStatus, error)
 	NodePoolAddAnnotations(v1.LatticeID, *latticev1.NodePool, map[string]string, latticev1.NodePoolEpoch) error
+
+	// NodePoolEpochNodes returns the nodes that are currently running in the node pool's epoch.
+	NodePoolEpochNodes(v1.LatticeID, *latticev1.NodePool, latticev1.NodePoolEpoch) ([]corev1.Node, error)
+
+	// RemoveNodePoolEpochNode removes a node that has been drained from the node pool's epoch,
+	// decreasing the number of instances in the epoch by one.
+	RemoveNodePoolEpochNode(v1.LatticeID, *latticev1.NodePool, latticev1.NodePoolEpoch, *corev1.Node) error
+
+	// NodePoolEpochSpotStatus returns the status of the spot instances in the node pool's epoch.
+	// It is only called for node pools that have spot instances.
+	NodePoolEpochSpotStatus(v1.LatticeID, *latticev1.NodePool, latticev1.NodePoolEpoch) (*latticev1.NodePoolSpotStatus, error)
 }
 
 type Options struct {
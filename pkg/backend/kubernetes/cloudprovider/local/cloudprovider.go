@@ -2,6 +2,7 @@ package local
 
 import (
 	"fmt"
+	"sync"
 
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/lifecycle/system/bootstrap/bootstrapper"
@@ -21,6 +22,7 @@ import (
 )
 
 const (
+	buildCacheVolumeHostPath          = "/data/component-builder-cache"
 	workDirectoryVolumeHostPathPrefix = "/data/component-builder"
 
 	dockerImageDNSController = "kubernetes-local-dns-controller"
@@ -54,6 +56,8 @@ func NewCloudProvider(
 		kubeClient:        kubeClient,
 		kubeNodeLister:    kubeInformerFactory.Core().V1().Nodes().Lister(),
 		kubeServiceLister: kubeInformerFactory.Core().V1().Services().Lister(),
+
+		simulatedNodes: make(map[string][]corev1.Node),
 	}
 
 	// shared informer factories only start informers which have been referenced
@@ -92,6 +96,11 @@ type DefaultLocalCloudProvider struct {
 	kubeClient        kubeclientset.Interface
 	kubeNodeLister    corelisters.NodeLister
 	kubeServiceLister corelisters.ServiceLister
+
+	// simulatedNodes maps the IDs of node pool epochs to their simulated nodes
+	simulatedNodesLock sync.Mutex
+	simulatedNodes     map[string][]corev1.Node
+	simulatedNodeCount int
 }
 
 func (cp *DefaultLocalCloudProvider) BootstrapSystemResources(resources *bootstrapper.SystemResources) {
@@ -112,6 +121,14 @@ func (cp *DefaultLocalCloudProvider) ComponentBuildWorkDirectoryVolumeSource(job
 	}
 }
 
+func (cp *DefaultLocalCloudProvider) ComponentBuildCacheVolumeSource() corev1.VolumeSource {
+	return corev1.VolumeSource{
+		HostPath: &corev1.HostPathVolumeSource{
+			Path: buildCacheVolumeHostPath,
+		},
+	}
+}
+
 func (cp *DefaultLocalCloudProvider) TransformPodTemplateSpec(spec *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
 	spec = removePodTemplateSpecAffinity(spec)
 
@@ -47,6 +47,7 @@ type Controller struct {
 	serviceMesh              servicemesh.Interface
 
 	latticeClient latticeclientset.Interface
+	kubeClient    kubeclientset.Interface
 
 	configLister       latticelisters.ConfigLister
 	configListerSynced cache.InformerSynced
@@ -93,6 +94,7 @@ func NewController(
 		staticServiceMeshOptions: serviceMeshOptions,
 
 		latticeClient: latticeClient,
+		kubeClient:    kubeClient,
 
 		configSetChan: make(chan struct{}),
 	}
@@ -189,7 +191,7 @@ func (c *Controller) newServiceMesh() error {
 		return err
 	}
 
-	serviceMesh, err := servicemesh.NewServiceMesh(options)
+	serviceMesh, err := servicemesh.NewServiceMesh(c.kubeClient, options)
 	if err != nil {
 		return err
 	}
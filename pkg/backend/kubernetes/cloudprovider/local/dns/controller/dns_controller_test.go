@@ -301,7 +301,7 @@ func TestAddressCreation(t *testing.T) {
 			},
 		}
 
-		serviceMesh, err := servicemesh.NewServiceMesh(serviceMeshOptions)
+		serviceMesh, err := servicemesh.NewServiceMesh(kubeClient, serviceMeshOptions)
 		if err != nil {
 			panic(err.Error())
 		}
@@ -5,6 +5,10 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func (cp *DefaultLocalCloudProvider) NodePoolNeedsNewEpoch(nodePool *latticev1.NodePool) (bool, error) {
@@ -39,11 +43,36 @@ func (cp *DefaultLocalCloudProvider) NodePoolAddAnnotations(
 	return nil
 }
 
+// Workloads all run on the local node, so the local cloud provider simulates
+// the nodes of node pools so that node pools, including autoscaled node pools,
+// behave as they would on a cloud provider.
+// Simulated nodes only exist in memory and are not registered with kubernetes.
+
 func (cp *DefaultLocalCloudProvider) EnsureNodePoolEpoch(
 	latticeID v1.LatticeID,
 	nodePool *latticev1.NodePool,
 	epoch latticev1.NodePoolEpoch,
 ) error {
+	allocatable, err := cp.localNodeAllocatable()
+	if err != nil {
+		return err
+	}
+
+	cp.simulatedNodesLock.Lock()
+	defer cp.simulatedNodesLock.Unlock()
+
+	id := nodePool.ID(epoch)
+	nodes := cp.simulatedNodes[id]
+	for int32(len(nodes)) < nodePool.Spec.NumInstances {
+		cp.simulatedNodeCount++
+		nodes = append(nodes, simulatedNode(nodePool, epoch, cp.simulatedNodeCount, allocatable))
+	}
+
+	if int32(len(nodes)) > nodePool.Spec.NumInstances {
+		nodes = nodes[:nodePool.Spec.NumInstances]
+	}
+
+	cp.simulatedNodes[id] = nodes
 	return nil
 }
 
@@ -52,6 +81,10 @@ func (cp *DefaultLocalCloudProvider) DestroyNodePoolEpoch(
 	nodePool *latticev1.NodePool,
 	epoch latticev1.NodePoolEpoch,
 ) error {
+	cp.simulatedNodesLock.Lock()
+	defer cp.simulatedNodesLock.Unlock()
+
+	delete(cp.simulatedNodes, nodePool.ID(epoch))
 	return nil
 }
 
@@ -61,10 +94,117 @@ func (cp *DefaultLocalCloudProvider) NodePoolEpochStatus(
 	epoch latticev1.NodePoolEpoch,
 	epochSpec *latticev1.NodePoolSpec,
 ) (*latticev1.NodePoolStatusEpochStatus, error) {
+	nodes, err := cp.NodePoolEpochNodes(latticeID, nodePool, epoch)
+	if err != nil {
+		return nil, err
+	}
+
 	status := &latticev1.NodePoolStatusEpochStatus{
-		NumInstances: epochSpec.NumInstances,
+		NumInstances: int32(len(nodes)),
 		InstanceType: epochSpec.InstanceType,
-		State:        latticev1.NodePoolStateStable,
+		State:        latticev1.NodePoolStateScaling,
 	}
+
+	if status.NumInstances == epochSpec.NumInstances {
+		status.State = latticev1.NodePoolStateStable
+	}
+
 	return status, nil
 }
+
+func (cp *DefaultLocalCloudProvider) NodePoolEpochNodes(
+	latticeID v1.LatticeID,
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+) ([]corev1.Node, error) {
+	cp.simulatedNodesLock.Lock()
+	defer cp.simulatedNodesLock.Unlock()
+
+	var nodes []corev1.Node
+	for _, node := range cp.simulatedNodes[nodePool.ID(epoch)] {
+		nodes = append(nodes, *node.DeepCopy())
+	}
+
+	return nodes, nil
+}
+
+func (cp *DefaultLocalCloudProvider) RemoveNodePoolEpochNode(
+	latticeID v1.LatticeID,
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+	node *corev1.Node,
+) error {
+	cp.simulatedNodesLock.Lock()
+	defer cp.simulatedNodesLock.Unlock()
+
+	id := nodePool.ID(epoch)
+	nodes := cp.simulatedNodes[id]
+	for i, n := range nodes {
+		if n.Name == node.Name {
+			cp.simulatedNodes[id] = append(nodes[:i], nodes[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%v epoch %v does not have node %v", nodePool.Description(cp.namespacePrefix), epoch, node.Name)
+}
+
+func (cp *DefaultLocalCloudProvider) NodePoolEpochSpotStatus(
+	latticeID v1.LatticeID,
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+) (*latticev1.NodePoolSpotStatus, error) {
+	// Simulated nodes are never interrupted, so spot capacity is always available.
+	return &latticev1.NodePoolSpotStatus{}, nil
+}
+
+// localNodeAllocatable returns the resources of the local node, which
+// simulated nodes are given.
+func (cp *DefaultLocalCloudProvider) localNodeAllocatable() (corev1.ResourceList, error) {
+	nodes, err := cp.kubeNodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("could not find local node")
+	}
+
+	return nodes[0].Status.Allocatable, nil
+}
+
+func simulatedNode(
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+	index int,
+	allocatable corev1.ResourceList,
+) corev1.Node {
+	toleration := nodePool.Toleration(epoch)
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("simulated-%v", index),
+			Labels: map[string]string{
+				latticev1.NodePoolIDLabelKey: nodePool.ID(epoch),
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{
+					Key:    toleration.Key,
+					Value:  toleration.Value,
+					Effect: toleration.Effect,
+				},
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity:    allocatable,
+			Allocatable: allocatable,
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+}
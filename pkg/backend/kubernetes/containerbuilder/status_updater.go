@@ -5,15 +5,20 @@ import (
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/util/sbom"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"encoding/json"
+	kubeclientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 type KubernetesStatusUpdater struct {
+	KubeClient      kubeclientset.Interface
 	LatticeClient   latticeclientset.Interface
 	NamespacePrefix string
 }
@@ -30,12 +35,18 @@ func NewKubernetesStatusUpdater(namespacePrefix string, kubeconfig string) (*Kub
 		return nil, err
 	}
 
+	kubeClient, err := kubeclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	latticeClient, err := latticeclientset.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
 	kb := &KubernetesStatusUpdater{
+		KubeClient:      kubeClient,
 		LatticeClient:   latticeClient,
 		NamespacePrefix: namespacePrefix,
 	}
@@ -110,3 +121,120 @@ func (u *KubernetesStatusUpdater) updateErrorInternal(buildID v1.ContainerBuildI
 	}
 	return nil
 }
+
+func (u *KubernetesStatusUpdater) UpdateCache(buildID v1.ContainerBuildID, systemID v1.SystemID, cache *v1.ContainerBuildCacheInfo) error {
+	// Retry once since we may lose a race against the controller at the beginning updating the Status.State
+	return u.updateCacheInternal(buildID, systemID, cache, 1)
+}
+
+func (u *KubernetesStatusUpdater) updateCacheInternal(buildID v1.ContainerBuildID, systemID v1.SystemID, cache *v1.ContainerBuildCacheInfo, numRetries int) error {
+	namespace := kubeutil.SystemNamespace(u.NamespacePrefix, systemID)
+	build, err := u.LatticeClient.LatticeV1().ContainerBuilds(namespace).Get(string(buildID), metav1.GetOptions{})
+	if err != nil {
+		if numRetries <= 0 {
+			return err
+		}
+		return u.updateCacheInternal(buildID, systemID, cache, numRetries-1)
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if build.Annotations == nil {
+		build.Annotations = make(map[string]string)
+	}
+	build.Annotations[latticev1.ContainerBuildCacheInfoAnnotationKey] = string(data)
+
+	_, err = u.LatticeClient.LatticeV1().ContainerBuilds(build.Namespace).Update(build)
+	if err != nil {
+		if numRetries <= 0 {
+			return err
+		}
+		return u.updateCacheInternal(buildID, systemID, cache, numRetries-1)
+	}
+	return nil
+}
+
+func (u *KubernetesStatusUpdater) UpdateProvenance(buildID v1.ContainerBuildID, systemID v1.SystemID, provenance *v1.ContainerBuildProvenance) error {
+	// Retry once since we may lose a race against the controller at the beginning updating the Status.State
+	return u.updateProvenanceInternal(buildID, systemID, provenance, 1)
+}
+
+func (u *KubernetesStatusUpdater) updateProvenanceInternal(buildID v1.ContainerBuildID, systemID v1.SystemID, provenance *v1.ContainerBuildProvenance, numRetries int) error {
+	namespace := kubeutil.SystemNamespace(u.NamespacePrefix, systemID)
+	build, err := u.LatticeClient.LatticeV1().ContainerBuilds(namespace).Get(string(buildID), metav1.GetOptions{})
+	if err != nil {
+		if numRetries <= 0 {
+			return err
+		}
+		return u.updateProvenanceInternal(buildID, systemID, provenance, numRetries-1)
+	}
+
+	stored, err := u.storeSBOM(build, provenance)
+	if err != nil {
+		if numRetries <= 0 {
+			return err
+		}
+		return u.updateProvenanceInternal(buildID, systemID, provenance, numRetries-1)
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	if build.Annotations == nil {
+		build.Annotations = make(map[string]string)
+	}
+	build.Annotations[latticev1.ContainerBuildProvenanceAnnotationKey] = string(data)
+
+	_, err = u.LatticeClient.LatticeV1().ContainerBuilds(build.Namespace).Update(build)
+	if err != nil {
+		if numRetries <= 0 {
+			return err
+		}
+		return u.updateProvenanceInternal(buildID, systemID, provenance, numRetries-1)
+	}
+	return nil
+}
+
+// storeSBOM stores the provenance's SBOM, which is too large for the container
+// build's annotations, in a ConfigMap owned by the container build. It returns
+// the provenance with the SBOM replaced by its digest.
+func (u *KubernetesStatusUpdater) storeSBOM(build *latticev1.ContainerBuild, provenance *v1.ContainerBuildProvenance) (*v1.ContainerBuildProvenance, error) {
+	if provenance.SBOM == nil {
+		return provenance, nil
+	}
+
+	data, digest, err := sbom.Encode(provenance.SBOM)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            latticev1.ContainerBuildSBOMConfigMapName(v1.ContainerBuildID(build.Name)),
+			Namespace:       build.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(build, latticev1.ContainerBuildKind)},
+		},
+		Data: map[string]string{
+			latticev1.ContainerBuildSBOMConfigMapKey: string(data),
+		},
+	}
+
+	_, err = u.KubeClient.CoreV1().ConfigMaps(configMap.Namespace).Create(configMap)
+	if errors.IsAlreadyExists(err) {
+		// the build was retried, so replace the SBOM of the earlier attempt
+		_, err = u.KubeClient.CoreV1().ConfigMaps(configMap.Namespace).Update(configMap)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := provenance.DeepCopy()
+	result.SBOM = nil
+	result.SBOMDigest = digest
+	return result, nil
+}
@@ -11,6 +11,7 @@ import (
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh"
 	netutil "github.com/mlab-lattice/lattice/pkg/util/net"
 
@@ -38,6 +39,12 @@ type Controller struct {
 	staticCloudProviderOptions *cloudprovider.Options
 	cloudProvider              cloudprovider.Interface
 
+	// NOTE: you must get a read lock on the configLock for the duration
+	//       of your use of the dnsProvider
+	//       if no dns provider was configured, dnsProvider is the cloudProvider
+	staticDNSProviderOptions *dnsprovider.Options
+	dnsProvider              dnsprovider.Interface
+
 	// NOTE: you must get a read lock on the configLock for the duration
 	//       of your use of the serviceMesh
 	staticServiceMeshOptions *servicemesh.Options
@@ -71,6 +78,7 @@ func NewController(
 	namespacePrefix string,
 	latticeID v1.LatticeID,
 	cloudProviderOptions *cloudprovider.Options,
+	dnsProviderOptions *dnsprovider.Options,
 	serviceMeshOptions *servicemesh.Options,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
@@ -82,6 +90,7 @@ func NewController(
 		latticeID:       latticeID,
 
 		staticCloudProviderOptions: cloudProviderOptions,
+		staticDNSProviderOptions:   dnsProviderOptions,
 		staticServiceMeshOptions:   serviceMeshOptions,
 
 		latticeClient: latticeClient,
@@ -170,6 +179,8 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 		go wait.Until(c.runWorker, time.Second, stopCh)
 	}
 
+	go wait.Until(c.reconcileOrphanedDNSRecords, orphanedDNSRecordReconciliationPeriod, stopCh)
+
 	// wait until we're told to stop
 	<-stopCh
 }
@@ -36,7 +36,7 @@ func (c *Controller) syncDeletedAddress(address *latticev1.Address) error {
 	}
 
 	domain := kubeutil.InternalAddressSubdomain(path.ToDomain(), systemID, c.latticeID)
-	err = c.cloudProvider.DestroyDNSRecord(c.latticeID, domain)
+	err = c.dnsProvider.DestroyDNSRecord(c.latticeID, domain)
 	if err != nil {
 		state := latticev1.AddressStateFailed
 		failureInfo := &latticev1.AddressStatusFailureInfo{
@@ -40,7 +40,7 @@ func (c *Controller) syncExternalNameAddress(address *latticev1.Address) error {
 	}
 
 	domain := kubeutil.InternalAddressSubdomain(path.ToDomain(), systemID, c.latticeID)
-	err = c.cloudProvider.EnsureDNSCNAMERecord(c.latticeID, domain, *address.Spec.ExternalName)
+	err = c.dnsProvider.EnsureDNSCNAMERecord(c.latticeID, domain, *address.Spec.ExternalName)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cloudprovider"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh"
 
 	"k8s.io/apimachinery/pkg/labels"
@@ -49,6 +50,13 @@ func (c *Controller) handleConfigEvent(config *latticev1.Config, verb string) er
 		return err
 	}
 
+	err = c.newDNSProvider()
+	if err != nil {
+		glog.Errorf("error creating dns provider: %v", err)
+		// FIXME: what to do here?
+		return err
+	}
+
 	err = c.newServiceMesh()
 	if err != nil {
 		glog.Errorf("error creating service mesh: %v", err)
@@ -80,13 +88,28 @@ func (c *Controller) newCloudProvider() error {
 	return nil
 }
 
+func (c *Controller) newDNSProvider() error {
+	dnsProvider, err := dnsprovider.NewDNSProvider(c.staticDNSProviderOptions)
+	if err != nil {
+		return err
+	}
+
+	// fall back to the cloud provider's dns if no dns provider was configured
+	if dnsProvider == nil {
+		dnsProvider = c.cloudProvider
+	}
+
+	c.dnsProvider = dnsProvider
+	return nil
+}
+
 func (c *Controller) newServiceMesh() error {
 	options, err := servicemesh.OverlayConfigOptions(c.staticServiceMeshOptions, &c.config.ServiceMesh)
 	if err != nil {
 		return err
 	}
 
-	serviceMesh, err := servicemesh.NewServiceMesh(options)
+	serviceMesh, err := servicemesh.NewServiceMesh(c.kubeClient, options)
 	if err != nil {
 		return err
 	}
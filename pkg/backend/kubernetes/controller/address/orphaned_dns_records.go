@@ -0,0 +1,72 @@
+package address
+
+import (
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/golang/glog"
+)
+
+const (
+	orphanedDNSRecordReconciliationPeriod = 5 * time.Minute
+)
+
+// reconcileOrphanedDNSRecords removes DNS records owned by the lattice that no longer
+// belong to an address, for example if an address was removed while its record was
+// being created. Only DNS providers that track ownership of their records support this.
+func (c *Controller) reconcileOrphanedDNSRecords() {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+
+	lister, ok := c.dnsProvider.(dnsprovider.OwnedRecordLister)
+	if !ok {
+		return
+	}
+
+	owned, err := lister.OwnedDNSRecords(c.latticeID)
+	if err != nil {
+		glog.Warningf("error listing owned DNS records: %v", err)
+		return
+	}
+
+	addresses, err := c.addressLister.List(labels.Everything())
+	if err != nil {
+		glog.Warningf("error listing addresses: %v", err)
+		return
+	}
+
+	expected := make(map[string]struct{})
+	for _, address := range addresses {
+		path, err := address.PathLabel()
+		if err != nil {
+			// bail out rather than risk removing the address's record
+			glog.Warningf("error getting path label for %v: %v", address.Description(c.namespacePrefix), err)
+			return
+		}
+
+		systemID, err := kubeutil.SystemID(c.namespacePrefix, address.Namespace)
+		if err != nil {
+			glog.Warningf("error getting system id for %v: %v", address.Description(c.namespacePrefix), err)
+			return
+		}
+
+		domain := kubeutil.InternalAddressSubdomain(path.ToDomain(), systemID, c.latticeID)
+		expected[domain] = struct{}{}
+	}
+
+	for _, name := range owned {
+		if _, ok := expected[name]; ok {
+			continue
+		}
+
+		glog.V(2).Infof("removing orphaned DNS record %v", name)
+		err := c.dnsProvider.DestroyDNSRecord(c.latticeID, name)
+		if err != nil {
+			glog.Warningf("error removing orphaned DNS record %v: %v", name, err)
+		}
+	}
+}
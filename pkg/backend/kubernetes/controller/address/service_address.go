@@ -93,7 +93,7 @@ func (c *Controller) syncServiceAddress(address *latticev1.Address) error {
 	}
 
 	domain := kubeutil.InternalAddressSubdomain(path.ToDomain(), systemID, c.latticeID)
-	needsUpdate, err := c.cloudProvider.DNSARecordNeedsUpdate(c.latticeID, domain, ip)
+	needsUpdate, err := c.dnsProvider.DNSARecordNeedsUpdate(c.latticeID, domain, ip)
 	if err != nil {
 		return fmt.Errorf(
 			"error checking if DNS A record(s) for %v needs update: %v",
@@ -115,7 +115,7 @@ func (c *Controller) syncServiceAddress(address *latticev1.Address) error {
 			return err
 		}
 
-		err = c.cloudProvider.EnsureDNSARecord(c.latticeID, domain, ip)
+		err = c.dnsProvider.EnsureDNSARecord(c.latticeID, domain, ip)
 		if err != nil {
 			state := latticev1.AddressStateFailed
 			failureInfo := &latticev1.AddressStatusFailureInfo{
@@ -9,6 +9,8 @@ import (
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions/lattice/v1"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -30,6 +32,12 @@ type Controller struct {
 
 	componentResolver resolver.Interface
 
+	secretProviders *secretprovider.Providers
+
+	// gitResolver is used to find the files that changed between
+	// commits of a system's definition repository
+	gitResolver *git.Resolver
+
 	systemLister       latticelisters.SystemLister
 	systemListerSynced cache.InformerSynced
 
@@ -46,6 +54,8 @@ func NewController(
 	namespacePrefix string,
 	latticeClient latticeclientset.Interface,
 	componentResolver resolver.Interface,
+	secretProviders *secretprovider.Providers,
+	gitResolver *git.Resolver,
 	systemInformer latticeinformers.SystemInformer,
 	buildInformer latticeinformers.BuildInformer,
 	containerBuildInformer latticeinformers.ContainerBuildInformer,
@@ -57,6 +67,10 @@ func NewController(
 
 		componentResolver: componentResolver,
 
+		secretProviders: secretProviders,
+
+		gitResolver: gitResolver,
+
 		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "build"),
 	}
 
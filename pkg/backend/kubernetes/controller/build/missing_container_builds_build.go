@@ -15,8 +15,16 @@ import (
 func (c *Controller) syncMissingContainerBuildsBuild(build *latticev1.Build, stateInfo stateInfo) error {
 	containerBuildStatuses := stateInfo.containerBuildStatuses
 	containerBuildHashes := make(map[string]*latticev1.ContainerBuild)
+	containerBuildReuse := make(map[string]*v1.ContainerBuildReuse)
 	workloads := make(map[tree.Path]latticev1.BuildStatusWorkload)
 
+	// container builds that share the definition's repository are
+	// compared against the previous successful build
+	previous, err := c.previousSuccessfulBuild(build)
+	if err != nil {
+		return err
+	}
+
 	// look through all the containers of each workload to see if there are any containers that
 	// don't have builds yet
 	for path, workload := range stateInfo.workloadsNeedNewContainerBuilds {
@@ -29,24 +37,42 @@ func (c *Controller) syncMissingContainerBuildsBuild(build *latticev1.Build, sta
 		}
 
 		// maps the workload's container names to the container builds for them
+		// and the reason they were reused or run
 		containerBuilds := make(map[string]v1.ContainerBuildID)
-		err := c.getContainerBuilds(build, path, containers, containerBuilds, containerBuildHashes, containerBuildStatuses)
+		reuse := make(map[string]*v1.ContainerBuildReuse)
+		err := c.getContainerBuilds(
+			build,
+			previous,
+			path,
+			containers,
+			containerBuilds,
+			reuse,
+			containerBuildHashes,
+			containerBuildReuse,
+			containerBuildStatuses,
+		)
 		if err != nil {
 			return err
 		}
 
 		workloadInfo := latticev1.BuildStatusWorkload{
-			MainContainer: containerBuilds[kubeutil.UserMainContainerName],
-			Sidecars:      make(map[string]v1.ContainerBuildID),
+			MainContainer:      containerBuilds[kubeutil.UserMainContainerName],
+			Sidecars:           make(map[string]v1.ContainerBuildID),
+			MainContainerReuse: reuse[kubeutil.UserMainContainerName],
+			SidecarReuse:       make(map[string]v1.ContainerBuildReuse),
 		}
 		for sidecar := range workload.Containers().Sidecars {
-			workloadInfo.Sidecars[sidecar] = containerBuilds[kubeutil.UserSidecarContainerName(sidecar)]
+			name := kubeutil.UserSidecarContainerName(sidecar)
+			workloadInfo.Sidecars[sidecar] = containerBuilds[name]
+			if r, ok := reuse[name]; ok {
+				workloadInfo.SidecarReuse[sidecar] = *r
+			}
 		}
 
 		workloads[path] = workloadInfo
 	}
 
-	_, err := c.updateBuildStatus(
+	_, err = c.updateBuildStatus(
 		build,
 		latticev1.BuildStateRunning,
 		"",
@@ -64,12 +90,16 @@ func (c *Controller) syncMissingContainerBuildsBuild(build *latticev1.Build, sta
 
 func (c *Controller) getContainerBuilds(
 	build *latticev1.Build,
+	previous *latticev1.Build,
 	path tree.Path,
 	containers map[string]definitionv1.Container,
 	containerBuilds map[string]v1.ContainerBuildID,
+	reuse map[string]*v1.ContainerBuildReuse,
 	containerBuildHashes map[string]*latticev1.ContainerBuild,
+	containerBuildReuse map[string]*v1.ContainerBuildReuse,
 	containerBuildStatuses map[v1.ContainerBuildID]latticev1.ContainerBuildStatus,
 ) error {
+	previousContainerBuilds := previousContainerBuilds(previous, path)
 	for containerName, container := range containers {
 		buildDefinition, err := c.hydrateContainerBuild(build, path, container.Build)
 		if err != nil {
@@ -83,9 +113,33 @@ func (c *Controller) getContainerBuilds(
 
 		// check if we've already observed a container build with this hash
 		containerBuild, ok := containerBuildHashes[definitionHash]
-		if !ok {
-			// if not, see if an active or succeeded container build already exists with this hash
-			containerBuild, err = c.findContainerBuildForDefinitionHash(build.Namespace, definitionHash)
+		if ok {
+			id := v1.ContainerBuildID(containerBuild.Name)
+			containerBuilds[containerName] = id
+			reuse[containerName] = containerBuildReuse[definitionHash]
+			continue
+		}
+
+		// if not, see if an active or succeeded container build already exists with this hash
+		containerBuild, err = c.findContainerBuildForDefinitionHash(build.Namespace, definitionHash)
+		if err != nil {
+			return err
+		}
+
+		r := &v1.ContainerBuildReuse{
+			Reused: true,
+			Reason: "an existing container build has an identical build definition",
+		}
+
+		// if not, see if the container build from the previous build can be reused
+		if containerBuild == nil {
+			containerBuild, r, err = c.findUnchangedContainerBuild(
+				build,
+				previous,
+				path,
+				previousContainerBuilds[containerName],
+				buildDefinition,
+			)
 			if err != nil {
 				return err
 			}
@@ -101,7 +155,9 @@ func (c *Controller) getContainerBuilds(
 			id := v1.ContainerBuildID(containerBuild.Name)
 			containerBuildStatuses[id] = containerBuild.Status
 			containerBuildHashes[definitionHash] = containerBuild
+			containerBuildReuse[definitionHash] = r
 			containerBuilds[containerName] = id
+			reuse[containerName] = r
 			continue
 		}
 
@@ -111,10 +167,19 @@ func (c *Controller) getContainerBuilds(
 			return err
 		}
 
+		if r == nil {
+			r = &v1.ContainerBuildReuse{
+				Reused: false,
+				Reason: "no existing container build has an identical build definition",
+			}
+		}
+
 		id := v1.ContainerBuildID(containerBuild.Name)
 		containerBuildStatuses[id] = containerBuild.Status
 		containerBuildHashes[definitionHash] = containerBuild
+		containerBuildReuse[definitionHash] = r
 		containerBuilds[containerName] = id
+		reuse[containerName] = r
 	}
 
 	return nil
@@ -171,7 +236,8 @@ func (c *Controller) hydrateCommandBuild(
 
 	if i.SSHKeySecret != nil {
 		b.CommandBuild.Source.GitRepository.SSHKey = &definitionv1.SecretRef{
-			Value: *i.SSHKeySecret,
+			Value:    *i.SSHKeySecret,
+			Provider: i.SSHKeySecretProvider,
 		}
 	}
 
@@ -222,7 +288,8 @@ func (c *Controller) hydrateDockerBuild(
 	var sshKey *definitionv1.SecretRef
 	if i.SSHKeySecret != nil {
 		sshKey = &definitionv1.SecretRef{
-			Value: *i.SSHKeySecret,
+			Value:    *i.SSHKeySecret,
+			Provider: i.SSHKeySecretProvider,
 		}
 	}
 
@@ -3,12 +3,15 @@ package build
 import (
 	"fmt"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/buildsource"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeimagepolicy "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/imagepolicy"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	"github.com/mlab-lattice/lattice/pkg/definition"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/imagepolicy"
 	"github.com/mlab-lattice/lattice/pkg/util/git"
 	reflectutil "github.com/mlab-lattice/lattice/pkg/util/reflect"
 
@@ -24,7 +27,7 @@ func (c *Controller) syncPendingBuild(build *latticev1.Build) error {
 			_, err := c.updateBuildStatus(
 				build,
 				latticev1.BuildStateFailed,
-				"either path or version must be set",
+				"either path, version, source or commit must be set",
 				nil,
 				nil,
 				nil,
@@ -40,7 +43,7 @@ func (c *Controller) syncPendingBuild(build *latticev1.Build) error {
 			_, err := c.updateBuildStatus(
 				build,
 				latticev1.BuildStateFailed,
-				"only path or version can be set",
+				"only one of path, version, source or commit can be set",
 				nil,
 				nil,
 				nil,
@@ -81,6 +84,28 @@ func (c *Controller) syncPendingBuild(build *latticev1.Build) error {
 		return err
 	}
 
+	reason, err := c.verifyQuota(system, build)
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		_, err := c.updateBuildStatus(
+			build,
+			latticev1.BuildStateFailed,
+			reason,
+			nil,
+			nil,
+			nil,
+			nil,
+			&now,
+			&now,
+			nil,
+			nil,
+		)
+		return err
+	}
+
 	// find and resolve the build's component
 	path, cmpnt, ctx, version, err := c.getBuildComponent(system, build)
 	if err != nil {
@@ -144,6 +169,37 @@ func (c *Controller) syncPendingBuild(build *latticev1.Build) error {
 		}
 	}
 
+	// ensure that the images the build will pull satisfy the system's image policy, and
+	// pin them to the verified digests so that the container builds pull the images that
+	// were verified even if their tags are moved
+	verifier, err := kubeimagepolicy.SystemVerifier(system, c.secretProviders)
+	if err != nil {
+		return err
+	}
+
+	if verifier != nil {
+		if err := verifier.PinDefinition(t); err != nil {
+			if _, ok := err.(*imagepolicy.ViolationError); !ok {
+				return err
+			}
+
+			_, err := c.updateBuildStatus(
+				build,
+				latticev1.BuildStateFailed,
+				err.Error(),
+				nil,
+				t,
+				&path,
+				&version,
+				&now,
+				&now,
+				nil,
+				nil,
+			)
+			return err
+		}
+	}
+
 	_, err = c.updateBuildStatus(
 		build,
 		latticev1.BuildStateAccepted,
@@ -169,6 +225,27 @@ func (c *Controller) getBuildComponent(
 	v1.Version,
 	error,
 ) {
+	// if the build is a build of a local snapshot, import the snapshot into a
+	// repository and return a reference pointing at its commit
+	if build.Spec.Source != nil {
+		return c.getBuildSourceComponent(build)
+	}
+
+	// if the build is a commit build, return a reference pointing
+	// at the commit on the system's definition repo
+	if build.Spec.Commit != nil {
+		ref := &definitionv1.Reference{
+			GitRepository: &definitionv1.GitRepositoryReference{
+				GitRepository: &definitionv1.GitRepository{
+					URL:    system.Spec.DefinitionURL,
+					Commit: build.Spec.Commit,
+				},
+			},
+		}
+
+		return tree.RootPath(), ref, nil, v1.Version(*build.Spec.Commit), nil
+	}
+
 	// if the build is a version build, return a reference pointing
 	// at the version's tag on the system's definition repo
 	if build.Spec.Path == nil {
@@ -299,3 +376,53 @@ func (c *Controller) getBuildComponent(
 
 	return path, cmpnt, parentInfo.Commit, version, nil
 }
+
+func (c *Controller) getBuildSourceComponent(
+	build *latticev1.Build,
+) (
+	tree.Path, definition.Component,
+	*git.CommitReference,
+	v1.Version,
+	error,
+) {
+	path := tree.RootPath()
+	version := v1.BuildSourceVersion
+
+	store, err := buildsource.NewStore(c.latticeClient, c.namespacePrefix)
+	if err != nil {
+		return "", nil, nil, "", err
+	}
+
+	url := store.URL(build.Spec.Source)
+	commit, err := c.gitResolver.ImportSnapshot(url, func(directory string) error {
+		return store.Pull(url, directory)
+	})
+	if err != nil {
+		now := metav1.Now()
+		_, err := c.updateBuildStatus(
+			build,
+			latticev1.BuildStateFailed,
+			fmt.Sprintf("error retrieving build source %v: %v", build.Spec.Source.ID, err),
+			nil,
+			nil,
+			&path,
+			&version,
+			&now,
+			&now,
+			nil,
+			nil,
+		)
+		return "", nil, nil, "", err
+	}
+
+	ref := &definitionv1.Reference{
+		GitRepository: &definitionv1.GitRepositoryReference{
+			GitRepository: &definitionv1.GitRepository{
+				URL:    url,
+				Commit: &commit,
+			},
+		},
+	}
+
+	return path, ref, nil, version, nil
+}
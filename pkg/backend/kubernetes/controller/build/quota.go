@@ -0,0 +1,65 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// verifyQuota checks the build against the builds limit of the system's quota,
+// if it has one. The API checks the limit before creating a build, but builds
+// created concurrently can all pass that check, so only the builds created
+// before this one count against it here. If the build exceeds the quota,
+// verifyQuota returns the reason it has to fail.
+func (c *Controller) verifyQuota(system *latticev1.System, build *latticev1.Build) (string, error) {
+	systemQuota, err := system.QuotaAnnotation()
+	if err != nil {
+		return "", err
+	}
+
+	if systemQuota == nil {
+		return "", nil
+	}
+
+	builds, err := c.buildLister.Builds(build.Namespace).List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+
+	usage := v1.SystemUsage{Builds: 1}
+	for _, other := range builds {
+		switch other.Status.State {
+		case latticev1.BuildStatePending, latticev1.BuildStateAccepted, latticev1.BuildStateRunning:
+		default:
+			continue
+		}
+
+		if createdBefore(other, build) {
+			usage.Builds++
+		}
+	}
+
+	if err := quota.Check(systemQuota, usage); err != nil {
+		if _, ok := err.(*quota.ExceededError); !ok {
+			return "", err
+		}
+
+		return fmt.Sprintf("build %v", err), nil
+	}
+
+	return "", nil
+}
+
+// createdBefore returns whether a was created before b, breaking ties by name
+// so that builds created at the same time are ordered consistently.
+func createdBefore(a, b *latticev1.Build) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
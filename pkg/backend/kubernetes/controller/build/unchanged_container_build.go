@@ -0,0 +1,350 @@
+package build
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	shortCommitLength = 7
+
+	// defaultDockerFileName is the name of the docker file the builder uses
+	// if the docker file's path is a directory
+	defaultDockerFileName = "Dockerfile"
+)
+
+// previousSuccessfulBuild returns the most recently completed successful build
+// of the build's system, or nil if there isn't one.
+func (c *Controller) previousSuccessfulBuild(build *latticev1.Build) (*latticev1.Build, error) {
+	builds, err := c.buildLister.Builds(build.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var previous *latticev1.Build
+	for _, b := range builds {
+		if b.Name == build.Name || b.DeletionTimestamp != nil {
+			continue
+		}
+
+		if b.Status.State != latticev1.BuildStateSucceeded || b.Status.CompletionTimestamp == nil {
+			continue
+		}
+
+		if previous == nil || previous.Status.CompletionTimestamp.Time.Before(b.Status.CompletionTimestamp.Time) {
+			previous = b
+		}
+	}
+
+	return previous, nil
+}
+
+// previousContainerBuilds returns the container builds that the previous build used for
+// the workload's containers, keyed by container name.
+func previousContainerBuilds(previous *latticev1.Build, path tree.Path) map[string]v1.ContainerBuildID {
+	if previous == nil {
+		return nil
+	}
+
+	workload, ok := previous.Status.Workloads[path]
+	if !ok {
+		return nil
+	}
+
+	containerBuilds := map[string]v1.ContainerBuildID{
+		kubeutil.UserMainContainerName: workload.MainContainer,
+	}
+	for sidecar, id := range workload.Sidecars {
+		containerBuilds[kubeutil.UserSidecarContainerName(sidecar)] = id
+	}
+
+	return containerBuilds
+}
+
+// findUnchangedContainerBuild handles container builds whose source is the repository the
+// workload's definition was resolved from. Every commit to that repository changes the
+// container build's definition, so instead the container build of the previous successful
+// build is reused if the only difference between the definitions is the commit and none
+// of the files under the build's context changed between the commits.
+// If the container build's source is not the definition repository, nil is returned
+// for both the container build and the reason.
+func (c *Controller) findUnchangedContainerBuild(
+	build *latticev1.Build,
+	previous *latticev1.Build,
+	path tree.Path,
+	previousContainerBuild v1.ContainerBuildID,
+	definition *definitionv1.ContainerBuild,
+) (*latticev1.ContainerBuild, *v1.ContainerBuildReuse, error) {
+	info, ok := build.Status.Definition.Get(path)
+	if !ok || info.Commit == nil {
+		return nil, nil, nil
+	}
+
+	contextPaths := definitionRepositoryPaths(definition, info.Commit)
+	if len(contextPaths) == 0 {
+		return nil, nil, nil
+	}
+
+	rebuild := func(format string, a ...interface{}) (*latticev1.ContainerBuild, *v1.ContainerBuildReuse, error) {
+		reuse := &v1.ContainerBuildReuse{
+			Reused: false,
+			Reason: fmt.Sprintf(format, a...),
+		}
+		return nil, reuse, nil
+	}
+
+	if previous == nil {
+		return rebuild("no previous successful build to compare with")
+	}
+
+	if previousContainerBuild == "" {
+		return rebuild("not part of the previous successful build %v", previous.Name)
+	}
+
+	previousInfo, ok := previous.Status.Definition.Get(path)
+	if !ok || previousInfo.Commit == nil || previousInfo.Commit.RepositoryURL != info.Commit.RepositoryURL {
+		return rebuild("definition repository changed since build %v", previous.Name)
+	}
+
+	previousCommit := previousInfo.Commit.Commit
+	if previousCommit == info.Commit.Commit {
+		// the definition hash already accounts for everything else that could have changed
+		return rebuild("build definition changed since build %v", previous.Name)
+	}
+
+	containerBuild, err := c.containerBuildLister.ContainerBuilds(build.Namespace).Get(string(previousContainerBuild))
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, nil, err
+		}
+
+		return rebuild("container build %v from build %v no longer exists", previousContainerBuild, previous.Name)
+	}
+
+	if containerBuild.DeletionTimestamp != nil || containerBuild.Status.State != latticev1.ContainerBuildStateSucceeded {
+		return rebuild("container build %v from build %v did not succeed", previousContainerBuild, previous.Name)
+	}
+
+	// if the definition only differs by its commit, it will have the same
+	// hash as the previous container build once it is moved to that commit
+	rebased := rebaseDefinitionRepository(definition, info.Commit, previousCommit)
+	rebasedHash, err := hashContainerBuild(rebased)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if containerBuild.Labels[latticev1.ContainerBuildDefinitionHashLabelKey] != rebasedHash {
+		return rebuild("build definition changed since build %v", previous.Name)
+	}
+
+	changed, err := c.changedFiles(build, info.Commit.RepositoryURL, previousCommit, info.Commit.Commit, definition)
+	if err != nil {
+		return rebuild("could not compare with commit %v: %v", shortCommit(previousCommit), err)
+	}
+
+	changed = filesUnder(changed, contextPaths)
+	if len(changed) != 0 {
+		return rebuild("%v changed since commit %v", describeFiles(changed), shortCommit(previousCommit))
+	}
+
+	reuse := &v1.ContainerBuildReuse{
+		Reused: true,
+		Reason: fmt.Sprintf(
+			"no files under %v changed since commit %v",
+			strings.Join(contextPaths, ", "),
+			shortCommit(previousCommit),
+		),
+	}
+	return containerBuild, reuse, nil
+}
+
+func (c *Controller) changedFiles(
+	build *latticev1.Build,
+	repositoryURL, from, to string,
+	definition *definitionv1.ContainerBuild,
+) ([]string, error) {
+	options := &git.Options{}
+	if sshKey := definitionRepositorySSHKey(definition, repositoryURL); sshKey != nil {
+		provider, err := c.secretProviders.Provider(sshKey.Provider)
+		if err != nil {
+			return nil, err
+		}
+
+		systemID, err := kubeutil.SystemID(c.namespacePrefix, build.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		secret, err := provider.Reveal(systemID, sshKey.Value, sshKey.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		options.SSHKey = []byte(secret.Value)
+	}
+
+	ctx := &git.Context{
+		RepositoryURL: repositoryURL,
+		Options:       options,
+	}
+	return c.gitResolver.ChangedFiles(ctx, from, to)
+}
+
+// definitionRepositoryPaths returns the paths within the definition repository that the
+// container build uses, or nil if it does not use the definition repository at the commit.
+// Command builds use the whole repository.
+func definitionRepositoryPaths(definition *definitionv1.ContainerBuild, commit *git.CommitReference) []string {
+	var paths []string
+	switch {
+	case definition.CommandBuild != nil:
+		source := definition.CommandBuild.Source
+		if source != nil && atCommit(source.GitRepository, commit) {
+			paths = append(paths, ".")
+		}
+
+	case definition.DockerBuild != nil:
+		dockerBuild := definition.DockerBuild
+		if dockerBuild.BuildContext != nil && dockerBuild.BuildContext.Location != nil &&
+			atCommit(dockerBuild.BuildContext.Location.GitRepository, commit) {
+			paths = append(paths, cleanRepositoryPath(dockerBuild.BuildContext.Path))
+		}
+
+		// the docker file's path may either be the file or the directory containing it
+		if dockerBuild.DockerFile != nil && dockerBuild.DockerFile.Location != nil &&
+			atCommit(dockerBuild.DockerFile.Location.GitRepository, commit) {
+			dockerFilePath := cleanRepositoryPath(dockerBuild.DockerFile.Path)
+			if dockerFilePath != "." {
+				paths = appendPath(paths, dockerFilePath)
+			}
+			paths = appendPath(paths, path.Join(dockerFilePath, defaultDockerFileName))
+		}
+	}
+
+	return paths
+}
+
+func appendPath(paths []string, p string) []string {
+	for _, existing := range paths {
+		if existing == p {
+			return paths
+		}
+	}
+	return append(paths, p)
+}
+
+// rebaseDefinitionRepository returns a copy of the container build with its
+// sources in the definition repository moved to the other commit.
+func rebaseDefinitionRepository(
+	definition *definitionv1.ContainerBuild,
+	commit *git.CommitReference,
+	other string,
+) *definitionv1.ContainerBuild {
+	rebased := definition.DeepCopy()
+	rebase := func(repository *definitionv1.GitRepository) {
+		if atCommit(repository, commit) {
+			repository.Commit = &other
+		}
+	}
+
+	switch {
+	case rebased.CommandBuild != nil:
+		if rebased.CommandBuild.Source != nil {
+			rebase(rebased.CommandBuild.Source.GitRepository)
+		}
+
+	case rebased.DockerBuild != nil:
+		if rebased.DockerBuild.BuildContext != nil && rebased.DockerBuild.BuildContext.Location != nil {
+			rebase(rebased.DockerBuild.BuildContext.Location.GitRepository)
+		}
+
+		if rebased.DockerBuild.DockerFile != nil && rebased.DockerBuild.DockerFile.Location != nil {
+			rebase(rebased.DockerBuild.DockerFile.Location.GitRepository)
+		}
+	}
+
+	return rebased
+}
+
+func definitionRepositorySSHKey(definition *definitionv1.ContainerBuild, repositoryURL string) *definitionv1.SecretRef {
+	var repositories []*definitionv1.GitRepository
+	switch {
+	case definition.CommandBuild != nil:
+		if definition.CommandBuild.Source != nil {
+			repositories = append(repositories, definition.CommandBuild.Source.GitRepository)
+		}
+
+	case definition.DockerBuild != nil:
+		if definition.DockerBuild.BuildContext != nil && definition.DockerBuild.BuildContext.Location != nil {
+			repositories = append(repositories, definition.DockerBuild.BuildContext.Location.GitRepository)
+		}
+	}
+
+	for _, repository := range repositories {
+		if repository != nil && repository.URL == repositoryURL && repository.SSHKey != nil {
+			return repository.SSHKey
+		}
+	}
+
+	return nil
+}
+
+func atCommit(repository *definitionv1.GitRepository, commit *git.CommitReference) bool {
+	return repository != nil &&
+		repository.URL == commit.RepositoryURL &&
+		repository.Commit != nil &&
+		*repository.Commit == commit.Commit
+}
+
+// cleanRepositoryPath returns the path relative to the root of the
+// repository, with "." being the root.
+func cleanRepositoryPath(p string) string {
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+// filesUnder returns the files that are equal to or within any of the paths.
+func filesUnder(files []string, paths []string) []string {
+	var under []string
+	for _, file := range files {
+		for _, p := range paths {
+			if p == "." || file == p || strings.HasPrefix(file, p+"/") {
+				under = append(under, file)
+				break
+			}
+		}
+	}
+
+	return under
+}
+
+func describeFiles(files []string) string {
+	switch len(files) {
+	case 1:
+		return files[0]
+	case 2:
+		return fmt.Sprintf("%v and 1 other file", files[0])
+	default:
+		return fmt.Sprintf("%v and %v other files", files[0], len(files)-1)
+	}
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > shortCommitLength {
+		return commit[:shortCommitLength]
+	}
+	return commit
+}
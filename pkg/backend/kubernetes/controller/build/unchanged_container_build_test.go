@@ -0,0 +1,157 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRepositoryURL = "https://github.com/mlab-lattice/petflix.git"
+
+func testGitRepository(url, commit string) *definitionv1.GitRepository {
+	return &definitionv1.GitRepository{
+		URL:    url,
+		Commit: &commit,
+	}
+}
+
+func testDockerBuild(contextPath, dockerFilePath string, commit string) *definitionv1.ContainerBuild {
+	return &definitionv1.ContainerBuild{
+		DockerBuild: &definitionv1.DockerBuild{
+			BuildContext: &definitionv1.DockerBuildContext{
+				Location: &definitionv1.Location{GitRepository: testGitRepository(testRepositoryURL, commit)},
+				Path:     contextPath,
+			},
+			DockerFile: &definitionv1.DockerFile{
+				Location: &definitionv1.Location{GitRepository: testGitRepository(testRepositoryURL, commit)},
+				Path:     dockerFilePath,
+			},
+		},
+	}
+}
+
+func TestDefinitionRepositoryPaths(t *testing.T) {
+	commit := &git.CommitReference{RepositoryURL: testRepositoryURL, Commit: "abc"}
+
+	tests := []struct {
+		name       string
+		definition *definitionv1.ContainerBuild
+		expected   []string
+	}{
+		{
+			name: "command build",
+			definition: &definitionv1.ContainerBuild{
+				CommandBuild: &definitionv1.ContainerBuildCommand{
+					Source: &definitionv1.ContainerBuildSource{
+						GitRepository: testGitRepository(testRepositoryURL, "abc"),
+					},
+				},
+			},
+			expected: []string{"."},
+		},
+		{
+			name:       "docker file directory",
+			definition: testDockerBuild("/api/", "api", "abc"),
+			expected:   []string{"api", "api/Dockerfile"},
+		},
+		{
+			name:       "docker file",
+			definition: testDockerBuild("api", "docker/api.Dockerfile", "abc"),
+			expected:   []string{"api", "docker/api.Dockerfile", "docker/api.Dockerfile/Dockerfile"},
+		},
+		{
+			name:       "repository root",
+			definition: testDockerBuild("", "", "abc"),
+			expected:   []string{".", "Dockerfile"},
+		},
+		{
+			name:       "other commit",
+			definition: testDockerBuild("api", "api", "def"),
+		},
+		{
+			name: "docker image",
+			definition: &definitionv1.ContainerBuild{
+				DockerImage: &definitionv1.DockerImage{Repository: "library/nginx", Tag: "latest"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, definitionRepositoryPaths(test.definition, commit), test.name)
+	}
+}
+
+func TestRebaseDefinitionRepository(t *testing.T) {
+	commit := &git.CommitReference{RepositoryURL: testRepositoryURL, Commit: "abc"}
+	definition := testDockerBuild("api", "api", "abc")
+	otherRepository := testGitRepository("https://github.com/mlab-lattice/other.git", "abc")
+	definition.DockerBuild.DockerFile.Location.GitRepository = otherRepository
+
+	rebased := rebaseDefinitionRepository(definition, commit, "def")
+	require.Equal(t, "def", *rebased.DockerBuild.BuildContext.Location.GitRepository.Commit)
+
+	// only sources in the definition repository are moved, and the definition is not modified
+	require.Equal(t, "abc", *rebased.DockerBuild.DockerFile.Location.GitRepository.Commit)
+	require.Equal(t, "abc", *definition.DockerBuild.BuildContext.Location.GitRepository.Commit)
+
+	// rebasing onto the previous commit gives the previous definition
+	previous := testDockerBuild("api", "api", "def")
+	previous.DockerBuild.DockerFile.Location.GitRepository = otherRepository
+	require.Equal(t, previous, rebased)
+}
+
+func TestFilesUnder(t *testing.T) {
+	files := []string{"api/main.go", "api-client/main.go", "api", "README.md", "docker/Dockerfile"}
+
+	require.Equal(t, files, filesUnder(files, []string{"."}))
+	require.Equal(t, []string{"api/main.go", "api"}, filesUnder(files, []string{"api"}))
+	require.Equal(
+		t,
+		[]string{"api/main.go", "api", "docker/Dockerfile"},
+		filesUnder(files, []string{"api", "docker/Dockerfile"}),
+	)
+	require.Empty(t, filesUnder(files, []string{"web"}))
+}
+
+func TestDescribeFilesAndCommits(t *testing.T) {
+	require.Equal(t, "a.go", describeFiles([]string{"a.go"}))
+	require.Equal(t, "a.go and 1 other file", describeFiles([]string{"a.go", "b.go"}))
+	require.Equal(t, "a.go and 2 other files", describeFiles([]string{"a.go", "b.go", "c.go"}))
+
+	require.Equal(t, "0123456", shortCommit("0123456789abcdef"))
+	require.Equal(t, "abc", shortCommit("abc"))
+
+	require.Equal(t, ".", cleanRepositoryPath(""))
+	require.Equal(t, ".", cleanRepositoryPath("/"))
+	require.Equal(t, "api", cleanRepositoryPath("./api/"))
+	require.Equal(t, "api", cleanRepositoryPath("../api"))
+}
+
+func TestPreviousContainerBuilds(t *testing.T) {
+	path := tree.RootPath().Child("api")
+	previous := &latticev1.Build{
+		Status: latticev1.BuildStatus{
+			Workloads: map[tree.Path]latticev1.BuildStatusWorkload{
+				path: {
+					MainContainer: "main",
+					Sidecars:      map[string]v1.ContainerBuildID{"proxy": "proxy"},
+				},
+			},
+		},
+	}
+
+	expected := map[string]v1.ContainerBuildID{
+		kubeutil.UserMainContainerName:             "main",
+		kubeutil.UserSidecarContainerName("proxy"): "proxy",
+	}
+	require.Equal(t, expected, previousContainerBuilds(previous, path))
+	require.Nil(t, previousContainerBuilds(previous, tree.RootPath().Child("worker")))
+	require.Nil(t, previousContainerBuilds(nil, path))
+}
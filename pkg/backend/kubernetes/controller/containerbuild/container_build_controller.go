@@ -10,6 +10,7 @@ import (
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -41,6 +42,8 @@ type Controller struct {
 	staticCloudProviderOptions *cloudprovider.Options
 	cloudProvider              cloudprovider.Interface
 
+	secretProviders *secretprovider.Providers
+
 	configLister       latticelisters.ConfigLister
 	configListerSynced cache.InformerSynced
 	configSetChan      chan struct{}
@@ -60,6 +63,7 @@ type Controller struct {
 func NewController(
 	namespacePrefix string,
 	cloudProviderOptions *cloudprovider.Options,
+	secretProviders *secretprovider.Providers,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
@@ -76,6 +80,8 @@ func NewController(
 
 		staticCloudProviderOptions: cloudProviderOptions,
 
+		secretProviders: secretProviders,
+
 		configSetChan: make(chan struct{}),
 
 		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "componentbuild"),
@@ -7,13 +7,15 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/constants"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubesecretprovider "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/secretprovider"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 	"github.com/mlab-lattice/lattice/pkg/util/docker"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 
-	"github.com/mlab-lattice/lattice/pkg/util/sha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -26,6 +28,13 @@ const (
 	jobDockerSocketVolumePath = "/var/run/docker.sock"
 	jobDockerSocketPath       = "/var/run/docker.sock"
 	jobDockerSocketVolumeName = "dockersock"
+
+	jobCacheDirectory           = "/var/cache/builder"
+	jobCacheDirectoryVolumeName = "cache"
+
+	defaultDockerCacheRepository = "lattice-build-cache"
+
+	imageBuilderDaemonless = "daemonless"
 )
 
 func (c *Controller) getJobForBuild(build *latticev1.ContainerBuild) (*batchv1.Job, error) {
@@ -121,6 +130,31 @@ func (c *Controller) jobSpec(build *latticev1.ContainerBuild) (batchv1.JobSpec,
 	name := jobName(build)
 	workingDirectoryVolumeSource := c.cloudProvider.ComponentBuildWorkDirectoryVolumeSource(name)
 
+	volumes := []corev1.Volume{
+		{
+			Name:         jobWorkingDirectoryVolumeName,
+			VolumeSource: workingDirectoryVolumeSource,
+		},
+	}
+
+	if c.requiresDockerDaemon(build) {
+		volumes = append(volumes, corev1.Volume{
+			Name: jobDockerSocketVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: jobDockerSocketVolumePath,
+				},
+			},
+		})
+	}
+
+	if c.config.ContainerBuild.Cache != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name:         jobCacheDirectoryVolumeName,
+			VolumeSource: c.cloudProvider.ComponentBuildCacheVolumeSource(),
+		})
+	}
+
 	var zero int32
 	spec := &batchv1.JobSpec{
 		// Only †ry to run the build once
@@ -137,20 +171,7 @@ func (c *Controller) jobSpec(build *latticev1.ContainerBuild) (batchv1.JobSpec,
 					// Can tolerate build node taint even in local case
 					constants.TolerationBuildNode,
 				},
-				Volumes: []corev1.Volume{
-					{
-						Name:         jobWorkingDirectoryVolumeName,
-						VolumeSource: workingDirectoryVolumeSource,
-					},
-					{
-						Name: jobDockerSocketVolumeName,
-						VolumeSource: corev1.VolumeSource{
-							HostPath: &corev1.HostPathVolumeSource{
-								Path: jobDockerSocketVolumePath,
-							},
-						},
-					},
-				},
+				Volumes:            volumes,
 				Containers:         []corev1.Container{*buildContainer},
 				ServiceAccountName: constants.ServiceAccountComponentBuilder,
 				RestartPolicy:      corev1.RestartPolicyNever,
@@ -215,6 +236,18 @@ func (c *Controller) getBuildContainer(build *latticev1.ContainerBuild) (*corev1
 		args = append(args, "--docker-registry-auth-type", *c.config.ContainerBuild.DockerArtifact.RegistryAuthType)
 	}
 
+	if imageBuilder := c.config.ContainerBuild.Builder.ImageBuilder; imageBuilder != "" {
+		args = append(args, "--image-builder", imageBuilder)
+	}
+
+	if c.config.ContainerBuild.Cache != nil {
+		args = append(
+			args,
+			"--cache-directory", jobCacheDirectory,
+			"--docker-cache-repository", c.dockerCacheRepository(),
+		)
+	}
+
 	buildContainer := &corev1.Container{
 		Name:  "build",
 		Image: c.config.ContainerBuild.Builder.Image,
@@ -230,14 +263,24 @@ func (c *Controller) getBuildContainer(build *latticev1.ContainerBuild) (*corev1
 				Name:      jobWorkingDirectoryVolumeName,
 				MountPath: jobWorkingDirectory,
 			},
-			{
-				Name:      jobDockerSocketVolumeName,
-				MountPath: jobDockerSocketPath,
-			},
 		},
 	}
 
-	if err := maybeSetSSSHKey(build, buildContainer); err != nil {
+	if c.requiresDockerDaemon(build) {
+		buildContainer.VolumeMounts = append(buildContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      jobDockerSocketVolumeName,
+			MountPath: jobDockerSocketPath,
+		})
+	}
+
+	if c.config.ContainerBuild.Cache != nil {
+		buildContainer.VolumeMounts = append(buildContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      jobCacheDirectoryVolumeName,
+			MountPath: jobCacheDirectory,
+		})
+	}
+
+	if err := c.maybeSetSSSHKey(build, buildContainer); err != nil {
 		return nil, "", err
 	}
 
@@ -251,7 +294,32 @@ func (c *Controller) getBuildContainer(build *latticev1.ContainerBuild) (*corev1
 	return buildContainer, dockerImageFQN, nil
 }
 
-func maybeSetSSSHKey(build *latticev1.ContainerBuild, container *corev1.Container) error {
+// requiresDockerDaemon returns whether the build needs access to the build node's
+// docker daemon. Docker builds always do, other builds only if the daemonless
+// image builder is not used.
+func (c *Controller) requiresDockerDaemon(build *latticev1.ContainerBuild) bool {
+	if build.Spec.Definition.DockerBuild != nil {
+		return true
+	}
+
+	return c.config.ContainerBuild.Builder.ImageBuilder != imageBuilderDaemonless
+}
+
+// dockerCacheRepository returns the repository that the builder should store the
+// images used to cache docker layers in.
+func (c *Controller) dockerCacheRepository() string {
+	if repo := c.config.ContainerBuild.Cache.Repository; repo != "" {
+		return repo
+	}
+
+	if repo := c.config.ContainerBuild.DockerArtifact.Repository; repo != "" {
+		return repo
+	}
+
+	return defaultDockerCacheRepository
+}
+
+func (c *Controller) maybeSetSSSHKey(build *latticev1.ContainerBuild, container *corev1.Container) error {
 	def := build.Spec.Definition
 	if def.CommandBuild == nil {
 		return nil
@@ -272,7 +340,23 @@ func maybeSetSSSHKey(build *latticev1.ContainerBuild, container *corev1.Containe
 		return nil
 	}
 
-	secretName, err := sha1.EncodeToHexString([]byte(sshKeySecret.Value.Path().String()))
+	systemID, err := kubeutil.SystemID(c.namespacePrefix, build.Namespace)
+	if err != nil {
+		return err
+	}
+
+	err = kubesecretprovider.EnsureSecretsMirrored(
+		c.namespacePrefix,
+		c.kubeClient,
+		c.secretProviders,
+		systemID,
+		[]definitionv1.SecretRef{*sshKeySecret},
+	)
+	if err != nil {
+		return fmt.Errorf("error mirroring ssh key secret for %v: %v", build.Description(c.namespacePrefix), err)
+	}
+
+	secretName, key, err := latticeutil.SecretNameAndKey(sshKeySecret)
 	if err != nil {
 		return err
 	}
@@ -284,7 +368,7 @@ func maybeSetSSSHKey(build *latticev1.ContainerBuild, container *corev1.Containe
 				LocalObjectReference: corev1.LocalObjectReference{
 					Name: secretName,
 				},
-				Key: sshKeySecret.Value.Subcomponent(),
+				Key: key,
 			},
 		},
 	})
@@ -39,6 +39,20 @@ func (c *Controller) syncSuccessfulComponentBuild(build *latticev1.ContainerBuil
 		DockerImageFQN: dockerImageFQN,
 	}
 
+	provenance, err := build.ProvenanceAnnotation()
+	if err != nil {
+		return err
+	}
+
+	if provenance != nil {
+		artifacts.DockerImageDigest = provenance.DockerImageDigest
+
+		// the builder does not know which version of itself is running, but the job does
+		if containers := job.Spec.Template.Spec.Containers; len(containers) > 0 {
+			provenance.BuilderVersion = containers[0].Image
+		}
+	}
+
 	// if we haven't logged a start timestamp yet, use now
 	startTimestamp := build.Status.StartTimestamp
 	if startTimestamp == nil {
@@ -53,12 +67,13 @@ func (c *Controller) syncSuccessfulComponentBuild(build *latticev1.ContainerBuil
 		completionTimestamp = &now
 	}
 
-	_, err := c.updateComponentBuildStatus(
+	_, err = c.updateComponentBuildStatus(
 		build,
 		latticev1.ContainerBuildStateSucceeded,
 		build.Status.StartTimestamp,
 		completionTimestamp,
 		artifacts,
+		provenance,
 	)
 	return err
 }
@@ -84,6 +99,7 @@ func (c *Controller) syncFailedComponentBuild(build *latticev1.ContainerBuild) e
 		build.Status.StartTimestamp,
 		completionTimestamp,
 		build.Status.Artifacts,
+		build.Status.Provenance,
 	)
 	return err
 }
@@ -103,6 +119,7 @@ func (c *Controller) syncUnfinishedComponentBuild(build *latticev1.ContainerBuil
 			startTimestamp,
 			nil,
 			build.Status.Artifacts,
+			build.Status.Provenance,
 		)
 		return err
 	}
@@ -113,6 +130,7 @@ func (c *Controller) syncUnfinishedComponentBuild(build *latticev1.ContainerBuil
 		startTimestamp,
 		nil,
 		build.Status.Artifacts,
+		build.Status.Provenance,
 	)
 	return err
 }
@@ -123,6 +141,7 @@ func (c *Controller) updateComponentBuildStatus(
 	startTimestamp *metav1.Time,
 	completionTimestamp *metav1.Time,
 	artifacts *latticev1.ContainerBuildArtifacts,
+	provenance *v1.ContainerBuildProvenance,
 ) (*latticev1.ContainerBuild, error) {
 	var phasePtr *v1.ContainerBuildPhase
 	if phase, ok := build.LastObservedPhaseAnnotation(); ok {
@@ -134,6 +153,11 @@ func (c *Controller) updateComponentBuildStatus(
 		return nil, err
 	}
 
+	cacheInfo, err := build.CacheInfoAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
 	status := latticev1.ContainerBuildStatus{
 		State:       state,
 		FailureInfo: failureInfo,
@@ -143,6 +167,9 @@ func (c *Controller) updateComponentBuildStatus(
 
 		Artifacts:         artifacts,
 		LastObservedPhase: phasePtr,
+
+		Cache:      cacheInfo,
+		Provenance: provenance,
 	}
 
 	if reflect.DeepEqual(build.Status, status) {
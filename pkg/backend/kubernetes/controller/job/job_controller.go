@@ -12,8 +12,10 @@ import (
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 
@@ -52,6 +54,8 @@ type Controller struct {
 	staticServiceMeshOptions *servicemesh.Options
 	serviceMesh              servicemesh.Interface
 
+	secretProviders *secretprovider.Providers
+
 	configLister       latticelisters.ConfigLister
 	configListerSynced cache.InformerSynced
 	configSetChan      chan struct{}
@@ -59,6 +63,9 @@ type Controller struct {
 	configLock         sync.RWMutex
 	config             latticev1.ConfigSpec
 
+	systemLister       latticelisters.SystemLister
+	systemListerSynced cache.InformerSynced
+
 	jobRunLister       latticelisters.JobRunLister
 	jobRunListerSynced cache.InformerSynced
 
@@ -80,6 +87,7 @@ func NewController(
 	internalDNSDomain string,
 	cloudProviderOptions *cloudprovider.Options,
 	serviceMeshOptions *servicemesh.Options,
+	secretProviders *secretprovider.Providers,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
@@ -100,6 +108,8 @@ func NewController(
 		staticCloudProviderOptions: cloudProviderOptions,
 		staticServiceMeshOptions:   serviceMeshOptions,
 
+		secretProviders: secretProviders,
+
 		configSetChan: make(chan struct{}),
 
 		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "service"),
@@ -118,6 +128,10 @@ func NewController(
 	sc.configLister = configInformer.Lister()
 	sc.configListerSynced = configInformer.Informer().HasSynced
 
+	systemInformer := latticeInformerFactory.Lattice().V1().Systems()
+	sc.systemLister = systemInformer.Lister()
+	sc.systemListerSynced = systemInformer.Informer().HasSynced
+
 	jobRunInformer := latticeInformerFactory.Lattice().V1().JobRuns()
 	jobRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    sc.handleJobRunAdd,
@@ -172,6 +186,7 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 	if !cache.WaitForCacheSync(
 		stopCh,
 		c.configListerSynced,
+		c.systemListerSynced,
 		c.jobRunListerSynced,
 		c.nodePoolListerSynced,
 		c.kubeJobListerSynced,
@@ -289,6 +304,20 @@ func (c *Controller) syncJobRun(key string) error {
 		return err
 	}
 
+	if jobRun.Status.State == latticev1.JobRunStatePending {
+		reason, err := c.verifyQuota(jobRun)
+		if err != nil {
+			return err
+		}
+
+		if reason != "" {
+			glog.V(2).Infof("failing %v: %v", jobRun.Description(c.namespacePrefix), reason)
+			now := metav1.Now()
+			_, err := c.updateJobRunStatus(jobRun, latticev1.JobRunStateFailed, &now, &now)
+			return err
+		}
+	}
+
 	nodePool, err := c.nodePool(jobRun)
 	if err != nil {
 		return err
@@ -304,6 +333,10 @@ func (c *Controller) syncJobRun(key string) error {
 		return err
 	}
 
+	if kubeJob == nil {
+		return nil
+	}
+
 	_, err = c.syncJobRunStatus(
 		jobRun,
 		kubeJob,
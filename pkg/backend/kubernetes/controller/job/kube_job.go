@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubesecretprovider "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/secretprovider"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -27,6 +29,12 @@ func (c *Controller) syncKubeJob(
 		return kubeJob, nil
 	}
 
+	// job runs that failed before their kube job was created, for example
+	// because they exceeded the system's quota, are never run
+	if jobRun.Status.State == latticev1.JobRunStateFailed {
+		return nil, nil
+	}
+
 	return c.createNewKubeJob(jobRun, nodePool)
 }
 
@@ -208,7 +216,7 @@ func (c *Controller) untransformedPodTemplateSpec(
 		},
 	}
 
-	tolerations := []corev1.Toleration{nodePool.Toleration(nodePoolEpoch)}
+	tolerations := nodePool.WorkloadTolerations(nodePoolEpoch, jobRun.Spec.Definition.Preemptible)
 
 	// copy so we don't mutate the cache
 	jobRun = jobRun.DeepCopy()
@@ -228,6 +236,39 @@ func (c *Controller) untransformedPodTemplateSpec(
 		jobRun.Spec.Definition.Exec.Environment[k] = v
 	}
 
+	// secrets stored in providers other than kubernetes have to be mirrored into
+	// the namespace before the pods referencing them can start
+	systemID, err := kubeutil.SystemID(c.namespacePrefix, jobRun.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = kubesecretprovider.EnsureSecretsMirrored(
+		c.namespacePrefix,
+		c.kubeClient,
+		c.secretProviders,
+		systemID,
+		kubesecretprovider.WorkloadSecretRefs(&jobRun.Spec.Definition),
+	)
+	if err != nil {
+		err := fmt.Errorf("error mirroring secrets for %v: %v", jobRun.Description(c.namespacePrefix), err)
+		return nil, err
+	}
+
+	err = kubesecretprovider.EnsureWorkloadFiles(
+		c.namespacePrefix,
+		c.kubeClient,
+		c.secretProviders,
+		systemID,
+		jobRun.Name,
+		*controllerRef(jobRun),
+		&jobRun.Spec.Definition,
+	)
+	if err != nil {
+		err := fmt.Errorf("error ensuring files for %v: %v", jobRun.Description(c.namespacePrefix), err)
+		return nil, err
+	}
+
 	return latticev1.PodTemplateSpecForV1Workload(
 		&jobRun.Spec.Definition,
 		path,
@@ -0,0 +1,98 @@
+package job
+
+import (
+	"fmt"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// verifyQuota checks the job run against the CPU, memory and job runs limits of
+// the system's quota, if it has one. The API checks the limits before creating a
+// job run, but job runs created concurrently can all pass that check, so only the
+// job runs created before this one count against it here. If the job run exceeds
+// the quota, verifyQuota returns the reason it has to fail.
+func (c *Controller) verifyQuota(jobRun *latticev1.JobRun) (string, error) {
+	systemID, err := kubeutil.SystemID(c.namespacePrefix, jobRun.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	system, err := c.systemLister.Systems(kubeutil.InternalNamespace(c.namespacePrefix)).Get(string(systemID))
+	if err != nil {
+		return "", err
+	}
+
+	systemQuota, err := system.QuotaAnnotation()
+	if err != nil {
+		return "", err
+	}
+
+	if systemQuota == nil {
+		return "", nil
+	}
+
+	services, err := quota.DefinitionUsage(system.Spec.Definition)
+	if err != nil {
+		return "", err
+	}
+
+	usage, err := quota.WorkloadUsage(&jobRun.Spec.Definition)
+	if err != nil {
+		return "", err
+	}
+
+	usage.JobRuns = 1
+
+	jobRuns, err := c.jobRunLister.JobRuns(jobRun.Namespace).List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+
+	for _, other := range jobRuns {
+		switch other.Status.State {
+		case latticev1.JobRunStatePending, latticev1.JobRunStateQueued, latticev1.JobRunStateRunning:
+		default:
+			continue
+		}
+
+		if !createdBefore(other, jobRun) {
+			continue
+		}
+
+		otherUsage, err := quota.WorkloadUsage(&other.Spec.Definition)
+		if err != nil {
+			return "", err
+		}
+
+		usage = quota.Add(usage, otherUsage)
+		usage.JobRuns++
+	}
+
+	// services' instances don't change when running a job, so only check the
+	// limits that the job run counts towards
+	usage.MilliCPU += services.MilliCPU
+	usage.MemoryMiB += services.MemoryMiB
+	if err := quota.Check(systemQuota, usage); err != nil {
+		if _, ok := err.(*quota.ExceededError); !ok {
+			return "", err
+		}
+
+		return fmt.Sprintf("job run %v", err), nil
+	}
+
+	return "", nil
+}
+
+// createdBefore returns whether a was created before b, breaking ties by name
+// so that job runs created at the same time are ordered consistently.
+func createdBefore(a, b *latticev1.JobRun) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
@@ -7,6 +7,13 @@ import (
 )
 
 func (c *Controller) syncActiveNodePool(nodePool *latticev1.NodePool) error {
+	scaled, err := c.autoscaleNodePool(nodePool)
+	if err != nil {
+		return fmt.Errorf("error autoscaling %v: %v", nodePool.Description(c.namespacePrefix), err)
+	}
+
+	nodePool = scaled
+
 	// Get the status of existing epochs.
 	epochs := make(latticev1.NodePoolStatusEpochs)
 	for _, epoch := range nodePool.Status.Epochs.Epochs() {
@@ -133,6 +140,13 @@ func (c *Controller) syncActiveNodePool(nodePool *latticev1.NodePool) error {
 		return err
 	}
 
+	result, err = c.syncNodePoolSpot(nodePool, epoch)
+	if err != nil {
+		return fmt.Errorf("error syncing spot instances of %v: %v", nodePool.Description(c.namespacePrefix), err)
+	}
+
+	nodePool = result
+
 	// We successfully provisioned the current epoch, so we can start trying to retire
 	// any earlier epochs.
 	_, err = c.retireEpochs(nodePool, false)
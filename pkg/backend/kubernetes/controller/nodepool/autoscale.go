@@ -0,0 +1,209 @@
+package nodepool
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/controller/nodepool/autoscaler"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/golang/glog"
+)
+
+// autoscaleNodePool scales an autoscaled node pool to fit the pods of the services and jobs
+// targeting it. Nodes are drained before they are removed, which can take multiple syncs, so
+// the node being drained is recorded in an annotation on the node pool.
+func (c *Controller) autoscaleNodePool(nodePool *latticev1.NodePool) (*latticev1.NodePool, error) {
+	if !nodePool.Spec.Autoscaled() {
+		return nodePool, nil
+	}
+
+	// Overridden node pools keep the number of instances they were overridden to until
+	// the override is removed.
+	override, err := nodePool.OverrideAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	if override != nil {
+		return c.stopDrainingNode(nodePool)
+	}
+
+	// Node pools migrating between epochs are not scaled until the migration is complete.
+	epoch, ok := nodePool.Status.Epochs.CurrentEpoch()
+	if !ok || len(nodePool.Status.Epochs) != 1 {
+		return nodePool, nil
+	}
+
+	nodes, err := c.cloudProvider.NodePoolEpochNodes(c.latticeID, nodePool, epoch)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cloud provider could not get nodes for %v epoch %v: %v",
+			nodePool.Description(c.namespacePrefix),
+			epoch,
+			err,
+		)
+	}
+
+	if _, ok := nodePool.DrainingNodeAnnotation(); ok {
+		return c.drainNode(nodePool, epoch, nodes)
+	}
+
+	// Wait until all of the node pool's instances are running before deciding whether to
+	// scale it again.
+	if !nodePool.Stable() {
+		return nodePool, nil
+	}
+
+	state, err := c.autoscalerNodePool(nodePool, epoch, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := autoscaler.Decide(state)
+	if decision.RemoveNode != "" {
+		glog.V(4).Infof(
+			"draining node %v to scale %v down to %v instances",
+			decision.RemoveNode,
+			nodePool.Description(c.namespacePrefix),
+			decision.NumInstances,
+		)
+		return c.startDrainingNode(nodePool, decision.RemoveNode)
+	}
+
+	if decision.NumInstances == nodePool.Spec.NumInstances {
+		return nodePool, nil
+	}
+
+	glog.V(4).Infof(
+		"scaling %v from %v to %v instances",
+		nodePool.Description(c.namespacePrefix),
+		nodePool.Spec.NumInstances,
+		decision.NumInstances,
+	)
+
+	// Copy so the shared cache isn't mutated
+	nodePool = nodePool.DeepCopy()
+	nodePool.Spec.NumInstances = decision.NumInstances
+
+	result, err := c.latticeClient.LatticeV1().NodePools(nodePool.Namespace).Update(nodePool)
+	if err != nil {
+		return nil, fmt.Errorf("error scaling %v: %v", nodePool.Description(c.namespacePrefix), err)
+	}
+
+	return result, nil
+}
+
+// autoscalerNodePool returns the state of the node pool's epoch that the autoscaler bases its
+// decisions on.
+func (c *Controller) autoscalerNodePool(
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+	nodes []corev1.Node,
+) (*autoscaler.NodePool, error) {
+	pods, err := c.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	state := &autoscaler.NodePool{
+		NumInstances: nodePool.Spec.NumInstances,
+		MinInstances: nodePool.Spec.MinInstances,
+		MaxInstances: nodePool.Spec.MaxInstances,
+	}
+
+	nodeIndexes := make(map[string]int)
+	for _, node := range nodes {
+		nodeIndexes[node.Name] = len(state.Nodes)
+		state.Nodes = append(state.Nodes, autoscaler.Node{
+			Name: node.Name,
+			Allocatable: autoscaler.Resources{
+				MilliCPU: node.Status.Allocatable.Cpu().MilliValue(),
+				Memory:   node.Status.Allocatable.Memory().Value(),
+			},
+		})
+	}
+
+	toleration := nodePool.Toleration(epoch)
+	for _, pod := range pods {
+		if podTerminated(pod) {
+			continue
+		}
+
+		requests := podRequests(pod)
+
+		if i, ok := nodeIndexes[pod.Spec.NodeName]; ok {
+			// Pods that run on every node, like daemon set pods, don't move if the node is
+			// removed, so they just reduce the resources available to the other pods.
+			if !podReschedulable(pod) {
+				state.Nodes[i].Allocatable = state.Nodes[i].Allocatable.Sub(requests)
+				continue
+			}
+
+			state.Nodes[i].Pods = append(state.Nodes[i].Pods, requests)
+			continue
+		}
+
+		if pod.Namespace == nodePool.Namespace && podUnschedulable(pod) && podTolerates(pod, toleration) {
+			state.Pending = append(state.Pending, requests)
+		}
+	}
+
+	return state, nil
+}
+
+func podRequests(pod *corev1.Pod) autoscaler.Resources {
+	var requests autoscaler.Resources
+	for _, container := range pod.Spec.Containers {
+		requests = requests.Add(autoscaler.Resources{
+			MilliCPU: container.Resources.Requests.Cpu().MilliValue(),
+			Memory:   container.Resources.Requests.Memory().Value(),
+		})
+	}
+	return requests
+}
+
+func podTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func podUnschedulable(pod *corev1.Pod) bool {
+	if pod.Spec.NodeName != "" {
+		return false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+
+	return false
+}
+
+func podTolerates(pod *corev1.Pod, toleration corev1.Toleration) bool {
+	for _, t := range pod.Spec.Tolerations {
+		if t.Key == toleration.Key && t.Value == toleration.Value && t.Effect == toleration.Effect {
+			return true
+		}
+	}
+	return false
+}
+
+// podReschedulable returns whether the pod would have to be rescheduled onto another node
+// if its node were removed.
+func podReschedulable(pod *corev1.Pod) bool {
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+
+	return true
+}
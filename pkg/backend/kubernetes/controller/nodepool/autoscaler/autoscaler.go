@@ -0,0 +1,248 @@
+// Package autoscaler decides how many instances an autoscaled node pool should
+// have based on the resources requested by the workloads targeting it.
+package autoscaler
+
+import (
+	"sort"
+)
+
+// ScaleDownUtilizationThreshold is the utilization below which a node is
+// considered for removal.
+const ScaleDownUtilizationThreshold = 0.5
+
+// Resources are amounts of CPU and memory.
+type Resources struct {
+	MilliCPU int64
+	Memory   int64
+}
+
+func (r Resources) Add(other Resources) Resources {
+	return Resources{
+		MilliCPU: r.MilliCPU + other.MilliCPU,
+		Memory:   r.Memory + other.Memory,
+	}
+}
+
+func (r Resources) Sub(other Resources) Resources {
+	return Resources{
+		MilliCPU: r.MilliCPU - other.MilliCPU,
+		Memory:   r.Memory - other.Memory,
+	}
+}
+
+// FitsIn returns whether r fits in the available resources.
+func (r Resources) FitsIn(available Resources) bool {
+	return r.MilliCPU <= available.MilliCPU && r.Memory <= available.Memory
+}
+
+// Node is an instance of the node pool.
+type Node struct {
+	Name        string
+	Allocatable Resources
+
+	// Pods are the requests of the pods on the node that would have to be
+	// rescheduled if the node were removed.
+	Pods []Resources
+}
+
+// Requested returns the sum of the requests of the node's pods.
+func (n *Node) Requested() Resources {
+	var requested Resources
+	for _, pod := range n.Pods {
+		requested = requested.Add(pod)
+	}
+	return requested
+}
+
+// Utilization returns the larger of the fractions of the node's CPU and
+// memory that are requested.
+func (n *Node) Utilization() float64 {
+	requested := n.Requested()
+
+	var cpu, memory float64
+	if n.Allocatable.MilliCPU > 0 {
+		cpu = float64(requested.MilliCPU) / float64(n.Allocatable.MilliCPU)
+	}
+	if n.Allocatable.Memory > 0 {
+		memory = float64(requested.Memory) / float64(n.Allocatable.Memory)
+	}
+
+	if cpu > memory {
+		return cpu
+	}
+	return memory
+}
+
+// NodePool is the state of an autoscaled node pool.
+type NodePool struct {
+	NumInstances int32
+	MinInstances int32
+	MaxInstances int32
+
+	// Nodes are the node pool's nodes that are currently running.
+	Nodes []Node
+
+	// Pending are the requests of the pods targeting the node pool that
+	// could not be scheduled.
+	Pending []Resources
+}
+
+// Decision is what the autoscaler decided to do to a node pool.
+type Decision struct {
+	// NumInstances is the number of instances the node pool should have.
+	NumInstances int32
+
+	// RemoveNode is the name of the node that should be drained and then
+	// removed to get to NumInstances, if the node pool is scaling down.
+	RemoveNode string
+}
+
+// Decide returns how the node pool should be scaled.
+// Node pools are scaled up to fit their pending pods, and are scaled down one
+// underutilized node at a time once there are no pending pods and the node's
+// pods fit on the rest of the node pool.
+func Decide(nodePool *NodePool) Decision {
+	numInstances := nodePool.NumInstances
+
+	if len(nodePool.Pending) > 0 {
+		desired := numInstances + additionalNodes(nodePool.Nodes, nodePool.Pending)
+		desired = clamp(desired, nodePool.MinInstances, nodePool.MaxInstances)
+
+		// Never scale down while there are pods waiting to be scheduled.
+		if desired < numInstances {
+			desired = numInstances
+		}
+		return Decision{NumInstances: desired}
+	}
+
+	if numInstances < nodePool.MinInstances {
+		return Decision{NumInstances: nodePool.MinInstances}
+	}
+
+	// Only scale down once all of the node pool's instances are running, so
+	// nodes aren't removed based on the utilization of a partial node pool.
+	if numInstances <= nodePool.MinInstances || int32(len(nodePool.Nodes)) != numInstances {
+		return Decision{NumInstances: numInstances}
+	}
+
+	node, ok := removableNode(nodePool.Nodes, numInstances > nodePool.MaxInstances)
+	if !ok {
+		return Decision{NumInstances: numInstances}
+	}
+
+	return Decision{
+		NumInstances: numInstances - 1,
+		RemoveNode:   node,
+	}
+}
+
+// additionalNodes returns the number of nodes that have to be added to the
+// node pool for the pending pods to be scheduled.
+func additionalNodes(nodes []Node, pending []Resources) int32 {
+	// If the node pool doesn't have any nodes there's no way to know how
+	// much a node can fit, so add one and look again once it is running.
+	if len(nodes) == 0 {
+		return 1
+	}
+
+	var free []Resources
+	for _, node := range nodes {
+		free = append(free, node.Allocatable.Sub(node.Requested()))
+	}
+
+	// Nodes in a node pool are all the same instance type.
+	allocatable := nodes[0].Allocatable
+
+	var added []Resources
+	for _, pod := range sortedDescending(pending) {
+		if fit(free, pod) || fit(added, pod) {
+			continue
+		}
+
+		// The pod will never fit on a node of this instance type, so adding
+		// nodes won't help it.
+		if !pod.FitsIn(allocatable) {
+			continue
+		}
+
+		added = append(added, allocatable.Sub(pod))
+	}
+
+	return int32(len(added))
+}
+
+// removableNode returns the least utilized node that can be removed. Unless
+// force is true, the node has to be underutilized and its pods have to fit on
+// the other nodes.
+func removableNode(nodes []Node, force bool) (string, bool) {
+	candidates := make([]Node, len(nodes))
+	copy(candidates, nodes)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Utilization() < candidates[j].Utilization()
+	})
+
+	if force {
+		return candidates[0].Name, true
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Utilization() >= ScaleDownUtilizationThreshold {
+			break
+		}
+
+		var free []Resources
+		for _, node := range nodes {
+			if node.Name != candidate.Name {
+				free = append(free, node.Allocatable.Sub(node.Requested()))
+			}
+		}
+
+		fits := true
+		for _, pod := range sortedDescending(candidate.Pods) {
+			if !fit(free, pod) {
+				fits = false
+				break
+			}
+		}
+
+		if fits {
+			return candidate.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// fit places the pod in the first of the free resources it fits in,
+// returning whether it was placed.
+func fit(free []Resources, pod Resources) bool {
+	for i := range free {
+		if pod.FitsIn(free[i]) {
+			free[i] = free[i].Sub(pod)
+			return true
+		}
+	}
+	return false
+}
+
+func sortedDescending(pods []Resources) []Resources {
+	sorted := make([]Resources, len(pods))
+	copy(sorted, pods)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].MilliCPU != sorted[j].MilliCPU {
+			return sorted[i].MilliCPU > sorted[j].MilliCPU
+		}
+		return sorted[i].Memory > sorted[j].Memory
+	})
+	return sorted
+}
+
+func clamp(numInstances, min, max int32) int32 {
+	if numInstances < min {
+		return min
+	}
+	if numInstances > max {
+		return max
+	}
+	return numInstances
+}
@@ -0,0 +1,186 @@
+package autoscaler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const gi = 1024 * 1024 * 1024
+
+func node(name string, pods ...Resources) Node {
+	return Node{
+		Name:        name,
+		Allocatable: Resources{MilliCPU: 2000, Memory: 4 * gi},
+		Pods:        pods,
+	}
+}
+
+func TestDecide(t *testing.T) {
+	small := Resources{MilliCPU: 250, Memory: gi / 2}
+	large := Resources{MilliCPU: 1500, Memory: 3 * gi}
+	huge := Resources{MilliCPU: 4000, Memory: gi}
+
+	tests := []struct {
+		name     string
+		nodePool NodePool
+		expected Decision
+	}{
+		{
+			name: "scales up to fit pending pods",
+			nodePool: NodePool{
+				NumInstances: 1,
+				MinInstances: 1,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a", large)},
+				Pending:      []Resources{large, large, small},
+			},
+			expected: Decision{NumInstances: 3},
+		},
+		{
+			name: "does not scale up past the maximum",
+			nodePool: NodePool{
+				NumInstances: 2,
+				MinInstances: 1,
+				MaxInstances: 3,
+				Nodes:        []Node{node("a", large), node("b", large)},
+				Pending:      []Resources{large, large, large},
+			},
+			expected: Decision{NumInstances: 3},
+		},
+		{
+			name: "does not scale up for pods that fit on existing nodes",
+			nodePool: NodePool{
+				NumInstances: 2,
+				MinInstances: 1,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a", large), node("b")},
+				Pending:      []Resources{small, small},
+			},
+			expected: Decision{NumInstances: 2},
+		},
+		{
+			name: "does not scale up for pods that cannot fit on any node",
+			nodePool: NodePool{
+				NumInstances: 1,
+				MinInstances: 1,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a", large)},
+				Pending:      []Resources{huge},
+			},
+			expected: Decision{NumInstances: 1},
+		},
+		{
+			name: "adds a node to an empty node pool with pending pods",
+			nodePool: NodePool{
+				NumInstances: 0,
+				MinInstances: 0,
+				MaxInstances: 5,
+				Pending:      []Resources{small},
+			},
+			expected: Decision{NumInstances: 1},
+		},
+		{
+			name: "scales up to the minimum",
+			nodePool: NodePool{
+				NumInstances: 1,
+				MinInstances: 2,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a")},
+			},
+			expected: Decision{NumInstances: 2},
+		},
+		{
+			name: "removes an underutilized node whose pods fit elsewhere",
+			nodePool: NodePool{
+				NumInstances: 3,
+				MinInstances: 1,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a", large), node("b", small), node("c", large)},
+			},
+			expected: Decision{NumInstances: 2, RemoveNode: "b"},
+		},
+		{
+			name: "keeps underutilized nodes whose pods do not fit elsewhere",
+			nodePool: NodePool{
+				NumInstances: 2,
+				MinInstances: 1,
+				MaxInstances: 5,
+				Nodes: []Node{
+					node("a", large),
+					node("b", Resources{MilliCPU: 900, Memory: gi}),
+				},
+			},
+			expected: Decision{NumInstances: 2},
+		},
+		{
+			name: "does not scale down past the minimum",
+			nodePool: NodePool{
+				NumInstances: 2,
+				MinInstances: 2,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a"), node("b")},
+			},
+			expected: Decision{NumInstances: 2},
+		},
+		{
+			name: "does not scale down while instances are coming up",
+			nodePool: NodePool{
+				NumInstances: 3,
+				MinInstances: 1,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a"), node("b")},
+			},
+			expected: Decision{NumInstances: 3},
+		},
+		{
+			name: "scales up to the minimum while pods are pending",
+			nodePool: NodePool{
+				NumInstances: 1,
+				MinInstances: 3,
+				MaxInstances: 5,
+				Nodes:        []Node{node("a")},
+				Pending:      []Resources{small},
+			},
+			expected: Decision{NumInstances: 3},
+		},
+		{
+			name: "does not scale up a node pool at the maximum",
+			nodePool: NodePool{
+				NumInstances: 2,
+				MinInstances: 2,
+				MaxInstances: 2,
+				Nodes:        []Node{node("a", large), node("b", large)},
+				Pending:      []Resources{large},
+			},
+			expected: Decision{NumInstances: 2},
+		},
+		{
+			name: "does not scale down to the maximum while pods are pending",
+			nodePool: NodePool{
+				NumInstances: 3,
+				MinInstances: 1,
+				MaxInstances: 2,
+				Nodes:        []Node{node("a", large), node("b", large), node("c", large)},
+				Pending:      []Resources{large},
+			},
+			expected: Decision{NumInstances: 3},
+		},
+		{
+			name: "scales down to the maximum",
+			nodePool: NodePool{
+				NumInstances: 2,
+				MinInstances: 1,
+				MaxInstances: 1,
+				Nodes:        []Node{node("a", large), node("b", large, small)},
+			},
+			expected: Decision{NumInstances: 1, RemoveNode: "a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, Decide(&test.nodePool))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package nodepool
+
+import (
+	"fmt"
+	"time"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/golang/glog"
+)
+
+// drainCheckInterval is how often a node pool that is draining a node is synced
+// to check whether the node has been drained.
+const drainCheckInterval = 10 * time.Second
+
+func (c *Controller) startDrainingNode(nodePool *latticev1.NodePool, node string) (*latticev1.NodePool, error) {
+	annotations := make(map[string]string)
+	for k, v := range nodePool.Annotations {
+		annotations[k] = v
+	}
+	annotations[latticev1.NodePoolDrainingNodeAnnotationKey] = node
+
+	return c.updateNodePoolAnnotations(nodePool, annotations)
+}
+
+// stopDrainingNode makes the node the node pool was draining, if any, schedulable again.
+func (c *Controller) stopDrainingNode(nodePool *latticev1.NodePool) (*latticev1.NodePool, error) {
+	node, ok := nodePool.DrainingNodeAnnotation()
+	if !ok {
+		return nodePool, nil
+	}
+
+	if err := c.setNodeUnschedulable(node, false); err != nil {
+		return nil, err
+	}
+
+	annotations := make(map[string]string)
+	for k, v := range nodePool.Annotations {
+		annotations[k] = v
+	}
+	delete(annotations, latticev1.NodePoolDrainingNodeAnnotationKey)
+
+	return c.updateNodePoolAnnotations(nodePool, annotations)
+}
+
+// drainNode cordons the node the node pool is draining and evicts its pods. Once all of its pods
+// are gone, the cloud provider removes the node and the node pool's number of instances is
+// decremented.
+func (c *Controller) drainNode(
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+	nodes []corev1.Node,
+) (*latticev1.NodePool, error) {
+	name, _ := nodePool.DrainingNodeAnnotation()
+
+	var node *corev1.Node
+	for i := range nodes {
+		if nodes[i].Name == name {
+			node = &nodes[i]
+			break
+		}
+	}
+
+	// If the node has already been removed from the node pool there's nothing to drain.
+	if node != nil {
+		if err := c.setNodeUnschedulable(name, true); err != nil {
+			return nil, err
+		}
+
+		drained, err := c.evictNodePods(name)
+		if err != nil {
+			return nil, fmt.Errorf("error draining node %v of %v: %v", name, nodePool.Description(c.namespacePrefix), err)
+		}
+
+		if !drained {
+			key, err := cache.MetaNamespaceKeyFunc(nodePool)
+			if err != nil {
+				return nil, err
+			}
+
+			glog.V(4).Infof("waiting for node %v of %v to drain", name, nodePool.Description(c.namespacePrefix))
+			c.queue.AddAfter(key, drainCheckInterval)
+			return nodePool, nil
+		}
+
+		err = c.cloudProvider.RemoveNodePoolEpochNode(c.latticeID, nodePool, epoch, node)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cloud provider could not remove node %v from %v epoch %v: %v",
+				name,
+				nodePool.Description(c.namespacePrefix),
+				epoch,
+				err,
+			)
+		}
+	}
+
+	// Copy so the shared cache isn't mutated
+	nodePool = nodePool.DeepCopy()
+	nodePool.Spec.NumInstances--
+	delete(nodePool.Annotations, latticev1.NodePoolDrainingNodeAnnotationKey)
+
+	result, err := c.latticeClient.LatticeV1().NodePools(nodePool.Namespace).Update(nodePool)
+	if err != nil {
+		return nil, fmt.Errorf("error scaling %v down: %v", nodePool.Description(c.namespacePrefix), err)
+	}
+
+	return result, nil
+}
+
+func (c *Controller) setNodeUnschedulable(name string, unschedulable bool) error {
+	node, err := c.kubeNodeLister.Get(name)
+	if err != nil {
+		// Nodes simulated by the cloud provider aren't registered with kubernetes,
+		// so there is nothing to cordon.
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+
+	// Copy so the shared cache isn't mutated
+	node = node.DeepCopy()
+	node.Spec.Unschedulable = unschedulable
+
+	_, err = c.kubeClient.CoreV1().Nodes().Update(node)
+	if err != nil {
+		return fmt.Errorf("error updating node %v: %v", name, err)
+	}
+
+	return nil
+}
+
+// evictNodePods evicts the pods on the node that have to be rescheduled elsewhere, and returns
+// whether all of them are gone.
+func (c *Controller) evictNodePods(node string) (bool, error) {
+	pods, err := c.podLister.List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+
+	drained := true
+	for _, pod := range pods {
+		if pod.Spec.NodeName != node || podTerminated(pod) || !podReschedulable(pod) {
+			continue
+		}
+
+		drained = false
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		eviction := &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		}
+		err := c.kubeClient.CoreV1().Pods(pod.Namespace).Evict(eviction)
+		if err != nil && !errors.IsNotFound(err) {
+			// Evictions that would violate a pod disruption budget are rejected,
+			// so retry them the next time the node pool is synced.
+			if errors.IsTooManyRequests(err) {
+				continue
+			}
+
+			return false, fmt.Errorf("error evicting pod %v/%v: %v", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	return drained, nil
+}
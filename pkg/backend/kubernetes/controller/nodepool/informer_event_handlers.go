@@ -241,3 +241,62 @@ func (c *Controller) handleKubeNodeEvent(node *corev1.Node, verb string) {
 
 	c.enqueue(nodePool)
 }
+
+func (c *Controller) handlePodAdd(obj interface{}) {
+	pod := obj.(*corev1.Pod)
+	c.handlePodEvent(pod, "added")
+}
+
+func (c *Controller) handlePodUpdate(old, cur interface{}) {
+	pod := cur.(*corev1.Pod)
+	c.handlePodEvent(pod, "updated")
+}
+
+func (c *Controller) handlePodDelete(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+
+	// When a delete is dropped, the relist will notice a pod in the store not
+	// in the list, leading to the insertion of a tombstone object which contains
+	// the deleted key/value.
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			runtime.HandleError(fmt.Errorf("couldn't get object from tombstone %#v", obj))
+			return
+		}
+		pod, ok = tombstone.Obj.(*corev1.Pod)
+		if !ok {
+			runtime.HandleError(fmt.Errorf("tombstone contained object that is not a pod %#v", obj))
+			return
+		}
+	}
+
+	c.handlePodEvent(pod, "deleted")
+}
+
+// handlePodEvent enqueues the node pool the pod targets, if any, so that autoscaled
+// node pools are scaled when their pods are pending or when their utilization changes.
+func (c *Controller) handlePodEvent(pod *corev1.Pod, verb string) {
+	for _, toleration := range pod.Spec.Tolerations {
+		if toleration.Key != latticev1.NodePoolIDLabelKey || toleration.Operator != corev1.TolerationOpEqual {
+			continue
+		}
+
+		glog.V(5).Infof("pod %v/%v %v", pod.Namespace, pod.Name, verb)
+
+		systemID, nodePoolID, _, err := latticev1.NodePoolIDLabelInfo(c.namespacePrefix, toleration.Value)
+		if err != nil {
+			glog.Warningf("error getting node pool id info for pod %v/%v: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		namespace := kubeutil.SystemNamespace(c.namespacePrefix, systemID)
+		nodePool, err := c.nodePoolLister.NodePools(namespace).Get(nodePoolID)
+		if err != nil {
+			// the node pool may have already been deleted
+			continue
+		}
+
+		c.enqueue(nodePool)
+	}
+}
@@ -3,6 +3,7 @@ package nodepool
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
@@ -38,6 +39,7 @@ func (c *Controller) updateNodePoolStatus(
 		ObservedGeneration: nodePool.ObjectMeta.Generation,
 		State:              state,
 		Epochs:             epochs,
+		InstanceHours:      nodePool.Status.InstanceHours.Record(epochsInstances(epochs), time.Now()),
 	}
 
 	if reflect.DeepEqual(nodePool.Status, status) {
@@ -56,6 +58,17 @@ func (c *Controller) updateNodePoolStatus(
 	return result, nil
 }
 
+// epochsInstances returns the number of instances of each instance type in the
+// epochs, so that the node pool's instance-hours can be recorded.
+func epochsInstances(epochs latticev1.NodePoolStatusEpochs) map[string]int32 {
+	instances := make(map[string]int32)
+	for _, epoch := range epochs {
+		instances[epoch.Status.InstanceType] += epoch.Status.NumInstances
+	}
+
+	return instances
+}
+
 func nodePoolState(epochs latticev1.NodePoolStatusEpochs) (latticev1.NodePoolState, error) {
 	if len(epochs) == 0 {
 		return latticev1.NodePoolStatePending, nil
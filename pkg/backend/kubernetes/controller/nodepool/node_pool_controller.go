@@ -9,17 +9,21 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cloudprovider"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
+	latticescheme "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned/scheme"
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	kubeinformers "k8s.io/client-go/informers"
 	kubeclientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/golang/glog"
@@ -35,6 +39,8 @@ type Controller struct {
 	kubeClient    kubeclientset.Interface
 	latticeClient latticeclientset.Interface
 
+	recorder record.EventRecorder
+
 	kubeInformerFactory    kubeinformers.SharedInformerFactory
 	latticeInformerFactory latticeinformers.SharedInformerFactory
 
@@ -54,9 +60,15 @@ type Controller struct {
 	serviceLister       latticelisters.ServiceLister
 	serviceListerSynced cache.InformerSynced
 
+	jobRunLister       latticelisters.JobRunLister
+	jobRunListerSynced cache.InformerSynced
+
 	kubeNodeLister       corelisters.NodeLister
 	kubeNodeListerSynced cache.InformerSynced
 
+	podLister       corelisters.PodLister
+	podListerSynced cache.InformerSynced
+
 	queue workqueue.RateLimitingInterface
 }
 
@@ -86,6 +98,11 @@ func NewController(
 		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "nodepool"),
 	}
 
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	sc.recorder = eventBroadcaster.NewRecorder(latticescheme.Scheme, corev1.EventSource{Component: "nodepool-controller"})
+
 	sc.syncHandler = sc.syncNodePool
 	sc.enqueue = sc.enqueueNodePool
 
@@ -117,6 +134,10 @@ func NewController(
 	sc.serviceLister = serviceInformer.Lister()
 	sc.serviceListerSynced = serviceInformer.Informer().HasSynced
 
+	jobRunInformer := latticeInformerFactory.Lattice().V1().JobRuns()
+	sc.jobRunLister = jobRunInformer.Lister()
+	sc.jobRunListerSynced = jobRunInformer.Informer().HasSynced
+
 	kubeNodeInformer := kubeInformerFactory.Core().V1().Nodes()
 	kubeNodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    sc.handleKubeNodeAdd,
@@ -126,6 +147,15 @@ func NewController(
 	sc.kubeNodeLister = kubeNodeInformer.Lister()
 	sc.serviceListerSynced = kubeNodeInformer.Informer().HasSynced
 
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sc.handlePodAdd,
+		UpdateFunc: sc.handlePodUpdate,
+		DeleteFunc: sc.handlePodDelete,
+	})
+	sc.podLister = podInformer.Lister()
+	sc.podListerSynced = podInformer.Informer().HasSynced
+
 	return sc
 }
 
@@ -139,7 +169,7 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 	defer glog.Infof("shutting down endpoint controller")
 
 	// wait for your secondary caches to fill before starting your work
-	if !cache.WaitForCacheSync(stopCh, c.configListerSynced, c.nodePoolListerSynced, c.serviceListerSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.configListerSynced, c.nodePoolListerSynced, c.serviceListerSynced, c.jobRunListerSynced, c.podListerSynced) {
 		return
 	}
 
@@ -0,0 +1,240 @@
+package nodepool
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// spotCheckInterval is how often node pools with spot instances are synced to check
+	// for interruption notices, which are sent shortly before an instance is interrupted.
+	spotCheckInterval = 30 * time.Second
+
+	// spotFallbackDuration is how long a node pool launches on-demand instances in place
+	// of spot instances before it tries launching spot instances again.
+	spotFallbackDuration = 30 * time.Minute
+
+	reasonSpotFallback     = "SpotFallback"
+	reasonSpotInterruption = "SpotInterruption"
+)
+
+// syncNodePoolSpot falls back to on-demand instances while spot capacity is unavailable if the
+// node pool's fallback policy allows it, and records events on the services and jobs running on
+// the epoch's nodes that have been sent interruption notices.
+func (c *Controller) syncNodePoolSpot(
+	nodePool *latticev1.NodePool,
+	epoch latticev1.NodePoolEpoch,
+) (*latticev1.NodePool, error) {
+	_, fallingBack := nodePool.Annotations[latticev1.NodePoolSpotFallbackAnnotationKey]
+	_, interrupted := nodePool.Annotations[latticev1.NodePoolInterruptedNodesAnnotationKey]
+	if nodePool.Spec.Spot == nil && !fallingBack && !interrupted {
+		return nodePool, nil
+	}
+
+	// Copy annotations so the shared cache isn't mutated
+	annotations := make(map[string]string)
+	for k, v := range nodePool.Annotations {
+		annotations[k] = v
+	}
+
+	if nodePool.Spec.Spot == nil {
+		delete(annotations, latticev1.NodePoolSpotFallbackAnnotationKey)
+		delete(annotations, latticev1.NodePoolInterruptedNodesAnnotationKey)
+		return c.updateNodePoolAnnotations(nodePool, annotations)
+	}
+
+	status, err := c.cloudProvider.NodePoolEpochSpotStatus(c.latticeID, nodePool, epoch)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cloud provider could not get spot status for %v epoch %v: %v",
+			nodePool.Description(c.namespacePrefix),
+			epoch,
+			err,
+		)
+	}
+
+	if err := c.syncSpotFallback(nodePool, status, annotations); err != nil {
+		return nil, err
+	}
+
+	if err := c.recordSpotInterruptions(nodePool, status, annotations); err != nil {
+		return nil, err
+	}
+
+	result, err := c.updateNodePoolAnnotations(nodePool, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := cache.MetaNamespaceKeyFunc(nodePool)
+	if err != nil {
+		return nil, err
+	}
+
+	c.queue.AddAfter(key, spotCheckInterval)
+	return result, nil
+}
+
+func (c *Controller) syncSpotFallback(
+	nodePool *latticev1.NodePool,
+	status *latticev1.NodePoolSpotStatus,
+	annotations map[string]string,
+) error {
+	since, fallingBack, err := nodePool.SpotFallbackAnnotation()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case nodePool.Spec.SpotFallback() != definitionv1.NodePoolSpotFallbackOnDemand:
+		delete(annotations, latticev1.NodePoolSpotFallbackAnnotationKey)
+
+	case fallingBack:
+		// Periodically try launching spot instances again.
+		if time.Since(since) > spotFallbackDuration {
+			glog.V(4).Infof("retrying spot instances for %v", nodePool.Description(c.namespacePrefix))
+			delete(annotations, latticev1.NodePoolSpotFallbackAnnotationKey)
+		}
+
+	case status.CapacityUnavailable:
+		c.recorder.Event(
+			nodePool,
+			corev1.EventTypeWarning,
+			reasonSpotFallback,
+			"spot capacity is unavailable, launching on-demand instances in place of spot instances",
+		)
+		annotations[latticev1.NodePoolSpotFallbackAnnotationKey] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	return nil
+}
+
+func (c *Controller) recordSpotInterruptions(
+	nodePool *latticev1.NodePool,
+	status *latticev1.NodePoolSpotStatus,
+	annotations map[string]string,
+) error {
+	recorded, err := nodePool.InterruptedNodesAnnotation()
+	if err != nil {
+		return err
+	}
+
+	alreadyRecorded := make(map[string]bool)
+	for _, node := range recorded {
+		alreadyRecorded[node] = true
+	}
+
+	for _, node := range status.InterruptedNodes {
+		if alreadyRecorded[node] {
+			continue
+		}
+
+		if err := c.recordNodeInterruption(nodePool, node); err != nil {
+			return err
+		}
+	}
+
+	// Only keep the nodes that are still being interrupted so that the annotation
+	// doesn't grow as the node pool's instances are replaced.
+	if len(status.InterruptedNodes) == 0 {
+		delete(annotations, latticev1.NodePoolInterruptedNodesAnnotationKey)
+		return nil
+	}
+
+	data, err := json.Marshal(status.InterruptedNodes)
+	if err != nil {
+		return err
+	}
+
+	annotations[latticev1.NodePoolInterruptedNodesAnnotationKey] = string(data)
+	return nil
+}
+
+// recordNodeInterruption records an event on each of the services and job runs with
+// pods running on the node.
+func (c *Controller) recordNodeInterruption(nodePool *latticev1.NodePool, node string) error {
+	pods, err := c.podLister.Pods(nodePool.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	recorded := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != node {
+			continue
+		}
+
+		object, err := c.podWorkload(pod)
+		if err != nil {
+			return err
+		}
+
+		if object == nil {
+			continue
+		}
+
+		key, err := cache.MetaNamespaceKeyFunc(object)
+		if err != nil {
+			return err
+		}
+
+		if recorded[key] {
+			continue
+		}
+
+		recorded[key] = true
+		c.recorder.Eventf(
+			object,
+			corev1.EventTypeWarning,
+			reasonSpotInterruption,
+			"node %v of %v was sent a spot interruption notice and will be interrupted",
+			node,
+			nodePool.Description(c.namespacePrefix),
+		)
+	}
+
+	return nil
+}
+
+// podWorkload returns the service or job run the pod is running for, if it exists.
+func (c *Controller) podWorkload(pod *corev1.Pod) (runtime.Object, error) {
+	if id, ok := pod.Labels[latticev1.ServiceIDLabelKey]; ok {
+		service, err := c.serviceLister.Services(pod.Namespace).Get(id)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return service, nil
+	}
+
+	if id, ok := pod.Labels[latticev1.JobRunIDLabelKey]; ok {
+		jobRun, err := c.jobRunLister.JobRuns(pod.Namespace).Get(id)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return jobRun, nil
+	}
+
+	return nil, nil
+}
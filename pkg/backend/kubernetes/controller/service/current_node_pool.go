@@ -18,7 +18,7 @@ func (c *Controller) syncCurrentNodePool(service *latticev1.Service) (*latticev1
 
 	switch info.nodePoolType {
 	case latticev1.NodePoolTypeServiceDedicated:
-		return c.syncDedicatedNodePool(service, info.numInstances, info.instanceType)
+		return c.syncDedicatedNodePool(service, nodePoolSpec(info))
 
 	case latticev1.NodePoolTypeSystemShared:
 		return c.syncSharedNodePool(service.Namespace, info.path)
@@ -32,7 +32,7 @@ func (c *Controller) syncCurrentNodePool(service *latticev1.Service) (*latticev1
 // syncDedicatedNodePool checks to see if a node pool dedicated to running a single instance of this
 // service on each node exists. if it does not exist, it creates one. if it does exist, it updates it if the update
 // is one that can be done in place (e.g. scaling), or creates a new one if it requires a rolling update (e.g. instance type change)
-func (c *Controller) syncDedicatedNodePool(service *latticev1.Service, numInstances int32, instanceType string) (*latticev1.NodePool, error) {
+func (c *Controller) syncDedicatedNodePool(service *latticev1.Service, spec latticev1.NodePoolSpec) (*latticev1.NodePool, error) {
 	nodePool, err := c.dedicatedNodePool(service)
 	if err != nil {
 		return nil, err
@@ -40,7 +40,7 @@ func (c *Controller) syncDedicatedNodePool(service *latticev1.Service, numInstan
 
 	// We didn't find a matching dedicated node, so we'll make a new one
 	if nodePool == nil {
-		nodePool, err := c.createNewDedicatedNodePool(service, numInstances, instanceType)
+		nodePool, err := c.createNewDedicatedNodePool(service, spec)
 		if err != nil {
 			return nil, err
 		}
@@ -48,7 +48,7 @@ func (c *Controller) syncDedicatedNodePool(service *latticev1.Service, numInstan
 		return nodePool, nil
 	}
 
-	return c.syncExistingDedicatedNodePool(nodePool, numInstances, instanceType)
+	return c.syncExistingDedicatedNodePool(nodePool, spec)
 }
 
 func (c *Controller) syncSharedNodePool(namespace string, path tree.PathSubcomponent) (*latticev1.NodePool, error) {
@@ -80,8 +80,13 @@ func (c *Controller) syncSharedNodePool(namespace string, path tree.PathSubcompo
 	return nodePools[0], nil
 }
 
-func (c *Controller) syncExistingDedicatedNodePool(nodePool *latticev1.NodePool, numInstances int32, instanceType string) (*latticev1.NodePool, error) {
-	spec := nodePoolSpec(numInstances, instanceType)
+func (c *Controller) syncExistingDedicatedNodePool(nodePool *latticev1.NodePool, spec latticev1.NodePoolSpec) (*latticev1.NodePool, error) {
+	// keep the node pool's override, if it has one, until the next deploy removes it
+	spec, err := nodePool.OverriddenSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
 	return c.updateNodePoolSpec(nodePool, spec)
 }
 
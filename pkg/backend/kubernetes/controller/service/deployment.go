@@ -6,6 +6,8 @@ import (
 	"reflect"
 
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubesecretprovider "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/secretprovider"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	"github.com/mlab-lattice/lattice/pkg/util/sha1"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -289,6 +291,39 @@ func (c *Controller) podTemplateSpec(
 	deploymentLabels map[string]string,
 	nodePool *latticev1.NodePool,
 ) (*corev1.PodTemplateSpec, error) {
+	// secrets stored in providers other than kubernetes have to be mirrored into
+	// the namespace before the pods referencing them can start
+	systemID, err := kubeutil.SystemID(c.namespacePrefix, service.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = kubesecretprovider.EnsureSecretsMirrored(
+		c.namespacePrefix,
+		c.kubeClient,
+		c.secretProviders,
+		systemID,
+		kubesecretprovider.WorkloadSecretRefs(&service.Spec.Definition),
+	)
+	if err != nil {
+		err := fmt.Errorf("error mirroring secrets for %v: %v", service.Description(c.namespacePrefix), err)
+		return nil, err
+	}
+
+	err = kubesecretprovider.EnsureWorkloadFiles(
+		c.namespacePrefix,
+		c.kubeClient,
+		c.secretProviders,
+		systemID,
+		service.Name,
+		*controllerRef(service),
+		&service.Spec.Definition,
+	)
+	if err != nil {
+		err := fmt.Errorf("error ensuring files for %v: %v", service.Description(c.namespacePrefix), err)
+		return nil, err
+	}
+
 	podTemplateSpec, err := c.untransformedPodTemplateSpec(service, name, deploymentLabels, nodePool)
 	if err != nil {
 		return nil, err
@@ -386,9 +421,9 @@ func (c *Controller) untransformedPodTemplateSpec(
 		},
 	}
 
-	tolerations := []corev1.Toleration{nodePool.Toleration(nodePoolEpoch)}
+	tolerations := nodePool.WorkloadTolerations(nodePoolEpoch, service.Spec.Definition.Preemptible)
 
-	return latticev1.PodTemplateSpecForV1Workload(
+	podTemplateSpec, err := latticev1.PodTemplateSpecForV1Workload(
 		&service.Spec.Definition,
 		path,
 		c.latticeID,
@@ -402,6 +437,19 @@ func (c *Controller) untransformedPodTemplateSpec(
 		affinity,
 		tolerations,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// if a secret referenced by the service was rotated, copy the annotation to the pods
+	// so that the change to the template rolls out new pods using the new secret value
+	if rotated, ok := service.Annotations[latticev1.SecretsRotatedAnnotationKey]; ok {
+		podTemplateSpec.Annotations = map[string]string{
+			latticev1.SecretsRotatedAnnotationKey: rotated,
+		}
+	}
+
+	return podTemplateSpec, nil
 }
 
 func (c *Controller) isDeploymentSpecUpdated(service *latticev1.Service, spec *appsv1.DeploymentSpec) (bool, string, error) {
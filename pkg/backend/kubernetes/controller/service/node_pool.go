@@ -209,8 +209,8 @@ func (c *Controller) updateNodePoolSpec(nodePool *latticev1.NodePool, desiredSpe
 	return result, nil
 }
 
-func (c *Controller) createNewDedicatedNodePool(service *latticev1.Service, numInstances int32, instanceType string) (*latticev1.NodePool, error) {
-	nodePool := newDedicatedNodePool(service, numInstances, instanceType)
+func (c *Controller) createNewDedicatedNodePool(service *latticev1.Service, spec latticev1.NodePoolSpec) (*latticev1.NodePool, error) {
+	nodePool := newDedicatedNodePool(service, spec)
 	result, err := c.latticeClient.LatticeV1().NodePools(service.Namespace).Create(nodePool)
 	if err != nil {
 		err := fmt.Errorf(
@@ -224,9 +224,7 @@ func (c *Controller) createNewDedicatedNodePool(service *latticev1.Service, numI
 	return result, nil
 }
 
-func newDedicatedNodePool(service *latticev1.Service, numInstances int32, instanceType string) *latticev1.NodePool {
-	spec := nodePoolSpec(numInstances, instanceType)
-
+func newDedicatedNodePool(service *latticev1.Service, spec latticev1.NodePoolSpec) *latticev1.NodePool {
 	nodePool := &latticev1.NodePool{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            uuid.NewV4().String(),
@@ -240,10 +238,15 @@ func newDedicatedNodePool(service *latticev1.Service, numInstances int32, instan
 	return nodePool
 }
 
-func nodePoolSpec(numInstances int32, instanceType string) latticev1.NodePoolSpec {
+func nodePoolSpec(info nodePoolInfo) latticev1.NodePoolSpec {
 	return latticev1.NodePoolSpec{
-		NumInstances: numInstances,
-		InstanceType: instanceType,
+		NumInstances: info.numInstances,
+		InstanceType: info.instanceType,
+		MinInstances: info.minInstances,
+		MaxInstances: info.maxInstances,
+
+		InstanceTypes: info.instanceTypes,
+		Spot:          info.spot,
 	}
 }
 
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -22,8 +24,13 @@ type nodePoolInfo struct {
 	// dedicated node pool options
 	instanceType string
 	numInstances int32
+	minInstances int32
+	maxInstances int32
 	perInstance  bool
 
+	instanceTypes []string
+	spot          *definitionv1.NodePoolSpot
+
 	// shared system node pool options
 	path tree.PathSubcomponent
 }
@@ -61,7 +68,12 @@ func (c *Controller) nodePoolInfo(service *latticev1.Service) (nodePoolInfo, err
 			nodePoolType: latticev1.NodePoolTypeServiceDedicated,
 			instanceType: definition.NodePool.NodePool.InstanceType,
 			numInstances: definition.NodePool.NodePool.NumInstances,
+			minInstances: definition.NodePool.NodePool.MinInstances,
+			maxInstances: definition.NodePool.NodePool.MaxInstances,
 			perInstance:  false,
+
+			instanceTypes: definition.NodePool.NodePool.InstanceTypes,
+			spot:          definition.NodePool.NodePool.Spot,
 		}
 		return info, nil
 	}
@@ -277,6 +289,11 @@ func (c *Controller) updateServiceStatus(
 	availableInstances, updatedInstances, staleInstances, terminatingInstances int32,
 	ports map[int32]string,
 ) (*latticev1.Service, error) {
+	instances, err := serviceNodePoolInstances(service, updatedInstances+staleInstances)
+	if err != nil {
+		return nil, err
+	}
+
 	status := latticev1.ServiceStatus{
 		ObservedGeneration: service.Generation,
 
@@ -290,6 +307,8 @@ func (c *Controller) updateServiceStatus(
 		TerminatingInstances: terminatingInstances,
 
 		Ports: ports,
+
+		InstanceHours: service.Status.InstanceHours.Record(instances, time.Now()),
 	}
 
 	if reflect.DeepEqual(service.Status, status) {
@@ -308,6 +327,25 @@ func (c *Controller) updateServiceStatus(
 	return result, nil
 }
 
+// serviceNodePoolInstances returns the service's instances on each of the node
+// pools in its namespace, so that its instance-hours can be recorded. Each of
+// the node pools the service is running on is counted as running all of its
+// instances.
+func serviceNodePoolInstances(service *latticev1.Service, numInstances int32) (map[string]int32, error) {
+	annotation, err := service.NodePoolAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make(map[string]int32)
+	nodePools, _ := annotation.NodePools(service.Namespace)
+	for nodePool := range nodePools {
+		instances[nodePool] = numInstances
+	}
+
+	return instances, nil
+}
+
 func (c *Controller) addFinalizer(service *latticev1.Service) (*latticev1.Service, error) {
 	// Check to see if the finalizer already exists. If so nothing needs to be done.
 	for _, finalizer := range service.Finalizers {
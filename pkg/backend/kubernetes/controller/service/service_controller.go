@@ -12,6 +12,7 @@ import (
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -52,6 +53,8 @@ type Controller struct {
 	staticServiceMeshOptions *servicemesh.Options
 	serviceMesh              servicemesh.Interface
 
+	secretProviders *secretprovider.Providers
+
 	configLister       latticelisters.ConfigLister
 	configListerSynced cache.InformerSynced
 	configSetChan      chan struct{}
@@ -86,6 +89,7 @@ func NewController(
 	internalDNSDomain string,
 	cloudProviderOptions *cloudprovider.Options,
 	serviceMeshOptions *servicemesh.Options,
+	secretProviders *secretprovider.Providers,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
@@ -106,6 +110,8 @@ func NewController(
 		staticCloudProviderOptions: cloudProviderOptions,
 		staticServiceMeshOptions:   serviceMeshOptions,
 
+		secretProviders: secretProviders,
+
 		configSetChan: make(chan struct{}),
 
 		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "service"),
@@ -89,7 +89,7 @@ func (c *Controller) newServiceMesh() error {
 		return err
 	}
 
-	serviceMesh, err := servicemesh.NewServiceMesh(options)
+	serviceMesh, err := servicemesh.NewServiceMesh(c.kubeClient, options)
 	if err != nil {
 		return err
 	}
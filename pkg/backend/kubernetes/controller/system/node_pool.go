@@ -223,7 +223,11 @@ func (c *Controller) updateNodePool(
 	nodePool *latticev1.NodePool,
 	definition *definitionv1.NodePool,
 ) (*latticev1.NodePool, error) {
-	spec := nodePoolSpec(definition)
+	// keep the node pool's override, if it has one, until the next deploy removes it
+	spec, err := nodePool.OverriddenSpec(nodePoolSpec(definition))
+	if err != nil {
+		return nil, err
+	}
 
 	if !c.nodePoolNeedsUpdate(subcomponent, nodePool, spec) {
 		return nodePool, nil
@@ -268,6 +272,11 @@ func nodePoolSpec(definition *definitionv1.NodePool) latticev1.NodePoolSpec {
 	return latticev1.NodePoolSpec{
 		InstanceType: definition.InstanceType,
 		NumInstances: definition.NumInstances,
+		MinInstances: definition.MinInstances,
+		MaxInstances: definition.MaxInstances,
+
+		InstanceTypes: definition.InstanceTypes,
+		Spot:          definition.Spot,
 	}
 }
 
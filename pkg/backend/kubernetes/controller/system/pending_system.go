@@ -34,6 +34,7 @@ func (c *Controller) syncPendingSystem(system *latticev1.System) error {
 		system,
 		latticev1.SystemStateStable,
 		system.Status.Services,
+		system.Status.NodePools,
 	)
 	return err
 }
@@ -41,7 +41,7 @@ func (c *Controller) syncSystemStatus(
 		state = latticev1.SystemStateDegraded
 	}
 
-	_, err = c.updateSystemStatus(system, state, services)
+	_, err = c.updateSystemStatus(system, state, services, nodePools)
 	return err
 }
 
@@ -113,11 +113,28 @@ func (c *Controller) updateSystemStatus(
 	system *latticev1.System,
 	state latticev1.SystemState,
 	services map[tree.Path]latticev1.SystemStatusService,
+	nodePools map[tree.PathSubcomponent]latticev1.SystemStatusNodePool,
 ) (*latticev1.System, error) {
+	// the instance-hours of the services and node pools are only needed on
+	// them, so they aren't copied into the system's status, which would
+	// otherwise grow with their history
+	statusServices := make(map[tree.Path]latticev1.SystemStatusService)
+	for path, service := range services {
+		service.InstanceHours = nil
+		statusServices[path] = service
+	}
+
+	statusNodePools := make(map[tree.PathSubcomponent]latticev1.SystemStatusNodePool)
+	for subcomponent, nodePool := range nodePools {
+		nodePool.InstanceHours = nil
+		statusNodePools[subcomponent] = nodePool
+	}
+
 	status := latticev1.SystemStatus{
 		ObservedGeneration: system.Generation,
 		State:              state,
-		Services:           services,
+		Services:           statusServices,
+		NodePools:          statusNodePools,
 	}
 
 	if reflect.DeepEqual(system.Status, status) {
@@ -5,9 +5,11 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeimagepolicy "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/imagepolicy"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/imagepolicy"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -29,6 +31,11 @@ func (c *Controller) syncAcceptedDeploy(deploy *latticev1.Deploy) error {
 		if err != nil {
 			return err
 		}
+
+		// the deploy failed before its build could be created
+		if build == nil {
+			return nil
+		}
 	}
 
 	build, err := c.addBuildOwnerReference(deploy, build)
@@ -56,7 +63,7 @@ func (c *Controller) syncAcceptedDeploy(deploy *latticev1.Deploy) error {
 
 	// if the build failed, fail the deploy as well
 	case latticev1.BuildStateFailed:
-		_, err := c.updateDeployStatus(
+		deploy, err := c.updateDeployStatus(
 			deploy,
 			latticev1.DeployStateFailed,
 			fmt.Sprintf("%v failed", build.Description(c.namespacePrefix)),
@@ -93,19 +100,35 @@ func (c *Controller) syncAcceptedBuildlessDeploy(deploy *latticev1.Deploy) (*lat
 		return deploy, build, nil
 	}
 
-	// Otherwise create the build and update the deploy's status
-	build := &latticev1.Build{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: deploy.Namespace,
-			Name:      uuid.NewV4().String(),
-		},
-		Spec: latticev1.BuildSpec{
-			Version: deploy.Spec.Version,
-			Path:    deploy.Spec.Path,
-		},
+	// Don't create the build if it would exceed the system's quota
+	reason, err := c.verifyBuildQuota(deploy.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if reason != "" {
+		now := metav1.Now()
+		deploy, err := c.updateDeployStatus(
+			deploy,
+			latticev1.DeployStateFailed,
+			reason,
+			nil,
+			nil,
+			nil,
+			nil,
+			deploy.Status.StartTimestamp,
+			&now,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// release the deploy's lock so other deploys can deploy along this path
+		return deploy, nil, c.releaseDeployLock(deploy)
 	}
 
-	build, err := c.latticeClient.LatticeV1().Builds(deploy.Namespace).Create(build)
+	// Otherwise create the build and update the deploy's status
+	build, err := c.createDeployBuild(deploy)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -129,6 +152,22 @@ func (c *Controller) syncAcceptedBuildlessDeploy(deploy *latticev1.Deploy) (*lat
 	return deploy, build, nil
 }
 
+// createDeployBuild creates a build of the deploy's path or version.
+func (c *Controller) createDeployBuild(deploy *latticev1.Deploy) (*latticev1.Build, error) {
+	build := &latticev1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: deploy.Namespace,
+			Name:      uuid.NewV4().String(),
+		},
+		Spec: latticev1.BuildSpec{
+			Version: deploy.Spec.Version,
+			Path:    deploy.Spec.Path,
+		},
+	}
+
+	return c.latticeClient.LatticeV1().Builds(deploy.Namespace).Create(build)
+}
+
 func (c *Controller) syncAcceptedDeployWithSuccessfulBuild(deploy *latticev1.Deploy, build *latticev1.Build) error {
 	system, err := c.getSystem(deploy.Namespace)
 	if err != nil {
@@ -143,6 +182,58 @@ func (c *Controller) syncAcceptedDeployWithSuccessfulBuild(deploy *latticev1.Dep
 		)
 	}
 
+	// the system's image policy may have changed since the build was run,
+	// so check the build's images again before deploying them
+	err = c.verifyImagePolicy(system, build)
+	if err != nil {
+		if _, ok := err.(*imagepolicy.ViolationError); !ok {
+			return err
+		}
+
+		return c.failDeployWithBuild(deploy, build, err.Error())
+	}
+
+	// apply the system's default container resources to the build's definition
+	// and check its containers against the system's maximum
+	definition, reason, err := c.enforceContainerResources(system, build)
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		return c.failDeployWithBuild(deploy, build, reason)
+	}
+
+	// check the node pools' instance bounds before they are created or
+	// updated, so that a misconfigured node pool isn't autoscaled
+	if err := definition.V1().ValidateNodePools(); err != nil {
+		return c.failDeployWithBuild(deploy, build, fmt.Sprintf("invalid node pool: %v", err))
+	}
+
+	// if the build specifies a path, isolate to that path
+	path := tree.RootPath()
+	if build.Spec.Path != nil {
+		path = *build.Spec.Path
+	}
+
+	// the system's quota may have changed since the deploy was created, so
+	// check it against the definition the deploy results in
+	reason, err = c.verifyQuota(system, path, definition)
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		return c.failDeployWithBuild(deploy, build, reason)
+	}
+
+	// record what the deploy will do to the system's cost before its
+	// definition replaces the system's
+	deploy, err = c.updateDeployMonthlyCostDelta(deploy, system, path, definition)
+	if err != nil {
+		return err
+	}
+
 	// if we're redeploying the whole system, update the system's version
 	if build.Status.Path.IsRoot() {
 		system, err = c.updateSystemLabels(system, build.Status.Version)
@@ -238,18 +329,12 @@ func (c *Controller) syncAcceptedDeployWithSuccessfulBuild(deploy *latticev1.Dep
 		spec.WorkloadBuildArtifacts = latticev1.NewSystemSpecWorkloadBuildArtifacts()
 	}
 
-	// if the build specifies a path, isolate to that path
-	path := tree.RootPath()
-	if build.Spec.Path != nil {
-		path = *build.Spec.Path
-	}
-
 	// replace the system's definition at path with the build's resolved definition at path
-	spec.Definition.ReplacePrefix(path, build.Status.Definition)
+	spec.Definition.ReplacePrefix(path, definition)
 	// replace the workload build artifacts at path with the artifacts that we just seeded
 	spec.WorkloadBuildArtifacts.ReplacePrefix(path, artifacts)
 
-	_, err = c.updateSystemSpec(system, spec)
+	_, err = c.updateSystemDefinition(system, spec, path)
 	if err != nil {
 		return err
 	}
@@ -267,3 +352,78 @@ func (c *Controller) syncAcceptedDeployWithSuccessfulBuild(deploy *latticev1.Dep
 	)
 	return err
 }
+
+// updateSystemDefinition updates the system's spec to the deployed spec, then
+// clears the overrides of the node pools within path. Node pools that were
+// overridden since the last deploy drifted from the system's definition, so
+// they are returned to it only once the definition they return to is the
+// deployed one. If clearing the overrides fails the deploy stays accepted, so
+// they are cleared when it is retried.
+func (c *Controller) updateSystemDefinition(
+	system *latticev1.System,
+	spec *latticev1.SystemSpec,
+	path tree.Path,
+) (*latticev1.System, error) {
+	system, err := c.updateSystemSpec(system, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.clearNodePoolOverrides(system, path); err != nil {
+		return nil, err
+	}
+
+	return system, nil
+}
+
+// failDeployWithBuild fails the deploy of the successful build and releases its
+// lock so other deploys can deploy along its path.
+func (c *Controller) failDeployWithBuild(deploy *latticev1.Deploy, build *latticev1.Build, message string) error {
+	now := metav1.Now()
+	deploy, err := c.updateDeployStatus(
+		deploy,
+		latticev1.DeployStateFailed,
+		message,
+		nil,
+		deploy.Status.Build,
+		build.Status.Path,
+		build.Status.Version,
+		deploy.Status.StartTimestamp,
+		&now,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.releaseDeployLock(deploy)
+}
+
+// verifyImagePolicy checks the images pulled by the build's workloads and the images
+// it produced against the system's image policy, if it has one.
+func (c *Controller) verifyImagePolicy(system *latticev1.System, build *latticev1.Build) error {
+	verifier, err := kubeimagepolicy.SystemVerifier(system, c.secretProviders)
+	if err != nil {
+		return err
+	}
+
+	if verifier == nil {
+		return nil
+	}
+
+	if err := verifier.VerifyDefinition(build.Status.Definition); err != nil {
+		return err
+	}
+
+	for _, containerBuild := range build.Status.ContainerBuildStatuses {
+		if containerBuild.Artifacts == nil {
+			continue
+		}
+
+		err := verifier.VerifyBuiltImage(containerBuild.Artifacts.DockerImageFQN, containerBuild.Artifacts.DockerImageDigest)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package systemlifecycle
+
+import (
+	"fmt"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/containerresources"
+	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+)
+
+// enforceContainerResources returns a copy of the build's definition with the
+// system's default container resources applied. If the resources of any of its
+// containers are invalid or more than the system's maximum, it instead returns
+// the reason the deploy has to fail.
+func (c *Controller) enforceContainerResources(
+	system *latticev1.System,
+	build *latticev1.Build,
+) (*resolver.ResolutionTree, string, error) {
+	systemResources, err := system.ContainerResourcesAnnotation()
+	if err != nil {
+		return nil, "", err
+	}
+
+	definition := build.Status.Definition.DeepCopy()
+	if err := containerresources.Enforce(definition, systemResources); err != nil {
+		return nil, fmt.Sprintf("invalid container resources: %v", err), nil
+	}
+
+	return definition, "", nil
+}
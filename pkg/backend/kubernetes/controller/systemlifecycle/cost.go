@@ -0,0 +1,50 @@
+package systemlifecycle
+
+import (
+	"fmt"
+	"strconv"
+
+	kubecost "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/cost"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+)
+
+// updateDeployMonthlyCostDelta records the projected change in the system's
+// monthly cost from deploying the build's definition at path on the deploy.
+// If the lattice does not price any instance types, nothing is recorded.
+func (c *Controller) updateDeployMonthlyCostDelta(
+	deploy *latticev1.Deploy,
+	system *latticev1.System,
+	path tree.Path,
+	buildDefinition *resolver.ResolutionTree,
+) (*latticev1.Deploy, error) {
+	model, err := kubecost.Model(c.latticeClient, c.namespacePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(model.InstanceTypePrices) == 0 {
+		return deploy, nil
+	}
+
+	delta := model.MonthlyCostDelta(system.Spec.Definition, path, buildDefinition)
+	deltaStr := strconv.FormatFloat(delta, 'f', 2, 64)
+	if current, ok := deploy.Annotations[latticev1.DeployMonthlyCostDeltaAnnotationKey]; ok && current == deltaStr {
+		return deploy, nil
+	}
+
+	// Copy so the shared cache isn't mutated
+	deploy = deploy.DeepCopy()
+	if deploy.Annotations == nil {
+		deploy.Annotations = make(map[string]string)
+	}
+	deploy.Annotations[latticev1.DeployMonthlyCostDeltaAnnotationKey] = deltaStr
+
+	result, err := c.latticeClient.LatticeV1().Deploys(deploy.Namespace).Update(deploy)
+	if err != nil {
+		return nil, fmt.Errorf("error updating %v monthly cost delta: %v", deploy.Description(c.namespacePrefix), err)
+	}
+
+	return result, nil
+}
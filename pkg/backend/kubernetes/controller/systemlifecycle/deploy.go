@@ -9,6 +9,7 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func (c *Controller) updateDeployStatus(
@@ -52,25 +53,107 @@ func (c *Controller) updateDeployStatus(
 	return result, nil
 }
 
-func (c *Controller) acquireDeployLock(deploy *latticev1.Deploy, path tree.Path) error {
-	namespace, err := c.kubeNamespaceLister.Get(deploy.Namespace)
+// acquireDeployLock acquires the lifecycle lock for the deploy's path and
+// records it on the deploy, so that a controller that restarts or takes over
+// leadership can rebuild the locks before processing any other deploys.
+func (c *Controller) acquireDeployLock(deploy *latticev1.Deploy, path tree.Path) (*latticev1.Deploy, error) {
+	systemID, err := c.lifecycleActionsSystemID(deploy.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.lifecycleActions.AcquireDeploy(systemID, deploy.V1ID(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.updateDeployLifecycleLockAnnotation(deploy, &path)
+	if err != nil {
+		// don't hold onto a lock that isn't recorded, since it would be
+		// lost if the controller restarted
+		c.lifecycleActions.ReleaseDeploy(systemID, deploy.V1ID())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// restoreDeployLock acquires the lifecycle lock recorded on the deploy.
+func (c *Controller) restoreDeployLock(deploy *latticev1.Deploy, path tree.Path) error {
+	systemID, err := c.lifecycleActionsSystemID(deploy.Namespace)
 	if err != nil {
 		return err
 	}
 
-	// TODO(kevindrosendahl): switch to using actual system ID once they're UUIDs
-	uniqueSystemIdentifier := v1.SystemID(namespace.UID)
-	return c.lifecycleActions.AcquireDeploy(uniqueSystemIdentifier, deploy.V1ID(), path)
+	return c.lifecycleActions.AcquireDeploy(systemID, deploy.V1ID(), path)
 }
 
 func (c *Controller) releaseDeployLock(deploy *latticev1.Deploy) error {
-	namespace, err := c.kubeNamespaceLister.Get(deploy.Namespace)
+	systemID, err := c.lifecycleActionsSystemID(deploy.Namespace)
 	if err != nil {
 		return err
 	}
 
-	// TODO(kevindrosendahl): switch to using actual system ID once they're UUIDs
-	uniqueSystemIdentifier := v1.SystemID(namespace.UID)
-	c.lifecycleActions.ReleaseDeploy(uniqueSystemIdentifier, deploy.V1ID())
+	c.lifecycleActions.ReleaseDeploy(systemID, deploy.V1ID())
+
+	_, err = c.updateDeployLifecycleLockAnnotation(deploy, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.enqueueQueuedDeploys(deploy.Namespace)
+}
+
+// enqueueQueuedDeploys enqueues the queued deploys in the namespace so that
+// they can attempt to acquire locks that were released.
+func (c *Controller) enqueueQueuedDeploys(namespace string) error {
+	deploys, err := c.deployLister.Deploys(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	for _, deploy := range deploys {
+		if deploy.Status.State == latticev1.DeployStateQueued {
+			c.enqueueDeploy(deploy)
+		}
+	}
+
 	return nil
 }
+
+func (c *Controller) updateDeployLifecycleLockAnnotation(deploy *latticev1.Deploy, path *tree.Path) (*latticev1.Deploy, error) {
+	current, ok := deploy.Annotations[latticev1.DeployLifecycleLockAnnotationKey]
+	if path == nil && !ok || path != nil && ok && current == path.String() {
+		return deploy, nil
+	}
+
+	// Copy so the shared cache isn't mutated
+	deploy = deploy.DeepCopy()
+	if path == nil {
+		delete(deploy.Annotations, latticev1.DeployLifecycleLockAnnotationKey)
+	} else {
+		if deploy.Annotations == nil {
+			deploy.Annotations = make(map[string]string)
+		}
+		deploy.Annotations[latticev1.DeployLifecycleLockAnnotationKey] = path.String()
+	}
+
+	result, err := c.latticeClient.LatticeV1().Deploys(deploy.Namespace).Update(deploy)
+	if err != nil {
+		return nil, fmt.Errorf("error updating %v lifecycle lock: %v", deploy.Description(c.namespacePrefix), err)
+	}
+
+	return result, nil
+}
+
+// lifecycleActionsSystemID returns the identifier the system in the namespace
+// is locked under.
+func (c *Controller) lifecycleActionsSystemID(namespace string) (v1.SystemID, error) {
+	kubeNamespace, err := c.kubeNamespaceLister.Get(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	// TODO(kevindrosendahl): switch to using actual system ID once they're UUIDs
+	return v1.SystemID(kubeNamespace.UID), nil
+}
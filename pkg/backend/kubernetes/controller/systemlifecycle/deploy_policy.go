@@ -0,0 +1,119 @@
+package systemlifecycle
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/deploypolicy"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// admitDeploy checks the deploy against the system's deploy policy. Deploys
+// created outside of the policy wait in the awaiting approval state until they
+// have been approved by enough approvers, and fail if any approver rejects them.
+// admitDeploy returns whether the deploy can proceed.
+func (c *Controller) admitDeploy(deploy *latticev1.Deploy) (bool, error) {
+	system, err := c.getSystem(deploy.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	policy, err := system.DeployPolicyAnnotation()
+	if err != nil {
+		return false, err
+	}
+
+	if policy == nil {
+		return true, nil
+	}
+
+	// the policy is evaluated at the time the deploy was created so that
+	// deploys don't leave the change window while waiting to be synced
+	required, reason, err := deploypolicy.RequiresApproval(policy, deploy.CreationTimestamp.Time)
+	if err != nil {
+		return false, err
+	}
+
+	if !required {
+		return true, nil
+	}
+
+	approvals, err := deploy.ApprovalsAnnotation()
+	if err != nil {
+		return false, err
+	}
+
+	approved, rejection := deploypolicy.Decide(policy, approvals)
+	if rejection != nil {
+		message := fmt.Sprintf("rejected by %v", rejection.User)
+		if rejection.Comment != "" {
+			message = fmt.Sprintf("%v: %v", message, rejection.Comment)
+		}
+
+		now := metav1.Now()
+		_, err := c.updateDeployStatus(
+			deploy,
+			latticev1.DeployStateFailed,
+			message,
+			nil,
+			deploy.Status.Build,
+			nil,
+			nil,
+			&now,
+			&now,
+		)
+		return false, err
+	}
+
+	if approved {
+		return true, nil
+	}
+
+	buildID, err := c.awaitingApprovalBuild(deploy)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.updateDeployStatus(
+		deploy,
+		latticev1.DeployStateAwaitingApproval,
+		fmt.Sprintf("awaiting approval: %v", reason),
+		nil,
+		buildID,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	return false, err
+}
+
+// awaitingApprovalBuild returns the build of a deploy of a path or version that
+// is awaiting approval. The build is created before the deploy is approved so
+// that the API can show approvers the deploy's monthly cost delta once the build
+// has resolved its definition. If the build would exceed the system's quota it
+// isn't created until the deploy is approved.
+func (c *Controller) awaitingApprovalBuild(deploy *latticev1.Deploy) (*v1.BuildID, error) {
+	if deploy.Spec.Build != nil || deploy.Status.Build != nil {
+		return deploy.Status.Build, nil
+	}
+
+	reason, err := c.verifyBuildQuota(deploy.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason != "" {
+		return nil, nil
+	}
+
+	build, err := c.createDeployBuild(deploy)
+	if err != nil {
+		return nil, err
+	}
+
+	buildID := v1.BuildID(build.Name)
+	return &buildID, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -15,26 +16,25 @@ func (c *Controller) syncInProgressDeploy(deploy *latticev1.Deploy) error {
 	}
 
 	// Check to see if the system controller has processed updates to its Spec.
-	// If it hasn't, the system.Status.State is not up to date. Return no error
+	// If it hasn't, the system's Status is not up to date. Return no error
 	// and wait until the System has been updated to resync.
 	if !system.UpdateProcessed() {
 		return nil
 	}
 
-	var state latticev1.DeployState
-	switch system.Status.State {
-	case latticev1.SystemStateUpdating, latticev1.SystemStateScaling:
-		// Still in progress, nothing more to do
-		return nil
-
-	case latticev1.SystemStateStable:
-		state = latticev1.DeployStateSucceeded
+	path := tree.RootPath()
+	if deploy.Status.Path != nil {
+		path = *deploy.Status.Path
+	}
 
-	case latticev1.SystemStateDegraded:
-		state = latticev1.DeployStateFailed
+	state, err := deployState(system, path)
+	if err != nil {
+		return err
+	}
 
-	default:
-		return fmt.Errorf("%v in unexpected state %v", system.Description(), system.Status.State)
+	if state == latticev1.DeployStateInProgress {
+		// Still in progress, nothing more to do
+		return nil
 	}
 
 	// need to update the deploy's status before releasing the lock. if we released the lock
@@ -59,3 +59,75 @@ func (c *Controller) syncInProgressDeploy(deploy *latticev1.Deploy) error {
 
 	return c.releaseDeployLock(deploy)
 }
+
+// deployState returns the state of a deploy to the path based only on the
+// services and node pools under the path, so that deploys to other paths
+// in the system do not affect its outcome. Jobs have no status of their own
+// and are applied once the system controller has processed the system's spec.
+func deployState(system *latticev1.System, path tree.Path) (latticev1.DeployState, error) {
+	failed := false
+	inProgress := false
+
+	for servicePath, status := range system.Status.Services {
+		if !servicePath.HasPrefix(path) {
+			continue
+		}
+
+		if status.ObservedGeneration < status.Generation {
+			inProgress = true
+			continue
+		}
+
+		switch status.State {
+		case latticev1.ServiceStateFailed:
+			failed = true
+
+		case latticev1.ServiceStateUpdating, latticev1.ServiceStateScaling,
+			latticev1.ServiceStatePending, latticev1.ServiceStateDeleting:
+			inProgress = true
+
+		case latticev1.ServiceStateStable:
+			// nothing to do
+
+		default:
+			return "", fmt.Errorf("service %v had unexpected state: %v", servicePath.String(), status.State)
+		}
+	}
+
+	for subcomponent, status := range system.Status.NodePools {
+		if !subcomponent.Path().HasPrefix(path) {
+			continue
+		}
+
+		if status.ObservedGeneration < status.Generation {
+			inProgress = true
+			continue
+		}
+
+		switch status.State {
+		case latticev1.NodePoolStateFailed:
+			failed = true
+
+		case latticev1.NodePoolStateUpdating, latticev1.NodePoolStateScaling,
+			latticev1.NodePoolStatePending, latticev1.NodePoolStateDeleting:
+			inProgress = true
+
+		case latticev1.NodePoolStateStable:
+			// nothing to do
+
+		default:
+			return "", fmt.Errorf("node pool %v had unexpected state: %v", subcomponent.String(), status.State)
+		}
+	}
+
+	// A failed workload takes priority over one that is still in progress
+	if failed {
+		return latticev1.DeployStateFailed, nil
+	}
+
+	if inProgress {
+		return latticev1.DeployStateInProgress, nil
+	}
+
+	return latticev1.DeployStateSucceeded, nil
+}
@@ -0,0 +1,168 @@
+package systemlifecycle
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	fakelattice "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned/fake"
+	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNamespacePrefix = "lattice"
+	testSystemID        = v1.SystemID("system")
+)
+
+func testDeploy(name string, path tree.Path) *latticev1.Deploy {
+	return &latticev1.Deploy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kubeutil.SystemNamespace(testNamespacePrefix, testSystemID),
+			Annotations: map[string]string{
+				latticev1.DeployLifecycleLockAnnotationKey: path.String(),
+			},
+		},
+		Status: latticev1.DeployStatus{
+			State: latticev1.DeployStateInProgress,
+			Path:  &path,
+		},
+	}
+}
+
+func testSystem(services map[tree.Path]latticev1.ServiceState) *latticev1.System {
+	system := &latticev1.System{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       string(testSystemID),
+			Namespace:  kubeutil.InternalNamespace(testNamespacePrefix),
+			Generation: 1,
+		},
+		Status: latticev1.SystemStatus{
+			ObservedGeneration: 1,
+			State:              latticev1.SystemStateDegraded,
+			Services:           make(map[tree.Path]latticev1.SystemStatusService),
+		},
+	}
+
+	for path, state := range services {
+		system.Status.Services[path] = latticev1.SystemStatusService{
+			Generation: 1,
+			ServiceStatus: latticev1.ServiceStatus{
+				ObservedGeneration: 1,
+				State:              state,
+			},
+		}
+	}
+
+	return system
+}
+
+func TestSyncInProgressDeploy(t *testing.T) {
+	teamA := tree.RootPath().Child("team-a")
+	teamB := tree.RootPath().Child("team-b")
+
+	tests := []struct {
+		name     string
+		services map[tree.Path]latticev1.ServiceState
+		expected map[string]latticev1.DeployState
+	}{
+		{
+			name: "one deploy degraded",
+			services: map[tree.Path]latticev1.ServiceState{
+				teamA.Child("api"): latticev1.ServiceStateStable,
+				teamB.Child("api"): latticev1.ServiceStateFailed,
+			},
+			expected: map[string]latticev1.DeployState{
+				"deploy-a": latticev1.DeployStateSucceeded,
+				"deploy-b": latticev1.DeployStateFailed,
+			},
+		},
+		{
+			name: "one deploy updating",
+			services: map[tree.Path]latticev1.ServiceState{
+				teamA.Child("api"): latticev1.ServiceStateStable,
+				teamB.Child("api"): latticev1.ServiceStateUpdating,
+			},
+			expected: map[string]latticev1.DeployState{
+				"deploy-a": latticev1.DeployStateSucceeded,
+				"deploy-b": latticev1.DeployStateInProgress,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deploys := []*latticev1.Deploy{
+				testDeploy("deploy-a", teamA),
+				testDeploy("deploy-b", teamB),
+			}
+			system := testSystem(test.services)
+			namespace := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: kubeutil.SystemNamespace(testNamespacePrefix, testSystemID),
+					UID:  "namespace-uid",
+				},
+			}
+
+			latticeClient := fakelattice.NewSimpleClientset(deploys[0], deploys[1], system)
+			kubeClient := fake.NewSimpleClientset(namespace)
+
+			informers := latticeinformers.NewSharedInformerFactory(latticeClient, 0)
+			kubeInformers := kubeinformers.NewSharedInformerFactory(kubeClient, 0)
+
+			c := NewController(
+				testNamespacePrefix,
+				kubeClient,
+				latticeClient,
+				nil,
+				informers.Lattice().V1().Deploys(),
+				informers.Lattice().V1().Teardowns(),
+				informers.Lattice().V1().Systems(),
+				informers.Lattice().V1().Builds(),
+				informers.Lattice().V1().ContainerBuilds(),
+				kubeInformers.Core().V1().Namespaces(),
+			)
+
+			require.NoError(t, informers.Lattice().V1().Systems().Informer().GetStore().Add(system))
+			require.NoError(t, kubeInformers.Core().V1().Namespaces().Informer().GetStore().Add(namespace))
+			for _, deploy := range deploys {
+				require.NoError(t, informers.Lattice().V1().Deploys().Informer().GetStore().Add(deploy))
+				require.NoError(t, c.lifecycleActions.AcquireDeploy(v1.SystemID(namespace.UID), deploy.V1ID(), *deploy.Status.Path))
+			}
+
+			for _, deploy := range deploys {
+				require.NoError(t, c.syncInProgressDeploy(deploy))
+			}
+
+			for _, deploy := range deploys {
+				result, err := latticeClient.LatticeV1().Deploys(deploy.Namespace).Get(deploy.Name, metav1.GetOptions{})
+				require.NoError(t, err)
+
+				state := test.expected[deploy.Name]
+				require.Equal(t, state, result.Status.State, deploy.Name)
+
+				// the lock on the deploy's path is only released once the deploy completes
+				err = c.lifecycleActions.AcquireDeploy(v1.SystemID(namespace.UID), "next", *deploy.Status.Path)
+				if state == latticev1.DeployStateInProgress {
+					require.Error(t, err, deploy.Name)
+					continue
+				}
+
+				require.NoError(t, err, deploy.Name)
+				require.NotNil(t, result.Status.CompletionTimestamp, deploy.Name)
+				require.NotContains(t, result.Annotations, latticev1.DeployLifecycleLockAnnotationKey, deploy.Name)
+				c.lifecycleActions.ReleaseDeploy(v1.SystemID(namespace.UID), "next")
+			}
+		})
+	}
+}
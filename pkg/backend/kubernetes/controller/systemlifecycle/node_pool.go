@@ -0,0 +1,75 @@
+package systemlifecycle
+
+import (
+	"fmt"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clearNodePoolOverrides removes the overrides of the system's node pools that
+// are within path, so that deploying path returns them to the system's definition.
+func (c *Controller) clearNodePoolOverrides(system *latticev1.System, path tree.Path) error {
+	namespace := system.ResourceNamespace(c.namespacePrefix)
+	nodePools, err := c.latticeClient.LatticeV1().NodePools(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing node pools for %v: %v", system.Description(), err)
+	}
+
+	for i := range nodePools.Items {
+		nodePool := &nodePools.Items[i]
+		if _, ok := nodePool.Annotations[latticev1.NodePoolOverrideAnnotationKey]; !ok {
+			continue
+		}
+
+		nodePoolPath, ok, err := c.nodePoolPath(nodePool)
+		if err != nil {
+			return err
+		}
+
+		if !ok || !nodePoolPath.HasPrefix(path) {
+			continue
+		}
+
+		delete(nodePool.Annotations, latticev1.NodePoolOverrideAnnotationKey)
+		_, err = c.latticeClient.LatticeV1().NodePools(namespace).Update(nodePool)
+		if err != nil {
+			return fmt.Errorf("error clearing override of %v: %v", nodePool.Description(c.namespacePrefix), err)
+		}
+	}
+
+	return nil
+}
+
+// nodePoolPath returns the path of the system shared node pool, or of the
+// service the node pool is dedicated to.
+func (c *Controller) nodePoolPath(nodePool *latticev1.NodePool) (tree.Path, bool, error) {
+	if serviceID, ok := nodePool.ServiceDedicatedIDLabel(); ok {
+		service, err := c.latticeClient.LatticeV1().Services(nodePool.Namespace).Get(serviceID, metav1.GetOptions{})
+		if err != nil {
+			// the service has been deleted, so the node pool is about to be as well
+			if errors.IsNotFound(err) {
+				return "", false, nil
+			}
+
+			return "", false, fmt.Errorf("error getting service for %v: %v", nodePool.Description(c.namespacePrefix), err)
+		}
+
+		path, err := service.PathLabel()
+		if err != nil {
+			return "", false, err
+		}
+
+		return path, true, nil
+	}
+
+	path, ok, err := nodePool.SystemSharedPathLabel()
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	return path.Path(), true, nil
+}
@@ -0,0 +1,145 @@
+package systemlifecycle
+
+import (
+	"fmt"
+	"testing"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	fakelattice "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned/fake"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testOverride = `{"numInstances":5,"definitionNumInstances":2}`
+
+func testSharedNodePool(name string, path tree.Path) *latticev1.NodePool {
+	return &latticev1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kubeutil.SystemNamespace(testNamespacePrefix, testSystemID),
+			Labels: map[string]string{
+				latticev1.NodePoolSystemSharedPathLabelKey: path.ToDomain(),
+				latticev1.NodePoolSystemSharedNameLabelKey: name,
+			},
+			Annotations: map[string]string{
+				latticev1.NodePoolOverrideAnnotationKey: testOverride,
+			},
+		},
+	}
+}
+
+func testDedicatedNodePool(name string, service *latticev1.Service) *latticev1.NodePool {
+	return &latticev1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: service.Namespace,
+			Labels: map[string]string{
+				latticev1.NodePoolServiceDedicatedIDLabelKey: service.Name,
+			},
+			Annotations: map[string]string{
+				latticev1.NodePoolOverrideAnnotationKey: testOverride,
+			},
+		},
+	}
+}
+
+func overridden(t *testing.T, c *Controller, nodePool *latticev1.NodePool) bool {
+	result, err := c.latticeClient.LatticeV1().NodePools(nodePool.Namespace).Get(nodePool.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, ok := result.Annotations[latticev1.NodePoolOverrideAnnotationKey]
+	return ok
+}
+
+func TestUpdateSystemDefinition(t *testing.T) {
+	teamA := tree.RootPath().Child("team-a")
+	teamB := tree.RootPath().Child("team-b")
+
+	service := &latticev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "service",
+			Namespace: kubeutil.SystemNamespace(testNamespacePrefix, testSystemID),
+			Labels: map[string]string{
+				latticev1.ServicePathLabelKey: teamA.Child("api").ToDomain(),
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		failUpdate bool
+		expected   map[string]bool
+	}{
+		{
+			name: "spec updated",
+			expected: map[string]bool{
+				"team-a-shared":    false,
+				"team-a-dedicated": false,
+				"team-b-shared":    true,
+			},
+		},
+		{
+			name:       "spec update failed",
+			failUpdate: true,
+			expected: map[string]bool{
+				"team-a-shared":    true,
+				"team-a-dedicated": true,
+				"team-b-shared":    true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodePools := []*latticev1.NodePool{
+				testSharedNodePool("team-a-shared", teamA),
+				testDedicatedNodePool("team-a-dedicated", service),
+				testSharedNodePool("team-b-shared", teamB),
+			}
+			system := testSystem(nil)
+
+			latticeClient := fakelattice.NewSimpleClientset(
+				system,
+				service,
+				nodePools[0],
+				nodePools[1],
+				nodePools[2],
+			)
+			if test.failUpdate {
+				latticeClient.PrependReactor(
+					"update",
+					"systems",
+					func(kubetesting.Action) (bool, runtime.Object, error) {
+						return true, nil, fmt.Errorf("conflict")
+					},
+				)
+			}
+
+			c := &Controller{
+				namespacePrefix: testNamespacePrefix,
+				latticeClient:   latticeClient,
+			}
+
+			spec := system.Spec.DeepCopy()
+			spec.WorkloadBuildArtifacts = latticev1.NewSystemSpecWorkloadBuildArtifacts()
+
+			_, err := c.updateSystemDefinition(system, spec, teamA)
+			if test.failUpdate {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for _, nodePool := range nodePools {
+				require.Equal(t, test.expected[nodePool.Name], overridden(t, c, nodePool), nodePool.Name)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package systemlifecycle
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
@@ -11,6 +13,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/golang/glog"
 )
@@ -66,6 +69,15 @@ func (c *Controller) syncPendingDeploy(deploy *latticev1.Deploy) error {
 		}
 	}
 
+	// deploys created outside of the system's deploy policy have to be approved
+	// before they can run. queued deploys have already been admitted.
+	if deploy.Status.State != latticev1.DeployStateQueued {
+		admitted, err := c.admitDeploy(deploy)
+		if err != nil || !admitted {
+			return err
+		}
+	}
+
 	// get the deploy's path so we can attempt to acquire the proper lifecycle lock
 	var buildID *v1.BuildID
 	var path tree.Path
@@ -118,16 +130,48 @@ func (c *Controller) syncPendingDeploy(deploy *latticev1.Deploy) error {
 		}
 
 	case deploy.Spec.Path != nil:
+		// the deploy's build may have been created while it awaited approval
+		buildID = deploy.Status.Build
 		path = *deploy.Spec.Path
 
 	case deploy.Spec.Version != nil:
+		buildID = deploy.Status.Build
 		path = tree.RootPath()
 		version = deploy.Spec.Version
 	}
 
+	// deploys that would conflict with queued deploys that were created before them
+	// are queued behind them so that the deploys are run in order
+	deploys, err := c.deployLister.Deploys(deploy.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	ahead := deploy.QueuedAhead(path, deploys)
+	if len(ahead) > 0 {
+		var ids []string
+		for _, d := range ahead {
+			ids = append(ids, d.Name)
+		}
+		sort.Strings(ids)
+
+		_, err := c.updateDeployStatus(
+			deploy,
+			latticev1.DeployStateQueued,
+			fmt.Sprintf("queued behind deploys %v", strings.Join(ids, ", ")),
+			nil,
+			buildID,
+			&path,
+			version,
+			nil,
+			nil,
+		)
+		return err
+	}
+
 	// attempt to acquire the proper lifecycle lock for the deploy. if we fail due to a locking conflict,
-	// fail the deploy.
-	err = c.acquireDeployLock(deploy, path)
+	// queue the deploy until the conflicting deploys or teardown release their locks.
+	result, err := c.acquireDeployLock(deploy, path)
 	if err != nil {
 		_, ok := err.(*syncutil.ConflictingLifecycleActionError)
 		if !ok {
@@ -136,20 +180,20 @@ func (c *Controller) syncPendingDeploy(deploy *latticev1.Deploy) error {
 
 		_, err = c.updateDeployStatus(
 			deploy,
-			latticev1.DeployStateFailed,
-			fmt.Sprintf("unable to acquire lifecycle lock: %v", err.Error()),
+			latticev1.DeployStateQueued,
+			fmt.Sprintf("waiting for lifecycle lock: %v", err.Error()),
 			nil,
 			buildID,
 			&path,
 			version,
-			&now,
-			&now,
+			nil,
+			nil,
 		)
 		return err
 	}
 
 	_, err = c.updateDeployStatus(
-		deploy,
+		result,
 		latticev1.DeployStateAccepted,
 		"",
 		nil,
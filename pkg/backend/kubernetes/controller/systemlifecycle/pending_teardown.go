@@ -11,7 +11,7 @@ import (
 
 func (c *Controller) syncPendingTeardown(teardown *latticev1.Teardown) error {
 	now := metav1.Now()
-	err := c.acquireTeardownLock(teardown)
+	result, err := c.acquireTeardownLock(teardown)
 	if err != nil {
 		_, ok := err.(*syncutil.ConflictingLifecycleActionError)
 		if !ok {
@@ -28,7 +28,7 @@ func (c *Controller) syncPendingTeardown(teardown *latticev1.Teardown) error {
 	}
 
 	_, err = c.updateTeardownStatus(
-		teardown,
+		result,
 		latticev1.TeardownStateInProgress,
 		"",
 		&now,
@@ -0,0 +1,102 @@
+package systemlifecycle
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// verifyQuota checks the system's definition with the build's definition deployed
+// at path against the system's quota, if it has one. If the deploy would exceed
+// the quota or its resources could not be determined, verifyQuota returns the
+// reason the deploy has to fail.
+func (c *Controller) verifyQuota(
+	system *latticev1.System,
+	path tree.Path,
+	buildDefinition *resolver.ResolutionTree,
+) (string, error) {
+	systemQuota, err := system.QuotaAnnotation()
+	if err != nil {
+		return "", err
+	}
+
+	if systemQuota == nil {
+		return "", nil
+	}
+
+	// only check the newly deployed definition's requests so that services
+	// deployed before the quota limited cpu or memory don't fail the deploy
+	if err := quota.CheckDefinitionRequests(systemQuota, buildDefinition); err != nil {
+		return fmt.Sprintf("deployed definition %v", err), nil
+	}
+
+	definition := resolver.NewResolutionTree()
+	if system.Spec.Definition != nil {
+		definition = system.Spec.Definition.DeepCopy()
+	}
+
+	definition.ReplacePrefix(path, buildDefinition)
+
+	usage, err := quota.DefinitionUsage(definition)
+	if err != nil {
+		return fmt.Sprintf("could not determine the resources of the deployed definition: %v", err), nil
+	}
+
+	if err := quota.Check(systemQuota, usage); err != nil {
+		if _, ok := err.(*quota.ExceededError); !ok {
+			return "", err
+		}
+
+		return fmt.Sprintf("deployed definition %v", err), nil
+	}
+
+	return "", nil
+}
+
+// verifyBuildQuota checks whether another build can be created in the namespace
+// under the system's quota, if it has one. If it can't, verifyBuildQuota returns
+// the reason why.
+func (c *Controller) verifyBuildQuota(namespace string) (string, error) {
+	system, err := c.getSystem(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	systemQuota, err := system.QuotaAnnotation()
+	if err != nil {
+		return "", err
+	}
+
+	if systemQuota == nil {
+		return "", nil
+	}
+
+	builds, err := c.buildLister.Builds(namespace).List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+
+	usage := v1.SystemUsage{Builds: 1}
+	for _, build := range builds {
+		switch build.Status.State {
+		case latticev1.BuildStatePending, latticev1.BuildStateAccepted, latticev1.BuildStateRunning:
+			usage.Builds++
+		}
+	}
+
+	if err := quota.Check(systemQuota, usage); err != nil {
+		if _, ok := err.(*quota.ExceededError); !ok {
+			return "", err
+		}
+
+		return fmt.Sprintf("build %v", err), nil
+	}
+
+	return "", nil
+}
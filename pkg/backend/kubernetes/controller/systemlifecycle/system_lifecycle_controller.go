@@ -9,6 +9,7 @@ import (
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions/lattice/v1"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 	syncutil "github.com/mlab-lattice/lattice/pkg/util/sync"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -33,6 +34,8 @@ type Controller struct {
 	kubeClient    kubeclientset.Interface
 	latticeClient latticeclientset.Interface
 
+	secretProviders *secretprovider.Providers
+
 	lifecycleActions       *syncutil.LifecycleActionManager
 	lifecycleActionsSynced chan struct{}
 
@@ -62,6 +65,7 @@ func NewController(
 	namespacePrefix string,
 	kubeClient kubeclientset.Interface,
 	latticeClient latticeclientset.Interface,
+	secretProviders *secretprovider.Providers,
 	deployInformer latticeinformers.DeployInformer,
 	teardownInformer latticeinformers.TeardownInformer,
 	systemInformer latticeinformers.SystemInformer,
@@ -75,6 +79,8 @@ func NewController(
 		kubeClient:    kubeClient,
 		latticeClient: latticeClient,
 
+		secretProviders: secretProviders,
+
 		lifecycleActions:       syncutil.NewLifecycleActionManager(),
 		lifecycleActionsSynced: make(chan struct{}),
 
@@ -196,6 +202,9 @@ func (c *Controller) enqueueTeardown(teardown *latticev1.Teardown) {
 	c.teardownQueue.Add(key)
 }
 
+// syncLifecycleActions rebuilds the lifecycle locks recorded on deploys and
+// teardowns that have not completed. It must be run before any deploys or
+// teardowns are processed so that they can't acquire conflicting locks.
 func (c *Controller) syncLifecycleActions() error {
 	deploys, err := c.deployLister.List(labels.Everything())
 	if err != nil {
@@ -203,36 +212,32 @@ func (c *Controller) syncLifecycleActions() error {
 	}
 
 	for _, deploy := range deploys {
-		if deploy.Status.State != latticev1.DeployStateInProgress {
+		// completed deploys may still have a lock recorded if the controller
+		// stopped before releasing it, it will be removed when they're synced
+		if deploy.Status.State == latticev1.DeployStateSucceeded || deploy.Status.State == latticev1.DeployStateFailed {
 			continue
 		}
 
-		var path tree.Path
-		switch {
-		case deploy.Spec.Build != nil:
-			build, err := c.buildLister.Builds(deploy.Namespace).Get(string(*deploy.Status.Build))
+		path, err := deploy.LifecycleLockAnnotation()
+		if err != nil {
+			return fmt.Errorf("error getting lifecycle lock for %v: %v", deploy.Description(c.namespacePrefix), err)
+		}
+
+		// deploys that were in progress before locks were recorded still hold one
+		if path == nil && deploy.Status.State == latticev1.DeployStateInProgress {
+			path, err = c.inProgressDeployPath(deploy)
 			if err != nil {
 				return err
 			}
+		}
 
-			switch {
-			case build.Spec.Path != nil:
-				path = *build.Spec.Path
-
-			case build.Spec.Version != nil:
-				path = tree.RootPath()
-			}
-
-		case deploy.Spec.Path != nil:
-			path = *deploy.Spec.Path
-
-		case deploy.Spec.Version != nil:
-			path = tree.RootPath()
+		if path == nil {
+			continue
 		}
 
 		glog.V(5).Infof("attempting to acquire lock for %v at path %v", deploy.Description(c.namespacePrefix), path.String())
 
-		err = c.acquireDeployLock(deploy, path)
+		err = c.restoreDeployLock(deploy, *path)
 		if err != nil {
 			return fmt.Errorf(
 				"error attempting to acquire lock for %v %v: %v",
@@ -249,13 +254,18 @@ func (c *Controller) syncLifecycleActions() error {
 	}
 
 	for _, teardown := range teardowns {
-		if teardown.Status.State != latticev1.TeardownStateInProgress {
+		if teardown.Status.State == latticev1.TeardownStateSucceeded || teardown.Status.State == latticev1.TeardownStateFailed {
+			continue
+		}
+
+		// teardowns that were in progress before locks were recorded still hold one
+		if !teardown.LifecycleLockAnnotation() && teardown.Status.State != latticev1.TeardownStateInProgress {
 			continue
 		}
 
 		glog.V(5).Infof("attempting to acquire lock for %v", teardown.Description(c.namespacePrefix))
 
-		err = c.acquireTeardownLock(teardown)
+		err = c.restoreTeardownLock(teardown)
 		if err != nil {
 			return fmt.Errorf(
 				"error attempting to acquire lock for %v: %v",
@@ -269,6 +279,34 @@ func (c *Controller) syncLifecycleActions() error {
 	return nil
 }
 
+func (c *Controller) inProgressDeployPath(deploy *latticev1.Deploy) (*tree.Path, error) {
+	var path tree.Path
+	switch {
+	case deploy.Spec.Build != nil:
+		build, err := c.buildLister.Builds(deploy.Namespace).Get(string(*deploy.Status.Build))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case build.Spec.Path != nil:
+			path = *build.Spec.Path
+
+		default:
+			// version, source and commit builds are builds of the whole system
+			path = tree.RootPath()
+		}
+
+	case deploy.Spec.Path != nil:
+		path = *deploy.Spec.Path
+
+	case deploy.Spec.Version != nil:
+		path = tree.RootPath()
+	}
+
+	return &path, nil
+}
+
 func (c *Controller) runDeployWorker() {
 	// hot loop until we're told to stop.  processNextWorkItem will
 	// automatically wait until there's work available, so we don't worry
@@ -352,7 +390,10 @@ func (c *Controller) syncDeploy(key string) error {
 	switch deploy.Status.State {
 	case latticev1.DeployStateSucceeded, latticev1.DeployStateFailed:
 		glog.V(4).Infof("%v already completed", deploy.Description(c.namespacePrefix))
-		return nil
+
+		// the controller may have stopped after completing the deploy
+		// but before releasing its lock
+		return c.releaseDeployLock(deploy)
 
 	case latticev1.DeployStateInProgress:
 		return c.syncInProgressDeploy(deploy)
@@ -360,7 +401,7 @@ func (c *Controller) syncDeploy(key string) error {
 	case latticev1.DeployStateAccepted:
 		return c.syncAcceptedDeploy(deploy)
 
-	case latticev1.DeployStatePending:
+	case latticev1.DeployStatePending, latticev1.DeployStateAwaitingApproval, latticev1.DeployStateQueued:
 		return c.syncPendingDeploy(deploy)
 
 	default:
@@ -395,7 +436,10 @@ func (c *Controller) syncTeardown(key string) error {
 	switch teardown.Status.State {
 	case latticev1.TeardownStateSucceeded, latticev1.TeardownStateFailed:
 		glog.V(4).Infof("%v already completed", teardown.Description(c.namespacePrefix))
-		return nil
+
+		// the controller may have stopped after completing the teardown
+		// but before releasing its lock
+		return c.releaseTeardownLock(teardown)
 
 	case latticev1.TeardownStateInProgress:
 		return c.syncInProgressTeardown(teardown)
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"reflect"
 
-	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -43,25 +43,80 @@ func (c *Controller) updateTeardownStatus(
 	return result, nil
 }
 
-func (c *Controller) acquireTeardownLock(teardown *latticev1.Teardown) error {
-	namespace, err := c.kubeNamespaceLister.Get(teardown.Namespace)
+// acquireTeardownLock acquires the lifecycle lock for the teardown's system and
+// records it on the teardown, so that a controller that restarts or takes over
+// leadership can rebuild the locks before processing any other teardowns.
+func (c *Controller) acquireTeardownLock(teardown *latticev1.Teardown) (*latticev1.Teardown, error) {
+	systemID, err := c.lifecycleActionsSystemID(teardown.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.lifecycleActions.AcquireTeardown(systemID, teardown.V1ID())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.updateTeardownLifecycleLockAnnotation(teardown, true)
+	if err != nil {
+		// don't hold onto a lock that isn't recorded, since it would be
+		// lost if the controller restarted
+		c.lifecycleActions.ReleaseTeardown(systemID, teardown.V1ID())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// restoreTeardownLock acquires the lifecycle lock recorded on the teardown.
+func (c *Controller) restoreTeardownLock(teardown *latticev1.Teardown) error {
+	systemID, err := c.lifecycleActionsSystemID(teardown.Namespace)
 	if err != nil {
 		return err
 	}
 
-	// TODO(kevindrosendahl): switch to using actual system ID once they're UUIDs
-	uniqueSystemIdentifier := v1.SystemID(namespace.UID)
-	return c.lifecycleActions.AcquireTeardown(uniqueSystemIdentifier, teardown.V1ID())
+	return c.lifecycleActions.AcquireTeardown(systemID, teardown.V1ID())
 }
 
 func (c *Controller) releaseTeardownLock(teardown *latticev1.Teardown) error {
-	namespace, err := c.kubeNamespaceLister.Get(teardown.Namespace)
+	systemID, err := c.lifecycleActionsSystemID(teardown.Namespace)
+	if err != nil {
+		return err
+	}
+
+	c.lifecycleActions.ReleaseTeardown(systemID, teardown.V1ID())
+
+	_, err = c.updateTeardownLifecycleLockAnnotation(teardown, false)
 	if err != nil {
 		return err
 	}
 
-	// TODO(kevindrosendahl): switch to using actual system ID once they're UUIDs
-	uniqueSystemIdentifier := v1.SystemID(namespace.UID)
-	c.lifecycleActions.ReleaseTeardown(uniqueSystemIdentifier, teardown.V1ID())
-	return nil
+	return c.enqueueQueuedDeploys(teardown.Namespace)
+}
+
+func (c *Controller) updateTeardownLifecycleLockAnnotation(
+	teardown *latticev1.Teardown,
+	locked bool,
+) (*latticev1.Teardown, error) {
+	if teardown.LifecycleLockAnnotation() == locked {
+		return teardown, nil
+	}
+
+	// Copy so the shared cache isn't mutated
+	teardown = teardown.DeepCopy()
+	if locked {
+		if teardown.Annotations == nil {
+			teardown.Annotations = make(map[string]string)
+		}
+		teardown.Annotations[latticev1.TeardownLifecycleLockAnnotationKey] = tree.RootPath().String()
+	} else {
+		delete(teardown.Annotations, latticev1.TeardownLifecycleLockAnnotationKey)
+	}
+
+	result, err := c.latticeClient.LatticeV1().Teardowns(teardown.Namespace).Update(teardown)
+	if err != nil {
+		return nil, fmt.Errorf("error updating lifecycle lock for %v: %v", teardown.Description(c.namespacePrefix), err)
+	}
+
+	return result, nil
 }
@@ -0,0 +1,33 @@
+package cost
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/constants"
+	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/cost"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Model returns the cost model configured by the lattice's config. If the
+// config does not have one, the returned model has no prices, so every
+// instance type is reported as unpriced.
+func Model(latticeClient latticeclientset.Interface, namespacePrefix string) (*cost.Model, error) {
+	config, err := latticeClient.LatticeV1().Configs(kubeutil.InternalNamespace(namespacePrefix)).Get(
+		constants.ConfigGlobal,
+		metav1.GetOptions{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Spec.Cost == nil {
+		return &cost.Model{}, nil
+	}
+
+	model := &cost.Model{
+		InstanceTypePrices: config.Spec.Cost.InstanceTypePrices,
+		CPUAllocation:      config.Spec.Cost.CPUAllocation,
+	}
+	return model, nil
+}
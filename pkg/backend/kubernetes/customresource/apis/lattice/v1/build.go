@@ -49,6 +49,14 @@ func (b *Build) Description(namespacePrefix string) string {
 type BuildSpec struct {
 	Version *v1.Version `json:"version"`
 	Path    *tree.Path  `json:"path"`
+
+	// Source is set if the system is built from a build source
+	// rather than the system's definition repository.
+	Source *v1.BuildSource `json:"source,omitempty"`
+
+	// Commit is set if the system is built from a commit of
+	// the system's definition repository.
+	Commit *string `json:"commit,omitempty"`
 }
 
 type BuildStatus struct {
@@ -76,6 +84,11 @@ type BuildStatus struct {
 type BuildStatusWorkload struct {
 	MainContainer v1.ContainerBuildID            `json:"mainContainer"`
 	Sidecars      map[string]v1.ContainerBuildID `json:"sidecars"`
+
+	// MainContainerReuse and SidecarReuse explain why each container's
+	// container build was reused or run.
+	MainContainerReuse *v1.ContainerBuildReuse           `json:"mainContainerReuse,omitempty"`
+	SidecarReuse       map[string]v1.ContainerBuildReuse `json:"sidecarReuse,omitempty"`
 }
 
 type BuildState string
@@ -23,6 +23,10 @@ type ConfigSpec struct {
 	CloudProvider  ConfigCloudProvider  `json:"cloudProvider"`
 	ContainerBuild ConfigContainerBuild `json:"containerBuild"`
 	ServiceMesh    ConfigServiceMesh    `json:"serviceMesh"`
+
+	// If set, systems' costs are estimated from the prices of their
+	// node pools' instances.
+	Cost *ConfigCost `json:"cost,omitempty"`
 }
 
 type ConfigCloudProvider struct {
@@ -38,9 +42,30 @@ type ConfigCloudProviderAWS struct {
 	KeyName         string `json:"keyName"`
 }
 
+type ConfigCost struct {
+	// InstanceTypePrices are the hourly prices of each instance type.
+	InstanceTypePrices map[string]float64 `json:"instanceTypePrices"`
+
+	// CPUAllocation is the fraction of a node pool's cost that is allocated
+	// to the services running on it by the CPU they request, and the rest
+	// is allocated by the memory they request. Defaults to 0.5.
+	CPUAllocation *float64 `json:"cpuAllocation,omitempty"`
+}
+
 type ConfigContainerBuild struct {
 	Builder        ConfigComponentBuildBuilder        `json:"builderConfig"`
 	DockerArtifact ConfigComponentBuildDockerArtifact `json:"dockerConfig"`
+
+	// If set, container builds share a cache of git repositories
+	// and docker layers.
+	Cache *ConfigContainerBuildCache `json:"cache,omitempty"`
+}
+
+type ConfigContainerBuildCache struct {
+	// Repository that the images whose layers are reused by later builds
+	// are stored in. If empty, the docker artifact's Repository is used,
+	// or if that is also empty, lattice-build-cache.
+	Repository string `json:"repository,omitempty"`
 }
 
 type ConfigComponentBuildBuilder struct {
@@ -48,6 +73,12 @@ type ConfigComponentBuildBuilder struct {
 
 	// Version of the docker API used by the build node docker daemons
 	DockerAPIVersion string `json:"dockerApiVersion"`
+
+	// Image builder used for docker image and command builds. Either docker, the
+	// default, or daemonless. The daemonless image builder does not require the
+	// build node's docker daemon, but requires images to be pushed. Docker builds
+	// always use the docker daemon.
+	ImageBuilder string `json:"imageBuilder,omitempty"`
 }
 
 type ConfigComponentBuildDockerArtifact struct {
@@ -69,13 +100,31 @@ type ConfigComponentBuildDockerArtifact struct {
 }
 
 type ConfigServiceMesh struct {
-	Envoy *ConfigServiceMeshEnvoy `json:"envoy"`
+	Envoy *ConfigServiceMeshEnvoy `json:"envoy,omitempty"`
+	None  *ConfigServiceMeshNone  `json:"none,omitempty"`
 }
 
 type ConfigServiceMeshEnvoy struct {
 	PrepareImage string `json:"prepareImage"`
 	Image        string `json:"image"`
 	XDSAPIImage  string `json:"xdsApiImage"`
+
+	// If set, envoy will report traces to the collector.
+	Tracing *ConfigServiceMeshEnvoyTracing `json:"tracing,omitempty"`
+}
+
+type ConfigServiceMeshEnvoyTracing struct {
+	// Address (host:port) of a collector that accepts spans via the Zipkin API,
+	// for example Zipkin, Jaeger, or an OpenTelemetry collector with the zipkin receiver.
+	CollectorAddress string `json:"collectorAddress"`
+
+	// Endpoint spans are sent to on the collector. Defaults to the Zipkin v1 API endpoint.
+	CollectorEndpoint string `json:"collectorEndpoint,omitempty"`
+}
+
+// ConfigServiceMeshNone configures the service mesh that relies on
+// kube-proxy rather than sidecars. It currently has no options.
+type ConfigServiceMeshNone struct {
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
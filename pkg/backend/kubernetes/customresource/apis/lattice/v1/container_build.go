@@ -3,6 +3,7 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
@@ -22,8 +23,20 @@ var (
 
 	ContainerBuildFailureInfoAnnotationKey       = fmt.Sprintf("containerbuild.%v/failure-info", GroupName)
 	ContainerBuildLastObservedPhaseAnnotationKey = fmt.Sprintf("containerbuild.%v/last-observed-phase", GroupName)
+	ContainerBuildCacheInfoAnnotationKey         = fmt.Sprintf("containerbuild.%v/cache-info", GroupName)
+	ContainerBuildProvenanceAnnotationKey        = fmt.Sprintf("containerbuild.%v/provenance", GroupName)
 )
 
+// ContainerBuildSBOMConfigMapKey is the key of the SBOM in the ConfigMap
+// named by ContainerBuildSBOMConfigMapName.
+const ContainerBuildSBOMConfigMapKey = "sbom.json"
+
+// ContainerBuildSBOMConfigMapName returns the name of the ConfigMap holding the
+// container build's SBOM, which is too large to store on the container build.
+func ContainerBuildSBOMConfigMapName(id v1.ContainerBuildID) string {
+	return fmt.Sprintf("%v-sbom", id)
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -54,6 +67,36 @@ func (b *ContainerBuild) FailureInfoAnnotation() (*v1.ContainerBuildFailureInfo,
 	return &failureInfo, nil
 }
 
+func (b *ContainerBuild) CacheInfoAnnotation() (*v1.ContainerBuildCacheInfo, error) {
+	infoStr, ok := b.Annotations[ContainerBuildCacheInfoAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	cacheInfo := v1.ContainerBuildCacheInfo{}
+	err := json.Unmarshal([]byte(infoStr), &cacheInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cacheInfo, nil
+}
+
+func (b *ContainerBuild) ProvenanceAnnotation() (*v1.ContainerBuildProvenance, error) {
+	provenanceStr, ok := b.Annotations[ContainerBuildProvenanceAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	provenance := v1.ContainerBuildProvenance{}
+	err := json.Unmarshal([]byte(provenanceStr), &provenance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &provenance, nil
+}
+
 func (b *ContainerBuild) LastObservedPhaseAnnotation() (v1.ContainerBuildPhase, bool) {
 	phase, ok := b.Annotations[ContainerBuildLastObservedPhaseAnnotationKey]
 	return v1.ContainerBuildPhase(phase), ok
@@ -83,6 +126,9 @@ type ContainerBuildStatus struct {
 
 	Artifacts         *ContainerBuildArtifacts `json:"artifacts,omitempty"`
 	LastObservedPhase *v1.ContainerBuildPhase  `json:"lastObservedPhase,omitempty"`
+
+	Cache      *v1.ContainerBuildCacheInfo  `json:"cache,omitempty"`
+	Provenance *v1.ContainerBuildProvenance `json:"provenance,omitempty"`
 }
 
 type ComponentBuildState string
@@ -96,7 +142,22 @@ const (
 )
 
 type ContainerBuildArtifacts struct {
-	DockerImageFQN string `json:"dockerImageFqn"`
+	DockerImageFQN    string `json:"dockerImageFqn"`
+	DockerImageDigest string `json:"dockerImageDigest,omitempty"`
+}
+
+// DockerImage returns the reference to the image that workloads should run. If the
+// image's digest is known the reference pins it, since the tag could be moved.
+func (a *ContainerBuildArtifacts) DockerImage() string {
+	if a.DockerImageDigest == "" {
+		return a.DockerImageFQN
+	}
+
+	name := a.DockerImageFQN
+	if i := strings.LastIndex(name, ":"); i != -1 && !strings.Contains(name[i:], "/") {
+		name = name[:i]
+	}
+	return fmt.Sprintf("%v@%v", name, a.DockerImageDigest)
 }
 
 type WorkloadContainerBuildArtifacts struct {
@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
@@ -13,6 +15,12 @@ import (
 var (
 	DeployKind     = SchemeGroupVersion.WithKind("Deploy")
 	DeployListKind = SchemeGroupVersion.WithKind("DeployList")
+
+	DeployBuildSourceAnnotationKey      = fmt.Sprintf("deploy.%v/build-source", GroupName)
+	DeployLifecycleLockAnnotationKey    = fmt.Sprintf("deploy.%v/lifecycle-lock", GroupName)
+	DeployCreatorAnnotationKey          = fmt.Sprintf("deploy.%v/creator", GroupName)
+	DeployApprovalsAnnotationKey        = fmt.Sprintf("deploy.%v/approvals", GroupName)
+	DeployMonthlyCostDeltaAnnotationKey = fmt.Sprintf("deploy.%v/monthly-cost-delta", GroupName)
 )
 
 // +genclient
@@ -29,6 +37,110 @@ func (d *Deploy) V1ID() v1.DeployID {
 	return v1.DeployID(d.Name)
 }
 
+// BuildSourceAnnotation returns the build source that the deploy's build was
+// built from, if it was built from one.
+func (d *Deploy) BuildSourceAnnotation() (*v1.BuildSource, error) {
+	sourceStr, ok := d.Annotations[DeployBuildSourceAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	source := v1.BuildSource{}
+	err := json.Unmarshal([]byte(sourceStr), &source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &source, nil
+}
+
+// LifecycleLockAnnotation returns the path the deploy holds the lifecycle
+// lock for, if it holds one.
+func (d *Deploy) LifecycleLockAnnotation() (*tree.Path, error) {
+	pathStr, ok := d.Annotations[DeployLifecycleLockAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	path, err := tree.NewPath(pathStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &path, nil
+}
+
+// CreatorAnnotation returns the user that created the deploy, or the empty
+// string if it was not created by an authenticated user.
+func (d *Deploy) CreatorAnnotation() string {
+	return d.Annotations[DeployCreatorAnnotationKey]
+}
+
+// ApprovalsAnnotation returns the approvals and rejections of the deploy
+// that were made while it was awaiting approval.
+func (d *Deploy) ApprovalsAnnotation() ([]v1.DeployApproval, error) {
+	approvalsStr, ok := d.Annotations[DeployApprovalsAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var approvals []v1.DeployApproval
+	if err := json.Unmarshal([]byte(approvalsStr), &approvals); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", DeployApprovalsAnnotationKey, err)
+	}
+
+	return approvals, nil
+}
+
+// MonthlyCostDeltaAnnotation returns the projected change in the system's
+// monthly cost from the deploy's definition, if it has been estimated.
+func (d *Deploy) MonthlyCostDeltaAnnotation() (*float64, error) {
+	deltaStr, ok := d.Annotations[DeployMonthlyCostDeltaAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	delta, err := strconv.ParseFloat(deltaStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", DeployMonthlyCostDeltaAnnotationKey, err)
+	}
+
+	return &delta, nil
+}
+
+// QueuedAhead returns the queued deploys in deploys that have to run before
+// the deploy can deploy path. Queued deploys run in the order they were
+// created, but only wait for the deploys whose paths overlap with theirs,
+// so that deploys to disjoint subtrees of the system can run in parallel.
+func (d *Deploy) QueuedAhead(path tree.Path, deploys []*Deploy) []*Deploy {
+	var ahead []*Deploy
+	for _, other := range deploys {
+		if other.Name == d.Name || other.Status.State != DeployStateQueued || other.Status.Path == nil {
+			continue
+		}
+
+		if !other.createdBefore(d) {
+			continue
+		}
+
+		if !other.Status.Path.HasPrefix(path) && !path.HasPrefix(*other.Status.Path) {
+			continue
+		}
+
+		ahead = append(ahead, other)
+	}
+
+	return ahead
+}
+
+func (d *Deploy) createdBefore(other *Deploy) bool {
+	if d.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return d.Name < other.Name
+	}
+
+	return d.CreationTimestamp.Before(&other.CreationTimestamp)
+}
+
 func (d *Deploy) Description(namespacePrefix string) string {
 	systemID, err := kubeutil.SystemID(namespacePrefix, d.Namespace)
 	if err != nil {
@@ -80,11 +192,13 @@ type DeployStatus struct {
 type DeployState string
 
 const (
-	DeployStatePending    DeployState = ""
-	DeployStateAccepted   DeployState = "accepted"
-	DeployStateInProgress DeployState = "in progress"
-	DeployStateSucceeded  DeployState = "succeeded"
-	DeployStateFailed     DeployState = "failed"
+	DeployStatePending          DeployState = ""
+	DeployStateAwaitingApproval DeployState = "awaiting approval"
+	DeployStateQueued           DeployState = "queued"
+	DeployStateAccepted         DeployState = "accepted"
+	DeployStateInProgress       DeployState = "in progress"
+	DeployStateSucceeded        DeployState = "succeeded"
+	DeployStateFailed           DeployState = "failed"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1,14 +1,18 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +38,30 @@ var (
 	// workloads that run on a node pool.
 	NodePoolWorkloadAnnotationKey = fmt.Sprintf("workload.%v/node-pools", GroupName)
 
+	// NodePoolOverrideAnnotationKey is the key of the annotation holding the JSON
+	// encoded v1.NodePoolOverride of a node pool that has been changed outside of
+	// a deploy.
+	NodePoolOverrideAnnotationKey = fmt.Sprintf("node-pool.%v/override", GroupName)
+
+	// NodePoolDrainingNodeAnnotationKey is the key of the annotation holding the
+	// name of the node an autoscaled node pool is draining before removing it.
+	NodePoolDrainingNodeAnnotationKey = fmt.Sprintf("node-pool.%v/draining-node", GroupName)
+
+	// NodePoolSpotLabelKey is the key of the label and taint that nodes running on
+	// spot instances register with. Only workloads that tolerate preemption
+	// tolerate the taint.
+	NodePoolSpotLabelKey = fmt.Sprintf("node-pool.%v/spot", GroupName)
+
+	// NodePoolSpotFallbackAnnotationKey is the key of the annotation holding the
+	// time a node pool started launching on-demand instances in place of spot
+	// instances because spot capacity was unavailable.
+	NodePoolSpotFallbackAnnotationKey = fmt.Sprintf("node-pool.%v/spot-fallback", GroupName)
+
+	// NodePoolInterruptedNodesAnnotationKey is the key of the annotation holding the
+	// JSON encoded names of the node pool's nodes whose interruption notices have
+	// already been recorded.
+	NodePoolInterruptedNodesAnnotationKey = fmt.Sprintf("node-pool.%v/interrupted-nodes", GroupName)
+
 	AllNodePoolsSelector = corev1.NodeSelector{
 		NodeSelectorTerms: []corev1.NodeSelectorTerm{
 			{
@@ -56,6 +84,12 @@ var (
 		Operator: corev1.TolerationOpExists,
 		Effect:   corev1.TaintEffectNoSchedule,
 	}
+
+	NodePoolSpotToleration = corev1.Toleration{
+		Key:      NodePoolSpotLabelKey,
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
 )
 
 func NodePoolIDLabelInfo(namespacePrefix, value string) (v1.SystemID, string, NodePoolEpoch, error) {
@@ -130,6 +164,81 @@ func (np *NodePool) SystemSharedPathLabel() (tree.PathSubcomponent, bool, error)
 	return subcomponent, true, nil
 }
 
+// OverrideAnnotation returns the node pool's override, or nil if it does not
+// have one.
+func (np *NodePool) OverrideAnnotation() (*v1.NodePoolOverride, error) {
+	overrideStr, ok := np.Annotations[NodePoolOverrideAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var override v1.NodePoolOverride
+	if err := json.Unmarshal([]byte(overrideStr), &override); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", NodePoolOverrideAnnotationKey, err)
+	}
+
+	return &override, nil
+}
+
+// OverriddenSpec returns the spec with the node pool's override applied to it.
+// Controllers should use it when updating the node pool so that they do not
+// revert its override, or the number of instances chosen by the autoscaler.
+func (np *NodePool) OverriddenSpec(spec NodePoolSpec) (NodePoolSpec, error) {
+	override, err := np.OverrideAnnotation()
+	if err != nil {
+		return NodePoolSpec{}, err
+	}
+
+	if spec.Autoscaled() && np.Spec.Autoscaled() {
+		spec.NumInstances = np.Spec.NumInstances
+	}
+
+	if override != nil {
+		spec.NumInstances = override.NumInstances
+	}
+
+	return spec, nil
+}
+
+// DrainingNodeAnnotation returns the name of the node the node pool is
+// draining, if it is draining one.
+func (np *NodePool) DrainingNodeAnnotation() (string, bool) {
+	node, ok := np.Annotations[NodePoolDrainingNodeAnnotationKey]
+	return node, ok
+}
+
+// SpotFallbackAnnotation returns the time the node pool started falling back to
+// on-demand instances, if it is falling back.
+func (np *NodePool) SpotFallbackAnnotation() (time.Time, bool, error) {
+	timestampStr, ok := np.Annotations[NodePoolSpotFallbackAnnotationKey]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %v annotation: %v", NodePoolSpotFallbackAnnotationKey, err)
+	}
+
+	return timestamp, true, nil
+}
+
+// InterruptedNodesAnnotation returns the names of the nodes whose interruption
+// notices have been recorded.
+func (np *NodePool) InterruptedNodesAnnotation() ([]string, error) {
+	nodesStr, ok := np.Annotations[NodePoolInterruptedNodesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var nodes []string
+	if err := json.Unmarshal([]byte(nodesStr), &nodes); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", NodePoolInterruptedNodesAnnotationKey, err)
+	}
+
+	return nodes, nil
+}
+
 func (np *NodePool) TypeDescription() string {
 	if np.Labels == nil {
 		return "UNKNOWN"
@@ -231,9 +340,61 @@ func (np *NodePool) Toleration(epoch NodePoolEpoch) corev1.Toleration {
 	}
 }
 
+// WorkloadTolerations returns the tolerations of workloads running on the epoch
+// of the node pool. Preemptible workloads also tolerate the node pool's spot
+// instances, so that they can be scheduled onto them.
+func (np *NodePool) WorkloadTolerations(epoch NodePoolEpoch, preemptible bool) []corev1.Toleration {
+	tolerations := []corev1.Toleration{np.Toleration(epoch)}
+	if preemptible {
+		tolerations = append(tolerations, NodePoolSpotToleration)
+	}
+
+	return tolerations
+}
+
 type NodePoolSpec struct {
 	NumInstances int32  `json:"numInstances"`
 	InstanceType string `json:"instanceType"`
+
+	// MinInstances and MaxInstances bound the number of instances of an
+	// autoscaled node pool. The node pool controller scales NumInstances
+	// between them.
+	MinInstances int32 `json:"minInstances,omitempty"`
+	MaxInstances int32 `json:"maxInstances,omitempty"`
+
+	// InstanceTypes are additional instance types the node pool's instances
+	// may be launched as.
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+
+	// Spot, if set, launches some of the node pool's instances as spot instances.
+	Spot *definitionv1.NodePoolSpot `json:"spot,omitempty"`
+}
+
+func (s *NodePoolSpec) Autoscaled() bool {
+	return s.MaxInstances != 0
+}
+
+// AllInstanceTypes returns the instance types the node pool's instances may be
+// launched as, starting with InstanceType.
+func (s *NodePoolSpec) AllInstanceTypes() []string {
+	instanceTypes := []string{s.InstanceType}
+	for _, instanceType := range s.InstanceTypes {
+		if instanceType != s.InstanceType {
+			instanceTypes = append(instanceTypes, instanceType)
+		}
+	}
+
+	return instanceTypes
+}
+
+// SpotFallback returns the node pool's policy for when spot capacity is
+// unavailable. It should only be called if the node pool has spot instances.
+func (s *NodePoolSpec) SpotFallback() definitionv1.NodePoolSpotFallback {
+	if s.Spot.Fallback == "" {
+		return definitionv1.NodePoolSpotFallbackOnDemand
+	}
+
+	return s.Spot.Fallback
 }
 
 type NodePoolStatus struct {
@@ -249,6 +410,10 @@ type NodePoolStatus struct {
 	// Changing the number of nodes in a node pool does not require replacing the
 	// existing nodes, simply scaling them, and thus does not require a new epoch.
 	Epochs NodePoolStatusEpochs `json:"epochs"`
+
+	// InstanceHours are the hours the node pool's instances of each instance
+	// type ran for.
+	InstanceHours *cost.InstanceHours `json:"instanceHours,omitempty"`
 }
 
 type NodePoolStatusFailureInfo struct {
@@ -321,6 +486,18 @@ type NodePoolStatusEpochStatus struct {
 	State        NodePoolState `json:"state"`
 }
 
+// NodePoolSpotStatus is the status of the spot instances of a node pool's epoch,
+// as reported by the cloud provider.
+type NodePoolSpotStatus struct {
+	// CapacityUnavailable is whether the cloud provider was unable to launch
+	// spot instances for the epoch.
+	CapacityUnavailable bool
+
+	// InterruptedNodes are the names of the epoch's nodes whose spot instances
+	// have been sent an interruption notice.
+	InterruptedNodes []string
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type NodePoolList struct {
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolWorkloadTolerations(t *testing.T) {
+	nodePool := &NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "lattice-system-petflix",
+			Name:      "shared",
+		},
+	}
+
+	idTaint := &corev1.Taint{
+		Key:    NodePoolIDLabelKey,
+		Value:  nodePool.ID(2),
+		Effect: corev1.TaintEffectNoSchedule,
+	}
+	otherEpochTaint := &corev1.Taint{
+		Key:    NodePoolIDLabelKey,
+		Value:  nodePool.ID(1),
+		Effect: corev1.TaintEffectNoSchedule,
+	}
+	spotTaint := &corev1.Taint{
+		Key:    NodePoolSpotLabelKey,
+		Value:  "true",
+		Effect: corev1.TaintEffectNoSchedule,
+	}
+
+	tests := []struct {
+		name        string
+		preemptible bool
+		tolerated   []*corev1.Taint
+		untolerated []*corev1.Taint
+	}{
+		{
+			name:        "workloads only tolerate their node pool's epoch",
+			tolerated:   []*corev1.Taint{idTaint},
+			untolerated: []*corev1.Taint{otherEpochTaint, spotTaint},
+		},
+		{
+			name:        "preemptible workloads also tolerate spot instances",
+			preemptible: true,
+			tolerated:   []*corev1.Taint{idTaint, spotTaint},
+			untolerated: []*corev1.Taint{otherEpochTaint},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tolerations := nodePool.WorkloadTolerations(2, test.preemptible)
+			for _, taint := range test.tolerated {
+				require.True(t, tolerates(tolerations, taint), taint.Key)
+			}
+
+			for _, taint := range test.untolerated {
+				require.False(t, tolerates(tolerations, taint), taint.Key)
+			}
+		})
+	}
+}
+
+func tolerates(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+
+	return false
+}
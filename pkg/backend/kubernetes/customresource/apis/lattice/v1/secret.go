@@ -1,10 +1,51 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
 	// SecretPath label is the key that should be used for the path of the secret.
 	SecretPathLabelKey = fmt.Sprintf("secret.%v/path", GroupName)
+
+	// SecretProviderLabelKey is the key that should be used for the provider of a secret
+	// that was mirrored into kubernetes from a secret provider other than kubernetes.
+	SecretProviderLabelKey = fmt.Sprintf("secret.%v/provider", GroupName)
+
+	// SecretVersionsAnnotationKey is the key of the annotation describing the versions
+	// of the secrets stored in a Secret. The value is a JSON encoded SecretVersions.
+	SecretVersionsAnnotationKey = fmt.Sprintf("secret.%v/versions", GroupName)
+
+	// SecretsRotatedAnnotationKey is the key of the annotation recording when a secret
+	// referenced by a workload was last rotated. Changing it causes the workload's pods
+	// to be restarted.
+	SecretsRotatedAnnotationKey = fmt.Sprintf("secret.%v/rotated", GroupName)
 )
+
+// SecretVersions maps the keys of a Secret to the versions of the secret, oldest first.
+type SecretVersions map[string][]SecretVersion
+
+type SecretVersion struct {
+	Version           int32       `json:"version"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	Author            string      `json:"author,omitempty"`
+}
+
+// SecretVersionsAnnotation returns the versions described by the annotations,
+// or an empty SecretVersions if there is no versions annotation.
+func SecretVersionsAnnotation(annotations map[string]string) (SecretVersions, error) {
+	versions := make(SecretVersions)
+	annotation, ok := annotations[SecretVersionsAnnotationKey]
+	if !ok {
+		return versions, nil
+	}
+
+	if err := json.Unmarshal([]byte(annotation), &versions); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
@@ -3,9 +3,11 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 
@@ -23,6 +25,12 @@ var (
 	ServicePathLabelKey = fmt.Sprintf("service.%v/path", GroupName)
 
 	ServiceDeploymentSpecHashAnnotationKey = fmt.Sprintf("service.%v/deployment-spec-hash", GroupName)
+
+	// ServiceFaultAnnotationKey is the key of the annotation describing the fault
+	// that the service mesh should inject into traffic sent to the service.
+	// The fault is kept in an annotation so that it survives deploys updating the
+	// service's spec.
+	ServiceFaultAnnotationKey = fmt.Sprintf("service.%v/fault", GroupName)
 )
 
 // +genclient
@@ -92,6 +100,22 @@ func (s *Service) NodePoolAnnotation() (NodePoolAnnotationValue, error) {
 	return annotation, nil
 }
 
+// FaultAnnotation returns the fault that should be injected into the traffic sent to
+// the service, or nil if no fault annotation exists.
+func (s *Service) FaultAnnotation() (*ServiceFault, error) {
+	annotation, ok := s.Annotations[ServiceFaultAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	fault := &ServiceFault{}
+	if err := json.Unmarshal([]byte(annotation), fault); err != nil {
+		return nil, err
+	}
+
+	return fault, nil
+}
+
 func (s *Service) NeedsAddressLoadBalancer() bool {
 	for _, port := range s.Spec.Definition.ContainerPorts() {
 		if port.Public() {
@@ -109,6 +133,30 @@ type ServiceSpec struct {
 	ContainerBuildArtifacts WorkloadContainerBuildArtifacts `json:"containerBuildArtifacts"`
 }
 
+// ServiceFault is the type that should be the value of the ServiceFaultAnnotationKey
+// annotation. Exactly one of Delay or Abort should be set.
+type ServiceFault struct {
+	Delay *ServiceFaultDelay `json:"delay,omitempty"`
+	Abort *ServiceFaultAbort `json:"abort,omitempty"`
+
+	Percentage int32      `json:"percentage"`
+	Source     *tree.Path `json:"source,omitempty"`
+
+	Expires metav1.Time `json:"expires"`
+}
+
+func (f *ServiceFault) Expired(now time.Time) bool {
+	return !now.Before(f.Expires.Time)
+}
+
+type ServiceFaultDelay struct {
+	Duration metav1.Duration `json:"duration"`
+}
+
+type ServiceFaultAbort struct {
+	HTTPStatus int32 `json:"httpStatus"`
+}
+
 type ServiceStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration"`
 
@@ -122,6 +170,10 @@ type ServiceStatus struct {
 	TerminatingInstances int32 `json:"terminatingInstances"`
 
 	Ports map[int32]string `json:"ports"`
+
+	// InstanceHours are the hours the service's instances ran for on each of
+	// the node pools in its namespace.
+	InstanceHours *cost.InstanceHours `json:"instanceHours,omitempty"`
 }
 
 type ServiceState string
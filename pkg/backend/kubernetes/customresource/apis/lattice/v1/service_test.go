@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceFaultExpired(t *testing.T) {
+	now := time.Now()
+	fault := &ServiceFault{
+		Percentage: 50,
+		Expires:    metav1.NewTime(now.Add(time.Minute)),
+	}
+
+	require.False(t, fault.Expired(now))
+	require.False(t, fault.Expired(now.Add(time.Minute-time.Nanosecond)))
+
+	// the fault is no longer injected from the moment it expires
+	require.True(t, fault.Expired(now.Add(time.Minute)))
+	require.True(t, fault.Expired(now.Add(time.Hour)))
+}
+
+func TestServiceFaultAnnotation(t *testing.T) {
+	service := &Service{}
+
+	fault, err := service.FaultAnnotation()
+	require.NoError(t, err)
+	require.Nil(t, fault)
+
+	service.Annotations = map[string]string{
+		ServiceFaultAnnotationKey: `{"abort":{"httpStatus":503},"percentage":10,"expires":"2018-06-01T12:00:00Z"}`,
+	}
+	fault, err = service.FaultAnnotation()
+	require.NoError(t, err)
+	require.Equal(t, int32(503), fault.Abort.HTTPStatus)
+	require.Equal(t, int32(10), fault.Percentage)
+	require.True(t, fault.Expires.Equal(&metav1.Time{Time: time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)}))
+
+	service.Annotations[ServiceFaultAnnotationKey] = "{"
+	_, err = service.FaultAnnotation()
+	require.Error(t, err)
+}
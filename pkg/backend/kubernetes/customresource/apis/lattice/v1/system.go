@@ -3,11 +3,13 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/tracing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -17,6 +19,38 @@ var (
 	SystemListKind = SchemeGroupVersion.WithKind("SystemList")
 
 	SystemDefinitionVersionLabelKey = fmt.Sprintf("system.%v/definition-version", GroupName)
+
+	// SystemTracingSamplingRateAnnotationKey is the key of the annotation holding the
+	// fraction (0-1) of requests within the system that the service mesh should trace,
+	// as set through the system's tracing endpoint.
+	// If the annotation is not set the service mesh's default is used.
+	SystemTracingSamplingRateAnnotationKey = fmt.Sprintf("system.%v/tracing-sampling-rate", GroupName)
+
+	// SystemImagePolicyAnnotationKey is the key of the annotation holding the JSON
+	// encoded image policy that the system's builds and deploys must satisfy, as set
+	// through the system's image policy endpoint.
+	// If the annotation is not set any image may be used.
+	SystemImagePolicyAnnotationKey = fmt.Sprintf("system.%v/image-policy", GroupName)
+
+	// SystemWebhookAnnotationKey is the key of the annotation holding the JSON
+	// encoded config of how the system reacts to webhooks for pushes to its
+	// definition repository. If the annotation is not set webhooks are ignored.
+	SystemWebhookAnnotationKey = fmt.Sprintf("system.%v/webhook", GroupName)
+
+	// SystemDeployPolicyAnnotationKey is the key of the annotation holding the
+	// JSON encoded deploy policy of the system. If the annotation is not set
+	// deploys never have to be approved.
+	SystemDeployPolicyAnnotationKey = fmt.Sprintf("system.%v/deploy-policy", GroupName)
+
+	// SystemQuotaAnnotationKey is the key of the annotation holding the JSON
+	// encoded quota of the system. If the annotation is not set the system's
+	// resources are unlimited.
+	SystemQuotaAnnotationKey = fmt.Sprintf("system.%v/quota", GroupName)
+
+	// SystemContainerResourcesAnnotationKey is the key of the annotation
+	// holding the JSON encoded default and maximum resources of the system's
+	// containers.
+	SystemContainerResourcesAnnotationKey = fmt.Sprintf("system.%v/container-resources", GroupName)
 )
 
 // +genclient
@@ -38,6 +72,106 @@ func (s *System) DefinitionVersionLabel() (v1.Version, bool) {
 	return v1.Version(v), ok
 }
 
+// TracingSamplingRateAnnotation returns the fraction of requests within the system
+// that should be traced, or nil if no sampling rate annotation exists.
+func (s *System) TracingSamplingRateAnnotation() (*float64, error) {
+	annotation, ok := s.Annotations[SystemTracingSamplingRateAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	rate, err := strconv.ParseFloat(annotation, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SystemTracingSamplingRateAnnotationKey, err)
+	}
+
+	if err := tracing.ValidateSamplingRate(rate); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SystemTracingSamplingRateAnnotationKey, err)
+	}
+
+	return &rate, nil
+}
+
+// ImagePolicyAnnotation returns the image policy of the system, or nil if no
+// image policy annotation exists.
+func (s *System) ImagePolicyAnnotation() (*v1.ImagePolicy, error) {
+	annotation, ok := s.Annotations[SystemImagePolicyAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var policy v1.ImagePolicy
+	if err := json.Unmarshal([]byte(annotation), &policy); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SystemImagePolicyAnnotationKey, err)
+	}
+
+	return &policy, nil
+}
+
+// WebhookAnnotation returns the webhook config of the system, or nil if no
+// webhook annotation exists.
+func (s *System) WebhookAnnotation() (*v1.WebhookConfig, error) {
+	annotation, ok := s.Annotations[SystemWebhookAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var config v1.WebhookConfig
+	if err := json.Unmarshal([]byte(annotation), &config); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SystemWebhookAnnotationKey, err)
+	}
+
+	return &config, nil
+}
+
+// DeployPolicyAnnotation returns the deploy policy of the system, or nil if no
+// deploy policy annotation exists.
+func (s *System) DeployPolicyAnnotation() (*v1.DeployPolicy, error) {
+	annotation, ok := s.Annotations[SystemDeployPolicyAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var policy v1.DeployPolicy
+	if err := json.Unmarshal([]byte(annotation), &policy); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SystemDeployPolicyAnnotationKey, err)
+	}
+
+	return &policy, nil
+}
+
+// QuotaAnnotation returns the quota of the system, or nil if no quota
+// annotation exists.
+func (s *System) QuotaAnnotation() (*v1.SystemQuota, error) {
+	annotation, ok := s.Annotations[SystemQuotaAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var quota v1.SystemQuota
+	if err := json.Unmarshal([]byte(annotation), &quota); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SystemQuotaAnnotationKey, err)
+	}
+
+	return &quota, nil
+}
+
+// ContainerResourcesAnnotation returns the default and maximum resources of the
+// system's containers, or nil if no container resources annotation exists.
+func (s *System) ContainerResourcesAnnotation() (*v1.SystemContainerResources, error) {
+	annotation, ok := s.Annotations[SystemContainerResourcesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var resources v1.SystemContainerResources
+	if err := json.Unmarshal([]byte(annotation), &resources); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation: %v", SystemContainerResourcesAnnotationKey, err)
+	}
+
+	return &resources, nil
+}
+
 func (s *System) ResourceNamespace(namespacePrefix string) string {
 	return kubeutil.SystemNamespace(namespacePrefix, s.V1ID())
 }
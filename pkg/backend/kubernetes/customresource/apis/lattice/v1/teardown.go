@@ -12,6 +12,8 @@ import (
 var (
 	TeardownKind     = SchemeGroupVersion.WithKind("Teardown")
 	TeardownListKind = SchemeGroupVersion.WithKind("TeardownList")
+
+	TeardownLifecycleLockAnnotationKey = fmt.Sprintf("teardown.%v/lifecycle-lock", GroupName)
 )
 
 // +genclient
@@ -28,6 +30,13 @@ func (t *Teardown) V1ID() v1.TeardownID {
 	return v1.TeardownID(t.Name)
 }
 
+// LifecycleLockAnnotation returns whether the teardown holds the lifecycle
+// lock for its system.
+func (t *Teardown) LifecycleLockAnnotation() bool {
+	_, ok := t.Annotations[TeardownLifecycleLockAnnotationKey]
+	return ok
+}
+
 func (t *Teardown) Description(namespacePrefix string) string {
 	systemID, err := kubeutil.SystemID(namespacePrefix, t.Namespace)
 	if err != nil {
@@ -2,21 +2,30 @@ package v1
 
 import (
 	"fmt"
+	"path"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+	"github.com/mlab-lattice/lattice/pkg/containerresources"
 	"github.com/mlab-lattice/lattice/pkg/definition"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/sha1"
 
 	corev1 "k8s.io/api/core/v1"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// resourceGPU is the name of the resource advertised by the NVIDIA device plugin.
+const resourceGPU corev1.ResourceName = "nvidia.com/gpu"
+
 func PodTemplateSpecForV1Workload(
 	workload definitionv1.Workload,
 	path tree.Path,
@@ -43,6 +52,14 @@ func PodTemplateSpecForV1Workload(
 		return nil, err
 	}
 
+	// files are mounted from projected volumes of the Secrets holding them
+	filesSecretName := latticeutil.WorkloadFilesSecretName(name)
+	volumes, mounts, err := containerFileVolumes(kubeutil.UserMainContainerName, workload.Containers().Main, filesSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	mainContainer.VolumeMounts = mounts
 	kubeContainers = append(kubeContainers, mainContainer)
 
 	for name, sidecar := range workload.Containers().Sidecars {
@@ -61,9 +78,26 @@ func PodTemplateSpecForV1Workload(
 			return nil, err
 		}
 
+		sidecarVolumes, sidecarMounts, err := containerFileVolumes(
+			kubeutil.UserSidecarContainerName(name),
+			sidecar,
+			filesSecretName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		volumes = append(volumes, sidecarVolumes...)
+		container.VolumeMounts = sidecarMounts
 		kubeContainers = append(kubeContainers, container)
 	}
 
+	// sidecars are iterated over in random order, so sort the volumes so the
+	// spec is deterministic
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Name < volumes[j].Name
+	})
+
 	// create the proper DNS options
 	systemID, err := kubeutil.SystemID(namespacePrefix, namespace)
 	if err != nil {
@@ -108,11 +142,113 @@ func PodTemplateSpecForV1Workload(
 			DNSConfig:     dnsConfig,
 			Affinity:      affinity,
 			Tolerations:   tolerations,
+			Volumes:       volumes,
 		},
 	}
 	return &podSpecTemplate, nil
 }
 
+// containerFileVolumes returns the volume containing the container's files and the mounts
+// of each of the files into the container. Each file is mounted at its own path with a subPath
+// so that the rest of its directory in the container's image is left in place. Kubernetes does
+// not update files mounted with a subPath, so the workload has to be restarted to pick up
+// changes to them.
+func containerFileVolumes(
+	containerName string,
+	container definitionv1.Container,
+	filesSecretName string,
+) ([]corev1.Volume, []corev1.VolumeMount, error) {
+	if len(container.Files) == 0 {
+		return nil, nil, nil
+	}
+
+	hash, err := sha1.EncodeToHexString([]byte(containerName))
+	if err != nil {
+		return nil, nil, err
+	}
+	volumeName := fmt.Sprintf("lattice-files-%v", hash[:16])
+
+	var projections []corev1.VolumeProjection
+	var mounts []corev1.VolumeMount
+	for filePath, file := range container.Files {
+		cleaned := path.Clean(filePath)
+		if !path.IsAbs(cleaned) || cleaned == "/" {
+			return nil, nil, fmt.Errorf("invalid file path %v, expected an absolute path to a file", filePath)
+		}
+
+		mode, err := file.FileMode()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid file %v: %v", filePath, err)
+		}
+
+		secretName := filesSecretName
+		var key string
+		switch {
+		case file.Content != nil && file.Content.SecretRef != nil:
+			secretName, key, err = latticeutil.SecretNameAndKey(file.Content.SecretRef)
+
+		case file.Content != nil && file.Content.Value != nil, file.Template != nil:
+			key, err = latticeutil.ContainerFileKey(containerName, filePath)
+
+		default:
+			err = fmt.Errorf("file %v must have content or a template", filePath)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// the file's path in the volume mirrors its path in the container, so
+		// that each file has its own path in the volume
+		volumePath := strings.TrimPrefix(cleaned, "/")
+		projections = append(
+			projections,
+			corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Items: []corev1.KeyToPath{
+						{
+							Key:  key,
+							Path: volumePath,
+							Mode: &mode,
+						},
+					},
+				},
+			},
+		)
+		mounts = append(
+			mounts,
+			corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: cleaned,
+				SubPath:   volumePath,
+				ReadOnly:  true,
+			},
+		)
+	}
+
+	// sort the projections and mounts so the spec is deterministic
+	sort.Slice(projections, func(i, j int) bool {
+		return projections[i].Secret.Items[0].Path < projections[j].Secret.Items[0].Path
+	})
+	sort.Slice(mounts, func(i, j int) bool {
+		return mounts[i].MountPath < mounts[j].MountPath
+	})
+
+	volumes := []corev1.Volume{
+		{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: projections,
+				},
+			},
+		},
+	}
+	return volumes, mounts, nil
+}
+
 type containersComponent struct {
 	MainContainer definitionv1.Container
 	Sidecars      map[string]definitionv1.Container
@@ -180,7 +316,7 @@ func KubeContainerForContainer(
 				},
 			)
 		} else if envVar.SecretRef != nil {
-			secretName, err := latticeutil.HashPath(envVar.SecretRef.Value.Path())
+			secretName, key, err := latticeutil.SecretNameAndKey(envVar.SecretRef)
 			if err != nil {
 				return corev1.Container{}, err
 			}
@@ -189,7 +325,7 @@ func KubeContainerForContainer(
 				LocalObjectReference: corev1.LocalObjectReference{
 					Name: secretName,
 				},
-				Key: envVar.SecretRef.Value.Subcomponent(),
+				Key: key,
 			}
 
 			envVars = append(
@@ -221,15 +357,80 @@ func KubeContainerForContainer(
 		command = container.Exec.Command
 	}
 
+	resources, err := containerResourceRequirements(container.Resources)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+
 	kubeContainer := corev1.Container{
 		Name:            containerName,
-		Image:           buildArtifacts.DockerImageFQN,
+		Image:           buildArtifacts.DockerImage(),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Command:         command,
 		Ports:           ports,
 		Env:             envVars,
+		Resources:       resources,
 		LivenessProbe:   probe,
 		ReadinessProbe:  probe,
 	}
 	return kubeContainer, nil
 }
+
+func containerResourceRequirements(resources *definitionv1.ContainerResources) (corev1.ResourceRequirements, error) {
+	requests, err := resourceList(containerresources.Requests(resources))
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+
+	limits, err := resourceList(containerresources.Limits(resources))
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+
+	// GPUs cannot be overcommitted, so kubernetes requires them to be limited
+	// to the number requested
+	if gpu, ok := requests[resourceGPU]; ok {
+		if limits == nil {
+			limits = make(corev1.ResourceList)
+		}
+		limits[resourceGPU] = gpu
+	}
+
+	requirements := corev1.ResourceRequirements{
+		Requests: requests,
+		Limits:   limits,
+	}
+	return requirements, nil
+}
+
+// resourceList returns the resources set in the list, or nil if none are set
+// so that containers without resources are unchanged.
+func resourceList(list definitionv1.ContainerResourceList) (corev1.ResourceList, error) {
+	quantities := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:              list.CPU,
+		corev1.ResourceMemory:           list.Memory,
+		corev1.ResourceEphemeralStorage: list.EphemeralStorage,
+	}
+	if list.GPU != 0 {
+		quantities[resourceGPU] = strconv.Itoa(int(list.GPU))
+	}
+
+	var resources corev1.ResourceList
+	for name, quantity := range quantities {
+		if quantity == "" {
+			continue
+		}
+
+		q, err := resource.ParseQuantity(quantity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v %q: %v", name, quantity, err)
+		}
+
+		if resources == nil {
+			resources = make(corev1.ResourceList)
+		}
+		resources[name] = q
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"testing"
+
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testNamespacePrefix = "lattice"
+
+func testPodTemplateSpec(
+	t *testing.T,
+	service *definitionv1.Service,
+	tolerations []corev1.Toleration,
+) *corev1.PodTemplateSpec {
+	namespace := kubeutil.SystemNamespace(testNamespacePrefix, "petflix")
+	artifacts := WorkloadContainerBuildArtifacts{
+		MainContainer: ContainerBuildArtifacts{
+			DockerImageFQN: "registry.example.com/petflix/api:1",
+		},
+	}
+
+	spec, err := PodTemplateSpecForV1Workload(
+		service,
+		tree.RootPath().Child("api"),
+		"lattice",
+		"lattice.local",
+		testNamespacePrefix,
+		namespace,
+		"api",
+		nil,
+		artifacts,
+		corev1.RestartPolicyAlways,
+		nil,
+		tolerations,
+	)
+	require.NoError(t, err)
+	return spec
+}
+
+func TestPodTemplateSpecForV1WorkloadTolerations(t *testing.T) {
+	nodePool := &NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: kubeutil.SystemNamespace(testNamespacePrefix, "petflix"),
+			Name:      "shared",
+		},
+	}
+
+	spotTaint := &corev1.Taint{
+		Key:    NodePoolSpotLabelKey,
+		Value:  "true",
+		Effect: corev1.TaintEffectNoSchedule,
+	}
+
+	for _, preemptible := range []bool{false, true} {
+		service := &definitionv1.Service{
+			NumInstances: 1,
+			Preemptible:  preemptible,
+		}
+
+		tolerations := nodePool.WorkloadTolerations(1, service.Preemptible)
+		spec := testPodTemplateSpec(t, service, tolerations)
+
+		require.Equal(t, tolerations, spec.Spec.Tolerations)
+		require.Equal(t, preemptible, tolerates(spec.Spec.Tolerations, spotTaint))
+	}
+}
+
+func TestContainerFileVolumes(t *testing.T) {
+	config := "debug = true"
+	key := "key"
+	container := definitionv1.Container{
+		Files: map[string]definitionv1.ContainerFile{
+			"/etc/app.conf": {
+				Content: &definitionv1.ValueOrSecret{Value: &config},
+			},
+			"/etc/ssl/key.pem": {
+				Content: &definitionv1.ValueOrSecret{Value: &key},
+				Mode:    "0600",
+			},
+		},
+	}
+
+	volumes, mounts, err := containerFileVolumes("main", container, "api-files")
+	require.NoError(t, err)
+	require.Len(t, volumes, 1)
+	require.Len(t, volumes[0].Projected.Sources, 2)
+
+	paths := make(map[string]string)
+	for _, mount := range mounts {
+		require.Equal(t, volumes[0].Name, mount.Name)
+		require.True(t, mount.ReadOnly)
+		paths[mount.MountPath] = mount.SubPath
+	}
+
+	// each file is mounted at its own path, so the image's /etc is left intact
+	require.Equal(
+		t,
+		map[string]string{
+			"/etc/app.conf":    "etc/app.conf",
+			"/etc/ssl/key.pem": "etc/ssl/key.pem",
+		},
+		paths,
+	)
+
+	volumes, mounts, err = containerFileVolumes("main", definitionv1.Container{}, "api-files")
+	require.NoError(t, err)
+	require.Empty(t, volumes)
+	require.Empty(t, mounts)
+}
@@ -6,6 +6,7 @@ package v1
 
 import (
 	api_v1 "github.com/mlab-lattice/lattice/pkg/api/v1"
+	cost "github.com/mlab-lattice/lattice/pkg/cost"
 	tree "github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definition_v1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -246,6 +247,24 @@ func (in *BuildSpec) DeepCopyInto(out *BuildSpec) {
 			**out = **in
 		}
 	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(api_v1.BuildSource)
+			**out = **in
+		}
+	}
+	if in.Commit != nil {
+		in, out := &in.Commit, &out.Commit
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
 	return
 }
 
@@ -354,6 +373,22 @@ func (in *BuildStatusWorkload) DeepCopyInto(out *BuildStatusWorkload) {
 			(*out)[key] = val
 		}
 	}
+	if in.MainContainerReuse != nil {
+		in, out := &in.MainContainerReuse, &out.MainContainerReuse
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(api_v1.ContainerBuildReuse)
+			**out = **in
+		}
+	}
+	if in.SidecarReuse != nil {
+		in, out := &in.SidecarReuse, &out.SidecarReuse
+		*out = make(map[string]api_v1.ContainerBuildReuse, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -506,6 +541,15 @@ func (in *ConfigContainerBuild) DeepCopyInto(out *ConfigContainerBuild) {
 	*out = *in
 	out.Builder = in.Builder
 	in.DockerArtifact.DeepCopyInto(&out.DockerArtifact)
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ConfigContainerBuildCache)
+			**out = **in
+		}
+	}
 	return
 }
 
@@ -519,6 +563,54 @@ func (in *ConfigContainerBuild) DeepCopy() *ConfigContainerBuild {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigContainerBuildCache) DeepCopyInto(out *ConfigContainerBuildCache) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigContainerBuildCache.
+func (in *ConfigContainerBuildCache) DeepCopy() *ConfigContainerBuildCache {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigContainerBuildCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCost) DeepCopyInto(out *ConfigCost) {
+	*out = *in
+	if in.InstanceTypePrices != nil {
+		in, out := &in.InstanceTypePrices, &out.InstanceTypePrices
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CPUAllocation != nil {
+		in, out := &in.CPUAllocation, &out.CPUAllocation
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(float64)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCost.
+func (in *ConfigCost) DeepCopy() *ConfigCost {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigList) DeepCopyInto(out *ConfigList) {
 	*out = *in
@@ -561,6 +653,15 @@ func (in *ConfigServiceMesh) DeepCopyInto(out *ConfigServiceMesh) {
 			*out = nil
 		} else {
 			*out = new(ConfigServiceMeshEnvoy)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.None != nil {
+		in, out := &in.None, &out.None
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ConfigServiceMeshNone)
 			**out = **in
 		}
 	}
@@ -580,6 +681,15 @@ func (in *ConfigServiceMesh) DeepCopy() *ConfigServiceMesh {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigServiceMeshEnvoy) DeepCopyInto(out *ConfigServiceMeshEnvoy) {
 	*out = *in
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ConfigServiceMeshEnvoyTracing)
+			**out = **in
+		}
+	}
 	return
 }
 
@@ -593,12 +703,53 @@ func (in *ConfigServiceMeshEnvoy) DeepCopy() *ConfigServiceMeshEnvoy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigServiceMeshEnvoyTracing) DeepCopyInto(out *ConfigServiceMeshEnvoyTracing) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigServiceMeshEnvoyTracing.
+func (in *ConfigServiceMeshEnvoyTracing) DeepCopy() *ConfigServiceMeshEnvoyTracing {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigServiceMeshEnvoyTracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigServiceMeshNone) DeepCopyInto(out *ConfigServiceMeshNone) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigServiceMeshNone.
+func (in *ConfigServiceMeshNone) DeepCopy() *ConfigServiceMeshNone {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigServiceMeshNone)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
 	*out = *in
 	in.CloudProvider.DeepCopyInto(&out.CloudProvider)
 	in.ContainerBuild.DeepCopyInto(&out.ContainerBuild)
 	in.ServiceMesh.DeepCopyInto(&out.ServiceMesh)
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ConfigCost)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -760,6 +911,24 @@ func (in *ContainerBuildStatus) DeepCopyInto(out *ContainerBuildStatus) {
 			**out = **in
 		}
 	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(api_v1.ContainerBuildCacheInfo)
+			**out = **in
+		}
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(api_v1.ContainerBuildProvenance)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -1236,7 +1405,7 @@ func (in *NodePool) DeepCopyInto(out *NodePool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -1332,6 +1501,20 @@ func (in *NodePoolList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 	*out = *in
+	if in.InstanceTypes != nil {
+		in, out := &in.InstanceTypes, &out.InstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Spot != nil {
+		in, out := &in.Spot, &out.Spot
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(definition_v1.NodePoolSpot)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -1345,6 +1528,27 @@ func (in *NodePoolSpec) DeepCopy() *NodePoolSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolSpotStatus) DeepCopyInto(out *NodePoolSpotStatus) {
+	*out = *in
+	if in.InterruptedNodes != nil {
+		in, out := &in.InterruptedNodes, &out.InterruptedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpotStatus.
+func (in *NodePoolSpotStatus) DeepCopy() *NodePoolSpotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolSpotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
 	*out = *in
@@ -1361,7 +1565,18 @@ func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
 		in, out := &in.Epochs, &out.Epochs
 		*out = make(NodePoolStatusEpochs, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			newVal := new(NodePoolStatusEpoch)
+			val.DeepCopyInto(newVal)
+			(*out)[key] = *newVal
+		}
+	}
+	if in.InstanceHours != nil {
+		in, out := &in.InstanceHours, &out.InstanceHours
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(cost.InstanceHours)
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	return
@@ -1380,7 +1595,7 @@ func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePoolStatusEpoch) DeepCopyInto(out *NodePoolStatusEpoch) {
 	*out = *in
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	out.Status = in.Status
 	return
 }
@@ -1417,7 +1632,9 @@ func (in NodePoolStatusEpochs) DeepCopyInto(out *NodePoolStatusEpochs) {
 		in := &in
 		*out = make(NodePoolStatusEpochs, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			newVal := new(NodePoolStatusEpoch)
+			val.DeepCopyInto(newVal)
+			(*out)[key] = *newVal
 		}
 		return
 	}
@@ -1450,6 +1667,52 @@ func (in *NodePoolStatusFailureInfo) DeepCopy() *NodePoolStatusFailureInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretVersion) DeepCopyInto(out *SecretVersion) {
+	*out = *in
+	in.CreationTimestamp.DeepCopyInto(&out.CreationTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVersion.
+func (in *SecretVersion) DeepCopy() *SecretVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in SecretVersions) DeepCopyInto(out *SecretVersions) {
+	{
+		in := &in
+		*out = make(SecretVersions, len(*in))
+		for key, val := range *in {
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				(*out)[key] = make([]SecretVersion, len(val))
+				for i := range val {
+					val[i].DeepCopyInto(&(*out)[key][i])
+				}
+			}
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVersions.
+func (in SecretVersions) DeepCopy() SecretVersions {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretVersions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -1478,6 +1741,83 @@ func (in *Service) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceFault) DeepCopyInto(out *ServiceFault) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ServiceFaultDelay)
+			**out = **in
+		}
+	}
+	if in.Abort != nil {
+		in, out := &in.Abort, &out.Abort
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ServiceFaultAbort)
+			**out = **in
+		}
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(tree.Path)
+			**out = **in
+		}
+	}
+	in.Expires.DeepCopyInto(&out.Expires)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFault.
+func (in *ServiceFault) DeepCopy() *ServiceFault {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceFault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceFaultAbort) DeepCopyInto(out *ServiceFaultAbort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFaultAbort.
+func (in *ServiceFaultAbort) DeepCopy() *ServiceFaultAbort {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceFaultAbort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceFaultDelay) DeepCopyInto(out *ServiceFaultDelay) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceFaultDelay.
+func (in *ServiceFaultDelay) DeepCopy() *ServiceFaultDelay {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceFaultDelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceList) DeepCopyInto(out *ServiceList) {
 	*out = *in
@@ -1557,6 +1897,15 @@ func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.InstanceHours != nil {
+		in, out := &in.InstanceHours, &out.InstanceHours
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(cost.InstanceHours)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -0,0 +1,11 @@
+package dnsprovider
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/corednsetcd"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/rfc2136"
+)
+
+const (
+	CoreDNSEtcd = corednsetcd.CoreDNSEtcd
+	RFC2136     = rfc2136.RFC2136
+)
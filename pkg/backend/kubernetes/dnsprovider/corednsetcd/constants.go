@@ -0,0 +1,5 @@
+package corednsetcd
+
+const (
+	CoreDNSEtcd = "coredns-etcd"
+)
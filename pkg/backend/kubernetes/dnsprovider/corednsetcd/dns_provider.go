@@ -0,0 +1,335 @@
+package corednsetcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/ownership"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+
+	"github.com/golang/glog"
+)
+
+const (
+	defaultPrefix    = "/skydns"
+	defaultAPIPrefix = "/v3"
+	defaultTTL       = 60
+
+	// CoreDNS only returns the value of a key if it has no children, so the
+	// record is stored as a child of the name's key alongside its ownership record.
+	recordKey = "lattice"
+
+	requestTimeout = 10 * time.Second
+)
+
+type Options struct {
+	// Endpoint is the URL of an etcd member, for example http://etcd:2379.
+	Endpoint string
+
+	// APIPrefix is the path of the etcd v3 JSON gateway.
+	// It is /v3 for etcd 3.4 and above, and /v3beta for etcd 3.3.
+	APIPrefix string
+
+	// Prefix is the path the CoreDNS etcd plugin is configured to read records from.
+	Prefix string
+
+	// Zone is the zone that the lattice's records are created in.
+	// Record names are relative to the zone.
+	Zone string
+
+	TTL int
+}
+
+func Flags() (cli.Flags, *Options) {
+	options := &Options{}
+	flags := cli.Flags{
+		"endpoint": &flags.String{
+			Required: true,
+			Usage:    "URL of the etcd endpoint used by CoreDNS",
+			Target:   &options.Endpoint,
+		},
+		"api-prefix": &flags.String{
+			Usage:   "path of the etcd v3 JSON gateway",
+			Default: defaultAPIPrefix,
+			Target:  &options.APIPrefix,
+		},
+		"prefix": &flags.String{
+			Usage:   "path CoreDNS reads records from",
+			Default: defaultPrefix,
+			Target:  &options.Prefix,
+		},
+		"zone": &flags.String{
+			Required: true,
+			Usage:    "zone to create records in",
+			Target:   &options.Zone,
+		},
+		"ttl": &flags.Int{
+			Usage:   "TTL of the created records",
+			Default: defaultTTL,
+			Target:  &options.TTL,
+		},
+	}
+	return flags, options
+}
+
+func NewDNSProvider(options *Options) (*DefaultCoreDNSEtcdDNSProvider, error) {
+	if options.Endpoint == "" {
+		return nil, fmt.Errorf("must provide an etcd endpoint")
+	}
+
+	apiPrefix := options.APIPrefix
+	if apiPrefix == "" {
+		apiPrefix = defaultAPIPrefix
+	}
+
+	prefix := options.Prefix
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	p := &DefaultCoreDNSEtcdDNSProvider{
+		etcd: &etcdClient{
+			endpoint: fmt.Sprintf("%v%v", strings.TrimSuffix(options.Endpoint, "/"), apiPrefix),
+			httpClient: &http.Client{
+				Timeout: requestTimeout,
+			},
+		},
+		prefix: strings.TrimSuffix(prefix, "/"),
+		zone:   strings.TrimSuffix(options.Zone, "."),
+		ttl:    uint32(ttl),
+	}
+	return p, nil
+}
+
+// DefaultCoreDNSEtcdDNSProvider manages records served by the CoreDNS etcd plugin.
+// The lattice's ownership of a record is marked with a TXT record, and records that
+// are not owned by the lattice are never modified.
+type DefaultCoreDNSEtcdDNSProvider struct {
+	etcd *etcdClient
+
+	prefix string
+	zone   string
+	ttl    uint32
+}
+
+// service is the record format read by the CoreDNS etcd plugin.
+type service struct {
+	Host string `json:"host,omitempty"`
+	Text string `json:"text,omitempty"`
+	TTL  uint32 `json:"ttl,omitempty"`
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) DNSARecordNeedsUpdate(latticeID v1.LatticeID, name, value string) (bool, error) {
+	owned, err := p.owned(latticeID, name)
+	if err != nil {
+		return false, err
+	}
+
+	if !owned {
+		return true, nil
+	}
+
+	kvs, err := p.etcd.get(p.recordKey(name))
+	if err != nil {
+		return false, err
+	}
+
+	if len(kvs) != 1 {
+		return true, nil
+	}
+
+	var s service
+	if err := json.Unmarshal(kvs[0].Value, &s); err != nil {
+		return true, nil
+	}
+
+	return s.Host != value || s.TTL != p.ttl, nil
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) EnsureDNSARecord(latticeID v1.LatticeID, name, value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() == nil {
+		return fmt.Errorf("invalid IPv4 address for A record %v: %v", name, value)
+	}
+
+	return p.ensureRecord(latticeID, name, value)
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) EnsureDNSCNAMERecord(latticeID v1.LatticeID, name, value string) error {
+	// CoreDNS serves a CNAME for any host that is not an IP address
+	return p.ensureRecord(latticeID, name, strings.TrimSuffix(value, "."))
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) DestroyDNSRecord(latticeID v1.LatticeID, name string) error {
+	owned, err := p.owned(latticeID, name)
+	if err != nil {
+		if _, ok := err.(*ownership.NotOwnedError); ok {
+			glog.Warningf("not destroying DNS record: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	if !owned {
+		return nil
+	}
+
+	// remove the record before the ownership record so that a failure
+	// part way through doesn't orphan an unowned record
+	if err := p.etcd.delete(p.recordKey(name)); err != nil {
+		return err
+	}
+
+	return p.etcd.delete(p.ownershipKey(name))
+}
+
+// OwnedDNSRecords returns the names of all of the records in the zone marked as owned by the lattice.
+func (p *DefaultCoreDNSEtcdDNSProvider) OwnedDNSRecords(latticeID v1.LatticeID) ([]string, error) {
+	zoneKey := p.key(p.zone)
+	kvs, err := p.etcd.getPrefix(zoneKey + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, kv := range kvs {
+		fqdn, ok := ownership.OwnedRecordName(p.name(kv.Key))
+		if !ok {
+			continue
+		}
+
+		var s service
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			continue
+		}
+
+		if owner, ok := ownership.Owner(s.Text); !ok || owner != latticeID {
+			continue
+		}
+
+		names = append(names, strings.TrimSuffix(fqdn, "."+p.zone))
+	}
+
+	return names, nil
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) ensureRecord(latticeID v1.LatticeID, name, host string) error {
+	if _, err := p.owned(latticeID, name); err != nil {
+		return err
+	}
+
+	owner, err := json.Marshal(service{Text: ownership.Value(latticeID)})
+	if err != nil {
+		return err
+	}
+
+	// write the ownership record first so that a failure part way through
+	// doesn't leave an unowned record behind
+	if err := p.etcd.put(p.ownershipKey(name), owner); err != nil {
+		return err
+	}
+
+	record, err := json.Marshal(service{Host: host, TTL: p.ttl})
+	if err != nil {
+		return err
+	}
+
+	return p.etcd.put(p.recordKey(name), record)
+}
+
+// owned returns true if the record is owned by the lattice, false if there is no record,
+// and a NotOwnedError if the record exists and is not owned by the lattice.
+func (p *DefaultCoreDNSEtcdDNSProvider) owned(latticeID v1.LatticeID, name string) (bool, error) {
+	kvs, err := p.etcd.get(p.ownershipKey(name))
+	if err != nil {
+		return false, err
+	}
+
+	for _, kv := range kvs {
+		var s service
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			continue
+		}
+
+		owner, ok := ownership.Owner(s.Text)
+		if !ok {
+			continue
+		}
+
+		if owner != latticeID {
+			return false, &ownership.NotOwnedError{Name: name, Owner: owner}
+		}
+		return true, nil
+	}
+
+	// check for records at the name itself or directly below it
+	nameKey := p.key(p.fqdn(name))
+	existing, err := p.etcd.get(nameKey)
+	if err != nil {
+		return false, err
+	}
+
+	children, err := p.etcd.getPrefix(nameKey + "/")
+	if err != nil {
+		return false, err
+	}
+
+	if len(existing) != 0 {
+		return false, &ownership.NotOwnedError{Name: name}
+	}
+
+	for _, kv := range children {
+		// records of names below this one are not records of this name
+		if strings.Contains(strings.TrimPrefix(kv.Key, nameKey+"/"), "/") {
+			continue
+		}
+
+		return false, &ownership.NotOwnedError{Name: name}
+	}
+
+	return false, nil
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) recordKey(name string) string {
+	return fmt.Sprintf("%v/%v", p.key(p.fqdn(name)), recordKey)
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) ownershipKey(name string) string {
+	return p.key(ownership.RecordName(p.fqdn(name)))
+}
+
+func (p *DefaultCoreDNSEtcdDNSProvider) fqdn(name string) string {
+	return fmt.Sprintf("%v.%v", name, p.zone)
+}
+
+// key returns the key of the name in the layout used by the CoreDNS etcd plugin,
+// i.e. foo.example.com is stored at <prefix>/com/example/foo.
+func (p *DefaultCoreDNSEtcdDNSProvider) key(name string) string {
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+
+	return fmt.Sprintf("%v/%v", p.prefix, strings.Join(labels, "/"))
+}
+
+// name is the inverse of key.
+func (p *DefaultCoreDNSEtcdDNSProvider) name(key string) string {
+	labels := strings.Split(strings.TrimPrefix(key, p.prefix+"/"), "/")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+
+	return strings.Join(labels, ".")
+}
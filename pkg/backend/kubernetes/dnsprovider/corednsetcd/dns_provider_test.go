@@ -0,0 +1,271 @@
+package corednsetcd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/ownership"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testZone      = "lattice.test"
+	testLatticeID = v1.LatticeID("lattice")
+	otherLattice  = v1.LatticeID("other")
+)
+
+// etcdGateway is a fake of the etcd v3 JSON gateway backed by a map.
+type etcdGateway struct {
+	sync.Mutex
+	kvs map[string]string
+}
+
+func newEtcdGateway(kvs map[string]string) (*etcdGateway, *httptest.Server) {
+	if kvs == nil {
+		kvs = make(map[string]string)
+	}
+
+	g := &etcdGateway{kvs: kvs}
+	return g, httptest.NewServer(g)
+}
+
+func (g *etcdGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.Lock()
+	defer g.Unlock()
+
+	var request struct {
+		Key      string `json:"key"`
+		RangeEnd string `json:"range_end"`
+		Value    string `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := decode(request.Key)
+	rangeEnd := decode(request.RangeEnd)
+
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		type kv struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+
+		var response struct {
+			KVs []kv `json:"kvs,omitempty"`
+		}
+		for _, k := range g.keys(key, rangeEnd) {
+			response.KVs = append(response.KVs, kv{
+				Key:   base64.StdEncoding.EncodeToString([]byte(k)),
+				Value: g.kvs[k],
+			})
+		}
+		json.NewEncoder(w).Encode(&response)
+
+	case "/v3/kv/put":
+		g.kvs[key] = request.Value
+		w.Write([]byte("{}"))
+
+	case "/v3/kv/deleterange":
+		for _, k := range g.keys(key, rangeEnd) {
+			delete(g.kvs, k)
+		}
+		w.Write([]byte("{}"))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// keys returns the keys in the range, or just the key if there is no range end.
+func (g *etcdGateway) keys(key, rangeEnd string) []string {
+	var keys []string
+	for k := range g.kvs {
+		switch {
+		case rangeEnd == "":
+			if k == key {
+				keys = append(keys, k)
+			}
+
+		case k >= key && (rangeEnd == "\x00" || k < rangeEnd):
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the decoded value at the key, or nil if there isn't one.
+func (g *etcdGateway) Get(t *testing.T, key string) *service {
+	g.Lock()
+	defer g.Unlock()
+
+	value, ok := g.kvs[key]
+	if !ok {
+		return nil
+	}
+
+	var s service
+	require.NoError(t, json.Unmarshal([]byte(decode(value)), &s))
+	return &s
+}
+
+func decode(s string) string {
+	data, _ := base64.StdEncoding.DecodeString(s)
+	return string(data)
+}
+
+func encodeService(t *testing.T, s service) string {
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func newTestDNSProvider(t *testing.T, server *httptest.Server) *DefaultCoreDNSEtcdDNSProvider {
+	p, err := NewDNSProvider(&Options{
+		Endpoint: server.URL,
+		Zone:     testZone,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+// notOwnedKVs returns records at unowned.lattice.test that aren't owned by a lattice,
+// and records at other.lattice.test owned by another lattice.
+func notOwnedKVs(t *testing.T) map[string]string {
+	return map[string]string{
+		"/skydns/test/lattice/unowned":                encodeService(t, service{Host: "192.168.0.1"}),
+		"/skydns/test/lattice/other/lattice":          encodeService(t, service{Host: "192.168.0.2"}),
+		"/skydns/test/lattice/other/_lattice-owner":   encodeService(t, service{Text: ownership.Value(otherLattice)}),
+		"/skydns/test/lattice/other/subdomain/record": encodeService(t, service{Host: "192.168.0.3"}),
+	}
+}
+
+func TestEnsureAndDestroyRecord(t *testing.T) {
+	g, server := newEtcdGateway(nil)
+	defer server.Close()
+	p := newTestDNSProvider(t, server)
+
+	name := "api.sys"
+	recordKey := "/skydns/test/lattice/sys/api/lattice"
+	ownershipKey := "/skydns/test/lattice/sys/api/_lattice-owner"
+
+	needsUpdate, err := p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.1")
+	require.NoError(t, err)
+	require.True(t, needsUpdate)
+
+	// create
+	require.NoError(t, p.EnsureDNSARecord(testLatticeID, name, "10.0.0.1"))
+	require.Equal(t, &service{Host: "10.0.0.1", TTL: defaultTTL}, g.Get(t, recordKey))
+	require.Equal(t, &service{Text: ownership.Value(testLatticeID)}, g.Get(t, ownershipKey))
+
+	needsUpdate, err = p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.1")
+	require.NoError(t, err)
+	require.False(t, needsUpdate)
+
+	// update
+	needsUpdate, err = p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.2")
+	require.NoError(t, err)
+	require.True(t, needsUpdate)
+
+	require.NoError(t, p.EnsureDNSARecord(testLatticeID, name, "10.0.0.2"))
+	require.Equal(t, &service{Host: "10.0.0.2", TTL: defaultTTL}, g.Get(t, recordKey))
+
+	require.NoError(t, p.EnsureDNSCNAMERecord(testLatticeID, name, "api.example.com."))
+	require.Equal(t, &service{Host: "api.example.com", TTL: defaultTTL}, g.Get(t, recordKey))
+
+	// delete
+	require.NoError(t, p.DestroyDNSRecord(testLatticeID, name))
+	require.Nil(t, g.Get(t, recordKey))
+	require.Nil(t, g.Get(t, ownershipKey))
+
+	// destroying a record that doesn't exist does nothing
+	require.NoError(t, p.DestroyDNSRecord(testLatticeID, name))
+}
+
+func TestRecordsNotOwned(t *testing.T) {
+	kvs := notOwnedKVs(t)
+	expected := make(map[string]string)
+	for k, v := range kvs {
+		expected[k] = v
+	}
+
+	g, server := newEtcdGateway(kvs)
+	defer server.Close()
+	p := newTestDNSProvider(t, server)
+
+	for _, name := range []string{"unowned", "other"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.1")
+			require.IsType(t, &ownership.NotOwnedError{}, err)
+
+			err = p.EnsureDNSARecord(testLatticeID, name, "10.0.0.1")
+			require.IsType(t, &ownership.NotOwnedError{}, err)
+
+			err = p.EnsureDNSCNAMERecord(testLatticeID, name, "api.example.com")
+			require.IsType(t, &ownership.NotOwnedError{}, err)
+
+			// destroying a record that isn't owned leaves it alone
+			require.NoError(t, p.DestroyDNSRecord(testLatticeID, name))
+		})
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	require.Equal(t, expected, g.kvs)
+}
+
+func TestRecordsBelowName(t *testing.T) {
+	g, server := newEtcdGateway(map[string]string{
+		"/skydns/test/lattice/sys/api/v1/lattice": encodeService(t, service{Host: "192.168.0.1"}),
+	})
+	defer server.Close()
+	p := newTestDNSProvider(t, server)
+
+	// records of names below the name don't prevent it from being created
+	require.NoError(t, p.EnsureDNSARecord(testLatticeID, "api.sys", "10.0.0.1"))
+	require.Equal(t, &service{Host: "10.0.0.1", TTL: defaultTTL}, g.Get(t, "/skydns/test/lattice/sys/api/lattice"))
+}
+
+func TestOwnedDNSRecords(t *testing.T) {
+	g, server := newEtcdGateway(notOwnedKVs(t))
+	defer server.Close()
+	p := newTestDNSProvider(t, server)
+
+	require.NoError(t, p.EnsureDNSARecord(testLatticeID, "api.sys", "10.0.0.1"))
+	require.NoError(t, p.EnsureDNSCNAMERecord(testLatticeID, "orphan.sys", "api.example.com"))
+
+	owned, err := p.OwnedDNSRecords(testLatticeID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"api.sys", "orphan.sys"}, owned)
+
+	owned, err = p.OwnedDNSRecords(otherLattice)
+	require.NoError(t, err)
+	require.Equal(t, []string{"other"}, owned)
+
+	// reconciling orphaned records destroys the owned records that are no
+	// longer needed
+	require.NoError(t, p.DestroyDNSRecord(testLatticeID, "orphan.sys"))
+
+	owned, err = p.OwnedDNSRecords(testLatticeID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api.sys"}, owned)
+	require.NotNil(t, g.Get(t, "/skydns/test/lattice/sys/api/lattice"))
+
+	g.Lock()
+	defer g.Unlock()
+	for key := range g.kvs {
+		require.False(t, strings.HasPrefix(key, "/skydns/test/lattice/sys/orphan/"), key)
+	}
+}
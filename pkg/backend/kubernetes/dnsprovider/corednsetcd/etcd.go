@@ -0,0 +1,142 @@
+package corednsetcd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// etcdClient is a minimal client for the etcd v3 API using its JSON gateway.
+type etcdClient struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+type etcdKeyValue struct {
+	Key   string
+	Value []byte
+}
+
+type etcdRangeRequest struct {
+	Key      string `json:"key"`
+	RangeEnd string `json:"range_end,omitempty"`
+}
+
+type etcdRangeResponse struct {
+	KVs []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"kvs"`
+}
+
+type etcdPutRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// get returns the value at the key if there is one.
+func (c *etcdClient) get(key string) ([]etcdKeyValue, error) {
+	request := etcdRangeRequest{
+		Key: encode(key),
+	}
+	return c.rangeRequest(request)
+}
+
+// getPrefix returns all of the keys and values with the prefix.
+func (c *etcdClient) getPrefix(prefix string) ([]etcdKeyValue, error) {
+	request := etcdRangeRequest{
+		Key:      encode(prefix),
+		RangeEnd: encode(prefixRangeEnd(prefix)),
+	}
+	return c.rangeRequest(request)
+}
+
+func (c *etcdClient) put(key string, value []byte) error {
+	request := etcdPutRequest{
+		Key:   encode(key),
+		Value: base64.StdEncoding.EncodeToString(value),
+	}
+	return c.post("kv/put", request, nil)
+}
+
+func (c *etcdClient) delete(key string) error {
+	request := etcdRangeRequest{
+		Key: encode(key),
+	}
+	return c.post("kv/deleterange", request, nil)
+}
+
+func (c *etcdClient) rangeRequest(request etcdRangeRequest) ([]etcdKeyValue, error) {
+	var response etcdRangeResponse
+	if err := c.post("kv/range", request, &response); err != nil {
+		return nil, err
+	}
+
+	var kvs []etcdKeyValue
+	for _, kv := range response.KVs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding etcd key: %v", err)
+		}
+
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding value of etcd key %v: %v", string(key), err)
+		}
+
+		kvs = append(kvs, etcdKeyValue{Key: string(key), Value: value})
+	}
+
+	return kvs, nil
+}
+
+func (c *etcdClient) post(path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%v/%v", strings.TrimSuffix(c.endpoint, "/"), path)
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error communicating with etcd: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("etcd returned %v for %v: %v", resp.StatusCode, path, string(data))
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, response)
+}
+
+func encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// prefixRangeEnd returns the end of the range containing all keys with the prefix.
+func prefixRangeEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+
+	// the prefix was all 0xff, so the range is to the end of the keyspace
+	return "\x00"
+}
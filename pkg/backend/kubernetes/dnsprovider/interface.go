@@ -1,6 +1,14 @@
 package dnsprovider
 
-import "github.com/mlab-lattice/lattice/pkg/api/v1"
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/corednsetcd"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/rfc2136"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
 
 type Interface interface {
 	DNSARecordNeedsUpdate(latticeID v1.LatticeID, name, value string) (bool, error)
@@ -11,3 +19,65 @@ type Interface interface {
 
 	DestroyDNSRecord(latticeID v1.LatticeID, name string) error
 }
+
+// OwnedRecordLister is implemented by DNS providers that mark the records they create as
+// owned by the lattice, allowing records that are no longer needed to be found and removed.
+type OwnedRecordLister interface {
+	// OwnedDNSRecords returns the names of all of the records owned by the lattice.
+	OwnedDNSRecords(latticeID v1.LatticeID) ([]string, error)
+}
+
+// Options configures a DNS provider that is used instead of the cloud provider's.
+type Options struct {
+	RFC2136     *rfc2136.Options
+	CoreDNSEtcd *corednsetcd.Options
+}
+
+// NewDNSProvider returns the configured DNS provider, or nil if no DNS provider
+// was configured, in which case the cloud provider's should be used.
+func NewDNSProvider(options *Options) (Interface, error) {
+	if options.RFC2136 != nil {
+		return rfc2136.NewDNSProvider(options.RFC2136)
+	}
+
+	if options.CoreDNSEtcd != nil {
+		return corednsetcd.NewDNSProvider(options.CoreDNSEtcd)
+	}
+
+	return nil, nil
+}
+
+// Flag returns a flag configuring the DNS provider. Since the DNS provider is optional,
+// none of the returned options will be set if no DNS provider was chosen.
+func Flag(dnsProvider *string) (cli.Flag, *Options) {
+	rfc2136Flags, rfc2136Options := rfc2136.Flags()
+	coreDNSEtcdFlags, coreDNSEtcdOptions := corednsetcd.Flags()
+	options := &Options{}
+
+	flag := &flags.DelayedEmbedded{
+		Required: false,
+		Usage:    "configuration for the dns provider",
+		Flags: map[string]cli.Flags{
+			RFC2136:     rfc2136Flags,
+			CoreDNSEtcd: coreDNSEtcdFlags,
+		},
+		FlagChooser: func() (*string, error) {
+			if dnsProvider == nil || *dnsProvider == "" {
+				return nil, nil
+			}
+
+			switch *dnsProvider {
+			case RFC2136:
+				options.RFC2136 = rfc2136Options
+			case CoreDNSEtcd:
+				options.CoreDNSEtcd = coreDNSEtcdOptions
+			default:
+				return nil, fmt.Errorf("unsupported dns provider %v", *dnsProvider)
+			}
+
+			return dnsProvider, nil
+		},
+	}
+
+	return flag, options
+}
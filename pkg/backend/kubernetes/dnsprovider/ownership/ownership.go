@@ -0,0 +1,69 @@
+package ownership
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+)
+
+const (
+	// RecordPrefix is prepended to a record's name to get the name of the
+	// TXT record that marks the lattice that owns it. The ownership record
+	// is kept at a separate name since a CNAME cannot share a name with
+	// any other record.
+	RecordPrefix = "_lattice-owner"
+
+	heritage = "heritage=lattice"
+	ownerKey = "lattice/owner="
+)
+
+// RecordName returns the name of the TXT record that marks the ownership of the record with the given name.
+func RecordName(name string) string {
+	return fmt.Sprintf("%v.%v", RecordPrefix, name)
+}
+
+// OwnedRecordName returns the name of the record whose ownership is marked by the TXT record with
+// the given name, and false if the name is not the name of an ownership record.
+func OwnedRecordName(recordName string) (string, bool) {
+	prefix := RecordPrefix + "."
+	if !strings.HasPrefix(recordName, prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(recordName, prefix), true
+}
+
+// Value returns the value of the TXT record marking a record as owned by the lattice.
+func Value(latticeID v1.LatticeID) string {
+	return fmt.Sprintf("%v,%v%v", heritage, ownerKey, latticeID)
+}
+
+// Owner parses the value of an ownership TXT record, returning the lattice that owns the
+// record and false if the value was not written by a lattice.
+func Owner(value string) (v1.LatticeID, bool) {
+	// TXT record values may be returned quoted
+	value = strings.Trim(value, `"`)
+
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 || parts[0] != heritage || !strings.HasPrefix(parts[1], ownerKey) {
+		return "", false
+	}
+
+	return v1.LatticeID(strings.TrimPrefix(parts[1], ownerKey)), true
+}
+
+// NotOwnedError is returned when a DNS provider refuses to modify a record that
+// exists but is not owned by the lattice.
+type NotOwnedError struct {
+	Name  string
+	Owner v1.LatticeID
+}
+
+func (e *NotOwnedError) Error() string {
+	if e.Owner == "" {
+		return fmt.Sprintf("DNS record %v exists and is not owned by a lattice", e.Name)
+	}
+
+	return fmt.Sprintf("DNS record %v is owned by lattice %v", e.Name, e.Owner)
+}
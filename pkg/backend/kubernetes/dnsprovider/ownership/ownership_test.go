@@ -0,0 +1,43 @@
+package ownership
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordName(t *testing.T) {
+	name := "api.local.sys.lattice"
+	recordName := RecordName(name)
+	require.Equal(t, "_lattice-owner.api.local.sys.lattice", recordName)
+
+	owned, ok := OwnedRecordName(recordName)
+	require.True(t, ok)
+	require.Equal(t, name, owned)
+
+	_, ok = OwnedRecordName(name)
+	require.False(t, ok)
+}
+
+func TestOwner(t *testing.T) {
+	latticeID := v1.LatticeID("lattice")
+
+	t.Run("round trip", func(t *testing.T) {
+		owner, ok := Owner(Value(latticeID))
+		require.True(t, ok)
+		require.Equal(t, latticeID, owner)
+	})
+
+	t.Run("quoted", func(t *testing.T) {
+		owner, ok := Owner(`"` + Value(latticeID) + `"`)
+		require.True(t, ok)
+		require.Equal(t, latticeID, owner)
+	})
+
+	t.Run("foreign record", func(t *testing.T) {
+		_, ok := Owner("v=spf1 -all")
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,5 @@
+package rfc2136
+
+const (
+	RFC2136 = "rfc2136"
+)
@@ -0,0 +1,396 @@
+package rfc2136
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/ownership"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+
+	"github.com/golang/glog"
+	"github.com/miekg/dns"
+)
+
+const (
+	defaultTTL           = 60
+	defaultTSIGAlgorithm = "hmac-sha256"
+
+	tsigFudgeSeconds = 300
+)
+
+var tsigAlgorithms = map[string]string{
+	"hmac-md5":    dns.HmacMD5,
+	"hmac-sha1":   dns.HmacSHA1,
+	"hmac-sha256": dns.HmacSHA256,
+	"hmac-sha512": dns.HmacSHA512,
+}
+
+type Options struct {
+	// Nameserver is the host:port of the primary nameserver for the zone.
+	Nameserver string
+
+	// Zone is the zone that the lattice's records are created in.
+	// Record names are relative to the zone.
+	Zone string
+
+	TSIGKeyName   string
+	TSIGSecret    string
+	TSIGAlgorithm string
+
+	TTL int
+}
+
+func Flags() (cli.Flags, *Options) {
+	options := &Options{}
+	flags := cli.Flags{
+		"nameserver": &flags.String{
+			Required: true,
+			Usage:    "host:port of the nameserver accepting dynamic updates",
+			Target:   &options.Nameserver,
+		},
+		"zone": &flags.String{
+			Required: true,
+			Usage:    "zone to create records in",
+			Target:   &options.Zone,
+		},
+		"tsig-key-name": &flags.String{
+			Usage:  "name of the TSIG key used to sign updates",
+			Target: &options.TSIGKeyName,
+		},
+		"tsig-secret": &flags.String{
+			Usage:  "base64 encoded TSIG secret used to sign updates",
+			Target: &options.TSIGSecret,
+		},
+		"tsig-algorithm": &flags.String{
+			Usage:   "algorithm of the TSIG key",
+			Default: defaultTSIGAlgorithm,
+			Target:  &options.TSIGAlgorithm,
+		},
+		"ttl": &flags.Int{
+			Usage:   "TTL of the created records",
+			Default: defaultTTL,
+			Target:  &options.TTL,
+		},
+	}
+	return flags, options
+}
+
+func NewDNSProvider(options *Options) (*DefaultRFC2136DNSProvider, error) {
+	if _, _, err := net.SplitHostPort(options.Nameserver); err != nil {
+		return nil, fmt.Errorf("invalid nameserver %v: %v", options.Nameserver, err)
+	}
+
+	if (options.TSIGKeyName == "") != (options.TSIGSecret == "") {
+		return nil, fmt.Errorf("must set both or neither of the TSIG key name and secret")
+	}
+
+	algorithmName := options.TSIGAlgorithm
+	if algorithmName == "" {
+		algorithmName = defaultTSIGAlgorithm
+	}
+
+	algorithm, ok := tsigAlgorithms[algorithmName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TSIG algorithm %v", algorithmName)
+	}
+
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	p := &DefaultRFC2136DNSProvider{
+		nameserver: options.Nameserver,
+		zone:       dns.Fqdn(options.Zone),
+
+		tsigAlgorithm: algorithm,
+
+		ttl: uint32(ttl),
+	}
+
+	if options.TSIGKeyName != "" {
+		p.tsigKeyName = dns.Fqdn(options.TSIGKeyName)
+		p.tsigSecret = options.TSIGSecret
+	}
+
+	return p, nil
+}
+
+// DefaultRFC2136DNSProvider manages records in a zone via RFC2136 dynamic updates, for
+// example on BIND or any other nameserver that supports them.
+// The lattice's ownership of a record is marked with a TXT record, and records that
+// are not owned by the lattice are never modified.
+type DefaultRFC2136DNSProvider struct {
+	nameserver string
+	zone       string
+
+	tsigKeyName   string
+	tsigSecret    string
+	tsigAlgorithm string
+
+	ttl uint32
+}
+
+func (p *DefaultRFC2136DNSProvider) DNSARecordNeedsUpdate(latticeID v1.LatticeID, name, value string) (bool, error) {
+	fqdn := p.fqdn(name)
+	owned, err := p.owned(latticeID, fqdn)
+	if err != nil {
+		return false, err
+	}
+
+	if !owned {
+		return true, nil
+	}
+
+	records, err := p.query(fqdn, dns.TypeA)
+	if err != nil {
+		return false, err
+	}
+
+	if len(records) != 1 {
+		return true, nil
+	}
+
+	return records[0].(*dns.A).A.String() != value, nil
+}
+
+func (p *DefaultRFC2136DNSProvider) EnsureDNSARecord(latticeID v1.LatticeID, name, value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() == nil {
+		return fmt.Errorf("invalid IPv4 address for A record %v: %v", name, value)
+	}
+
+	fqdn := p.fqdn(name)
+	record := &dns.A{
+		Hdr: p.header(fqdn, dns.TypeA),
+		A:   ip.To4(),
+	}
+	return p.ensureRecord(latticeID, fqdn, record)
+}
+
+func (p *DefaultRFC2136DNSProvider) EnsureDNSCNAMERecord(latticeID v1.LatticeID, name, value string) error {
+	fqdn := p.fqdn(name)
+	record := &dns.CNAME{
+		Hdr:    p.header(fqdn, dns.TypeCNAME),
+		Target: dns.Fqdn(value),
+	}
+	return p.ensureRecord(latticeID, fqdn, record)
+}
+
+func (p *DefaultRFC2136DNSProvider) DestroyDNSRecord(latticeID v1.LatticeID, name string) error {
+	fqdn := p.fqdn(name)
+	owned, err := p.owned(latticeID, fqdn)
+	if err != nil {
+		if _, ok := err.(*ownership.NotOwnedError); ok {
+			glog.Warningf("not destroying DNS record: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	if !owned {
+		return nil
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(p.zone)
+	m.RemoveRRset([]dns.RR{
+		p.rrset(fqdn, dns.TypeA),
+		p.rrset(fqdn, dns.TypeCNAME),
+		p.rrset(ownership.RecordName(fqdn), dns.TypeTXT),
+	})
+
+	_, err = p.exchange(m)
+	return err
+}
+
+// OwnedDNSRecords transfers the zone and returns the names of all of the records marked as
+// owned by the lattice.
+func (p *DefaultRFC2136DNSProvider) OwnedDNSRecords(latticeID v1.LatticeID) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetAxfr(p.zone)
+
+	t := new(dns.Transfer)
+	if p.tsigKeyName != "" {
+		m.SetTsig(p.tsigKeyName, p.tsigAlgorithm, tsigFudgeSeconds, time.Now().Unix())
+		t.TsigSecret = map[string]string{p.tsigKeyName: p.tsigSecret}
+	}
+
+	envelopes, err := t.In(m, p.nameserver)
+	if err != nil {
+		return nil, fmt.Errorf("error transferring zone %v: %v", p.zone, err)
+	}
+
+	var names []string
+	for envelope := range envelopes {
+		if envelope.Error != nil {
+			return nil, fmt.Errorf("error transferring zone %v: %v", p.zone, envelope.Error)
+		}
+
+		for _, rr := range envelope.RR {
+			txt, ok := rr.(*dns.TXT)
+			if !ok {
+				continue
+			}
+
+			fqdn, ok := ownership.OwnedRecordName(strings.ToLower(txt.Hdr.Name))
+			if !ok || !ownedBy(latticeID, txt) {
+				continue
+			}
+
+			names = append(names, p.relativeName(fqdn))
+		}
+	}
+
+	return names, nil
+}
+
+func (p *DefaultRFC2136DNSProvider) ensureRecord(latticeID v1.LatticeID, fqdn string, record dns.RR) error {
+	if _, err := p.owned(latticeID, fqdn); err != nil {
+		return err
+	}
+
+	ownershipName := ownership.RecordName(fqdn)
+	owner := &dns.TXT{
+		Hdr: p.header(ownershipName, dns.TypeTXT),
+		Txt: []string{ownership.Value(latticeID)},
+	}
+
+	// a CNAME cannot coexist with other records, so clear out both types
+	// before inserting the desired record
+	m := new(dns.Msg)
+	m.SetUpdate(p.zone)
+	m.RemoveRRset([]dns.RR{
+		p.rrset(fqdn, dns.TypeA),
+		p.rrset(fqdn, dns.TypeCNAME),
+		p.rrset(ownershipName, dns.TypeTXT),
+	})
+	m.Insert([]dns.RR{owner, record})
+
+	_, err := p.exchange(m)
+	return err
+}
+
+// owned returns true if the record is owned by the lattice, false if there is no record,
+// and a NotOwnedError if the record exists and is not owned by the lattice.
+func (p *DefaultRFC2136DNSProvider) owned(latticeID v1.LatticeID, fqdn string) (bool, error) {
+	owners, err := p.query(ownership.RecordName(fqdn), dns.TypeTXT)
+	if err != nil {
+		return false, err
+	}
+
+	for _, rr := range owners {
+		for _, value := range rr.(*dns.TXT).Txt {
+			owner, ok := ownership.Owner(value)
+			if !ok {
+				continue
+			}
+
+			if owner != latticeID {
+				return false, &ownership.NotOwnedError{Name: p.relativeName(fqdn), Owner: owner}
+			}
+			return true, nil
+		}
+	}
+
+	for _, recordType := range []uint16{dns.TypeA, dns.TypeCNAME} {
+		records, err := p.query(fqdn, recordType)
+		if err != nil {
+			return false, err
+		}
+
+		if len(records) != 0 {
+			return false, &ownership.NotOwnedError{Name: p.relativeName(fqdn)}
+		}
+	}
+
+	return false, nil
+}
+
+// query returns the records of the given type at the name.
+func (p *DefaultRFC2136DNSProvider) query(fqdn string, recordType uint16) ([]dns.RR, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(fqdn, recordType)
+	m.RecursionDesired = false
+
+	response, err := p.exchange(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []dns.RR
+	for _, rr := range response.Answer {
+		header := rr.Header()
+		if header.Rrtype == recordType && strings.EqualFold(header.Name, fqdn) {
+			records = append(records, rr)
+		}
+	}
+
+	return records, nil
+}
+
+func (p *DefaultRFC2136DNSProvider) exchange(m *dns.Msg) (*dns.Msg, error) {
+	client := &dns.Client{
+		Net: "tcp",
+	}
+
+	if p.tsigKeyName != "" {
+		m.SetTsig(p.tsigKeyName, p.tsigAlgorithm, tsigFudgeSeconds, time.Now().Unix())
+		client.TsigSecret = map[string]string{p.tsigKeyName: p.tsigSecret}
+	}
+
+	response, _, err := client.Exchange(m, p.nameserver)
+	if err != nil {
+		return nil, fmt.Errorf("error communicating with nameserver %v: %v", p.nameserver, err)
+	}
+
+	switch response.Rcode {
+	case dns.RcodeSuccess, dns.RcodeNameError:
+		return response, nil
+
+	default:
+		return nil, fmt.Errorf("nameserver %v returned %v", p.nameserver, dns.RcodeToString[response.Rcode])
+	}
+}
+
+func (p *DefaultRFC2136DNSProvider) header(fqdn string, recordType uint16) dns.RR_Header {
+	return dns.RR_Header{
+		Name:   fqdn,
+		Rrtype: recordType,
+		Class:  dns.ClassINET,
+		Ttl:    p.ttl,
+	}
+}
+
+// rrset returns an RR identifying the RRset of the given type at the name,
+// for use when removing the RRset.
+func (p *DefaultRFC2136DNSProvider) rrset(fqdn string, recordType uint16) dns.RR {
+	return &dns.ANY{
+		Hdr: dns.RR_Header{
+			Name:   fqdn,
+			Rrtype: recordType,
+			Class:  dns.ClassINET,
+		},
+	}
+}
+
+func (p *DefaultRFC2136DNSProvider) fqdn(name string) string {
+	return dns.Fqdn(fmt.Sprintf("%v.%v", name, p.zone))
+}
+
+func (p *DefaultRFC2136DNSProvider) relativeName(fqdn string) string {
+	return strings.TrimSuffix(fqdn, "."+p.zone)
+}
+
+func ownedBy(latticeID v1.LatticeID, txt *dns.TXT) bool {
+	for _, value := range txt.Txt {
+		if owner, ok := ownership.Owner(value); ok && owner == latticeID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package rfc2136
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/dnsprovider/ownership"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testZone      = "lattice.test."
+	testLatticeID = v1.LatticeID("lattice")
+	otherLattice  = v1.LatticeID("other")
+)
+
+// nameserver is an in-process nameserver for a single zone that answers
+// queries and zone transfers and applies RFC2136 updates.
+type nameserver struct {
+	sync.Mutex
+	records []dns.RR
+	server  *dns.Server
+}
+
+func newNameserver(t *testing.T, records ...dns.RR) *nameserver {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	n := &nameserver{records: records}
+	n.server = &dns.Server{
+		Listener:          listener,
+		Handler:           n,
+		NotifyStartedFunc: func() { close(started) },
+	}
+
+	go n.server.ActivateAndServe()
+	<-started
+	return n
+}
+
+func (n *nameserver) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	n.Lock()
+	defer n.Unlock()
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+
+	switch {
+	case r.Opcode == dns.OpcodeUpdate:
+		n.update(r.Ns)
+
+	case r.Question[0].Qtype == dns.TypeAXFR:
+		soa := &dns.SOA{
+			Hdr:    dns.RR_Header{Name: testZone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 60},
+			Ns:     "ns." + testZone,
+			Mbox:   "admin." + testZone,
+			Serial: 1,
+		}
+		m.Answer = append([]dns.RR{soa}, n.records...)
+		m.Answer = append(m.Answer, soa)
+
+	default:
+		m.Answer = n.lookup(r.Question[0].Name, r.Question[0].Qtype)
+		if len(m.Answer) == 0 {
+			m.Rcode = dns.RcodeNameError
+		}
+	}
+
+	w.WriteMsg(m)
+}
+
+func (n *nameserver) update(rrs []dns.RR) {
+	for _, rr := range rrs {
+		header := rr.Header()
+		if header.Class != dns.ClassANY {
+			n.records = append(n.records, rr)
+			continue
+		}
+
+		// class ANY removes the name's RRset of the type
+		var records []dns.RR
+		for _, record := range n.records {
+			if record.Header().Rrtype != header.Rrtype || !strings.EqualFold(record.Header().Name, header.Name) {
+				records = append(records, record)
+			}
+		}
+		n.records = records
+	}
+}
+
+func (n *nameserver) lookup(fqdn string, recordType uint16) []dns.RR {
+	var records []dns.RR
+	for _, record := range n.records {
+		if record.Header().Rrtype == recordType && strings.EqualFold(record.Header().Name, fqdn) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Lookup returns the records of the type at the name relative to the zone.
+func (n *nameserver) Lookup(name string, recordType uint16) []dns.RR {
+	n.Lock()
+	defer n.Unlock()
+	return n.lookup(name+"."+testZone, recordType)
+}
+
+func (n *nameserver) Address() string {
+	return n.server.Listener.Addr().String()
+}
+
+func newTestDNSProvider(t *testing.T, n *nameserver) *DefaultRFC2136DNSProvider {
+	p, err := NewDNSProvider(&Options{
+		Nameserver: n.Address(),
+		Zone:       testZone,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func newRecord(t *testing.T, s string) dns.RR {
+	rr, err := dns.NewRR(s)
+	require.NoError(t, err)
+	return rr
+}
+
+func TestEnsureAndDestroyRecord(t *testing.T) {
+	n := newNameserver(t)
+	defer n.server.Shutdown()
+	p := newTestDNSProvider(t, n)
+
+	name := "api.sys"
+
+	needsUpdate, err := p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.1")
+	require.NoError(t, err)
+	require.True(t, needsUpdate)
+
+	// create
+	require.NoError(t, p.EnsureDNSARecord(testLatticeID, name, "10.0.0.1"))
+
+	records := n.Lookup(name, dns.TypeA)
+	require.Len(t, records, 1)
+	require.Equal(t, "10.0.0.1", records[0].(*dns.A).A.String())
+
+	owners := n.Lookup(ownership.RecordName(name), dns.TypeTXT)
+	require.Len(t, owners, 1)
+	require.Equal(t, []string{ownership.Value(testLatticeID)}, owners[0].(*dns.TXT).Txt)
+
+	needsUpdate, err = p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.1")
+	require.NoError(t, err)
+	require.False(t, needsUpdate)
+
+	// update
+	needsUpdate, err = p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.2")
+	require.NoError(t, err)
+	require.True(t, needsUpdate)
+
+	require.NoError(t, p.EnsureDNSARecord(testLatticeID, name, "10.0.0.2"))
+
+	records = n.Lookup(name, dns.TypeA)
+	require.Len(t, records, 1)
+	require.Equal(t, "10.0.0.2", records[0].(*dns.A).A.String())
+	require.Len(t, n.Lookup(ownership.RecordName(name), dns.TypeTXT), 1)
+
+	// replacing the A record with a CNAME removes the A record
+	require.NoError(t, p.EnsureDNSCNAMERecord(testLatticeID, name, "api.example.com"))
+	require.Empty(t, n.Lookup(name, dns.TypeA))
+
+	records = n.Lookup(name, dns.TypeCNAME)
+	require.Len(t, records, 1)
+	require.Equal(t, "api.example.com.", records[0].(*dns.CNAME).Target)
+
+	// delete
+	require.NoError(t, p.DestroyDNSRecord(testLatticeID, name))
+	require.Empty(t, n.Lookup(name, dns.TypeCNAME))
+	require.Empty(t, n.Lookup(ownership.RecordName(name), dns.TypeTXT))
+
+	// destroying a record that doesn't exist does nothing
+	require.NoError(t, p.DestroyDNSRecord(testLatticeID, name))
+}
+
+func TestRecordsNotOwned(t *testing.T) {
+	n := newNameserver(
+		t,
+		newRecord(t, "unowned.lattice.test. 60 IN A 192.168.0.1"),
+		newRecord(t, "other.lattice.test. 60 IN A 192.168.0.2"),
+		newRecord(t, "_lattice-owner.other.lattice.test. 60 IN TXT "+ownership.Value(otherLattice)),
+	)
+	defer n.server.Shutdown()
+	p := newTestDNSProvider(t, n)
+
+	for _, name := range []string{"unowned", "other"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := p.DNSARecordNeedsUpdate(testLatticeID, name, "10.0.0.1")
+			require.IsType(t, &ownership.NotOwnedError{}, err)
+
+			err = p.EnsureDNSARecord(testLatticeID, name, "10.0.0.1")
+			require.IsType(t, &ownership.NotOwnedError{}, err)
+
+			err = p.EnsureDNSCNAMERecord(testLatticeID, name, "api.example.com")
+			require.IsType(t, &ownership.NotOwnedError{}, err)
+
+			// destroying a record that isn't owned leaves it alone
+			require.NoError(t, p.DestroyDNSRecord(testLatticeID, name))
+
+			records := n.Lookup(name, dns.TypeA)
+			require.Len(t, records, 1)
+			require.True(t, strings.HasPrefix(records[0].(*dns.A).A.String(), "192.168.0."))
+			require.Empty(t, n.Lookup(name, dns.TypeCNAME))
+		})
+	}
+
+	owners := n.Lookup(ownership.RecordName("other"), dns.TypeTXT)
+	require.Len(t, owners, 1)
+	require.Equal(t, []string{ownership.Value(otherLattice)}, owners[0].(*dns.TXT).Txt)
+	require.Empty(t, n.Lookup(ownership.RecordName("unowned"), dns.TypeTXT))
+}
+
+func TestOwnedDNSRecords(t *testing.T) {
+	n := newNameserver(
+		t,
+		newRecord(t, "unowned.lattice.test. 60 IN A 192.168.0.1"),
+		newRecord(t, "other.lattice.test. 60 IN A 192.168.0.2"),
+		newRecord(t, "_lattice-owner.other.lattice.test. 60 IN TXT "+ownership.Value(otherLattice)),
+	)
+	defer n.server.Shutdown()
+	p := newTestDNSProvider(t, n)
+
+	require.NoError(t, p.EnsureDNSARecord(testLatticeID, "api.sys", "10.0.0.1"))
+	require.NoError(t, p.EnsureDNSCNAMERecord(testLatticeID, "orphan.sys", "api.example.com"))
+
+	owned, err := p.OwnedDNSRecords(testLatticeID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"api.sys", "orphan.sys"}, owned)
+
+	owned, err = p.OwnedDNSRecords(otherLattice)
+	require.NoError(t, err)
+	require.Equal(t, []string{"other"}, owned)
+
+	// reconciling orphaned records destroys the owned records that are no
+	// longer needed
+	require.NoError(t, p.DestroyDNSRecord(testLatticeID, "orphan.sys"))
+
+	owned, err = p.OwnedDNSRecords(testLatticeID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api.sys"}, owned)
+	require.Len(t, n.Lookup("api.sys", dns.TypeA), 1)
+	require.Empty(t, n.Lookup("orphan.sys", dns.TypeCNAME))
+}
@@ -0,0 +1,46 @@
+package imagepolicy
+
+import (
+	"fmt"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	"github.com/mlab-lattice/lattice/pkg/imagepolicy"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+)
+
+// SystemVerifier returns a verifier for the system's image policy, retrieving the policy's
+// signature keys from the secret providers. If the system does not have an image policy,
+// nil is returned.
+func SystemVerifier(system *latticev1.System, providers *secretprovider.Providers) (*imagepolicy.Verifier, error) {
+	policy, err := system.ImagePolicyAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return nil, nil
+	}
+
+	var keys [][]byte
+	for _, key := range policy.SignatureKeys {
+		provider, err := providers.Provider(key.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("error getting secret provider %v for signature key %v: %v", key.Provider, key.Secret.String(), err)
+		}
+
+		secret, err := provider.Reveal(system.V1ID(), key.Secret, 0)
+		if err != nil {
+			return nil, fmt.Errorf("error getting signature key %v: %v", key.Secret.String(), err)
+		}
+
+		keys = append(keys, []byte(secret.Value))
+	}
+
+	// images are verified against registries that lattice has no credentials
+	// for, so only anonymous access is supported
+	newClient := func(r string) *registry.Client {
+		return registry.NewClient(r, nil)
+	}
+	return imagepolicy.NewVerifier(policy, keys, newClient)
+}
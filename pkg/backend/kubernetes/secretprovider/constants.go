@@ -0,0 +1,9 @@
+package secretprovider
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+)
+
+const (
+	Kubernetes = latticeutil.KubernetesSecretProvider
+)
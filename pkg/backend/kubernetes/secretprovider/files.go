@@ -0,0 +1,137 @@
+package secretprovider
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeclientset "k8s.io/client-go/kubernetes"
+)
+
+// EnsureWorkloadFiles ensures that the Secret named by latticeutil.WorkloadFilesSecretName
+// contains the current contents of the workload's literal and templated files. Files whose
+// content is a secret are mounted directly from the secret's Secret, so are not included.
+func EnsureWorkloadFiles(
+	namespacePrefix string,
+	kubeClient kubeclientset.Interface,
+	providers *secretprovider.Providers,
+	systemID v1.SystemID,
+	name string,
+	owner metav1.OwnerReference,
+	workload definitionv1.Workload,
+) error {
+	secretValue := func(ref *definitionv1.SecretRef) (string, error) {
+		provider, err := providers.Provider(ref.Provider)
+		if err != nil {
+			return "", fmt.Errorf("error getting secret provider %v for secret %v: %v", ref.Provider, ref.Value.String(), err)
+		}
+
+		secret, err := provider.Reveal(systemID, ref.Value, ref.Version)
+		if err != nil {
+			return "", fmt.Errorf("error getting secret %v from provider %v: %v", ref.Value.String(), ref.Provider, err)
+		}
+
+		return secret.Value, nil
+	}
+
+	containers := workload.Containers()
+	data, err := containerFilesData(kubeutil.UserMainContainerName, containers.Main, secretValue)
+	if err != nil {
+		return err
+	}
+
+	for sidecarName, sidecar := range containers.Sidecars {
+		sidecarData, err := containerFilesData(kubeutil.UserSidecarContainerName(sidecarName), sidecar, secretValue)
+		if err != nil {
+			return err
+		}
+
+		for k, v := range sidecarData {
+			data[k] = v
+		}
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	secretName := latticeutil.WorkloadFilesSecretName(name)
+	namespace := kubeutil.SystemNamespace(namespacePrefix, systemID)
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            secretName,
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Data: data,
+		}
+
+		if _, err := kubeClient.CoreV1().Secrets(namespace).Create(secret); err != nil {
+			return fmt.Errorf("error creating files secret %v: %v", secretName, err)
+		}
+		return nil
+	}
+
+	if reflect.DeepEqual(data, secret.Data) {
+		return nil
+	}
+
+	// copy so the shared cache isn't mutated
+	secret = secret.DeepCopy()
+	secret.Data = data
+	if _, err := kubeClient.CoreV1().Secrets(namespace).Update(secret); err != nil {
+		return fmt.Errorf("error updating files secret %v: %v", secretName, err)
+	}
+
+	return nil
+}
+
+func containerFilesData(
+	containerName string,
+	container definitionv1.Container,
+	secretValue func(ref *definitionv1.SecretRef) (string, error),
+) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	for path, file := range container.Files {
+		var content string
+		switch {
+		case file.Content != nil && file.Content.Value != nil:
+			content = *file.Content.Value
+
+		case file.Template != nil:
+			var err error
+			content, err = file.Template.Render(secretValue)
+			if err != nil {
+				return nil, fmt.Errorf("error rendering file %v: %v", path, err)
+			}
+
+		default:
+			continue
+		}
+
+		key, err := latticeutil.ContainerFileKey(containerName, path)
+		if err != nil {
+			return nil, err
+		}
+
+		data[key] = []byte(content)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,482 @@
+package secretprovider
+
+import (
+	"encoding/json"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	kubeclientset "k8s.io/client-go/kubernetes"
+)
+
+func NewKubernetesSecretProvider(
+	namespacePrefix string,
+	kubeClient kubeclientset.Interface,
+) *DefaultKubernetesSecretProvider {
+	return &DefaultKubernetesSecretProvider{
+		namespacePrefix: namespacePrefix,
+		kubeClient:      kubeClient,
+	}
+}
+
+// DefaultKubernetesSecretProvider stores secrets as kubernetes Secrets in the system's
+// namespace. The current versions of the secrets for a path are stored in a Secret named
+// after the hashed path, with a key for each of the path's secrets and an annotation
+// describing each secret's versions. The values of every version are also stored in a
+// second Secret so that pods can reference a specific version.
+type DefaultKubernetesSecretProvider struct {
+	namespacePrefix string
+	kubeClient      kubeclientset.Interface
+}
+
+func (p *DefaultKubernetesSecretProvider) Ready() bool {
+	return true
+}
+
+func (p *DefaultKubernetesSecretProvider) List(systemID v1.SystemID) ([]v1.Secret, error) {
+	// There are secrets in the namespace that are not secrets set for lattice.
+	// Don't expose those in ListSystemSecrets
+	selector := labels.NewSelector()
+	requirement, err := labels.NewRequirement(latticev1.SecretPathLabelKey, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector = selector.Add(*requirement)
+
+	namespace := kubeutil.SystemNamespace(p.namespacePrefix, systemID)
+	secrets, err := p.kubeClient.CoreV1().Secrets(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	externalSecrets := make([]v1.Secret, 0)
+	for _, secret := range secrets.Items {
+		path, err := tree.NewPathFromDomain(secret.Labels[latticev1.SecretPathLabelKey])
+		if err != nil {
+			return nil, err
+		}
+
+		versions, err := latticev1.SecretVersionsAnnotation(secret.Annotations)
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range secret.Data {
+			subcomponent, err := tree.NewPathSubcomponentFromParts(path, name)
+			if err != nil {
+				return nil, err
+			}
+
+			history := secretHistory(&secret, versions, name)
+			externalSecrets = append(externalSecrets, transformSecretVersion(subcomponent, &history[len(history)-1]))
+		}
+	}
+
+	return externalSecrets, nil
+}
+
+func (p *DefaultKubernetesSecretProvider) Get(systemID v1.SystemID, subcomponent tree.PathSubcomponent) (*v1.Secret, error) {
+	history, _, err := p.history(systemID, subcomponent)
+	if err != nil {
+		return nil, err
+	}
+
+	externalSecret := transformSecretVersion(subcomponent, &history[len(history)-1])
+	return &externalSecret, nil
+}
+
+func (p *DefaultKubernetesSecretProvider) Versions(systemID v1.SystemID, subcomponent tree.PathSubcomponent) ([]v1.Secret, error) {
+	history, _, err := p.history(systemID, subcomponent)
+	if err != nil {
+		return nil, err
+	}
+
+	externalSecrets := make([]v1.Secret, 0)
+	for _, version := range history {
+		externalSecrets = append(externalSecrets, transformSecretVersion(subcomponent, &version))
+	}
+
+	return externalSecrets, nil
+}
+
+func (p *DefaultKubernetesSecretProvider) Reveal(
+	systemID v1.SystemID,
+	subcomponent tree.PathSubcomponent,
+	version int32,
+) (*v1.Secret, error) {
+	history, secret, err := p.history(systemID, subcomponent)
+	if err != nil {
+		return nil, err
+	}
+
+	current := history[len(history)-1]
+	if version == 0 || version == current.Version {
+		externalSecret := transformSecretVersion(subcomponent, &current)
+		externalSecret.Value = string(secret.Data[subcomponent.Subcomponent()])
+		return &externalSecret, nil
+	}
+
+	for _, v := range history {
+		if v.Version != version {
+			continue
+		}
+
+		name, err := latticeutil.SecretVersionsName(subcomponent.Path(), Kubernetes)
+		if err != nil {
+			return nil, err
+		}
+
+		versionsSecret, err := p.kubeClient.CoreV1().Secrets(secret.Namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, v1.NewInvalidSecretVersionError()
+			}
+
+			return nil, err
+		}
+
+		value, ok := versionsSecret.Data[latticeutil.SecretVersionKey(subcomponent.Subcomponent(), version)]
+		if !ok {
+			return nil, v1.NewInvalidSecretVersionError()
+		}
+
+		externalSecret := transformSecretVersion(subcomponent, &v)
+		externalSecret.Value = string(value)
+		return &externalSecret, nil
+	}
+
+	return nil, v1.NewInvalidSecretVersionError()
+}
+
+// history returns the versions of the secret, oldest first, along with
+// the Secret holding the secret's current value.
+func (p *DefaultKubernetesSecretProvider) history(
+	systemID v1.SystemID,
+	subcomponent tree.PathSubcomponent,
+) ([]latticev1.SecretVersion, *corev1.Secret, error) {
+	kubeSecretName, err := latticeutil.HashPath(subcomponent.Path())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	namespace := kubeutil.SystemNamespace(p.namespacePrefix, systemID)
+	secret, err := p.kubeClient.CoreV1().Secrets(namespace).Get(kubeSecretName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, v1.NewInvalidSecretError()
+		}
+
+		return nil, nil, err
+	}
+
+	if _, ok := secret.Data[subcomponent.Subcomponent()]; !ok {
+		return nil, nil, v1.NewInvalidSecretError()
+	}
+
+	versions, err := latticev1.SecretVersionsAnnotation(secret.Annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return secretHistory(secret, versions, subcomponent.Subcomponent()), secret, nil
+}
+
+func (p *DefaultKubernetesSecretProvider) Set(
+	systemID v1.SystemID,
+	subcomponent tree.PathSubcomponent,
+	value, author string,
+) error {
+	kubeSecretName, err := latticeutil.HashPath(subcomponent.Path())
+	if err != nil {
+		return err
+	}
+
+	namespace := kubeutil.SystemNamespace(p.namespacePrefix, systemID)
+	secret, err := p.kubeClient.CoreV1().Secrets(namespace).Get(kubeSecretName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		secret = nil
+	}
+
+	versions := make(latticev1.SecretVersions)
+	var history []latticev1.SecretVersion
+	if secret != nil {
+		versions, err = latticev1.SecretVersionsAnnotation(secret.Annotations)
+		if err != nil {
+			return err
+		}
+
+		if current, ok := secret.Data[subcomponent.Subcomponent()]; ok {
+			history = secretHistory(secret, versions, subcomponent.Subcomponent())
+
+			// secrets set before versions were recorded only have their value in
+			// the path's Secret, so store it as the first version before adding
+			// another so that it can still be revealed and referenced
+			if len(versions[subcomponent.Subcomponent()]) == 0 {
+				err := p.setVersion(namespace, subcomponent, history[0].Version, string(current))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	version := latticev1.SecretVersion{
+		Version:           1,
+		CreationTimestamp: metav1.Now(),
+		Author:            author,
+	}
+	if len(history) > 0 {
+		version.Version = history[len(history)-1].Version + 1
+	}
+
+	// store the version before making it current so that pods referencing
+	// the version can always find it
+	if err := p.setVersion(namespace, subcomponent, version.Version, value); err != nil {
+		return err
+	}
+
+	versions[subcomponent.Subcomponent()] = append(history, version)
+	annotation, err := json.Marshal(&versions)
+	if err != nil {
+		return err
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: kubeSecretName,
+				Labels: map[string]string{
+					latticev1.SecretPathLabelKey: subcomponent.Path().ToDomain(),
+				},
+				Annotations: map[string]string{
+					latticev1.SecretVersionsAnnotationKey: string(annotation),
+				},
+			},
+			Data: map[string][]byte{
+				subcomponent.Subcomponent(): []byte(value),
+			},
+		}
+
+		_, err = p.kubeClient.CoreV1().Secrets(namespace).Create(secret)
+		if errors.IsAlreadyExists(err) {
+			return v1.NewConflictError()
+		}
+
+		return err
+	}
+
+	secret = secret.DeepCopy()
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[latticev1.SecretVersionsAnnotationKey] = string(annotation)
+	secret.Data[subcomponent.Subcomponent()] = []byte(value)
+
+	_, err = p.kubeClient.CoreV1().Secrets(namespace).Update(secret)
+	if err == nil {
+		return nil
+	}
+
+	// if there was a conflict or the secret no longer exists (i.e. it was deleted since we found it)
+	// return a conflict error
+	if errors.IsConflict(err) || errors.IsNotFound(err) {
+		return v1.NewConflictError()
+	}
+
+	return err
+}
+
+// setVersion stores the value of the version of the secret in the Secret holding
+// the path's versions, creating the Secret if it does not exist.
+func (p *DefaultKubernetesSecretProvider) setVersion(
+	namespace string,
+	subcomponent tree.PathSubcomponent,
+	version int32,
+	value string,
+) error {
+	name, err := latticeutil.SecretVersionsName(subcomponent.Path(), Kubernetes)
+	if err != nil {
+		return err
+	}
+
+	key := latticeutil.SecretVersionKey(subcomponent.Subcomponent(), version)
+	secret, err := p.kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Data: map[string][]byte{
+				key: []byte(value),
+			},
+		}
+
+		_, err = p.kubeClient.CoreV1().Secrets(namespace).Create(secret)
+		if errors.IsAlreadyExists(err) {
+			return v1.NewConflictError()
+		}
+
+		return err
+	}
+
+	secret = secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[key] = []byte(value)
+
+	_, err = p.kubeClient.CoreV1().Secrets(namespace).Update(secret)
+	if errors.IsConflict(err) || errors.IsNotFound(err) {
+		return v1.NewConflictError()
+	}
+
+	return err
+}
+
+func (p *DefaultKubernetesSecretProvider) Unset(systemID v1.SystemID, subcomponent tree.PathSubcomponent) error {
+	history, secret, err := p.history(systemID, subcomponent)
+	if err != nil {
+		// If we can't find the secret, then it is unset
+		if v1err, ok := err.(*v1.Error); ok && v1err.Code == v1.ErrorCodeInvalidSecret {
+			return nil
+		}
+
+		return err
+	}
+
+	versions, err := latticev1.SecretVersionsAnnotation(secret.Annotations)
+	if err != nil {
+		return err
+	}
+
+	secret = secret.DeepCopy()
+	delete(secret.Data, subcomponent.Subcomponent())
+	delete(versions, subcomponent.Subcomponent())
+	if err := p.updateOrDelete(secret, versions); err != nil {
+		return err
+	}
+
+	// remove the stored versions of the secret
+	name, err := latticeutil.SecretVersionsName(subcomponent.Path(), Kubernetes)
+	if err != nil {
+		return err
+	}
+
+	versionsSecret, err := p.kubeClient.CoreV1().Secrets(secret.Namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	versionsSecret = versionsSecret.DeepCopy()
+	for _, version := range history {
+		delete(versionsSecret.Data, latticeutil.SecretVersionKey(subcomponent.Subcomponent(), version.Version))
+	}
+
+	return p.updateOrDelete(versionsSecret, nil)
+}
+
+// updateOrDelete updates the secret, or deletes it if it no longer holds any data.
+// If versions is non-nil it replaces the secret's versions annotation.
+func (p *DefaultKubernetesSecretProvider) updateOrDelete(secret *corev1.Secret, versions latticev1.SecretVersions) error {
+	if len(secret.Data) == 0 {
+		err := p.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(secret.Name, nil)
+		if err == nil || errors.IsNotFound(err) {
+			return nil
+		}
+
+		if errors.IsConflict(err) {
+			return v1.NewConflictError()
+		}
+
+		return err
+	}
+
+	if versions != nil {
+		annotation, err := json.Marshal(&versions)
+		if err != nil {
+			return err
+		}
+
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[latticev1.SecretVersionsAnnotationKey] = string(annotation)
+	}
+
+	_, err := p.kubeClient.CoreV1().Secrets(secret.Namespace).Update(secret)
+	if err == nil {
+		return nil
+	}
+
+	if errors.IsConflict(err) {
+		return v1.NewConflictError()
+	}
+
+	return err
+}
+
+// secretHistory returns the versions of the secret stored under the key. Secrets set
+// before versions were recorded are treated as having a single version.
+func secretHistory(secret *corev1.Secret, versions latticev1.SecretVersions, key string) []latticev1.SecretVersion {
+	history := versions[key]
+	if len(history) == 0 {
+		history = []latticev1.SecretVersion{
+			{
+				Version:           1,
+				CreationTimestamp: secret.CreationTimestamp,
+			},
+		}
+	}
+
+	return history
+}
+
+func transformSecretVersion(subcomponent tree.PathSubcomponent, version *latticev1.SecretVersion) v1.Secret {
+	return v1.Secret{
+		Path:              subcomponent,
+		Provider:          Kubernetes,
+		Version:           version.Version,
+		CreationTimestamp: *timeutil.New(version.CreationTimestamp.Time),
+		Author:            version.Author,
+	}
+}
+
+// NewProviders returns the kubernetes secret provider along with the configured external
+// secret providers. The kubernetes secret provider is the default provider.
+func NewProviders(
+	namespacePrefix string,
+	kubeClient kubeclientset.Interface,
+	options *secretprovider.Options,
+) (*secretprovider.Providers, error) {
+	providers, err := secretprovider.NewSecretProviders(options)
+	if err != nil {
+		return nil, err
+	}
+
+	providers[Kubernetes] = NewKubernetesSecretProvider(namespacePrefix, kubeClient)
+	return secretprovider.NewProviders(Kubernetes, providers)
+}
@@ -0,0 +1,124 @@
+package secretprovider
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNamespacePrefix = "lattice"
+	testSystemID        = v1.SystemID("system")
+)
+
+// referencedValue returns the value that a workload referencing the version of
+// the secret is given, where version 0 references the current version.
+func referencedValue(t *testing.T, provider *DefaultKubernetesSecretProvider, secret tree.PathSubcomponent, version int32) string {
+	ref := &definitionv1.SecretRef{Value: secret, Version: version}
+	name, key, err := latticeutil.SecretNameAndKey(ref)
+	require.NoError(t, err)
+
+	namespace := kubeutil.SystemNamespace(testNamespacePrefix, testSystemID)
+	kubeSecret, err := provider.kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	value, ok := kubeSecret.Data[key]
+	require.True(t, ok)
+	return string(value)
+}
+
+func revealedValue(t *testing.T, provider *DefaultKubernetesSecretProvider, secret tree.PathSubcomponent, version int32) string {
+	revealed, err := provider.Reveal(testSystemID, secret, version)
+	require.NoError(t, err)
+	return revealed.Value
+}
+
+func TestKubernetesSecretVersions(t *testing.T) {
+	provider := NewKubernetesSecretProvider(testNamespacePrefix, fake.NewSimpleClientset())
+	secret, err := tree.NewPathSubcomponent("/a/b:password")
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Set(testSystemID, secret, "first", "alice"))
+	require.NoError(t, provider.Set(testSystemID, secret, "second", "bob"))
+
+	current, err := provider.Get(testSystemID, secret)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), current.Version)
+	require.Equal(t, "bob", current.Author)
+	require.Empty(t, current.Value)
+
+	versions, err := provider.Versions(testSystemID, secret)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, int32(1), versions[0].Version)
+	require.Equal(t, "alice", versions[0].Author)
+
+	require.Equal(t, "second", revealedValue(t, provider, secret, 0))
+	require.Equal(t, "first", revealedValue(t, provider, secret, 1))
+
+	_, err = provider.Reveal(testSystemID, secret, 3)
+	require.Equal(t, v1.NewInvalidSecretVersionError(), err)
+
+	// workloads can be rolled back to an earlier version by pinning it
+	require.Equal(t, "second", referencedValue(t, provider, secret, 0))
+	require.Equal(t, "first", referencedValue(t, provider, secret, 1))
+	require.Equal(t, "second", referencedValue(t, provider, secret, 2))
+
+	require.NoError(t, provider.Unset(testSystemID, secret))
+	_, err = provider.Get(testSystemID, secret)
+	require.Equal(t, v1.NewInvalidSecretError(), err)
+}
+
+func TestKubernetesSecretUnversioned(t *testing.T) {
+	secret, err := tree.NewPathSubcomponent("/a/b:password")
+	require.NoError(t, err)
+
+	name, err := latticeutil.HashPath(secret.Path())
+	require.NoError(t, err)
+
+	// secrets set before versions were recorded don't have a versions
+	// annotation or Secret
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kubeutil.SystemNamespace(testNamespacePrefix, testSystemID),
+			Labels: map[string]string{
+				latticev1.SecretPathLabelKey: secret.Path().ToDomain(),
+			},
+		},
+		Data: map[string][]byte{
+			secret.Subcomponent(): []byte("unversioned"),
+		},
+	})
+	provider := NewKubernetesSecretProvider(testNamespacePrefix, client)
+
+	current, err := provider.Get(testSystemID, secret)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), current.Version)
+	require.Equal(t, "unversioned", revealedValue(t, provider, secret, 1))
+
+	// the first versioned write keeps the unversioned value as the first version
+	require.NoError(t, provider.Set(testSystemID, secret, "versioned", "alice"))
+
+	versions, err := provider.Versions(testSystemID, secret)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, int32(2), versions[1].Version)
+
+	require.Equal(t, "unversioned", revealedValue(t, provider, secret, 1))
+	require.Equal(t, "versioned", revealedValue(t, provider, secret, 2))
+	require.Equal(t, "unversioned", referencedValue(t, provider, secret, 1))
+	require.Equal(t, "versioned", referencedValue(t, provider, secret, 0))
+}
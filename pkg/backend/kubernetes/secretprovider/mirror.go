@@ -0,0 +1,177 @@
+package secretprovider
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/latticeutil"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeclientset "k8s.io/client-go/kubernetes"
+)
+
+// WorkloadSecretRefs returns the secret references in the environment and files of the workload's containers.
+func WorkloadSecretRefs(workload definitionv1.Workload) []definitionv1.SecretRef {
+	containers := workload.Containers()
+
+	refs := containerSecretRefs(containers.Main)
+	for _, sidecar := range containers.Sidecars {
+		refs = append(refs, containerSecretRefs(sidecar)...)
+	}
+
+	return refs
+}
+
+// WorkloadFileSecretRefs returns the secret references in the files of the workload's containers.
+func WorkloadFileSecretRefs(workload definitionv1.Workload) []definitionv1.SecretRef {
+	containers := workload.Containers()
+
+	refs := containerFileSecretRefs(containers.Main)
+	for _, sidecar := range containers.Sidecars {
+		refs = append(refs, containerFileSecretRefs(sidecar)...)
+	}
+
+	return refs
+}
+
+func containerSecretRefs(container definitionv1.Container) []definitionv1.SecretRef {
+	refs := containerFileSecretRefs(container)
+	if container.Exec == nil {
+		return refs
+	}
+
+	for _, v := range container.Exec.Environment {
+		if v.SecretRef != nil {
+			refs = append(refs, *v.SecretRef)
+		}
+	}
+
+	return refs
+}
+
+func containerFileSecretRefs(container definitionv1.Container) []definitionv1.SecretRef {
+	var refs []definitionv1.SecretRef
+	for _, file := range container.Files {
+		if file.Content != nil && file.Content.SecretRef != nil {
+			refs = append(refs, *file.Content.SecretRef)
+		}
+
+		if file.Template != nil {
+			for _, v := range file.Template.Parameters {
+				if v.SecretRef != nil {
+					refs = append(refs, *v.SecretRef)
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// EnsureSecretsMirrored ensures that the values of the referenced secrets that are stored in
+// providers other than kubernetes are mirrored into kubernetes Secrets in the system's namespace,
+// so that they can be consumed by pods. The Secrets are named using latticeutil.SecretNameAndKey.
+func EnsureSecretsMirrored(
+	namespacePrefix string,
+	kubeClient kubeclientset.Interface,
+	providers *secretprovider.Providers,
+	systemID v1.SystemID,
+	refs []definitionv1.SecretRef,
+) error {
+	type mirroredSecret struct {
+		path     tree.Path
+		provider string
+		data     map[string][]byte
+	}
+
+	mirrored := make(map[string]*mirroredSecret)
+	for _, ref := range refs {
+		if ref.Provider == "" || ref.Provider == Kubernetes {
+			continue
+		}
+
+		provider, err := providers.Provider(ref.Provider)
+		if err != nil {
+			return fmt.Errorf("error getting secret provider %v for secret %v: %v", ref.Provider, ref.Value.String(), err)
+		}
+
+		secret, err := provider.Reveal(systemID, ref.Value, ref.Version)
+		if err != nil {
+			return fmt.Errorf("error getting secret %v from provider %v: %v", ref.Value.String(), ref.Provider, err)
+		}
+
+		name, key, err := latticeutil.SecretNameAndKey(&ref)
+		if err != nil {
+			return err
+		}
+
+		m, ok := mirrored[name]
+		if !ok {
+			m = &mirroredSecret{
+				path:     ref.Value.Path(),
+				provider: ref.Provider,
+				data:     make(map[string][]byte),
+			}
+			mirrored[name] = m
+		}
+
+		m.data[key] = []byte(secret.Value)
+	}
+
+	namespace := kubeutil.SystemNamespace(namespacePrefix, systemID)
+	for name, m := range mirrored {
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						latticev1.SecretProviderLabelKey: m.provider,
+					},
+				},
+				Data: m.data,
+			}
+
+			if _, err := kubeClient.CoreV1().Secrets(namespace).Create(secret); err != nil {
+				return fmt.Errorf("error mirroring secrets for %v from provider %v: %v", m.path.String(), m.provider, err)
+			}
+			continue
+		}
+
+		// Only add or update the referenced keys, other workloads may
+		// reference other secrets at the same path.
+		data := make(map[string][]byte)
+		for k, v := range secret.Data {
+			data[k] = v
+		}
+		for k, v := range m.data {
+			data[k] = v
+		}
+
+		if reflect.DeepEqual(data, secret.Data) {
+			continue
+		}
+
+		secret = secret.DeepCopy()
+		secret.Data = data
+		if _, err := kubeClient.CoreV1().Secrets(namespace).Update(secret); err != nil {
+			return fmt.Errorf("error mirroring secrets for %v from provider %v: %v", m.path.String(), m.provider, err)
+		}
+	}
+
+	return nil
+}
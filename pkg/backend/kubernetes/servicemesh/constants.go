@@ -2,8 +2,10 @@ package servicemesh
 
 import (
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/none"
 )
 
 const (
 	Envoy = envoy.Envoy
+	None  = none.None
 )
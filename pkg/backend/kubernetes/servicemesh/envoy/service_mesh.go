@@ -15,6 +15,7 @@ import (
 
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 	netutil "github.com/mlab-lattice/lattice/pkg/util/net"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -35,6 +36,9 @@ const (
 	initContainerNamePrepareEnvoy = deploymentResourcePrefix + "prepare-envoy"
 	containerNameEnvoy            = deploymentResourcePrefix + "envoy"
 
+	// the zipkin v1 API endpoint, which envoy's zipkin tracer speaks
+	defaultTracingCollectorEndpoint = "/api/v1/spans"
+
 	xdsAPIVersion       = "2"
 	xdsAPI              = "xds-api"
 	labelKeyEnvoyXDSAPI = "envoy.servicemesh.lattice.mlab.com/xds-api"
@@ -45,6 +49,12 @@ type Options struct {
 	Image             string
 	RedirectCIDRBlock net.IPNet
 	XDSAPIPort        int32
+	Tracing           *TracingOptions
+}
+
+type TracingOptions struct {
+	CollectorAddress  string
+	CollectorEndpoint string
 }
 
 func NewOptions(staticOptions *Options, dynamicConfig *latticev1.ConfigServiceMeshEnvoy) (*Options, error) {
@@ -54,6 +64,23 @@ func NewOptions(staticOptions *Options, dynamicConfig *latticev1.ConfigServiceMe
 		RedirectCIDRBlock: staticOptions.RedirectCIDRBlock,
 		XDSAPIPort:        staticOptions.XDSAPIPort,
 	}
+
+	if dynamicConfig.Tracing != nil {
+		if _, _, err := net.SplitHostPort(dynamicConfig.Tracing.CollectorAddress); err != nil {
+			return nil, fmt.Errorf("invalid tracing collector address: %v", err)
+		}
+
+		collectorEndpoint := dynamicConfig.Tracing.CollectorEndpoint
+		if collectorEndpoint == "" {
+			collectorEndpoint = defaultTracingCollectorEndpoint
+		}
+
+		options.Tracing = &TracingOptions{
+			CollectorAddress:  dynamicConfig.Tracing.CollectorAddress,
+			CollectorEndpoint: collectorEndpoint,
+		}
+	}
+
 	return options, nil
 }
 
@@ -72,6 +99,7 @@ func NewEnvoyServiceMesh(options *Options) (*DefaultEnvoyServiceMesh, error) {
 		image:             options.Image,
 		redirectCIDRBlock: options.RedirectCIDRBlock,
 		xdsAPIPort:        options.XDSAPIPort,
+		tracing:           options.Tracing,
 		leaseManager:      leaseManager,
 	}, nil
 }
@@ -97,6 +125,7 @@ type DefaultEnvoyServiceMesh struct {
 	image             string
 	redirectCIDRBlock net.IPNet
 	xdsAPIPort        int32
+	tracing           *TracingOptions
 	leaseManager      netutil.LeaseManager
 }
 
@@ -105,6 +134,13 @@ type EnvoyEgressPorts struct {
 	TCP  int32 `json:"tcp"`
 }
 
+// EnvoyServiceMeshPort is the port envoy is listening on for a component port,
+// along with the protocol envoy should speak on it.
+type EnvoyServiceMeshPort struct {
+	Port     int32  `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
 func (sm *DefaultEnvoyServiceMesh) BootstrapSystemResources(resources *bootstrapper.SystemResources) {
 }
 
@@ -189,15 +225,21 @@ func envoyPorts(service *latticev1.Service) ([]int32, error) {
 	return envoyPorts, nil
 }
 
-func assignEnvoyPorts(service *latticev1.Service, envoyPorts []int32) (map[int32]int32, []int32, error) {
+func assignEnvoyPorts(
+	service *latticev1.Service,
+	envoyPorts []int32,
+) (map[int32]EnvoyServiceMeshPort, []int32, error) {
 	// Assign an envoy port to each component port, and pop the used envoy port off the slice each time.
-	componentPorts := make(map[int32]int32)
-	for portNum := range service.Spec.Definition.ContainerPorts() {
+	componentPorts := make(map[int32]EnvoyServiceMeshPort)
+	for portNum, port := range service.Spec.Definition.ContainerPorts() {
 		if len(envoyPorts) == 0 {
 			return nil, nil, fmt.Errorf("ran out of ports when assigning envoyPorts")
 		}
 
-		componentPorts[int32(portNum)] = envoyPorts[0]
+		componentPorts[int32(portNum)] = EnvoyServiceMeshPort{
+			Port:     envoyPorts[0],
+			Protocol: port.Protocol,
+		}
 		envoyPorts = envoyPorts[1:]
 	}
 
@@ -258,23 +300,57 @@ func (sm *DefaultEnvoyServiceMesh) ServiceMeshPort(service *latticev1.Service, p
 }
 
 func (sm *DefaultEnvoyServiceMesh) ServiceMeshPorts(service *latticev1.Service) (map[int32]int32, error) {
+	envoyPorts, err := sm.EnvoyServiceMeshPorts(service)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceMeshPorts := make(map[int32]int32)
+	for componentPort, envoyPort := range envoyPorts {
+		serviceMeshPorts[componentPort] = envoyPort.Port
+	}
+
+	return serviceMeshPorts, nil
+}
+
+// EnvoyServiceMeshPorts returns a map whose keys are component ports and values are the port
+// envoy is listening on for the given key and the protocol it should speak on it.
+func (sm *DefaultEnvoyServiceMesh) EnvoyServiceMeshPorts(
+	service *latticev1.Service,
+) (map[int32]EnvoyServiceMeshPort, error) {
 	serviceMeshPortsJSON, ok := service.Annotations[annotationKeyServiceMeshPorts]
 	if !ok {
 		err := fmt.Errorf(
 			"service %v/%v does not have expected annotation %v",
 			service.Namespace,
 			service.Name,
-			serviceMeshPortsJSON,
+			annotationKeyServiceMeshPorts,
 		)
 		return nil, err
 	}
 
-	serviceMeshPorts := make(map[int32]int32)
+	serviceMeshPorts := make(map[int32]EnvoyServiceMeshPort)
 	err := json.Unmarshal([]byte(serviceMeshPortsJSON), &serviceMeshPorts)
-	if err != nil {
+	if err == nil {
+		return serviceMeshPorts, nil
+	}
+
+	// Services created before the protocol was recorded in the annotation
+	// only have the envoy port, so fall back to the protocol in the definition.
+	legacyServiceMeshPorts := make(map[int32]int32)
+	if legacyErr := json.Unmarshal([]byte(serviceMeshPortsJSON), &legacyServiceMeshPorts); legacyErr != nil {
 		return nil, err
 	}
 
+	serviceMeshPorts = make(map[int32]EnvoyServiceMeshPort)
+	componentPorts := service.Spec.Definition.ContainerPorts()
+	for componentPort, envoyPort := range legacyServiceMeshPorts {
+		serviceMeshPorts[componentPort] = EnvoyServiceMeshPort{
+			Port:     envoyPort,
+			Protocol: componentPorts[componentPort].Protocol,
+		}
+	}
+
 	return serviceMeshPorts, nil
 }
 
@@ -312,10 +388,18 @@ func (sm *DefaultEnvoyServiceMesh) ServicePorts(service *latticev1.Service) (map
 	return servicePorts, nil
 }
 
+// serviceProtocols returns the set of egress protocols used by the service's ports.
+// Ports speaking protocols layered on top of HTTP (HTTP/2, gRPC) are all handled by
+// envoy's HTTP egress listener, so they are all reported as HTTP.
 func serviceProtocols(service *latticev1.Service) []string {
 	protocolSet := make(map[string]interface{})
 	for _, componentPort := range service.Spec.Definition.Ports {
-		protocolSet[componentPort.Protocol] = nil
+		protocol := componentPort.Protocol
+		if componentPort.HTTPBased() {
+			protocol = definitionv1.ContainerPortProtocolHTTP
+		}
+
+		protocolSet[protocol] = nil
 	}
 
 	protocols := make([]string, 0, 1)
@@ -349,14 +433,14 @@ func (sm *DefaultEnvoyServiceMesh) ServiceIP(
 	}
 
 	switch protocol {
-	case "HTTP":
+	case definitionv1.ContainerPortProtocolHTTP:
 		netIP := sm.redirectCIDRBlock.IP.String()
 		if ip != "" && ip != netIP {
 			return "", nil, fmt.Errorf("got IP %s for service %s, expected %s", ip, service.Name, netIP)
 		} else {
 			ip = netIP
 		}
-	case "TCP", "NULL":
+	case definitionv1.ContainerPortProtocolTCP, "NULL":
 		var err error
 		ips := make([]string, 0, 1)
 		if ip != "" {
@@ -671,6 +755,20 @@ func (sm *DefaultEnvoyServiceMesh) envoyContainers(service *latticev1.Service) (
 		},
 	}
 
+	if sm.tracing != nil {
+		prepareEnvoy.Env = append(
+			prepareEnvoy.Env,
+			corev1.EnvVar{
+				Name:  "TRACING_COLLECTOR_ADDRESS",
+				Value: sm.tracing.CollectorAddress,
+			},
+			corev1.EnvVar{
+				Name:  "TRACING_COLLECTOR_ENDPOINT",
+				Value: sm.tracing.CollectorEndpoint,
+			},
+		)
+	}
+
 	var envoyPorts []corev1.ContainerPort
 	serviceMeshPorts, err := sm.ServiceMeshPorts(service)
 	if err != nil {
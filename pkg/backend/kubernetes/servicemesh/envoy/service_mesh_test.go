@@ -0,0 +1,83 @@
+package envoy
+
+import (
+	"testing"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testService() *latticev1.Service {
+	return &latticev1.Service{
+		Spec: latticev1.ServiceSpec{
+			Definition: definitionv1.Service{
+				Container: definitionv1.Container{
+					Ports: map[int32]definitionv1.ContainerPort{
+						8080: {Protocol: definitionv1.ContainerPortProtocolHTTP},
+						9090: {Protocol: definitionv1.ContainerPortProtocolGRPC},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestEnvoyServiceMeshPorts(t *testing.T) {
+	serviceMesh := &DefaultEnvoyServiceMesh{}
+	service := testService()
+
+	annotations, err := serviceMesh.ServiceAnnotations(service)
+	require.NoError(t, err)
+	service.Annotations = annotations
+
+	envoyPorts, err := serviceMesh.EnvoyServiceMeshPorts(service)
+	require.NoError(t, err)
+	require.Len(t, envoyPorts, 2)
+	require.Equal(t, definitionv1.ContainerPortProtocolHTTP, envoyPorts[8080].Protocol)
+	require.Equal(t, definitionv1.ContainerPortProtocolGRPC, envoyPorts[9090].Protocol)
+	require.NotEqual(t, envoyPorts[8080].Port, envoyPorts[9090].Port)
+
+	serviceMeshPorts, err := serviceMesh.ServiceMeshPorts(service)
+	require.NoError(t, err)
+	require.Equal(t, map[int32]int32{8080: envoyPorts[8080].Port, 9090: envoyPorts[9090].Port}, serviceMeshPorts)
+}
+
+func TestEnvoyServiceMeshPortsLegacy(t *testing.T) {
+	serviceMesh := &DefaultEnvoyServiceMesh{}
+
+	// services annotated before the protocol was recorded take it from their definition
+	service := testService()
+	service.Annotations = map[string]string{
+		annotationKeyServiceMeshPorts: `{"8080":10000,"9090":10001}`,
+	}
+
+	envoyPorts, err := serviceMesh.EnvoyServiceMeshPorts(service)
+	require.NoError(t, err)
+
+	expected := map[int32]EnvoyServiceMeshPort{
+		8080: {Port: 10000, Protocol: definitionv1.ContainerPortProtocolHTTP},
+		9090: {Port: 10001, Protocol: definitionv1.ContainerPortProtocolGRPC},
+	}
+	require.Equal(t, expected, envoyPorts)
+
+	service.Annotations[annotationKeyServiceMeshPorts] = `{"8080":"http"}`
+	_, err = serviceMesh.EnvoyServiceMeshPorts(service)
+	require.Error(t, err)
+
+	delete(service.Annotations, annotationKeyServiceMeshPorts)
+	_, err = serviceMesh.EnvoyServiceMeshPorts(service)
+	require.Error(t, err)
+}
+
+func TestServiceProtocols(t *testing.T) {
+	// HTTP/2 and gRPC ports are served by the HTTP egress listener
+	service := testService()
+	require.Equal(t, []string{definitionv1.ContainerPortProtocolHTTP}, serviceProtocols(service))
+
+	service.Spec.Definition.Ports = map[int32]definitionv1.ContainerPort{
+		5432: {Protocol: definitionv1.ContainerPortProtocolTCP},
+	}
+	require.Equal(t, []string{definitionv1.ContainerPortProtocolTCP}, serviceProtocols(service))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/golang/glog"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
@@ -34,6 +35,7 @@ import (
 	latticeclientset "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/clientset/versioned"
 	latticeinformers "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/informers/externalversions"
 	latticelisters "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/generated/listers/lattice/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 
 	xdsapi "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2"
 	xdsservicenode "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/service_node"
@@ -53,6 +55,9 @@ type KubernetesPerNodeBackend struct {
 	addressLister       latticelisters.AddressLister
 	addressListerSynced cache.InformerSynced
 
+	systemLister       latticelisters.SystemLister
+	systemListerSynced cache.InformerSynced
+
 	queue workqueue.RateLimitingInterface
 
 	count     int
@@ -106,6 +111,7 @@ func NewKubernetesPerNodeBackend(kubeconfig string, redirectCIDRBlock *net.IPNet
 	kubeEndpointInformer := kubeInformers.Core().V1().Endpoints()
 	serviceInformer := latticeInformers.Lattice().V1().Services()
 	addressInformer := latticeInformers.Lattice().V1().Addresses()
+	systemInformer := latticeInformers.Lattice().V1().Systems()
 
 	b := &KubernetesPerNodeBackend{
 		serviceMesh:              serviceMesh,
@@ -115,6 +121,8 @@ func NewKubernetesPerNodeBackend(kubeconfig string, redirectCIDRBlock *net.IPNet
 		serviceListerSynced:      serviceInformer.Informer().HasSynced,
 		addressLister:            addressInformer.Lister(),
 		addressListerSynced:      addressInformer.Informer().HasSynced,
+		systemLister:             systemInformer.Lister(),
+		systemListerSynced:       systemInformer.Informer().HasSynced,
 		queue:                    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "envoy-api-backend"),
 		stopCh:                   stopCh,
 	}
@@ -155,6 +163,26 @@ func NewKubernetesPerNodeBackend(kubeconfig string, redirectCIDRBlock *net.IPNet
 		},
 	}, time.Duration(12*time.Hour))
 
+	// systems are only watched for their tracing sampling rate
+	systemInformer.Informer().AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			_old := old.(*latticev1.System)
+			_cur := cur.(*latticev1.System)
+			oldRate := _old.Annotations[latticev1.SystemTracingSamplingRateAnnotationKey]
+			curRate := _cur.Annotations[latticev1.SystemTracingSamplingRateAnnotationKey]
+			if oldRate == curRate {
+				return
+			}
+
+			task, err := b.enqueueSystemCacheUpdateTask(_cur)
+			if err != nil {
+				runtime.HandleError(err)
+			} else if task != "" {
+				glog.V(4).Infof("Got Lattice System \"Update\" event: %s", task)
+			}
+		},
+	}, time.Duration(12*time.Hour))
+
 	return b, nil
 }
 
@@ -188,6 +216,16 @@ func (b *KubernetesPerNodeBackend) ClearXDSCacheSnapshot(id string) error {
 }
 
 func (b *KubernetesPerNodeBackend) enqueueCacheUpdateTask(_type xdsapi.EntityType, event xdsapi.Event, obj interface{}) (string, error) {
+	taskKey, err := b.cacheUpdateTaskKey(_type, event, obj)
+	if err != nil {
+		return "", err
+	}
+
+	b.queue.Add(taskKey)
+	return taskKey, nil
+}
+
+func (b *KubernetesPerNodeBackend) cacheUpdateTaskKey(_type xdsapi.EntityType, event xdsapi.Event, obj interface{}) (string, error) {
 	var err error
 	var ok bool
 	var name string
@@ -229,13 +267,30 @@ func (b *KubernetesPerNodeBackend) enqueueCacheUpdateTask(_type xdsapi.EntityTyp
 		return "", err
 	}
 
-	taskKey := string(task[:])
-	b.queue.Add(taskKey)
-	return taskKey, nil
+	return string(task[:]), nil
+}
+
+// enqueueSystemCacheUpdateTask enqueues an update of the service nodes of the system.
+// Service nodes are grouped by their namespace, so an update task for any one of the
+// system's services is enqueued.
+func (b *KubernetesPerNodeBackend) enqueueSystemCacheUpdateTask(system *latticev1.System) (string, error) {
+	services, err := b.serviceLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+
+	for _, service := range services {
+		if owner := metav1.GetControllerOf(service); owner != nil && owner.UID == system.UID {
+			return b.enqueueCacheUpdateTask(xdsapi.LatticeEntityType, xdsapi.InformerUpdateEvent, service)
+		}
+	}
+
+	// the system does not have any services yet, so there are no service nodes to update
+	return "", nil
 }
 
 func (b *KubernetesPerNodeBackend) Ready() bool {
-	return cache.WaitForCacheSync(b.stopCh, b.serviceListerSynced)
+	return cache.WaitForCacheSync(b.stopCh, b.serviceListerSynced, b.systemListerSynced)
 }
 
 func (b *KubernetesPerNodeBackend) Run(threadiness int) error {
@@ -428,6 +483,11 @@ func (b *KubernetesPerNodeBackend) SystemServices(serviceCluster string) (map[tr
 		return nil, err
 	}
 
+	samplingRates, err := b.tracingSamplingRates()
+	if err != nil {
+		return nil, err
+	}
+
 	for _, service := range services {
 		path, err := service.PathLabel()
 		if err != nil {
@@ -479,20 +539,22 @@ func (b *KubernetesPerNodeBackend) SystemServices(serviceCluster string) (map[tr
 			}
 		}
 
+		envoyPorts, err := b.serviceMesh.EnvoyServiceMeshPorts(service)
+		if err != nil {
+			return nil, err
+		}
+
 		// FIXME: we should reevaluate these structures. Component isn't a thing anymore.
 		mainContainer := xdsapi.Component{
 			Ports: make(map[int32]xdsapi.ListenerPort),
 		}
 		for port, containerPort := range service.Spec.Definition.Ports {
-			envoyPort, err := b.serviceMesh.ServiceMeshPort(service, port)
+			listenerPort, err := newListenerPort(service, envoyPorts, port, containerPort)
 			if err != nil {
 				return nil, err
 			}
 
-			mainContainer.Ports[port] = xdsapi.ListenerPort{
-				Port:     envoyPort,
-				Protocol: containerPort.Protocol,
-			}
+			mainContainer.Ports[port] = listenerPort
 		}
 		xdsService.Components[kubernetes.UserMainContainerName] = mainContainer
 
@@ -501,25 +563,141 @@ func (b *KubernetesPerNodeBackend) SystemServices(serviceCluster string) (map[tr
 				Ports: make(map[int32]xdsapi.ListenerPort),
 			}
 			for port, containerPort := range sidecar.Ports {
-				envoyPort, err := b.serviceMesh.ServiceMeshPort(service, port)
+				listenerPort, err := newListenerPort(service, envoyPorts, port, containerPort)
 				if err != nil {
 					return nil, err
 				}
 
-				c.Ports[port] = xdsapi.ListenerPort{
-					Port:     envoyPort,
-					Protocol: containerPort.Protocol,
-				}
+				c.Ports[port] = listenerPort
 			}
 			xdsService.Components[kubernetes.UserSidecarContainerName(name)] = c
 		}
 
+		xdsService.Fault, err = b.serviceFault(service)
+		if err != nil {
+			return nil, err
+		}
+
+		xdsService.TracingSamplingRate = tracingSamplingRate(service, samplingRates)
+
 		result[path] = xdsService
 	}
 
 	return result, nil
 }
 
+func newListenerPort(
+	service *latticev1.Service,
+	envoyPorts map[int32]envoy.EnvoyServiceMeshPort,
+	port int32,
+	containerPort definitionv1.ContainerPort,
+) (xdsapi.ListenerPort, error) {
+	envoyPort, ok := envoyPorts[port]
+	if !ok {
+		err := fmt.Errorf(
+			"service %v/%v does not have expected port %v",
+			service.Namespace,
+			service.Name,
+			port,
+		)
+		return xdsapi.ListenerPort{}, err
+	}
+
+	listenerPort := xdsapi.ListenerPort{
+		Port:     envoyPort.Port,
+		Protocol: envoyPort.Protocol,
+	}
+	if envoyPort.Protocol == definitionv1.ContainerPortProtocolGRPC {
+		listenerPort.GRPC = containerPort.GRPC
+	}
+
+	return listenerPort, nil
+}
+
+// tracingSamplingRates returns the tracing sampling rate of each system that
+// sets a valid one, by the system's UID.
+func (b *KubernetesPerNodeBackend) tracingSamplingRates() (map[types.UID]float64, error) {
+	systems, err := b.systemLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	samplingRates := make(map[types.UID]float64)
+	for _, system := range systems {
+		// one system's invalid sampling rate shouldn't break every system's
+		// snapshots, so its services fall back to the service mesh's default
+		samplingRate, err := system.TracingSamplingRateAnnotation()
+		if err != nil {
+			glog.Warningf("error getting tracing sampling rate of system %v: %v", system.Name, err)
+			continue
+		}
+
+		if samplingRate != nil {
+			samplingRates[system.UID] = *samplingRate
+		}
+	}
+
+	return samplingRates, nil
+}
+
+// tracingSamplingRate returns the tracing sampling rate of the system the service
+// belongs to, or nil if the system does not set one.
+func tracingSamplingRate(service *latticev1.Service, samplingRates map[types.UID]float64) *float64 {
+	owner := metav1.GetControllerOf(service)
+	if owner == nil || owner.Kind != latticev1.SystemKind.Kind {
+		return nil
+	}
+
+	samplingRate, ok := samplingRates[owner.UID]
+	if !ok {
+		return nil
+	}
+
+	return &samplingRate
+}
+
+// serviceFault returns the fault that should currently be injected into the service's traffic,
+// or nil if there isn't one. If there is a fault, a cache update for the service is scheduled for
+// when the fault expires so that the fault is removed.
+func (b *KubernetesPerNodeBackend) serviceFault(service *latticev1.Service) (*xdsapi.Fault, error) {
+	fault, err := service.FaultAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if fault == nil || fault.Expired(now) {
+		return nil, nil
+	}
+
+	taskKey, err := b.cacheUpdateTaskKey(xdsapi.LatticeEntityType, xdsapi.InformerUpdateEvent, service)
+	if err != nil {
+		return nil, err
+	}
+	b.queue.AddAfter(taskKey, fault.Expires.Sub(now))
+
+	xdsFault := &xdsapi.Fault{
+		Percentage: uint32(fault.Percentage),
+	}
+
+	if fault.Delay != nil {
+		delay := fault.Delay.Duration.Duration
+		xdsFault.Delay = &delay
+	}
+
+	if fault.Abort != nil {
+		status := uint32(fault.Abort.HTTPStatus)
+		xdsFault.AbortHTTPStatus = &status
+	}
+
+	if fault.Source != nil {
+		// envoy service nodes are identified by the domain of their service's path
+		xdsFault.SourceNodes = []string{fault.Source.ToDomain()}
+	}
+
+	return xdsFault, nil
+}
+
 // interface implementations
 
 // github.com/envoyproxy/go-control-plane/pkg/cache#NodeHash{} -- for b.xdsCache
@@ -7,4 +7,9 @@ import (
 const (
 	ClusterConnectTimeout     = time.Duration(250) * time.Millisecond
 	ClusterLBPolicyRoundRobin = "ROUND_ROBIN"
+
+	ClusterHealthCheckTimeout            = time.Duration(1) * time.Second
+	ClusterHealthCheckInterval           = time.Duration(10) * time.Second
+	ClusterHealthCheckUnhealthyThreshold = 3
+	ClusterHealthCheckHealthyThreshold   = 1
 )
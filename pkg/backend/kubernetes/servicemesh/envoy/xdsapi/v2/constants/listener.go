@@ -9,8 +9,9 @@ const (
 	// envoy listener filter names
 	OriginalDestinationListenerFilterName = "envoy.listener.original_dst"
 
-	// envoy http router filter name
+	// envoy http filter names
 	HTTPRouterFilterName = "envoy.router"
+	HTTPFaultFilterName  = "envoy.fault"
 
 	// lattice egress listener names
 	HTTPEgressListenerName = "egress-http"
@@ -6,6 +6,8 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
 	xdsapi "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2"
 	xdsconstants "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/constants"
 	xdsmsgs "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/service_node/messages"
@@ -34,14 +36,16 @@ func (s *ServiceNode) getClusters(
 		isLocalService := servicePath == path
 
 		for componentName, component := range service.Components {
-			for port := range component.Ports {
+			for port, listenerPort := range component.Ports {
 				clusterName := xdsutil.GetClusterNameForComponentPort(
 					s.ServiceCluster(), path, componentName, port)
 
 				clusters = append(clusters, xdsmsgs.NewEdsCluster(
 					clusterName,
 					xdsconstants.ClusterConnectTimeout,
-					xdsconstants.ClusterLBPolicyRoundRobin))
+					xdsconstants.ClusterLBPolicyRoundRobin,
+					listenerPort.HTTP2(),
+					healthChecks(listenerPort)))
 
 				if isLocalService {
 					clusterName = xdsutil.GetLocalClusterNameForComponentPort(
@@ -51,6 +55,7 @@ func (s *ServiceNode) getClusters(
 						clusterName,
 						xdsconstants.ClusterConnectTimeout,
 						xdsconstants.ClusterLBPolicyRoundRobin,
+						listenerPort.HTTP2(),
 						[]*envoycore.Address{xdsmsgs.NewTcpSocketAddress(xdsconstants.Localhost, port)}))
 				}
 			}
@@ -59,3 +64,26 @@ func (s *ServiceNode) getClusters(
 
 	return clusters, err
 }
+
+// healthChecks returns the active health checks envoy should perform against
+// the upstream hosts of a port. Currently only GRPC ports are actively health
+// checked, using the gRPC health checking protocol.
+func healthChecks(listenerPort xdsapi.ListenerPort) []*envoycore.HealthCheck {
+	if listenerPort.Protocol != definitionv1.ContainerPortProtocolGRPC {
+		return nil
+	}
+
+	var serviceName string
+	if listenerPort.GRPC != nil {
+		serviceName = listenerPort.GRPC.HealthCheckService
+	}
+
+	return []*envoycore.HealthCheck{
+		xdsmsgs.NewGrpcHealthCheck(
+			serviceName,
+			xdsconstants.ClusterHealthCheckTimeout,
+			xdsconstants.ClusterHealthCheckInterval,
+			xdsconstants.ClusterHealthCheckUnhealthyThreshold,
+			xdsconstants.ClusterHealthCheckHealthyThreshold),
+	}
+}
@@ -12,6 +12,8 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
 	xdsapi "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2"
 	xdsconstants "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/constants"
 	xdsmsgs "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/service_node/messages"
@@ -26,7 +28,11 @@ func (s *ServiceNode) newHTTPEgressListener(service *xdsapi.Service) *envoyv2.Li
 
 	filters := []envoylistener.Filter{
 		*xdsmsgs.NewRdsHttpConnectionManagerFilter(
-			xdsconstants.HTTPEgressStatPrefix, xdsconstants.RouteNameEgress, httpFilters),
+			xdsconstants.HTTPEgressStatPrefix,
+			xdsconstants.RouteNameEgress,
+			httpFilters,
+			xdsmsgs.NewHttpConnectionManagerTracing(
+				envoyhttpcxnmgr.EGRESS, service.TracingSamplingRate)),
 	}
 
 	filterChains := []envoylistener.FilterChain{
@@ -46,7 +52,7 @@ func (s *ServiceNode) newTCPEgressListener(
 		for componentName, component := range _service.Components {
 			for servicePort, listenerPort := range component.Ports {
 				// only add routes for TCP services
-				if listenerPort.Protocol != "TCP" {
+				if listenerPort.Protocol != definitionv1.ContainerPortProtocolTCP {
 					continue
 				}
 				clusterName := xdsutil.GetClusterNameForComponentPort(
@@ -75,39 +81,47 @@ func (s *ServiceNode) newTCPEgressListener(
 
 func (s *ServiceNode) newHTTPIngressListener(
 	path tree.Path,
+	service *xdsapi.Service,
 	listenerName, componentName string,
-	servicePort, envoyPort int32) *envoyv2.Listener {
-	httpFilters := []*envoyhttpcxnmgr.HttpFilter{
-		xdsmsgs.NewHttpRouterFilter(),
-	}
+	servicePort int32,
+	listenerPort xdsapi.ListenerPort) *envoyv2.Listener {
+	httpFilters := make([]*envoyhttpcxnmgr.HttpFilter, 0)
 
-	routes := []envoyroute.Route{
-		*xdsmsgs.NewRouteRoute(
-			xdsmsgs.NewPrefixRouteMatch("/"),
-			xdsmsgs.NewClusterRouteActionRouteRoute(
-				xdsutil.GetLocalClusterNameForComponentPort(
-					s.ServiceCluster(), path, componentName, servicePort))),
+	// the fault filter has to come before the router so it can act on requests
+	// before they are routed to the component
+	if fault := service.Fault; fault != nil {
+		httpFilters = append(httpFilters, xdsmsgs.NewHttpFaultFilter(
+			fault.Delay, fault.AbortHTTPStatus, fault.Percentage, fault.SourceNodes))
 	}
 
+	httpFilters = append(httpFilters, xdsmsgs.NewHttpRouterFilter())
+
+	clusterName := xdsutil.GetLocalClusterNameForComponentPort(
+		s.ServiceCluster(), path, componentName, servicePort)
+
 	virtualHosts := []envoyroute.VirtualHost{
 		*xdsmsgs.NewVirtualHost(
 			fmt.Sprintf("%v %v port %v", path, componentName, servicePort),
 			[]string{"*"},
-			routes),
+			portRoutes(clusterName, listenerPort)),
 	}
 
 	// FIXME: add health_check filter
-	// FIXME: look into other filters (buffer, potentially add fault injection for testing)
+	// FIXME: look into other filters (buffer)
 	filters := []envoylistener.Filter{
 		*xdsmsgs.NewStaticHttpConnectionManagerFilter(
-			listenerName, virtualHosts, httpFilters),
+			listenerName,
+			virtualHosts,
+			httpFilters,
+			xdsmsgs.NewHttpConnectionManagerTracing(
+				envoyhttpcxnmgr.INGRESS, service.TracingSamplingRate)),
 	}
 
 	filterChains := []envoylistener.FilterChain{
 		*xdsmsgs.NewFilterChain(nil, nil, false, filters),
 	}
 
-	address := xdsmsgs.NewTcpSocketAddress("0.0.0.0", envoyPort)
+	address := xdsmsgs.NewTcpSocketAddress("0.0.0.0", listenerPort.Port)
 
 	return xdsmsgs.NewListener(listenerName, address, filterChains)
 }
@@ -165,10 +179,15 @@ func (s *ServiceNode) getListeners(
 				"%v %v port %v %v ingress", path, componentName, port, listenerPort.Protocol)
 
 			switch listenerPort.Protocol {
-			case "HTTP":
+			// the HTTP connection manager detects whether downstream connections
+			// are speaking HTTP/1.1 or HTTP/2, so HTTP2 and GRPC ports share the
+			// HTTP listener
+			case definitionv1.ContainerPortProtocolHTTP,
+				definitionv1.ContainerPortProtocolHTTP2,
+				definitionv1.ContainerPortProtocolGRPC:
 				listener = s.newHTTPIngressListener(
-					path, listenerName, componentName, port, listenerPort.Port)
-			case "TCP":
+					path, service, listenerName, componentName, port, listenerPort)
+			case definitionv1.ContainerPortProtocolTCP:
 				listener = s.newTCPIngressListener(
 					path, listenerName, componentName, port, listenerPort.Port)
 			default:
@@ -3,6 +3,8 @@ package messages
 import (
 	"time"
 
+	pbtypes "github.com/gogo/protobuf/types"
+
 	envoyv2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 )
@@ -10,7 +12,9 @@ import (
 func NewEdsCluster(
 	clusterName string,
 	connectTimeout time.Duration,
-	lbPolicy string) *envoyv2.Cluster {
+	lbPolicy string,
+	http2 bool,
+	healthChecks []*envoycore.HealthCheck) *envoyv2.Cluster {
 	return &envoyv2.Cluster{
 		Name: clusterName,
 		Type: envoyv2.Cluster_EDS,
@@ -25,6 +29,8 @@ func NewEdsCluster(
 			},
 			ServiceName: clusterName,
 		},
+		Http2ProtocolOptions: newHttp2ProtocolOptions(http2),
+		HealthChecks:         healthChecks,
 	}
 }
 
@@ -32,12 +38,45 @@ func NewStaticCluster(
 	clusterName string,
 	connectTimeout time.Duration,
 	lbPolicy string,
+	http2 bool,
 	addresses []*envoycore.Address) *envoyv2.Cluster {
 	return &envoyv2.Cluster{
-		Name:           clusterName,
-		Type:           envoyv2.Cluster_STATIC,
-		ConnectTimeout: connectTimeout,
-		LbPolicy:       stringToClusterLbPolicy(lbPolicy),
-		Hosts:          addresses,
+		Name:                 clusterName,
+		Type:                 envoyv2.Cluster_STATIC,
+		ConnectTimeout:       connectTimeout,
+		LbPolicy:             stringToClusterLbPolicy(lbPolicy),
+		Hosts:                addresses,
+		Http2ProtocolOptions: newHttp2ProtocolOptions(http2),
+	}
+}
+
+// NewGrpcHealthCheck returns a health check using the gRPC health checking protocol
+// (grpc.health.v1.Health) for the supplied service name.
+func NewGrpcHealthCheck(
+	serviceName string,
+	timeout, interval time.Duration,
+	unhealthyThreshold, healthyThreshold uint32) *envoycore.HealthCheck {
+	return &envoycore.HealthCheck{
+		Timeout:  &timeout,
+		Interval: &interval,
+		UnhealthyThreshold: &pbtypes.UInt32Value{
+			Value: unhealthyThreshold,
+		},
+		HealthyThreshold: &pbtypes.UInt32Value{
+			Value: healthyThreshold,
+		},
+		HealthChecker: &envoycore.HealthCheck_GrpcHealthCheck_{
+			GrpcHealthCheck: &envoycore.HealthCheck_GrpcHealthCheck{
+				ServiceName: serviceName,
+			},
+		},
+	}
+}
+
+// clusters only speak HTTP/2 to their upstream hosts if the protocol options are set
+func newHttp2ProtocolOptions(http2 bool) *envoycore.Http2ProtocolOptions {
+	if !http2 {
+		return nil
 	}
+	return &envoycore.Http2ProtocolOptions{}
 }
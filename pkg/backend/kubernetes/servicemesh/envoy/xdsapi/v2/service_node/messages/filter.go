@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 	// "strings"
 
 	"github.com/golang/glog"
@@ -16,12 +17,16 @@ import (
 	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoylistener "github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
 	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	envoyfault "github.com/envoyproxy/go-control-plane/envoy/config/filter/fault/v2"
+	envoyhttpfault "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/fault/v2"
 	envoyhttprouter "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/router/v2"
 	envoyhttpcxnmgr "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
 	envoytcpproxy "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/tcp_proxy/v2"
+	envoytype "github.com/envoyproxy/go-control-plane/envoy/type"
 	envoyutil "github.com/envoyproxy/go-control-plane/pkg/util"
 
 	xdsconstants "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2/constants"
+	"github.com/mlab-lattice/lattice/pkg/tracing"
 )
 
 // -------------
@@ -57,16 +62,75 @@ func NewHttpRouterFilter() *envoyhttpcxnmgr.HttpFilter {
 	}
 }
 
+// NewHttpFaultFilter returns a fault filter that injects a fixed delay (if delay is non-nil)
+// or aborts with the given HTTP status (if abortHTTPStatus is non-nil) into percent of the
+// requests. If downstreamNodes is not empty, only requests from those nodes are affected.
+func NewHttpFaultFilter(
+	delay *time.Duration,
+	abortHTTPStatus *uint32,
+	percent uint32,
+	downstreamNodes []string) *envoyhttpcxnmgr.HttpFilter {
+	filterConfig := envoyhttpfault.HTTPFault{
+		DownstreamNodes: downstreamNodes,
+	}
+
+	if delay != nil {
+		filterConfig.Delay = &envoyfault.FaultDelay{
+			Type:    envoyfault.FaultDelay_FIXED,
+			Percent: percent,
+			FaultDelaySecifier: &envoyfault.FaultDelay_FixedDelay{
+				FixedDelay: delay,
+			},
+		}
+	}
+
+	if abortHTTPStatus != nil {
+		filterConfig.Abort = &envoyhttpfault.FaultAbort{
+			Percent: percent,
+			ErrorType: &envoyhttpfault.FaultAbort_HttpStatus{
+				HttpStatus: *abortHTTPStatus,
+			},
+		}
+	}
+
+	filterConfigPBStruct, err := envoyutil.MessageToStruct(&filterConfig)
+	if err != nil {
+		panic(fmt.Sprintf("error serializing http fault filter: %v", err))
+	}
+	return &envoyhttpcxnmgr.HttpFilter{
+		Name:   xdsconstants.HTTPFaultFilterName,
+		Config: filterConfigPBStruct,
+	}
+}
+
 // ---------------
 // network filters
 // ---------------
 
 // HTTP connection manager
 
+// NewHttpConnectionManagerTracing returns tracing configuration for an HTTP connection manager.
+// If samplingRate is non-nil, only that fraction of requests will be randomly sampled,
+// otherwise envoy's default is used.
+func NewHttpConnectionManagerTracing(
+	operationName envoyhttpcxnmgr.HttpConnectionManager_Tracing_OperationName,
+	samplingRate *float64) *envoyhttpcxnmgr.HttpConnectionManager_Tracing {
+	config := &envoyhttpcxnmgr.HttpConnectionManager_Tracing{
+		OperationName: operationName,
+	}
+	if samplingRate != nil {
+		config.RandomSampling = &envoytype.Percent{
+			Value: tracing.SamplingPercentage(*samplingRate),
+		}
+	}
+	return config
+}
+
 func NewRdsHttpConnectionManagerFilter(
 	statPrefix string,
 	routeConfigName string,
-	httpFilters []*envoyhttpcxnmgr.HttpFilter) *envoylistener.Filter {
+	httpFilters []*envoyhttpcxnmgr.HttpFilter,
+	tracing *envoyhttpcxnmgr.HttpConnectionManager_Tracing) *envoylistener.Filter {
 	filterConfig := envoyhttpcxnmgr.HttpConnectionManager{
 		CodecType:  envoyhttpcxnmgr.AUTO,
 		StatPrefix: statPrefix,
@@ -81,6 +145,13 @@ func NewRdsHttpConnectionManagerFilter(
 			},
 		},
 		HttpFilters: httpFilters,
+		// Adds the x-envoy-downstream-service-node header to requests so that
+		// upstream fault filters can match on the request's source.
+		AddUserAgent: &pbtypes.BoolValue{Value: true},
+		// Generates an x-request-id for requests that don't have one so that
+		// the request can be followed across services.
+		GenerateRequestId: &pbtypes.BoolValue{Value: true},
+		Tracing:           tracing,
 	}
 	filterConfigPBStruct, err := envoyutil.MessageToStruct(&filterConfig)
 	if err != nil {
@@ -95,7 +166,8 @@ func NewRdsHttpConnectionManagerFilter(
 func NewStaticHttpConnectionManagerFilter(
 	statPrefix string,
 	virtualHosts []envoyroute.VirtualHost,
-	httpFilters []*envoyhttpcxnmgr.HttpFilter) *envoylistener.Filter {
+	httpFilters []*envoyhttpcxnmgr.HttpFilter,
+	tracing *envoyhttpcxnmgr.HttpConnectionManager_Tracing) *envoylistener.Filter {
 	filterConfig := envoyhttpcxnmgr.HttpConnectionManager{
 		CodecType:  envoyhttpcxnmgr.AUTO,
 		StatPrefix: statPrefix,
@@ -104,7 +176,9 @@ func NewStaticHttpConnectionManagerFilter(
 				VirtualHosts: virtualHosts,
 			},
 		},
-		HttpFilters: httpFilters,
+		HttpFilters:       httpFilters,
+		GenerateRequestId: &pbtypes.BoolValue{Value: true},
+		Tracing:           tracing,
 	}
 	filterConfigPBStruct, err := envoyutil.MessageToStruct(&filterConfig)
 	if err != nil {
@@ -30,7 +30,7 @@ func (s *ServiceNode) getRoutes(
 	for path, service := range systemServices {
 		for componentName, component := range service.Components {
 			for servicePort, listenerPort := range component.Ports {
-				if listenerPort.Protocol != "HTTP" {
+				if !listenerPort.HTTPBased() {
 					continue
 				}
 				domain := fmt.Sprintf("%v.local", path.ToDomain())
@@ -42,15 +42,11 @@ func (s *ServiceNode) getRoutes(
 				if servicePort == xdsconstants.PortHTTPDefault {
 					domains = append(domains, domain)
 				}
+				clusterName := xdsutil.GetClusterNameForComponentPort(
+					s.ServiceCluster(), path, componentName, servicePort)
 				virtualHosts = append(
 					virtualHosts, *xdsmsgs.NewVirtualHost(
-						string(path), domains, []envoyroute.Route{
-							*xdsmsgs.NewRouteRoute(
-								xdsmsgs.NewPrefixRouteMatch("/"),
-								xdsmsgs.NewClusterRouteActionRouteRoute(
-									xdsutil.GetClusterNameForComponentPort(
-										s.ServiceCluster(), path, componentName, servicePort))),
-						}))
+						string(path), domains, portRoutes(clusterName, listenerPort)))
 			}
 		}
 	}
@@ -59,3 +55,26 @@ func (s *ServiceNode) getRoutes(
 		xdsmsgs.NewRouteConfiguration(xdsconstants.RouteNameEgress, virtualHosts),
 	}, err
 }
+
+// portRoutes returns the routes to the cluster for the port.
+// gRPC requests are made to /<package>.<service>/<method>, so if the port
+// lists the gRPC services it serves, only requests for those services'
+// methods are routed to it.
+func portRoutes(clusterName string, listenerPort xdsapi.ListenerPort) []envoyroute.Route {
+	prefixes := []string{"/"}
+	if listenerPort.GRPC != nil && len(listenerPort.GRPC.Services) > 0 {
+		prefixes = make([]string, 0, len(listenerPort.GRPC.Services))
+		for _, service := range listenerPort.GRPC.Services {
+			prefixes = append(prefixes, fmt.Sprintf("/%v/", service))
+		}
+	}
+
+	routes := make([]envoyroute.Route, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		routes = append(routes, *xdsmsgs.NewRouteRoute(
+			xdsmsgs.NewPrefixRouteMatch(prefix),
+			xdsmsgs.NewClusterRouteActionRouteRoute(clusterName)))
+	}
+
+	return routes
+}
@@ -0,0 +1,59 @@
+package servicenode
+
+import (
+	"testing"
+
+	xdsapi "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy/xdsapi/v2"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoyroute "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+
+	"github.com/stretchr/testify/require"
+)
+
+func routePrefixes(routes []envoyroute.Route) []string {
+	var prefixes []string
+	for _, route := range routes {
+		prefixes = append(prefixes, route.Match.PathSpecifier.(*envoyroute.RouteMatch_Prefix).Prefix)
+	}
+	return prefixes
+}
+
+func TestPortRoutes(t *testing.T) {
+	httpPort := xdsapi.ListenerPort{Port: 10000, Protocol: definitionv1.ContainerPortProtocolHTTP}
+	require.Equal(t, []string{"/"}, routePrefixes(portRoutes("cluster", httpPort)))
+
+	grpcPort := xdsapi.ListenerPort{
+		Port:     10001,
+		Protocol: definitionv1.ContainerPortProtocolGRPC,
+		GRPC:     &definitionv1.ContainerPortGRPC{},
+	}
+	require.Equal(t, []string{"/"}, routePrefixes(portRoutes("cluster", grpcPort)))
+
+	// only the methods of the listed gRPC services are routed to the port
+	grpcPort.GRPC.Services = []string{"helloworld.Greeter", "grpc.health.v1.Health"}
+	routes := portRoutes("cluster", grpcPort)
+	require.Equal(t, []string{"/helloworld.Greeter/", "/grpc.health.v1.Health/"}, routePrefixes(routes))
+	for _, route := range routes {
+		action := route.Action.(*envoyroute.Route_Route).Route.ClusterSpecifier.(*envoyroute.RouteAction_Cluster)
+		require.Equal(t, "cluster", action.Cluster)
+	}
+}
+
+func TestHealthChecks(t *testing.T) {
+	require.Empty(t, healthChecks(xdsapi.ListenerPort{Protocol: definitionv1.ContainerPortProtocolHTTP2}))
+
+	checks := healthChecks(xdsapi.ListenerPort{Protocol: definitionv1.ContainerPortProtocolGRPC})
+	require.Len(t, checks, 1)
+	grpc := checks[0].HealthChecker.(*envoycore.HealthCheck_GrpcHealthCheck_).GrpcHealthCheck
+	require.Empty(t, grpc.ServiceName)
+
+	checks = healthChecks(xdsapi.ListenerPort{
+		Protocol: definitionv1.ContainerPortProtocolGRPC,
+		GRPC:     &definitionv1.ContainerPortGRPC{HealthCheckService: "helloworld.Greeter"},
+	})
+	require.Len(t, checks, 1)
+	grpc = checks[0].HealthChecker.(*envoycore.HealthCheck_GrpcHealthCheck_).GrpcHealthCheck
+	require.Equal(t, "helloworld.Greeter", grpc.ServiceName)
+}
@@ -1,7 +1,11 @@
 package v2
 
 import (
+	"time"
+
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy"
+
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 )
 
 type Service struct {
@@ -9,6 +13,11 @@ type Service struct {
 	Components  map[string]Component
 	ServiceIP   string
 	EndpointIPs []string
+	Fault       *Fault
+
+	// TracingSamplingRate is the fraction (0-1) of requests to the Service that should be traced.
+	// If nil, envoy's default is used.
+	TracingSamplingRate *float64
 }
 
 type Component struct {
@@ -19,6 +28,32 @@ type Component struct {
 type ListenerPort struct {
 	Port     int32
 	Protocol string
+
+	// GRPC is only set for ports speaking the GRPC protocol.
+	GRPC *definitionv1.ContainerPortGRPC
+}
+
+// HTTPBased returns whether the port speaks a protocol that is handled by envoy's
+// HTTP connection manager.
+func (p ListenerPort) HTTPBased() bool {
+	return definitionv1.ContainerPort{Protocol: p.Protocol}.HTTPBased()
+}
+
+// HTTP2 returns whether envoy should speak HTTP/2 to the port's upstream hosts.
+func (p ListenerPort) HTTP2() bool {
+	return definitionv1.ContainerPort{Protocol: p.Protocol}.HTTP2()
+}
+
+// Fault is a fault that should be injected into the HTTP traffic sent to a Service.
+// Exactly one of Delay or AbortHTTPStatus will be set.
+type Fault struct {
+	Delay           *time.Duration
+	AbortHTTPStatus *uint32
+	Percentage      uint32
+
+	// SourceNodes limits the fault to requests coming from the listed envoy service nodes.
+	// If empty, the fault is applied to all requests.
+	SourceNodes []string
 }
 
 type EntityType int
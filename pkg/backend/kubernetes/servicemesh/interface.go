@@ -6,11 +6,13 @@ import (
 	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
 	systembootstrapper "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/lifecycle/system/bootstrap/bootstrapper"
 	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/envoy"
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/servicemesh/none"
 	"github.com/mlab-lattice/lattice/pkg/util/cli"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kubeclientset "k8s.io/client-go/kubernetes"
 )
 
 type Interface interface {
@@ -62,15 +64,18 @@ type Interface interface {
 
 type Options struct {
 	Envoy *envoy.Options
+	None  *none.Options
 }
 
-func NewServiceMesh(options *Options) (Interface, error) {
+func NewServiceMesh(kubeClient kubeclientset.Interface, options *Options) (Interface, error) {
 	var serviceMesh Interface
 	var err error
 
 	switch {
 	case options.Envoy != nil:
 		serviceMesh, err = envoy.NewEnvoyServiceMesh(options.Envoy)
+	case options.None != nil:
+		serviceMesh, err = none.NewNoneServiceMesh(kubeClient, options.None)
 	default:
 		err = fmt.Errorf("must provide service mesh options")
 	}
@@ -95,18 +100,38 @@ func OverlayConfigOptions(staticOptions *Options, dynamicConfig *latticev1.Confi
 		return options, nil
 	}
 
+	if staticOptions.None != nil {
+		if dynamicConfig.None == nil {
+			return nil, fmt.Errorf("static options were for none but dynamic config did not have none options set")
+		}
+
+		noneOptions, err := none.NewOptions(staticOptions.None, dynamicConfig.None)
+		if err != nil {
+			return nil, err
+		}
+
+		options := &Options{
+			None: noneOptions,
+		}
+		return options, nil
+	}
+
 	return nil, fmt.Errorf("must provide service mesh options")
 }
 
 func Flag(serviceMesh *string) (cli.Flag, *Options) {
 	envoyFlags, envoyOptions := envoy.Flags()
+	noneFlags, noneOptions := none.Flags()
 	options := &Options{}
 
 	flag := &flags.DelayedEmbedded{
-		Required: true,
+		// the none service mesh does not take any variables, so the flag itself
+		// cannot be required. the envoy variables are still required if envoy is chosen.
+		Required: false,
 		Usage:    "configuration for the service mesh",
 		Flags: map[string]cli.Flags{
 			Envoy: envoyFlags,
+			None:  noneFlags,
 		},
 		FlagChooser: func() (*string, error) {
 			if serviceMesh == nil {
@@ -116,6 +141,8 @@ func Flag(serviceMesh *string) (cli.Flag, *Options) {
 			switch *serviceMesh {
 			case Envoy:
 				options.Envoy = envoyOptions
+			case None:
+				options.None = noneOptions
 			default:
 				return nil, fmt.Errorf("unsupported service mesh %v", *serviceMesh)
 			}
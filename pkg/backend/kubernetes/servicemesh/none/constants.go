@@ -0,0 +1,5 @@
+package none
+
+const (
+	None = "none"
+)
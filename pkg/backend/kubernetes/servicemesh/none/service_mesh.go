@@ -0,0 +1,215 @@
+package none
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/mlab-lattice/lattice/pkg/backend/kubernetes/lifecycle/system/bootstrap/bootstrapper"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	kubeutil "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/util/kubernetes"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubeclientset "k8s.io/client-go/kubernetes"
+)
+
+const (
+	annotationKeyIP = "none.servicemesh.lattice.mlab.com/ip"
+
+	clusterIPKubeServiceSuffix = "-cluster-ip"
+)
+
+type Options struct {
+}
+
+func NewOptions(staticOptions *Options, dynamicConfig *latticev1.ConfigServiceMeshNone) (*Options, error) {
+	return &Options{}, nil
+}
+
+func Flags() (cli.Flags, *Options) {
+	return cli.Flags{}, &Options{}
+}
+
+// NewNoneServiceMesh returns a service mesh that relies on plain kubernetes networking.
+// No sidecars are added to pods, and services are reached via the ClusterIP of a
+// kubernetes Service load balanced by kube-proxy.
+func NewNoneServiceMesh(kubeClient kubeclientset.Interface, options *Options) (*DefaultNoneServiceMesh, error) {
+	return &DefaultNoneServiceMesh{
+		kubeClient: kubeClient,
+	}, nil
+}
+
+type DefaultNoneServiceMesh struct {
+	kubeClient kubeclientset.Interface
+}
+
+func (sm *DefaultNoneServiceMesh) BootstrapSystemResources(resources *bootstrapper.SystemResources) {
+}
+
+func (sm *DefaultNoneServiceMesh) ServiceAnnotations(service *latticev1.Service) (map[string]string, error) {
+	return make(map[string]string), nil
+}
+
+func (sm *DefaultNoneServiceMesh) ServiceAddressAnnotations(address *latticev1.Address) (map[string]string, error) {
+	annotations := map[string]string{
+		annotationKeyIP: address.Annotations[annotationKeyIP],
+	}
+	return annotations, nil
+}
+
+func (sm *DefaultNoneServiceMesh) TransformServicePodTemplateSpec(
+	service *latticev1.Service,
+	spec *corev1.PodTemplateSpec,
+) (*corev1.PodTemplateSpec, error) {
+	return spec.DeepCopy(), nil
+}
+
+func (sm *DefaultNoneServiceMesh) ServiceMeshPort(service *latticev1.Service, port int32) (int32, error) {
+	return sm.ServicePort(service, port)
+}
+
+func (sm *DefaultNoneServiceMesh) ServiceMeshPorts(service *latticev1.Service) (map[int32]int32, error) {
+	return sm.ServicePorts(service)
+}
+
+func (sm *DefaultNoneServiceMesh) ServicePort(service *latticev1.Service, port int32) (int32, error) {
+	if _, ok := service.Spec.Definition.ContainerPorts()[port]; !ok {
+		err := fmt.Errorf(
+			"service %v/%v does not have expected port %v",
+			service.Namespace,
+			service.Name,
+			port,
+		)
+		return 0, err
+	}
+
+	return port, nil
+}
+
+func (sm *DefaultNoneServiceMesh) ServicePorts(service *latticev1.Service) (map[int32]int32, error) {
+	// there is no proxy in front of the service, so the ports are the component ports
+	ports := make(map[int32]int32)
+	for port := range service.Spec.Definition.ContainerPorts() {
+		ports[port] = port
+	}
+
+	return ports, nil
+}
+
+func (sm *DefaultNoneServiceMesh) HasServiceIP(address *latticev1.Address) (string, error) {
+	return address.Annotations[annotationKeyIP], nil
+}
+
+func (sm *DefaultNoneServiceMesh) ServiceIP(
+	service *latticev1.Service,
+	address *latticev1.Address,
+) (string, map[string]string, error) {
+	kubeService, err := sm.ensureClusterIPKubeService(service)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ip := kubeService.Spec.ClusterIP
+	if ip == "" || ip == corev1.ClusterIPNone {
+		err := fmt.Errorf(
+			"kube service %v/%v for service %v does not have a cluster IP",
+			kubeService.Namespace,
+			kubeService.Name,
+			service.Name,
+		)
+		return "", nil, err
+	}
+
+	annotations, err := sm.ServiceAddressAnnotations(address)
+	if err != nil {
+		return "", nil, err
+	}
+	annotations[annotationKeyIP] = ip
+
+	return ip, annotations, nil
+}
+
+func (sm *DefaultNoneServiceMesh) ReleaseServiceIP(address *latticev1.Address) (map[string]string, error) {
+	// the cluster IP kube service is owned by the service, so it (and its cluster IP)
+	// will be garbage collected when the service is deleted
+	annotations, err := sm.ServiceAddressAnnotations(address)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations[annotationKeyIP] = ""
+	return annotations, nil
+}
+
+func (sm *DefaultNoneServiceMesh) IsDeploymentSpecUpdated(
+	service *latticev1.Service,
+	current, desired, untransformed *appsv1.DeploymentSpec,
+) (bool, string, *appsv1.DeploymentSpec) {
+	// TransformServicePodTemplateSpec is a no-op, so there's nothing to check or undo
+	return true, "", desired.DeepCopy()
+}
+
+func (sm *DefaultNoneServiceMesh) ensureClusterIPKubeService(service *latticev1.Service) (*corev1.Service, error) {
+	name := clusterIPKubeServiceName(service)
+	desiredSpec := clusterIPKubeServiceSpec(service)
+
+	kubeService, err := sm.kubeClient.CoreV1().Services(service.Namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+
+		kubeService = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(service, latticev1.ServiceKind)},
+			},
+			Spec: desiredSpec,
+		}
+		return sm.kubeClient.CoreV1().Services(service.Namespace).Create(kubeService)
+	}
+
+	if reflect.DeepEqual(kubeService.Spec.Ports, desiredSpec.Ports) {
+		return kubeService, nil
+	}
+
+	// the cluster IP is immutable, so only update the ports
+	kubeService = kubeService.DeepCopy()
+	kubeService.Spec.Ports = desiredSpec.Ports
+	return sm.kubeClient.CoreV1().Services(service.Namespace).Update(kubeService)
+}
+
+func clusterIPKubeServiceName(service *latticev1.Service) string {
+	return fmt.Sprintf("%v%v", kubeutil.GetKubeServiceNameForService(service.Name), clusterIPKubeServiceSuffix)
+}
+
+func clusterIPKubeServiceSpec(service *latticev1.Service) corev1.ServiceSpec {
+	var ports []corev1.ServicePort
+	for port := range service.Spec.Definition.ContainerPorts() {
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("%v", port),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       port,
+			TargetPort: intstr.FromInt(int(port)),
+		})
+	}
+
+	// sort the ports so the spec is deterministic
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Port < ports[j].Port
+	})
+
+	return corev1.ServiceSpec{
+		Selector: map[string]string{
+			latticev1.ServiceIDLabelKey: service.Name,
+		},
+		Type:  corev1.ServiceTypeClusterIP,
+		Ports: ports,
+	}
+}
@@ -0,0 +1,160 @@
+package none
+
+import (
+	"testing"
+
+	latticev1 "github.com/mlab-lattice/lattice/pkg/backend/kubernetes/customresource/apis/lattice/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testService() *latticev1.Service {
+	return &latticev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "service",
+			Namespace: "lattice-system-system",
+		},
+		Spec: latticev1.ServiceSpec{
+			Definition: definitionv1.Service{
+				Container: definitionv1.Container{
+					Ports: map[int32]definitionv1.ContainerPort{
+						8080: {Protocol: definitionv1.ContainerPortProtocolHTTP},
+					},
+				},
+				Sidecars: map[string]definitionv1.Container{
+					"metrics": {
+						Ports: map[int32]definitionv1.ContainerPort{
+							9090: {Protocol: definitionv1.ContainerPortProtocolHTTP},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testServiceMesh(t *testing.T, objects ...runtime.Object) *DefaultNoneServiceMesh {
+	serviceMesh, err := NewNoneServiceMesh(fake.NewSimpleClientset(objects...), &Options{})
+	require.NoError(t, err)
+	return serviceMesh
+}
+
+func TestServicePorts(t *testing.T) {
+	serviceMesh := testServiceMesh(t)
+	service := testService()
+
+	// there is no proxy, so services and the mesh are reached on the component ports
+	expected := map[int32]int32{8080: 8080, 9090: 9090}
+
+	ports, err := serviceMesh.ServicePorts(service)
+	require.NoError(t, err)
+	require.Equal(t, expected, ports)
+
+	ports, err = serviceMesh.ServiceMeshPorts(service)
+	require.NoError(t, err)
+	require.Equal(t, expected, ports)
+
+	port, err := serviceMesh.ServicePort(service, 9090)
+	require.NoError(t, err)
+	require.Equal(t, int32(9090), port)
+
+	port, err = serviceMesh.ServiceMeshPort(service, 8080)
+	require.NoError(t, err)
+	require.Equal(t, int32(8080), port)
+
+	_, err = serviceMesh.ServicePort(service, 80)
+	require.Error(t, err)
+}
+
+func TestServiceAnnotations(t *testing.T) {
+	serviceMesh := testServiceMesh(t)
+	service := testService()
+
+	annotations, err := serviceMesh.ServiceAnnotations(service)
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+
+	// pods are left as they are since there are no sidecars to add
+	spec := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "service"}},
+		},
+	}
+	transformed, err := serviceMesh.TransformServicePodTemplateSpec(service, spec)
+	require.NoError(t, err)
+	require.Equal(t, spec, transformed)
+}
+
+func TestServiceIP(t *testing.T) {
+	service := testService()
+	address := &latticev1.Address{}
+
+	// the service's cluster IP kube service is created with its ports
+	serviceMesh := testServiceMesh(t)
+	serviceMesh.kubeClient.(*fake.Clientset).PrependReactor(
+		"create",
+		"services",
+		func(action kubetesting.Action) (bool, runtime.Object, error) {
+			kubeService := action.(kubetesting.CreateAction).GetObject().(*corev1.Service)
+			kubeService.Spec.ClusterIP = "10.0.0.1"
+			return false, nil, nil
+		},
+	)
+
+	ip, annotations, err := serviceMesh.ServiceIP(service, address)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", ip)
+	require.Equal(t, map[string]string{annotationKeyIP: "10.0.0.1"}, annotations)
+
+	kubeService, err := serviceMesh.kubeClient.CoreV1().Services(service.Namespace).Get(
+		clusterIPKubeServiceName(service),
+		metav1.GetOptions{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, clusterIPKubeServiceSpec(service).Ports, kubeService.Spec.Ports)
+	require.Equal(t, service.Name, kubeService.Spec.Selector[latticev1.ServiceIDLabelKey])
+
+	address.Annotations = annotations
+	ip, err = serviceMesh.HasServiceIP(address)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", ip)
+
+	annotations, err = serviceMesh.ReleaseServiceIP(address)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{annotationKeyIP: ""}, annotations)
+}
+
+func TestServiceIPUpdatesPorts(t *testing.T) {
+	service := testService()
+
+	// the cluster IP of an existing kube service is kept when the service's
+	// ports change
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterIPKubeServiceName(service),
+			Namespace: service.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.2",
+			Ports:     []corev1.ServicePort{{Port: 80}},
+		},
+	}
+	serviceMesh := testServiceMesh(t, existing)
+
+	ip, _, err := serviceMesh.ServiceIP(service, &latticev1.Address{})
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.2", ip)
+
+	kubeService, err := serviceMesh.kubeClient.CoreV1().Services(service.Namespace).Get(existing.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.2", kubeService.Spec.ClusterIP)
+	require.Equal(t, clusterIPKubeServiceSpec(service).Ports, kubeService.Spec.Ports)
+}
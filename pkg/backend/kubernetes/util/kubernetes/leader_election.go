@@ -0,0 +1,115 @@
+package kubernetes
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/golang/glog"
+	"github.com/satori/go.uuid"
+)
+
+// Same defaults as the kubernetes control plane components.
+// https://github.com/kubernetes/kubernetes/blob/v1.10.8/pkg/client/leaderelectionconfig/config.go
+const (
+	leaderElectionLeaseDuration = 15 * time.Second
+	leaderElectionRenewDeadline = 10 * time.Second
+	leaderElectionRetryPeriod   = 2 * time.Second
+)
+
+// LeaderElectionPodNameEnvVar is the environment variable that processes run
+// with leader election read their pod's name from. It should be set through the
+// downward API.
+const LeaderElectionPodNameEnvVar = "POD_NAME"
+
+// RunWithLeaderElection blocks until the lease stored in the config map
+// namespace/name is acquired, then calls run. The lease is renewed for as
+// long as the process runs. If it is lost the process exits, since the
+// informers and controllers started by run cannot be safely restarted and
+// another replica may already be acting as the leader.
+func RunWithLeaderElection(
+	kubeClient kubeclientset.Interface,
+	namespace string,
+	name string,
+	run func(stop <-chan struct{}),
+) error {
+	identity, err := leaderElectionIdentity()
+	if err != nil {
+		return err
+	}
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events(namespace)})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: name})
+
+	lock, err := resourcelock.New(
+		resourcelock.ConfigMapsResourceLock,
+		namespace,
+		name,
+		kubeClient.CoreV1(),
+		resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: recorder,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error creating leader election lock: %v", err)
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaderElectionLeaseDuration,
+		RenewDeadline: leaderElectionRenewDeadline,
+		RetryPeriod:   leaderElectionRetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(stop <-chan struct{}) {
+				glog.Infof("%v acquired lease %v/%v", identity, namespace, name)
+				run(stop)
+			},
+			OnStoppedLeading: func() {
+				glog.Fatalf("%v lost lease %v/%v", identity, namespace, name)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					glog.Infof("%v is the leader for lease %v/%v", leader, namespace, name)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("%v waiting to acquire lease %v/%v", identity, namespace, name)
+	elector.Run()
+
+	// Run only returns once the lease has been lost, at which point
+	// OnStoppedLeading has already exited the process.
+	return fmt.Errorf("lost lease %v/%v", namespace, name)
+}
+
+// leaderElectionIdentity returns an identity that is unique among the processes
+// competing for a lease. Pods on the host network share their node's hostname,
+// so the pod's name is used if it is set. Otherwise the hostname is suffixed
+// with a random ID.
+func leaderElectionIdentity() (string, error) {
+	if podName := os.Getenv(LeaderElectionPodNameEnvVar); podName != "" {
+		return podName, nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%v_%v", hostname, uuid.NewV4().String()), nil
+}
@@ -0,0 +1,19 @@
+package latticeutil
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/util/sha1"
+)
+
+// WorkloadFilesSecretName returns the name of the kubernetes Secret holding the
+// literal and templated files of the workload with the given name.
+func WorkloadFilesSecretName(name string) string {
+	return fmt.Sprintf("lattice-files-%v", name)
+}
+
+// ContainerFileKey returns the key of the file at the path in the container
+// in the Secret named by WorkloadFilesSecretName.
+func ContainerFileKey(container, path string) (string, error) {
+	return sha1.EncodeToHexString([]byte(fmt.Sprintf("%v:%v", container, path)))
+}
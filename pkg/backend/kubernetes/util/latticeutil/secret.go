@@ -0,0 +1,57 @@
+package latticeutil
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+)
+
+// KubernetesSecretProvider is the name of the secret provider that stores
+// secrets as kubernetes Secrets.
+const KubernetesSecretProvider = "kubernetes"
+
+// SecretName returns the name of the kubernetes Secret holding the secrets at the path
+// from the provider. Secrets stored in kubernetes are named after the hashed path, secrets
+// from other providers are mirrored into Secrets suffixed with the provider's name.
+func SecretName(path tree.Path, provider string) (string, error) {
+	name, err := HashPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	if provider == "" || provider == KubernetesSecretProvider {
+		return name, nil
+	}
+
+	return fmt.Sprintf("%v-%v", name, provider), nil
+}
+
+// SecretVersionsName returns the name of the kubernetes Secret holding the versions
+// of the secrets at the path from the provider that are referenced by version.
+func SecretVersionsName(path tree.Path, provider string) (string, error) {
+	name, err := SecretName(path, provider)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%v-versions", name), nil
+}
+
+// SecretVersionKey returns the key of the version of the secret in the Secret
+// named by SecretVersionsName.
+func SecretVersionKey(subcomponent string, version int32) string {
+	return fmt.Sprintf("%v.%v", subcomponent, version)
+}
+
+// SecretNameAndKey returns the name of the kubernetes Secret and the key within it
+// that the referenced secret's value is stored in.
+func SecretNameAndKey(ref *definitionv1.SecretRef) (string, string, error) {
+	if ref.Version == 0 {
+		name, err := SecretName(ref.Value.Path(), ref.Provider)
+		return name, ref.Value.Subcomponent(), err
+	}
+
+	name, err := SecretVersionsName(ref.Value.Path(), ref.Provider)
+	return name, SecretVersionKey(ref.Value.Subcomponent(), ref.Version), err
+}
@@ -3,12 +3,20 @@ package backend
 import (
 	serverv1 "github.com/mlab-lattice/lattice/pkg/api/server/backend/v1"
 	backendv1 "github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/v1"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
 )
 
-func NewMockBackend(r resolver.Interface) *MockBackend {
+func NewMockBackend(
+	r resolver.Interface,
+	gitResolver *git.Resolver,
+	secretProviders *secretprovider.Providers,
+	costModel *cost.Model,
+) *MockBackend {
 	return &MockBackend{
-		v1: backendv1.NewBackend(r),
+		v1: backendv1.NewBackend(r, gitResolver, secretProviders, costModel),
 	}
 }
 
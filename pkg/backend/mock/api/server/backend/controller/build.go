@@ -3,6 +3,7 @@ package controller
 import (
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
@@ -12,6 +13,7 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 	"github.com/mlab-lattice/lattice/pkg/util/git"
+	imageregistry "github.com/mlab-lattice/lattice/pkg/util/registry"
 	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
 
 	"github.com/satori/go.uuid"
@@ -88,6 +90,9 @@ func (c *Controller) runBuild(build *v1.Build, record *registry.SystemRecord) {
 
 				StartTimestamp:      workload.Status.StartTimestamp,
 				CompletionTimestamp: now,
+
+				Artifacts:  mockContainerBuildArtifacts(workload.ID),
+				Provenance: mockContainerBuildProvenance(),
 			},
 		}
 
@@ -100,6 +105,9 @@ func (c *Controller) runBuild(build *v1.Build, record *registry.SystemRecord) {
 
 					StartTimestamp:      workload.Sidecars[sidecar].Status.StartTimestamp,
 					CompletionTimestamp: now,
+
+					Artifacts:  mockContainerBuildArtifacts(build.ID),
+					Provenance: mockContainerBuildProvenance(),
 				},
 			}
 		}
@@ -113,6 +121,19 @@ func (c *Controller) runBuild(build *v1.Build, record *registry.SystemRecord) {
 	log.Printf("build %v complete", build.ID)
 }
 
+func mockContainerBuildProvenance() *v1.ContainerBuildProvenance {
+	return &v1.ContainerBuildProvenance{
+		BuilderVersion: "mock",
+		ImageBuilder:   "mock",
+	}
+}
+
+func mockContainerBuildArtifacts(id v1.ContainerBuildID) *v1.ContainerBuildArtifacts {
+	return &v1.ContainerBuildArtifacts{
+		DockerImageFQN: fmt.Sprintf("mock/%v:latest", id),
+	}
+}
+
 func (c *Controller) resolveBuildComponent(build *v1.Build, record *registry.SystemRecord) bool {
 	var buildInfo *registry.BuildInfo
 	func() {
@@ -175,6 +196,28 @@ func (c *Controller) getBuildComponent(
 	c.registry.Lock()
 	defer c.registry.Unlock()
 
+	if build.Source != nil {
+		return c.getBuildSourceComponent(build, record)
+	}
+
+	if build.Commit != nil {
+		root := tree.RootPath()
+		version := v1.Version(*build.Commit)
+		build.Status.Path = &root
+		build.Status.Version = &version
+
+		ref := &definitionv1.Reference{
+			GitRepository: &definitionv1.GitRepositoryReference{
+				GitRepository: &definitionv1.GitRepository{
+					URL:    record.System.DefinitionURL,
+					Commit: build.Commit,
+				},
+			},
+		}
+
+		return tree.RootPath(), ref, nil, true
+	}
+
 	if build.Path == nil {
 		root := tree.RootPath()
 		build.Status.Path = &root
@@ -240,3 +283,53 @@ func (c *Controller) getBuildComponent(
 
 	return path, cmpnt, parentInfo.Commit, true
 }
+
+// getBuildSourceComponent imports the build's source into the git resolver and
+// returns a reference to the system definition in it.
+func (c *Controller) getBuildSourceComponent(
+	build *v1.Build,
+	record *registry.SystemRecord,
+) (
+	tree.Path,
+	definition.Component,
+	*git.CommitReference,
+	bool,
+) {
+	root := tree.RootPath()
+	version := v1.BuildSourceVersion
+	build.Status.Path = &root
+	build.Status.Version = &version
+
+	source, ok := record.BuildSources[build.Source.ID]
+	if !ok {
+		build.Status.State = v1.BuildStateFailed
+		build.Status.Message = fmt.Sprintf("build source %v does not exist", build.Source.ID)
+		return "", nil, nil, false
+	}
+
+	commit, err := c.gitResolver.ImportSnapshot(source.URL, func(directory string) error {
+		f, err := os.Open(source.Layer)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return imageregistry.ExtractLayer(f, directory)
+	})
+	if err != nil {
+		build.Status.State = v1.BuildStateFailed
+		build.Status.Message = fmt.Sprintf("error retrieving build source %v: %v", build.Source.ID, err)
+		return "", nil, nil, false
+	}
+
+	ref := &definitionv1.Reference{
+		GitRepository: &definitionv1.GitRepositoryReference{
+			GitRepository: &definitionv1.GitRepository{
+				URL:    source.URL,
+				Commit: &commit,
+			},
+		},
+	}
+
+	return root, ref, nil, true
+}
@@ -2,17 +2,26 @@ package controller
 
 import (
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
 	syncutil "github.com/mlab-lattice/lattice/pkg/util/sync"
 
 	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/registry"
 )
 
-func New(r *registry.Registry, cr resolver.Interface) *Controller {
+func New(
+	r *registry.Registry,
+	cr resolver.Interface,
+	gitResolver *git.Resolver,
+	costModel *cost.Model,
+) *Controller {
 	return &Controller{
 		registry:          r,
 		actions:           syncutil.NewLifecycleActionManager(),
 		componentResolver: cr,
+		gitResolver:       gitResolver,
+		costModel:         costModel,
 	}
 }
 
@@ -20,6 +29,13 @@ type Controller struct {
 	registry          *registry.Registry
 	actions           *syncutil.LifecycleActionManager
 	componentResolver resolver.Interface
+
+	// gitResolver is the git resolver used by the componentResolver,
+	// build sources are imported into it so they can be resolved
+	gitResolver *git.Resolver
+
+	// costModel projects the change in a system's cost from its deploys
+	costModel *cost.Model
 }
 
 func (c *Controller) CreateSystem(system *registry.SystemRecord) {
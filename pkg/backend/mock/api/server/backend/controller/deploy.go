@@ -9,9 +9,12 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/registry"
+	"github.com/mlab-lattice/lattice/pkg/containerresources"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/deploypolicy"
+	"github.com/mlab-lattice/lattice/pkg/quota"
 	syncutil "github.com/mlab-lattice/lattice/pkg/util/sync"
 	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
 )
@@ -22,6 +25,10 @@ func (c *Controller) runDeploy(deploy *v1.Deploy, record *registry.SystemRecord)
 
 	log.Printf("evaluating deploy %v", deploy.ID)
 
+	if !c.waitForDeployApproval(deploy, record) {
+		return
+	}
+
 	path, ok := c.getDeployPath(deploy, record)
 	if !ok {
 		return
@@ -53,11 +60,12 @@ func (c *Controller) runDeploy(deploy *v1.Deploy, record *registry.SystemRecord)
 		deploy.Status.StartTimestamp = timeutil.New(time.Now())
 	}()
 
-	if !c.waitForBuildTermination(deploy, record) {
+	definition, ok := c.waitForBuildTermination(deploy, record)
+	if !ok {
 		return
 	}
 
-	c.deployBuild(deploy, path, record)
+	c.deployBuild(deploy, path, definition, record)
 
 	c.registry.Lock()
 	defer c.registry.Unlock()
@@ -68,6 +76,52 @@ func (c *Controller) runDeploy(deploy *v1.Deploy, record *registry.SystemRecord)
 	log.Printf("deploy %v complete", deploy.ID)
 }
 
+// waitForDeployApproval waits until a deploy awaiting approval has been approved
+// by enough approvers, and fails it if it is rejected.
+func (c *Controller) waitForDeployApproval(deploy *v1.Deploy, record *registry.SystemRecord) bool {
+	for {
+		done, ok := func() (bool, bool) {
+			c.registry.Lock()
+			defer c.registry.Unlock()
+
+			if deploy.Status.State != v1.DeployStateAwaitingApproval {
+				return true, true
+			}
+
+			// the system's policy may have been removed since the deploy was created
+			policy := record.DeployPolicy
+			if policy == nil {
+				policy = &v1.DeployPolicy{}
+			}
+
+			approved, rejection := deploypolicy.Decide(policy, deploy.Approvals)
+			if rejection != nil {
+				log.Printf("deploy %v rejected by %v", deploy.ID, rejection.User)
+				deploy.Status.State = v1.DeployStateFailed
+				deploy.Status.Message = fmt.Sprintf("rejected by %v", rejection.User)
+				if rejection.Comment != "" {
+					deploy.Status.Message = fmt.Sprintf("%v: %v", deploy.Status.Message, rejection.Comment)
+				}
+				return true, false
+			}
+
+			if approved {
+				log.Printf("deploy %v approved", deploy.ID)
+				deploy.Status.State = v1.DeployStatePending
+				deploy.Status.Message = ""
+				return true, true
+			}
+
+			return false, true
+		}()
+		if done {
+			return ok
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
 func (c *Controller) getDeployPath(deploy *v1.Deploy, record *registry.SystemRecord) (tree.Path, bool) {
 	c.registry.Lock()
 	defer c.registry.Unlock()
@@ -103,26 +157,66 @@ func (c *Controller) getDeployPath(deploy *v1.Deploy, record *registry.SystemRec
 }
 
 func (c *Controller) lockDeploy(deploy *v1.Deploy, path tree.Path, record *registry.SystemRecord) bool {
+	for {
+		locked, queued := c.tryLockDeploy(deploy, path, record)
+		if !queued {
+			return locked
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+func (c *Controller) tryLockDeploy(deploy *v1.Deploy, path tree.Path, record *registry.SystemRecord) (bool, bool) {
 	c.registry.Lock()
 	defer c.registry.Unlock()
 
+	// deploys queued before this one to overlapping paths are run first
+	if record.DeploysQueuedAhead(deploy.ID, path) > 0 {
+		c.queueDeploy(deploy, path, record, "queued behind earlier deploys")
+		return false, true
+	}
+
 	// attempt to acquire the proper lifecycle lock for the deploy. if we fail due to a locking conflict,
-	// fail the deploy.
+	// queue the deploy until the lock is released.
 	err := c.actions.AcquireDeploy(record.System.ID, deploy.ID, path)
 	if err != nil {
-		deploy.Status.State = v1.DeployStateFailed
 		_, ok := err.(*syncutil.ConflictingLifecycleActionError)
 		if !ok {
+			c.dequeueDeploy(deploy, record)
+			deploy.Status.State = v1.DeployStateFailed
 			deploy.Status.Message = err.Error()
-			return false
+			return false, false
 		}
 
-		deploy.Status.Message = fmt.Sprintf("unable to acquire lifecycle lock: %v", err.Error())
-		return false
+		c.queueDeploy(deploy, path, record, fmt.Sprintf("waiting for lifecycle lock: %v", err.Error()))
+		return false, true
 	}
 
+	c.dequeueDeploy(deploy, record)
 	deploy.Status.State = v1.DeployStateAccepted
-	return true
+	deploy.Status.Message = ""
+	return true, false
+}
+
+func (c *Controller) queueDeploy(deploy *v1.Deploy, path tree.Path, record *registry.SystemRecord, message string) {
+	if deploy.Status.State != v1.DeployStateQueued {
+		log.Printf("queueing deploy %v", deploy.ID)
+		record.DeployQueue = append(record.DeployQueue, deploy.ID)
+	}
+
+	deploy.Status.State = v1.DeployStateQueued
+	deploy.Status.Path = &path
+	deploy.Status.Message = message
+}
+
+func (c *Controller) dequeueDeploy(deploy *v1.Deploy, record *registry.SystemRecord) {
+	for i, id := range record.DeployQueue {
+		if id == deploy.ID {
+			record.DeployQueue = append(record.DeployQueue[:i], record.DeployQueue[i+1:]...)
+			return
+		}
+	}
 }
 
 func (c *Controller) createDeployBuild(deploy *v1.Deploy, record *registry.SystemRecord) bool {
@@ -131,6 +225,14 @@ func (c *Controller) createDeployBuild(deploy *v1.Deploy, record *registry.Syste
 	c.registry.Lock()
 	defer c.registry.Unlock()
 
+	usage := v1.SystemUsage{Builds: record.RunningBuilds() + 1}
+	if err := quota.Check(record.System.Quota, usage); err != nil {
+		log.Printf("build for deploy %v %v, failing deploy", deploy.ID, err)
+		deploy.Status.State = v1.DeployStateFailed
+		deploy.Status.Message = fmt.Sprintf("build %v", err)
+		return false
+	}
+
 	build := c.registry.CreateBuild(deploy.Path, deploy.Version, record)
 	c.RunBuild(build, record)
 
@@ -139,8 +241,14 @@ func (c *Controller) createDeployBuild(deploy *v1.Deploy, record *registry.Syste
 	return true
 }
 
-func (c *Controller) waitForBuildTermination(deploy *v1.Deploy, record *registry.SystemRecord) bool {
+// waitForBuildTermination waits for the deploy's build to finish, and returns
+// the definition to deploy if it succeeded.
+func (c *Controller) waitForBuildTermination(
+	deploy *v1.Deploy,
+	record *registry.SystemRecord,
+) (*resolver.ResolutionTree, bool) {
 	log.Printf("waiting for build for deploy %v to terminate", deploy.ID)
+	var definition *resolver.ResolutionTree
 	for {
 		done, ok := func() (bool, bool) {
 			c.registry.Lock()
@@ -152,6 +260,40 @@ func (c *Controller) waitForBuildTermination(deploy *v1.Deploy, record *registry
 
 			switch build.Status.State {
 			case v1.BuildStateSucceeded:
+				// apply the system's default container resources to the build's
+				// definition and check its containers against the system's maximum
+				definition = record.Builds[*deploy.Status.Build].Definition.DeepCopy()
+				message := ""
+				if err := containerresources.Enforce(definition, record.System.ContainerResources); err != nil {
+					message = fmt.Sprintf("invalid container resources: %v", err)
+				}
+
+				if message == "" {
+					if err := definition.V1().ValidateNodePools(); err != nil {
+						message = fmt.Sprintf("invalid node pool: %v", err)
+					}
+				}
+
+				// the system's quota may have changed since the deploy was created,
+				// so check it against the definition the deploy results in
+				if message == "" {
+					message = verifyDeployQuota(record, *build.Status.Path, definition)
+				}
+
+				if message != "" {
+					log.Printf("deploy %v failed: %v", deploy.ID, message)
+					deploy.Status.State = v1.DeployStateFailed
+					deploy.Status.Message = message
+					return true, false
+				}
+
+				// record what the deploy will do to the system's cost before its
+				// definition replaces the system's
+				if len(c.costModel.InstanceTypePrices) != 0 {
+					delta := c.costModel.MonthlyCostDelta(record.Definition, *build.Status.Path, definition)
+					deploy.MonthlyCostDelta = &delta
+				}
+
 				log.Printf("build %v for deploy %v succeeded, moving deploy to in progress", deploy.Status.Build, deploy.ID)
 				deploy.Status.State = v1.DeployStateInProgress
 
@@ -170,23 +312,45 @@ func (c *Controller) waitForBuildTermination(deploy *v1.Deploy, record *registry
 			return false, true
 		}()
 		if done {
-			return ok
+			return definition, ok
 		}
 
 		time.Sleep(time.Second)
 	}
 }
 
-func (c *Controller) deployBuild(deploy *v1.Deploy, path tree.Path, record *registry.SystemRecord) {
-	var buildDefinition *resolver.ResolutionTree
+// verifyDeployQuota returns the reason a deploy of the build definition at path
+// has to fail because of the system's quota, if any.
+func verifyDeployQuota(record *registry.SystemRecord, path tree.Path, buildDefinition *resolver.ResolutionTree) string {
+	if record.System.Quota == nil {
+		return ""
+	}
 
-	func() {
-		c.registry.Lock()
-		defer c.registry.Unlock()
+	if err := quota.CheckDefinitionRequests(record.System.Quota, buildDefinition); err != nil {
+		return fmt.Sprintf("deployed definition %v", err)
+	}
 
-		buildDefinition = record.Builds[*deploy.Status.Build].Definition
-	}()
+	definition := record.Definition.DeepCopy()
+	definition.ReplacePrefix(path, buildDefinition)
 
+	usage, err := quota.DefinitionUsage(definition)
+	if err != nil {
+		return fmt.Sprintf("could not determine the resources of the deployed definition: %v", err)
+	}
+
+	if err := quota.Check(record.System.Quota, usage); err != nil {
+		return fmt.Sprintf("deployed definition %v", err)
+	}
+
+	return ""
+}
+
+func (c *Controller) deployBuild(
+	deploy *v1.Deploy,
+	path tree.Path,
+	buildDefinition *resolver.ResolutionTree,
+	record *registry.SystemRecord,
+) {
 	var wg sync.WaitGroup
 
 	func() {
@@ -195,25 +359,30 @@ func (c *Controller) deployBuild(deploy *v1.Deploy, path tree.Path, record *regi
 
 		log.Printf("updating existing services")
 
-		// act on existing services
-		record.Definition.V1().Services(func(path tree.Path, service *definitionv1.Service, info *resolver.ResolutionInfo) tree.WalkContinuation {
+		// act on existing services, leaving the services outside of the deploy's
+		// path to the deploys of the rest of the system
+		record.Definition.V1().Services(func(servicePath tree.Path, service *definitionv1.Service, info *resolver.ResolutionInfo) tree.WalkContinuation {
+			if !servicePath.HasPrefix(path) {
+				return tree.ContinueWalk
+			}
+
 			wg.Add(1)
 
 			// if the path is no longer in the tree, terminate the service
-			other, ok := buildDefinition.Get(path)
+			other, ok := buildDefinition.Get(servicePath)
 			if !ok {
-				go c.terminateService(path, record, &wg)
+				go c.terminateService(servicePath, record, &wg)
 				return tree.ContinueWalk
 			}
 
 			// if it's being replaced by another service, roll the service over
 			if otherService, ok := other.Component.(*definitionv1.Service); ok {
-				go c.rollService(path, otherService, record, &wg)
+				go c.rollService(servicePath, otherService, record, &wg)
 				return tree.ContinueWalk
 			}
 
 			// otherwise this path is no longer a service, so terminate it
-			go c.terminateService(path, record, &wg)
+			go c.terminateService(servicePath, record, &wg)
 			return tree.ContinueWalk
 		})
 
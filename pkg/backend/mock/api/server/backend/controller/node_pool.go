@@ -3,6 +3,7 @@ package controller
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/registry"
@@ -23,17 +24,8 @@ func (c *Controller) addNodePool(
 	c.registry.Lock()
 	defer c.registry.Unlock()
 
-	record.NodePools[subcomponent] = &v1.NodePool{
-		InstanceType: definition.InstanceType,
-		NumInstances: definition.NumInstances,
-
-		Status: v1.NodePoolStatus{
-			State: v1.NodePoolStateStable,
-
-			InstanceType: definition.InstanceType,
-			NumInstances: definition.NumInstances,
-		},
-	}
+	record.NodePools[subcomponent] = newNodePool(definition)
+	recordInstanceHours(record)
 
 	// TODO: add node pool scaling
 }
@@ -51,17 +43,9 @@ func (c *Controller) rollNodePool(
 	c.registry.Lock()
 	defer c.registry.Unlock()
 
-	record.NodePools[subcomponent] = &v1.NodePool{
-		InstanceType: definition.InstanceType,
-		NumInstances: definition.NumInstances,
-
-		Status: v1.NodePoolStatus{
-			State: v1.NodePoolStateStable,
-
-			InstanceType: definition.InstanceType,
-			NumInstances: definition.NumInstances,
-		},
-	}
+	// rolling the node pool replaces any override it had with the definition
+	record.NodePools[subcomponent] = newNodePool(definition)
+	recordInstanceHours(record)
 
 	// TODO: add node pool scaling
 }
@@ -79,6 +63,52 @@ func (c *Controller) terminateNodePool(
 	defer c.registry.Unlock()
 
 	delete(record.NodePools, subcomponent)
+	recordInstanceHours(record)
 
 	// TODO: add node pool scaling
 }
+
+func newNodePool(definition *definitionv1.NodePool) *v1.NodePool {
+	nodePool := &v1.NodePool{
+		InstanceType: definition.InstanceType,
+		NumInstances: definition.NumInstances,
+
+		InstanceTypes: definition.AllInstanceTypes(),
+
+		Status: v1.NodePoolStatus{
+			State: v1.NodePoolStateStable,
+
+			InstanceType: definition.InstanceType,
+			NumInstances: definition.NumInstances,
+		},
+	}
+
+	if definition.Spot != nil {
+		nodePool.Spot = &v1.NodePoolSpot{
+			OnDemandBaseInstances: definition.Spot.OnDemandBaseInstances,
+			OnDemandPercentage:    definition.Spot.OnDemandPercentage,
+			Fallback:              string(definition.Spot.Fallback),
+		}
+
+		if nodePool.Spot.Fallback == "" {
+			nodePool.Spot.Fallback = string(definitionv1.NodePoolSpotFallbackOnDemand)
+		}
+	}
+
+	if definition.Autoscaled() {
+		nodePool.Autoscaling = &v1.NodePoolAutoscaling{
+			MinInstances: definition.MinInstances,
+			MaxInstances: definition.MaxInstances,
+		}
+	}
+
+	return nodePool
+}
+
+// recordInstanceHours records the instance-hours of the system's node pools and
+// services after their instances changed. The registry must be locked.
+func recordInstanceHours(record *registry.SystemRecord) {
+	if err := record.RecordInstanceHours(time.Now()); err != nil {
+		log.Printf("error recording instance-hours for system %v: %v", record.System.ID, err)
+	}
+}
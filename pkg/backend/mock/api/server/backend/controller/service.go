@@ -60,6 +60,7 @@ func (c *Controller) addService(
 		}
 
 		record.ServicePaths[path] = service.ID
+		recordInstanceHours(record)
 	}()
 
 	for {
@@ -221,6 +222,7 @@ func (c *Controller) terminateService(path tree.Path, record *registry.SystemRec
 
 			delete(record.Services, service.ID)
 			delete(record.ServicePaths, path)
+			recordInstanceHours(record)
 
 			return
 		}
@@ -2,11 +2,14 @@ package registry
 
 import (
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/quota"
 	"github.com/satori/go.uuid"
 	"sync"
+	"time"
 )
 
 func New() *Registry {
@@ -23,20 +26,30 @@ type SystemRecord struct {
 	System     *v1.System
 	Definition *resolver.ResolutionTree
 
-	Builds map[v1.BuildID]*BuildInfo
+	Builds       map[v1.BuildID]*BuildInfo
+	BuildSources map[v1.BuildSourceID]*BuildSourceInfo
 
 	Deploys map[v1.DeployID]*v1.Deploy
+	// DeployQueue holds the queued deploys in the order they were queued.
+	DeployQueue  []v1.DeployID
+	DeployPolicy *v1.DeployPolicy
 
 	Jobs map[v1.JobID]*v1.Job
 
 	NodePools map[tree.PathSubcomponent]*v1.NodePool
-
-	Secrets map[tree.PathSubcomponent]*v1.Secret
+	// NodePoolInstanceHours and ServiceInstanceHours are the instance-hours
+	// recorded for the system's node pools and services.
+	NodePoolInstanceHours map[tree.PathSubcomponent]*cost.InstanceHours
+	ServiceInstanceHours  map[tree.Path]*cost.InstanceHours
 
 	Services     map[v1.ServiceID]*ServiceInfo
 	ServicePaths map[tree.Path]v1.ServiceID
 
 	Teardowns map[v1.TeardownID]*v1.Teardown
+
+	WebhookConfig *v1.WebhookConfig
+	// WebhookEvents are ordered newest first.
+	WebhookEvents []v1.WebhookEvent
 }
 
 type BuildInfo struct {
@@ -44,6 +57,15 @@ type BuildInfo struct {
 	Definition *resolver.ResolutionTree
 }
 
+type BuildSourceInfo struct {
+	Source *v1.BuildSource
+
+	// URL is the repository URL the build source is resolved from,
+	// and Layer the path to the layer containing its files.
+	URL   string
+	Layer string
+}
+
 type ServiceInfo struct {
 	Service    *v1.Service
 	Definition *definitionv1.Service
@@ -68,3 +90,204 @@ func (r *Registry) CreateBuild(p *tree.Path, v *v1.Version, record *SystemRecord
 
 	return build
 }
+
+// DeploysQueuedAhead returns the number of deploys queued before the deploy
+// whose paths overlap with path, which have to run before the deploy can.
+func (r *SystemRecord) DeploysQueuedAhead(id v1.DeployID, path tree.Path) int {
+	ahead := 0
+	for _, queued := range r.DeployQueue {
+		if queued == id {
+			break
+		}
+
+		deploy := r.Deploys[queued]
+		if deploy.Status.Path == nil {
+			continue
+		}
+
+		if deploy.Status.Path.HasPrefix(path) || path.HasPrefix(*deploy.Status.Path) {
+			ahead++
+		}
+	}
+
+	return ahead
+}
+
+// Usage returns the resources used by the system's definition and by its job
+// runs and builds that have not finished.
+func (r *SystemRecord) Usage() (v1.SystemUsage, error) {
+	usage, err := quota.DefinitionUsage(r.Definition)
+	if err != nil {
+		return v1.SystemUsage{}, err
+	}
+
+	jobRuns, err := r.JobRunsUsage()
+	if err != nil {
+		return v1.SystemUsage{}, err
+	}
+
+	usage = quota.Add(usage, jobRuns)
+	usage.Builds = r.RunningBuilds()
+	return usage, nil
+}
+
+// JobRunsUsage returns the CPU and memory requested by the system's job runs that
+// have not finished, and the number of them.
+func (r *SystemRecord) JobRunsUsage() (v1.SystemUsage, error) {
+	var usage v1.SystemUsage
+	for _, job := range r.Jobs {
+		switch job.Status.State {
+		case v1.JobStatePending, v1.JobStateQueued, v1.JobStateRunning:
+		default:
+			continue
+		}
+
+		usage.JobRuns++
+
+		// the job may have been removed from the definition since it was run
+		i, ok := r.Definition.Get(job.Path)
+		if !ok {
+			continue
+		}
+
+		definition, ok := i.Component.(*definitionv1.Job)
+		if !ok {
+			continue
+		}
+
+		jobUsage, err := quota.WorkloadUsage(definition)
+		if err != nil {
+			return v1.SystemUsage{}, err
+		}
+
+		usage.MilliCPU += jobUsage.MilliCPU
+		usage.MemoryMiB += jobUsage.MemoryMiB
+	}
+
+	return usage, nil
+}
+
+// RunningBuilds returns the number of the system's builds that have not finished.
+func (r *SystemRecord) RunningBuilds() int32 {
+	var running int32
+	for _, info := range r.Builds {
+		switch info.Build.Status.State {
+		case v1.BuildStatePending, v1.BuildStateAccepted, v1.BuildStateRunning:
+			running++
+		}
+	}
+
+	return running
+}
+
+// Cost returns the hourly cost of the system's node pools allocated to its
+// services by the model, and their cost between since and now from the
+// instance-hours recorded for them.
+func (r *SystemRecord) Cost(model *cost.Model, since, now time.Time) (*cost.Estimate, error) {
+	nodePools, services, err := r.costWorkloads()
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordInstanceHours(nodePools, services, now)
+
+	for i := range nodePools {
+		nodePools[i].InstanceHours = r.NodePoolInstanceHours[nodePools[i].Path].Since(since, now)
+	}
+
+	for i := range services {
+		services[i].InstanceHours = r.ServiceInstanceHours[services[i].Path].Since(since, now)
+	}
+
+	return model.Estimate(nodePools, services), nil
+}
+
+// RecordInstanceHours records the instance-hours of the system's node pools and
+// services up to now, counting their current instances from now on. It should be
+// called whenever their instances change.
+func (r *SystemRecord) RecordInstanceHours(now time.Time) error {
+	nodePools, services, err := r.costWorkloads()
+	if err != nil {
+		return err
+	}
+
+	r.recordInstanceHours(nodePools, services, now)
+	return nil
+}
+
+func (r *SystemRecord) recordInstanceHours(nodePools []cost.NodePool, services []cost.Service, now time.Time) {
+	// like the kubernetes backend, which records instance-hours in the status of
+	// node pools and services, the instance-hours of removed node pools and
+	// services are removed with them
+	nodePoolInstanceHours := make(map[tree.PathSubcomponent]*cost.InstanceHours)
+	for _, nodePool := range nodePools {
+		nodePoolInstanceHours[nodePool.Path] = r.NodePoolInstanceHours[nodePool.Path].Record(nodePool.Instances, now)
+	}
+	r.NodePoolInstanceHours = nodePoolInstanceHours
+
+	serviceInstanceHours := make(map[tree.Path]*cost.InstanceHours)
+	for _, service := range services {
+		instances := make(map[string]int32)
+		for _, nodePool := range service.NodePools {
+			instances[nodePool] = service.Instances
+		}
+		serviceInstanceHours[service.Path] = r.ServiceInstanceHours[service.Path].Record(instances, now)
+	}
+	r.ServiceInstanceHours = serviceInstanceHours
+}
+
+// costWorkloads returns the system's node pools and services as they are
+// currently running.
+func (r *SystemRecord) costWorkloads() ([]cost.NodePool, []cost.Service, error) {
+	var nodePools []cost.NodePool
+	for path, nodePool := range r.NodePools {
+		nodePools = append(nodePools, cost.NodePool{
+			ID:   path.String(),
+			Path: path,
+			Instances: map[string]int32{
+				nodePool.Status.InstanceType: nodePool.Status.NumInstances,
+			},
+		})
+	}
+
+	var services []cost.Service
+	for _, info := range r.Services {
+		path := info.Service.Path
+
+		var serviceNodePools []string
+		if info.Definition.NodePool != nil {
+			switch {
+			case info.Definition.NodePool.NodePoolPath != nil:
+				serviceNodePools = append(serviceNodePools, info.Definition.NodePool.NodePoolPath.String())
+
+			// the mock does not track services' dedicated node pools, so they
+			// run at the size of their definition
+			case info.Definition.NodePool.NodePool != nil:
+				dedicated, err := tree.NewPathSubcomponentFromParts(path, "node_pool")
+				if err != nil {
+					return nil, nil, err
+				}
+
+				definition := info.Definition.NodePool.NodePool
+				nodePools = append(nodePools, cost.NodePool{
+					ID:   dedicated.String(),
+					Path: dedicated,
+					Instances: map[string]int32{
+						definition.InstanceType: definition.NumInstances,
+					},
+				})
+				serviceNodePools = append(serviceNodePools, dedicated.String())
+			}
+		}
+
+		instances := info.Service.Status.UpdatedInstances + info.Service.Status.StaleInstances
+		service, err := cost.NewService(path, info.Definition, instances, serviceNodePools, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		services = append(services, service)
+	}
+
+	return nodePools, services, nil
+}
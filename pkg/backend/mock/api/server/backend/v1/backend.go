@@ -3,15 +3,23 @@ package v1
 import (
 	"github.com/mlab-lattice/lattice/pkg/api/server/backend/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/v1/system"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
 )
 
 type Backend struct {
 	systems *system.Backend
 }
 
-func NewBackend(componentResolver resolver.Interface) *Backend {
-	return &Backend{system.NewBackend(componentResolver)}
+func NewBackend(
+	componentResolver resolver.Interface,
+	gitResolver *git.Resolver,
+	secretProviders *secretprovider.Providers,
+	costModel *cost.Model,
+) *Backend {
+	return &Backend{system.NewBackend(componentResolver, gitResolver, secretProviders, costModel)}
 }
 
 func (b *Backend) Systems() v1.SystemBackend {
@@ -8,22 +8,39 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/controller"
 	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/registry"
+	"github.com/mlab-lattice/lattice/pkg/cost"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/imagepolicy"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
+	"github.com/mlab-lattice/lattice/pkg/util/git"
 	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
 )
 
 type Backend struct {
-	registry   *registry.Registry
-	controller *controller.Controller
+	registry        *registry.Registry
+	controller      *controller.Controller
+	secretProviders *secretprovider.Providers
+	costModel       *cost.Model
 }
 
-func NewBackend(componentResolver resolver.Interface) *Backend {
+func NewBackend(
+	componentResolver resolver.Interface,
+	gitResolver *git.Resolver,
+	secretProviders *secretprovider.Providers,
+	costModel *cost.Model,
+) *Backend {
+	if costModel == nil {
+		costModel = &cost.Model{}
+	}
+
 	r := registry.New()
-	c := controller.New(r, componentResolver)
+	c := controller.New(r, componentResolver, gitResolver, costModel)
 	return &Backend{
-		registry:   r,
-		controller: c,
+		registry:        r,
+		controller:      c,
+		secretProviders: secretProviders,
+		costModel:       costModel,
 	}
 }
 
@@ -48,14 +65,13 @@ func (b *Backend) Create(systemID v1.SystemID, definitionURL string) (*v1.System
 		},
 		Definition: resolver.NewResolutionTree(),
 
-		Builds: make(map[v1.BuildID]*registry.BuildInfo),
+		Builds:       make(map[v1.BuildID]*registry.BuildInfo),
+		BuildSources: make(map[v1.BuildSourceID]*registry.BuildSourceInfo),
 
 		Deploys: make(map[v1.DeployID]*v1.Deploy),
 
 		Jobs: make(map[v1.JobID]*v1.Job),
 
-		Secrets: make(map[tree.PathSubcomponent]*v1.Secret),
-
 		Services:     make(map[v1.ServiceID]*registry.ServiceInfo),
 		ServicePaths: make(map[tree.Path]v1.ServiceID),
 
@@ -91,7 +107,14 @@ func (b *Backend) Get(systemID v1.SystemID) (*v1.System, error) {
 		return nil, err
 	}
 
-	return record.System.DeepCopy(), nil
+	usage, err := record.Usage()
+	if err != nil {
+		return nil, err
+	}
+
+	system := record.System.DeepCopy()
+	system.Status.Usage = &usage
+	return system, nil
 }
 
 func (b *Backend) Delete(systemID v1.SystemID) error {
@@ -107,6 +130,137 @@ func (b *Backend) Delete(systemID v1.SystemID) error {
 	return nil
 }
 
+func (b *Backend) SetQuota(systemID v1.SystemID, quota *v1.SystemQuota) (*v1.System, error) {
+	b.registry.Lock()
+	defer b.registry.Unlock()
+
+	record, err := b.systemRecord(systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.System.Status.State == v1.SystemStateDeleting {
+		return nil, v1.NewSystemDeletingError()
+	}
+
+	record.System.Quota = quota.DeepCopy()
+
+	usage, err := record.Usage()
+	if err != nil {
+		return nil, err
+	}
+
+	system := record.System.DeepCopy()
+	system.Status.Usage = &usage
+	return system, nil
+}
+
+func (b *Backend) SetContainerResources(
+	systemID v1.SystemID,
+	resources *v1.SystemContainerResources,
+) (*v1.System, error) {
+	b.registry.Lock()
+	defer b.registry.Unlock()
+
+	record, err := b.systemRecord(systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.System.Status.State == v1.SystemStateDeleting {
+		return nil, v1.NewSystemDeletingError()
+	}
+
+	record.System.ContainerResources = resources.DeepCopy()
+
+	usage, err := record.Usage()
+	if err != nil {
+		return nil, err
+	}
+
+	system := record.System.DeepCopy()
+	system.Status.Usage = &usage
+	return system, nil
+}
+
+func (b *Backend) Cost(systemID v1.SystemID, path tree.Path, since time.Time) (*v1.CostEstimate, error) {
+	b.registry.Lock()
+	defer b.registry.Unlock()
+
+	record, err := b.systemRecordInitialized(systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the system didn't cost anything before it was created
+	if since.Before(record.System.Status.CreationTimestamp.Time) {
+		since = record.System.Status.CreationTimestamp.Time
+	}
+
+	estimate, err := record.Cost(b.costModel, since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return estimate.CostEstimate(path, since), nil
+}
+
+func (b *Backend) SetTracing(systemID v1.SystemID, tracing *v1.SystemTracing) (*v1.System, error) {
+	b.registry.Lock()
+	defer b.registry.Unlock()
+
+	record, err := b.systemRecord(systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.System.Status.State == v1.SystemStateDeleting {
+		return nil, v1.NewSystemDeletingError()
+	}
+
+	record.System.Tracing = nil
+	if tracing.SamplingRate != nil {
+		record.System.Tracing = tracing.DeepCopy()
+	}
+
+	usage, err := record.Usage()
+	if err != nil {
+		return nil, err
+	}
+
+	system := record.System.DeepCopy()
+	system.Status.Usage = &usage
+	return system, nil
+}
+
+func (b *Backend) SetImagePolicy(systemID v1.SystemID, policy *v1.ImagePolicy) (*v1.System, error) {
+	b.registry.Lock()
+	defer b.registry.Unlock()
+
+	record, err := b.systemRecord(systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.System.Status.State == v1.SystemStateDeleting {
+		return nil, v1.NewSystemDeletingError()
+	}
+
+	record.System.ImagePolicy = nil
+	if !imagepolicy.IsEmpty(policy) {
+		record.System.ImagePolicy = policy.DeepCopy()
+	}
+
+	usage, err := record.Usage()
+	if err != nil {
+		return nil, err
+	}
+
+	system := record.System.DeepCopy()
+	system.Status.Usage = &usage
+	return system, nil
+}
+
 func (b *Backend) Builds(id v1.SystemID) backendv1.SystemBuildBackend {
 	return &BuildBackend{
 		backend:  b,
@@ -156,6 +310,13 @@ func (b *Backend) Teardowns(id v1.SystemID) backendv1.SystemTeardownBackend {
 	}
 }
 
+func (b *Backend) Webhooks(id v1.SystemID) backendv1.SystemWebhookBackend {
+	return &WebhookBackend{
+		backend:  b,
+		systemID: id,
+	}
+}
+
 // helpers
 func (b *Backend) systemRecordInitialized(id v1.SystemID) (*registry.SystemRecord, error) {
 	record, err := b.systemRecord(id)
@@ -3,10 +3,22 @@ package system
 import (
 	"io"
 	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/registry"
+	"github.com/mlab-lattice/lattice/pkg/buildsource"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// mockBuildSourceRegistry is the registry given to the URLs of build sources,
+	// which are only stored in the mock backend
+	mockBuildSourceRegistry = "mock"
 )
 
 type BuildBackend struct {
@@ -22,6 +34,96 @@ func (b *BuildBackend) CreateFromVersion(v v1.Version) (*v1.Build, error) {
 	return b.create(nil, &v)
 }
 
+func (b *BuildBackend) CreateFromCommit(commit string) (*v1.Build, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBuildQuota(record); err != nil {
+		return nil, err
+	}
+
+	build := b.backend.registry.CreateBuild(nil, nil, record)
+	build.Commit = &commit
+
+	// run the build
+	b.backend.controller.RunBuild(build, record)
+
+	// copy the build so we don't return a pointer into the backend
+	// so we can release the lock
+	return build.DeepCopy(), nil
+}
+
+func (b *BuildBackend) CreateFromSource(id v1.BuildSourceID) (*v1.Build, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	source, ok := record.BuildSources[id]
+	if !ok {
+		return nil, v1.NewInvalidBuildSourceIDError()
+	}
+
+	if err := checkBuildQuota(record); err != nil {
+		return nil, err
+	}
+
+	build := b.backend.registry.CreateBuild(nil, nil, record)
+	build.Source = source.Source.DeepCopy()
+
+	// run the build
+	b.backend.controller.RunBuild(build, record)
+
+	// copy the build so we don't return a pointer into the backend
+	// so we can release the lock
+	return build.DeepCopy(), nil
+}
+
+// UploadSource stores the archive's files in a layer in a temporary file, since
+// the mock has no registry to push them to.
+func (b *BuildBackend) UploadSource(archive io.Reader) (*v1.BuildSource, error) {
+	file, err := ioutil.TempFile("", "mock-build-source")
+	if err != nil {
+		return nil, err
+	}
+	file.Close()
+
+	layer, err := buildsource.NewLayer(file.Name(), archive)
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	source := &v1.BuildSource{
+		ID:     v1.BuildSourceID(uuid.NewV4().String()),
+		Digest: layer.Digest,
+	}
+	record.BuildSources[source.ID] = &registry.BuildSourceInfo{
+		Source: source,
+		URL:    buildsource.URL(mockBuildSourceRegistry, string(b.systemID), layer.Digest),
+		Layer:  layer.Path,
+	}
+
+	return source.DeepCopy(), nil
+}
+
 func (b *BuildBackend) create(p *tree.Path, v *v1.Version) (*v1.Build, error) {
 	b.backend.registry.Lock()
 	defer b.backend.registry.Unlock()
@@ -31,6 +133,10 @@ func (b *BuildBackend) create(p *tree.Path, v *v1.Version) (*v1.Build, error) {
 		return nil, err
 	}
 
+	if err := checkBuildQuota(record); err != nil {
+		return nil, err
+	}
+
 	build := b.backend.registry.CreateBuild(p, v, record)
 
 	// run the build
@@ -41,6 +147,21 @@ func (b *BuildBackend) create(p *tree.Path, v *v1.Version) (*v1.Build, error) {
 	return build.DeepCopy(), nil
 }
 
+// checkBuildQuota returns a quota exceeded error if creating another build would
+// exceed the builds limit of the system's quota.
+func checkBuildQuota(record *registry.SystemRecord) error {
+	usage := v1.SystemUsage{Builds: record.RunningBuilds() + 1}
+	if err := quota.Check(record.System.Quota, usage); err != nil {
+		if _, ok := err.(*quota.ExceededError); ok {
+			return v1.NewSystemQuotaExceededError()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 func (b *BuildBackend) List() ([]v1.Build, error) {
 	b.backend.registry.Lock()
 	defer b.backend.registry.Unlock()
@@ -77,6 +198,49 @@ func (b *BuildBackend) Get(id v1.BuildID) (*v1.Build, error) {
 	return build.Build.DeepCopy(), nil
 }
 
+func (b *BuildBackend) Provenance(id v1.BuildID) (*v1.BuildProvenance, error) {
+	build, err := b.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	provenance := &v1.BuildProvenance{
+		ID:        id,
+		Path:      build.Path,
+		Version:   build.Version,
+		Workloads: make(map[tree.Path]v1.WorkloadBuildProvenance),
+	}
+
+	for path, workload := range build.Status.Workloads {
+		if workload.Status.State != v1.ContainerBuildStateSucceeded {
+			continue
+		}
+
+		workloadProvenance := v1.WorkloadBuildProvenance{
+			ContainerBuildProvenanceDocument: containerBuildProvenance(&workload.ContainerBuild),
+			Sidecars:                         make(map[string]v1.ContainerBuildProvenanceDocument),
+		}
+		for sidecar, containerBuild := range workload.Sidecars {
+			workloadProvenance.Sidecars[sidecar] = containerBuildProvenance(&containerBuild)
+		}
+
+		provenance.Workloads[path] = workloadProvenance
+	}
+
+	return provenance, nil
+}
+
+func containerBuildProvenance(containerBuild *v1.ContainerBuild) v1.ContainerBuildProvenanceDocument {
+	document := v1.ContainerBuildProvenanceDocument{ID: containerBuild.ID}
+	if containerBuild.Status.Artifacts != nil {
+		document.DockerImageFQN = containerBuild.Status.Artifacts.DockerImageFQN
+	}
+	if containerBuild.Status.Provenance != nil {
+		document.ContainerBuildProvenance = *containerBuild.Status.Provenance
+	}
+	return document
+}
+
 func (b *BuildBackend) Logs(
 	id v1.BuildID,
 	path tree.Path,
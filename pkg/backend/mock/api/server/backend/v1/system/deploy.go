@@ -1,8 +1,15 @@
 package system
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/registry"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/deploypolicy"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+
 	"github.com/satori/go.uuid"
 )
 
@@ -11,19 +18,19 @@ type DeployBackend struct {
 	backend  *Backend
 }
 
-func (b *DeployBackend) CreateFromBuild(id v1.BuildID) (*v1.Deploy, error) {
-	return b.create(&id, nil, nil)
+func (b *DeployBackend) CreateFromBuild(id v1.BuildID, creator string) (*v1.Deploy, error) {
+	return b.create(&id, nil, nil, creator)
 }
 
-func (b *DeployBackend) CreateFromPath(p tree.Path) (*v1.Deploy, error) {
-	return b.create(nil, &p, nil)
+func (b *DeployBackend) CreateFromPath(p tree.Path, creator string) (*v1.Deploy, error) {
+	return b.create(nil, &p, nil, creator)
 }
 
-func (b *DeployBackend) CreateFromVersion(v v1.Version) (*v1.Deploy, error) {
-	return b.create(nil, nil, &v)
+func (b *DeployBackend) CreateFromVersion(v v1.Version, creator string) (*v1.Deploy, error) {
+	return b.create(nil, nil, &v, creator)
 }
 
-func (b *DeployBackend) create(id *v1.BuildID, p *tree.Path, v *v1.Version) (*v1.Deploy, error) {
+func (b *DeployBackend) create(id *v1.BuildID, p *tree.Path, v *v1.Version, creator string) (*v1.Deploy, error) {
 	b.backend.registry.Lock()
 	defer b.backend.registry.Unlock()
 
@@ -33,15 +40,36 @@ func (b *DeployBackend) create(id *v1.BuildID, p *tree.Path, v *v1.Version) (*v1
 	}
 
 	deploy := &v1.Deploy{
-		ID:      v1.DeployID(uuid.NewV4().String()),
-		Build:   id,
-		Path:    p,
-		Version: v,
+		ID:        v1.DeployID(uuid.NewV4().String()),
+		Build:     id,
+		Path:      p,
+		Version:   v,
+		CreatedBy: creator,
 		Status: v1.DeployStatus{
 			State: v1.DeployStatePending,
 		},
 	}
 
+	// deploys created outside of the system's deploy policy wait for approval
+	if record.DeployPolicy != nil {
+		required, reason, err := deploypolicy.RequiresApproval(record.DeployPolicy, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		if required {
+			deploy.Status.State = v1.DeployStateAwaitingApproval
+			deploy.Status.Message = fmt.Sprintf("awaiting approval: %v", reason)
+		}
+	}
+
+	// deploys of builds of build sources can't be reproduced either
+	if id != nil {
+		if build, ok := record.Builds[*id]; ok && build.Build.Source != nil {
+			deploy.Source = build.Build.Source.DeepCopy()
+		}
+	}
+
 	record.Deploys[deploy.ID] = deploy
 
 	b.backend.controller.RunDeploy(deploy, record)
@@ -62,7 +90,7 @@ func (b *DeployBackend) List() ([]v1.Deploy, error) {
 
 	var deploys []v1.Deploy
 	for _, deploy := range record.Deploys {
-		deploys = append(deploys, *deploy.DeepCopy())
+		deploys = append(deploys, *externalDeploy(deploy, record))
 	}
 
 	return deploys, nil
@@ -82,7 +110,97 @@ func (b *DeployBackend) Get(id v1.DeployID) (*v1.Deploy, error) {
 		return nil, v1.NewInvalidDeployIDError()
 	}
 
-	// copy the deploy so we don't return a pointer into the backend
-	// so we can release the lock
-	return deploy.DeepCopy(), nil
+	return externalDeploy(deploy, record), nil
+}
+
+func (b *DeployBackend) Approve(id v1.DeployID, approver string, roles []string, comment string) (*v1.Deploy, error) {
+	return b.decide(id, v1.DeployApprovalDecisionApproved, approver, roles, comment)
+}
+
+func (b *DeployBackend) Reject(id v1.DeployID, approver string, roles []string, comment string) (*v1.Deploy, error) {
+	return b.decide(id, v1.DeployApprovalDecisionRejected, approver, roles, comment)
+}
+
+// decide records the approver's decision on the deploy. The controller
+// decides whether the deploy has been approved or rejected.
+func (b *DeployBackend) decide(
+	id v1.DeployID,
+	decision v1.DeployApprovalDecision,
+	approver string,
+	roles []string,
+	comment string,
+) (*v1.Deploy, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	deploy, ok := record.Deploys[id]
+	if !ok {
+		return nil, v1.NewInvalidDeployIDError()
+	}
+
+	if deploy.Status.State != v1.DeployStateAwaitingApproval {
+		return nil, v1.NewDeployNotAwaitingApprovalError()
+	}
+
+	// the system's policy may have been removed since the deploy was created
+	policy := record.DeployPolicy
+	if policy == nil {
+		policy = &v1.DeployPolicy{}
+	}
+
+	err = deploypolicy.CanDecide(policy, deploy.CreatedBy, deploy.Approvals, approver, roles)
+	if err != nil {
+		return nil, v1.NewDeployApprovalForbiddenError()
+	}
+
+	deploy.Approvals = append(deploy.Approvals, v1.DeployApproval{
+		User:      approver,
+		Decision:  decision,
+		Comment:   comment,
+		Timestamp: *timeutil.New(time.Now()),
+	})
+
+	return externalDeploy(deploy, record), nil
+}
+
+func (b *DeployBackend) Policy() (*v1.DeployPolicy, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecord(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.DeployPolicy.DeepCopy(), nil
+}
+
+func (b *DeployBackend) SetPolicy(policy *v1.DeployPolicy) error {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecord(b.systemID)
+	if err != nil {
+		return err
+	}
+
+	record.DeployPolicy = policy.DeepCopy()
+	return nil
+}
+
+// externalDeploy copies the deploy so we don't return a pointer into the backend
+// so we can release the lock, and sets its position in the queue if it is queued.
+func externalDeploy(deploy *v1.Deploy, record *registry.SystemRecord) *v1.Deploy {
+	deploy = deploy.DeepCopy()
+	if deploy.Status.State == v1.DeployStateQueued && deploy.Status.Path != nil {
+		position := int32(record.DeploysQueuedAhead(deploy.ID, *deploy.Status.Path) + 1)
+		deploy.Status.QueuePosition = &position
+	}
+
+	return deploy
 }
@@ -2,8 +2,10 @@ package system
 
 import (
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/backend/mock/api/server/backend/registry"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/quota"
 	"github.com/satori/go.uuid"
 	"io"
 	"io/ioutil"
@@ -33,11 +35,15 @@ func (b *JobBackend) Run(
 		return nil, v1.NewInvalidPathError()
 	}
 
-	_, ok = i.Component.(*definitionv1.Job)
+	definition, ok := i.Component.(*definitionv1.Job)
 	if !ok {
 		return nil, v1.NewInvalidPathError()
 	}
 
+	if err := checkJobRunQuota(record, definition); err != nil {
+		return nil, err
+	}
+
 	job := &v1.Job{
 		ID:   v1.JobID(uuid.NewV4().String()),
 		Path: path,
@@ -56,6 +62,55 @@ func (b *JobBackend) Run(
 	return job.DeepCopy(), nil
 }
 
+// checkJobRunQuota returns a quota exceeded error if running the job would exceed
+// the CPU, memory or job runs limits of the system's quota, or if the job's
+// containers don't request the CPU or memory the quota limits.
+func checkJobRunQuota(record *registry.SystemRecord, job *definitionv1.Job) error {
+	if record.System.Quota == nil {
+		return nil
+	}
+
+	usage, err := quota.DefinitionUsage(record.Definition)
+	if err != nil {
+		return err
+	}
+
+	jobRuns, err := record.JobRunsUsage()
+	if err != nil {
+		return err
+	}
+
+	if err := quota.CheckRequests(record.System.Quota, job); err != nil {
+		if _, ok := err.(*quota.UnrequestedError); ok {
+			return v1.NewSystemQuotaExceededError()
+		}
+
+		return err
+	}
+
+	jobRun, err := quota.WorkloadUsage(job)
+	if err != nil {
+		return err
+	}
+
+	// services' instances don't change when running a job, so only check the
+	// limits that the job run counts towards
+	usage = v1.SystemUsage{
+		MilliCPU:  usage.MilliCPU + jobRuns.MilliCPU + jobRun.MilliCPU,
+		MemoryMiB: usage.MemoryMiB + jobRuns.MemoryMiB + jobRun.MemoryMiB,
+		JobRuns:   jobRuns.JobRuns + 1,
+	}
+	if err := quota.Check(record.System.Quota, usage); err != nil {
+		if _, ok := err.(*quota.ExceededError); ok {
+			return v1.NewSystemQuotaExceededError()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 func (b *JobBackend) List() ([]v1.Job, error) {
 	b.backend.registry.Lock()
 	defer b.backend.registry.Unlock()
@@ -1,8 +1,11 @@
 package system
 
 import (
+	"time"
+
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
 )
 
 type NodePoolBackend struct {
@@ -43,3 +46,64 @@ func (b *NodePoolBackend) Get(path tree.PathSubcomponent) (*v1.NodePool, error)
 
 	return nodePool.DeepCopy(), nil
 }
+
+func (b *NodePoolBackend) Override(path tree.PathSubcomponent, numInstances int32) (*v1.NodePool, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodePool, ok := record.NodePools[path]
+	if !ok {
+		return nil, v1.NewInvalidPathError()
+	}
+
+	definitionNumInstances := nodePool.NumInstances
+	if nodePool.Override != nil {
+		definitionNumInstances = nodePool.Override.DefinitionNumInstances
+	}
+
+	nodePool.Override = &v1.NodePoolOverride{
+		NumInstances:           numInstances,
+		DefinitionNumInstances: definitionNumInstances,
+		Timestamp:              *timeutil.New(time.Now()),
+	}
+	nodePool.NumInstances = numInstances
+	nodePool.Status.NumInstances = numInstances
+
+	if err := record.RecordInstanceHours(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return nodePool.DeepCopy(), nil
+}
+
+func (b *NodePoolBackend) ClearOverride(path tree.PathSubcomponent) (*v1.NodePool, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodePool, ok := record.NodePools[path]
+	if !ok {
+		return nil, v1.NewInvalidPathError()
+	}
+
+	if nodePool.Override != nil {
+		nodePool.NumInstances = nodePool.Override.DefinitionNumInstances
+		nodePool.Status.NumInstances = nodePool.Override.DefinitionNumInstances
+		nodePool.Override = nil
+	}
+
+	if err := record.RecordInstanceHours(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return nodePool.DeepCopy(), nil
+}
@@ -3,6 +3,7 @@ package system
 import (
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 )
 
 type SecretBackend struct {
@@ -12,80 +13,87 @@ type SecretBackend struct {
 
 // Secrets
 func (b *SecretBackend) List() ([]v1.Secret, error) {
-	b.backend.registry.Lock()
-	defer b.backend.registry.Unlock()
-
-	record, err := b.backend.systemRecordInitialized(b.systemID)
-	if err != nil {
+	if err := b.ensureSystemInitialized(); err != nil {
 		return nil, err
 	}
 
-	var secrets []v1.Secret
-	for _, secret := range record.Secrets {
-		secrets = append(secrets, *secret.DeepCopy())
+	secrets := make([]v1.Secret, 0)
+	for _, name := range b.backend.secretProviders.Names() {
+		provider, err := b.backend.secretProviders.Provider(name)
+		if err != nil {
+			return nil, err
+		}
+
+		providerSecrets, err := provider.List(b.systemID)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, providerSecrets...)
 	}
 
 	return secrets, nil
 }
 
-func (b *SecretBackend) Get(path tree.PathSubcomponent) (*v1.Secret, error) {
-	b.backend.registry.Lock()
-	defer b.backend.registry.Unlock()
-
-	record, err := b.backend.systemRecordInitialized(b.systemID)
+func (b *SecretBackend) Get(path tree.PathSubcomponent, provider string) (*v1.Secret, error) {
+	p, err := b.provider(provider)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, secret := range record.Secrets {
-		if secret.Path == path {
-			return secret.DeepCopy(), nil
-		}
+	return p.Get(b.systemID, path)
+}
+
+func (b *SecretBackend) Versions(path tree.PathSubcomponent, provider string) ([]v1.Secret, error) {
+	p, err := b.provider(provider)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, v1.NewInvalidSecretError()
+	return p.Versions(b.systemID, path)
 }
 
-func (b *SecretBackend) Set(path tree.PathSubcomponent, value string) error {
-	b.backend.registry.Lock()
-	defer b.backend.registry.Unlock()
-
-	record, err := b.backend.systemRecordInitialized(b.systemID)
+func (b *SecretBackend) Reveal(path tree.PathSubcomponent, provider string, version int32) (*v1.Secret, error) {
+	p, err := b.provider(provider)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, secret := range record.Secrets {
-		if secret.Path == path {
-			secret.Value = value
-			return nil
-		}
-	}
+	return p.Reveal(b.systemID, path, version)
+}
 
-	secret := &v1.Secret{
-		Path:  path,
-		Value: value,
+// Set stores a new version of the secret. The mock backend does not run any
+// workloads, so there is nothing to restart.
+func (b *SecretBackend) Set(path tree.PathSubcomponent, provider, value, author string, restart bool) error {
+	p, err := b.provider(provider)
+	if err != nil {
+		return err
 	}
 
-	record.Secrets[path] = secret
-
-	return nil
+	return p.Set(b.systemID, path, value, author)
 }
 
-func (b *SecretBackend) Unset(path tree.PathSubcomponent) error {
-	b.backend.registry.Lock()
-	defer b.backend.registry.Unlock()
-
-	record, err := b.backend.systemRecordInitialized(b.systemID)
+func (b *SecretBackend) Unset(path tree.PathSubcomponent, provider string) error {
+	p, err := b.provider(provider)
 	if err != nil {
 		return err
 	}
 
-	_, ok := record.Secrets[path]
-	if !ok {
-		return v1.NewInvalidSecretError()
+	return p.Unset(b.systemID, path)
+}
+
+func (b *SecretBackend) provider(name string) (secretprovider.Interface, error) {
+	if err := b.ensureSystemInitialized(); err != nil {
+		return nil, err
 	}
 
-	delete(record.Secrets, path)
-	return nil
+	return b.backend.secretProviders.Provider(name)
+}
+
+func (b *SecretBackend) ensureSystemInitialized() error {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	_, err := b.backend.systemRecordInitialized(b.systemID)
+	return err
 }
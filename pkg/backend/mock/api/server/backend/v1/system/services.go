@@ -1,11 +1,13 @@
 package system
 
 import (
+	"fmt"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 )
 
 type ServiceBackend struct {
@@ -25,7 +27,7 @@ func (b *ServiceBackend) List() ([]v1.Service, error) {
 
 	var services []v1.Service
 	for _, service := range record.Services {
-		services = append(services, *service.Service.DeepCopy())
+		services = append(services, *transformService(service.Service))
 	}
 
 	return services, nil
@@ -45,7 +47,7 @@ func (b *ServiceBackend) Get(id v1.ServiceID) (*v1.Service, error) {
 		return nil, v1.NewInvalidServiceIDError()
 	}
 
-	return service.Service.DeepCopy(), nil
+	return transformService(service.Service), nil
 }
 
 func (b *ServiceBackend) GetByPath(path tree.Path) (*v1.Service, error) {
@@ -63,7 +65,7 @@ func (b *ServiceBackend) GetByPath(path tree.Path) (*v1.Service, error) {
 	}
 
 	service := record.Services[id]
-	return service.Service.DeepCopy(), nil
+	return transformService(service.Service), nil
 }
 
 func (b *ServiceBackend) Logs(
@@ -79,3 +81,101 @@ func (b *ServiceBackend) Logs(
 
 	return ioutil.NopCloser(strings.NewReader("this is a long line")), nil
 }
+
+func (b *ServiceBackend) InjectFault(id v1.ServiceID, fault *v1.ServiceFault) error {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		return err
+	}
+
+	service, ok := record.Services[id]
+	if !ok {
+		return v1.NewInvalidServiceIDError()
+	}
+
+	service.Service.Fault = fault.DeepCopy()
+	return nil
+}
+
+func (b *ServiceBackend) ClearFault(id v1.ServiceID) error {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecordInitialized(b.systemID)
+	if err != nil {
+		return err
+	}
+
+	service, ok := record.Services[id]
+	if !ok {
+		return v1.NewInvalidServiceIDError()
+	}
+
+	service.Service.Fault = nil
+	return nil
+}
+
+func (b *ServiceBackend) Exec(
+	id v1.ServiceID,
+	sidecar *string,
+	instance string,
+	command []string,
+	tty bool,
+	streams *v1.ContainerExecStreams,
+) error {
+	if err := b.validateInstance(id, instance); err != nil {
+		return err
+	}
+
+	// echo stdin back if there is any, otherwise pretend to run the command
+	if streams.Stdin != nil {
+		_, err := io.Copy(streams.Stdout, streams.Stdin)
+		return err
+	}
+
+	_, err := fmt.Fprintf(streams.Stdout, "ran %v\n", strings.Join(command, " "))
+	return err
+}
+
+func (b *ServiceBackend) PortForward(id v1.ServiceID, instance string, port int32, conn io.ReadWriter) error {
+	if err := b.validateInstance(id, instance); err != nil {
+		return err
+	}
+
+	// echo the connection back
+	_, err := io.Copy(conn, conn)
+	return err
+}
+
+// transformService returns a copy of the service without its fault if the
+// fault has expired.
+func transformService(service *v1.Service) *v1.Service {
+	service = service.DeepCopy()
+	if service.Fault != nil && !time.Now().Before(service.Fault.Expires.Time) {
+		service.Fault = nil
+	}
+
+	return service
+}
+
+func (b *ServiceBackend) validateInstance(id v1.ServiceID, instance string) error {
+	service, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if instance == "" {
+		return nil
+	}
+
+	for _, i := range service.Status.Instances {
+		if i == instance {
+			return nil
+		}
+	}
+
+	return v1.NewInvalidInstanceError()
+}
@@ -0,0 +1,80 @@
+package system
+
+import (
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+
+	"github.com/satori/go.uuid"
+)
+
+// maxWebhookEvents is the number of events kept for each system.
+const maxWebhookEvents = 100
+
+type WebhookBackend struct {
+	systemID v1.SystemID
+	backend  *Backend
+}
+
+func (b *WebhookBackend) Config() (*v1.WebhookConfig, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecord(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.WebhookConfig.DeepCopy(), nil
+}
+
+func (b *WebhookBackend) SetConfig(config *v1.WebhookConfig) error {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecord(b.systemID)
+	if err != nil {
+		return err
+	}
+
+	record.WebhookConfig = config.DeepCopy()
+	return nil
+}
+
+func (b *WebhookBackend) ListEvents() ([]v1.WebhookEvent, error) {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecord(b.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]v1.WebhookEvent, 0)
+	for _, event := range record.WebhookEvents {
+		events = append(events, *event.DeepCopy())
+	}
+
+	return events, nil
+}
+
+func (b *WebhookBackend) RecordEvent(event *v1.WebhookEvent) error {
+	b.backend.registry.Lock()
+	defer b.backend.registry.Unlock()
+
+	record, err := b.backend.systemRecord(b.systemID)
+	if err != nil {
+		return err
+	}
+
+	event.ID = v1.WebhookEventID(uuid.NewV4().String())
+	event.ReceivedTimestamp = *timeutil.New(time.Now())
+
+	record.WebhookEvents = append([]v1.WebhookEvent{*event.DeepCopy()}, record.WebhookEvents...)
+	if len(record.WebhookEvents) > maxWebhookEvents {
+		record.WebhookEvents = record.WebhookEvents[:maxWebhookEvents]
+	}
+
+	return nil
+}
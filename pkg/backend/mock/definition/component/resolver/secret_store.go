@@ -6,6 +6,7 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 )
 
 func NewMemorySecretStore() *MemorySecretStore {
@@ -23,8 +24,8 @@ func (s *MemorySecretStore) Ready() bool {
 	return true
 }
 
-func (s *MemorySecretStore) Get(systemID v1.SystemID, path tree.PathSubcomponent) (string, error) {
-	v, ok := s.store[s.keyString(systemID, path)]
+func (s *MemorySecretStore) Get(systemID v1.SystemID, ref *definitionv1.SecretRef) (string, error) {
+	v, ok := s.store[s.keyString(systemID, ref.Value)]
 	if !ok {
 		return "", &resolver.SecretDoesNotExistError{}
 	}
@@ -32,6 +33,11 @@ func (s *MemorySecretStore) Get(systemID v1.SystemID, path tree.PathSubcomponent
 	return v, nil
 }
 
+// Set sets the value of the system's secret at path.
+func (s *MemorySecretStore) Set(systemID v1.SystemID, path tree.PathSubcomponent, value string) {
+	s.store[s.keyString(systemID, path)] = value
+}
+
 func (s *MemorySecretStore) keyString(systemID v1.SystemID, path tree.PathSubcomponent) string {
 	return fmt.Sprintf("%v.%v", systemID, path.String())
 }
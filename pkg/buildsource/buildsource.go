@@ -0,0 +1,141 @@
+package buildsource
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/util/docker"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+)
+
+const (
+	// URLScheme is the scheme of the repository URLs given to build sources. The
+	// rest of the URL is the reference to the image the build source is stored in,
+	// pinned by its digest.
+	URLScheme = "lattice-build-source"
+
+	urlPrefix = URLScheme + "://"
+
+	gitDirectory = ".git"
+)
+
+// URL returns the repository URL of the build source stored in the image in
+// the registry's repository with the digest.
+func URL(registry, repository, digest string) string {
+	return fmt.Sprintf("%v%v/%v@%v", urlPrefix, registry, repository, digest)
+}
+
+// IsURL returns whether the repository URL refers to a build source.
+func IsURL(url string) bool {
+	return strings.HasPrefix(url, urlPrefix)
+}
+
+// ParseURL returns the registry, repository and digest of the image
+// that the build source with the repository URL is stored in.
+func ParseURL(url string) (string, string, string, error) {
+	if !IsURL(url) {
+		return "", "", "", fmt.Errorf("%v is not a build source URL", url)
+	}
+
+	image := strings.TrimPrefix(url, urlPrefix)
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) != 2 {
+		return "", "", "", fmt.Errorf("build source URL %v does not contain a repository", url)
+	}
+
+	registry, reference := parts[0], parts[1]
+	i := strings.LastIndex(reference, "@")
+	if i == -1 || i == 0 || i == len(reference)-1 {
+		return "", "", "", fmt.Errorf("build source URL %v does not contain a digest", url)
+	}
+
+	return registry, reference[:i], reference[i+1:], nil
+}
+
+// Push stores the tar archive as a single layer image in the repository with the tag,
+// and returns the digest of the image.
+func Push(client *registry.Client, repository, tag string, archive io.Reader) (string, error) {
+	file, err := ioutil.TempFile("", "build-source")
+	if err != nil {
+		return "", err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	layer, err := NewLayer(file.Name(), archive)
+	if err != nil {
+		return "", err
+	}
+
+	image := registry.NewImage()
+	image.AppendLayer(layer, "lattice build source")
+	return client.Push(image, repository, tag)
+}
+
+// NewLayer writes the contents of the tar archive to a layer in the file.
+// Git metadata in the archive is not included in the layer.
+func NewLayer(file string, archive io.Reader) (*registry.Layer, error) {
+	layer, err := registry.NewLayer(file, func(tw *tar.Writer) error {
+		return copyArchive(tar.NewReader(archive), tw)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading build source archive: %v", err)
+	}
+
+	return layer, nil
+}
+
+func copyArchive(tr *tar.Reader, tw *tar.Writer) error {
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(header.Name)
+		if name == "." || name == "/" {
+			continue
+		}
+
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("%v is outside of the archived directory", header.Name)
+		}
+
+		if name == gitDirectory || strings.HasPrefix(name, gitDirectory+"/") {
+			continue
+		}
+
+		header.Name = name
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// Pull extracts the build source with the repository URL into the directory. If
+// credentials is nil, the build source's registry is accessed anonymously.
+func Pull(credentials docker.RegistryLoginProvider, url, directory string) error {
+	registryHost, repository, digest, err := ParseURL(url)
+	if err != nil {
+		return err
+	}
+
+	image, err := registry.NewClient(registryHost, credentials).Pull(repository, digest)
+	if err != nil {
+		return err
+	}
+
+	return image.Unpack(directory)
+}
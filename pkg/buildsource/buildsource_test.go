@@ -0,0 +1,101 @@
+package buildsource
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+	"github.com/mlab-lattice/lattice/pkg/util/registry/registrytest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushPull(t *testing.T) {
+	reg := registrytest.NewRegistry()
+	defer reg.Close()
+
+	archive := &bytes.Buffer{}
+	tw := tar.NewWriter(archive)
+	writeEntry(t, tw, "", tar.TypeDir, "")
+	writeEntry(t, tw, "lattice.yaml", tar.TypeReg, "type: v1/system")
+	writeEntry(t, tw, "service", tar.TypeDir, "")
+	writeEntry(t, tw, "service/Dockerfile", tar.TypeReg, "FROM scratch")
+	writeEntry(t, tw, ".git", tar.TypeDir, "")
+	writeEntry(t, tw, ".git/HEAD", tar.TypeReg, "ref: refs/heads/master")
+	require.NoError(t, tw.Close())
+
+	client := registry.NewClient(reg.Host(), nil)
+	digest, err := Push(client, "sources", "build-source-1", archive)
+	require.NoError(t, err)
+
+	url := URL(reg.Host(), "sources", digest)
+	require.True(t, IsURL(url))
+
+	directory, err := ioutil.TempDir("", "build-source-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	require.NoError(t, Pull(nil, url, directory))
+
+	contents, err := ioutil.ReadFile(filepath.Join(directory, "lattice.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "type: v1/system", string(contents))
+
+	contents, err = ioutil.ReadFile(filepath.Join(directory, "service", "Dockerfile"))
+	require.NoError(t, err)
+	require.Equal(t, "FROM scratch", string(contents))
+
+	_, err = os.Stat(filepath.Join(directory, ".git"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestPushOutsideDirectory(t *testing.T) {
+	reg := registrytest.NewRegistry()
+	defer reg.Close()
+
+	archive := &bytes.Buffer{}
+	tw := tar.NewWriter(archive)
+	writeEntry(t, tw, "../escape", tar.TypeReg, "")
+	require.NoError(t, tw.Close())
+
+	_, err := Push(registry.NewClient(reg.Host(), nil), "sources", "build-source-1", archive)
+	require.Error(t, err)
+}
+
+func TestParseURL(t *testing.T) {
+	registryHost, repository, digest, err := ParseURL(URL("registry.example.com:5000", "team/sources", "sha256:abc"))
+	require.NoError(t, err)
+	require.Equal(t, "registry.example.com:5000", registryHost)
+	require.Equal(t, "team/sources", repository)
+	require.Equal(t, "sha256:abc", digest)
+
+	for _, url := range []string{
+		"https://github.com/mlab-lattice/lattice.git",
+		"lattice-build-source://registry.example.com",
+		"lattice-build-source://registry.example.com/sources",
+		"lattice-build-source://registry.example.com/sources@",
+	} {
+		_, _, _, err := ParseURL(url)
+		require.Error(t, err, url)
+	}
+}
+
+func writeEntry(t *testing.T, tw *tar.Writer, name string, typeflag byte, contents string) {
+	header := &tar.Header{
+		Name:     name,
+		Typeflag: typeflag,
+		Mode:     0644,
+		Size:     int64(len(contents)),
+	}
+	if typeflag == tar.TypeDir {
+		header.Mode = 0755
+	}
+
+	require.NoError(t, tw.WriteHeader(header))
+	_, err := tw.Write([]byte(contents))
+	require.NoError(t, err)
+}
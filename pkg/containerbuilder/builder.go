@@ -17,8 +17,13 @@ type Builder struct {
 	WorkingDir    string
 	DockerOptions *DockerOptions
 	DockerClient  *dockerclient.Client
+	ImageBuilder  ImageBuilder
 	GitOptions    *git.Options
+	CacheOptions  *CacheOptions
 	StatusUpdater StatusUpdater
+
+	cacheInfo  v1.ContainerBuildCacheInfo
+	provenance v1.ContainerBuildProvenance
 }
 
 type DockerOptions struct {
@@ -68,6 +73,8 @@ func NewBuilder(
 	workDirectory string,
 	dockerOptions *DockerOptions,
 	gitResolverOptions *git.Options,
+	cacheOptions *CacheOptions,
+	imageBuilderType ImageBuilderType,
 	updater StatusUpdater,
 ) (*Builder, error) {
 	if workDirectory == "" {
@@ -97,8 +104,21 @@ func NewBuilder(
 		DockerOptions: dockerOptions,
 		DockerClient:  dockerClient,
 		GitOptions:    gitResolverOptions,
+		CacheOptions:  cacheOptions,
 		StatusUpdater: updater,
 	}
+
+	imageBuilder, err := newImageBuilder(b, imageBuilderType)
+	if err != nil {
+		return nil, err
+	}
+	b.ImageBuilder = imageBuilder
+
+	if imageBuilderType == "" {
+		imageBuilderType = ImageBuilderDocker
+	}
+	b.provenance.ImageBuilder = string(imageBuilderType)
+
 	return b, nil
 }
 
@@ -108,16 +128,29 @@ func (b *Builder) Build(containerBuild *definitionv1.ContainerBuild) error {
 		return newErrorInternal("failed to create working directory: " + err.Error())
 	}
 
+	// report how much of the cache the build was able to use regardless
+	// of whether the build succeeded
+	defer b.reportCacheInfo()
+
+	if err := b.handleError(b.build(containerBuild)); err != nil {
+		return err
+	}
+
+	b.reportProvenance()
+	return nil
+}
+
+func (b *Builder) build(containerBuild *definitionv1.ContainerBuild) error {
 	if containerBuild.CommandBuild != nil {
-		return b.handleError(b.buildCommandBuildContainer(containerBuild.CommandBuild))
+		return b.buildCommandBuildContainer(containerBuild.CommandBuild)
 	}
 
 	if containerBuild.DockerImage != nil {
-		return b.handleError(b.buildDockerImageContainer(containerBuild.DockerImage))
+		return b.ImageBuilder.BuildFromImage(containerBuild.DockerImage)
 	}
 
 	if containerBuild.DockerBuild != nil {
-		return b.handleError(b.buildDockerBuildContainer(containerBuild.DockerBuild))
+		return b.buildDockerBuildContainer(containerBuild.DockerBuild)
 	}
 
 	return newErrorUser("unsupported container build type")
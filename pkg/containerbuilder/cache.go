@@ -0,0 +1,239 @@
+package containerbuilder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/sha1"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/fatih/color"
+)
+
+const (
+	cacheGitDirectory     = "git"
+	dockerCacheTagPrefix  = "cache-"
+	dockerCacheTagHashLen = 20
+)
+
+// CacheOptions configures the cache shared between builds.
+type CacheOptions struct {
+	// Directory persists between builds. Git repositories are mirrored
+	// under it, keyed by their URL.
+	Directory string
+
+	// DockerRepository is the repository that the images whose layers are
+	// reused by later builds are stored in. If empty, docker layers are not cached.
+	DockerRepository string
+}
+
+func (b *Builder) gitCacheEnabled() bool {
+	return b.CacheOptions != nil && b.CacheOptions.Directory != ""
+}
+
+func (b *Builder) dockerCacheEnabled() bool {
+	return b.CacheOptions != nil && b.CacheOptions.DockerRepository != ""
+}
+
+// recordCacheResult records whether a part of the build was able to use the cache.
+// A single miss means the build was not able to fully use that part of the cache.
+func recordCacheResult(result *v1.ContainerBuildCacheResult, hit bool) {
+	if !hit {
+		*result = v1.ContainerBuildCacheMiss
+		return
+	}
+
+	if *result == "" {
+		*result = v1.ContainerBuildCacheHit
+	}
+}
+
+func (b *Builder) reportCacheInfo() {
+	if b.StatusUpdater == nil {
+		return
+	}
+
+	if b.cacheInfo.GitRepository == "" && b.cacheInfo.DockerLayers == "" {
+		return
+	}
+
+	// For now ignore status update errors, don't need to fail a build because the status could
+	// not be updated.
+	b.StatusUpdater.UpdateCache(b.BuildID, b.SystemID, &b.cacheInfo)
+}
+
+// lockDirectory takes an exclusive lock on the directory so that concurrent builds
+// on the same node do not modify the same git mirror at once. The returned function
+// releases the lock.
+func lockDirectory(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	unlock := func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}
+	return unlock, nil
+}
+
+// copyCheckout copies the checked out files of the repository at src into dst,
+// skipping the repository's .git directory.
+func copyCheckout(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode())
+
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+
+		default:
+			return copyFile(path, target, info.Mode())
+		}
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// dockerCacheImageFQN returns the FQN of the image whose layers are reused by builds
+// of the container identified by the parts, or false if docker layers are not cached.
+func (b *Builder) dockerCacheImageFQN(parts ...string) (string, bool, error) {
+	if !b.dockerCacheEnabled() {
+		return "", false, nil
+	}
+
+	hash, err := sha1.EncodeToHexString([]byte(strings.Join(parts, "\n")))
+	if err != nil {
+		return "", false, err
+	}
+
+	tag := dockerCacheTagPrefix + hash[:dockerCacheTagHashLen]
+	return getDockerImageFQN(b.DockerOptions.Registry, b.CacheOptions.DockerRepository, tag), true, nil
+}
+
+// importDockerLayerCache makes the cache image available to the docker daemon so its
+// layers can be reused, and returns whether it is available. If images are not pushed
+// the cache image can only be on the daemon already.
+func (b *Builder) importDockerLayerCache(cacheImageFQN string) bool {
+	color.Blue("Importing docker layer cache...")
+
+	hit := b.dockerImageExists(cacheImageFQN)
+	if !hit && b.DockerOptions.Push {
+		hit = b.pullDockerCacheImage(cacheImageFQN)
+	}
+
+	recordCacheResult(&b.cacheInfo.DockerLayers, hit)
+
+	if hit {
+		color.Green("✓ Cache hit")
+	} else {
+		color.Yellow("Cache miss")
+	}
+	fmt.Println()
+
+	return hit
+}
+
+func (b *Builder) dockerImageExists(dockerImageFQN string) bool {
+	_, _, err := b.DockerClient.ImageInspectWithRaw(context.Background(), dockerImageFQN)
+	return err == nil
+}
+
+func (b *Builder) pullDockerCacheImage(cacheImageFQN string) bool {
+	pullOptions := dockertypes.ImagePullOptions{}
+	auth, err := b.registryAuth()
+	if err != nil {
+		return false
+	}
+	pullOptions.RegistryAuth = auth
+
+	responseBody, err := b.DockerClient.ImagePull(context.Background(), cacheImageFQN, pullOptions)
+	if err != nil {
+		return false
+	}
+	defer responseBody.Close()
+
+	// the cache image not existing is expected, so don't display the progress
+	err = jsonmessage.DisplayJSONMessagesStream(responseBody, ioutil.Discard, 0, false, nil)
+	return err == nil
+}
+
+// exportDockerLayerCache tags the built image as the cache image, and pushes it if
+// images are pushed. Failing to export the cache does not fail the build.
+func (b *Builder) exportDockerLayerCache(cacheImageFQN string) {
+	color.Blue("Exporting docker layer cache...")
+
+	dockerImageFQN := getDockerImageFQN(b.DockerOptions.Registry, b.DockerOptions.Repository, b.DockerOptions.Tag)
+	if err := b.DockerClient.ImageTag(context.Background(), dockerImageFQN, cacheImageFQN); err != nil {
+		color.Yellow("failed to tag docker layer cache image: " + err.Error())
+		fmt.Println()
+		return
+	}
+
+	if b.DockerOptions.Push {
+		if err := b.pushImage(cacheImageFQN); err != nil {
+			color.Yellow("failed to push docker layer cache image: " + err.Error())
+			fmt.Println()
+			return
+		}
+	}
+
+	color.Green("✓ Success!")
+	fmt.Println()
+}
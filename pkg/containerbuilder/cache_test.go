@@ -0,0 +1,136 @@
+package containerbuilder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordCacheResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		hits     []bool
+		expected v1.ContainerBuildCacheResult
+	}{
+		{name: "hit", hits: []bool{true, true}, expected: v1.ContainerBuildCacheHit},
+		{name: "miss", hits: []bool{false}, expected: v1.ContainerBuildCacheMiss},
+		{name: "miss after hit", hits: []bool{true, false, true}, expected: v1.ContainerBuildCacheMiss},
+	}
+
+	for _, test := range tests {
+		var result v1.ContainerBuildCacheResult
+		for _, hit := range test.hits {
+			recordCacheResult(&result, hit)
+		}
+		require.Equal(t, test.expected, result, test.name)
+	}
+}
+
+func TestCopyCheckout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerbuilder")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "mirror")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "cmd"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "cmd", "main.go"), []byte("package main"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "build.sh"), []byte("make"), 0755))
+	require.NoError(t, os.Symlink("build.sh", filepath.Join(src, "run.sh")))
+
+	// files left over from an earlier build are removed
+	dst := filepath.Join(dir, "checkout")
+	require.NoError(t, os.MkdirAll(dst, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dst, "stale"), nil, 0644))
+
+	require.NoError(t, copyCheckout(src, dst))
+
+	data, err := ioutil.ReadFile(filepath.Join(dst, "cmd", "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package main", string(data))
+
+	info, err := os.Stat(filepath.Join(dst, "build.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(dst, "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, "build.sh", link)
+
+	_, err = os.Stat(filepath.Join(dst, ".git"))
+	require.True(t, os.IsNotExist(err))
+
+	_, err = os.Stat(filepath.Join(dst, "stale"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestLockDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerbuilder")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the lock is taken next to the directory, whose parents are created
+	path := filepath.Join(dir, "git", "example.com", "repo")
+	unlock, err := lockDirectory(path)
+	require.NoError(t, err)
+
+	_, err = os.Stat(path + ".lock")
+	require.NoError(t, err)
+	unlock()
+
+	// the lock can be taken again once released
+	unlock, err = lockDirectory(path)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestDockerCacheImageFQN(t *testing.T) {
+	b := &Builder{
+		DockerOptions: &DockerOptions{Registry: "registry.example.com"},
+	}
+
+	_, ok, err := b.dockerCacheImageFQN("system", "api")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	b.CacheOptions = &CacheOptions{DockerRepository: "cache"}
+	fqn, ok, err := b.dockerCacheImageFQN("system", "api")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, strings.HasPrefix(fqn, "registry.example.com/cache:"+dockerCacheTagPrefix), fqn)
+	require.Len(t, strings.TrimPrefix(fqn, "registry.example.com/cache:"+dockerCacheTagPrefix), dockerCacheTagHashLen)
+
+	// the same container shares a cache image, other containers do not
+	same, _, err := b.dockerCacheImageFQN("system", "api")
+	require.NoError(t, err)
+	require.Equal(t, fqn, same)
+
+	other, _, err := b.dockerCacheImageFQN("system", "worker")
+	require.NoError(t, err)
+	require.NotEqual(t, fqn, other)
+}
+
+func TestLocationCacheKey(t *testing.T) {
+	require.Empty(t, locationCacheKey(nil))
+	require.Empty(t, locationCacheKey(&definitionv1.Location{}))
+
+	// builds of different commits of a repository share a cache
+	location := func(commit string) *definitionv1.Location {
+		return &definitionv1.Location{
+			GitRepository: &definitionv1.GitRepository{
+				URL:    "https://github.com/mlab-lattice/lattice.git",
+				Commit: &commit,
+			},
+		}
+	}
+	require.Equal(t, locationCacheKey(location("a")), locationCacheKey(location("b")))
+	require.Equal(t, "https://github.com/mlab-lattice/lattice.git", locationCacheKey(location("a")))
+}
@@ -10,16 +10,8 @@ func (b *Builder) buildCommandBuildContainer(commandBuild *v1.ContainerBuildComm
 		return err
 	}
 
-	baseImage, err := getDockerImageFQNFromDockerImageBlock(&commandBuild.BaseImage)
-	if err != nil {
-		return err
-	}
+	// the command's environment is passed to the build as build args
+	b.recordBuildArgs(commandBuild.Environment)
 
-	// docker needs the build args to be a map from strings to pointer to strings,
-	// but commandBuild.Environment maps to strings, so create a buildArgs map
-	buildArgs := make(map[string]*string, len(commandBuild.Environment))
-	for k, v := range commandBuild.Environment {
-		buildArgs[k] = &v
-	}
-	return b.buildDockerImage(sourceDirectory, baseImage, commandBuild.Command, buildArgs)
+	return b.ImageBuilder.BuildFromCommand(sourceDirectory, commandBuild)
 }
@@ -0,0 +1,264 @@
+package containerbuilder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+
+	"github.com/fatih/color"
+)
+
+const (
+	daemonlessLayersDirectory = "layers"
+	daemonlessRootFSDirectory = "rootfs"
+
+	commandBuildSourceDirectory = "/usr/src/app"
+
+	// the PATH docker uses when running commands in images that do not set one
+	defaultPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+// daemonlessImageBuilder is the ImageBuilder that assembles images from the layers of
+// their base image and pushes them to the registry itself.
+type daemonlessImageBuilder struct {
+	*Builder
+	registry *registry.Client
+}
+
+func newDaemonlessImageBuilder(b *Builder) (*daemonlessImageBuilder, error) {
+	// without a daemon the registry is the only place the image can be stored
+	if !b.DockerOptions.Push {
+		return nil, newErrorInternal("the daemonless image builder requires images to be pushed")
+	}
+
+	builder := &daemonlessImageBuilder{
+		Builder:  b,
+		registry: registry.NewClient(b.DockerOptions.Registry, b.DockerOptions.RegistryAuthProvider),
+	}
+	return builder, nil
+}
+
+func (b *daemonlessImageBuilder) BuildFromImage(image *definitionv1.DockerImage) error {
+	source, err := b.pull(image)
+	if err != nil {
+		return err
+	}
+
+	if err := b.recordPulledImage(image, source); err != nil {
+		return err
+	}
+
+	return b.push(source)
+}
+
+func (b *daemonlessImageBuilder) BuildFromCommand(sourceDirectory string, commandBuild *definitionv1.ContainerBuildCommand) error {
+	image, err := b.pull(&commandBuild.BaseImage)
+	if err != nil {
+		return err
+	}
+
+	if err := b.recordPulledImage(&commandBuild.BaseImage, image); err != nil {
+		return err
+	}
+
+	color.Blue("Building docker image...")
+
+	if b.StatusUpdater != nil {
+		// For now ignore status update errors, don't need to fail a build because the status could
+		// not be updated.
+		b.StatusUpdater.UpdateProgress(b.BuildID, b.SystemID, v1.ContainerBuildPhaseBuildingDockerImage)
+	}
+
+	layersDirectory := filepath.Join(b.WorkingDir, daemonlessLayersDirectory)
+	if err := os.MkdirAll(layersDirectory, 0777); err != nil {
+		return newErrorInternal("failed to create layers directory: " + err.Error())
+	}
+
+	// produces the same image as the Dockerfile used by the docker image builder
+	source, err := registry.DirectoryLayer(
+		filepath.Join(layersDirectory, "source.tar.gz"),
+		sourceDirectory,
+		commandBuildSourceDirectory,
+	)
+	if err != nil {
+		return newErrorInternal("could not create source layer: " + err.Error())
+	}
+
+	image.AppendLayer(source, fmt.Sprintf("COPY . %v", commandBuildSourceDirectory))
+	image.Config.Config.WorkingDir = commandBuildSourceDirectory
+
+	if len(commandBuild.Command) > 0 {
+		layer, err := b.runCommand(image, commandBuild, layersDirectory)
+		if err != nil {
+			return err
+		}
+
+		image.AppendLayer(layer, "RUN "+strings.Join(commandBuild.Command, " "))
+	}
+
+	color.Green("✓ Success!")
+	fmt.Println()
+
+	return b.push(image)
+}
+
+// runCommand runs the build's command in the image's filesystem, and returns a layer
+// containing the changes it made to the filesystem.
+func (b *daemonlessImageBuilder) runCommand(
+	image *registry.Image,
+	commandBuild *definitionv1.ContainerBuildCommand,
+	layersDirectory string,
+) (*registry.Layer, error) {
+	rootfs := filepath.Join(b.WorkingDir, daemonlessRootFSDirectory)
+	if err := os.RemoveAll(rootfs); err != nil {
+		return nil, newErrorInternal("failed to clean root filesystem directory: " + err.Error())
+	}
+
+	if err := image.Unpack(rootfs); err != nil {
+		return nil, newErrorInternal("could not unpack image: " + err.Error())
+	}
+
+	// like docker, let the command resolve hostnames the same way the builder does
+	resolvConf := filepath.Join(rootfs, "etc", "resolv.conf")
+	if err := os.MkdirAll(filepath.Dir(resolvConf), 0755); err != nil {
+		return nil, newErrorInternal("could not create /etc: " + err.Error())
+	}
+	if err := os.Remove(resolvConf); err != nil && !os.IsNotExist(err) {
+		return nil, newErrorInternal("could not remove image's resolv.conf: " + err.Error())
+	}
+	if err := copyFile("/etc/resolv.conf", resolvConf, 0644); err != nil {
+		return nil, newErrorInternal("could not copy resolv.conf: " + err.Error())
+	}
+
+	// the snapshot is taken after resolv.conf is copied so that it is not included in the layer
+	snapshot, err := registry.NewSnapshot(rootfs)
+	if err != nil {
+		return nil, newErrorInternal("could not snapshot root filesystem: " + err.Error())
+	}
+
+	// The command is run in a chroot of the image's filesystem rather than in a container,
+	// so it runs as the builder's user and shares its network and process namespaces.
+	cmd := exec.Command("/bin/sh", "-c", strings.Join(commandBuild.Command, " "))
+	cmd.Dir = commandBuildSourceDirectory
+	cmd.Env = commandEnvironment(image.Config.Config.Env, commandBuild.Environment)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Chroot: rootfs,
+	}
+
+	if err := cmd.Run(); err != nil {
+		return nil, newErrorUser("build command failed: " + err.Error())
+	}
+
+	layer, err := snapshot.Layer(filepath.Join(layersDirectory, "command.tar.gz"))
+	if err != nil {
+		return nil, newErrorInternal("could not create command layer: " + err.Error())
+	}
+
+	return layer, nil
+}
+
+// commandEnvironment returns the environment the command is run with, which like a
+// build arg in a Dockerfile is not persisted in the image.
+func commandEnvironment(imageEnv []string, buildEnv definitionv1.ContainerBuildEnvironment) []string {
+	env := make([]string, len(imageEnv))
+	copy(env, imageEnv)
+
+	hasPath := false
+	for _, e := range env {
+		if strings.HasPrefix(e, "PATH=") {
+			hasPath = true
+		}
+	}
+	if !hasPath {
+		env = append(env, defaultPath)
+	}
+
+	var names []string
+	for name := range buildEnv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		env = append(env, fmt.Sprintf("%v=%v", name, buildEnv[name]))
+	}
+
+	return env
+}
+
+func (b *daemonlessImageBuilder) pull(image *definitionv1.DockerImage) (*registry.Image, error) {
+	color.Blue("Pulling docker image...")
+
+	if b.StatusUpdater != nil {
+		// For now ignore status update errors, don't need to fail a build because the status could
+		// not be updated.
+		b.StatusUpdater.UpdateProgress(b.BuildID, b.SystemID, v1.ContainerBuildPhasePullingDockerImage)
+	}
+
+	// only the builder's registry has credentials
+	client := b.registry
+	if image.Registry != b.DockerOptions.Registry {
+		client = registry.NewClient(image.Registry, nil)
+	}
+
+	reference := image.Digest
+	if reference == "" {
+		reference = image.Tag
+	}
+	if reference == "" {
+		reference = "latest"
+	}
+
+	pulled, err := client.Pull(image.Repository, reference)
+	if err != nil {
+		return nil, newErrorUser("pulling docker image failed: " + err.Error())
+	}
+
+	color.Green("✓ Success!")
+	fmt.Println()
+
+	return pulled, nil
+}
+
+// recordPulledImage records the image pulled for the definition's image as the build's base image.
+func (b *daemonlessImageBuilder) recordPulledImage(image *definitionv1.DockerImage, pulled *registry.Image) error {
+	dockerImageFQN, err := getDockerImageFQNFromDockerImageBlock(image)
+	if err != nil {
+		return err
+	}
+
+	b.recordBaseImage(dockerImageFQN, pulled.Digest)
+	return nil
+}
+
+func (b *daemonlessImageBuilder) push(image *registry.Image) error {
+	color.Blue("Pushing docker image...")
+
+	if b.StatusUpdater != nil {
+		// For now ignore status update errors, don't need to fail a build because the status could
+		// not be updated.
+		b.StatusUpdater.UpdateProgress(b.BuildID, b.SystemID, v1.ContainerBuildPhasePushingDockerImage)
+	}
+
+	digest, err := b.registry.Push(image, b.DockerOptions.Repository, b.DockerOptions.Tag)
+	if err != nil {
+		return newErrorInternal("pushing docker image failed: " + err.Error())
+	}
+	b.provenance.DockerImageDigest = digest
+
+	color.Green("✓ Success!")
+	fmt.Println()
+
+	b.recordSBOM(image)
+	return nil
+}
@@ -0,0 +1,129 @@
+package containerbuilder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+	"github.com/mlab-lattice/lattice/pkg/util/registry/registrytest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDaemonlessImageBuilder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerbuilder")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	reg := registrytest.NewRegistry()
+	defer reg.Close()
+
+	// push a base image containing a single file
+	rootfs := filepath.Join(dir, "base")
+	require.NoError(t, os.MkdirAll(filepath.Join(rootfs, "bin"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(rootfs, "bin", "hello"), []byte("hello"), 0755))
+
+	status := filepath.Join(rootfs, "var", "lib", "dpkg", "status")
+	require.NoError(t, os.MkdirAll(filepath.Dir(status), 0755))
+	require.NoError(t, ioutil.WriteFile(status, []byte("Package: hello\nStatus: install ok installed\nVersion: 1.0\n"), 0644))
+
+	layer, err := registry.DirectoryLayer(filepath.Join(dir, "base.tar.gz"), rootfs, "/")
+	require.NoError(t, err)
+
+	base := registry.NewImage()
+	base.AppendLayer(layer, "COPY . /")
+	base.Config.Config.Cmd = []string{"/bin/hello"}
+
+	client := registry.NewClient(reg.Host(), nil)
+	baseDigest, err := client.Push(base, "base", "latest")
+	require.NoError(t, err)
+
+	baseImage := definitionv1.DockerImage{
+		Registry:   reg.Host(),
+		Repository: "base",
+		Tag:        "latest",
+	}
+
+	newBuilder := func(tag string) *daemonlessImageBuilder {
+		b := &Builder{
+			WorkingDir: filepath.Join(dir, "work-"+tag),
+			DockerOptions: &DockerOptions{
+				Registry:   reg.Host(),
+				Repository: "app",
+				Tag:        tag,
+				Push:       true,
+			},
+		}
+
+		imageBuilder, err := newImageBuilder(b, ImageBuilderDaemonless)
+		require.NoError(t, err)
+		return imageBuilder.(*daemonlessImageBuilder)
+	}
+
+	t.Run("docker image", func(t *testing.T) {
+		b := newBuilder("image")
+		require.NoError(t, b.BuildFromImage(&baseImage))
+
+		image := requireManifest(t, reg, "app", "image")
+		require.Len(t, image.Layers, 1)
+		require.Equal(t, layer.Digest, image.Layers[0].Digest)
+
+		// the image is the same as the base image, so has the same digest
+		require.Equal(t, baseDigest, b.provenance.DockerImageDigest)
+		require.Equal(t, baseDigest, b.provenance.BaseImage.Digest)
+		require.Equal(t, []v1.ContainerBuildPackage{{Type: "deb", Name: "hello", Version: "1.0"}}, b.provenance.SBOM.Packages)
+	})
+
+	t.Run("command", func(t *testing.T) {
+		source := filepath.Join(dir, "source")
+		require.NoError(t, os.MkdirAll(source, 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(source, "main.go"), []byte("package main"), 0644))
+
+		commandBuild := &definitionv1.ContainerBuildCommand{
+			BaseImage: baseImage,
+		}
+		b := newBuilder("command")
+		require.NoError(t, b.BuildFromCommand(source, commandBuild))
+
+		image := requireManifest(t, reg, "app", "command")
+		require.Len(t, image.Layers, 2)
+		require.Equal(t, baseDigest, b.provenance.BaseImage.Digest)
+		require.NotEqual(t, baseDigest, b.provenance.DockerImageDigest)
+
+		pulled, err := client.Pull("app", "command")
+		require.NoError(t, err)
+		require.Equal(t, commandBuildSourceDirectory, pulled.Config.Config.WorkingDir)
+		require.Equal(t, []string{"/bin/hello"}, pulled.Config.Config.Cmd)
+
+		unpacked := filepath.Join(dir, "unpacked")
+		require.NoError(t, pulled.Unpack(unpacked))
+
+		data, err := ioutil.ReadFile(filepath.Join(unpacked, "usr", "src", "app", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(data))
+	})
+
+	t.Run("requires push", func(t *testing.T) {
+		b := &Builder{
+			WorkingDir:    dir,
+			DockerOptions: &DockerOptions{Registry: reg.Host()},
+		}
+
+		_, err := newImageBuilder(b, ImageBuilderDaemonless)
+		require.Error(t, err)
+	})
+}
+
+func requireManifest(t *testing.T, reg *registrytest.Registry, repository, tag string) *registry.Manifest {
+	_, data, ok := reg.Manifest(repository, tag)
+	require.True(t, ok, "%v:%v was not pushed", repository, tag)
+
+	var manifest registry.Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	return &manifest
+}
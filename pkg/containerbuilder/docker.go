@@ -12,6 +12,7 @@ import (
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
 	"github.com/mlab-lattice/lattice/pkg/util/tar"
 
 	dockertypes "github.com/docker/docker/api/types"
@@ -23,7 +24,12 @@ const (
 	defaultDockerFileName = "Dockerfile"
 )
 
-func (b *Builder) buildDockerImageContainer(image *definitionv1.DockerImage) error {
+// dockerImageBuilder is the ImageBuilder that builds images with the docker daemon.
+type dockerImageBuilder struct {
+	*Builder
+}
+
+func (b *dockerImageBuilder) BuildFromImage(image *definitionv1.DockerImage) error {
 	sourceDockerImageFQN, err := getDockerImageFQNFromDockerImageBlock(image)
 	if err != nil {
 		return err
@@ -34,6 +40,10 @@ func (b *Builder) buildDockerImageContainer(image *definitionv1.DockerImage) err
 		return err
 	}
 
+	if err := b.recordDockerBaseImage(sourceDockerImageFQN); err != nil {
+		return err
+	}
+
 	err = b.tagDockerImage(sourceDockerImageFQN)
 	if err != nil {
 		return err
@@ -47,11 +57,49 @@ func (b *Builder) buildDockerImageContainer(image *definitionv1.DockerImage) err
 	return b.pushDockerImage()
 }
 
+func (b *dockerImageBuilder) BuildFromCommand(sourceDirectory string, commandBuild *definitionv1.ContainerBuildCommand) error {
+	baseImage, err := getDockerImageFQNFromDockerImageBlock(&commandBuild.BaseImage)
+	if err != nil {
+		return err
+	}
+
+	// docker needs the build args to be a map from strings to pointer to strings,
+	// but commandBuild.Environment maps to strings, so create a buildArgs map
+	buildArgs := make(map[string]*string, len(commandBuild.Environment))
+	for k, v := range commandBuild.Environment {
+		v := v
+		buildArgs[k] = &v
+	}
+
+	// builds of the same repository on the same base image share layers
+	cacheImageFQN, cache, err := b.dockerCacheImageFQN(commandBuild.Source.GitRepository.URL, baseImage)
+	if err != nil {
+		return newErrorInternal("could not get docker layer cache image: " + err.Error())
+	}
+
+	err = b.buildDockerImage(sourceDirectory, baseImage, commandBuild.Command, buildArgs, cacheImageFQN, cache)
+	if err != nil {
+		return err
+	}
+
+	// the daemon pulled the base image while building
+	return b.recordDockerBaseImage(baseImage)
+}
+
 func (b *Builder) buildDockerImage(
 	sourceDirectory,
 	baseImage string,
 	dockerfileCommand []string,
-	buildArgs map[string]*string) error {
+	buildArgs map[string]*string,
+	cacheImageFQN string,
+	cache bool) error {
+	var cacheFrom []string
+	if cache && b.importDockerLayerCache(cacheImageFQN) {
+		// The base image has to be included since once cache sources are
+		// specified they are the only images whose layers are reused.
+		cacheFrom = []string{cacheImageFQN, baseImage}
+	}
+
 	color.Blue("Building docker image...")
 
 	if b.StatusUpdater != nil {
@@ -80,7 +128,7 @@ func (b *Builder) buildDockerImage(
 	// Tag the image to be built with the desired FQN
 	dockerImageFQN := getDockerImageFQN(b.DockerOptions.Registry, b.DockerOptions.Repository, b.DockerOptions.Tag)
 
-	dockerClientBuildOptions := getImageBuildOptions(dockerImageFQN, buildArgs, nil)
+	dockerClientBuildOptions := getImageBuildOptions(dockerImageFQN, buildArgs, nil, cacheFrom)
 
 	response, err := b.DockerClient.ImageBuild(
 		context.Background(), buildContext, dockerClientBuildOptions)
@@ -109,6 +157,10 @@ func (b *Builder) buildDockerImage(
 	color.Green("✓ Success!")
 	fmt.Println()
 
+	if cache {
+		b.exportDockerLayerCache(cacheImageFQN)
+	}
+
 	// If the image is not to be pushed, there's no more to do
 	if !b.DockerOptions.Push {
 		return nil
@@ -128,13 +180,36 @@ func (b *Builder) buildDockerBuildContainer(dockerBuild *definitionv1.DockerBuil
 		return err
 	}
 
+	// builds of the same docker file and build context share layers
+	cacheImageFQN, cache, err := b.dockerCacheImageFQN(
+		locationCacheKey(dockerBuild.DockerFile.Location),
+		dockerBuild.DockerFile.Path,
+		locationCacheKey(dockerBuild.BuildContext.Location),
+		dockerBuild.BuildContext.Path,
+	)
+	if err != nil {
+		return newErrorInternal("could not get docker layer cache image: " + err.Error())
+	}
+
+	// build args without values are taken from the builder's environment,
+	// which is not part of the build's definition
+	buildArgs := make(map[string]string)
+	for k, v := range dockerBuild.BuildArgs {
+		if v != nil {
+			buildArgs[k] = *v
+		}
+	}
+	b.recordBuildArgs(buildArgs)
+
 	return b.buildDockerBuild(
 		dockerFileDirectory,
 		dockerBuild.DockerFile.Path,
 		buildContextDirectory,
 		dockerBuild.BuildContext.Path,
 		dockerBuild.BuildArgs,
-		dockerBuild.Options)
+		dockerBuild.Options,
+		cacheImageFQN,
+		cache)
 }
 
 func (b *Builder) buildDockerBuild(
@@ -143,7 +218,16 @@ func (b *Builder) buildDockerBuild(
 	buildContextDirectory,
 	buildContextPath string,
 	buildArgs map[string]*string,
-	buildOptions *definitionv1.DockerBuildOptions) error {
+	buildOptions *definitionv1.DockerBuildOptions,
+	cacheImageFQN string,
+	cache bool) error {
+	// there's no point importing the cache if the build won't use it
+	var cacheFrom []string
+	noCache := buildOptions != nil && buildOptions.NoCache
+	if cache && !noCache && b.importDockerLayerCache(cacheImageFQN) {
+		cacheFrom = []string{cacheImageFQN}
+	}
+
 	color.Blue("Building docker build...")
 
 	if b.StatusUpdater != nil {
@@ -197,7 +281,7 @@ func (b *Builder) buildDockerBuild(
 	// Tag the image to be built with the desired FQN
 	dockerImageFQN := getDockerImageFQN(b.DockerOptions.Registry, b.DockerOptions.Repository, b.DockerOptions.Tag)
 
-	dockerClientBuildOptions := getImageBuildOptions(dockerImageFQN, buildArgs, buildOptions)
+	dockerClientBuildOptions := getImageBuildOptions(dockerImageFQN, buildArgs, buildOptions, cacheFrom)
 
 	response, err := b.DockerClient.ImageBuild(
 		context.Background(), buildContext, dockerClientBuildOptions)
@@ -224,6 +308,10 @@ func (b *Builder) buildDockerBuild(
 	color.Green("✓ Success!")
 	fmt.Println()
 
+	if cache {
+		b.exportDockerLayerCache(cacheImageFQN)
+	}
+
 	// If the image is not to be pushed, there's no more to do
 	if !b.DockerOptions.Push {
 		return nil
@@ -253,11 +341,14 @@ func (b *Builder) getDockerfileContents(
 
 	command := fmt.Sprintf("RUN %v", strings.Join(dockerfileCommand, " "))
 
+	// The build args are declared after the directory is created so that
+	// the layers shared by every build on the base image can be reused
+	// regardless of the build args.
 	dockerfileContents := fmt.Sprintf(`FROM %v
-%v
+
 RUN mkdir -p /usr/src/app
 WORKDIR /usr/src/app
-
+%v
 COPY %v /usr/src/app
 
 %v`,
@@ -275,6 +366,9 @@ func getDockerImageFQNFromDockerImageBlock(image *definitionv1.DockerImage) (str
 		return "", newErrorInternal("cannot get docker image FQN from nil image")
 	}
 
+	if image.Digest != "" {
+		return getDockerImageFQNWithDigest(image.Registry, image.Repository, image.Digest), nil
+	}
 	return getDockerImageFQN(image.Registry, image.Repository, image.Tag), nil
 }
 
@@ -341,29 +435,61 @@ func (b *Builder) pushDockerImage() error {
 
 	// Assumes the image has already been built and tagged.
 	dockerImageFQN := getDockerImageFQN(b.DockerOptions.Registry, b.DockerOptions.Repository, b.DockerOptions.Tag)
+	if err := b.pushImage(dockerImageFQN); err != nil {
+		return err
+	}
 
-	// Include creds if they were passed in
-	pushOptions := dockertypes.ImagePushOptions{}
-	if b.DockerOptions.RegistryAuthProvider != nil {
-		user, pass, err := b.DockerOptions.RegistryAuthProvider.GetLoginCredentials(b.DockerOptions.Registry)
-		if err != nil {
-			return newErrorInternal("failed to retrieve registry auth token: " + err.Error())
-		}
+	digest, err := b.dockerImageDigest(dockerImageFQN)
+	if err != nil {
+		return newErrorInternal("could not get pushed docker image digest: " + err.Error())
+	}
+	b.provenance.DockerImageDigest = digest
 
-		// A little help here from https://github.com/docker/cli/blob/042575aac918c90e9838c67c9ac9e2ff2810c326/cli/command/image/trust.go#L168-L180
-		// and https://github.com/docker/cli/blob/74af31be7f2d956a021f097af894ed9adf89272f/cli/command/registry.go#L40-L47
-		authConfig := dockertypes.AuthConfig{
-			Username:      user,
-			Password:      pass,
-			ServerAddress: "https://" + b.DockerOptions.Registry,
-		}
+	color.Green("✓ Success!")
+	fmt.Println()
 
-		buf, err := json.Marshal(authConfig)
-		if err != nil {
-			return newErrorInternal("marshalling auth config to JSON failed: " + err.Error())
-		}
+	b.recordPushedSBOM()
+	return nil
+}
+
+// recordDockerBaseImage records the image pulled by the docker daemon as the build's base image.
+func (b *Builder) recordDockerBaseImage(dockerImageFQN string) error {
+	digest, err := b.dockerImageDigest(dockerImageFQN)
+	if err != nil {
+		return newErrorInternal("could not get base docker image digest: " + err.Error())
+	}
+
+	b.recordBaseImage(dockerImageFQN, digest)
+	return nil
+}
+
+// recordPushedSBOM records the SBOM of the pushed image by reading its layers from the
+// registry, since the docker daemon does not expose the image's filesystem.
+func (b *Builder) recordPushedSBOM() {
+	reference := b.provenance.DockerImageDigest
+	if reference == "" {
+		reference = b.DockerOptions.Tag
+	}
+
+	client := registry.NewClient(b.DockerOptions.Registry, b.DockerOptions.RegistryAuthProvider)
+	image, err := client.Pull(b.DockerOptions.Repository, reference)
+	if err != nil {
+		color.Yellow("Could not generate SBOM: %v", err)
+		return
+	}
+
+	b.recordSBOM(image)
+}
+
+func (b *Builder) pushImage(dockerImageFQN string) error {
+	// Include creds if they were passed in
+	auth, err := b.registryAuth()
+	if err != nil {
+		return err
+	}
 
-		pushOptions.RegistryAuth = base64.URLEncoding.EncodeToString(buf)
+	pushOptions := dockertypes.ImagePushOptions{
+		RegistryAuth: auth,
 	}
 
 	responseBody, err := b.DockerClient.ImagePush(context.Background(), dockerImageFQN, pushOptions)
@@ -385,12 +511,37 @@ func (b *Builder) pushDockerImage() error {
 		return newErrorInternal("docker image push stream failed: " + err.Error())
 	}
 
-	color.Green("✓ Success!")
-	fmt.Println()
-
 	return nil
 }
 
+// registryAuth returns the encoded credentials for the registry, or an empty
+// string if no credentials are required.
+func (b *Builder) registryAuth() (string, error) {
+	if b.DockerOptions.RegistryAuthProvider == nil {
+		return "", nil
+	}
+
+	user, pass, err := b.DockerOptions.RegistryAuthProvider.GetLoginCredentials(b.DockerOptions.Registry)
+	if err != nil {
+		return "", newErrorInternal("failed to retrieve registry auth token: " + err.Error())
+	}
+
+	// A little help here from https://github.com/docker/cli/blob/042575aac918c90e9838c67c9ac9e2ff2810c326/cli/command/image/trust.go#L168-L180
+	// and https://github.com/docker/cli/blob/74af31be7f2d956a021f097af894ed9adf89272f/cli/command/registry.go#L40-L47
+	authConfig := dockertypes.AuthConfig{
+		Username:      user,
+		Password:      pass,
+		ServerAddress: "https://" + b.DockerOptions.Registry,
+	}
+
+	buf, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", newErrorInternal("marshalling auth config to JSON failed: " + err.Error())
+	}
+
+	return base64.URLEncoding.EncodeToString(buf), nil
+}
+
 func getDockerImageFQN(registry, repository, tag string) string {
 	if registry == "" {
 		return fmt.Sprintf("%v:%v", repository, tag)
@@ -398,12 +549,21 @@ func getDockerImageFQN(registry, repository, tag string) string {
 	return fmt.Sprintf("%v/%v:%v", registry, repository, tag)
 }
 
+func getDockerImageFQNWithDigest(registry, repository, digest string) string {
+	if registry == "" {
+		return fmt.Sprintf("%v@%v", repository, digest)
+	}
+	return fmt.Sprintf("%v/%v@%v", registry, repository, digest)
+}
+
 func getImageBuildOptions(
 	dockerImageFQN string,
 	buildArgs map[string]*string,
-	buildOptions *definitionv1.DockerBuildOptions) dockertypes.ImageBuildOptions {
+	buildOptions *definitionv1.DockerBuildOptions,
+	cacheFrom []string) dockertypes.ImageBuildOptions {
 	dockerClientBuildOptions := dockertypes.ImageBuildOptions{
-		Tags: []string{dockerImageFQN},
+		Tags:      []string{dockerImageFQN},
+		CacheFrom: cacheFrom,
 	}
 
 	if len(buildArgs) > 0 {
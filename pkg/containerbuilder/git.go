@@ -2,15 +2,23 @@ package containerbuilder
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/buildsource"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/docker"
 	"github.com/mlab-lattice/lattice/pkg/util/git"
 
 	"github.com/fatih/color"
 )
 
 func (b *Builder) retrieveGitRepository(repository *definitionv1.GitRepository) (string, error) {
+	if buildsource.IsURL(repository.URL) {
+		return b.retrieveBuildSource(repository)
+	}
+
 	color.Blue("Cloning git repository...")
 
 	if b.StatusUpdater != nil {
@@ -19,7 +27,14 @@ func (b *Builder) retrieveGitRepository(repository *definitionv1.GitRepository)
 		b.StatusUpdater.UpdateProgress(b.BuildID, b.SystemID, v1.ContainerBuildPhasePullingGitRepository)
 	}
 
-	gitResolver, err := git.NewResolver(b.WorkingDir+"/git", false)
+	// If there is a build cache, check out the repository in its mirror
+	// so that only new objects have to be fetched.
+	gitDirectory := b.WorkingDir + "/git"
+	if b.gitCacheEnabled() {
+		gitDirectory = filepath.Join(b.CacheOptions.Directory, cacheGitDirectory)
+	}
+
+	gitResolver, err := git.NewResolver(gitDirectory, false)
 	if err != nil {
 		return "", newErrorInternal("failed to create git resolver: " + err.Error())
 	}
@@ -36,12 +51,95 @@ func (b *Builder) retrieveGitRepository(repository *definitionv1.GitRepository)
 		Version: repository.Version,
 	}
 
+	repositoryPath := gitResolver.RepositoryPath(repository.URL)
+	if b.gitCacheEnabled() {
+		unlock, err := lockDirectory(repositoryPath)
+		if err != nil {
+			return "", newErrorInternal("failed to lock git repository cache: " + err.Error())
+		}
+		defer unlock()
+
+		_, err = os.Stat(repositoryPath)
+		recordCacheResult(&b.cacheInfo.GitRepository, err == nil)
+	}
+
 	if err := gitResolver.Checkout(ctx, ref); err != nil {
 		return "", newErrorUser("git repository checkout failed: " + err.Error())
 	}
 
+	commit, err := gitResolver.HeadCommit(ctx)
+	if err != nil {
+		return "", newErrorInternal("failed to get checked out git commit: " + err.Error())
+	}
+	b.recordGitSource(repository.URL, commit)
+
+	if b.gitCacheEnabled() {
+		// Copy the checkout out of the mirror so that the build does not modify
+		// the mirror, and other builds can check out other commits.
+		rel, err := filepath.Rel(gitDirectory, repositoryPath)
+		if err != nil {
+			return "", newErrorInternal("failed to get relative git repository path: " + err.Error())
+		}
+
+		checkoutPath := filepath.Join(b.WorkingDir, "git", rel)
+		if err := copyCheckout(repositoryPath, checkoutPath); err != nil {
+			return "", newErrorInternal("failed to copy git repository from cache: " + err.Error())
+		}
+		repositoryPath = checkoutPath
+	}
+
 	color.Green("✓ Success!")
 	fmt.Println()
 
-	return gitResolver.RepositoryPath(repository.URL), nil
+	return repositoryPath, nil
+}
+
+// retrieveBuildSource extracts a local snapshot uploaded as a build source.
+// Build sources are stored in the registry that images are pushed to, so the
+// registry's credentials are used to pull it.
+func (b *Builder) retrieveBuildSource(repository *definitionv1.GitRepository) (string, error) {
+	color.Blue("Pulling build source...")
+
+	if b.StatusUpdater != nil {
+		// For now ignore status update errors, don't need to fail a build because the status could
+		// not be updated.
+		b.StatusUpdater.UpdateProgress(b.BuildID, b.SystemID, v1.ContainerBuildPhasePullingGitRepository)
+	}
+
+	credentials, err := b.buildSourceCredentials(repository.URL)
+	if err != nil {
+		return "", newErrorUser("invalid build source: " + err.Error())
+	}
+
+	sourcePath := filepath.Join(b.WorkingDir, "build-source")
+	if err := buildsource.Pull(credentials, repository.URL, sourcePath); err != nil {
+		return "", newErrorUser("build source pull failed: " + err.Error())
+	}
+
+	commit := ""
+	if repository.Commit != nil {
+		commit = *repository.Commit
+	}
+	b.recordGitSource(repository.URL, commit)
+
+	color.Green("✓ Success!")
+	fmt.Println()
+
+	return sourcePath, nil
+}
+
+// buildSourceCredentials returns the credentials to pull the build source with
+// the URL with. The registry's credentials are only returned if the build source
+// is stored in the registry, so that they are not sent to other hosts.
+func (b *Builder) buildSourceCredentials(url string) (docker.RegistryLoginProvider, error) {
+	registryHost, _, _, err := buildsource.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.DockerOptions == nil || registryHost != b.DockerOptions.Registry {
+		return nil, nil
+	}
+
+	return b.DockerOptions.RegistryAuthProvider, nil
 }
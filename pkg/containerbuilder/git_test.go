@@ -0,0 +1,43 @@
+package containerbuilder
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/buildsource"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRegistryLoginProvider struct{}
+
+func (p *testRegistryLoginProvider) GetLoginCredentials(registry string) (string, string, error) {
+	return "user", "password", nil
+}
+
+func TestBuildSourceCredentials(t *testing.T) {
+	provider := &testRegistryLoginProvider{}
+	b := &Builder{
+		DockerOptions: &DockerOptions{
+			Registry:             "registry.example.com",
+			RegistryAuthProvider: provider,
+		},
+	}
+
+	credentials, err := b.buildSourceCredentials(buildsource.URL("registry.example.com", "sources", "sha256:abc"))
+	require.NoError(t, err)
+	require.Equal(t, provider, credentials)
+
+	// the registry's credentials must not be sent to other hosts
+	for _, host := range []string{"attacker.example.com", "registry.example.com.attacker.com", "registry.example.com:5000"} {
+		credentials, err := b.buildSourceCredentials(buildsource.URL(host, "sources", "sha256:abc"))
+		require.NoError(t, err, host)
+		require.Nil(t, credentials, host)
+	}
+
+	_, err = b.buildSourceCredentials("lattice-build-source://registry.example.com")
+	require.Error(t, err)
+
+	credentials, err = (&Builder{}).buildSourceCredentials(buildsource.URL("registry.example.com", "sources", "sha256:abc"))
+	require.NoError(t, err)
+	require.Nil(t, credentials)
+}
@@ -0,0 +1,41 @@
+package containerbuilder
+
+import (
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+)
+
+type ImageBuilderType string
+
+const (
+	// ImageBuilderDocker builds images with the docker daemon.
+	ImageBuilderDocker ImageBuilderType = "docker"
+
+	// ImageBuilderDaemonless assembles images in the builder and pushes them
+	// directly to the registry, without requiring a docker daemon.
+	ImageBuilderDaemonless ImageBuilderType = "daemonless"
+)
+
+// ImageBuilder produces the image for DockerImage and command builds, tagging it
+// with the build's docker image FQN and pushing it if images are pushed.
+// DockerBuild builds always use the docker daemon.
+type ImageBuilder interface {
+	// BuildFromImage uses an existing image as the build's image.
+	BuildFromImage(image *definitionv1.DockerImage) error
+
+	// BuildFromCommand copies the source directory into the base image and runs
+	// the build's command in it.
+	BuildFromCommand(sourceDirectory string, commandBuild *definitionv1.ContainerBuildCommand) error
+}
+
+func newImageBuilder(b *Builder, imageBuilderType ImageBuilderType) (ImageBuilder, error) {
+	switch imageBuilderType {
+	case "", ImageBuilderDocker:
+		return &dockerImageBuilder{b}, nil
+
+	case ImageBuilderDaemonless:
+		return newDaemonlessImageBuilder(b)
+
+	default:
+		return nil, newErrorInternal("unsupported image builder: " + string(imageBuilderType))
+	}
+}
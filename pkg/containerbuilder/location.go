@@ -12,3 +12,13 @@ func (b *Builder) retrieveLocation(location *v1.Location) (string, error) {
 
 	return "", newErrorUser("docker build did not include a location")
 }
+
+// locationCacheKey returns the part of the location that identifies it across builds.
+// Only the repository is used, so that builds of different commits share a cache.
+func locationCacheKey(location *v1.Location) string {
+	if location == nil || location.GitRepository == nil {
+		return ""
+	}
+
+	return location.GitRepository.URL
+}
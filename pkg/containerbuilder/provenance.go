@@ -0,0 +1,88 @@
+package containerbuilder
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+	"github.com/mlab-lattice/lattice/pkg/util/sbom"
+
+	"github.com/fatih/color"
+)
+
+func (b *Builder) recordGitSource(url, commit string) {
+	source := v1.ContainerBuildProvenanceSource{
+		GitRepositoryURL: url,
+		Commit:           commit,
+	}
+	b.provenance.Sources = append(b.provenance.Sources, source)
+}
+
+func (b *Builder) recordBaseImage(dockerImageFQN, digest string) {
+	b.provenance.BaseImage = &v1.ContainerBuildProvenanceImage{
+		DockerImageFQN: dockerImageFQN,
+		Digest:         digest,
+	}
+}
+
+func (b *Builder) recordBuildArgs(buildArgs map[string]string) {
+	if len(buildArgs) == 0 {
+		return
+	}
+
+	b.provenance.BuildArgs = make(map[string]string, len(buildArgs))
+	for k, v := range buildArgs {
+		b.provenance.BuildArgs[k] = v
+	}
+}
+
+// recordSBOM records the OS packages installed in the image. Failing to read them
+// does not fail the build, the provenance just will not include an SBOM.
+func (b *Builder) recordSBOM(image *registry.Image) {
+	files, err := image.Files(sbom.Paths...)
+	if err != nil {
+		color.Yellow("Could not generate SBOM: %v", err)
+		return
+	}
+
+	b.provenance.SBOM = sbom.Generate(files)
+}
+
+func (b *Builder) reportProvenance() {
+	if b.StatusUpdater == nil {
+		return
+	}
+
+	// For now ignore status update errors, don't need to fail a build because the status could
+	// not be updated.
+	b.StatusUpdater.UpdateProvenance(b.BuildID, b.SystemID, &b.provenance)
+}
+
+// dockerImageDigest returns the digest the docker daemon recorded for the image
+// when it was pulled from or pushed to its repository, or an empty string if it has none.
+func (b *Builder) dockerImageDigest(dockerImageFQN string) (string, error) {
+	image, _, err := b.DockerClient.ImageInspectWithRaw(context.Background(), dockerImageFQN)
+	if err != nil {
+		return "", err
+	}
+
+	name := dockerImageName(dockerImageFQN)
+	for _, repoDigest := range image.RepoDigests {
+		parts := strings.SplitN(repoDigest, "@", 2)
+		if len(parts) == 2 && parts[0] == name {
+			return parts[1], nil
+		}
+	}
+
+	return "", nil
+}
+
+// dockerImageName strips the tag from the docker image FQN.
+func dockerImageName(dockerImageFQN string) string {
+	i := strings.LastIndex(dockerImageFQN, ":")
+	if i == -1 || strings.Contains(dockerImageFQN[i:], "/") {
+		return dockerImageFQN
+	}
+	return dockerImageFQN[:i]
+}
@@ -7,4 +7,6 @@ import (
 type StatusUpdater interface {
 	UpdateProgress(v1.ContainerBuildID, v1.SystemID, v1.ContainerBuildPhase) error
 	UpdateError(buildID v1.ContainerBuildID, systemID v1.SystemID, internal bool, err error) error
+	UpdateCache(buildID v1.ContainerBuildID, systemID v1.SystemID, cache *v1.ContainerBuildCacheInfo) error
+	UpdateProvenance(buildID v1.ContainerBuildID, systemID v1.SystemID, provenance *v1.ContainerBuildProvenance) error
 }
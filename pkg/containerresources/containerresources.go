@@ -0,0 +1,366 @@
+package containerresources
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+)
+
+// List is an amount of each resource, parsed from a ContainerResourceList.
+type List struct {
+	MilliCPU            int64
+	MemoryMiB           int64
+	GPU                 int32
+	EphemeralStorageMiB int64
+}
+
+type resource struct {
+	name     string
+	quantity func(definitionv1.ContainerResourceList) string
+	value    func(List) int64
+}
+
+var resources = []resource{
+	{
+		name:     "cpu",
+		quantity: func(l definitionv1.ContainerResourceList) string { return l.CPU },
+		value:    func(l List) int64 { return l.MilliCPU },
+	},
+	{
+		name:     "memory",
+		quantity: func(l definitionv1.ContainerResourceList) string { return l.Memory },
+		value:    func(l List) int64 { return l.MemoryMiB },
+	},
+	{
+		name: "gpu",
+		quantity: func(l definitionv1.ContainerResourceList) string {
+			if l.GPU == 0 {
+				return ""
+			}
+			return strconv.Itoa(int(l.GPU))
+		},
+		value: func(l List) int64 { return int64(l.GPU) },
+	},
+	{
+		name:     "ephemeral storage",
+		quantity: func(l definitionv1.ContainerResourceList) string { return l.EphemeralStorage },
+		value:    func(l List) int64 { return l.EphemeralStorageMiB },
+	},
+}
+
+// Requests returns the resources requested by the container, including the
+// CPU and memory requested with the shorthand fields.
+func Requests(r *definitionv1.ContainerResources) definitionv1.ContainerResourceList {
+	var requests definitionv1.ContainerResourceList
+	if r == nil {
+		return requests
+	}
+
+	if r.Requests != nil {
+		requests = *r.Requests
+	}
+
+	if r.CPU != "" {
+		requests.CPU = r.CPU
+	}
+
+	if r.Memory != "" {
+		requests.Memory = r.Memory
+	}
+
+	return requests
+}
+
+// Limits returns the limits of the container's resources.
+func Limits(r *definitionv1.ContainerResources) definitionv1.ContainerResourceList {
+	if r == nil || r.Limits == nil {
+		return definitionv1.ContainerResourceList{}
+	}
+
+	return *r.Limits
+}
+
+// Parse parses the quantities in the list. Resources that aren't set are zero.
+func Parse(list definitionv1.ContainerResourceList) (List, error) {
+	var parsed List
+	var err error
+	if list.CPU != "" {
+		parsed.MilliCPU, err = ParseMilliCPU(list.CPU)
+		if err != nil {
+			return List{}, err
+		}
+	}
+
+	if list.Memory != "" {
+		parsed.MemoryMiB, err = ParseMemoryMiB(list.Memory)
+		if err != nil {
+			return List{}, err
+		}
+	}
+
+	if list.GPU < 0 {
+		return List{}, fmt.Errorf("invalid gpu %v: must not be negative", list.GPU)
+	}
+	parsed.GPU = list.GPU
+
+	if list.EphemeralStorage != "" {
+		parsed.EphemeralStorageMiB, err = ParseEphemeralStorageMiB(list.EphemeralStorage)
+		if err != nil {
+			return List{}, err
+		}
+	}
+
+	return parsed, nil
+}
+
+// Validate checks that the container's resources are valid quantities, that
+// it does not request more of a resource than its limit, and that neither its
+// requests nor its limits are more than max, if it is not nil.
+func Validate(r *definitionv1.ContainerResources, max *v1.ContainerResourceList) error {
+	if r != nil && r.Requests != nil {
+		if r.CPU != "" && r.Requests.CPU != "" {
+			return fmt.Errorf("cpu and requests.cpu cannot both be set")
+		}
+
+		if r.Memory != "" && r.Requests.Memory != "" {
+			return fmt.Errorf("memory and requests.memory cannot both be set")
+		}
+	}
+
+	requestList := Requests(r)
+	requests, err := Parse(requestList)
+	if err != nil {
+		return fmt.Errorf("requests: %v", err)
+	}
+
+	limitList := Limits(r)
+	limits, err := Parse(limitList)
+	if err != nil {
+		return fmt.Errorf("limits: %v", err)
+	}
+
+	var maxList definitionv1.ContainerResourceList
+	if max != nil {
+		maxList = definitionList(max)
+	}
+
+	maxes, err := Parse(maxList)
+	if err != nil {
+		return fmt.Errorf("max: %v", err)
+	}
+
+	// GPUs cannot be overcommitted, so a container must be limited to the
+	// GPUs it requests
+	if requests.GPU != 0 && limits.GPU != 0 && requests.GPU != limits.GPU {
+		return fmt.Errorf("gpu request %v does not equal its limit %v", requests.GPU, limits.GPU)
+	}
+
+	for _, resource := range resources {
+		request := resource.quantity(requestList)
+		limit := resource.quantity(limitList)
+		if request != "" && limit != "" && resource.value(requests) > resource.value(limits) {
+			return fmt.Errorf("%v request %v is more than its limit %v", resource.name, request, limit)
+		}
+
+		maximum := resource.quantity(maxList)
+		if maximum == "" {
+			continue
+		}
+
+		if request != "" && resource.value(requests) > resource.value(maxes) {
+			return fmt.Errorf("%v request %v is more than the maximum of %v", resource.name, request, maximum)
+		}
+
+		if limit != "" && resource.value(limits) > resource.value(maxes) {
+			return fmt.Errorf("%v limit %v is more than the maximum of %v", resource.name, limit, maximum)
+		}
+	}
+
+	return nil
+}
+
+// ValidateSystem checks that the system's default and maximum container
+// resources are valid quantities, and that the defaults are valid resources
+// for a container under the maximum.
+func ValidateSystem(systemResources *v1.SystemContainerResources) error {
+	if systemResources == nil {
+		return nil
+	}
+
+	defaults := &definitionv1.ContainerResources{}
+	if systemResources.DefaultRequests != nil {
+		requests := definitionList(systemResources.DefaultRequests)
+		defaults.Requests = &requests
+	}
+
+	if systemResources.DefaultLimits != nil {
+		limits := definitionList(systemResources.DefaultLimits)
+		defaults.Limits = &limits
+	}
+
+	if err := Validate(defaults, systemResources.Max); err != nil {
+		return fmt.Errorf("defaults: %v", err)
+	}
+
+	return nil
+}
+
+// ApplyDefaults sets each resource the container does not request or limit
+// to the system's default. Resources that the container limits but does not
+// request are requested at their limit rather than the default request.
+func ApplyDefaults(container *definitionv1.Container, systemResources *v1.SystemContainerResources) {
+	if systemResources == nil {
+		return
+	}
+
+	limits := Limits(container.Resources)
+	defaultRequests := missing(Requests(container.Resources), systemResources.DefaultRequests, limits)
+	defaultLimits := missing(limits, systemResources.DefaultLimits, definitionv1.ContainerResourceList{})
+
+	empty := definitionv1.ContainerResourceList{}
+	if defaultRequests == empty && defaultLimits == empty {
+		return
+	}
+
+	if container.Resources == nil {
+		container.Resources = &definitionv1.ContainerResources{}
+	}
+
+	container.Resources.Requests = merge(container.Resources.Requests, defaultRequests)
+	container.Resources.Limits = merge(container.Resources.Limits, defaultLimits)
+}
+
+// Enforce applies the system's default container resources to the containers
+// of the definition's workloads, then validates their resources against the
+// system's maximum.
+func Enforce(t *resolver.ResolutionTree, systemResources *v1.SystemContainerResources) error {
+	var max *v1.ContainerResourceList
+	if systemResources != nil {
+		max = systemResources.Max
+	}
+
+	var err error
+	t.V1().Workloads(func(path tree.Path, workload definitionv1.Workload, info *resolver.ResolutionInfo) tree.WalkContinuation {
+		main, sidecars := workloadContainers(workload)
+		if main == nil {
+			return tree.ContinueWalk
+		}
+
+		ApplyDefaults(main, systemResources)
+		if err = Validate(main.Resources, max); err != nil {
+			err = fmt.Errorf("%v: %v", path.String(), err)
+			return tree.HaltWalk
+		}
+
+		for name, sidecar := range sidecars {
+			ApplyDefaults(&sidecar, systemResources)
+			if err = Validate(sidecar.Resources, max); err != nil {
+				err = fmt.Errorf("%v: sidecar %v: %v", path.String(), name, err)
+				return tree.HaltWalk
+			}
+
+			sidecars[name] = sidecar
+		}
+
+		return tree.ContinueWalk
+	})
+
+	return err
+}
+
+// workloadContainers returns the workload's main container and sidecars so that
+// they can be modified in place.
+func workloadContainers(workload definitionv1.Workload) (*definitionv1.Container, map[string]definitionv1.Container) {
+	switch w := workload.(type) {
+	case *definitionv1.Service:
+		return &w.Container, w.Sidecars
+	case *definitionv1.Job:
+		return &w.Container, w.Sidecars
+	default:
+		return nil, nil
+	}
+}
+
+// missing returns the defaults of the resources that are not set in list. If
+// fallback sets a resource, it is used instead of the default.
+func missing(
+	list definitionv1.ContainerResourceList,
+	defaults *v1.ContainerResourceList,
+	fallback definitionv1.ContainerResourceList,
+) definitionv1.ContainerResourceList {
+	var missing definitionv1.ContainerResourceList
+	if defaults == nil {
+		return missing
+	}
+
+	if list.CPU == "" {
+		missing.CPU = defaults.CPU
+		if fallback.CPU != "" {
+			missing.CPU = fallback.CPU
+		}
+	}
+
+	if list.Memory == "" {
+		missing.Memory = defaults.Memory
+		if fallback.Memory != "" {
+			missing.Memory = fallback.Memory
+		}
+	}
+
+	if list.GPU == 0 {
+		missing.GPU = defaults.GPU
+		if fallback.GPU != 0 {
+			missing.GPU = fallback.GPU
+		}
+	}
+
+	if list.EphemeralStorage == "" {
+		missing.EphemeralStorage = defaults.EphemeralStorage
+		if fallback.EphemeralStorage != "" {
+			missing.EphemeralStorage = fallback.EphemeralStorage
+		}
+	}
+
+	return missing
+}
+
+// merge returns list with the resources it does not set taken from other.
+func merge(list *definitionv1.ContainerResourceList, other definitionv1.ContainerResourceList) *definitionv1.ContainerResourceList {
+	if other == (definitionv1.ContainerResourceList{}) {
+		return list
+	}
+
+	merged := other
+	if list != nil {
+		if list.CPU != "" {
+			merged.CPU = list.CPU
+		}
+
+		if list.Memory != "" {
+			merged.Memory = list.Memory
+		}
+
+		if list.GPU != 0 {
+			merged.GPU = list.GPU
+		}
+
+		if list.EphemeralStorage != "" {
+			merged.EphemeralStorage = list.EphemeralStorage
+		}
+	}
+
+	return &merged
+}
+
+func definitionList(list *v1.ContainerResourceList) definitionv1.ContainerResourceList {
+	return definitionv1.ContainerResourceList{
+		CPU:              list.CPU,
+		Memory:           list.Memory,
+		GPU:              list.GPU,
+		EphemeralStorage: list.EphemeralStorage,
+	}
+}
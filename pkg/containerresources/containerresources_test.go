@@ -0,0 +1,96 @@
+package containerresources
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuantities(t *testing.T) {
+	cpus := map[string]int64{
+		"500m": 500,
+		"2":    2000,
+		"0.25": 250,
+		"1e1":  10000,
+	}
+	for quantity, expected := range cpus {
+		cpu, err := ParseMilliCPU(quantity)
+		require.NoError(t, err, quantity)
+		require.Equal(t, expected, cpu, quantity)
+	}
+
+	memories := map[string]int64{
+		"512Mi":   512,
+		"1Gi":     1024,
+		"1G":      954,
+		"1048576": 1,
+		"1k":      1,
+	}
+	for quantity, expected := range memories {
+		memory, err := ParseMemoryMiB(quantity)
+		require.NoError(t, err, quantity)
+		require.Equal(t, expected, memory, quantity)
+	}
+
+	for _, quantity := range []string{"", "abc", "1Xi", "-1"} {
+		_, err := ParseMilliCPU(quantity)
+		require.Error(t, err, quantity)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	systemResources := &v1.SystemContainerResources{
+		DefaultRequests: &v1.ContainerResourceList{CPU: "100m", Memory: "128Mi"},
+		DefaultLimits:   &v1.ContainerResourceList{CPU: "1", Memory: "1Gi"},
+	}
+
+	container := &definitionv1.Container{
+		Resources: &definitionv1.ContainerResources{
+			CPU:    "250m",
+			Limits: &definitionv1.ContainerResourceList{Memory: "512Mi"},
+		},
+	}
+
+	ApplyDefaults(container, systemResources)
+	require.Equal(
+		t,
+		&definitionv1.ContainerResources{
+			CPU:      "250m",
+			Requests: &definitionv1.ContainerResourceList{Memory: "512Mi"},
+			Limits:   &definitionv1.ContainerResourceList{CPU: "1", Memory: "512Mi"},
+		},
+		container.Resources,
+	)
+	require.NoError(t, Validate(container.Resources, nil))
+}
+
+func TestValidate(t *testing.T) {
+	max := &v1.ContainerResourceList{CPU: "2", Memory: "4Gi", GPU: 1}
+
+	valid := []*definitionv1.ContainerResources{
+		nil,
+		{CPU: "500m", Memory: "1Gi"},
+		{
+			Requests: &definitionv1.ContainerResourceList{CPU: "1", GPU: 1},
+			Limits:   &definitionv1.ContainerResourceList{CPU: "2", GPU: 1, EphemeralStorage: "10Gi"},
+		},
+	}
+	for _, resources := range valid {
+		require.NoError(t, Validate(resources, max))
+	}
+
+	invalid := []*definitionv1.ContainerResources{
+		{CPU: "lots"},
+		{CPU: "1", Requests: &definitionv1.ContainerResourceList{CPU: "1"}},
+		{CPU: "4"},
+		{Limits: &definitionv1.ContainerResourceList{Memory: "8Gi"}},
+		{CPU: "1", Limits: &definitionv1.ContainerResourceList{CPU: "500m"}},
+		{Requests: &definitionv1.ContainerResourceList{GPU: 1}, Limits: &definitionv1.ContainerResourceList{GPU: 2}},
+	}
+	for _, resources := range invalid {
+		require.Error(t, Validate(resources, max))
+	}
+}
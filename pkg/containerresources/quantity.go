@@ -0,0 +1,109 @@
+package containerresources
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const mebibyte = 1 << 20
+
+var (
+	// binarySuffixes and decimalSuffixes are the multipliers of the suffixes
+	// that kubernetes quantities can have.
+	binarySuffixes = map[string]float64{
+		"Ki": 1 << 10,
+		"Mi": 1 << 20,
+		"Gi": 1 << 30,
+		"Ti": 1 << 40,
+		"Pi": 1 << 50,
+		"Ei": 1 << 60,
+	}
+	decimalSuffixes = map[string]float64{
+		"n": 1e-9,
+		"u": 1e-6,
+		"m": 1e-3,
+		"":  1,
+		"k": 1e3,
+		"M": 1e6,
+		"G": 1e9,
+		"T": 1e12,
+		"P": 1e15,
+		"E": 1e18,
+	}
+)
+
+// ParseMilliCPU returns the number of thousandths of a core in the CPU quantity,
+// for example "500m" or "2", rounded up.
+func ParseMilliCPU(quantity string) (int64, error) {
+	value, err := parseQuantity(quantity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cpu %q: %v", quantity, err)
+	}
+
+	return int64(math.Ceil(value * 1000)), nil
+}
+
+// ParseMemoryMiB returns the number of mebibytes in the memory quantity, for
+// example "512Mi" or "1G", rounded up.
+func ParseMemoryMiB(quantity string) (int64, error) {
+	return parseMiB("memory", quantity)
+}
+
+// ParseEphemeralStorageMiB returns the number of mebibytes in the ephemeral
+// storage quantity, rounded up.
+func ParseEphemeralStorageMiB(quantity string) (int64, error) {
+	return parseMiB("ephemeral storage", quantity)
+}
+
+func parseMiB(resource, quantity string) (int64, error) {
+	value, err := parseQuantity(quantity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v %q: %v", resource, quantity, err)
+	}
+
+	return int64(math.Ceil(value / mebibyte)), nil
+}
+
+// parseQuantity parses a kubernetes resource quantity.
+func parseQuantity(quantity string) (float64, error) {
+	quantity = strings.TrimSpace(quantity)
+
+	// find where the number ends and the suffix starts
+	end := len(quantity)
+	for i, c := range quantity {
+		if (c < '0' || c > '9') && c != '.' && c != '+' && c != '-' {
+			end = i
+			break
+		}
+	}
+
+	number, suffix := quantity[:end], quantity[end:]
+
+	// an exponent is part of the number rather than a suffix
+	if len(suffix) > 1 && (suffix[0] == 'e' || suffix[0] == 'E') {
+		if _, err := strconv.Atoi(suffix[1:]); err == nil {
+			number, suffix = quantity, ""
+		}
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number")
+	}
+
+	if value < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+
+	if multiplier, ok := binarySuffixes[suffix]; ok {
+		return value * multiplier, nil
+	}
+
+	if multiplier, ok := decimalSuffixes[suffix]; ok {
+		return value * multiplier, nil
+	}
+
+	return 0, fmt.Errorf("invalid suffix %q", suffix)
+}
@@ -0,0 +1,346 @@
+package cost
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+)
+
+const (
+	// HoursPerMonth is the average number of hours in a month.
+	HoursPerMonth = 730
+
+	// DefaultCPUAllocation is the fraction of a node pool's cost allocated by
+	// the CPU requested by its services if the model does not specify one.
+	DefaultCPUAllocation = 0.5
+)
+
+// Model prices node pools by the instance types of their instances, and
+// allocates the cost of each node pool to the services running on it by the
+// CPU and memory they request.
+type Model struct {
+	// InstanceTypePrices are the hourly prices of each instance type.
+	InstanceTypePrices map[string]float64 `json:"instanceTypePrices"`
+
+	// CPUAllocation is the fraction of a node pool's cost that is allocated by
+	// the CPU requested by its services, and the rest is allocated by the memory
+	// they request.
+	CPUAllocation *float64 `json:"cpuAllocation,omitempty"`
+}
+
+// NodePool is the instances of a node pool.
+type NodePool struct {
+	ID   string
+	Path tree.PathSubcomponent
+
+	// Instances are the number of the node pool's instances of each instance type.
+	Instances map[string]int32
+
+	// InstanceHours are the hours the node pool's instances of each instance
+	// type ran for during the period being estimated.
+	InstanceHours map[string]float64
+}
+
+// Service is the instances of a service and the node pools they run on.
+type Service struct {
+	Path      tree.Path
+	NodePools []string
+	Instances int32
+
+	// InstanceHours are the hours the service's instances ran for on each of
+	// the node pools during the period being estimated.
+	InstanceHours map[string]float64
+
+	// MilliCPU and MemoryMiB are the CPU and memory requested by each of the
+	// service's instances.
+	MilliCPU  int64
+	MemoryMiB int64
+}
+
+// Estimate is the hourly cost of a system's services and node pools, and their
+// cost over the period of their instance-hours.
+type Estimate struct {
+	// Services are the hourly cost allocated to each service.
+	Services map[tree.Path]float64
+
+	// UnallocatedNodePools are the hourly cost of each node pool that is not
+	// running any services.
+	UnallocatedNodePools map[tree.PathSubcomponent]float64
+
+	// ServiceCosts and UnallocatedNodePoolCosts are the cost of each service and
+	// node pool that was not running any services over the period.
+	ServiceCosts             map[tree.Path]float64
+	UnallocatedNodePoolCosts map[tree.PathSubcomponent]float64
+
+	// UnpricedInstanceTypes are the instance types that the model does not
+	// have a price for, whose instances are not included in the estimate.
+	UnpricedInstanceTypes []string
+}
+
+// NewService returns the service at path with the definition, whose instances
+// run on the node pools and ran for the instance-hours on each of them.
+func NewService(
+	path tree.Path,
+	definition *definitionv1.Service,
+	instances int32,
+	nodePools []string,
+	instanceHours map[string]float64,
+) (Service, error) {
+	usage, err := quota.WorkloadUsage(definition)
+	if err != nil {
+		return Service{}, err
+	}
+
+	service := Service{
+		Path:          path,
+		NodePools:     nodePools,
+		Instances:     instances,
+		InstanceHours: instanceHours,
+		MilliCPU:      usage.MilliCPU,
+		MemoryMiB:     usage.MemoryMiB,
+	}
+	return service, nil
+}
+
+// Estimate returns the hourly cost of the node pools and their cost over the
+// period of their instance-hours, allocated to the services running on them.
+func (m *Model) Estimate(nodePools []NodePool, services []Service) *Estimate {
+	estimate := &Estimate{
+		Services:                 make(map[tree.Path]float64),
+		UnallocatedNodePools:     make(map[tree.PathSubcomponent]float64),
+		ServiceCosts:             make(map[tree.Path]float64),
+		UnallocatedNodePoolCosts: make(map[tree.PathSubcomponent]float64),
+	}
+
+	servicesByNodePool := make(map[string][]*Service)
+	servicesByNodePoolHours := make(map[string][]*Service)
+	for i := range services {
+		service := &services[i]
+		estimate.Services[service.Path] = 0
+		estimate.ServiceCosts[service.Path] = 0
+
+		for _, nodePool := range service.NodePools {
+			servicesByNodePool[nodePool] = append(servicesByNodePool[nodePool], service)
+		}
+
+		for nodePool, hours := range service.InstanceHours {
+			if hours > 0 {
+				servicesByNodePoolHours[nodePool] = append(servicesByNodePoolHours[nodePool], service)
+			}
+		}
+	}
+
+	unpriced := make(map[string]bool)
+	for _, nodePool := range nodePools {
+		var hourly float64
+		for instanceType, instances := range nodePool.Instances {
+			price, ok := m.InstanceTypePrices[instanceType]
+			if !ok {
+				unpriced[instanceType] = true
+				continue
+			}
+
+			hourly += price * float64(instances)
+		}
+
+		var cost float64
+		for instanceType, hours := range nodePool.InstanceHours {
+			price, ok := m.InstanceTypePrices[instanceType]
+			if !ok {
+				unpriced[instanceType] = true
+				continue
+			}
+
+			cost += price * hours
+		}
+
+		// the current cost is allocated by the services' current instances
+		running := servicesByNodePool[nodePool.ID]
+		if len(running) == 0 {
+			estimate.UnallocatedNodePools[nodePool.Path] += hourly
+		}
+
+		instances := func(service *Service) float64 {
+			return float64(service.Instances)
+		}
+		for path, share := range m.shares(running, instances) {
+			estimate.Services[path] += hourly * share
+		}
+
+		// and the cost over the period by the hours they ran on the node pool
+		ran := servicesByNodePoolHours[nodePool.ID]
+		if len(ran) == 0 {
+			estimate.UnallocatedNodePoolCosts[nodePool.Path] += cost
+		}
+
+		instanceHours := func(service *Service) float64 {
+			return service.InstanceHours[nodePool.ID]
+		}
+		for path, share := range m.shares(ran, instanceHours) {
+			estimate.ServiceCosts[path] += cost * share
+		}
+	}
+
+	for instanceType := range unpriced {
+		estimate.UnpricedInstanceTypes = append(estimate.UnpricedInstanceTypes, instanceType)
+	}
+	sort.Strings(estimate.UnpricedInstanceTypes)
+
+	return estimate
+}
+
+// HourlyCost returns the hourly cost of the services and unallocated node pools
+// under prefix.
+func (e *Estimate) HourlyCost(prefix tree.Path) float64 {
+	return sumUnder(prefix, e.Services, e.UnallocatedNodePools)
+}
+
+// Cost returns the cost of the services and unallocated node pools under prefix
+// over the period.
+func (e *Estimate) Cost(prefix tree.Path) float64 {
+	return sumUnder(prefix, e.ServiceCosts, e.UnallocatedNodePoolCosts)
+}
+
+// CostEstimate returns the estimate of the services and node pools under
+// prefix, including their cost over the period since since.
+func (e *Estimate) CostEstimate(prefix tree.Path, since time.Time) *v1.CostEstimate {
+	hourly := e.HourlyCost(prefix)
+	estimate := &v1.CostEstimate{
+		Path: prefix,
+
+		HourlyCost:           hourly,
+		ProjectedMonthlyCost: hourly * HoursPerMonth,
+
+		Since: *timeutil.New(since),
+		Cost:  e.Cost(prefix),
+
+		Services:  make(map[tree.Path]float64),
+		NodePools: make(map[tree.PathSubcomponent]float64),
+
+		UnpricedInstanceTypes: e.UnpricedInstanceTypes,
+	}
+
+	for path, cost := range e.Services {
+		if path.HasPrefix(prefix) {
+			estimate.Services[path] = cost
+		}
+	}
+
+	for path, cost := range e.UnallocatedNodePools {
+		if path.Path().HasPrefix(prefix) {
+			estimate.NodePools[path] = cost
+		}
+	}
+
+	return estimate
+}
+
+func sumUnder(
+	prefix tree.Path,
+	services map[tree.Path]float64,
+	nodePools map[tree.PathSubcomponent]float64,
+) float64 {
+	var sum float64
+	for path, cost := range services {
+		if path.HasPrefix(prefix) {
+			sum += cost
+		}
+	}
+
+	for path, cost := range nodePools {
+		if path.Path().HasPrefix(prefix) {
+			sum += cost
+		}
+	}
+
+	return sum
+}
+
+// DefinitionHourlyCost returns the hourly cost of the node pools of the
+// definition, including services' dedicated node pools, with the number of
+// instances they start with. Instance types without a price do not count
+// towards it.
+func (m *Model) DefinitionHourlyCost(t *resolver.ResolutionTree) float64 {
+	if t == nil {
+		return 0
+	}
+
+	var hourly float64
+	t.V1().NodePools(func(subcomponent tree.PathSubcomponent, nodePool *definitionv1.NodePool) tree.WalkContinuation {
+		hourly += m.InstanceTypePrices[nodePool.InstanceType] * float64(nodePool.NumInstances)
+		return tree.ContinueWalk
+	})
+
+	t.V1().Services(func(path tree.Path, service *definitionv1.Service, info *resolver.ResolutionInfo) tree.WalkContinuation {
+		if service.NodePool != nil && service.NodePool.NodePool != nil {
+			nodePool := service.NodePool.NodePool
+			hourly += m.InstanceTypePrices[nodePool.InstanceType] * float64(nodePool.NumInstances)
+		}
+		return tree.ContinueWalk
+	})
+
+	return hourly
+}
+
+// MonthlyCostDelta returns the change in the monthly cost of the system's
+// definition from replacing it at path with the build's definition.
+func (m *Model) MonthlyCostDelta(
+	definition *resolver.ResolutionTree,
+	path tree.Path,
+	buildDefinition *resolver.ResolutionTree,
+) float64 {
+	deployed := resolver.NewResolutionTree()
+	if definition != nil {
+		deployed = definition.DeepCopy()
+	}
+	deployed.ReplacePrefix(path, buildDefinition)
+
+	return (m.DefinitionHourlyCost(deployed) - m.DefinitionHourlyCost(definition)) * HoursPerMonth
+}
+
+// shares returns the fraction of a node pool's cost allocated to each of the
+// services running on it, weighing each service's requests by its instances.
+func (m *Model) shares(services []*Service, serviceInstances func(*Service) float64) map[tree.Path]float64 {
+	var milliCPU, memoryMiB, instances float64
+	for _, service := range services {
+		milliCPU += float64(service.MilliCPU) * serviceInstances(service)
+		memoryMiB += float64(service.MemoryMiB) * serviceInstances(service)
+		instances += serviceInstances(service)
+	}
+
+	cpuAllocation := float64(DefaultCPUAllocation)
+	if m.CPUAllocation != nil {
+		cpuAllocation = *m.CPUAllocation
+	}
+
+	shares := make(map[tree.Path]float64)
+	for _, service := range services {
+		n := serviceInstances(service)
+		cpuShare := share(float64(service.MilliCPU)*n, milliCPU, n, instances, len(services))
+		memoryShare := share(float64(service.MemoryMiB)*n, memoryMiB, n, instances, len(services))
+		shares[service.Path] += cpuAllocation*cpuShare + (1-cpuAllocation)*memoryShare
+	}
+
+	return shares
+}
+
+// share returns a service's share of the requests of a resource. If no service
+// requests the resource, the service's share of the instances is used instead,
+// and if there are no instances each service shares equally.
+func share(requested, total, instances, totalInstances float64, services int) float64 {
+	if total > 0 {
+		return requested / total
+	}
+
+	if totalInstances > 0 {
+		return instances / totalInstances
+	}
+
+	return 1 / float64(services)
+}
@@ -0,0 +1,146 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimate(t *testing.T) {
+	cpuAllocation := 0.75
+	model := &Model{
+		InstanceTypePrices: map[string]float64{
+			"large": 0.2,
+			"small": 0.05,
+		},
+		CPUAllocation: &cpuAllocation,
+	}
+
+	nodePools := []NodePool{
+		{
+			ID:        "shared",
+			Path:      tree.PathSubcomponent("/team-a:shared"),
+			Instances: map[string]int32{"large": 2, "unknown": 1},
+
+			InstanceHours: map[string]float64{"large": 20, "unknown": 10},
+		},
+		{
+			ID:        "idle",
+			Path:      tree.PathSubcomponent("/team-b:idle"),
+			Instances: map[string]int32{"small": 2},
+
+			InstanceHours: map[string]float64{"small": 20},
+		},
+	}
+
+	services := []Service{
+		{
+			Path:      tree.Path("/team-a/api"),
+			NodePools: []string{"shared"},
+			Instances: 3,
+			MilliCPU:  1000,
+			MemoryMiB: 256,
+
+			InstanceHours: map[string]float64{"shared": 30},
+		},
+		{
+			Path:      tree.Path("/team-a/worker"),
+			NodePools: []string{"shared"},
+			Instances: 1,
+			MilliCPU:  1000,
+			MemoryMiB: 256,
+
+			InstanceHours: map[string]float64{"shared": 10},
+		},
+		{
+			Path:      tree.Path("/team-b/web"),
+			Instances: 1,
+		},
+	}
+
+	estimate := model.Estimate(nodePools, services)
+
+	// the api requests 3/4 of the shared node pool's cpu and memory
+	require.InDelta(t, 0.3, estimate.Services["/team-a/api"], 1e-9)
+	require.InDelta(t, 0.1, estimate.Services["/team-a/worker"], 1e-9)
+	require.InDelta(t, 0, estimate.Services["/team-b/web"], 1e-9)
+	require.InDelta(t, 0.1, estimate.UnallocatedNodePools["/team-b:idle"], 1e-9)
+	require.Equal(t, []string{"unknown"}, estimate.UnpricedInstanceTypes)
+
+	require.InDelta(t, 0.5, estimate.HourlyCost(tree.RootPath()), 1e-9)
+	require.InDelta(t, 0.4, estimate.HourlyCost(tree.Path("/team-a")), 1e-9)
+	require.InDelta(t, 0.1, estimate.HourlyCost(tree.Path("/team-b")), 1e-9)
+
+	require.InDelta(t, 3, estimate.ServiceCosts["/team-a/api"], 1e-9)
+	require.InDelta(t, 1, estimate.ServiceCosts["/team-a/worker"], 1e-9)
+	require.InDelta(t, 1, estimate.UnallocatedNodePoolCosts["/team-b:idle"], 1e-9)
+
+	teamA := estimate.CostEstimate(tree.Path("/team-a"), time.Now().Add(-10*time.Hour))
+	require.InDelta(t, 0.4*HoursPerMonth, teamA.ProjectedMonthlyCost, 1e-9)
+	require.InDelta(t, 4, teamA.Cost, 1e-9)
+	require.Len(t, teamA.Services, 2)
+	require.Empty(t, teamA.NodePools)
+}
+
+func TestEstimateSharesWithoutRequests(t *testing.T) {
+	model := &Model{InstanceTypePrices: map[string]float64{"large": 0.3}}
+
+	nodePools := []NodePool{
+		{ID: "shared", Path: tree.PathSubcomponent("/:shared"), Instances: map[string]int32{"large": 1}},
+	}
+
+	// services that don't request any resources split the node pool by
+	// their instances
+	services := []Service{
+		{Path: tree.Path("/a"), NodePools: []string{"shared"}, Instances: 2},
+		{Path: tree.Path("/b"), NodePools: []string{"shared"}, Instances: 1},
+	}
+
+	estimate := model.Estimate(nodePools, services)
+	require.InDelta(t, 0.2, estimate.Services["/a"], 1e-9)
+	require.InDelta(t, 0.1, estimate.Services["/b"], 1e-9)
+}
+
+func TestEstimateCostFromInstanceHours(t *testing.T) {
+	model := &Model{InstanceTypePrices: map[string]float64{"large": 0.2, "small": 0.05}}
+
+	// the node pool was scaled down and changed instance type during the
+	// period, and the worker ran on it for longer than the api
+	nodePools := []NodePool{
+		{
+			ID:            "shared",
+			Path:          tree.PathSubcomponent("/:shared"),
+			Instances:     map[string]int32{"small": 1},
+			InstanceHours: map[string]float64{"large": 30, "small": 40},
+		},
+	}
+
+	services := []Service{
+		{
+			Path:          tree.Path("/api"),
+			NodePools:     []string{"shared"},
+			Instances:     1,
+			MilliCPU:      1000,
+			MemoryMiB:     256,
+			InstanceHours: map[string]float64{"shared": 10},
+		},
+		{
+			Path:          tree.Path("/worker"),
+			NodePools:     []string{"shared"},
+			Instances:     1,
+			MilliCPU:      1000,
+			MemoryMiB:     256,
+			InstanceHours: map[string]float64{"shared": 30},
+		},
+	}
+
+	estimate := model.Estimate(nodePools, services)
+	require.InDelta(t, 0.025, estimate.Services["/api"], 1e-9)
+	require.InDelta(t, 0.025, estimate.Services["/worker"], 1e-9)
+	require.InDelta(t, 2, estimate.ServiceCosts["/api"], 1e-9)
+	require.InDelta(t, 6, estimate.ServiceCosts["/worker"], 1e-9)
+	require.InDelta(t, 8, estimate.Cost(tree.RootPath()), 1e-9)
+}
@@ -0,0 +1,180 @@
+package cost
+
+import (
+	"reflect"
+	"time"
+
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+)
+
+const (
+	// InstanceHoursRetention is how long the instance-hours of each day are kept.
+	InstanceHoursRetention = 90 * 24 * time.Hour
+
+	// InstanceHoursRecordInterval is how often instance-hours are recorded while
+	// the number of instances does not change.
+	InstanceHoursRecordInterval = time.Hour
+
+	day = 24 * time.Hour
+)
+
+// +k8s:deepcopy-gen=true
+
+// InstanceHours are the hours that instances ran for, bucketed by the UTC day
+// they ran in. The instances are counted by kind, such as the instance type of
+// a node pool's instances or the node pool a service's instances run on.
+type InstanceHours struct {
+	// Days are the instance-hours of each day, oldest first.
+	Days []InstanceHoursDay `json:"days,omitempty"`
+
+	// Instances are the number of instances of each kind since Timestamp, when
+	// the instance-hours were last recorded.
+	Instances map[string]int32 `json:"instances,omitempty"`
+	Timestamp *timeutil.Time   `json:"timestamp,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// InstanceHoursDay are the hours instances of each kind ran for during a day,
+// from Start, when the day's hours started being recorded.
+type InstanceHoursDay struct {
+	Start timeutil.Time      `json:"start"`
+	Hours map[string]float64 `json:"hours"`
+}
+
+// Record returns the instance-hours with the hours of the instances since they
+// were last recorded added, counting instances from now on. If the instances
+// have not changed since they were recorded less than InstanceHoursRecordInterval
+// ago, the instance-hours are returned unchanged so that recording them on every
+// sync does not change the status they are part of.
+func (h *InstanceHours) Record(instances map[string]int32, now time.Time) *InstanceHours {
+	instances = nonZero(instances)
+	if h != nil && h.Timestamp != nil &&
+		reflect.DeepEqual(h.Instances, instances) &&
+		now.Sub(h.Timestamp.Time) < InstanceHoursRecordInterval {
+		return h
+	}
+
+	recorded := &InstanceHours{}
+	if h != nil {
+		recorded = h.DeepCopy()
+	}
+
+	if recorded.Timestamp != nil {
+		recorded.add(recorded.Instances, recorded.Timestamp.Time, now)
+	}
+
+	recorded.Instances = instances
+	recorded.Timestamp = timeutil.New(now)
+	recorded.expire(now)
+	return recorded
+}
+
+// Since returns the hours instances of each kind ran for between since and now,
+// including the hours since they were last recorded. The hours of the day since
+// falls in are prorated as if they were spread evenly over the part of the day
+// that was recorded.
+func (h *InstanceHours) Since(since, now time.Time) map[string]float64 {
+	hours := make(map[string]float64)
+	if h == nil {
+		return hours
+	}
+
+	current := h.DeepCopy()
+	if current.Timestamp != nil {
+		current.add(current.Instances, current.Timestamp.Time, now)
+	}
+
+	for _, d := range current.Days {
+		start := d.Start.Time
+		end := start.Truncate(day).Add(day)
+		if end.After(now) {
+			end = now
+		}
+
+		if !end.After(since) {
+			continue
+		}
+
+		fraction := 1.0
+		if start.Before(since) {
+			fraction = end.Sub(since).Hours() / end.Sub(start).Hours()
+		}
+
+		for kind, n := range d.Hours {
+			hours[kind] += n * fraction
+		}
+	}
+
+	return hours
+}
+
+// add adds the hours the instances ran for between start and end to the days
+// they ran in.
+func (h *InstanceHours) add(instances map[string]int32, start, end time.Time) {
+	if len(instances) == 0 {
+		return
+	}
+
+	for start.Before(end) {
+		// days start at midnight UTC since the zero time does
+		dayStart := start.UTC().Truncate(day)
+		next := dayStart.Add(day)
+		if next.After(end) {
+			next = end
+		}
+
+		d := h.day(start)
+		for kind, n := range instances {
+			d.Hours[kind] += float64(n) * next.Sub(start).Hours()
+		}
+
+		start = next
+	}
+}
+
+// day returns the instance-hours of the day that start falls in, adding it
+// starting at start if it isn't already recorded.
+func (h *InstanceHours) day(start time.Time) *InstanceHoursDay {
+	if len(h.Days) != 0 {
+		last := &h.Days[len(h.Days)-1]
+		if last.Start.Truncate(day).Equal(start.Truncate(day)) {
+			return last
+		}
+	}
+
+	h.Days = append(h.Days, InstanceHoursDay{
+		Start: *timeutil.New(start.UTC()),
+		Hours: make(map[string]float64),
+	})
+	return &h.Days[len(h.Days)-1]
+}
+
+// expire removes the days that ended more than InstanceHoursRetention ago.
+func (h *InstanceHours) expire(now time.Time) {
+	cutoff := now.Add(-InstanceHoursRetention)
+
+	i := 0
+	for i < len(h.Days) && !h.Days[i].Start.Truncate(day).Add(day).After(cutoff) {
+		i++
+	}
+
+	h.Days = h.Days[i:]
+}
+
+// nonZero returns the kinds that have instances, or nil if none do.
+func nonZero(instances map[string]int32) map[string]int32 {
+	var result map[string]int32
+	for kind, n := range instances {
+		if n == 0 {
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]int32)
+		}
+		result[kind] = n
+	}
+
+	return result
+}
@@ -0,0 +1,55 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceHours(t *testing.T) {
+	midnight := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	// two instances start at 22:00, and scale to three at 02:00 the next day
+	var h *InstanceHours
+	h = h.Record(map[string]int32{"large": 2}, midnight.Add(-2*time.Hour))
+	h = h.Record(map[string]int32{"large": 3}, midnight.Add(2*time.Hour))
+
+	require.Len(t, h.Days, 2)
+	require.InDelta(t, 4, h.Days[0].Hours["large"], 1e-9)
+	require.InDelta(t, 4, h.Days[1].Hours["large"], 1e-9)
+
+	// the hours since the instances were last recorded are included
+	now := midnight.Add(4 * time.Hour)
+	require.InDelta(t, 14, h.Since(time.Time{}, now)["large"], 1e-9)
+
+	// the hours of the day since falls in are prorated over the part of the
+	// day that was recorded
+	require.InDelta(t, 10*3.0/4, h.Since(midnight.Add(time.Hour), now)["large"], 1e-9)
+	require.InDelta(t, 2, h.Since(midnight.Add(-time.Hour), now)["large"]-h.Since(midnight, now)["large"], 1e-9)
+	require.InDelta(t, 14, h.Since(midnight.Add(-2*time.Hour), now)["large"], 1e-9)
+
+	// instances that haven't changed aren't recorded again until the interval passes
+	require.True(t, h == h.Record(map[string]int32{"large": 3}, midnight.Add(2*time.Hour+time.Minute)))
+
+	recorded := h.Record(map[string]int32{"large": 3}, midnight.Add(2*time.Hour+InstanceHoursRecordInterval))
+	require.False(t, h == recorded)
+	require.InDelta(t, 4+3, recorded.Days[1].Hours["large"], 1e-9)
+
+	// kinds without instances stop counting
+	recorded = recorded.Record(map[string]int32{"large": 0}, midnight.Add(4*time.Hour))
+	require.Nil(t, recorded.Instances)
+	require.InDelta(t, 14, recorded.Since(time.Time{}, midnight.Add(12*time.Hour))["large"], 1e-9)
+}
+
+func TestInstanceHoursRetention(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var h *InstanceHours
+	h = h.Record(map[string]int32{"large": 1}, start)
+	h = h.Record(map[string]int32{"large": 2}, start.Add(day))
+	h = h.Record(map[string]int32{"large": 2}, start.Add(InstanceHoursRetention+day))
+
+	require.Equal(t, start.Add(day), h.Days[0].Start.Time)
+	require.InDelta(t, 2*24*90, h.Since(time.Time{}, start.Add(InstanceHoursRetention+day))["large"], 1e-9)
+}
@@ -19,6 +19,9 @@ type ResolutionInfo struct {
 	// TODO(kevindrosendahl): probably want to move this out when we have a more
 	// concrete theory on component resolution secrets.
 	SSHKeySecret *tree.PathSubcomponent
+	// SSHKeySecretProvider is the secret provider SSHKeySecret should be
+	// retrieved from.
+	SSHKeySecretProvider string
 }
 
 func (i *ResolutionInfo) MarshalJSON() ([]byte, error) {
@@ -31,6 +34,8 @@ func (i *ResolutionInfo) MarshalJSON() ([]byte, error) {
 		Component:    componentData,
 		Commit:       i.Commit,
 		SSHKeySecret: i.SSHKeySecret,
+
+		SSHKeySecretProvider: i.SSHKeySecretProvider,
 	}
 	return json.Marshal(&d)
 }
@@ -61,6 +66,7 @@ func (i *ResolutionInfo) UnmarshalJSON(data []byte) error {
 	(*i).Component = c
 	(*i).Commit = d.Commit
 	(*i).SSHKeySecret = d.SSHKeySecret
+	(*i).SSHKeySecretProvider = d.SSHKeySecretProvider
 
 	return nil
 }
@@ -69,10 +75,17 @@ type resolutionInfoDecoder struct {
 	Component    json.RawMessage        `json:"component"`
 	Commit       *git.CommitReference   `json:"commit"`
 	SSHKeySecret *tree.PathSubcomponent `json:"sshKeySecret"`
+
+	SSHKeySecretProvider string `json:"sshKeySecretProvider,omitempty"`
 }
 
 type resolutionContext struct {
-	CommitReference *git.CommitReference
-	SSHKeySecret    *tree.PathSubcomponent
-	SSHKey          []byte
+	CommitReference      *git.CommitReference
+	SSHKeySecret         *tree.PathSubcomponent
+	SSHKeySecretProvider string
+	SSHKey               []byte
+
+	// SecretProvider is the default secret provider for secret references
+	// that do not name their own provider.
+	SecretProvider string
 }
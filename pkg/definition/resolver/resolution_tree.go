@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
 	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
@@ -165,6 +166,37 @@ func (t *V1Tree) NodePools(fn V1TreeNodePoolWalkFn) {
 	})
 }
 
+// ValidateNodePools returns an error describing the first invalid node pool in the
+// tree, including the node pools dedicated to services.
+func (t *V1Tree) ValidateNodePools() error {
+	var err error
+	t.NodePools(func(subcomponent tree.PathSubcomponent, nodePool *definitionv1.NodePool) tree.WalkContinuation {
+		if err = nodePool.Validate(); err != nil {
+			err = fmt.Errorf("node pool %v: %v", subcomponent.String(), err)
+			return tree.HaltWalk
+		}
+
+		return tree.ContinueWalk
+	})
+	if err != nil {
+		return err
+	}
+
+	t.Services(func(path tree.Path, service *definitionv1.Service, info *ResolutionInfo) tree.WalkContinuation {
+		if service.NodePool == nil || service.NodePool.NodePool == nil {
+			return tree.ContinueWalk
+		}
+
+		if err = service.NodePool.NodePool.Validate(); err != nil {
+			err = fmt.Errorf("node pool of service %v: %v", path.String(), err)
+			return tree.HaltWalk
+		}
+
+		return tree.ContinueWalk
+	})
+	return err
+}
+
 // References walks the resolution tree, invoking the supplied function on each path that contains a v1/reference.
 func (t *V1Tree) References(fn V1TreeReferenceWalkFn) {
 	t.ResolutionTree.Walk(func(path tree.Path, i *ResolutionInfo) tree.WalkContinuation {
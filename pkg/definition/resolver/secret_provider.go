@@ -0,0 +1,66 @@
+package resolver
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/definition"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+)
+
+// setDefaultSecretProvider sets the provider of any of the component's secret
+// references that do not name a provider to the default provider.
+func setDefaultSecretProvider(c definition.Component, provider string) {
+	if provider == "" {
+		return
+	}
+
+	workload, ok := c.(definitionv1.Workload)
+	if !ok {
+		return
+	}
+
+	containers := workload.Containers()
+	setContainerDefaultSecretProvider(&containers.Main, provider)
+	for _, sidecar := range containers.Sidecars {
+		setContainerDefaultSecretProvider(&sidecar, provider)
+	}
+}
+
+func setContainerDefaultSecretProvider(container *definitionv1.Container, provider string) {
+	var refs []*definitionv1.SecretRef
+	if container.Exec != nil {
+		for _, v := range container.Exec.Environment {
+			refs = append(refs, v.SecretRef)
+		}
+	}
+
+	for _, file := range container.Files {
+		if file.Content != nil {
+			refs = append(refs, file.Content.SecretRef)
+		}
+
+		if file.Template != nil {
+			for _, v := range file.Template.Parameters {
+				refs = append(refs, v.SecretRef)
+			}
+		}
+	}
+
+	if container.Build != nil {
+		if b := container.Build.CommandBuild; b != nil && b.Source != nil && b.Source.GitRepository != nil {
+			refs = append(refs, b.Source.GitRepository.SSHKey)
+		}
+
+		if b := container.Build.DockerBuild; b != nil && b.BuildContext != nil && b.BuildContext.Location != nil {
+			if b.BuildContext.Location.GitRepository != nil {
+				refs = append(refs, b.BuildContext.Location.GitRepository.SSHKey)
+			}
+		}
+	}
+
+	// the containers are copies, but their secret references are pointers
+	// shared with the component, so updating them updates the component
+	for _, ref := range refs {
+		if ref != nil && ref.Provider == "" {
+			ref.Provider = provider
+		}
+	}
+}
@@ -2,12 +2,13 @@ package resolver
 
 import (
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
-	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider"
 )
 
 type SecretStore interface {
 	Ready() bool
-	Get(systemID v1.SystemID, path tree.PathSubcomponent) (string, error)
+	Get(systemID v1.SystemID, ref *definitionv1.SecretRef) (string, error)
 }
 
 type SecretDoesNotExistError struct{}
@@ -15,3 +16,34 @@ type SecretDoesNotExistError struct{}
 func (e *SecretDoesNotExistError) Error() string {
 	return "Secret does not exist"
 }
+
+func NewProviderSecretStore(providers *secretprovider.Providers) *ProviderSecretStore {
+	return &ProviderSecretStore{providers}
+}
+
+// ProviderSecretStore implements a SecretStore that retrieves secrets from
+// the secret provider named by the secret reference.
+type ProviderSecretStore struct {
+	providers *secretprovider.Providers
+}
+
+func (s *ProviderSecretStore) Ready() bool {
+	return s.providers.Ready()
+}
+
+func (s *ProviderSecretStore) Get(systemID v1.SystemID, ref *definitionv1.SecretRef) (string, error) {
+	provider, err := s.providers.Provider(ref.Provider)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := provider.Reveal(systemID, ref.Value, ref.Version)
+	if err != nil {
+		if v1err, ok := err.(*v1.Error); ok && (v1err.Code == v1.ErrorCodeInvalidSecret || v1err.Code == v1.ErrorCodeInvalidSecretVersion) {
+			return "", &SecretDoesNotExistError{}
+		}
+		return "", err
+	}
+
+	return secret.Value, nil
+}
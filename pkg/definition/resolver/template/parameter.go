@@ -21,6 +21,12 @@ const (
 
 	SecretParameterLVal    = "$secret"
 	SecretRefParameterLVal = "$secret_ref"
+
+	// SecretProviderParameterLVal optionally names the secret provider a $secret
+	// should be retrieved from.
+	SecretProviderParameterLVal = "provider"
+	// SecretVersionParameterLVal optionally pins a $secret to a version.
+	SecretVersionParameterLVal = "version"
 )
 
 type ParameterTypeError struct {
@@ -101,6 +107,49 @@ func (d Parameter) Validate(assignment interface{}) error {
 	}
 }
 
+// NewSecretRef returns a reference to the secret at the path, using the provider
+// and version named in the $secret map if there are any.
+func NewSecretRef(path tree.PathSubcomponent, secret map[string]interface{}) (*definitionv1.SecretRef, error) {
+	ref := &definitionv1.SecretRef{Value: path}
+	if v, ok := secret[SecretProviderParameterLVal]; ok {
+		provider, ok := v.(string)
+		if !ok {
+			return nil, &ParameterTypeError{
+				Expected: "string",
+				Actual:   reflect.TypeOf(v).String(),
+			}
+		}
+
+		ref.Provider = provider
+	}
+
+	if v, ok := secret[SecretVersionParameterLVal]; ok {
+		var version float64
+		switch val := v.(type) {
+		case float64:
+			version = val
+		case int:
+			version = float64(val)
+		default:
+			return nil, &ParameterTypeError{
+				Expected: "positive integer",
+				Actual:   reflect.TypeOf(v).String(),
+			}
+		}
+
+		if version < 1 || version != float64(int32(version)) {
+			return nil, &ParameterTypeError{
+				Expected: "positive integer",
+				Actual:   fmt.Sprintf("%v", version),
+			}
+		}
+
+		ref.Version = int32(version)
+	}
+
+	return ref, nil
+}
+
 type Parameters map[string]Parameter
 
 // Bind will take in a set of bindings for parameters, type check them, and properly set defaults if necessary.
@@ -157,8 +206,9 @@ func (p Parameters) Bind(path tree.Path, bindings map[string]interface{}) (map[s
 					return nil, err
 				}
 
-				def = &definitionv1.SecretRef{
-					Value: secretRefPath,
+				def, err = NewSecretRef(secretRefPath, secret)
+				if err != nil {
+					return nil, err
 				}
 			}
 
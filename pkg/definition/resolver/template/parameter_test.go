@@ -386,6 +386,35 @@ func TestParameterBind(t *testing.T) {
 			result:   map[string]interface{}{"foo": &definitionv1.SecretRef{Value: tree.PathSubcomponent("/foo/bar:baz")}},
 			err:      nil,
 		},
+		{
+			description: "default pinned secret",
+			params: Parameters{
+				"foo": Parameter{
+					Type:    ParameterTypeSecret,
+					Default: map[string]interface{}{"$secret": "baz", "version": float64(2)},
+				},
+			},
+			bindings: make(map[string]interface{}),
+			path:     tree.Path("/foo/bar"),
+			result:   map[string]interface{}{"foo": &definitionv1.SecretRef{Value: tree.PathSubcomponent("/foo/bar:baz"), Version: 2}},
+			err:      nil,
+		},
+		{
+			description: "default secret with invalid version",
+			params: Parameters{
+				"foo": Parameter{
+					Type:    ParameterTypeSecret,
+					Default: map[string]interface{}{"$secret": "baz", "version": float64(1.5)},
+				},
+			},
+			bindings: make(map[string]interface{}),
+			path:     tree.Path("/foo/bar"),
+			result:   nil,
+			err: &ParameterTypeError{
+				Expected: "positive integer",
+				Actual:   "1.5",
+			},
+		},
 		{
 			description: "wrong default type secret",
 			params: Parameters{
@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/mlab-lattice/lattice/pkg/definition/tree"
-	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
 )
 
 const parametersField = "$parameters"
@@ -125,7 +124,7 @@ func evaluateMap(path tree.Path, val, bindings map[string]interface{}) (interfac
 		// TODO(kevindrosendahl): this is too tightly coupled, may want to make these different
 		//                        resolution phases pluggable so different versions can implement
 		//                        their own parsers/validators in here
-		// TODO(kevindrosendahl): should check to make sure that $secret is the only key in the map
+		// TODO(kevindrosendahl): should check to make sure that $secret, provider and version are the only keys in the map
 		if k == SecretParameterLVal {
 			// Ensure the $secret key is a string
 			// TODO(kevindrosendahl): validate character set here?
@@ -142,10 +141,7 @@ func evaluateMap(path tree.Path, val, bindings map[string]interface{}) (interfac
 				return nil, err
 			}
 
-			secretRef := &definitionv1.SecretRef{
-				Value: secretRefPath,
-			}
-			return secretRef, nil
+			return NewSecretRef(secretRefPath, val)
 		}
 
 		result, err := evaluateValue(path, v, bindings)
@@ -1,6 +1,10 @@
 package test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	//"os"
 	"reflect"
@@ -1041,6 +1045,86 @@ func TestComponentResolver_FileReference(t *testing.T) {
 	)
 }
 
+func TestComponentResolver_SSHKeySecretProvider(t *testing.T) {
+	gitResolver, err := git.NewResolver(fmt.Sprintf("%v/resolver", workDir), true)
+	if err != nil {
+		t.Fatalf("error creating git resolver: %v", err)
+	}
+
+	sshKeySecret := tree.PathSubcomponent("/:ssh-key")
+	secretStore := mockresolver.NewMemorySecretStore()
+	secretStore.Set(system1ID, sshKeySecret, sshKey(t))
+
+	r := NewComponentResolver(gitResolver, mockresolver.NewMemoryTemplateStore(), secretStore)
+
+	// seed repo with a system containing a job, a service and a reference
+	// that is left unresolved
+	repo := repoURL(repo1)
+	os.RemoveAll(workDir)
+	err = git.Init(repo)
+	if err != nil {
+		t.Fatalf("error initializing repo: %v", err)
+	}
+
+	serviceFile := "service1.json"
+	system := &definitionv1.System{
+		Description: "system with reference",
+		Components: map[string]definition.Component{
+			"job":       job1,
+			"service":   service1,
+			"reference": &definitionv1.Reference{File: &serviceFile},
+		},
+	}
+	systemBytes, err := json.Marshal(&system)
+	if err != nil {
+		t.Fatalf("error marshalling system: %v", err)
+	}
+
+	commit, err := git.WriteAndCommitFile(repo, DefaultFile, systemBytes, 0700, "system")
+	if err != nil {
+		t.Fatalf("error commiting to repo: %v", err)
+	}
+	commitStr := commit.String()
+
+	ref := &definitionv1.Reference{
+		GitRepository: &definitionv1.GitRepositoryReference{
+			GitRepository: &definitionv1.GitRepository{
+				URL:    repo,
+				Commit: &commitStr,
+				SSHKey: &definitionv1.SecretRef{
+					Value:    sshKeySecret,
+					Provider: "vault",
+				},
+			},
+		},
+	}
+
+	// resolving the reference and the system's components leaves the nested
+	// reference at depth 0
+	result, err := r.Resolve(ref, system1ID, tree.RootPath(), nil, 1)
+	if err != nil {
+		t.Fatalf("expected no error resolving reference with ssh key, got: %v", err)
+	}
+
+	if result.Len() != 4 {
+		t.Errorf("expected result to contain 4 entries, found %v", result.Len())
+	}
+
+	// every component resolved from the repository has to be able to retrieve
+	// its ssh key from the reference's secret provider
+	result.Walk(func(path tree.Path, info *ResolutionInfo) tree.WalkContinuation {
+		if info.SSHKeySecret == nil || *info.SSHKeySecret != sshKeySecret {
+			t.Errorf("expected %v to have ssh key secret %v, got %v", path.String(), sshKeySecret, info.SSHKeySecret)
+		}
+
+		if info.SSHKeySecretProvider != "vault" {
+			t.Errorf("expected %v to have ssh key secret provider vault, got %q", path.String(), info.SSHKeySecretProvider)
+		}
+
+		return tree.ContinueWalk
+	})
+}
+
 type successfulResolutionTest struct {
 	name     string
 	c        definition.Component
@@ -1129,3 +1213,16 @@ func resolver() Interface {
 
 	return NewComponentResolver(gitResolver, mockresolver.NewMemoryTemplateStore(), mockresolver.NewMemorySecretStore())
 }
+
+func sshKey(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating ssh key: %v", err)
+	}
+
+	block := &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}
+	return string(pem.EncodeToMemory(block))
+}
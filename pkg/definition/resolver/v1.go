@@ -55,10 +55,14 @@ func (r *v1ComponentResolver) Resolve(
 		return r.resolveSystem(v1Component, id, path, ctx, depth, result)
 
 	default:
+		setDefaultSecretProvider(c, ctx.SecretProvider)
+
 		info := &ResolutionInfo{
 			Component:    c,
 			Commit:       ctx.CommitReference,
 			SSHKeySecret: ctx.SSHKeySecret,
+
+			SSHKeySecretProvider: ctx.SSHKeySecretProvider,
 		}
 		result.Insert(path, info)
 		return nil
@@ -94,6 +98,8 @@ func (r *v1ComponentResolver) resolveReference(
 			Component:    ref,
 			Commit:       ctx.CommitReference,
 			SSHKeySecret: ctx.SSHKeySecret,
+
+			SSHKeySecretProvider: ctx.SSHKeySecretProvider,
 		}
 		result.Insert(path, info)
 		return nil
@@ -148,9 +154,19 @@ func (r *v1ComponentResolver) resolveSystem(
 		Component:    system,
 		Commit:       ctx.CommitReference,
 		SSHKeySecret: ctx.SSHKeySecret,
+
+		SSHKeySecretProvider: ctx.SSHKeySecretProvider,
 	}
 	result.Insert(path, info)
 
+	// if the system sets a default secret provider, use it for the
+	// system's subcomponents
+	if system.SecretProvider != "" {
+		systemCtx := *ctx
+		systemCtx.SecretProvider = system.SecretProvider
+		ctx = &systemCtx
+	}
+
 	for name, c := range system.Components {
 		err := r.resolver.resolve(c, id, path.Child(name), ctx, depth, result)
 		if err != nil {
@@ -174,6 +190,7 @@ func (r *v1ComponentResolver) resolveTemplate(
 	// Get the proper commit reference and file for the reference, potentially updating
 	// the context as well.
 	var sshKeySecret *tree.PathSubcomponent
+	var sshKeySecretProvider string
 	var gitRef *git.Reference
 	var file string
 	switch {
@@ -192,7 +209,16 @@ func (r *v1ComponentResolver) resolveTemplate(
 		var sshKey []byte
 		if ref.GitRepository.SSHKey != nil {
 			sshKeySecret = &ref.GitRepository.SSHKey.Value
-			sshKeyVal, err := r.secretStore.Get(systemID, ref.GitRepository.SSHKey.Value)
+			sshKeySecretProvider = ref.GitRepository.SSHKey.Provider
+			if sshKeySecretProvider == "" {
+				sshKeySecretProvider = ctx.SecretProvider
+			}
+
+			secretRef := &definitionv1.SecretRef{
+				Value:    ref.GitRepository.SSHKey.Value,
+				Provider: sshKeySecretProvider,
+			}
+			sshKeyVal, err := r.secretStore.Get(systemID, secretRef)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -214,6 +240,8 @@ func (r *v1ComponentResolver) resolveTemplate(
 		// file to file referenced.
 		gitCtx.RepositoryURL = ctx.CommitReference.RepositoryURL
 		gitCtx.Options.SSHKey = ctx.SSHKey
+		sshKeySecret = ctx.SSHKeySecret
+		sshKeySecretProvider = ctx.SSHKeySecretProvider
 		gitRef = &git.Reference{Commit: &ctx.CommitReference.Commit}
 		file = *ref.File
 	}
@@ -229,9 +257,11 @@ func (r *v1ComponentResolver) resolveTemplate(
 	}
 
 	resolvedContext := &resolutionContext{
-		CommitReference: commitRef,
-		SSHKey:          gitCtx.Options.SSHKey,
-		SSHKeySecret:    sshKeySecret,
+		CommitReference:      commitRef,
+		SSHKey:               gitCtx.Options.SSHKey,
+		SSHKeySecret:         sshKeySecret,
+		SSHKeySecretProvider: sshKeySecretProvider,
+		SecretProvider:       ctx.SecretProvider,
 	}
 
 	// Only want to check the cache if no credentials are required.
@@ -355,7 +385,12 @@ func (r *v1ComponentResolver) hydrateReferenceParameters(
 			return nil, fmt.Errorf("got error creating secret reference for parameter %v: %v", k, err)
 		}
 
-		p[k] = &definitionv1.SecretRef{Value: sp}
+		ref, err := template.NewSecretRef(sp, m)
+		if err != nil {
+			return nil, fmt.Errorf("got error creating secret reference for parameter %v: %v", k, err)
+		}
+
+		p[k] = ref
 	}
 
 	return p, nil
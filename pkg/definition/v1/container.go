@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"text/template"
 
 	"github.com/mlab-lattice/lattice/pkg/definition"
 )
@@ -12,6 +15,11 @@ const (
 	ContainerBuildTypeCommand     = "command_build"
 	ContainerBuildTypeDockerImage = "docker_image"
 	ContainerBuildTypeDockerBuild = "docker_build"
+
+	ContainerPortProtocolHTTP  = "HTTP"
+	ContainerPortProtocolHTTP2 = "HTTP2"
+	ContainerPortProtocolGRPC  = "GRPC"
+	ContainerPortProtocolTCP   = "TCP"
 )
 
 var ContainerType = definition.Type{
@@ -23,6 +31,9 @@ type Container struct {
 	Build *ContainerBuild `json:"build,omitempty"`
 	Exec  *ContainerExec  `json:"exec,omitempty"`
 
+	// Files maps absolute paths in the container to the files to mount at them.
+	Files map[string]ContainerFile `json:"files,omitempty"`
+
 	Ports map[int32]ContainerPort `json:"ports,omitempty"`
 
 	HealthCheck *ContainerHealthCheck `json:"health_check,omitempty"`
@@ -142,8 +153,77 @@ type ContainerExec struct {
 
 type ContainerExecEnvironment map[string]ValueOrSecret
 
+// ContainerFile is a file mounted into a container. Exactly one of
+// Content and Template should be set.
+type ContainerFile struct {
+	// Content is either the literal content of the file or a reference
+	// to the secret whose value is the content of the file.
+	Content *ValueOrSecret `json:"content,omitempty"`
+
+	// Template is rendered to produce the content of the file.
+	Template *ContainerFileTemplate `json:"template,omitempty"`
+
+	// Mode is the file's permission bits in octal (e.g. "0600").
+	// If empty, the file's mode is 0644.
+	Mode string `json:"mode,omitempty"`
+}
+
+// FileMode returns the file's permission bits.
+func (f ContainerFile) FileMode() (int32, error) {
+	if f.Mode == "" {
+		return 0644, nil
+	}
+
+	mode, err := strconv.ParseInt(f.Mode, 8, 32)
+	if err != nil || mode < 0 || mode > 0777 {
+		return 0, fmt.Errorf("invalid file mode %v, expected octal permission bits", f.Mode)
+	}
+
+	return int32(mode), nil
+}
+
+// ContainerFileTemplate is a text/template (e.g. "token: {{ .token }}") that
+// is rendered with its parameters.
+type ContainerFileTemplate struct {
+	Content    string                   `json:"content"`
+	Parameters map[string]ValueOrSecret `json:"parameters,omitempty"`
+}
+
+// Render renders the template. secretValue is used to retrieve the values
+// of the parameters that reference secrets.
+func (t *ContainerFileTemplate) Render(secretValue func(ref *SecretRef) (string, error)) (string, error) {
+	tmpl, err := template.New("file").Option("missingkey=error").Parse(t.Content)
+	if err != nil {
+		return "", fmt.Errorf("error parsing file template: %v", err)
+	}
+
+	values := make(map[string]string)
+	for name, parameter := range t.Parameters {
+		switch {
+		case parameter.Value != nil:
+			values[name] = *parameter.Value
+
+		case parameter.SecretRef != nil:
+			value, err := secretValue(parameter.SecretRef)
+			if err != nil {
+				return "", err
+			}
+
+			values[name] = value
+		}
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, values); err != nil {
+		return "", fmt.Errorf("error rendering file template: %v", err)
+	}
+
+	return b.String(), nil
+}
+
 type ContainerPort struct {
 	Protocol       string                       `json:"protocol"`
+	GRPC           *ContainerPortGRPC           `json:"grpc,omitempty"`
 	ExternalAccess *ContainerPortExternalAccess `json:"external_access,omitempty"`
 }
 
@@ -151,6 +231,31 @@ func (c ContainerPort) Public() bool {
 	return c.ExternalAccess != nil && c.ExternalAccess.Public
 }
 
+// HTTPBased returns whether the port speaks a protocol that is layered on top of HTTP.
+func (c ContainerPort) HTTPBased() bool {
+	switch c.Protocol {
+	case ContainerPortProtocolHTTP, ContainerPortProtocolHTTP2, ContainerPortProtocolGRPC:
+		return true
+	default:
+		return false
+	}
+}
+
+// HTTP2 returns whether the port expects to be spoken to via HTTP/2.
+func (c ContainerPort) HTTP2() bool {
+	return c.Protocol == ContainerPortProtocolHTTP2 || c.Protocol == ContainerPortProtocolGRPC
+}
+
+type ContainerPortGRPC struct {
+	// Services are the fully qualified names of the gRPC services served
+	// on the port (e.g. helloworld.Greeter). If set, only requests for these
+	// services are routed to the port.
+	Services []string `json:"services,omitempty"`
+
+	// HealthCheckService is the service name sent in gRPC health checks.
+	HealthCheckService string `json:"health_check_service,omitempty"`
+}
+
 type ContainerPortExternalAccess struct {
 	Public bool `json:"public"`
 }
@@ -164,7 +269,23 @@ type ContainerHealthCheckHTTP struct {
 	Port int32  `json:"port"`
 }
 
+// ContainerResources are the resources a container requests and the limits of
+// the resources it can use. Memory and CPU are shorthand for requesting them,
+// and cannot be set alongside the same resource in Requests.
 type ContainerResources struct {
-	Memory string `json:"memory"`
-	CPU    string `json:"cpu"`
+	Memory string `json:"memory,omitempty"`
+	CPU    string `json:"cpu,omitempty"`
+
+	Requests *ContainerResourceList `json:"requests,omitempty"`
+	Limits   *ContainerResourceList `json:"limits,omitempty"`
+}
+
+// ContainerResourceList is an amount of each resource a container can use.
+// CPU, Memory and EphemeralStorage are kubernetes style quantities, for example
+// "500m" or "512Mi".
+type ContainerResourceList struct {
+	CPU              string `json:"cpu,omitempty"`
+	Memory           string `json:"memory,omitempty"`
+	GPU              int32  `json:"gpu,omitempty"`
+	EphemeralStorage string `json:"ephemeral_storage,omitempty"`
 }
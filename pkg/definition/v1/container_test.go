@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+)
+
+func TestContainerFileFileMode(t *testing.T) {
+	tests := []struct {
+		mode     string
+		expected int32
+		valid    bool
+	}{
+		{mode: "", expected: 0644, valid: true},
+		{mode: "0600", expected: 0600, valid: true},
+		{mode: "755", expected: 0755, valid: true},
+		{mode: "0800", valid: false},
+		{mode: "1777", valid: false},
+	}
+
+	for _, test := range tests {
+		mode, err := ContainerFile{Mode: test.mode}.FileMode()
+		if !test.valid {
+			if err == nil {
+				t.Errorf("expected error for mode %v", test.mode)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if mode != test.expected {
+			t.Errorf("expected mode %o for %v but got %o", test.expected, test.mode, mode)
+		}
+	}
+}
+
+func TestContainerFileTemplateRender(t *testing.T) {
+	host := "example.com"
+	token := SecretRef{
+		Value: tree.PathSubcomponent("/foo/bar:token"),
+	}
+
+	tmpl := ContainerFileTemplate{
+		Content: "server: {{ .host }}\ntoken: {{ .token }}\n",
+		Parameters: map[string]ValueOrSecret{
+			"host":  {Value: &host},
+			"token": {SecretRef: &token},
+		},
+	}
+
+	secretValue := func(ref *SecretRef) (string, error) {
+		if ref.Value != token.Value {
+			return "", fmt.Errorf("unexpected secret %v", ref.Value.String())
+		}
+		return "abc", nil
+	}
+
+	content, err := tmpl.Render(secretValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "server: example.com\ntoken: abc\n"
+	if content != expected {
+		t.Errorf("expected %q but got %q", expected, content)
+	}
+
+	tmpl.Content = "{{ .missing }}"
+	if _, err := tmpl.Render(secretValue); err == nil {
+		t.Errorf("expected error rendering template with missing parameter")
+	}
+}
+
+func TestContainerPortProtocols(t *testing.T) {
+	tests := []struct {
+		protocol  string
+		httpBased bool
+		http2     bool
+	}{
+		{protocol: ContainerPortProtocolHTTP, httpBased: true, http2: false},
+		{protocol: ContainerPortProtocolHTTP2, httpBased: true, http2: true},
+		{protocol: ContainerPortProtocolGRPC, httpBased: true, http2: true},
+		{protocol: ContainerPortProtocolTCP, httpBased: false, http2: false},
+	}
+
+	for _, test := range tests {
+		port := ContainerPort{Protocol: test.protocol}
+		if port.HTTPBased() != test.httpBased {
+			t.Errorf("expected HTTPBased %v for %v but got %v", test.httpBased, test.protocol, port.HTTPBased())
+		}
+
+		if port.HTTP2() != test.http2 {
+			t.Errorf("expected HTTP2 %v for %v but got %v", test.http2, test.protocol, port.HTTP2())
+		}
+	}
+}
@@ -8,6 +8,10 @@ type DockerImage struct {
 	Registry   string `json:"registry,omitempty"`
 	Repository string `json:"repository"`
 	Tag        string `json:"tag"`
+
+	// Digest pins the image to the manifest with the digest. If set, the tag
+	// is only informational.
+	Digest string `json:"digest,omitempty"`
 }
 
 type DockerBuildContext struct {
@@ -23,6 +23,10 @@ type Job struct {
 	Container
 	Sidecars map[string]Container
 
+	// Preemptible is whether the job tolerates its runs being interrupted,
+	// which allows them to run on spot instances.
+	Preemptible bool
+
 	// FIXME: remove these
 	NodePool tree.PathSubcomponent `json:"node_pool"`
 }
@@ -46,6 +50,8 @@ func (j *Job) MarshalJSON() ([]byte, error) {
 		Container: j.Container,
 		Sidecars:  j.Sidecars,
 
+		Preemptible: j.Preemptible,
+
 		NodePool: j.NodePool,
 	}
 	return json.Marshal(&e)
@@ -71,6 +77,8 @@ func (j *Job) UnmarshalJSON(data []byte) error {
 		Container: e.Container,
 		Sidecars:  e.Sidecars,
 
+		Preemptible: e.Preemptible,
+
 		NodePool: e.NodePool,
 	}
 	*j = *job
@@ -84,5 +92,7 @@ type jobEncoder struct {
 	Container
 	Sidecars map[string]Container `json:"sidecars,omitempty"`
 
+	Preemptible bool `json:"preemptible,omitempty"`
+
 	NodePool tree.PathSubcomponent `json:"node_pool"`
 }
@@ -8,10 +8,98 @@ import (
 )
 
 type NodePool struct {
+	// NumInstances is the number of instances in the node pool. If the node
+	// pool is autoscaled, it is the number of instances the node pool starts
+	// with.
 	NumInstances int32  `json:"num_instances"`
 	InstanceType string `json:"instance_type"`
+
+	// MinInstances and MaxInstances bound the number of instances of an
+	// autoscaled node pool.
+	MinInstances int32 `json:"min_instances,omitempty"`
+	MaxInstances int32 `json:"max_instances,omitempty"`
+
+	// InstanceTypes are additional instance types the node pool's instances
+	// may be launched as, so that it can draw on more spot capacity.
+	InstanceTypes []string `json:"instance_types,omitempty"`
+
+	// Spot, if set, launches some of the node pool's instances as spot
+	// instances, which are cheaper but may be interrupted.
+	Spot *NodePoolSpot `json:"spot,omitempty"`
+}
+
+// Autoscaled returns whether the node pool's number of instances is scaled
+// to fit the workloads running on it.
+func (np *NodePool) Autoscaled() bool {
+	return np.MaxInstances != 0
+}
+
+// Validate returns an error if the node pool's number of instances or its
+// autoscaling bounds are invalid.
+func (np *NodePool) Validate() error {
+	if np.NumInstances < 0 || np.MinInstances < 0 || np.MaxInstances < 0 {
+		return fmt.Errorf("num_instances, min_instances and max_instances must not be negative")
+	}
+
+	if np.MinInstances > np.MaxInstances {
+		return fmt.Errorf(
+			"min_instances (%v) must not be greater than max_instances (%v)",
+			np.MinInstances,
+			np.MaxInstances,
+		)
+	}
+
+	if np.Autoscaled() && (np.NumInstances < np.MinInstances || np.NumInstances > np.MaxInstances) {
+		return fmt.Errorf(
+			"num_instances (%v) must be between min_instances (%v) and max_instances (%v)",
+			np.NumInstances,
+			np.MinInstances,
+			np.MaxInstances,
+		)
+	}
+
+	return nil
 }
 
+// AllInstanceTypes returns the instance types the node pool's instances
+// may be launched as, starting with InstanceType.
+func (np *NodePool) AllInstanceTypes() []string {
+	instanceTypes := []string{np.InstanceType}
+	for _, instanceType := range np.InstanceTypes {
+		if instanceType != np.InstanceType {
+			instanceTypes = append(instanceTypes, instanceType)
+		}
+	}
+
+	return instanceTypes
+}
+
+// NodePoolSpot describes the mix of on-demand and spot instances in a node pool.
+// Only workloads that tolerate preemption are scheduled onto spot instances.
+type NodePoolSpot struct {
+	// OnDemandBaseInstances is the number of instances that are always on-demand.
+	OnDemandBaseInstances int32 `json:"on_demand_base_instances,omitempty"`
+
+	// OnDemandPercentage is the percentage of the instances above the base
+	// instances that are on-demand. The rest are spot instances.
+	OnDemandPercentage int32 `json:"on_demand_percentage,omitempty"`
+
+	// Fallback is what the node pool does when spot capacity is unavailable.
+	// It defaults to NodePoolSpotFallbackOnDemand.
+	Fallback NodePoolSpotFallback `json:"fallback,omitempty"`
+}
+
+type NodePoolSpotFallback string
+
+const (
+	// NodePoolSpotFallbackOnDemand launches on-demand instances in place of spot
+	// instances while spot capacity is unavailable.
+	NodePoolSpotFallbackOnDemand NodePoolSpotFallback = "on-demand"
+
+	// NodePoolSpotFallbackNone waits for spot capacity to become available.
+	NodePoolSpotFallbackNone NodePoolSpotFallback = "none"
+)
+
 type NodePoolOrReference struct {
 	NodePool     *NodePool
 	NodePoolPath *tree.PathSubcomponent
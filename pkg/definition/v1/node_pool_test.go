@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"testing"
+)
+
+func TestNodePoolValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodePool NodePool
+		valid    bool
+	}{
+		{name: "fixed", nodePool: NodePool{NumInstances: 2}, valid: true},
+		{name: "autoscaled", nodePool: NodePool{NumInstances: 2, MinInstances: 1, MaxInstances: 3}, valid: true},
+		{name: "autoscaled at minimum", nodePool: NodePool{NumInstances: 1, MinInstances: 1, MaxInstances: 3}, valid: true},
+		{name: "autoscaled at maximum", nodePool: NodePool{NumInstances: 3, MinInstances: 1, MaxInstances: 3}, valid: true},
+		{name: "negative num instances", nodePool: NodePool{NumInstances: -1}, valid: false},
+		{name: "negative min instances", nodePool: NodePool{NumInstances: 1, MinInstances: -1, MaxInstances: 3}, valid: false},
+		{name: "negative max instances", nodePool: NodePool{NumInstances: 1, MaxInstances: -1}, valid: false},
+		{name: "min greater than max", nodePool: NodePool{NumInstances: 3, MinInstances: 4, MaxInstances: 3}, valid: false},
+		{name: "min without max", nodePool: NodePool{NumInstances: 3, MinInstances: 1}, valid: false},
+		{name: "num below min", nodePool: NodePool{NumInstances: 0, MinInstances: 1, MaxInstances: 3}, valid: false},
+		{name: "num above max", nodePool: NodePool{NumInstances: 4, MinInstances: 1, MaxInstances: 3}, valid: false},
+	}
+
+	for _, test := range tests {
+		err := test.nodePool.Validate()
+		if test.valid && err != nil {
+			t.Errorf("expected %v to be valid but got %v", test.name, err)
+		}
+
+		if !test.valid && err == nil {
+			t.Errorf("expected error for %v", test.name)
+		}
+	}
+}
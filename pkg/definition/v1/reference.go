@@ -93,6 +93,23 @@ func (r *Reference) UnmarshalJSON(data []byte) error {
 		}
 
 		sr := &SecretRef{Value: p}
+		if pv, ok := m["provider"]; ok {
+			provider, ok := pv.(string)
+			if !ok {
+				return fmt.Errorf("expected secret provider to be string")
+			}
+			sr.Provider = provider
+		}
+
+		if vv, ok := m["version"]; ok {
+			// json numbers are decoded as float64s
+			version, ok := vv.(float64)
+			if !ok || version < 1 || version != float64(int32(version)) {
+				return fmt.Errorf("expected secret version to be a positive integer")
+			}
+			sr.Version = int32(version)
+		}
+
 		e.Parameters[k] = sr
 	}
 
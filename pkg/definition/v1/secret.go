@@ -8,6 +8,14 @@ import (
 
 type SecretRef struct {
 	Value tree.PathSubcomponent `json:"$secret_ref"`
+
+	// Provider is the name of the secret provider the secret should be retrieved
+	// from. If empty, the system's default secret provider is used.
+	Provider string `json:"provider,omitempty"`
+
+	// Version pins the reference to a version of the secret. If zero,
+	// the secret's current version is used.
+	Version int32 `json:"version,omitempty"`
 }
 
 // ValueOrSecret contains either a value (i.e. just a string value), or a Secret.
@@ -25,6 +25,10 @@ type Service struct {
 	Container
 	Sidecars map[string]Container
 
+	// Preemptible is whether the service tolerates its instances being
+	// interrupted, which allows them to run on spot instances.
+	Preemptible bool
+
 	// FIXME: remove these
 	NumInstances int32
 	NodePool     *NodePoolOrReference
@@ -70,6 +74,8 @@ func (s *Service) MarshalJSON() ([]byte, error) {
 		Container: s.Container,
 		Sidecars:  s.Sidecars,
 
+		Preemptible: s.Preemptible,
+
 		NumInstances: s.NumInstances,
 		NodePool:     s.NodePool,
 		InstanceType: s.InstanceType,
@@ -97,6 +103,8 @@ func (s *Service) UnmarshalJSON(data []byte) error {
 		Container: e.Container,
 		Sidecars:  e.Sidecars,
 
+		Preemptible: e.Preemptible,
+
 		NumInstances: e.NumInstances,
 		NodePool:     e.NodePool,
 		InstanceType: e.InstanceType,
@@ -112,6 +120,8 @@ type serviceEncoder struct {
 	Container
 	Sidecars map[string]Container `json:"sidecars,omitempty"`
 
+	Preemptible bool `json:"preemptible,omitempty"`
+
 	NumInstances int32                `json:"num_instances,omitempty"`
 	NodePool     *NodePoolOrReference `json:"node_pool,omitempty"`
 	InstanceType *string              `json:"instance_type,omitempty"`
@@ -19,6 +19,10 @@ var SystemType = definition.Type{
 type System struct {
 	Description string
 
+	// SecretProvider is the default secret provider for secret references
+	// in the system that do not name their own provider.
+	SecretProvider string
+
 	Components map[string]definition.Component
 	// FIXME: remove this
 	NodePools map[string]NodePool
@@ -33,6 +37,8 @@ func (s *System) MarshalJSON() ([]byte, error) {
 		Type:        SystemType,
 		Description: s.Description,
 
+		SecretProvider: s.SecretProvider,
+
 		Components: s.Components,
 		NodePools:  s.NodePools,
 	}
@@ -66,6 +72,8 @@ func (s *System) UnmarshalJSON(data []byte) error {
 	system := &System{
 		Description: e.Description,
 
+		SecretProvider: e.SecretProvider,
+
 		Components: components,
 		NodePools:  e.NodePools,
 	}
@@ -77,6 +85,8 @@ type systemEncoder struct {
 	Type        definition.Type `json:"type"`
 	Description string          `json:"description,omitempty"`
 
+	SecretProvider string `json:"secret_provider,omitempty"`
+
 	Components map[string]definition.Component `json:"components"`
 	NodePools  map[string]NodePool             `json:"node_pools,omitempty"`
 }
@@ -85,6 +95,8 @@ type systemDecoder struct {
 	Type        definition.Type `json:"type"`
 	Description string          `json:"description,omitempty"`
 
+	SecretProvider string `json:"secret_provider,omitempty"`
+
 	Components map[string]json.RawMessage `json:"components"`
 	NodePools  map[string]NodePool        `json:"node_pools,omitempty"`
 }
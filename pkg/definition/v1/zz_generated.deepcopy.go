@@ -30,6 +30,15 @@ func (in *Container) DeepCopyInto(out *Container) {
 			(*in).DeepCopyInto(*out)
 		}
 	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make(map[string]ContainerFile, len(*in))
+		for key, val := range *in {
+			newVal := new(ContainerFile)
+			val.DeepCopyInto(newVal)
+			(*out)[key] = *newVal
+		}
+	}
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
 		*out = make(map[int32]ContainerPort, len(*in))
@@ -54,7 +63,7 @@ func (in *Container) DeepCopyInto(out *Container) {
 			*out = nil
 		} else {
 			*out = new(ContainerResources)
-			**out = **in
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	return
@@ -251,6 +260,65 @@ func (in *ContainerExec) DeepCopy() *ContainerExec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerFile) DeepCopyInto(out *ContainerFile) {
+	*out = *in
+	if in.Content != nil {
+		in, out := &in.Content, &out.Content
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ValueOrSecret)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerFileTemplate)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerFile.
+func (in *ContainerFile) DeepCopy() *ContainerFile {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerFileTemplate) DeepCopyInto(out *ContainerFileTemplate) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]ValueOrSecret, len(*in))
+		for key, val := range *in {
+			newVal := new(ValueOrSecret)
+			val.DeepCopyInto(newVal)
+			(*out)[key] = *newVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerFileTemplate.
+func (in *ContainerFileTemplate) DeepCopy() *ContainerFileTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerFileTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerHealthCheck) DeepCopyInto(out *ContainerHealthCheck) {
 	*out = *in
@@ -295,6 +363,15 @@ func (in *ContainerHealthCheckHTTP) DeepCopy() *ContainerHealthCheckHTTP {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerPort) DeepCopyInto(out *ContainerPort) {
 	*out = *in
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerPortGRPC)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	if in.ExternalAccess != nil {
 		in, out := &in.ExternalAccess, &out.ExternalAccess
 		if *in == nil {
@@ -333,9 +410,64 @@ func (in *ContainerPortExternalAccess) DeepCopy() *ContainerPortExternalAccess {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerPortGRPC) DeepCopyInto(out *ContainerPortGRPC) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerPortGRPC.
+func (in *ContainerPortGRPC) DeepCopy() *ContainerPortGRPC {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerPortGRPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerResourceList) DeepCopyInto(out *ContainerResourceList) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResourceList.
+func (in *ContainerResourceList) DeepCopy() *ContainerResourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerResourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerResources) DeepCopyInto(out *ContainerResources) {
 	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerResourceList)
+			**out = **in
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ContainerResourceList)
+			**out = **in
+		}
+	}
 	return
 }
 
@@ -613,6 +745,20 @@ func (in *Location) DeepCopy() *Location {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePool) DeepCopyInto(out *NodePool) {
 	*out = *in
+	if in.InstanceTypes != nil {
+		in, out := &in.InstanceTypes, &out.InstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Spot != nil {
+		in, out := &in.Spot, &out.Spot
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(NodePoolSpot)
+			**out = **in
+		}
+	}
 	return
 }
 
@@ -635,7 +781,7 @@ func (in *NodePoolOrReference) DeepCopyInto(out *NodePoolOrReference) {
 			*out = nil
 		} else {
 			*out = new(NodePool)
-			**out = **in
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	if in.NodePoolPath != nil {
@@ -660,6 +806,22 @@ func (in *NodePoolOrReference) DeepCopy() *NodePoolOrReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolSpot) DeepCopyInto(out *NodePoolSpot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpot.
+func (in *NodePoolSpot) DeepCopy() *NodePoolSpot {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolSpot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Reference) DeepCopyInto(out *Reference) {
 	*out = *in
@@ -799,7 +961,9 @@ func (in *System) DeepCopyInto(out *System) {
 		in, out := &in.NodePools, &out.NodePools
 		*out = make(map[string]NodePool, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			newVal := new(NodePool)
+			val.DeepCopyInto(newVal)
+			(*out)[key] = *newVal
 		}
 	}
 	return
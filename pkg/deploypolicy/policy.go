@@ -0,0 +1,186 @@
+package deploypolicy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+)
+
+// maxChangeWindowDuration bounds how far back windows have to be searched for
+// when checking whether a time is in them.
+const maxChangeWindowDuration = 7 * 24 * time.Hour
+
+// Validate returns an error if the policy is invalid.
+func Validate(policy *v1.DeployPolicy) error {
+	if policy.Approval != nil && policy.Approval.Approvals < 0 {
+		return fmt.Errorf("approvals must not be negative")
+	}
+
+	for i, window := range policy.ChangeWindows {
+		if _, _, _, err := parseChangeWindow(window); err != nil {
+			return fmt.Errorf("change window %v: %v", i, err)
+		}
+	}
+
+	for i, freeze := range policy.Freezes {
+		if !freeze.End.After(freeze.Start.Time) {
+			return fmt.Errorf("freeze %v: end must be after start", i)
+		}
+	}
+
+	return nil
+}
+
+// RequiresApproval returns whether a deploy created at t has to be approved
+// under the policy, and if so why.
+func RequiresApproval(policy *v1.DeployPolicy, t time.Time) (bool, string, error) {
+	for _, freeze := range policy.Freezes {
+		if t.Before(freeze.Start.Time) || !t.Before(freeze.End.Time) {
+			continue
+		}
+
+		reason := fmt.Sprintf("created during a deploy freeze ending %v", freeze.End.UTC().Format(time.RFC3339))
+		if freeze.Reason != "" {
+			reason = fmt.Sprintf("%v (%v)", reason, freeze.Reason)
+		}
+		return true, reason, nil
+	}
+
+	if policy.Approval != nil && policy.Approval.Always {
+		return true, "the deploy policy requires every deploy to be approved", nil
+	}
+
+	if len(policy.ChangeWindows) == 0 {
+		return false, "", nil
+	}
+
+	for _, window := range policy.ChangeWindows {
+		in, err := inChangeWindow(window, t)
+		if err != nil {
+			return false, "", err
+		}
+
+		if in {
+			return false, "", nil
+		}
+	}
+
+	return true, "created outside of the deploy policy's change windows", nil
+}
+
+// CanDecide returns an error if the user with the given roles cannot approve
+// or reject a deploy that was created by creator and has already received the
+// approvals.
+func CanDecide(
+	policy *v1.DeployPolicy,
+	creator string,
+	approvals []v1.DeployApproval,
+	user string,
+	roles []string,
+) error {
+	// anonymous decisions could not be told apart, so one caller could
+	// approve a deploy many times
+	if user == "" {
+		return fmt.Errorf("only authenticated users can approve or reject deploys")
+	}
+
+	if user == creator {
+		return fmt.Errorf("%v created the deploy so cannot approve or reject it", user)
+	}
+
+	for _, approval := range approvals {
+		if approval.User == user {
+			return fmt.Errorf("%v has already %v the deploy", user, approval.Decision)
+		}
+	}
+
+	if policy.Approval == nil || len(policy.Approval.Users) == 0 && len(policy.Approval.Roles) == 0 {
+		return nil
+	}
+
+	for _, u := range policy.Approval.Users {
+		if u == user {
+			return nil
+		}
+	}
+
+	for _, role := range policy.Approval.Roles {
+		for _, r := range roles {
+			if r == role {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%v is not an approver of the system's deploys", user)
+}
+
+// Decide returns whether a deploy has received enough approvals to run under
+// the policy. Approvals are counted by distinct approver, and approvals without
+// an approver are not counted. If the deploy was rejected the rejection is
+// returned instead.
+func Decide(policy *v1.DeployPolicy, approvals []v1.DeployApproval) (bool, *v1.DeployApproval) {
+	required := 1
+	if policy.Approval != nil && policy.Approval.Approvals > 0 {
+		required = int(policy.Approval.Approvals)
+	}
+
+	approvers := make(map[string]bool)
+	for i, approval := range approvals {
+		switch approval.Decision {
+		case v1.DeployApprovalDecisionRejected:
+			return false, &approvals[i]
+
+		case v1.DeployApprovalDecisionApproved:
+			if approval.User != "" {
+				approvers[approval.User] = true
+			}
+		}
+	}
+
+	return len(approvers) >= required, nil
+}
+
+func inChangeWindow(window v1.DeployChangeWindow, t time.Time) (bool, error) {
+	s, duration, location, err := parseChangeWindow(window)
+	if err != nil {
+		return false, err
+	}
+
+	// the window contains t if it opened at a minute less than its
+	// duration before t
+	t = t.In(location)
+	opened := t.Add(-duration)
+	for m := t.Truncate(time.Minute); m.After(opened); m = m.Add(-time.Minute) {
+		if s.matches(m) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func parseChangeWindow(window v1.DeployChangeWindow) (*schedule, time.Duration, *time.Location, error) {
+	s, err := parseSchedule(window.Schedule)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid duration %q", window.Duration)
+	}
+
+	if duration <= 0 || duration > maxChangeWindowDuration {
+		return nil, 0, nil, fmt.Errorf("duration must be positive and at most %v", maxChangeWindowDuration)
+	}
+
+	// an empty timezone loads UTC
+	location, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid timezone %q", window.Timezone)
+	}
+
+	return s, duration, location, nil
+}
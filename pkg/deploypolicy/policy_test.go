@@ -0,0 +1,135 @@
+package deploypolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiresApproval(t *testing.T) {
+	policy := &v1.DeployPolicy{
+		ChangeWindows: []v1.DeployChangeWindow{
+			{
+				// weekdays from 9:00 to 17:00 new york time
+				Schedule: "0 9 * * 1-5",
+				Duration: "8h",
+				Timezone: "America/New_York",
+			},
+		},
+		Freezes: []v1.DeployFreeze{
+			{
+				Start:  *timeutil.New(time.Date(2018, time.December, 24, 0, 0, 0, 0, time.UTC)),
+				End:    *timeutil.New(time.Date(2018, time.December, 26, 0, 0, 0, 0, time.UTC)),
+				Reason: "holidays",
+			},
+		},
+	}
+	require.NoError(t, Validate(policy))
+
+	tests := []struct {
+		description string
+		time        time.Time
+		required    bool
+	}{
+		{
+			description: "in window",
+			// wednesday 10:00 in new york
+			time:     time.Date(2018, time.October, 17, 14, 0, 0, 0, time.UTC),
+			required: false,
+		},
+		{
+			description: "last minute of window",
+			time:        time.Date(2018, time.October, 17, 20, 59, 0, 0, time.UTC),
+			required:    false,
+		},
+		{
+			description: "window closed",
+			time:        time.Date(2018, time.October, 17, 21, 0, 0, 0, time.UTC),
+			required:    true,
+		},
+		{
+			description: "weekend",
+			time:        time.Date(2018, time.October, 20, 14, 0, 0, 0, time.UTC),
+			required:    true,
+		},
+		{
+			description: "frozen during window",
+			time:        time.Date(2018, time.December, 24, 15, 0, 0, 0, time.UTC),
+			required:    true,
+		},
+	}
+
+	for _, test := range tests {
+		required, reason, err := RequiresApproval(policy, test.time)
+		require.NoError(t, err, test.description)
+		require.Equal(t, test.required, required, test.description)
+		require.Equal(t, test.required, reason != "", test.description)
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	s, err := parseSchedule("*/15 0-6,22 1 * 0")
+	require.NoError(t, err)
+
+	// the first is a sunday, so matches either the day of month or week
+	require.True(t, s.matches(time.Date(2018, time.July, 1, 22, 45, 0, 0, time.UTC)))
+	require.True(t, s.matches(time.Date(2018, time.July, 8, 3, 0, 0, 0, time.UTC)))
+	require.True(t, s.matches(time.Date(2018, time.August, 1, 6, 30, 0, 0, time.UTC)))
+	require.False(t, s.matches(time.Date(2018, time.August, 1, 7, 30, 0, 0, time.UTC)))
+	require.False(t, s.matches(time.Date(2018, time.August, 2, 3, 0, 0, 0, time.UTC)))
+	require.False(t, s.matches(time.Date(2018, time.July, 8, 3, 10, 0, 0, time.UTC)))
+
+	for _, invalid := range []string{"* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "mon * * * *"} {
+		_, err := parseSchedule(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestCanDecide(t *testing.T) {
+	policy := &v1.DeployPolicy{
+		Approval: &v1.DeployApprovalPolicy{
+			Approvals: 2,
+			Users:     []string{"alice"},
+			Roles:     []string{"sre"},
+		},
+	}
+
+	approvals := []v1.DeployApproval{
+		{User: "alice", Decision: v1.DeployApprovalDecisionApproved},
+	}
+
+	require.Error(t, CanDecide(policy, "bob", nil, "bob", []string{"sre"}))
+	require.Error(t, CanDecide(policy, "bob", nil, "", []string{"sre"}))
+	require.Error(t, CanDecide(&v1.DeployPolicy{}, "", nil, "", nil))
+	require.Error(t, CanDecide(policy, "bob", approvals, "alice", nil))
+	require.Error(t, CanDecide(policy, "bob", approvals, "carol", []string{"dev"}))
+	require.NoError(t, CanDecide(policy, "bob", approvals, "carol", []string{"dev", "sre"}))
+
+	approved, rejection := Decide(policy, approvals)
+	require.False(t, approved)
+	require.Nil(t, rejection)
+
+	// repeated and anonymous approvals do not count towards the required approvals
+	repeated := append(approvals, []v1.DeployApproval{
+		{User: "alice", Decision: v1.DeployApprovalDecisionApproved},
+		{User: "", Decision: v1.DeployApprovalDecisionApproved},
+		{User: "", Decision: v1.DeployApprovalDecisionApproved},
+	}...)
+	approved, rejection = Decide(policy, repeated)
+	require.False(t, approved)
+	require.Nil(t, rejection)
+
+	approvals = append(approvals, v1.DeployApproval{User: "carol", Decision: v1.DeployApprovalDecisionApproved})
+	approved, rejection = Decide(policy, approvals)
+	require.True(t, approved)
+	require.Nil(t, rejection)
+
+	approvals = append(approvals, v1.DeployApproval{User: "dave", Decision: v1.DeployApprovalDecisionRejected})
+	approved, rejection = Decide(policy, approvals)
+	require.False(t, approved)
+	require.Equal(t, "dave", rejection.User)
+}
@@ -0,0 +1,160 @@
+package deploypolicy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule is a parsed cron expression. Each field is a bitset of the
+// values it matches.
+type schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// as in cron, if both the day of month and day of week are restricted
+	// a day matches if either of them does
+	dayOfMonthRestricted bool
+	dayOfWeekRestricted  bool
+}
+
+type scheduleField struct {
+	name string
+	min  int
+	max  int
+}
+
+var scheduleFields = []scheduleField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	// both 0 and 7 are sunday
+	{name: "day of week", min: 0, max: 7},
+}
+
+// parseSchedule parses a cron expression with five numeric fields. Fields
+// are comma separated lists of values, ranges ("1-5") and "*", each of which
+// can have a step ("*/15", "0-30/10").
+func parseSchedule(expression string) (*schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("expected %v fields in schedule %q but found %v", len(scheduleFields), expression, len(fields))
+	}
+
+	var bits []uint64
+	for i, field := range fields {
+		b, err := parseScheduleField(field, scheduleFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits = append(bits, b)
+	}
+
+	// fold sunday as 7 into sunday as 0
+	dayOfWeek := bits[4]
+	if dayOfWeek&(1<<7) != 0 {
+		dayOfWeek = dayOfWeek&^(1<<7) | 1
+	}
+
+	s := &schedule{
+		minute:               bits[0],
+		hour:                 bits[1],
+		dayOfMonth:           bits[2],
+		month:                bits[3],
+		dayOfWeek:            dayOfWeek,
+		dayOfMonthRestricted: !strings.HasPrefix(fields[2], "*"),
+		dayOfWeekRestricted:  !strings.HasPrefix(fields[4], "*"),
+	}
+	return s, nil
+}
+
+func parseScheduleField(field string, f scheduleField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart := part
+		step := 1
+
+		if i := strings.Index(part, "/"); i != -1 {
+			rangePart = part[:i]
+
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %v field %q", f.name, field)
+			}
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rangePart == "*":
+
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+
+			var err error
+			start, err = parseScheduleValue(bounds[0], f)
+			if err != nil {
+				return 0, err
+			}
+
+			end, err = parseScheduleValue(bounds[1], f)
+			if err != nil {
+				return 0, err
+			}
+
+			if end < start {
+				return 0, fmt.Errorf("invalid range in %v field %q", f.name, field)
+			}
+
+		default:
+			var err error
+			start, err = parseScheduleValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+
+			// a single value with a step runs from the value to the maximum
+			if step == 1 {
+				end = start
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseScheduleValue(value string, f scheduleField) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %v %q, must be between %v and %v", f.name, value, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// matches returns whether the schedule fires at the minute containing t,
+// in t's location.
+func (s *schedule) matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 ||
+		s.hour&(1<<uint(t.Hour())) == 0 ||
+		s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.dayOfMonthRestricted && s.dayOfWeekRestricted {
+		return dayOfMonth || dayOfWeek
+	}
+
+	return dayOfMonth && dayOfWeek
+}
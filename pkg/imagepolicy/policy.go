@@ -0,0 +1,284 @@
+package imagepolicy
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+)
+
+const dockerHubRegistry = "docker.io"
+
+// IsEmpty returns whether the policy allows any image to be used.
+func IsEmpty(policy *v1.ImagePolicy) bool {
+	return len(policy.AllowedRegistries) == 0 && !policy.RequireDigest && len(policy.SignatureKeys) == 0
+}
+
+// Validate returns an error if the policy's registries or signature keys are invalid.
+func Validate(policy *v1.ImagePolicy) error {
+	for _, allowed := range policy.AllowedRegistries {
+		host := strings.TrimPrefix(allowed, "*.")
+		if host == "" || strings.ContainsAny(host, "*/") {
+			return fmt.Errorf("invalid allowed registry %v", allowed)
+		}
+	}
+
+	for _, key := range policy.SignatureKeys {
+		if _, err := tree.NewPathSubcomponent(key.Secret.String()); err != nil {
+			return fmt.Errorf("invalid signature key %v: %v", key.Secret.String(), err)
+		}
+	}
+
+	return nil
+}
+
+// ViolationError is returned when an image does not satisfy the policy.
+// Path is the workload using the image, if known.
+type ViolationError struct {
+	Path   tree.Path
+	Image  string
+	Reason string
+}
+
+func (e *ViolationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("image %v violates the image policy: %v", e.Image, e.Reason)
+	}
+	return fmt.Sprintf("image %v used by %v violates the image policy: %v", e.Image, e.Path.String(), e.Reason)
+}
+
+// Verifier checks images against a policy.
+type Verifier struct {
+	policy *v1.ImagePolicy
+	keys   []*ecdsa.PublicKey
+
+	// newClient returns the client used to retrieve images and their
+	// signatures from the registry
+	newClient func(registry string) *registry.Client
+}
+
+// NewVerifier returns a verifier for the policy. keys are the PEM encoded
+// values of the policy's signature keys.
+func NewVerifier(policy *v1.ImagePolicy, keys [][]byte, newClient func(registry string) *registry.Client) (*Verifier, error) {
+	v := &Verifier{
+		policy:    policy,
+		newClient: newClient,
+	}
+
+	for i, data := range keys {
+		key, err := ParsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature key %v: %v", i, err)
+		}
+
+		v.keys = append(v.keys, key)
+	}
+
+	return v, nil
+}
+
+// VerifyDefinition verifies the images pulled by the builds of each of the tree's
+// workloads' containers.
+func (v *Verifier) VerifyDefinition(t *resolver.ResolutionTree) error {
+	return v.verifyDefinition(t, false)
+}
+
+// PinDefinition verifies the tree's images like VerifyDefinition, and pins each image
+// referenced by tag to the digest that was verified, so that moving the tag after
+// the image was verified does not change the image that is built and run.
+func (v *Verifier) PinDefinition(t *resolver.ResolutionTree) error {
+	return v.verifyDefinition(t, true)
+}
+
+func (v *Verifier) verifyDefinition(t *resolver.ResolutionTree, pin bool) error {
+	var err error
+	t.V1().Workloads(func(path tree.Path, workload definitionv1.Workload, info *resolver.ResolutionInfo) tree.WalkContinuation {
+		err = v.verifyWorkload(workload, pin)
+		if err != nil {
+			if violation, ok := err.(*ViolationError); ok {
+				violation.Path = path
+			}
+			return tree.HaltWalk
+		}
+		return tree.ContinueWalk
+	})
+	return err
+}
+
+// verifyWorkload verifies the images pulled by the builds of the workload's containers.
+func (v *Verifier) verifyWorkload(workload definitionv1.Workload, pin bool) error {
+	containers := workload.Containers()
+	if containers.Main.Build != nil {
+		if err := v.verifyContainerBuild(containers.Main.Build, pin); err != nil {
+			return err
+		}
+	}
+
+	for _, sidecar := range containers.Sidecars {
+		if sidecar.Build == nil {
+			continue
+		}
+
+		if err := v.verifyContainerBuild(sidecar.Build, pin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyContainerBuild verifies the images that the container build pulls: the image
+// of a docker image build and the base image of a command build. The base images of
+// docker builds are named in their Dockerfile, so cannot be verified before the build.
+func (v *Verifier) verifyContainerBuild(build *definitionv1.ContainerBuild, pin bool) error {
+	var image *definitionv1.DockerImage
+	switch {
+	case build.DockerImage != nil:
+		image = build.DockerImage
+
+	case build.CommandBuild != nil:
+		image = &build.CommandBuild.BaseImage
+
+	default:
+		return nil
+	}
+
+	digest, err := v.VerifyImage(image)
+	if err != nil {
+		return err
+	}
+
+	if pin {
+		image.Digest = digest
+	}
+	return nil
+}
+
+// VerifyImage checks that the image is from an allowed registry, is pinned to a
+// digest if required, and is signed by one of the policy's keys if any are set.
+// It returns the digest of the image that was verified, which is empty if the
+// image is referenced by tag and the policy has no signature keys.
+func (v *Verifier) VerifyImage(image *definitionv1.DockerImage) (string, error) {
+	name := imageName(image)
+
+	if !v.registryAllowed(image.Registry) {
+		return "", v.registryViolation(name, image.Registry)
+	}
+
+	if v.policy.RequireDigest && image.Digest == "" {
+		return "", &ViolationError{Image: name, Reason: "image must be referenced by digest"}
+	}
+
+	if len(v.keys) == 0 {
+		return image.Digest, nil
+	}
+
+	client := v.newClient(image.Registry)
+
+	// signatures are made over the digest, so resolve the tag if the image isn't pinned
+	digest := image.Digest
+	if digest == "" {
+		tag := image.Tag
+		if tag == "" {
+			tag = "latest"
+		}
+
+		var err error
+		digest, err = client.ManifestDigest(image.Repository, tag)
+		if err != nil {
+			return "", fmt.Errorf("error resolving digest of %v: %v", name, err)
+		}
+	}
+
+	if err := v.verifySignature(client, name, image.Repository, digest); err != nil {
+		return "", err
+	}
+
+	return digest, nil
+}
+
+// VerifyBuiltImage checks an image produced by the builder, which is always pushed
+// to the builder's registry.
+func (v *Verifier) VerifyBuiltImage(dockerImageFQN, digest string) error {
+	registry := imageRegistry(dockerImageFQN)
+	if !v.registryAllowed(registry) {
+		return v.registryViolation(dockerImageFQN, registry)
+	}
+
+	if v.policy.RequireDigest && digest == "" {
+		return &ViolationError{Image: dockerImageFQN, Reason: "built image was not pushed with a digest"}
+	}
+
+	return nil
+}
+
+func (v *Verifier) registryViolation(image, registry string) *ViolationError {
+	reason := fmt.Sprintf("registry %v is not one of the allowed registries (%v)",
+		canonicalRegistry(registry),
+		strings.Join(v.policy.AllowedRegistries, ", "),
+	)
+	return &ViolationError{Image: image, Reason: reason}
+}
+
+func (v *Verifier) registryAllowed(registry string) bool {
+	if len(v.policy.AllowedRegistries) == 0 {
+		return true
+	}
+
+	registry = canonicalRegistry(registry)
+	for _, allowed := range v.policy.AllowedRegistries {
+		allowed = canonicalRegistry(allowed)
+		if allowed == registry {
+			return true
+		}
+
+		if strings.HasPrefix(allowed, "*.") && strings.HasSuffix(registry, allowed[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canonicalRegistry returns the name of the registry, treating the
+// different names of docker hub as the same registry.
+func canonicalRegistry(registry string) string {
+	switch registry {
+	case "", "index.docker.io", "registry-1.docker.io":
+		return dockerHubRegistry
+	}
+	return registry
+}
+
+// imageRegistry returns the registry of the image reference, which is empty for
+// images on docker hub. Like docker, the first component of the reference is only
+// a registry if it looks like a host name.
+func imageRegistry(reference string) string {
+	parts := strings.SplitN(reference, "/", 2)
+	if len(parts) == 1 {
+		return ""
+	}
+
+	host := parts[0]
+	if host != "localhost" && !strings.ContainsAny(host, ".:") {
+		return ""
+	}
+	return host
+}
+
+func imageName(image *definitionv1.DockerImage) string {
+	name := image.Repository
+	if image.Registry != "" {
+		name = fmt.Sprintf("%v/%v", image.Registry, image.Repository)
+	}
+
+	if image.Digest != "" {
+		return fmt.Sprintf("%v@%v", name, image.Digest)
+	}
+	return fmt.Sprintf("%v:%v", name, image.Tag)
+}
@@ -0,0 +1,282 @@
+package imagepolicy
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+	"github.com/mlab-lattice/lattice/pkg/util/registry/registrytest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyImage(t *testing.T) {
+	reg := registrytest.NewRegistry()
+	defer reg.Close()
+
+	client := registry.NewClient(reg.Host(), nil)
+	newClient := func(string) *registry.Client { return client }
+
+	signed := pushImage(t, client, "signed", "v1")
+	unsigned := pushImage(t, client, "unsigned", "v1")
+
+	trusted := generateKey(t)
+	untrusted := generateKey(t)
+	sign(t, client, "signed", signed, trusted)
+
+	policy := &v1.ImagePolicy{
+		AllowedRegistries: []string{reg.Host()},
+		SignatureKeys:     []v1.ImagePolicySignatureKey{{Secret: "/:image-key"}},
+	}
+	verifier, err := NewVerifier(policy, [][]byte{publicKeyPEM(t, trusted)}, newClient)
+	require.NoError(t, err)
+
+	image := func(repository, tag, digest string) *definitionv1.DockerImage {
+		return &definitionv1.DockerImage{
+			Registry:   reg.Host(),
+			Repository: repository,
+			Tag:        tag,
+			Digest:     digest,
+		}
+	}
+
+	// images referenced by tag are verified by the digest the tag points to
+	digest, err := verifier.VerifyImage(image("signed", "v1", ""))
+	require.NoError(t, err)
+	require.Equal(t, signed, digest)
+
+	digest, err = verifier.VerifyImage(image("signed", "", signed))
+	require.NoError(t, err)
+	require.Equal(t, signed, digest)
+
+	requireViolation(t, verifyImage(verifier, image("unsigned", "v1", "")), "image is not signed")
+	requireViolation(t, verifyImage(verifier, image("unsigned", "", unsigned)), "image is not signed")
+
+	// a signature of another image must not be accepted
+	sign(t, client, "unsigned", signed, trusted)
+	requireViolation(t, verifyImage(verifier, image("unsigned", "", unsigned)), "image is not signed")
+
+	untrustedVerifier, err := NewVerifier(policy, [][]byte{publicKeyPEM(t, untrusted)}, newClient)
+	require.NoError(t, err)
+	requireViolation(
+		t,
+		verifyImage(untrustedVerifier, image("signed", "v1", "")),
+		"image is not signed by any of the trusted keys",
+	)
+
+	requireViolation(
+		t,
+		verifyImage(verifier, &definitionv1.DockerImage{Repository: "library/alpine", Tag: "3.8"}),
+		"registry docker.io is not one of the allowed registries",
+	)
+
+	policy.RequireDigest = true
+	requireViolation(t, verifyImage(verifier, image("signed", "v1", "")), "image must be referenced by digest")
+	require.NoError(t, verifyImage(verifier, image("signed", "", signed)))
+
+	builtImage := reg.Host() + "/builds/service:abc"
+	requireViolation(t, verifier.VerifyBuiltImage(builtImage, ""), "built image was not pushed with a digest")
+	require.NoError(t, verifier.VerifyBuiltImage(builtImage, signed))
+	requireViolation(
+		t,
+		verifier.VerifyBuiltImage("registry.example.com/builds/service:abc", signed),
+		"registry registry.example.com is not one of the allowed registries",
+	)
+}
+
+func TestPinContainerBuild(t *testing.T) {
+	reg := registrytest.NewRegistry()
+	defer reg.Close()
+
+	client := registry.NewClient(reg.Host(), nil)
+	newClient := func(string) *registry.Client { return client }
+
+	key := generateKey(t)
+	signed := pushImage(t, client, "signed", "v1")
+	sign(t, client, "signed", signed, key)
+
+	policy := &v1.ImagePolicy{
+		SignatureKeys: []v1.ImagePolicySignatureKey{{Secret: "/:image-key"}},
+	}
+	verifier, err := NewVerifier(policy, [][]byte{publicKeyPEM(t, key)}, newClient)
+	require.NoError(t, err)
+
+	build := &definitionv1.ContainerBuild{
+		DockerImage: &definitionv1.DockerImage{
+			Registry:   reg.Host(),
+			Repository: "signed",
+			Tag:        "v1",
+		},
+	}
+
+	require.NoError(t, verifier.verifyContainerBuild(build, false))
+	require.Empty(t, build.DockerImage.Digest)
+
+	require.NoError(t, verifier.verifyContainerBuild(build, true))
+	require.Equal(t, signed, build.DockerImage.Digest)
+
+	// once pinned, moving the tag to an unsigned image doesn't change the image that is verified
+	unsigned := pushImage(t, client, "signed", "v2")
+	moved, _, err := client.Manifest("signed", unsigned)
+	require.NoError(t, err)
+	_, err = client.PutManifest("signed", "v1", moved)
+	require.NoError(t, err)
+
+	require.NoError(t, verifier.verifyContainerBuild(build, true))
+	require.Equal(t, signed, build.DockerImage.Digest)
+
+	build.DockerImage.Digest = ""
+	requireViolation(t, verifier.verifyContainerBuild(build, true), "image is not signed")
+}
+
+func TestValidate(t *testing.T) {
+	valid := &v1.ImagePolicy{
+		AllowedRegistries: []string{"docker.io", "*.gcr.io", "localhost:5000"},
+		SignatureKeys:     []v1.ImagePolicySignatureKey{{Secret: "/:image-key"}},
+	}
+	require.NoError(t, Validate(valid))
+	require.NoError(t, Validate(&v1.ImagePolicy{}))
+
+	invalid := []*v1.ImagePolicy{
+		{AllowedRegistries: []string{""}},
+		{AllowedRegistries: []string{"*."}},
+		{AllowedRegistries: []string{"*.*.gcr.io"}},
+		{AllowedRegistries: []string{"gcr.io/project"}},
+		{SignatureKeys: []v1.ImagePolicySignatureKey{{Secret: tree.PathSubcomponent("image-key")}}},
+	}
+	for _, policy := range invalid {
+		require.Error(t, Validate(policy), "%+v", policy)
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	require.Equal(t, "", imageRegistry("alpine:3.8"))
+	require.Equal(t, "", imageRegistry("library/alpine:3.8"))
+	require.Equal(t, "gcr.io", imageRegistry("gcr.io/project/image:v1"))
+	require.Equal(t, "localhost", imageRegistry("localhost/image:v1"))
+	require.Equal(t, "127.0.0.1:5000", imageRegistry("127.0.0.1:5000/builds/service@sha256:abc"))
+}
+
+func TestRegistryAllowed(t *testing.T) {
+	verifier, err := NewVerifier(&v1.ImagePolicy{AllowedRegistries: []string{"docker.io", "*.gcr.io"}}, nil, nil)
+	require.NoError(t, err)
+
+	require.True(t, verifier.registryAllowed(""))
+	require.True(t, verifier.registryAllowed("registry-1.docker.io"))
+	require.True(t, verifier.registryAllowed("us.gcr.io"))
+	require.False(t, verifier.registryAllowed("gcr.io"))
+	require.False(t, verifier.registryAllowed("quay.io"))
+
+	verifier, err = NewVerifier(&v1.ImagePolicy{}, nil, nil)
+	require.NoError(t, err)
+	require.True(t, verifier.registryAllowed("quay.io"))
+}
+
+func verifyImage(verifier *Verifier, image *definitionv1.DockerImage) error {
+	_, err := verifier.VerifyImage(image)
+	return err
+}
+
+func requireViolation(t *testing.T, err error, reason string) {
+	require.Error(t, err)
+	violation, ok := err.(*ViolationError)
+	require.True(t, ok, "expected a policy violation, got %v", err)
+	require.Contains(t, violation.Reason, reason)
+}
+
+func pushImage(t *testing.T, client *registry.Client, repository, tag string) string {
+	data, err := json.Marshal(&registry.ImageConfig{
+		Architecture: "amd64",
+		OS:           "linux",
+		Config:       registry.ContainerConfig{Labels: map[string]string{"repository": repository, "tag": tag}},
+		RootFS:       registry.RootFS{Type: "layers"},
+	})
+	require.NoError(t, err)
+
+	config := pushBlob(t, client, repository, data)
+	config.MediaType = registry.MediaTypeDockerConfig
+
+	manifest := &registry.Manifest{
+		SchemaVersion: 2,
+		MediaType:     registry.MediaTypeDockerManifest,
+		Config:        config,
+	}
+	digest, err := client.PutManifest(repository, tag, manifest)
+	require.NoError(t, err)
+	return digest
+}
+
+// sign signs the digest the way cosign does.
+func sign(t *testing.T, client *registry.Client, repository, digest string, key *ecdsa.PrivateKey) {
+	payload := SignaturePayload{}
+	payload.Critical.Type = signatureType
+	payload.Critical.Identity.DockerReference = repository
+	payload.Critical.Image.DockerManifestDigest = digest
+
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+
+	signature, err := asn1.Marshal(struct{ R, S interface{} }{r, s})
+	require.NoError(t, err)
+
+	layer := pushBlob(t, client, repository, data)
+	layer.MediaType = SignatureMediaType
+	layer.Annotations = map[string]string{
+		SignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+	}
+
+	config := pushBlob(t, client, repository, []byte("{}"))
+	config.MediaType = registry.MediaTypeOCIConfig
+
+	manifest := &registry.Manifest{
+		SchemaVersion: 2,
+		MediaType:     registry.MediaTypeOCIManifest,
+		Config:        config,
+		Layers:        []registry.Descriptor{layer},
+	}
+	_, err = client.PutManifest(repository, SignatureTag(digest), manifest)
+	require.NoError(t, err)
+}
+
+func pushBlob(t *testing.T, client *registry.Client, repository string, data []byte) registry.Descriptor {
+	digest := registry.Digest(data)
+	open := func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	require.NoError(t, client.PushBlob(repository, digest, int64(len(data)), open))
+
+	return registry.Descriptor{
+		Size:   int64(len(data)),
+		Digest: digest,
+	}
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func publicKeyPEM(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -0,0 +1,157 @@
+package imagepolicy
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/util/registry"
+)
+
+// Signatures are stored the way cosign stores them: as an image in the signed image's
+// repository, tagged after the signed digest, whose layers are the signed payloads.
+const (
+	SignatureMediaType  = "application/vnd.dev.cosign.simplesigning.v1+json"
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	signatureTagSuffix = ".sig"
+	signatureType      = "cosign container image signature"
+)
+
+// SignaturePayload is the payload that is signed to sign an image.
+type SignaturePayload struct {
+	Critical SignaturePayloadCritical `json:"critical"`
+	Optional map[string]interface{}   `json:"optional"`
+}
+
+type SignaturePayloadCritical struct {
+	Identity SignaturePayloadIdentity `json:"identity"`
+	Image    SignaturePayloadImage    `json:"image"`
+	Type     string                   `json:"type"`
+}
+
+type SignaturePayloadIdentity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+type SignaturePayloadImage struct {
+	DockerManifestDigest string `json:"docker-manifest-digest"`
+}
+
+// SignatureTag returns the tag of the signatures of the image with the digest.
+func SignatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + signatureTagSuffix
+}
+
+// ParsePublicKey parses a PEM encoded ECDSA public key.
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an ECDSA public key, got %T", key)
+	}
+
+	return ecdsaKey, nil
+}
+
+func (v *Verifier) verifySignature(client *registry.Client, name, repository, digest string) error {
+	manifest, _, err := client.Manifest(repository, SignatureTag(digest))
+	if err != nil {
+		if _, ok := err.(*registry.NotFoundError); ok {
+			return &ViolationError{Image: name, Reason: "image is not signed"}
+		}
+		return fmt.Errorf("error retrieving signatures of %v: %v", name, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != SignatureMediaType {
+			continue
+		}
+
+		signature, ok := layer.Annotations[SignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		payload, err := signaturePayload(client, repository, layer.Digest)
+		if err != nil {
+			return fmt.Errorf("error retrieving signature payload of %v: %v", name, err)
+		}
+
+		if v.validSignature(payload, signature, digest) {
+			return nil
+		}
+	}
+
+	return &ViolationError{Image: name, Reason: "image is not signed by any of the trusted keys"}
+}
+
+func signaturePayload(client *registry.Client, repository, digest string) ([]byte, error) {
+	blob, err := client.Blob(repository, digest)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	payload, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	if registry.Digest(payload) != digest {
+		return nil, fmt.Errorf("payload does not match its digest %v", digest)
+	}
+
+	return payload, nil
+}
+
+// validSignature returns whether the signature of the payload was made by one of the
+// keys, and the payload signs the digest.
+func (v *Verifier) validSignature(payload []byte, signature, digest string) bool {
+	var p SignaturePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return false
+	}
+
+	if p.Critical.Type != signatureType || p.Critical.Image.DockerManifestDigest != digest {
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	var rs struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+		return false
+	}
+
+	hash := sha256.Sum256(payload)
+	for _, key := range v.keys {
+		if ecdsa.Verify(key, hash[:], rs.R, rs.S) {
+			return true
+		}
+	}
+
+	return false
+}
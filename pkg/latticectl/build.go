@@ -14,20 +14,23 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/printer"
+	"github.com/mlab-lattice/lattice/pkg/util/tar"
 )
 
 const (
+	buildLocalFlag   = "local"
 	buildPathFlag    = "path"
 	buildVersionFlag = "version"
 )
 
 var (
-	buildTypeFlags = []string{buildPathFlag, buildVersionFlag}
+	buildTypeFlags = []string{buildLocalFlag, buildPathFlag, buildVersionFlag}
 )
 
 func Build() *cli.Command {
 	var (
 		output  string
+		local   string
 		path    tree.Path
 		version string
 		watch   bool
@@ -43,6 +46,7 @@ func Build() *cli.Command {
 				},
 				printer.FormatTable,
 			),
+			buildLocalFlag:        &flags.String{Target: &local},
 			buildPathFlag:         &flags.Path{Target: &path},
 			buildVersionFlag:      &flags.String{Target: &version},
 			command.WatchFlagName: command.WatchFlag(&watch),
@@ -52,6 +56,9 @@ func Build() *cli.Command {
 		Run: func(ctx *command.SystemCommandContext, args []string, flags cli.Flags) error {
 			format := printer.Format(output)
 			switch {
+			case flags[buildLocalFlag].Set():
+				return BuildLocal(ctx.Client, ctx.System, local, os.Stdout, format, watch)
+
 			case flags[buildPathFlag].Set():
 				return BuildPath(ctx.Client, ctx.System, path, os.Stdout, format, watch)
 
@@ -68,6 +75,35 @@ func Build() *cli.Command {
 	return cmd.Command()
 }
 
+// BuildLocal uploads the working tree in the directory, including any uncommitted
+// changes, and builds the system from it. The build is a snapshot of the directory
+// and cannot be reproduced from the system's definition repository.
+func BuildLocal(
+	client client.Interface,
+	system v1.SystemID,
+	directory string,
+	w io.Writer,
+	f printer.Format,
+	watch bool,
+) error {
+	archive, err := tar.ArchiveDirectory(directory)
+	if err != nil {
+		return fmt.Errorf("error archiving %v: %v", directory, err)
+	}
+
+	source, err := client.V1().Systems().Builds(system).UploadSource(archive)
+	if err != nil {
+		return err
+	}
+
+	build, err := client.V1().Systems().Builds(system).CreateFromSource(source.ID)
+	if err != nil {
+		return err
+	}
+
+	return displayBuild(client, system, build, fmt.Sprintf("local snapshot of %v", directory), w, f, watch)
+}
+
 func BuildPath(
 	client client.Interface,
 	system v1.SystemID,
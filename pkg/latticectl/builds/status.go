@@ -133,6 +133,9 @@ func buildString(build *v1.Build) string {
 
 	case build.Version != nil:
 		spec = fmt.Sprintf("version %s", *build.Version)
+
+	case build.Source != nil:
+		spec = fmt.Sprintf("local source %s", build.Source.ID)
 	}
 
 	stateColor := color.BoldString
@@ -183,6 +186,13 @@ func buildString(build *v1.Build) string {
 		)
 	}
 
+	if build.Source != nil {
+		additional += fmt.Sprintf(`
+  source: %s (local snapshot, not reproducible)`,
+			build.Source.Digest,
+		)
+	}
+
 	if len(build.Status.Workloads) != 0 {
 		additional += `
   workloads:`
@@ -204,9 +214,10 @@ func buildString(build *v1.Build) string {
 		mainColor := containerBuildColor(workload.Status.State)
 		additional += mainColor(
 			fmt.Sprintf(`
-    %v%v`,
+    %v%v%v`,
 				path,
 				mainDescriptor,
+				reuseString(workload.Reuse),
 			),
 		)
 
@@ -214,9 +225,10 @@ func buildString(build *v1.Build) string {
 			sidecarColor := containerBuildColor(sidecarBuild.Status.State)
 			additional += sidecarColor(
 				fmt.Sprintf(`
-    %v (%v sidecar)`,
+    %v (%v sidecar)%v`,
 					path,
 					sidecar,
+					reuseString(sidecarBuild.Reuse),
 				),
 			)
 		}
@@ -232,6 +244,18 @@ func buildString(build *v1.Build) string {
 	)
 }
 
+// reuseString describes whether a container build was reused from a previous build or rebuilt.
+func reuseString(reuse *v1.ContainerBuildReuse) string {
+	if reuse == nil {
+		return ""
+	}
+
+	if reuse.Reused {
+		return fmt.Sprintf(": reused (%v)", reuse.Reason)
+	}
+	return fmt.Sprintf(": rebuilt (%v)", reuse.Reason)
+}
+
 func containerBuildColor(state v1.ContainerBuildState) color.Formatter {
 	switch state {
 	case v1.ContainerBuildStateSucceeded:
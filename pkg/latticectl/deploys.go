@@ -47,7 +47,9 @@ func Deploys() *cli.Command {
 			return PrintDeploys(ctx.Client, ctx.System, format, os.Stdout)
 		},
 		Subcommands: map[string]*cli.Command{
-			"status": deploys.Status(),
+			"approve": deploys.Approve(),
+			"reject":  deploys.Reject(),
+			"status":  deploys.Status(),
 		},
 	}
 
@@ -161,10 +163,16 @@ func deploysTableRows(deploys []v1.Deploy) [][]string {
 			completed = deploy.Status.CompletionTimestamp.Local().Format(time.RFC1123)
 		}
 
+		// queued deploys show how many deploys have to run before them
+		state := string(deploy.Status.State)
+		if deploy.Status.QueuePosition != nil {
+			state = fmt.Sprintf("%v (#%v)", state, *deploy.Status.QueuePosition)
+		}
+
 		rows = append(rows, []string{
 			color.IDString(string(deploy.ID)),
 			target,
-			stateColor(string(deploy.Status.State)),
+			stateColor(state),
 			started,
 			completed,
 		})
@@ -0,0 +1,73 @@
+package deploys
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+const (
+	commentFlagName = "comment"
+)
+
+func Approve() *cli.Command {
+	var comment string
+
+	cmd := Command{
+		Flags: map[string]cli.Flag{
+			commentFlagName: &flags.String{
+				Usage:  "comment recorded with the approval",
+				Target: &comment,
+			},
+		},
+		Run: func(ctx *DeployCommandContext, args []string, flags cli.Flags) error {
+			return ApproveDeploy(ctx.Client, ctx.System, ctx.Deploy, comment, os.Stdout)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func Reject() *cli.Command {
+	var comment string
+
+	cmd := Command{
+		Flags: map[string]cli.Flag{
+			commentFlagName: &flags.String{
+				Usage:  "comment recorded with the rejection",
+				Target: &comment,
+			},
+		},
+		Run: func(ctx *DeployCommandContext, args []string, flags cli.Flags) error {
+			return RejectDeploy(ctx.Client, ctx.System, ctx.Deploy, comment, os.Stdout)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func ApproveDeploy(client client.Interface, system v1.SystemID, id v1.DeployID, comment string, w io.Writer) error {
+	deploy, err := client.V1().Systems().Deploys(system).Approve(id, comment)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, color.BoldHiSuccessString(fmt.Sprintf("✓ approved deploy %v\n", deploy.ID)))
+	return nil
+}
+
+func RejectDeploy(client client.Interface, system v1.SystemID, id v1.DeployID, comment string, w io.Writer) error {
+	deploy, err := client.V1().Systems().Deploys(system).Reject(id, comment)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, color.BoldHiSuccessString(fmt.Sprintf("✓ rejected deploy %v\n", deploy.ID)))
+	return nil
+}
@@ -3,6 +3,7 @@ package deploys
 import (
 	"fmt"
 	"io"
+	"math"
 	"os"
 
 	"github.com/mlab-lattice/lattice/pkg/api/client"
@@ -137,7 +138,7 @@ func deployString(deploy *v1.Deploy) string {
 
 	stateColor := color.BoldString
 	switch deploy.Status.State {
-	case v1.DeployStatePending, v1.DeployStateAccepted, v1.DeployStateInProgress:
+	case v1.DeployStatePending, v1.DeployStateAwaitingApproval, v1.DeployStateQueued, v1.DeployStateAccepted, v1.DeployStateInProgress:
 		stateColor = color.BoldHiWarningString
 
 	case v1.DeployStateSucceeded:
@@ -155,6 +156,35 @@ func deployString(deploy *v1.Deploy) string {
 		)
 	}
 
+	if deploy.Status.QueuePosition != nil {
+		additional += fmt.Sprintf(`
+  queue position: %v`,
+			*deploy.Status.QueuePosition,
+		)
+	}
+
+	if deploy.CreatedBy != "" {
+		additional += fmt.Sprintf(`
+  created by: %v`,
+			deploy.CreatedBy,
+		)
+	}
+
+	for _, approval := range deploy.Approvals {
+		comment := ""
+		if approval.Comment != "" {
+			comment = fmt.Sprintf(" (%v)", approval.Comment)
+		}
+
+		additional += fmt.Sprintf(`
+  %v by: %v at %v%v`,
+			approval.Decision,
+			approval.User,
+			approval.Timestamp.Local().String(),
+			comment,
+		)
+	}
+
 	if deploy.Status.Build != nil {
 		additional += fmt.Sprintf(`
   build: %s`,
@@ -176,6 +206,26 @@ func deployString(deploy *v1.Deploy) string {
 		)
 	}
 
+	if deploy.Source != nil {
+		additional += fmt.Sprintf(`
+  source: %s (local snapshot, not reproducible)`,
+			deploy.Source.Digest,
+		)
+	}
+
+	if deploy.MonthlyCostDelta != nil {
+		sign := "+"
+		if *deploy.MonthlyCostDelta < 0 {
+			sign = "-"
+		}
+
+		additional += fmt.Sprintf(`
+  projected monthly cost change: %s$%.2f`,
+			sign,
+			math.Abs(*deploy.MonthlyCostDelta),
+		)
+	}
+
 	if deploy.Status.StartTimestamp != nil {
 		additional += fmt.Sprintf(`
   started: %v`,
@@ -0,0 +1,16 @@
+package latticectl
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/latticectl/nodepools"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+)
+
+func NodePools() *cli.Command {
+	return &cli.Command{
+		Subcommands: map[string]*cli.Command{
+			"list":   nodepools.List(),
+			"scale":  nodepools.Scale(),
+			"status": nodepools.Status(),
+		},
+	}
+}
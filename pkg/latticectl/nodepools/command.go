@@ -0,0 +1,58 @@
+package nodepools
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+const (
+	nodePoolFlagName = "node-pool"
+)
+
+type Command struct {
+	Name                   string
+	Short                  string
+	Args                   cli.Args
+	Flags                  cli.Flags
+	Run                    func(ctx *NodePoolCommandContext, args []string, flags cli.Flags) error
+	MutuallyExclusiveFlags [][]string
+	RequiredFlagSet        [][]string
+	Subcommands            map[string]*cli.Command
+}
+
+type NodePoolCommandContext struct {
+	*command.SystemCommandContext
+	NodePool tree.PathSubcomponent
+}
+
+func (c *Command) Command() *cli.Command {
+	if c.Flags == nil {
+		c.Flags = make(cli.Flags)
+	}
+
+	var nodePool tree.PathSubcomponent
+	c.Flags[nodePoolFlagName] = &flags.PathSubcomponent{
+		Required: true,
+		Target:   &nodePool,
+	}
+
+	cmd := &command.SystemCommand{
+		Short: c.Short,
+		Args:  c.Args,
+		Flags: c.Flags,
+		MutuallyExclusiveFlags: c.MutuallyExclusiveFlags,
+		RequiredFlagSet:        c.RequiredFlagSet,
+		Run: func(ctx *command.SystemCommandContext, args []string, f cli.Flags) error {
+			nodePoolCtx := &NodePoolCommandContext{
+				SystemCommandContext: ctx,
+				NodePool:             nodePool,
+			}
+			return c.Run(nodePoolCtx, args, f)
+		},
+		Subcommands: c.Subcommands,
+	}
+
+	return cmd.Command()
+}
@@ -0,0 +1,146 @@
+package nodepools
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/printer"
+)
+
+func List() *cli.Command {
+	var (
+		output string
+		watch  bool
+	)
+
+	cmd := command.SystemCommand{
+		Flags: map[string]cli.Flag{
+			command.OutputFlagName: command.OutputFlag(
+				&output,
+				[]printer.Format{
+					printer.FormatJSON,
+					printer.FormatTable,
+				},
+				printer.FormatTable,
+			),
+			command.WatchFlagName: command.WatchFlag(&watch),
+		},
+		Run: func(ctx *command.SystemCommandContext, args []string, flags cli.Flags) error {
+			format := printer.Format(output)
+
+			if watch {
+				return WatchNodePools(ctx.Client, ctx.System, os.Stdout, format)
+			}
+
+			return PrintNodePools(ctx.Client, ctx.System, os.Stdout, format)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func PrintNodePools(client client.Interface, system v1.SystemID, w io.Writer, f printer.Format) error {
+	nodePools, err := client.V1().Systems().NodePools(system).List()
+	if err != nil {
+		return err
+	}
+
+	switch f {
+	case printer.FormatTable:
+		t := nodePoolsTable(w)
+		r := nodePoolsTableRows(nodePools)
+		t.AppendRows(r)
+		t.Print()
+
+	case printer.FormatJSON:
+		j := printer.NewJSON(w)
+		j.Print(nodePools)
+
+	default:
+		return fmt.Errorf("unexpected format %v", f)
+	}
+
+	return nil
+}
+
+func WatchNodePools(client client.Interface, system v1.SystemID, w io.Writer, f printer.Format) error {
+	var handle func([]v1.NodePool)
+	switch f {
+	case printer.FormatTable:
+		t := nodePoolsTable(w)
+		handle = func(nodePools []v1.NodePool) {
+			r := nodePoolsTableRows(nodePools)
+			t.Overwrite(r)
+		}
+
+	case printer.FormatJSON:
+		j := printer.NewJSON(w)
+		handle = func(nodePools []v1.NodePool) {
+			j.Print(nodePools)
+		}
+
+	default:
+		return fmt.Errorf("unexpected format %v", f)
+	}
+
+	for {
+		nodePools, err := client.V1().Systems().NodePools(system).List()
+		if err != nil {
+			return err
+		}
+
+		handle(nodePools)
+
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func nodePoolsTable(w io.Writer) *printer.Table {
+	return printer.NewTable(w, []string{"PATH", "STATE", "INSTANCE TYPE", "INSTANCES", "OVERRIDE"})
+}
+
+func nodePoolsTableRows(nodePools []v1.NodePool) [][]string {
+	var rows [][]string
+	for _, nodePool := range nodePools {
+		override := "-"
+		if nodePool.Override != nil {
+			override = color.WarningString(fmt.Sprintf(
+				"%d (definition: %d)",
+				nodePool.Override.NumInstances,
+				nodePool.Override.DefinitionNumInstances,
+			))
+		}
+
+		rows = append(rows, []string{
+			color.IDString(nodePool.Path.String()),
+			nodePoolStateColor(nodePool.Status.State)(string(nodePool.Status.State)),
+			nodePool.Status.InstanceType,
+			fmt.Sprintf("%d/%d", nodePool.Status.NumInstances, nodePool.NumInstances),
+			override,
+		})
+	}
+
+	// sort the rows by node pool path
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	return rows
+}
+
+func nodePoolStateColor(state v1.NodePoolState) color.Formatter {
+	switch state {
+	case v1.NodePoolStateStable:
+		return color.SuccessString
+	case v1.NodePoolStateFailed, v1.NodePoolStateDeleting:
+		return color.FailureString
+	default:
+		return color.WarningString
+	}
+}
@@ -0,0 +1,75 @@
+package nodepools
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+const (
+	scaleNumInstancesFlag = "num-instances"
+	scaleClearFlag        = "clear"
+)
+
+var scaleFlags = []string{scaleNumInstancesFlag, scaleClearFlag}
+
+// Scale overrides the number of instances in a node pool. The override
+// lasts until the next deploy, or until it is cleared.
+func Scale() *cli.Command {
+	var (
+		numInstances  int32
+		clearOverride bool
+	)
+
+	cmd := Command{
+		Flags: map[string]cli.Flag{
+			scaleNumInstancesFlag: &flags.Int32{
+				Usage:  "number of instances to scale the node pool to until the next deploy",
+				Target: &numInstances,
+			},
+			scaleClearFlag: &flags.Bool{
+				Usage:  "remove the node pool's override, returning it to its definition",
+				Target: &clearOverride,
+			},
+		},
+		MutuallyExclusiveFlags: [][]string{scaleFlags},
+		RequiredFlagSet:        [][]string{scaleFlags},
+		Run: func(ctx *NodePoolCommandContext, args []string, f cli.Flags) error {
+			client := ctx.Client.V1().Systems().NodePools(ctx.System)
+
+			if clearOverride {
+				nodePool, err := client.ClearOverride(ctx.NodePool)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf(
+					"cleared override on node pool %v, scaling back to %v instances\n",
+					color.IDString(ctx.NodePool.String()),
+					nodePool.NumInstances,
+				)
+				return nil
+			}
+
+			if numInstances < 1 {
+				return fmt.Errorf("--%v must be at least 1", scaleNumInstancesFlag)
+			}
+
+			_, err := client.Override(ctx.NodePool, numInstances)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf(
+				"scaling node pool %v to %v instances until the next deploy\n",
+				color.IDString(ctx.NodePool.String()),
+				numInstances,
+			)
+			return nil
+		},
+	}
+
+	return cmd.Command()
+}
@@ -0,0 +1,212 @@
+package nodepools
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/printer"
+)
+
+func Status() *cli.Command {
+	var (
+		output string
+		watch  bool
+	)
+
+	cmd := Command{
+		Flags: map[string]cli.Flag{
+			command.OutputFlagName: command.OutputFlag(
+				&output,
+				[]printer.Format{
+					printer.FormatJSON,
+					printer.FormatTable,
+				},
+				printer.FormatTable,
+			),
+			command.WatchFlagName: command.WatchFlag(&watch),
+		},
+		Run: func(ctx *NodePoolCommandContext, args []string, flags cli.Flags) error {
+			format := printer.Format(output)
+
+			if watch {
+				return WatchNodePool(ctx.Client, ctx.System, ctx.NodePool, os.Stdout, format)
+			}
+
+			return PrintNodePool(ctx.Client, ctx.System, ctx.NodePool, os.Stdout, format)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func PrintNodePool(
+	client client.Interface,
+	system v1.SystemID,
+	path tree.PathSubcomponent,
+	w io.Writer,
+	f printer.Format,
+) error {
+	nodePool, err := client.V1().Systems().NodePools(system).Get(path)
+	if err != nil {
+		return err
+	}
+
+	switch f {
+	case printer.FormatTable:
+		dw := nodePoolWriter(w)
+		s := nodePoolString(nodePool)
+		dw.Print(s)
+
+	case printer.FormatJSON:
+		j := printer.NewJSON(w)
+		j.Print(nodePool)
+
+	default:
+		return fmt.Errorf("unexpected format %v", f)
+	}
+
+	return nil
+}
+
+func WatchNodePool(
+	client client.Interface,
+	system v1.SystemID,
+	path tree.PathSubcomponent,
+	w io.Writer,
+	f printer.Format,
+) error {
+	var handle func(*v1.NodePool)
+	switch f {
+	case printer.FormatTable:
+		dw := nodePoolWriter(w)
+		handle = func(nodePool *v1.NodePool) {
+			s := nodePoolString(nodePool)
+			dw.Overwrite(s)
+		}
+
+	case printer.FormatJSON:
+		j := printer.NewJSON(w)
+		handle = func(nodePool *v1.NodePool) {
+			j.Print(nodePool)
+		}
+
+	default:
+		return fmt.Errorf("unexpected format %v", f)
+	}
+
+	for {
+		nodePool, err := client.V1().Systems().NodePools(system).Get(path)
+		if err != nil {
+			return err
+		}
+
+		handle(nodePool)
+
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func nodePoolWriter(w io.Writer) *printer.Custom {
+	return printer.NewCustom(w)
+}
+
+func nodePoolString(nodePool *v1.NodePool) string {
+	stateColor := nodePoolStateColor(nodePool.Status.State)
+
+	failure := ""
+	if nodePool.Status.FailureInfo != nil {
+		failure = fmt.Sprintf(`
+  failure: %s`,
+			color.FailureString(nodePool.Status.FailureInfo.Message),
+		)
+	}
+
+	instanceTypes := ""
+	if len(nodePool.InstanceTypes) > 1 {
+		instanceTypes = fmt.Sprintf(`
+  instance types: %s`,
+			strings.Join(nodePool.InstanceTypes, ", "),
+		)
+	}
+
+	spot := ""
+	if nodePool.Spot != nil {
+		spot = fmt.Sprintf(`
+  spot: %d on-demand base instances, %d%% on-demand above base, fallback: %s`,
+			nodePool.Spot.OnDemandBaseInstances,
+			nodePool.Spot.OnDemandPercentage,
+			nodePool.Spot.Fallback,
+		)
+
+		if nodePool.Spot.FallbackTimestamp != nil {
+			spot += color.WarningString(fmt.Sprintf(
+				" (falling back to on-demand since %v)",
+				nodePool.Spot.FallbackTimestamp.Local().Format(time.RFC1123),
+			))
+		}
+	}
+
+	autoscaling := ""
+	if nodePool.Autoscaling != nil {
+		autoscaling = fmt.Sprintf(`
+  autoscaling: %d-%d instances`,
+			nodePool.Autoscaling.MinInstances,
+			nodePool.Autoscaling.MaxInstances,
+		)
+	}
+
+	override := ""
+	if nodePool.Override != nil {
+		override = fmt.Sprintf(`
+  override: %s`,
+			color.WarningString(fmt.Sprintf(
+				"%d instances (definition: %d, since %v)",
+				nodePool.Override.NumInstances,
+				nodePool.Override.DefinitionNumInstances,
+				nodePool.Override.Timestamp.Local().Format(time.RFC1123),
+			)),
+		)
+	}
+
+	epochs := ""
+	if len(nodePool.Status.Epochs) != 0 {
+		epochs = `
+  epochs:`
+	}
+	for _, epoch := range nodePool.Status.Epochs {
+		epochs += fmt.Sprintf(`
+    %d: %s (%s, %d instances)`,
+			epoch.Epoch,
+			nodePoolStateColor(epoch.State)(string(epoch.State)),
+			epoch.InstanceType,
+			epoch.NumInstances,
+		)
+	}
+
+	return fmt.Sprintf(`node pool %s
+  state: %s
+  instance type: %s
+  instances: %d/%d%s%s%s%s%s%s
+`,
+		color.IDString(nodePool.Path.String()),
+		stateColor(string(nodePool.Status.State)),
+		nodePool.Status.InstanceType,
+		nodePool.Status.NumInstances,
+		nodePool.NumInstances,
+		failure,
+		instanceTypes,
+		spot,
+		autoscaling,
+		override,
+		epochs,
+	)
+}
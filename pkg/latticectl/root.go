@@ -9,17 +9,18 @@ var Command = cli.RootCommand{
 	Command: &cli.Command{
 		Short: "utility for interacting with lattices",
 		Subcommands: map[string]*cli.Command{
-			"build":     Build(),
-			"builds":    Builds(),
-			"context":   Context(),
-			"deploy":    Deploy(),
-			"deploys":   Deploys(),
-			"jobs":      Jobs(),
-			"secrets":   Secrets(),
-			"services":  Services(),
-			"systems":   Systems(),
-			"teardown":  Teardown(),
-			"teardowns": Teardowns(),
+			"build":      Build(),
+			"builds":     Builds(),
+			"context":    Context(),
+			"deploy":     Deploy(),
+			"deploys":    Deploys(),
+			"jobs":       Jobs(),
+			"node-pools": NodePools(),
+			"secrets":    Secrets(),
+			"services":   Services(),
+			"systems":    Systems(),
+			"teardown":   Teardown(),
+			"teardowns":  Teardowns(),
 		},
 	},
 }
@@ -8,9 +8,10 @@ import (
 func Secrets() *cli.Command {
 	return &cli.Command{
 		Subcommands: map[string]*cli.Command{
-			"get":   secrets.Get(),
-			"set":   secrets.Set(),
-			"unset": secrets.Unset(),
+			"get":      secrets.Get(),
+			"set":      secrets.Set(),
+			"unset":    secrets.Unset(),
+			"versions": secrets.Versions(),
 		},
 	}
 }
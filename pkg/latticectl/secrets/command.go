@@ -8,7 +8,8 @@ import (
 )
 
 const (
-	secretFlagName = "secret"
+	secretFlagName   = "secret"
+	providerFlagName = "provider"
 )
 
 type Command struct {
@@ -25,6 +26,10 @@ type Command struct {
 type SecretCommandContext struct {
 	*command.SystemCommandContext
 	Secret tree.PathSubcomponent
+
+	// Provider is the secret provider the secret is stored in.
+	// If empty, the lattice's default secret provider is used.
+	Provider string
 }
 
 func (c *Command) Command() *cli.Command {
@@ -38,6 +43,12 @@ func (c *Command) Command() *cli.Command {
 		Target:   &secret,
 	}
 
+	var provider string
+	c.Flags[providerFlagName] = &flags.String{
+		Usage:  "secret provider the secret is stored in",
+		Target: &provider,
+	}
+
 	cmd := &command.SystemCommand{
 		Short: c.Short,
 		Args:  c.Args,
@@ -48,6 +59,7 @@ func (c *Command) Command() *cli.Command {
 			secretCtx := &SecretCommandContext{
 				SystemCommandContext: ctx,
 				Secret:               secret,
+				Provider:             provider,
 			}
 			return c.Run(secretCtx, args, f)
 		},
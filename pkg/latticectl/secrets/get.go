@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/client"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
@@ -11,12 +12,20 @@ import (
 	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
 	"github.com/mlab-lattice/lattice/pkg/util/cli"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/printer"
 )
 
+const (
+	getRevealFlag  = "reveal"
+	getVersionFlag = "version"
+)
+
 func Get() *cli.Command {
 	var (
-		output string
+		output  string
+		reveal  bool
+		version int32
 	)
 
 	cmd := Command{
@@ -29,10 +38,22 @@ func Get() *cli.Command {
 				},
 				printer.FormatTable,
 			),
+			getRevealFlag: &flags.Bool{
+				Usage:  "include the secret's value in the output",
+				Target: &reveal,
+			},
+			getVersionFlag: &flags.Int32{
+				Usage:  "version of the secret to reveal (defaults to the current version)",
+				Target: &version,
+			},
 		},
-		Run: func(ctx *SecretCommandContext, args []string, flags cli.Flags) error {
+		Run: func(ctx *SecretCommandContext, args []string, f cli.Flags) error {
+			if f[getVersionFlag].Set() && !reveal {
+				return fmt.Errorf("--%v can only be used with --%v", getVersionFlag, getRevealFlag)
+			}
+
 			format := printer.Format(output)
-			return GetSecret(ctx.Client, ctx.System, ctx.Secret, os.Stdout, format)
+			return GetSecret(ctx.Client, ctx.System, ctx.Secret, ctx.Provider, reveal, version, os.Stdout, format)
 		},
 	}
 
@@ -43,10 +64,19 @@ func GetSecret(
 	client client.Interface,
 	system v1.SystemID,
 	secret tree.PathSubcomponent,
+	provider string,
+	reveal bool,
+	version int32,
 	w io.Writer,
 	f printer.Format,
 ) error {
-	result, err := client.V1().Systems().Secrets(system).Get(secret)
+	var result *v1.Secret
+	var err error
+	if reveal {
+		result, err = client.V1().Systems().Secrets(system).Reveal(secret, provider, version)
+	} else {
+		result, err = client.V1().Systems().Secrets(system).Get(secret, provider)
+	}
 	if err != nil {
 		return err
 	}
@@ -67,15 +97,33 @@ func GetSecret(
 
 	return nil
 }
+
 func secretWriter(w io.Writer) *printer.Custom {
 	return printer.NewCustom(w)
 }
 
 func secretString(secret *v1.Secret) string {
-	return fmt.Sprintf(`secret %v
-  value: %v
+	author := secret.Author
+	if author == "" {
+		author = "-"
+	}
+
+	s := fmt.Sprintf(`secret %v
+  provider: %v
+  version: %v
+  created: %v
+  author: %v
 `,
 		color.IDString(secret.Path.String()),
-		secret.Value,
+		secret.Provider,
+		secret.Version,
+		secret.CreationTimestamp.Local().Format(time.RFC1123),
+		author,
 	)
+
+	if secret.Value != "" {
+		s += fmt.Sprintf("  value: %v\n", secret.Value)
+	}
+
+	return s
 }
@@ -15,22 +15,28 @@ import (
 )
 
 const (
-	setFileFlag  = "file"
-	setValueFlag = "value"
+	setFileFlag    = "file"
+	setValueFlag   = "value"
+	setRestartFlag = "restart"
 )
 
 var setContentFlags = []string{setFileFlag, setValueFlag}
 
 func Set() *cli.Command {
 	var (
-		file  string
-		value string
+		file    string
+		value   string
+		restart bool
 	)
 
 	cmd := Command{
 		Flags: map[string]cli.Flag{
 			setFileFlag:  &flags.String{Target: &file},
 			setValueFlag: &flags.String{Target: &value},
+			setRestartFlag: &flags.Bool{
+				Usage:  "restart the services and jobs referencing the secret",
+				Target: &restart,
+			},
 		},
 		MutuallyExclusiveFlags: [][]string{setContentFlags},
 		RequiredFlagSet:        [][]string{setContentFlags},
@@ -50,7 +56,7 @@ func Set() *cli.Command {
 				value = string(data)
 			}
 
-			return SetSecret(ctx.Client, ctx.System, ctx.Secret, value, os.Stdout)
+			return SetSecret(ctx.Client, ctx.System, ctx.Secret, ctx.Provider, value, restart, os.Stdout)
 		},
 	}
 
@@ -61,10 +67,11 @@ func SetSecret(
 	client client.Interface,
 	system v1.SystemID,
 	secret tree.PathSubcomponent,
-	value string,
+	provider, value string,
+	restart bool,
 	w io.Writer,
 ) error {
-	err := client.V1().Systems().Secrets(system).Set(secret, value)
+	err := client.V1().Systems().Secrets(system).Set(secret, provider, value, restart)
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,7 @@ import (
 func Unset() *cli.Command {
 	cmd := Command{
 		Run: func(ctx *SecretCommandContext, args []string, flags cli.Flags) error {
-			return UnsetSecret(ctx.Client, ctx.System, ctx.Secret, os.Stdout)
+			return UnsetSecret(ctx.Client, ctx.System, ctx.Secret, ctx.Provider, os.Stdout)
 		},
 	}
 
@@ -26,9 +26,10 @@ func UnsetSecret(
 	client client.Interface,
 	system v1.SystemID,
 	secret tree.PathSubcomponent,
+	provider string,
 	w io.Writer,
 ) error {
-	err := client.V1().Systems().Secrets(system).Unset(secret)
+	err := client.V1().Systems().Secrets(system).Unset(secret, provider)
 	if err != nil {
 		return err
 	}
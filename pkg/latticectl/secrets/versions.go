@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/printer"
+)
+
+func Versions() *cli.Command {
+	var (
+		output string
+	)
+
+	cmd := Command{
+		Flags: map[string]cli.Flag{
+			command.OutputFlagName: command.OutputFlag(
+				&output,
+				[]printer.Format{
+					printer.FormatJSON,
+					printer.FormatTable,
+				},
+				printer.FormatTable,
+			),
+		},
+		Run: func(ctx *SecretCommandContext, args []string, flags cli.Flags) error {
+			format := printer.Format(output)
+			return PrintSecretVersions(ctx.Client, ctx.System, ctx.Secret, ctx.Provider, os.Stdout, format)
+		},
+	}
+
+	return cmd.Command()
+}
+
+// PrintSecretVersions writes the versions of the secret to the supplied io.Writer in the given printer.Format.
+func PrintSecretVersions(
+	client client.Interface,
+	system v1.SystemID,
+	secret tree.PathSubcomponent,
+	provider string,
+	w io.Writer,
+	f printer.Format,
+) error {
+	versions, err := client.V1().Systems().Secrets(system).Versions(secret, provider)
+	if err != nil {
+		return err
+	}
+
+	switch f {
+	case printer.FormatTable:
+		t := printer.NewTable(w, []string{"VERSION", "CREATED", "AUTHOR"})
+		t.AppendRows(secretVersionsTableRows(versions))
+		t.Print()
+
+	case printer.FormatJSON:
+		j := printer.NewJSON(w)
+		j.Print(versions)
+
+	default:
+		return fmt.Errorf("unexpected format %v", f)
+	}
+
+	return nil
+}
+
+func secretVersionsTableRows(versions []v1.Secret) [][]string {
+	var rows [][]string
+	for _, version := range versions {
+		author := version.Author
+		if author == "" {
+			author = "-"
+		}
+
+		rows = append(rows, []string{
+			fmt.Sprintf("%v", version.Version),
+			version.CreationTimestamp.Local().Format(time.RFC1123),
+			author,
+		})
+	}
+
+	return rows
+}
@@ -47,8 +47,11 @@ func Services() *cli.Command {
 			return PrintServices(ctx.Client, ctx.System, os.Stdout, format)
 		},
 		Subcommands: map[string]*cli.Command{
-			"logs":   services.Logs(),
-			"status": services.Status(),
+			"exec":         services.Exec(),
+			"fault":        services.Fault(),
+			"logs":         services.Logs(),
+			"port-forward": services.PortForward(),
+			"status":       services.Status(),
 		},
 	}
 
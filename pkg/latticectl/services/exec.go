@@ -0,0 +1,119 @@
+package services
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Exec returns the command for running a command in a service instance,
+// e.g. latticectl services exec --service foo -it -- /bin/sh
+func Exec() *cli.Command {
+	var (
+		instance    string
+		interactive bool
+		sidecar     string
+		tty         bool
+	)
+
+	cmd := Command{
+		Short: "run a command in a service instance",
+		Args:  cli.Args{AllowAdditional: true},
+		Flags: map[string]cli.Flag{
+			instanceFlag:            &flags.String{Target: &instance},
+			command.SidecarFlagName: command.SidecarFlag(&sidecar),
+			"interactive": &flags.Bool{
+				Short:  "i",
+				Usage:  "pass stdin to the command",
+				Target: &interactive,
+			},
+			"tty": &flags.Bool{
+				Short:  "t",
+				Usage:  "run the command with a TTY",
+				Target: &tty,
+			},
+		},
+		Run: func(ctx *ServiceCommandContext, args []string, flags cli.Flags) error {
+			var instancePtr *string
+			if flags[instanceFlag].Set() {
+				instancePtr = &instance
+			}
+
+			var sidecarPtr *string
+			if flags[command.SidecarFlagName].Set() {
+				sidecarPtr = &sidecar
+			}
+
+			return ExecService(ctx.Client, ctx.System, ctx.Service, instancePtr, sidecarPtr, args, interactive, tty)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func ExecService(
+	client client.Interface,
+	system v1.SystemID,
+	id v1.ServiceID,
+	instance, sidecar *string,
+	command []string,
+	interactive, tty bool,
+) error {
+	streams := &v1.ContainerExecStreams{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+
+	if interactive {
+		streams.Stdin = os.Stdin
+	}
+
+	// only use a TTY if stdin is a terminal
+	fd := int(os.Stdin.Fd())
+	tty = tty && terminal.IsTerminal(fd)
+	if tty {
+		// put the terminal into raw mode so that input such as ctrl-c is
+		// sent to the command rather than handled locally
+		state, err := terminal.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer terminal.Restore(fd, state)
+
+		resize := make(chan v1.TerminalSize, 1)
+		streams.Resize = resize
+
+		winch := make(chan os.Signal, 1)
+		signal.Notify(winch, syscall.SIGWINCH)
+		defer signal.Stop(winch)
+
+		sendSize := func() {
+			width, height, err := terminal.GetSize(fd)
+			if err != nil {
+				return
+			}
+
+			select {
+			case resize <- v1.TerminalSize{Width: uint16(width), Height: uint16(height)}:
+			default:
+			}
+		}
+
+		sendSize()
+		go func() {
+			for range winch {
+				sendSize()
+			}
+		}()
+	}
+
+	return client.V1().Systems().Services(system).Exec(id, instance, sidecar, command, tty, streams)
+}
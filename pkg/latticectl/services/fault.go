@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+const (
+	faultAbortFlag      = "abort"
+	faultDelayFlag      = "delay"
+	faultExpiryFlag     = "expiry"
+	faultPercentageFlag = "percentage"
+	faultSourceFlag     = "source"
+)
+
+var faultTypeFlags = []string{faultAbortFlag, faultDelayFlag}
+
+// Fault returns the command for managing faults injected into a service's traffic.
+func Fault() *cli.Command {
+	return &cli.Command{
+		Short: "manage faults injected into a service's traffic",
+		Subcommands: map[string]*cli.Command{
+			"inject": FaultInject(),
+			"clear":  FaultClear(),
+		},
+	}
+}
+
+func FaultInject() *cli.Command {
+	var (
+		abort      int32
+		delay      string
+		expiry     string
+		percentage int32
+		source     tree.Path
+	)
+
+	cmd := Command{
+		Short: "inject a delay or abort fault into a service's traffic",
+		Flags: map[string]cli.Flag{
+			faultAbortFlag: &flags.Int32{
+				Usage:  "HTTP status to abort requests with",
+				Target: &abort,
+			},
+			faultDelayFlag: &flags.String{
+				Usage:  "duration to delay requests by (e.g. 500ms)",
+				Target: &delay,
+			},
+			faultExpiryFlag: &flags.String{
+				Default: "10m",
+				Usage:   "duration after which the fault is removed",
+				Target:  &expiry,
+			},
+			faultPercentageFlag: &flags.Int32{
+				Default: 100,
+				Usage:   "percentage of requests to apply the fault to",
+				Target:  &percentage,
+			},
+			faultSourceFlag: &flags.Path{
+				Usage:  "only apply the fault to requests from the service at this path",
+				Target: &source,
+			},
+		},
+		MutuallyExclusiveFlags: [][]string{faultTypeFlags},
+		RequiredFlagSet:        [][]string{faultTypeFlags},
+		Run: func(ctx *ServiceCommandContext, args []string, flags cli.Flags) error {
+			expiryDuration, err := time.ParseDuration(expiry)
+			if err != nil {
+				return fmt.Errorf("invalid expiry %v: %v", expiry, err)
+			}
+
+			var delayFault *v1.ServiceFaultDelay
+			if flags[faultDelayFlag].Set() {
+				delayFault = &v1.ServiceFaultDelay{Duration: delay}
+			}
+
+			var abortFault *v1.ServiceFaultAbort
+			if flags[faultAbortFlag].Set() {
+				abortFault = &v1.ServiceFaultAbort{HTTPStatus: abort}
+			}
+
+			var sourcePtr *tree.Path
+			if flags[faultSourceFlag].Set() {
+				sourcePtr = &source
+			}
+
+			return InjectServiceFault(
+				ctx.Client,
+				ctx.System,
+				ctx.Service,
+				delayFault,
+				abortFault,
+				percentage,
+				sourcePtr,
+				expiryDuration,
+				os.Stdout,
+			)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func InjectServiceFault(
+	client client.Interface,
+	system v1.SystemID,
+	id v1.ServiceID,
+	delay *v1.ServiceFaultDelay,
+	abort *v1.ServiceFaultAbort,
+	percentage int32,
+	source *tree.Path,
+	expiry time.Duration,
+	w io.Writer,
+) error {
+	err := client.V1().Systems().Services(system).InjectFault(id, delay, abort, percentage, source, expiry)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, color.BoldHiSuccessString(fmt.Sprintf("✓ succesfully injected fault into service %v for %v\n", id, expiry)))
+	return nil
+}
+
+func FaultClear() *cli.Command {
+	cmd := Command{
+		Short: "remove any fault injected into a service's traffic",
+		Run: func(ctx *ServiceCommandContext, args []string, flags cli.Flags) error {
+			return ClearServiceFault(ctx.Client, ctx.System, ctx.Service, os.Stdout)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func ClearServiceFault(client client.Interface, system v1.SystemID, id v1.ServiceID, w io.Writer) error {
+	err := client.V1().Systems().Services(system).ClearFault(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, color.BoldHiSuccessString(fmt.Sprintf("✓ succesfully cleared fault for service %v\n", id)))
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/mlab-lattice/lattice/pkg/api/client"
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+// PortForward returns the command for forwarding a local port to a port of
+// a service instance.
+func PortForward() *cli.Command {
+	var (
+		address   string
+		instance  string
+		localPort int32
+		port      int32
+	)
+
+	cmd := Command{
+		Short: "forward a local port to a service instance",
+		Flags: map[string]cli.Flag{
+			instanceFlag: &flags.String{Target: &instance},
+			"port": &flags.Int32{
+				Usage:    "port of the service instance to forward to",
+				Required: true,
+				Target:   &port,
+			},
+			"local-port": &flags.Int32{
+				Usage:  "local port to listen on, defaults to the forwarded port",
+				Target: &localPort,
+			},
+			"address": &flags.String{
+				Usage:   "local address to listen on",
+				Default: "localhost",
+				Target:  &address,
+			},
+		},
+		Run: func(ctx *ServiceCommandContext, args []string, flags cli.Flags) error {
+			var instancePtr *string
+			if flags[instanceFlag].Set() {
+				instancePtr = &instance
+			}
+
+			if localPort == 0 {
+				localPort = port
+			}
+
+			return PortForwardService(ctx.Client, ctx.System, ctx.Service, instancePtr, address, localPort, port)
+		},
+	}
+
+	return cmd.Command()
+}
+
+func PortForwardService(
+	client client.Interface,
+	system v1.SystemID,
+	id v1.ServiceID,
+	instance *string,
+	address string,
+	localPort, port int32,
+) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%v:%v", address, localPort))
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	fmt.Printf("forwarding %v to port %v of service %v\n", listener.Addr(), port, id)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+
+			err := client.V1().Systems().Services(system).PortForward(id, instance, port, conn)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error forwarding connection from %v: %v\n", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
@@ -169,12 +169,20 @@ func serviceString(service *v1.Service) string {
 		)
 	}
 
+	fault := ""
+	if service.Fault != nil {
+		fault = fmt.Sprintf(`
+  fault: %s`,
+			color.WarningString(serviceFaultString(service.Fault)),
+		)
+	}
+
 	return fmt.Sprintf(`service %s (%s)
   state: %s
   available instances: %s
   updated instances: %s
   stale instances: %s
-  terminating instances: %s%s%s%s
+  terminating instances: %s%s%s%s%s
 `,
 		color.IDString(string(service.ID)),
 		service.Path.String(),
@@ -184,7 +192,25 @@ func serviceString(service *v1.Service) string {
 		staleInstancesColor(strconv.Itoa(int(service.Status.StaleInstances))),
 		terminatingInstancesColor(strconv.Itoa(int(service.Status.TerminatingInstances))),
 		message,
+		fault,
 		ports,
 		instances,
 	)
 }
+
+func serviceFaultString(fault *v1.ServiceFault) string {
+	description := ""
+	switch {
+	case fault.Delay != nil:
+		description = fmt.Sprintf("delay %v", fault.Delay.Duration)
+	case fault.Abort != nil:
+		description = fmt.Sprintf("abort %v", fault.Abort.HTTPStatus)
+	}
+
+	description += fmt.Sprintf(" (%v%% of requests", fault.Percentage)
+	if fault.Source != nil {
+		description += fmt.Sprintf(" from %v", fault.Source.String())
+	}
+
+	return description + fmt.Sprintf(", expires %v)", fault.Expires.Local().Format(time.RFC1123))
+}
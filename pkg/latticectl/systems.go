@@ -47,10 +47,15 @@ func Systems() *cli.Command {
 			return PrintSystems(ctx.Client, format, os.Stdout)
 		},
 		Subcommands: map[string]*cli.Command{
-			"create":   systems.Create(),
-			"delete":   systems.Delete(),
-			"status":   systems.Status(),
-			"versions": systems.Versions(),
+			"container-resources": systems.ContainerResources(),
+			"cost":                systems.Cost(),
+			"create":              systems.Create(),
+			"delete":              systems.Delete(),
+			"image-policy":        systems.ImagePolicy(),
+			"quota":               systems.Quota(),
+			"status":              systems.Status(),
+			"tracing":             systems.Tracing(),
+			"versions":            systems.Versions(),
 		},
 	}
 
@@ -0,0 +1,174 @@
+package systems
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/containerresources"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+const (
+	containerResourcesDefaultCPUFlag                   = "default-cpu"
+	containerResourcesDefaultMemoryFlag                = "default-memory"
+	containerResourcesDefaultEphemeralStorageFlag      = "default-ephemeral-storage"
+	containerResourcesDefaultCPULimitFlag              = "default-cpu-limit"
+	containerResourcesDefaultMemoryLimitFlag           = "default-memory-limit"
+	containerResourcesDefaultEphemeralStorageLimitFlag = "default-ephemeral-storage-limit"
+	containerResourcesMaxCPUFlag                       = "max-cpu"
+	containerResourcesMaxMemoryFlag                    = "max-memory"
+	containerResourcesMaxGPUFlag                       = "max-gpu"
+	containerResourcesMaxEphemeralStorageFlag          = "max-ephemeral-storage"
+)
+
+var containerResourcesFlags = []string{
+	containerResourcesDefaultCPUFlag,
+	containerResourcesDefaultMemoryFlag,
+	containerResourcesDefaultEphemeralStorageFlag,
+	containerResourcesDefaultCPULimitFlag,
+	containerResourcesDefaultMemoryLimitFlag,
+	containerResourcesDefaultEphemeralStorageLimitFlag,
+	containerResourcesMaxCPUFlag,
+	containerResourcesMaxMemoryFlag,
+	containerResourcesMaxGPUFlag,
+	containerResourcesMaxEphemeralStorageFlag,
+}
+
+// ContainerResources sets the default resources of the system's containers and
+// the most resources a container can have. Resources that aren't passed keep
+// their current value, and an empty value or a GPU count of 0 removes them.
+func ContainerResources() *cli.Command {
+	var (
+		defaultCPU                   string
+		defaultMemory                string
+		defaultEphemeralStorage      string
+		defaultCPULimit              string
+		defaultMemoryLimit           string
+		defaultEphemeralStorageLimit string
+		maxCPU                       string
+		maxMemory                    string
+		maxGPU                       int32
+		maxEphemeralStorage          string
+	)
+
+	cmd := command.SystemCommand{
+		Flags: map[string]cli.Flag{
+			containerResourcesDefaultCPUFlag: &flags.String{
+				Usage:  "cpu requested by containers that don't request it, e.g. 250m",
+				Target: &defaultCPU,
+			},
+			containerResourcesDefaultMemoryFlag: &flags.String{
+				Usage:  "memory requested by containers that don't request it, e.g. 256Mi",
+				Target: &defaultMemory,
+			},
+			containerResourcesDefaultEphemeralStorageFlag: &flags.String{
+				Usage:  "ephemeral storage requested by containers that don't request it",
+				Target: &defaultEphemeralStorage,
+			},
+			containerResourcesDefaultCPULimitFlag: &flags.String{
+				Usage:  "cpu limit of containers that don't limit it",
+				Target: &defaultCPULimit,
+			},
+			containerResourcesDefaultMemoryLimitFlag: &flags.String{
+				Usage:  "memory limit of containers that don't limit it",
+				Target: &defaultMemoryLimit,
+			},
+			containerResourcesDefaultEphemeralStorageLimitFlag: &flags.String{
+				Usage:  "ephemeral storage limit of containers that don't limit it",
+				Target: &defaultEphemeralStorageLimit,
+			},
+			containerResourcesMaxCPUFlag: &flags.String{
+				Usage:  "most cpu a container can request or be limited to",
+				Target: &maxCPU,
+			},
+			containerResourcesMaxMemoryFlag: &flags.String{
+				Usage:  "most memory a container can request or be limited to",
+				Target: &maxMemory,
+			},
+			containerResourcesMaxGPUFlag: &flags.Int32{
+				Usage:  "most gpus a container can request or be limited to",
+				Target: &maxGPU,
+			},
+			containerResourcesMaxEphemeralStorageFlag: &flags.String{
+				Usage:  "most ephemeral storage a container can request or be limited to",
+				Target: &maxEphemeralStorage,
+			},
+		},
+		RequiredFlagSet: [][]string{containerResourcesFlags},
+		Run: func(ctx *command.SystemCommandContext, args []string, f cli.Flags) error {
+			client := ctx.Client.V1().Systems()
+
+			system, err := client.Get(ctx.System)
+			if err != nil {
+				return err
+			}
+
+			resources := system.ContainerResources
+			if resources == nil {
+				resources = &v1.SystemContainerResources{}
+			}
+
+			defaultRequests := resourceList(resources.DefaultRequests)
+			defaultLimits := resourceList(resources.DefaultLimits)
+			max := resourceList(resources.Max)
+
+			set := func(flag string, target *string, value string) {
+				if f[flag].Set() {
+					*target = value
+				}
+			}
+
+			set(containerResourcesDefaultCPUFlag, &defaultRequests.CPU, defaultCPU)
+			set(containerResourcesDefaultMemoryFlag, &defaultRequests.Memory, defaultMemory)
+			set(containerResourcesDefaultEphemeralStorageFlag, &defaultRequests.EphemeralStorage, defaultEphemeralStorage)
+			set(containerResourcesDefaultCPULimitFlag, &defaultLimits.CPU, defaultCPULimit)
+			set(containerResourcesDefaultMemoryLimitFlag, &defaultLimits.Memory, defaultMemoryLimit)
+			set(containerResourcesDefaultEphemeralStorageLimitFlag, &defaultLimits.EphemeralStorage, defaultEphemeralStorageLimit)
+			set(containerResourcesMaxCPUFlag, &max.CPU, maxCPU)
+			set(containerResourcesMaxMemoryFlag, &max.Memory, maxMemory)
+			set(containerResourcesMaxEphemeralStorageFlag, &max.EphemeralStorage, maxEphemeralStorage)
+
+			if f[containerResourcesMaxGPUFlag].Set() {
+				max.GPU = maxGPU
+			}
+
+			resources = &v1.SystemContainerResources{
+				DefaultRequests: nonEmptyResourceList(defaultRequests),
+				DefaultLimits:   nonEmptyResourceList(defaultLimits),
+				Max:             nonEmptyResourceList(max),
+			}
+
+			if err := containerresources.ValidateSystem(resources); err != nil {
+				return err
+			}
+
+			if _, err := client.SetContainerResources(ctx.System, resources); err != nil {
+				return err
+			}
+
+			fmt.Printf("updated container resources of system %v\n", color.IDString(string(ctx.System)))
+			return nil
+		},
+	}
+
+	return cmd.Command()
+}
+
+func resourceList(list *v1.ContainerResourceList) *v1.ContainerResourceList {
+	if list == nil {
+		return &v1.ContainerResourceList{}
+	}
+
+	return list
+}
+
+func nonEmptyResourceList(list *v1.ContainerResourceList) *v1.ContainerResourceList {
+	if *list == (v1.ContainerResourceList{}) {
+		return nil
+	}
+
+	return list
+}
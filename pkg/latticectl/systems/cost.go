@@ -0,0 +1,155 @@
+package systems
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/printer"
+)
+
+// Cost prints the estimated cost of the system's services and node pools under
+// a path, and their cost over the period since --since from the hours their
+// instances ran for.
+func Cost() *cli.Command {
+	var (
+		output string
+		path   tree.Path
+		since  string
+	)
+
+	cmd := command.SystemCommand{
+		Flags: map[string]cli.Flag{
+			command.OutputFlagName: command.OutputFlag(
+				&output,
+				[]printer.Format{
+					printer.FormatJSON,
+					printer.FormatTable,
+				},
+				printer.FormatTable,
+			),
+			"path": &flags.Path{
+				Default: tree.RootPath(),
+				Usage:   "path to estimate the cost of the services and node pools under",
+				Target:  &path,
+			},
+			"since": &flags.String{
+				Usage:  "period to estimate the cost over, e.g. 7d or 12h, defaults to since the system was created",
+				Target: &since,
+			},
+		},
+		Run: func(ctx *command.SystemCommandContext, args []string, f cli.Flags) error {
+			var sinceTime time.Time
+			if since != "" {
+				period, err := parsePeriod(since)
+				if err != nil {
+					return err
+				}
+
+				sinceTime = time.Now().Add(-period)
+			}
+
+			estimate, err := ctx.Client.V1().Systems().Cost(ctx.System, path, sinceTime)
+			if err != nil {
+				return err
+			}
+
+			switch printer.Format(output) {
+			case printer.FormatTable:
+				printer.NewCustom(os.Stdout).Print(costString(ctx.System, estimate))
+
+			case printer.FormatJSON:
+				printer.NewJSON(os.Stdout).Print(estimate)
+
+			default:
+				return fmt.Errorf("unexpected format %v", output)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd.Command()
+}
+
+// parsePeriod parses a duration that may also be a number of days, e.g. 7d.
+func parsePeriod(period string) (time.Duration, error) {
+	if strings.HasSuffix(period, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid period %v", period)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid period %v", period)
+	}
+
+	return duration, nil
+}
+
+func costString(id v1.SystemID, estimate *v1.CostEstimate) string {
+	output := fmt.Sprintf(`cost of %s in system %s
+  hourly: %s
+  projected monthly: %s
+  since %s: %s
+`,
+		color.IDString(estimate.Path.String()),
+		color.IDString(string(id)),
+		dollars(estimate.HourlyCost),
+		dollars(estimate.ProjectedMonthlyCost),
+		estimate.Since.Local().Format(time.RFC1123),
+		dollars(estimate.Cost),
+	)
+
+	if len(estimate.Services) != 0 {
+		var paths []string
+		for path := range estimate.Services {
+			paths = append(paths, path.String())
+		}
+		sort.Strings(paths)
+
+		output += "  services:\n"
+		for _, path := range paths {
+			output += fmt.Sprintf("    %v: %v/hour\n", path, dollars(estimate.Services[tree.Path(path)]))
+		}
+	}
+
+	if len(estimate.NodePools) != 0 {
+		var paths []string
+		for path := range estimate.NodePools {
+			paths = append(paths, path.String())
+		}
+		sort.Strings(paths)
+
+		output += "  node pools without services:\n"
+		for _, path := range paths {
+			output += fmt.Sprintf("    %v: %v/hour\n", path, dollars(estimate.NodePools[tree.PathSubcomponent(path)]))
+		}
+	}
+
+	if len(estimate.UnpricedInstanceTypes) != 0 {
+		output += color.WarningString(fmt.Sprintf(
+			"  instance types without a price are not included: %v\n",
+			strings.Join(estimate.UnpricedInstanceTypes, ", "),
+		))
+	}
+
+	return output
+}
+
+func dollars(amount float64) string {
+	return fmt.Sprintf("$%.2f", amount)
+}
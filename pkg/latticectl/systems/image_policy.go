@@ -0,0 +1,90 @@
+package systems
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/imagepolicy"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+const (
+	imagePolicyAllowedRegistryFlag = "allowed-registry"
+	imagePolicyRequireDigestFlag   = "require-digest"
+	imagePolicySignatureKeyFlag    = "signature-key"
+	imagePolicyRemoveFlag          = "remove"
+)
+
+// ImagePolicy sets the registries, digests and signatures required of the images
+// that the system's workloads use, replacing its current image policy.
+func ImagePolicy() *cli.Command {
+	var (
+		allowedRegistries []string
+		requireDigest     bool
+		signatureKeys     []string
+		remove            bool
+	)
+
+	cmd := command.SystemCommand{
+		Flags: map[string]cli.Flag{
+			imagePolicyAllowedRegistryFlag: &flags.StringArray{
+				Usage:  "registry that images can be pulled from, e.g. gcr.io or *.gcr.io",
+				Target: &allowedRegistries,
+			},
+			imagePolicyRequireDigestFlag: &flags.Bool{
+				Usage:  "require images to be referenced by digest",
+				Target: &requireDigest,
+			},
+			imagePolicySignatureKeyFlag: &flags.StringArray{
+				Usage:  "secret holding a public key that images can be signed with, e.g. /path/to:secret",
+				Target: &signatureKeys,
+			},
+			imagePolicyRemoveFlag: &flags.Bool{
+				Usage:  "remove the image policy so that any image can be used",
+				Target: &remove,
+			},
+		},
+		RequiredFlagSet: [][]string{{
+			imagePolicyAllowedRegistryFlag,
+			imagePolicyRequireDigestFlag,
+			imagePolicySignatureKeyFlag,
+			imagePolicyRemoveFlag,
+		}},
+		Run: func(ctx *command.SystemCommandContext, args []string, f cli.Flags) error {
+			policy := &v1.ImagePolicy{
+				AllowedRegistries: allowedRegistries,
+				RequireDigest:     requireDigest,
+			}
+
+			for _, key := range signatureKeys {
+				secret, err := tree.NewPathSubcomponent(key)
+				if err != nil {
+					return fmt.Errorf("invalid signature key %v. expected /path/to:secret", key)
+				}
+
+				policy.SignatureKeys = append(policy.SignatureKeys, v1.ImagePolicySignatureKey{Secret: secret})
+			}
+
+			if remove && !imagepolicy.IsEmpty(policy) {
+				return fmt.Errorf("--%v cannot be used with the other flags", imagePolicyRemoveFlag)
+			}
+
+			if err := imagepolicy.Validate(policy); err != nil {
+				return err
+			}
+
+			if _, err := ctx.Client.V1().Systems().SetImagePolicy(ctx.System, policy); err != nil {
+				return err
+			}
+
+			fmt.Printf("updated image policy of system %v\n", color.IDString(string(ctx.System)))
+			return nil
+		},
+	}
+
+	return cmd.Command()
+}
@@ -0,0 +1,130 @@
+package systems
+
+import (
+	"fmt"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/containerresources"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/quota"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+const (
+	quotaCPUFlag               = "cpu"
+	quotaMemoryFlag            = "memory"
+	quotaInstancesFlag         = "instances"
+	quotaNodePoolInstancesFlag = "node-pool-instances"
+	quotaJobRunsFlag           = "job-runs"
+	quotaBuildsFlag            = "builds"
+)
+
+var quotaFlags = []string{
+	quotaCPUFlag,
+	quotaMemoryFlag,
+	quotaInstancesFlag,
+	quotaNodePoolInstancesFlag,
+	quotaJobRunsFlag,
+	quotaBuildsFlag,
+}
+
+// Quota sets the limits of the system's quota. Limits that aren't passed keep
+// their current value, and a limit of 0 removes it.
+func Quota() *cli.Command {
+	var (
+		cpu               string
+		memory            string
+		instances         int32
+		nodePoolInstances int32
+		jobRuns           int32
+		builds            int32
+	)
+
+	cmd := command.SystemCommand{
+		Flags: map[string]cli.Flag{
+			quotaCPUFlag: &flags.String{
+				Usage:  "total cpu the system's containers can request, e.g. 4 or 500m",
+				Target: &cpu,
+			},
+			quotaMemoryFlag: &flags.String{
+				Usage:  "total memory the system's containers can request, e.g. 8Gi",
+				Target: &memory,
+			},
+			quotaInstancesFlag: &flags.Int32{
+				Usage:  "total instances of the system's services",
+				Target: &instances,
+			},
+			quotaNodePoolInstancesFlag: &flags.Int32{
+				Usage:  "total instances of the system's node pools",
+				Target: &nodePoolInstances,
+			},
+			quotaJobRunsFlag: &flags.Int32{
+				Usage:  "number of job runs that can run at the same time",
+				Target: &jobRuns,
+			},
+			quotaBuildsFlag: &flags.Int32{
+				Usage:  "number of builds that can run at the same time",
+				Target: &builds,
+			},
+		},
+		RequiredFlagSet: [][]string{quotaFlags},
+		Run: func(ctx *command.SystemCommandContext, args []string, f cli.Flags) error {
+			client := ctx.Client.V1().Systems()
+
+			system, err := client.Get(ctx.System)
+			if err != nil {
+				return err
+			}
+
+			systemQuota := system.Quota
+			if systemQuota == nil {
+				systemQuota = &v1.SystemQuota{}
+			}
+
+			if f[quotaCPUFlag].Set() {
+				systemQuota.MilliCPU, err = containerresources.ParseMilliCPU(cpu)
+				if err != nil {
+					return err
+				}
+			}
+
+			if f[quotaMemoryFlag].Set() {
+				systemQuota.MemoryMiB, err = containerresources.ParseMemoryMiB(memory)
+				if err != nil {
+					return err
+				}
+			}
+
+			if f[quotaInstancesFlag].Set() {
+				systemQuota.Instances = instances
+			}
+
+			if f[quotaNodePoolInstancesFlag].Set() {
+				systemQuota.NodePoolInstances = nodePoolInstances
+			}
+
+			if f[quotaJobRunsFlag].Set() {
+				systemQuota.JobRuns = jobRuns
+			}
+
+			if f[quotaBuildsFlag].Set() {
+				systemQuota.Builds = builds
+			}
+
+			if err := quota.Validate(systemQuota); err != nil {
+				return err
+			}
+
+			if _, err := client.SetQuota(ctx.System, systemQuota); err != nil {
+				return err
+			}
+
+			fmt.Printf("updated quota of system %v\n", color.IDString(string(ctx.System)))
+			return nil
+		},
+	}
+
+	return cmd.Command()
+}
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/mlab-lattice/lattice/pkg/api/client"
 	"github.com/mlab-lattice/lattice/pkg/api/v1"
 	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/tracing"
 	"github.com/mlab-lattice/lattice/pkg/util/cli"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
 	"github.com/mlab-lattice/lattice/pkg/util/cli/printer"
@@ -120,10 +122,76 @@ func systemString(system *v1.System) string {
 		stateColor = color.BoldHiFailureString
 	}
 
-	return fmt.Sprintf(`system %s
+	output := fmt.Sprintf(`system %s
   state: %s
 `,
 		color.IDString(string(system.ID)),
 		stateColor(string(system.Status.State)),
 	)
+
+	if system.Tracing != nil && system.Tracing.SamplingRate != nil {
+		output += fmt.Sprintf(
+			"  tracing sampling rate: %v%%\n",
+			tracing.SamplingPercentage(*system.Tracing.SamplingRate),
+		)
+	}
+
+	if system.ImagePolicy != nil {
+		output += imagePolicyString(system.ImagePolicy)
+	}
+
+	if system.Status.Usage != nil {
+		output += usageString(system.Status.Usage, system.Quota)
+	}
+
+	return output
+}
+
+// imagePolicyString returns the registries, digests and signatures required by the policy.
+func imagePolicyString(policy *v1.ImagePolicy) string {
+	output := "  image policy:\n"
+	if len(policy.AllowedRegistries) != 0 {
+		output += fmt.Sprintf("    allowed registries: %v\n", strings.Join(policy.AllowedRegistries, ", "))
+	}
+
+	if policy.RequireDigest {
+		output += "    require digest: true\n"
+	}
+
+	if len(policy.SignatureKeys) != 0 {
+		var keys []string
+		for _, key := range policy.SignatureKeys {
+			keys = append(keys, key.Secret.String())
+		}
+		output += fmt.Sprintf("    signature keys: %v\n", strings.Join(keys, ", "))
+	}
+
+	return output
+}
+
+// usageString returns the system's usage of each resource next to its limit.
+func usageString(usage *v1.SystemUsage, quota *v1.SystemQuota) string {
+	if quota == nil {
+		quota = &v1.SystemQuota{}
+	}
+
+	resource := func(name string, used, limit int64, unit string) string {
+		usedString := fmt.Sprintf("%v%v", used, unit)
+		if limit == 0 {
+			return fmt.Sprintf("    %v: %v (unlimited)\n", name, usedString)
+		}
+
+		if used > limit {
+			usedString = color.FailureString(usedString)
+		}
+		return fmt.Sprintf("    %v: %v of %v%v\n", name, usedString, limit, unit)
+	}
+
+	return "  usage:\n" +
+		resource("cpu", usage.MilliCPU, quota.MilliCPU, "m") +
+		resource("memory", usage.MemoryMiB, quota.MemoryMiB, "Mi") +
+		resource("instances", int64(usage.Instances), int64(quota.Instances), "") +
+		resource("node pool instances", int64(usage.NodePoolInstances), int64(quota.NodePoolInstances), "") +
+		resource("job runs", int64(usage.JobRuns), int64(quota.JobRuns), "") +
+		resource("builds", int64(usage.Builds), int64(quota.Builds), "")
 }
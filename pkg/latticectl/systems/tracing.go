@@ -0,0 +1,54 @@
+package systems
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/latticectl/command"
+	"github.com/mlab-lattice/lattice/pkg/tracing"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/color"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+// Tracing sets the fraction of requests within the system that the service
+// mesh traces. An empty sampling rate removes it, so that the service mesh's
+// default is used.
+func Tracing() *cli.Command {
+	var samplingRate string
+
+	cmd := command.SystemCommand{
+		Flags: map[string]cli.Flag{
+			"sampling-rate": &flags.String{
+				Required: true,
+				Usage:    "fraction (0-1) of requests to trace, or empty to use the default",
+				Target:   &samplingRate,
+			},
+		},
+		Run: func(ctx *command.SystemCommandContext, args []string, f cli.Flags) error {
+			systemTracing := &v1.SystemTracing{}
+			if samplingRate != "" {
+				rate, err := strconv.ParseFloat(samplingRate, 64)
+				if err != nil {
+					return fmt.Errorf("invalid sampling rate %v", samplingRate)
+				}
+
+				systemTracing.SamplingRate = &rate
+			}
+
+			if err := tracing.ValidateSystem(systemTracing); err != nil {
+				return err
+			}
+
+			if _, err := ctx.Client.V1().Systems().SetTracing(ctx.System, systemTracing); err != nil {
+				return err
+			}
+
+			fmt.Printf("updated tracing of system %v\n", color.IDString(string(ctx.System)))
+			return nil
+		},
+	}
+
+	return cmd.Command()
+}
@@ -0,0 +1,254 @@
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/containerresources"
+	"github.com/mlab-lattice/lattice/pkg/definition/resolver"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+)
+
+// ExceededError is returned when a system's usage exceeds its quota.
+type ExceededError struct {
+	// Exceeded describes each of the quota's limits that were exceeded.
+	Exceeded []string
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("exceeds the system's quota: %v", strings.Join(e.Exceeded, ", "))
+}
+
+// UnrequestedError is returned when a quota limits a resource that one of a
+// workload's containers does not request, so the container's usage of it
+// can't be counted towards the quota.
+type UnrequestedError struct {
+	Container string
+	Resource  string
+}
+
+func (e *UnrequestedError) Error() string {
+	return fmt.Sprintf(
+		"%v does not request %v, which the system's quota limits",
+		e.Container,
+		e.Resource,
+	)
+}
+
+// Validate returns an error if the quota is invalid.
+func Validate(quota *v1.SystemQuota) error {
+	if quota.MilliCPU < 0 || quota.MemoryMiB < 0 || quota.Instances < 0 ||
+		quota.NodePoolInstances < 0 || quota.JobRuns < 0 || quota.Builds < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+
+	return nil
+}
+
+// Check returns an *ExceededError if the usage exceeds any of the quota's limits.
+// A nil quota is unlimited.
+func Check(quota *v1.SystemQuota, usage v1.SystemUsage) error {
+	if quota == nil {
+		return nil
+	}
+
+	var exceeded []string
+	check := func(name string, used, limit int64, unit string) {
+		if limit != 0 && used > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%v (%v%v of %v%v)", name, used, unit, limit, unit))
+		}
+	}
+
+	check("cpu", usage.MilliCPU, quota.MilliCPU, "m")
+	check("memory", usage.MemoryMiB, quota.MemoryMiB, "Mi")
+	check("instances", int64(usage.Instances), int64(quota.Instances), "")
+	check("node pool instances", int64(usage.NodePoolInstances), int64(quota.NodePoolInstances), "")
+	check("job runs", int64(usage.JobRuns), int64(quota.JobRuns), "")
+	check("builds", int64(usage.Builds), int64(quota.Builds), "")
+
+	if len(exceeded) == 0 {
+		return nil
+	}
+
+	return &ExceededError{Exceeded: exceeded}
+}
+
+// Add returns the sum of the usages.
+func Add(a, b v1.SystemUsage) v1.SystemUsage {
+	return v1.SystemUsage{
+		MilliCPU:          a.MilliCPU + b.MilliCPU,
+		MemoryMiB:         a.MemoryMiB + b.MemoryMiB,
+		Instances:         a.Instances + b.Instances,
+		NodePoolInstances: a.NodePoolInstances + b.NodePoolInstances,
+		JobRuns:           a.JobRuns + b.JobRuns,
+		Builds:            a.Builds + b.Builds,
+	}
+}
+
+// DefinitionUsage returns the CPU, memory and instances used by the services of
+// the definition, and the instances of its node pools.
+func DefinitionUsage(t *resolver.ResolutionTree) (v1.SystemUsage, error) {
+	var usage v1.SystemUsage
+	if t == nil {
+		return usage, nil
+	}
+
+	var err error
+	t.V1().Services(func(path tree.Path, service *definitionv1.Service, info *resolver.ResolutionInfo) tree.WalkContinuation {
+		var serviceUsage v1.SystemUsage
+		serviceUsage, err = ServiceUsage(service)
+		if err != nil {
+			err = fmt.Errorf("service %v: %v", path.String(), err)
+			return tree.HaltWalk
+		}
+
+		usage = Add(usage, serviceUsage)
+		return tree.ContinueWalk
+	})
+	if err != nil {
+		return v1.SystemUsage{}, err
+	}
+
+	t.V1().NodePools(func(subcomponent tree.PathSubcomponent, nodePool *definitionv1.NodePool) tree.WalkContinuation {
+		usage.NodePoolInstances += NodePoolInstances(nodePool)
+		return tree.ContinueWalk
+	})
+
+	return usage, nil
+}
+
+// ServiceUsage returns the CPU, memory and instances used by the service,
+// including the instances of its node pool if it has a dedicated one.
+func ServiceUsage(service *definitionv1.Service) (v1.SystemUsage, error) {
+	instance, err := WorkloadUsage(service)
+	if err != nil {
+		return v1.SystemUsage{}, err
+	}
+
+	usage := v1.SystemUsage{
+		MilliCPU:  instance.MilliCPU * int64(service.NumInstances),
+		MemoryMiB: instance.MemoryMiB * int64(service.NumInstances),
+		Instances: service.NumInstances,
+	}
+
+	if service.NodePool != nil && service.NodePool.NodePool != nil {
+		usage.NodePoolInstances = NodePoolInstances(service.NodePool.NodePool)
+	}
+
+	return usage, nil
+}
+
+// CheckRequests returns an *UnrequestedError if the quota limits CPU or memory
+// and a container of the workload does not request it. A nil quota is unlimited.
+func CheckRequests(quota *v1.SystemQuota, workload definitionv1.Workload) error {
+	if quota == nil || (quota.MilliCPU == 0 && quota.MemoryMiB == 0) {
+		return nil
+	}
+
+	containers := workload.Containers()
+	if err := checkContainerRequests(quota, "main container", &containers.Main); err != nil {
+		return err
+	}
+
+	var sidecars []string
+	for name := range containers.Sidecars {
+		sidecars = append(sidecars, name)
+	}
+	sort.Strings(sidecars)
+
+	for _, name := range sidecars {
+		sidecar := containers.Sidecars[name]
+		err := checkContainerRequests(quota, fmt.Sprintf("sidecar %v", name), &sidecar)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckDefinitionRequests returns an error if CheckRequests fails for any of the
+// services of the definition.
+func CheckDefinitionRequests(quota *v1.SystemQuota, t *resolver.ResolutionTree) error {
+	if t == nil {
+		return nil
+	}
+
+	var err error
+	t.V1().Services(func(path tree.Path, service *definitionv1.Service, info *resolver.ResolutionInfo) tree.WalkContinuation {
+		if err = CheckRequests(quota, service); err != nil {
+			err = fmt.Errorf("service %v: %v", path.String(), err)
+			return tree.HaltWalk
+		}
+
+		return tree.ContinueWalk
+	})
+	return err
+}
+
+// WorkloadUsage returns the CPU and memory requested by the containers of one
+// instance of the workload. Containers that do not specify resources do not
+// count towards either, so workloads should be checked with CheckRequests
+// before being counted towards a quota.
+func WorkloadUsage(workload definitionv1.Workload) (v1.SystemUsage, error) {
+	containers := workload.Containers()
+
+	usage, err := containerUsage(&containers.Main)
+	if err != nil {
+		return v1.SystemUsage{}, err
+	}
+
+	for name, sidecar := range containers.Sidecars {
+		sidecarUsage, err := containerUsage(&sidecar)
+		if err != nil {
+			return v1.SystemUsage{}, fmt.Errorf("sidecar %v: %v", name, err)
+		}
+
+		usage = Add(usage, sidecarUsage)
+	}
+
+	return usage, nil
+}
+
+// NodePoolInstances returns the number of instances the node pool counts
+// towards a quota, which is its maximum if it is autoscaled.
+func NodePoolInstances(nodePool *definitionv1.NodePool) int32 {
+	if nodePool.Autoscaled() {
+		return nodePool.MaxInstances
+	}
+
+	return nodePool.NumInstances
+}
+
+func containerUsage(container *definitionv1.Container) (v1.SystemUsage, error) {
+	requests, err := containerresources.Parse(containerresources.Requests(container.Resources))
+	if err != nil {
+		return v1.SystemUsage{}, err
+	}
+
+	usage := v1.SystemUsage{
+		MilliCPU:  requests.MilliCPU,
+		MemoryMiB: requests.MemoryMiB,
+	}
+	return usage, nil
+}
+
+func checkContainerRequests(quota *v1.SystemQuota, name string, container *definitionv1.Container) error {
+	requests, err := containerresources.Parse(containerresources.Requests(container.Resources))
+	if err != nil {
+		return fmt.Errorf("%v: %v", name, err)
+	}
+
+	if quota.MilliCPU != 0 && requests.MilliCPU == 0 {
+		return &UnrequestedError{Container: name, Resource: "cpu"}
+	}
+
+	if quota.MemoryMiB != 0 && requests.MemoryMiB == 0 {
+		return &UnrequestedError{Container: name, Resource: "memory"}
+	}
+
+	return nil
+}
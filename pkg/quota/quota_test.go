@@ -0,0 +1,77 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	definitionv1 "github.com/mlab-lattice/lattice/pkg/definition/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceUsage(t *testing.T) {
+	service := &definitionv1.Service{
+		Container: definitionv1.Container{
+			Resources: &definitionv1.ContainerResources{CPU: "250m", Memory: "256Mi"},
+		},
+		Sidecars: map[string]definitionv1.Container{
+			"proxy": {
+				Resources: &definitionv1.ContainerResources{
+					Requests: &definitionv1.ContainerResourceList{CPU: "100m"},
+					Limits:   &definitionv1.ContainerResourceList{CPU: "200m"},
+				},
+			},
+			"logger": {},
+		},
+		NumInstances: 3,
+		NodePool: &definitionv1.NodePoolOrReference{
+			NodePool: &definitionv1.NodePool{NumInstances: 1, MinInstances: 1, MaxInstances: 4},
+		},
+	}
+
+	usage, err := ServiceUsage(service)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		v1.SystemUsage{MilliCPU: 1050, MemoryMiB: 768, Instances: 3, NodePoolInstances: 4},
+		usage,
+	)
+}
+
+func TestCheck(t *testing.T) {
+	quota := &v1.SystemQuota{MilliCPU: 1000, Instances: 2}
+	require.NoError(t, Validate(quota))
+
+	require.NoError(t, Check(nil, v1.SystemUsage{MilliCPU: 5000}))
+	require.NoError(t, Check(quota, v1.SystemUsage{MilliCPU: 1000, MemoryMiB: 1 << 20, Instances: 2}))
+
+	err := Check(quota, v1.SystemUsage{MilliCPU: 1500, Instances: 3})
+	require.Error(t, err)
+	exceeded, ok := err.(*ExceededError)
+	require.True(t, ok)
+	require.Equal(t, []string{"cpu (1500m of 1000m)", "instances (3 of 2)"}, exceeded.Exceeded)
+
+	require.Error(t, Validate(&v1.SystemQuota{Builds: -1}))
+}
+
+func TestCheckRequests(t *testing.T) {
+	service := &definitionv1.Service{
+		Container: definitionv1.Container{
+			Resources: &definitionv1.ContainerResources{CPU: "250m", Memory: "256Mi"},
+		},
+		Sidecars: map[string]definitionv1.Container{
+			"proxy": {
+				Resources: &definitionv1.ContainerResources{
+					Requests: &definitionv1.ContainerResourceList{CPU: "100m"},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, CheckRequests(nil, service))
+	require.NoError(t, CheckRequests(&v1.SystemQuota{Instances: 2}, service))
+	require.NoError(t, CheckRequests(&v1.SystemQuota{MilliCPU: 1000}, service))
+
+	err := CheckRequests(&v1.SystemQuota{MilliCPU: 1000, MemoryMiB: 1024}, service)
+	require.Equal(t, &UnrequestedError{Container: "sidecar proxy", Resource: "memory"}, err)
+}
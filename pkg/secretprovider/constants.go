@@ -0,0 +1,11 @@
+package secretprovider
+
+import (
+	"github.com/mlab-lattice/lattice/pkg/secretprovider/encryptedfile"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider/vault"
+)
+
+const (
+	EncryptedFile = encryptedfile.EncryptedFile
+	Vault         = vault.Vault
+)
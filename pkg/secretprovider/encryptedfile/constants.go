@@ -0,0 +1,5 @@
+package encryptedfile
+
+const (
+	EncryptedFile = "encrypted-file"
+)
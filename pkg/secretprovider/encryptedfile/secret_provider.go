@@ -0,0 +1,311 @@
+package encryptedfile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+)
+
+type Options struct {
+	// Path is the path of the file the secrets are stored in.
+	Path string
+
+	// Passphrase is used to derive the key the file is encrypted with.
+	Passphrase string
+}
+
+func Flags() (cli.Flags, *Options) {
+	options := &Options{}
+	flags := cli.Flags{
+		"path": &flags.String{
+			Required: true,
+			Usage:    "path of the file to store secrets in",
+			Target:   &options.Path,
+		},
+		"passphrase": &flags.String{
+			Required: true,
+			Usage:    "passphrase used to encrypt the file",
+			Target:   &options.Passphrase,
+		},
+	}
+	return flags, options
+}
+
+func NewSecretProvider(options *Options) (*DefaultEncryptedFileSecretProvider, error) {
+	if options.Path == "" {
+		return nil, fmt.Errorf("encrypted file path must be set")
+	}
+
+	if options.Passphrase == "" {
+		return nil, fmt.Errorf("encrypted file passphrase must be set")
+	}
+
+	key := sha256.Sum256([]byte(options.Passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &DefaultEncryptedFileSecretProvider{
+		path: options.Path,
+		aead: aead,
+	}
+	return provider, nil
+}
+
+// DefaultEncryptedFileSecretProvider stores secrets in a local file encrypted
+// with AES-GCM. It is intended for local development and testing.
+type DefaultEncryptedFileSecretProvider struct {
+	sync.Mutex
+	path string
+	aead cipher.AEAD
+}
+
+// contents maps system IDs to the system's secrets, keyed by path.
+// Each secret's versions are ordered oldest first.
+type contents map[v1.SystemID]map[tree.PathSubcomponent][]secretVersion
+
+type secretVersion struct {
+	Value             string    `json:"value"`
+	Version           int32     `json:"version"`
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+	Author            string    `json:"author,omitempty"`
+}
+
+func (v *secretVersion) secret(path tree.PathSubcomponent) v1.Secret {
+	return v1.Secret{
+		Path:              path,
+		Provider:          EncryptedFile,
+		Version:           v.Version,
+		CreationTimestamp: *timeutil.New(v.CreationTimestamp),
+		Author:            v.Author,
+	}
+}
+
+func (p *DefaultEncryptedFileSecretProvider) Ready() bool {
+	return true
+}
+
+func (p *DefaultEncryptedFileSecretProvider) List(systemID v1.SystemID) ([]v1.Secret, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	c, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]v1.Secret, 0)
+	for path, versions := range c[systemID] {
+		secrets = append(secrets, versions[len(versions)-1].secret(path))
+	}
+
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Path < secrets[j].Path
+	})
+	return secrets, nil
+}
+
+func (p *DefaultEncryptedFileSecretProvider) Get(systemID v1.SystemID, path tree.PathSubcomponent) (*v1.Secret, error) {
+	secret, err := p.Reveal(systemID, path, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Value = ""
+	return secret, nil
+}
+
+func (p *DefaultEncryptedFileSecretProvider) Versions(systemID v1.SystemID, path tree.PathSubcomponent) ([]v1.Secret, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	c, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, ok := c[systemID][path]
+	if !ok {
+		return nil, v1.NewInvalidSecretError()
+	}
+
+	secrets := make([]v1.Secret, 0)
+	for _, version := range versions {
+		secrets = append(secrets, version.secret(path))
+	}
+
+	return secrets, nil
+}
+
+func (p *DefaultEncryptedFileSecretProvider) Reveal(
+	systemID v1.SystemID,
+	path tree.PathSubcomponent,
+	version int32,
+) (*v1.Secret, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	c, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, ok := c[systemID][path]
+	if !ok {
+		return nil, v1.NewInvalidSecretError()
+	}
+
+	if version == 0 {
+		version = versions[len(versions)-1].Version
+	}
+
+	for _, v := range versions {
+		if v.Version == version {
+			secret := v.secret(path)
+			secret.Value = v.Value
+			return &secret, nil
+		}
+	}
+
+	return nil, v1.NewInvalidSecretVersionError()
+}
+
+func (p *DefaultEncryptedFileSecretProvider) Set(
+	systemID v1.SystemID,
+	path tree.PathSubcomponent,
+	value, author string,
+) error {
+	p.Lock()
+	defer p.Unlock()
+
+	c, err := p.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := c[systemID]; !ok {
+		c[systemID] = make(map[tree.PathSubcomponent][]secretVersion)
+	}
+
+	versions := c[systemID][path]
+	version := int32(1)
+	if len(versions) > 0 {
+		version = versions[len(versions)-1].Version + 1
+	}
+
+	c[systemID][path] = append(versions, secretVersion{
+		Value:             value,
+		Version:           version,
+		CreationTimestamp: time.Now().UTC(),
+		Author:            author,
+	})
+	return p.write(c)
+}
+
+func (p *DefaultEncryptedFileSecretProvider) Unset(systemID v1.SystemID, path tree.PathSubcomponent) error {
+	p.Lock()
+	defer p.Unlock()
+
+	c, err := p.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := c[systemID][path]; !ok {
+		return v1.NewInvalidSecretError()
+	}
+
+	delete(c[systemID], path)
+	if len(c[systemID]) == 0 {
+		delete(c, systemID)
+	}
+
+	return p.write(c)
+}
+
+func (p *DefaultEncryptedFileSecretProvider) read() (contents, error) {
+	data, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(contents), nil
+		}
+		return nil, err
+	}
+
+	nonceSize := p.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("encrypted secrets file %v is corrupt", p.path)
+	}
+
+	plaintext, err := p.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting secrets file %v: %v", p.path, err)
+	}
+
+	c := make(contents)
+	if err := json.Unmarshal(plaintext, &c); err != nil {
+		return nil, fmt.Errorf("error decoding secrets file %v: %v", p.path, err)
+	}
+
+	return c, nil
+}
+
+func (p *DefaultEncryptedFileSecretProvider) write(c contents) error {
+	plaintext, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	data := p.aead.Seal(nonce, nonce, plaintext, nil)
+
+	dir := filepath.Dir(p.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it so the file is never partially written
+	tmp, err := ioutil.TempFile(dir, filepath.Base(p.path))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p.path)
+}
@@ -0,0 +1,93 @@
+package encryptedfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryptedfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	options := &Options{
+		Path:       filepath.Join(dir, "secrets"),
+		Passphrase: "passphrase",
+	}
+	provider, err := NewSecretProvider(options)
+	require.NoError(t, err)
+
+	systemID := v1.SystemID("system")
+	path := tree.PathSubcomponent("/a/b:c")
+
+	_, err = provider.Get(systemID, path)
+	require.Equal(t, v1.NewInvalidSecretError(), err)
+
+	require.NoError(t, provider.Set(systemID, path, "value", "author"))
+
+	secret, err := provider.Get(systemID, path)
+	require.NoError(t, err)
+	require.Empty(t, secret.Value)
+	require.Equal(t, EncryptedFile, secret.Provider)
+	require.Equal(t, int32(1), secret.Version)
+	require.Equal(t, "author", secret.Author)
+
+	secret, err = provider.Reveal(systemID, path, 0)
+	require.NoError(t, err)
+	require.Equal(t, "value", secret.Value)
+
+	t.Run("versions", func(t *testing.T) {
+		require.NoError(t, provider.Set(systemID, path, "rotated", "other"))
+
+		versions, err := provider.Versions(systemID, path)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, int32(2), versions[1].Version)
+		require.Equal(t, "other", versions[1].Author)
+		require.Empty(t, versions[1].Value)
+
+		secret, err := provider.Reveal(systemID, path, 0)
+		require.NoError(t, err)
+		require.Equal(t, "rotated", secret.Value)
+
+		secret, err = provider.Reveal(systemID, path, 1)
+		require.NoError(t, err)
+		require.Equal(t, "value", secret.Value)
+
+		_, err = provider.Reveal(systemID, path, 3)
+		require.Equal(t, v1.NewInvalidSecretVersionError(), err)
+	})
+
+	t.Run("encrypted at rest", func(t *testing.T) {
+		data, err := ioutil.ReadFile(options.Path)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "value")
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		other, err := NewSecretProvider(&Options{Path: options.Path, Passphrase: "wrong"})
+		require.NoError(t, err)
+
+		_, err = other.Get(systemID, path)
+		require.Error(t, err)
+	})
+
+	secrets, err := provider.List(systemID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	require.Equal(t, int32(2), secrets[0].Version)
+	require.Empty(t, secrets[0].Value)
+
+	require.NoError(t, provider.Unset(systemID, path))
+
+	secrets, err = provider.List(systemID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 0)
+}
@@ -0,0 +1,162 @@
+package secretprovider
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider/encryptedfile"
+	"github.com/mlab-lattice/lattice/pkg/secretprovider/vault"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+)
+
+// Interface is implemented by stores that hold the versioned secrets of systems.
+// Secrets that do not exist should be reported with a v1.ErrorCodeInvalidSecret error,
+// and versions that do not exist with a v1.ErrorCodeInvalidSecretVersion error.
+// Only Reveal returns the values of secrets.
+type Interface interface {
+	Ready() bool
+
+	// List returns the current version of each of the system's secrets.
+	List(systemID v1.SystemID) ([]v1.Secret, error)
+	// Get returns the current version of the secret.
+	Get(systemID v1.SystemID, path tree.PathSubcomponent) (*v1.Secret, error)
+	// Versions returns every version of the secret, oldest first.
+	Versions(systemID v1.SystemID, path tree.PathSubcomponent) ([]v1.Secret, error)
+	// Reveal returns the version of the secret along with its value.
+	// If version is zero, the current version is returned.
+	Reveal(systemID v1.SystemID, path tree.PathSubcomponent, version int32) (*v1.Secret, error)
+	// Set stores the value as a new version of the secret.
+	Set(systemID v1.SystemID, path tree.PathSubcomponent, value, author string) error
+	// Unset removes every version of the secret.
+	Unset(systemID v1.SystemID, path tree.PathSubcomponent) error
+}
+
+// Providers is a set of named secret providers, one of which is used
+// for secrets that do not name a provider.
+type Providers struct {
+	defaultProvider string
+	providers       map[string]Interface
+}
+
+func NewProviders(defaultProvider string, providers map[string]Interface) (*Providers, error) {
+	if _, ok := providers[defaultProvider]; !ok {
+		return nil, fmt.Errorf("default secret provider %v is not configured", defaultProvider)
+	}
+
+	p := &Providers{
+		defaultProvider: defaultProvider,
+		providers:       providers,
+	}
+	return p, nil
+}
+
+// Default returns the name of the provider used for secrets that do not name one.
+func (p *Providers) Default() string {
+	return p.defaultProvider
+}
+
+// Names returns the sorted names of the configured providers.
+func (p *Providers) Names() []string {
+	var names []string
+	for name := range p.providers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Provider returns the provider with the given name, or the default provider
+// if name is empty.
+func (p *Providers) Provider(name string) (Interface, error) {
+	if name == "" {
+		name = p.defaultProvider
+	}
+
+	provider, ok := p.providers[name]
+	if !ok {
+		return nil, v1.NewInvalidSecretProviderError()
+	}
+
+	return provider, nil
+}
+
+func (p *Providers) Ready() bool {
+	for _, provider := range p.providers {
+		if !provider.Ready() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Options configures an external secret provider that is used in addition
+// to the backend's own provider.
+type Options struct {
+	Vault         *vault.Options
+	EncryptedFile *encryptedfile.Options
+}
+
+// NewSecretProviders returns the configured external secret providers keyed by name.
+func NewSecretProviders(options *Options) (map[string]Interface, error) {
+	providers := make(map[string]Interface)
+	if options.Vault != nil {
+		provider, err := vault.NewSecretProvider(options.Vault)
+		if err != nil {
+			return nil, err
+		}
+
+		providers[Vault] = provider
+	}
+
+	if options.EncryptedFile != nil {
+		provider, err := encryptedfile.NewSecretProvider(options.EncryptedFile)
+		if err != nil {
+			return nil, err
+		}
+
+		providers[EncryptedFile] = provider
+	}
+
+	return providers, nil
+}
+
+// Flag returns a flag configuring an external secret provider. Since the external
+// secret provider is optional, none of the returned options will be set if no
+// provider was chosen.
+func Flag(secretProvider *string) (cli.Flag, *Options) {
+	vaultFlags, vaultOptions := vault.Flags()
+	encryptedFileFlags, encryptedFileOptions := encryptedfile.Flags()
+	options := &Options{}
+
+	flag := &flags.DelayedEmbedded{
+		Required: false,
+		Usage:    "configuration for the external secret provider",
+		Flags: map[string]cli.Flags{
+			Vault:         vaultFlags,
+			EncryptedFile: encryptedFileFlags,
+		},
+		FlagChooser: func() (*string, error) {
+			if secretProvider == nil || *secretProvider == "" {
+				return nil, nil
+			}
+
+			switch *secretProvider {
+			case Vault:
+				options.Vault = vaultOptions
+			case EncryptedFile:
+				options.EncryptedFile = encryptedFileOptions
+			default:
+				return nil, fmt.Errorf("unsupported secret provider %v", *secretProvider)
+			}
+
+			return secretProvider, nil
+		},
+	}
+
+	return flag, options
+}
@@ -0,0 +1,185 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tokenHeader = "X-Vault-Token"
+
+	methodList = "LIST"
+)
+
+// kvClient is a minimal client for version 2 of Vault's KV secrets engine.
+type kvClient struct {
+	address    string
+	token      string
+	mount      string
+	httpClient *http.Client
+}
+
+type kvReadResponse struct {
+	Data kvVersion `json:"data"`
+}
+
+// kvVersion is a version of the data stored at a path.
+type kvVersion struct {
+	Data     map[string]string `json:"data"`
+	Metadata struct {
+		CreatedTime time.Time `json:"created_time"`
+		Version     int32     `json:"version"`
+	} `json:"metadata"`
+}
+
+type kvMetadataResponse struct {
+	Data struct {
+		CurrentVersion int32                      `json:"current_version"`
+		Versions       map[string]json.RawMessage `json:"versions"`
+	} `json:"data"`
+}
+
+type kvWriteRequest struct {
+	Data map[string]string `json:"data"`
+}
+
+type kvListResponse struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+// read returns the version of the data stored at the path, or nil if the version
+// does not exist or was deleted. If version is zero the current version is returned.
+func (c *kvClient) read(path string, version int32) (*kvVersion, error) {
+	url := c.url("data", path)
+	if version != 0 {
+		url = fmt.Sprintf("%v?version=%v", url, version)
+	}
+
+	var response kvReadResponse
+	found, err := c.do(http.MethodGet, url, nil, &response)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
+
+// versions returns the sorted version numbers of the data stored at the path.
+func (c *kvClient) versions(path string) ([]int32, error) {
+	var response kvMetadataResponse
+	found, err := c.do(http.MethodGet, c.url("metadata", path), nil, &response)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var versions []int32
+	for v := range response.Data.Versions {
+		version, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %v for %v: %v", v, path, err)
+		}
+
+		versions = append(versions, int32(version))
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+	return versions, nil
+}
+
+func (c *kvClient) write(path string, data map[string]string) error {
+	_, err := c.do(http.MethodPost, c.url("data", path), &kvWriteRequest{Data: data}, nil)
+	return err
+}
+
+// list returns the keys directly under the path. Keys ending in a '/'
+// have keys nested under them.
+func (c *kvClient) list(path string) ([]string, error) {
+	var response kvListResponse
+	found, err := c.do(methodList, c.url("metadata", path), nil, &response)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return response.Data.Keys, nil
+}
+
+// destroy removes all versions of the data stored at the path.
+func (c *kvClient) destroy(path string) error {
+	_, err := c.do(http.MethodDelete, c.url("metadata", path), nil, nil)
+	return err
+}
+
+func (c *kvClient) url(endpoint, path string) string {
+	return fmt.Sprintf(
+		"%v/v1/%v/%v/%v",
+		strings.TrimSuffix(c.address, "/"),
+		strings.Trim(c.mount, "/"),
+		endpoint,
+		strings.TrimPrefix(path, "/"),
+	)
+}
+
+// do performs the request, decoding the response body into result if it is non-nil.
+// It returns false if vault reported that nothing exists at the path.
+func (c *kvClient) do(method, url string, body, result interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return false, err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set(tokenHeader, c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("vault returned status %v for %v %v: %v", resp.StatusCode, method, url, string(data))
+	}
+
+	if result == nil || len(data) == 0 {
+		return true, nil
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return false, fmt.Errorf("error decoding vault response: %v", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,5 @@
+package vault
+
+const (
+	Vault = "vault"
+)
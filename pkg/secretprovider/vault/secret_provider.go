@@ -0,0 +1,269 @@
+package vault
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+	"github.com/mlab-lattice/lattice/pkg/definition/tree"
+	"github.com/mlab-lattice/lattice/pkg/util/cli"
+	"github.com/mlab-lattice/lattice/pkg/util/cli/flags"
+	timeutil "github.com/mlab-lattice/lattice/pkg/util/time"
+)
+
+const (
+	defaultMount  = "secret"
+	defaultPrefix = "lattice"
+
+	// valueKey is the key in a secret's KV data that holds the secret's value,
+	// and authorKey the key that holds the author of the secret's version.
+	valueKey  = "value"
+	authorKey = "author"
+
+	requestTimeout = 10 * time.Second
+)
+
+type Options struct {
+	// Address is the address of the vault server, e.g. http://127.0.0.1:8200.
+	Address string
+	Token   string
+
+	// Mount is the path that the KV version 2 secrets engine is mounted at.
+	Mount string
+
+	// Prefix is the path under the mount that the lattice's secrets are stored under.
+	Prefix string
+}
+
+func Flags() (cli.Flags, *Options) {
+	options := &Options{}
+	flags := cli.Flags{
+		"address": &flags.String{
+			Required: true,
+			Usage:    "address of the vault server",
+			Target:   &options.Address,
+		},
+		"token": &flags.String{
+			Required: true,
+			Usage:    "token used to authenticate with vault",
+			Target:   &options.Token,
+		},
+		"mount": &flags.String{
+			Usage:   "path the KV version 2 secrets engine is mounted at",
+			Default: defaultMount,
+			Target:  &options.Mount,
+		},
+		"prefix": &flags.String{
+			Usage:   "path under the mount to store secrets under",
+			Default: defaultPrefix,
+			Target:  &options.Prefix,
+		},
+	}
+	return flags, options
+}
+
+func NewSecretProvider(options *Options) (*DefaultVaultSecretProvider, error) {
+	if options.Address == "" {
+		return nil, fmt.Errorf("vault address must be set")
+	}
+
+	client := &kvClient{
+		address:    options.Address,
+		token:      options.Token,
+		mount:      options.Mount,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+
+	provider := &DefaultVaultSecretProvider{
+		client: client,
+		prefix: strings.Trim(options.Prefix, "/"),
+	}
+	return provider, nil
+}
+
+// DefaultVaultSecretProvider stores secrets in Vault's KV version 2 secrets engine.
+// Each secret is stored at <prefix>/<system ID>/<path>/<subcomponent>, with its
+// value under the "value" key. Versions of secrets are Vault's versions of the key.
+type DefaultVaultSecretProvider struct {
+	client *kvClient
+	prefix string
+}
+
+func (p *DefaultVaultSecretProvider) Ready() bool {
+	return true
+}
+
+func (p *DefaultVaultSecretProvider) List(systemID v1.SystemID) ([]v1.Secret, error) {
+	secrets := make([]v1.Secret, 0)
+	err := p.walk(p.systemKey(systemID), nil, func(parts []string, name string) error {
+		path := tree.RootPath()
+		for _, part := range parts {
+			path = path.Child(part)
+		}
+
+		subcomponent, err := tree.NewPathSubcomponentFromParts(path, name)
+		if err != nil {
+			return err
+		}
+
+		secret, err := p.Get(systemID, subcomponent)
+		if err != nil {
+			// the secret may have been removed since it was listed
+			if v1err, ok := err.(*v1.Error); ok && v1err.Code == v1.ErrorCodeInvalidSecret {
+				return nil
+			}
+			return err
+		}
+
+		secrets = append(secrets, *secret)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// walk calls fn with the path parts and name of each key nested under key.
+func (p *DefaultVaultSecretProvider) walk(key string, parts []string, fn func([]string, string) error) error {
+	keys, err := p.client.list(key)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if strings.HasSuffix(k, "/") {
+			dir := strings.TrimSuffix(k, "/")
+
+			// copy the parts so that sibling directories do not share a backing array
+			subparts := append(append([]string{}, parts...), dir)
+			if err := p.walk(key+"/"+dir, subparts, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := fn(parts, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *DefaultVaultSecretProvider) Get(systemID v1.SystemID, path tree.PathSubcomponent) (*v1.Secret, error) {
+	secret, err := p.Reveal(systemID, path, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Value = ""
+	return secret, nil
+}
+
+func (p *DefaultVaultSecretProvider) Versions(systemID v1.SystemID, path tree.PathSubcomponent) ([]v1.Secret, error) {
+	key := p.secretKey(systemID, path)
+	versions, err := p.client.versions(key)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]v1.Secret, 0)
+	for _, version := range versions {
+		data, err := p.client.read(key, version)
+		if err != nil {
+			return nil, err
+		}
+
+		// skip versions that have been deleted
+		if data == nil {
+			continue
+		}
+
+		secrets = append(secrets, secret(path, data))
+	}
+
+	if len(secrets) == 0 {
+		return nil, v1.NewInvalidSecretError()
+	}
+
+	return secrets, nil
+}
+
+func (p *DefaultVaultSecretProvider) Reveal(
+	systemID v1.SystemID,
+	path tree.PathSubcomponent,
+	version int32,
+) (*v1.Secret, error) {
+	key := p.secretKey(systemID, path)
+	data, err := p.client.read(key, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		if version == 0 {
+			return nil, v1.NewInvalidSecretError()
+		}
+
+		// differentiate between the secret and the version not existing
+		if _, err := p.Get(systemID, path); err != nil {
+			return nil, err
+		}
+
+		return nil, v1.NewInvalidSecretVersionError()
+	}
+
+	value, ok := data.Data[valueKey]
+	if !ok {
+		return nil, v1.NewInvalidSecretError()
+	}
+
+	s := secret(path, data)
+	s.Value = value
+	return &s, nil
+}
+
+func (p *DefaultVaultSecretProvider) Set(systemID v1.SystemID, path tree.PathSubcomponent, value, author string) error {
+	data := map[string]string{
+		valueKey:  value,
+		authorKey: author,
+	}
+	return p.client.write(p.secretKey(systemID, path), data)
+}
+
+func (p *DefaultVaultSecretProvider) Unset(systemID v1.SystemID, path tree.PathSubcomponent) error {
+	return p.client.destroy(p.secretKey(systemID, path))
+}
+
+func (p *DefaultVaultSecretProvider) systemKey(systemID v1.SystemID) string {
+	if p.prefix == "" {
+		return string(systemID)
+	}
+
+	return fmt.Sprintf("%v/%v", p.prefix, systemID)
+}
+
+func (p *DefaultVaultSecretProvider) secretKey(systemID v1.SystemID, path tree.PathSubcomponent) string {
+	parts := []string{p.systemKey(systemID)}
+	if !path.Path().IsRoot() {
+		parts = append(parts, path.Path().Subpaths()...)
+	}
+
+	parts = append(parts, path.Subcomponent())
+	return strings.Join(parts, "/")
+}
+
+// secret returns the metadata of the version of the secret.
+func secret(path tree.PathSubcomponent, data *kvVersion) v1.Secret {
+	return v1.Secret{
+		Path:              path,
+		Provider:          Vault,
+		Version:           data.Metadata.Version,
+		CreationTimestamp: *timeutil.New(data.Metadata.CreatedTime),
+		Author:            data.Data[authorKey],
+	}
+}
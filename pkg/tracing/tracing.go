@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+)
+
+// ValidateSystem returns an error if the system's tracing is invalid.
+func ValidateSystem(tracing *v1.SystemTracing) error {
+	if tracing.SamplingRate == nil {
+		return nil
+	}
+
+	return ValidateSamplingRate(*tracing.SamplingRate)
+}
+
+// ValidateSamplingRate returns an error if the sampling rate is not a
+// fraction between 0 and 1.
+func ValidateSamplingRate(rate float64) error {
+	if math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return fmt.Errorf("sampling rate must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// SamplingPercentage returns the sampling rate as a percentage of requests.
+func SamplingPercentage(rate float64) float64 {
+	return rate * 100
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSystem(t *testing.T) {
+	rate := func(r float64) *float64 {
+		return &r
+	}
+
+	tests := []struct {
+		name         string
+		samplingRate *float64
+		valid        bool
+	}{
+		{name: "default", valid: true},
+		{name: "none", samplingRate: rate(0), valid: true},
+		{name: "some", samplingRate: rate(0.25), valid: true},
+		{name: "all", samplingRate: rate(1), valid: true},
+		{name: "negative", samplingRate: rate(-0.1)},
+		{name: "percentage", samplingRate: rate(10)},
+		{name: "above one", samplingRate: rate(1.01)},
+		{name: "not a number", samplingRate: rate(math.NaN())},
+		{name: "infinite", samplingRate: rate(math.Inf(1))},
+	}
+
+	for _, test := range tests {
+		err := ValidateSystem(&v1.SystemTracing{SamplingRate: test.samplingRate})
+		if test.valid {
+			require.NoError(t, err, test.name)
+		} else {
+			require.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestSamplingPercentage(t *testing.T) {
+	require.Equal(t, float64(0), SamplingPercentage(0))
+	require.Equal(t, float64(25), SamplingPercentage(0.25))
+	require.Equal(t, float64(100), SamplingPercentage(1))
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"sort"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -144,6 +145,11 @@ func (r *Resolver) Fetch(ctx *Context) error {
 		return err
 	}
 
+	// snapshots do not have a remote, so there is nothing to fetch
+	if _, err := repository.Remote(remoteNameOrigin); err == git.ErrRemoteNotFound {
+		return nil
+	}
+
 	fetchOptions := &git.FetchOptions{
 		RemoteName: remoteNameOrigin,
 	}
@@ -292,6 +298,131 @@ func (r *Resolver) Checkout(ctx *Context, ref *Reference) error {
 	return worktree.Checkout(checkoutOpts)
 }
 
+// HeadCommit returns the hash of the commit currently checked out in the
+// repository, without fetching from origin.
+func (r *Resolver) HeadCommit(ctx *Context) (string, error) {
+	repository, err := r.Clone(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
+
+// ImportSnapshot creates a repository for the url with a single commit containing the
+// files unpack writes to the directory it is passed, and returns the commit. The
+// repository has no remote, so the url is never fetched from. If the snapshot was
+// already imported, its commit is returned without calling unpack.
+func (r *Resolver) ImportSnapshot(url string, unpack func(directory string) error) (string, error) {
+	repoDir := r.RepositoryPath(url)
+	repoExists, err := pathExists(repoDir)
+	if err != nil {
+		return "", err
+	}
+
+	if repoExists {
+		return r.HeadCommit(&Context{RepositoryURL: url, Options: &Options{}})
+	}
+
+	// create the repository next to where it belongs and move it into place once it has
+	// been committed, so that a partially imported snapshot is never opened
+	importDir := repoDir + ".import"
+	if err := os.RemoveAll(importDir); err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(importDir)
+
+	if err := os.MkdirAll(importDir, 0700); err != nil {
+		return "", err
+	}
+
+	if err := unpack(importDir); err != nil {
+		return "", err
+	}
+
+	if _, err := git.PlainInit(importDir, false); err != nil {
+		return "", err
+	}
+
+	if err := AddFile(importDir, "."); err != nil {
+		return "", err
+	}
+
+	hash, err := Commit(importDir, fmt.Sprintf("snapshot of %v", url))
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(importDir, repoDir); err != nil {
+		return "", err
+	}
+
+	return hash.String(), nil
+}
+
+// ChangedFiles will clone and fetch, then return the sorted paths of the files that
+// were added, modified or removed between the two commits.
+func (r *Resolver) ChangedFiles(ctx *Context, from, to string) ([]string, error) {
+	err := r.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := r.Clone(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fromTree, err := commitTree(repository, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toTree, err := commitTree(repository, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := gitplumbingobject.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for _, change := range changes {
+		// a change from an empty name is an addition and a change
+		// to an empty name is a removal
+		if change.From.Name != "" {
+			changed[change.From.Name] = true
+		}
+		if change.To.Name != "" {
+			changed[change.To.Name] = true
+		}
+	}
+
+	var files []string
+	for file := range changed {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func commitTree(repository *git.Repository, commit string) (*gitplumbingobject.Tree, error) {
+	c, err := repository.CommitObject(gitplumbing.NewHash(commit))
+	if err != nil {
+		return nil, fmt.Errorf("error getting commit %v: %v", commit, err)
+	}
+
+	return c.Tree()
+}
+
 // FileContents will clone, fetch, and checkout the proper reference, and if successful
 // will attempt to return the contents of the file at fileName.
 func (r *Resolver) FileContents(ctx *Context, ref *Reference, fileName string) ([]byte, error) {
@@ -34,6 +34,8 @@ func TestGitResolver(t *testing.T) {
 	t.Run("TestCloneGithubRepo", testCloneGithubRepo)
 	t.Run("TestTags", testTags)
 	t.Run("TestFileContents", testFileContents)
+	t.Run("TestChangedFiles", testChangedFiles)
+	t.Run("TestImportSnapshot", testImportSnapshot)
 	t.Run("TestInvalidURI", testInvalidURI)
 }
 
@@ -134,6 +136,93 @@ func testFileContent(t *testing.T, uri string, ref *Reference, filename string,
 	}
 }
 
+func testChangedFiles(t *testing.T) {
+	resolver, err := NewResolver(testWorkDir, true)
+
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	ctx := &Context{
+		RepositoryURL: localRepoURI1,
+		Options:       &Options{},
+	}
+
+	v1 := "v1"
+	v2 := "v2"
+	from, err := resolver.GetCommit(ctx, &Reference{Tag: &v1})
+	if err != nil {
+		t.Fatalf("Got error getting commit: %v", err)
+	}
+
+	to, err := resolver.GetCommit(ctx, &Reference{Tag: &v2})
+	if err != nil {
+		t.Fatalf("Got error getting commit: %v", err)
+	}
+
+	files, err := resolver.ChangedFiles(ctx, from.Hash.String(), to.Hash.String())
+	if err != nil {
+		t.Fatalf("Got error getting changed files: %v", err)
+	}
+
+	if len(files) != 1 || files[0] != testFile {
+		t.Fatalf("bad changed files: %v. Must be [%v]", files, testFile)
+	}
+
+	files, err = resolver.ChangedFiles(ctx, to.Hash.String(), to.Hash.String())
+	if err != nil {
+		t.Fatalf("Got error getting changed files: %v", err)
+	}
+
+	if len(files) != 0 {
+		t.Fatalf("bad changed files: %v. Must be empty", files)
+	}
+}
+
+func testImportSnapshot(t *testing.T) {
+	resolver, err := NewResolver(testWorkDir, false)
+
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	uri := "lattice-build-source://registry.example.com/sources@sha256:abc"
+	unpack := func(directory string) error {
+		if err := os.MkdirAll(path.Join(directory, "dir"), 0700); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(path.Join(directory, "dir", testFile), []byte("snapshot"), 0600)
+	}
+
+	commit, err := resolver.ImportSnapshot(uri, unpack)
+	if err != nil {
+		t.Fatalf("Got error importing snapshot: %v", err)
+	}
+
+	ctx := &Context{
+		RepositoryURL: uri,
+		Options:       &Options{},
+	}
+	ref := &Reference{Commit: &commit}
+	testFileContent(t, uri, ref, path.Join("dir", testFile), "snapshot")
+
+	// importing the snapshot again should not unpack it again
+	again, err := resolver.ImportSnapshot(uri, func(string) error {
+		return fmt.Errorf("snapshot unpacked twice")
+	})
+	if err != nil {
+		t.Fatalf("Got error importing snapshot again: %v", err)
+	}
+
+	if again != commit {
+		t.Fatalf("bad commit: %v. Must be %v", again, commit)
+	}
+
+	if _, err := resolver.GetCommit(ctx, ref); err != nil {
+		t.Fatalf("Got error getting snapshot commit: %v", err)
+	}
+}
+
 func testCloneURI(t *testing.T, uri string) {
 	fmt.Printf("Test clone %s\n", uri)
 
@@ -0,0 +1,252 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type request struct {
+	method string
+
+	// either the path relative to the registry or the full url of the request
+	path string
+	url  string
+
+	scopes []string
+	header http.Header
+	body   func() (io.ReadCloser, error)
+	length int64
+}
+
+// do sends the request, authenticating with the registry if it responds with a challenge,
+// and returns an error if the response status is not one of the expected statuses.
+func (c *Client) do(req *request, expected ...int) (*http.Response, error) {
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		if err := c.authenticate(challenge, req.scopes); err != nil {
+			return nil, fmt.Errorf("error authenticating with %v: %v", c.registry, err)
+		}
+
+		resp, err = c.send(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf(
+		"%v %v returned unexpected status %v: %v",
+		req.method,
+		resp.Request.URL.Path,
+		resp.StatusCode,
+		strings.TrimSpace(string(body)),
+	)
+}
+
+func (c *Client) send(req *request) (*http.Response, error) {
+	u := req.url
+	if u == "" {
+		u = fmt.Sprintf("%v://%v%v", c.scheme, c.registry, req.path)
+	}
+
+	var body io.ReadCloser
+	if req.body != nil {
+		b, err := req.body()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	httpReq, err := http.NewRequest(req.method, u, body)
+	if err != nil {
+		if body != nil {
+			body.Close()
+		}
+		return nil, err
+	}
+
+	for k, v := range req.header {
+		httpReq.Header[k] = v
+	}
+	if req.body != nil {
+		httpReq.ContentLength = req.length
+	}
+
+	if auth, ok := c.authorization(req.scopes); ok {
+		httpReq.Header.Set("Authorization", auth)
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+func (c *Client) authorization(scopes []string) (string, bool) {
+	c.tokensLock.Lock()
+	defer c.tokensLock.Unlock()
+
+	auth, ok := c.tokens[strings.Join(scopes, " ")]
+	return auth, ok
+}
+
+// authenticate handles the challenge returned by the registry, storing the authorization
+// to use for requests requiring the scopes.
+func (c *Client) authenticate(challenge string, scopes []string) error {
+	scheme, params := parseChallenge(challenge)
+
+	var auth string
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.credentials == nil {
+			return fmt.Errorf("registry requires credentials")
+		}
+
+		user, pass, err := c.credentials.GetLoginCredentials(c.registry)
+		if err != nil {
+			return err
+		}
+
+		r := &http.Request{Header: make(http.Header)}
+		r.SetBasicAuth(user, pass)
+		auth = r.Header.Get("Authorization")
+
+	case "bearer":
+		token, err := c.token(params, scopes)
+		if err != nil {
+			return err
+		}
+		auth = "Bearer " + token
+
+	default:
+		return fmt.Errorf("unsupported authentication scheme %q", scheme)
+	}
+
+	c.tokensLock.Lock()
+	defer c.tokensLock.Unlock()
+
+	c.tokens[strings.Join(scopes, " ")] = auth
+	return nil
+}
+
+// token retrieves a token for the scopes from the token server named in the bearer challenge.
+func (c *Client) token(params map[string]string, scopes []string) (string, error) {
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("bearer challenge missing realm")
+	}
+
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid realm %q: %v", realm, err)
+	}
+
+	query := u.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	for _, scope := range scopes {
+		query.Add("scope", scope)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if c.credentials != nil {
+		user, pass, err := c.credentials.GetLoginCredentials(c.registry)
+		if err != nil {
+			return "", err
+		}
+		req.SetBasicAuth(user, pass)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token server returned unexpected status %v", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("token server did not return a token")
+}
+
+// parseChallenge parses a WWW-Authenticate header of the form:
+// Bearer realm="https://auth.example.com/token",service="registry.example.com"
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) == 1 {
+		return parts[0], params
+	}
+
+	rest := parts[1]
+	for rest != "" {
+		eq := strings.Index(rest, "=")
+		if eq == -1 {
+			break
+		}
+
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end == -1 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.Index(rest, ",")
+			if end == -1 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+		}
+
+		params[key] = value
+		rest = strings.TrimLeft(rest, ", ")
+	}
+
+	return parts[0], params
+}
@@ -0,0 +1,332 @@
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/mlab-lattice/lattice/pkg/util/docker"
+)
+
+const (
+	dockerHubRegistry     = "registry-1.docker.io"
+	dockerHubLibrary      = "library/"
+	defaultPlatformOS     = "linux"
+	manifestAcceptHeaders = MediaTypeDockerManifest + "," + MediaTypeDockerManifestList + "," +
+		MediaTypeOCIManifest + "," + MediaTypeOCIIndex
+)
+
+// Client talks to a registry via the docker registry HTTP API V2.
+type Client struct {
+	registry    string
+	scheme      string
+	credentials docker.RegistryLoginProvider
+	httpClient  *http.Client
+
+	tokensLock sync.Mutex
+	tokens     map[string]string
+}
+
+// NewClient returns a client for the registry. If the registry is empty, Docker Hub
+// is used. If credentials is nil, the registry is accessed anonymously.
+func NewClient(registry string, credentials docker.RegistryLoginProvider) *Client {
+	if registry == "" || registry == "docker.io" || registry == "index.docker.io" {
+		registry = dockerHubRegistry
+	}
+
+	// like the docker daemon, assume registries on the loopback interface are not served over TLS
+	scheme := "https"
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if host == "localhost" || strings.HasPrefix(host, "127.") {
+		scheme = "http"
+	}
+
+	c := &Client{
+		registry:    registry,
+		scheme:      scheme,
+		credentials: credentials,
+		httpClient:  &http.Client{},
+		tokens:      make(map[string]string),
+	}
+	return c
+}
+
+// Registry returns the host of the registry the client talks to.
+func (c *Client) Registry() string {
+	return c.registry
+}
+
+// Manifest returns the image manifest for the reference, which may be a tag or digest,
+// along with its digest. If the reference is to a manifest list, the manifest for the
+// platform the client is running on is returned.
+func (c *Client) Manifest(repository, reference string) (*Manifest, string, error) {
+	repository = c.repository(repository)
+	data, mediaType, digest, err := c.manifest(repository, reference)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if isManifestList(mediaType) {
+		var list ManifestList
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, "", err
+		}
+
+		platformDigest, err := platformManifest(&list)
+		if err != nil {
+			return nil, "", fmt.Errorf("%v:%v: %v", repository, reference, err)
+		}
+
+		data, mediaType, digest, err = c.manifest(repository, platformDigest)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, "", err
+	}
+
+	if manifest.MediaType == "" {
+		manifest.MediaType = mediaType
+	}
+
+	if manifest.MediaType != MediaTypeDockerManifest && manifest.MediaType != MediaTypeOCIManifest {
+		return nil, "", fmt.Errorf("%v:%v has unsupported manifest media type %v", repository, reference, manifest.MediaType)
+	}
+
+	return &manifest, digest, nil
+}
+
+// ManifestDigest returns the digest of the manifest or manifest list that the reference
+// points to, without resolving manifest lists to the manifest for the platform.
+func (c *Client) ManifestDigest(repository, reference string) (string, error) {
+	_, _, digest, err := c.manifest(c.repository(repository), reference)
+	return digest, err
+}
+
+func (c *Client) manifest(repository, reference string) ([]byte, string, string, error) {
+	req := &request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/v2/%v/manifests/%v", repository, reference),
+		scopes: []string{pullScope(repository)},
+		header: http.Header{"Accept": []string{manifestAcceptHeaders}},
+	}
+
+	resp, err := c.do(req, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", "", &NotFoundError{Repository: repository, Reference: reference}
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = Digest(data)
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if i := strings.Index(mediaType, ";"); i != -1 {
+		mediaType = mediaType[:i]
+	}
+
+	return data, mediaType, digest, nil
+}
+
+func platformManifest(list *ManifestList) (string, error) {
+	for _, m := range list.Manifests {
+		if m.Platform != nil && m.Platform.OS == defaultPlatformOS && m.Platform.Architecture == runtime.GOARCH {
+			return m.Digest, nil
+		}
+	}
+	return "", fmt.Errorf("no manifest for platform %v/%v", defaultPlatformOS, runtime.GOARCH)
+}
+
+// NotFoundError is returned when a manifest does not exist.
+type NotFoundError struct {
+	Repository string
+	Reference  string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("manifest %v:%v not found", e.Repository, e.Reference)
+}
+
+// PutManifest uploads the manifest with the reference, and returns its digest.
+func (c *Client) PutManifest(repository, reference string, manifest *Manifest) (string, error) {
+	repository = c.repository(repository)
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	req := &request{
+		method: http.MethodPut,
+		path:   fmt.Sprintf("/v2/%v/manifests/%v", repository, reference),
+		scopes: []string{pushScope(repository)},
+		header: http.Header{"Content-Type": []string{manifest.MediaType}},
+		body:   bytesBody(data),
+		length: int64(len(data)),
+	}
+
+	resp, err := c.do(req, http.StatusCreated)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return Digest(data), nil
+}
+
+// Blob returns the contents of the blob. The caller must close the returned reader.
+func (c *Client) Blob(repository, digest string) (io.ReadCloser, error) {
+	repository = c.repository(repository)
+	req := &request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/v2/%v/blobs/%v", repository, digest),
+		scopes: []string{pullScope(repository)},
+	}
+
+	resp, err := c.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// BlobExists returns whether the blob exists in the repository.
+func (c *Client) BlobExists(repository, digest string) (bool, error) {
+	repository = c.repository(repository)
+	req := &request{
+		method: http.MethodHead,
+		path:   fmt.Sprintf("/v2/%v/blobs/%v", repository, digest),
+		scopes: []string{pullScope(repository)},
+	}
+
+	resp, err := c.do(req, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// MountBlob attempts to make the blob in the from repository available in the repository
+// without uploading it, and returns whether it was able to.
+func (c *Client) MountBlob(repository, from, digest string) (bool, error) {
+	repository = c.repository(repository)
+	from = c.repository(from)
+
+	query := url.Values{}
+	query.Set("mount", digest)
+	query.Set("from", from)
+
+	req := &request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v2/%v/blobs/uploads/?%v", repository, query.Encode()),
+		scopes: []string{pushScope(repository), pullScope(from)},
+	}
+
+	// the registry starts a regular upload if it was unable to mount the blob
+	resp, err := c.do(req, http.StatusCreated, http.StatusAccepted)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusCreated, nil
+}
+
+// PushBlob uploads the blob to the repository. open may be called more than once.
+func (c *Client) PushBlob(repository, digest string, size int64, open func() (io.ReadCloser, error)) error {
+	repository = c.repository(repository)
+	req := &request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v2/%v/blobs/uploads/", repository),
+		scopes: []string{pushScope(repository)},
+	}
+
+	resp, err := c.do(req, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid upload location: %v", err)
+	}
+
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	req = &request{
+		method: http.MethodPut,
+		url:    location.String(),
+		scopes: []string{pushScope(repository)},
+		header: http.Header{"Content-Type": []string{"application/octet-stream"}},
+		body:   open,
+		length: size,
+	}
+
+	resp, err = c.do(req, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// repository returns the repository's name in the registry, adding the implicit
+// library namespace for official images on Docker Hub.
+func (c *Client) repository(repository string) string {
+	if c.registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		return dockerHubLibrary + repository
+	}
+	return repository
+}
+
+// Digest returns the sha256 digest of the data.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func pullScope(repository string) string {
+	return fmt.Sprintf("repository:%v:pull", repository)
+}
+
+func pushScope(repository string) string {
+	return fmt.Sprintf("repository:%v:pull,push", repository)
+}
+
+func bytesBody(data []byte) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+}
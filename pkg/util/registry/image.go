@@ -0,0 +1,263 @@
+package registry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Image is an image being assembled on top of an image stored in a registry.
+type Image struct {
+	Manifest Manifest
+	Config   ImageConfig
+
+	// Digest is the digest of the manifest of the image that was pulled,
+	// if the image was pulled
+	Digest string
+
+	// client and repository that the blobs of the image that was pulled are stored in,
+	// if the image was pulled
+	client     *Client
+	repository string
+
+	// layers appended to the image that was pulled, keyed by digest
+	layers map[string]*Layer
+}
+
+// NewImage returns an empty image for the platform the client is running on, that
+// layers can be appended to.
+func NewImage() *Image {
+	image := &Image{
+		Manifest: Manifest{
+			SchemaVersion: 2,
+			MediaType:     MediaTypeDockerManifest,
+		},
+		Config: ImageConfig{
+			Architecture: runtime.GOARCH,
+			OS:           defaultPlatformOS,
+			RootFS:       RootFS{Type: "layers"},
+		},
+		layers: make(map[string]*Layer),
+	}
+	return image
+}
+
+// Pull retrieves the manifest and config of the image so that layers can be appended to it.
+// The image's layers are not retrieved until they are needed.
+func (c *Client) Pull(repository, reference string) (*Image, error) {
+	manifest, digest, err := c.Manifest(repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := c.Blob(repository, manifest.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	var config ImageConfig
+	if err := json.NewDecoder(blob).Decode(&config); err != nil {
+		return nil, fmt.Errorf("error decoding config of %v:%v: %v", repository, reference, err)
+	}
+
+	image := &Image{
+		Manifest:   *manifest,
+		Config:     config,
+		Digest:     digest,
+		client:     c,
+		repository: repository,
+		layers:     make(map[string]*Layer),
+	}
+	return image, nil
+}
+
+// AppendLayer adds the layer to the top of the image, recording createdBy in its history.
+func (i *Image) AppendLayer(layer *Layer, createdBy string) {
+	now := time.Now().UTC()
+
+	i.Manifest.Layers = append(i.Manifest.Layers, Descriptor{
+		MediaType: layerMediaType(i.Manifest.MediaType),
+		Size:      layer.Size,
+		Digest:    layer.Digest,
+	})
+
+	i.Config.RootFS.DiffIDs = append(i.Config.RootFS.DiffIDs, layer.DiffID)
+	i.Config.History = append(i.Config.History, History{
+		Created:   &now,
+		CreatedBy: createdBy,
+	})
+	i.Config.Created = &now
+
+	i.layers[layer.Digest] = layer
+}
+
+// Unpack extracts the image's layers into the directory, producing the image's filesystem.
+func (i *Image) Unpack(directory string) error {
+	for _, descriptor := range i.Manifest.Layers {
+		r, err := i.openLayer(descriptor.Digest)
+		if err != nil {
+			return err
+		}
+
+		err = ExtractLayer(r, directory)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("error extracting layer %v: %v", descriptor.Digest, err)
+		}
+	}
+
+	return nil
+}
+
+// Files returns the contents of the regular files at the paths in the image's
+// filesystem, keyed by path. Paths that are not regular files in the image are omitted.
+func (i *Image) Files(paths ...string) (map[string][]byte, error) {
+	wanted := make(map[string]bool)
+	for _, p := range paths {
+		wanted[path.Clean("/"+p)] = true
+	}
+
+	files := make(map[string][]byte)
+	for _, descriptor := range i.Manifest.Layers {
+		r, err := i.openLayer(descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		err = layerFiles(r, wanted, files)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading layer %v: %v", descriptor.Digest, err)
+		}
+	}
+
+	return files, nil
+}
+
+// layerFiles applies the changes the gzipped layer makes to the wanted files.
+func layerFiles(r io.Reader, wanted map[string]bool, files map[string][]byte) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean("/" + header.Name)
+		dir, base := path.Split(name)
+
+		// whiteouts remove the files in lower layers at or below the path
+		var removed string
+		switch {
+		case base == whiteoutOpaque:
+			removed = dir
+		case strings.HasPrefix(base, whiteoutPrefix):
+			removed = path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix))
+		}
+
+		if removed != "" {
+			for file := range files {
+				if file == removed || strings.HasPrefix(file, strings.TrimSuffix(removed, "/")+"/") {
+					delete(files, file)
+				}
+			}
+			continue
+		}
+
+		if !wanted[name] {
+			continue
+		}
+
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			delete(files, name)
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		files[name] = data
+	}
+}
+
+func (i *Image) openLayer(digest string) (io.ReadCloser, error) {
+	if layer, ok := i.layers[digest]; ok {
+		return layer.open()
+	}
+	return i.client.Blob(i.repository, digest)
+}
+
+// Push uploads the image's blobs and manifest to the repository with the tag,
+// and returns the digest of the manifest.
+func (c *Client) Push(image *Image, repository, tag string) (string, error) {
+	for _, descriptor := range image.Manifest.Layers {
+		if err := c.pushLayer(image, repository, descriptor); err != nil {
+			return "", fmt.Errorf("error pushing layer %v: %v", descriptor.Digest, err)
+		}
+	}
+
+	config, err := json.Marshal(&image.Config)
+	if err != nil {
+		return "", err
+	}
+
+	manifest := image.Manifest
+	manifest.Config = Descriptor{
+		MediaType: configMediaType(manifest.MediaType),
+		Size:      int64(len(config)),
+		Digest:    Digest(config),
+	}
+
+	err = c.PushBlob(repository, manifest.Config.Digest, manifest.Config.Size, bytesBody(config))
+	if err != nil {
+		return "", fmt.Errorf("error pushing config: %v", err)
+	}
+
+	return c.PutManifest(repository, tag, &manifest)
+}
+
+func (c *Client) pushLayer(image *Image, repository string, descriptor Descriptor) error {
+	exists, err := c.BlobExists(repository, descriptor.Digest)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	// layers of the pulled image in the same registry can be mounted rather than
+	// downloaded and uploaded again
+	_, appended := image.layers[descriptor.Digest]
+	if !appended && image.client != nil && image.client.registry == c.registry {
+		mounted, err := c.MountBlob(repository, image.repository, descriptor.Digest)
+		if err != nil {
+			return err
+		}
+		if mounted {
+			return nil
+		}
+	}
+
+	open := func() (io.ReadCloser, error) {
+		return image.openLayer(descriptor.Digest)
+	}
+	return c.PushBlob(repository, descriptor.Digest, descriptor.Size, open)
+}
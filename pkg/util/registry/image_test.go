@@ -0,0 +1,199 @@
+package registry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/util/registry/registrytest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	reg := registrytest.NewRegistry()
+	defer reg.Close()
+	reg.RequireToken = true
+
+	client := NewClient(reg.Host(), nil)
+
+	base := writeFiles(t, filepath.Join(dir, "base"), map[string]string{
+		"bin/hello":  "hello",
+		"etc/config": "config",
+	})
+	pushBaseImage(t, client, dir, base)
+
+	image, err := client.Pull("base", "latest")
+	require.NoError(t, err)
+	require.Len(t, image.Manifest.Layers, 1)
+	require.Equal(t, "linux", image.Config.OS)
+
+	source := writeFiles(t, filepath.Join(dir, "source"), map[string]string{
+		"main.go": "package main",
+	})
+	layer, err := DirectoryLayer(filepath.Join(dir, "source.tar.gz"), source, "/usr/src/app")
+	require.NoError(t, err)
+
+	image.AppendLayer(layer, "COPY . /usr/src/app")
+	image.Config.Config.WorkingDir = "/usr/src/app"
+
+	digest, err := client.Push(image, "app", "v1")
+	require.NoError(t, err)
+
+	mediaType, data, ok := reg.Manifest("app", "v1")
+	require.True(t, ok)
+	require.Equal(t, MediaTypeDockerManifest, mediaType)
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest.Layers, 2)
+	for _, l := range manifest.Layers {
+		_, ok := reg.Blob("app", l.Digest)
+		require.True(t, ok, "layer %v was not pushed", l.Digest)
+	}
+
+	pushed, err := client.Pull("app", "v1")
+	require.NoError(t, err)
+	require.Equal(t, digest, pushed.Digest)
+	require.Equal(t, "/usr/src/app", pushed.Config.Config.WorkingDir)
+	require.Equal(t, []string{layer.DiffID}, pushed.Config.RootFS.DiffIDs[1:])
+
+	files, err := pushed.Files("/etc/config", "usr/src/app/main.go", "/missing")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"/etc/config":          []byte("config"),
+		"/usr/src/app/main.go": []byte("package main"),
+	}, files)
+
+	rootfs := filepath.Join(dir, "rootfs")
+	require.NoError(t, pushed.Unpack(rootfs))
+	requireFile(t, filepath.Join(rootfs, "bin/hello"), "hello")
+	requireFile(t, filepath.Join(rootfs, "usr/src/app/main.go"), "package main")
+}
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rootfs := writeFiles(t, filepath.Join(dir, "rootfs"), map[string]string{
+		"a":      "a",
+		"b/c":    "c",
+		"keep/d": "d",
+	})
+
+	snapshot, err := NewSnapshot(rootfs)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(rootfs, "a"), []byte("changed"), 0644))
+	require.NoError(t, os.RemoveAll(filepath.Join(rootfs, "b")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(rootfs, "keep/e"), []byte("e"), 0644))
+
+	layer, err := snapshot.Layer(filepath.Join(dir, "layer.tar.gz"))
+	require.NoError(t, err)
+	require.Equal(t, []string{".wh.b", "a", "keep/e"}, layerEntries(t, layer))
+
+	// applying the layer to the original filesystem produces the changed filesystem
+	original := writeFiles(t, filepath.Join(dir, "original"), map[string]string{
+		"a":      "a",
+		"b/c":    "c",
+		"keep/d": "d",
+	})
+
+	f, err := os.Open(layer.Path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NoError(t, ExtractLayer(f, original))
+	requireFile(t, filepath.Join(original, "a"), "changed")
+	requireFile(t, filepath.Join(original, "keep/d"), "d")
+	requireFile(t, filepath.Join(original, "keep/e"), "e")
+
+	_, err = os.Stat(filepath.Join(original, "b"))
+	require.True(t, os.IsNotExist(err))
+
+	// the files of an image with the layer on top of the original filesystem
+	// reflect the changes too
+	lower := writeFiles(t, filepath.Join(dir, "lower"), map[string]string{
+		"a":   "a",
+		"b/c": "c",
+	})
+	base, err := DirectoryLayer(filepath.Join(dir, "base.tar.gz"), lower, "/")
+	require.NoError(t, err)
+
+	image := NewImage()
+	image.AppendLayer(base, "COPY . /")
+	image.AppendLayer(layer, "RUN change")
+
+	files, err := image.Files("/a", "/b/c")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"/a": []byte("changed")}, files)
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull"`)
+	require.Equal(t, "Bearer", scheme)
+	require.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/alpine:pull",
+	}, params)
+}
+
+func pushBaseImage(t *testing.T, client *Client, dir, rootfs string) {
+	layer, err := DirectoryLayer(filepath.Join(dir, "base.tar.gz"), rootfs, "/")
+	require.NoError(t, err)
+
+	image := NewImage()
+	image.AppendLayer(layer, "COPY . /")
+	image.Config.Config.Cmd = []string{"/bin/hello"}
+
+	_, err = client.Push(image, "base", "latest")
+	require.NoError(t, err)
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) string {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func requireFile(t *testing.T, path, content string) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+}
+
+func layerEntries(t *testing.T, layer *Layer) []string {
+	f, err := os.Open(layer.Path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,422 @@
+package registry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// Layer is a gzipped layer tarball stored on disk.
+type Layer struct {
+	Path string
+
+	// Digest is the digest of the compressed layer, and DiffID the digest
+	// of the uncompressed layer.
+	Digest string
+	DiffID string
+	Size   int64
+}
+
+func (l *Layer) open() (io.ReadCloser, error) {
+	return os.Open(l.Path)
+}
+
+// NewLayer writes the layer produced by write to the file.
+func NewLayer(file string, write func(*tar.Writer) error) (*Layer, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	compressed := &countingHash{Hash: sha256.New()}
+	gz := gzip.NewWriter(io.MultiWriter(f, compressed))
+
+	uncompressed := sha256.New()
+	tw := tar.NewWriter(io.MultiWriter(gz, uncompressed))
+
+	if err := write(tw); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	layer := &Layer{
+		Path:   file,
+		Digest: "sha256:" + hex.EncodeToString(compressed.Sum(nil)),
+		DiffID: "sha256:" + hex.EncodeToString(uncompressed.Sum(nil)),
+		Size:   compressed.n,
+	}
+	return layer, nil
+}
+
+type countingHash struct {
+	hash.Hash
+	n int64
+}
+
+func (h *countingHash) Write(p []byte) (int, error) {
+	n, err := h.Hash.Write(p)
+	h.n += int64(n)
+	return n, err
+}
+
+// DirectoryLayer writes a layer to the file containing the contents of the
+// directory under the destination directory of the image's filesystem.
+func DirectoryLayer(file, directory, destination string) (*Layer, error) {
+	destination = strings.Trim(filepath.ToSlash(destination), "/")
+
+	return NewLayer(file, func(tw *tar.Writer) error {
+		// the destination's parents have to be included for the layer to be extracted
+		// on an image that does not have them
+		var parent string
+		for _, part := range strings.Split(destination, "/") {
+			if part == "" {
+				continue
+			}
+
+			parent = path.Join(parent, part)
+			header := &tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     parent + "/",
+				Mode:     0755,
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+		}
+
+		return filepath.Walk(directory, func(f string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(directory, f)
+			if err != nil {
+				return err
+			}
+
+			if rel == "." {
+				return nil
+			}
+
+			return writeEntry(tw, f, path.Join(destination, filepath.ToSlash(rel)), info)
+		})
+	})
+}
+
+func writeEntry(tw *tar.Writer, file, name string, info os.FileInfo) error {
+	var link string
+	if info.Mode()&os.ModeSymlink != 0 {
+		l, err := os.Readlink(file)
+		if err != nil {
+			return err
+		}
+		link = l
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// ExtractLayer applies the gzipped layer to the filesystem rooted at the directory,
+// including deleting the files whited out by the layer.
+func ExtractLayer(r io.Reader, directory string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean("/" + header.Name)
+		target := filepath.Join(directory, filepath.FromSlash(name))
+		dir, base := filepath.Split(target)
+
+		if base == whiteoutOpaque {
+			if err := removeContents(dir); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			if err := os.RemoveAll(filepath.Join(dir, strings.TrimPrefix(base, whiteoutPrefix))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := extractEntry(tr, header, directory, target); err != nil {
+			return fmt.Errorf("error extracting %v: %v", header.Name, err)
+		}
+	}
+}
+
+func extractEntry(tr *tar.Reader, header *tar.Header, directory, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	// an entry replaces whatever was at its path in lower layers, except
+	// that directories are merged
+	if header.Typeflag != tar.TypeDir {
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+	}
+
+	mode := os.FileMode(header.Mode).Perm()
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if info, err := os.Lstat(target); err == nil && !info.IsDir() {
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(target, mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(target, mode); err != nil {
+			return err
+		}
+
+	case tar.TypeReg, tar.TypeRegA:
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+	case tar.TypeSymlink:
+		return os.Symlink(header.Linkname, target)
+
+	case tar.TypeLink:
+		source := filepath.Join(directory, filepath.FromSlash(path.Clean("/"+header.Linkname)))
+		return os.Link(source, target)
+
+	default:
+		// device files and fifos can only be created with privileges that
+		// the builder may not have, and are not needed to build images
+		return nil
+	}
+
+	// ownership can only be preserved when running as root
+	if os.Geteuid() == 0 {
+		if err := os.Lchown(target, header.Uid, header.Gid); err != nil {
+			return err
+		}
+	}
+
+	return os.Chtimes(target, header.ModTime, header.ModTime)
+}
+
+func removeContents(dir string) error {
+	f, err := os.Open(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	names, err := f.Readdirnames(-1)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type snapshotEntry struct {
+	mode    os.FileMode
+	size    int64
+	modTime int64
+	link    string
+}
+
+// Snapshot records the state of a filesystem so that a layer containing the
+// changes made to it after the snapshot was taken can be produced.
+type Snapshot struct {
+	directory string
+	entries   map[string]snapshotEntry
+}
+
+// NewSnapshot records the state of the filesystem rooted at the directory.
+func NewSnapshot(directory string) (*Snapshot, error) {
+	entries, err := snapshotEntries(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		directory: directory,
+		entries:   entries,
+	}
+	return s, nil
+}
+
+// Layer writes a layer to the file containing the files added or changed since
+// the snapshot was taken, and whiteouts for the files removed since then.
+func (s *Snapshot) Layer(file string) (*Layer, error) {
+	current, err := snapshotEntries(s.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLayer(file, func(tw *tar.Writer) error {
+		err := filepath.Walk(s.directory, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name, err := filepath.Rel(s.directory, file)
+			if err != nil {
+				return err
+			}
+
+			if name == "." {
+				return nil
+			}
+
+			name = filepath.ToSlash(name)
+			previous, ok := s.entries[name]
+			if ok && previous == current[name] {
+				return nil
+			}
+
+			return writeEntry(tw, file, name, info)
+		})
+		if err != nil {
+			return err
+		}
+
+		for name := range s.entries {
+			if _, ok := current[name]; ok {
+				continue
+			}
+
+			// removing a directory removes its contents, so only the directory
+			// needs to be whited out
+			if _, ok := s.entries[path.Dir(name)]; ok {
+				if _, ok := current[path.Dir(name)]; !ok {
+					continue
+				}
+			}
+
+			header := &tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     path.Join(path.Dir(name), whiteoutPrefix+path.Base(name)),
+				Mode:     0644,
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func snapshotEntries(directory string) (map[string]snapshotEntry, error) {
+	entries := make(map[string]snapshotEntry)
+	err := filepath.Walk(directory, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(directory, file)
+		if err != nil {
+			return err
+		}
+
+		if name == "." {
+			return nil
+		}
+
+		entry := snapshotEntry{
+			mode:    info.Mode(),
+			size:    info.Size(),
+			modTime: info.ModTime().UnixNano(),
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(file)
+			if err != nil {
+				return err
+			}
+			entry.link = link
+		}
+
+		// directories' modification times change when their contents do,
+		// which is already captured by their contents
+		if info.IsDir() {
+			entry.size = 0
+			entry.modTime = 0
+		}
+
+		entries[filepath.ToSlash(name)] = entry
+		return nil
+	})
+	return entries, err
+}
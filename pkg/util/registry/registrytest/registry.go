@@ -0,0 +1,215 @@
+package registrytest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Registry is an in-memory stand-in for a registry implementing the parts of the
+// docker registry HTTP API V2 used to pull and push images.
+type Registry struct {
+	*httptest.Server
+
+	// If set, requests must include a bearer token retrieved from the
+	// registry's token endpoint.
+	RequireToken bool
+
+	lock      sync.Mutex
+	blobs     map[string][]byte
+	repoBlobs map[string]map[string]bool
+	manifests map[string]manifest
+	uploads   int
+}
+
+type manifest struct {
+	mediaType string
+	data      []byte
+}
+
+const token = "registrytest-token"
+
+// NewRegistry starts a registry listening on the loopback interface.
+// The caller should call Close when finished.
+func NewRegistry() *Registry {
+	r := &Registry{
+		blobs:     make(map[string][]byte),
+		repoBlobs: make(map[string]map[string]bool),
+		manifests: make(map[string]manifest),
+	}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+// Host returns the host:port that the registry can be reached at.
+func (r *Registry) Host() string {
+	return strings.TrimPrefix(r.URL, "http://")
+}
+
+// Manifest returns the media type and contents of the manifest with the reference.
+func (r *Registry) Manifest(repository, reference string) (string, []byte, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	m, ok := r.manifests[repository+":"+reference]
+	return m.mediaType, m.data, ok
+}
+
+// Blob returns the contents of the blob in the repository.
+func (r *Registry) Blob(repository, digest string) ([]byte, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.repoBlobs[repository][digest] {
+		return nil, false
+	}
+	return r.blobs[digest], true
+}
+
+func (r *Registry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		fmt.Fprintf(w, `{"token": %q}`, token)
+		return
+	}
+
+	if r.RequireToken && req.Header.Get("Authorization") != "Bearer "+token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%v/token",service="registrytest"`, r.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case path == "" || path == "/":
+		w.WriteHeader(http.StatusOK)
+
+	case strings.Contains(path, "/manifests/"):
+		parts := strings.SplitN(path, "/manifests/", 2)
+		r.serveManifest(w, req, parts[0], parts[1])
+
+	case strings.Contains(path, "/blobs/uploads/"):
+		parts := strings.SplitN(path, "/blobs/uploads/", 2)
+		r.serveUpload(w, req, parts[0], parts[1])
+
+	case strings.Contains(path, "/blobs/"):
+		parts := strings.SplitN(path, "/blobs/", 2)
+		r.serveBlob(w, req, parts[0], parts[1])
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (r *Registry) serveManifest(w http.ResponseWriter, req *http.Request, repository, reference string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		m, ok := r.manifests[repository+":"+reference]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", m.mediaType)
+		w.Header().Set("Docker-Content-Digest", digest(m.data))
+		if req.Method == http.MethodGet {
+			w.Write(m.data)
+		}
+
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		m := manifest{
+			mediaType: req.Header.Get("Content-Type"),
+			data:      data,
+		}
+		r.manifests[repository+":"+reference] = m
+		r.manifests[repository+":"+digest(data)] = m
+
+		w.Header().Set("Docker-Content-Digest", digest(data))
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Registry) serveBlob(w http.ResponseWriter, req *http.Request, repository, digest string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.repoBlobs[repository][digest] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	data := r.blobs[digest]
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	w.Header().Set("Docker-Content-Digest", digest)
+	if req.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+func (r *Registry) serveUpload(w http.ResponseWriter, req *http.Request, repository, id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch req.Method {
+	case http.MethodPost:
+		query := req.URL.Query()
+		if d, from := query.Get("mount"), query.Get("from"); d != "" && r.repoBlobs[from][d] {
+			r.addBlob(repository, d, r.blobs[d])
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		r.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%v/blobs/uploads/%v?state=upload", repository, r.uploads))
+		w.WriteHeader(http.StatusAccepted)
+
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		d := req.URL.Query().Get("digest")
+		if d != digest(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "digest mismatch")
+			return
+		}
+
+		r.addBlob(repository, d, data)
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Registry) addBlob(repository, digest string, data []byte) {
+	r.blobs[digest] = data
+	if _, ok := r.repoBlobs[repository]; !ok {
+		r.repoBlobs[repository] = make(map[string]bool)
+	}
+	r.repoBlobs[repository][digest] = true
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -6,6 +6,9 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
+
+	"golang.org/x/net/websocket"
 )
 
 const (
@@ -87,6 +90,7 @@ type Client interface {
 	PutJSON(url string, body io.Reader) *RequestContext
 	Post(url, contentType string, body io.Reader) *RequestContext
 	PostJSON(url string, body io.Reader) *RequestContext
+	Websocket(url string) (*websocket.Conn, error)
 }
 
 func NewClient() *DefaultClient {
@@ -112,12 +116,14 @@ func NewInsecureClient(headers map[string]string) *DefaultClient {
 	return &DefaultClient{
 		defaultHeaders: headers,
 		client:         &http.Client{Transport: insecureTransport},
+		tlsConfig:      insecureTransport.TLSClientConfig,
 	}
 }
 
 type DefaultClient struct {
 	client         *http.Client
 	defaultHeaders map[string]string
+	tlsConfig      *tls.Config
 }
 
 func (dc *DefaultClient) Get(url string) *RequestContext {
@@ -199,3 +205,28 @@ func (dc *DefaultClient) Delete(url string) *RequestContext {
 		URL:         url,
 	}
 }
+
+// Websocket opens a websocket to the http or https URL, sending the client's
+// default headers with the handshake.
+func (dc *DefaultClient) Websocket(url string) (*websocket.Conn, error) {
+	wsURL := url
+	switch {
+	case strings.HasPrefix(url, "https://"):
+		wsURL = "wss://" + strings.TrimPrefix(url, "https://")
+
+	case strings.HasPrefix(url, "http://"):
+		wsURL = "ws://" + strings.TrimPrefix(url, "http://")
+	}
+
+	config, err := websocket.NewConfig(wsURL, url)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range dc.defaultHeaders {
+		config.Header.Set(k, v)
+	}
+	config.TlsConfig = dc.tlsConfig
+
+	return websocket.DialConfig(config)
+}
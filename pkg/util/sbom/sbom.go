@@ -0,0 +1,141 @@
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+)
+
+const (
+	PackageTypeDeb = "deb"
+	PackageTypeAPK = "apk"
+
+	dpkgStatusPath   = "/var/lib/dpkg/status"
+	apkInstalledPath = "/lib/apk/db/installed"
+)
+
+// Paths are the files in an image's filesystem that its packages are read from.
+var Paths = []string{dpkgStatusPath, apkInstalledPath}
+
+// Generate returns the SBOM of the OS packages recorded in the files, keyed by
+// path, from an image's filesystem.
+func Generate(files map[string][]byte) *v1.ContainerBuildSBOM {
+	var packages []v1.ContainerBuildPackage
+	if data, ok := files[dpkgStatusPath]; ok {
+		packages = append(packages, dpkgPackages(data)...)
+	}
+	if data, ok := files[apkInstalledPath]; ok {
+		packages = append(packages, apkPackages(data)...)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Type != packages[j].Type {
+			return packages[i].Type < packages[j].Type
+		}
+		return packages[i].Name < packages[j].Name
+	})
+
+	return &v1.ContainerBuildSBOM{Packages: packages}
+}
+
+// Encode returns the JSON encoding of the SBOM and its digest, which is
+// recorded in the build's provenance in place of the SBOM itself.
+func Encode(sbom *v1.ContainerBuildSBOM) ([]byte, string, error) {
+	data, err := json.Marshal(sbom)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, digest(data), nil
+}
+
+// Decode decodes the SBOM encoded by Encode, checking that it matches the
+// digest recorded in the build's provenance.
+func Decode(data []byte, expectedDigest string) (*v1.ContainerBuildSBOM, error) {
+	if actual := digest(data); actual != expectedDigest {
+		return nil, fmt.Errorf("SBOM has digest %v, expected %v", actual, expectedDigest)
+	}
+
+	var sbom v1.ContainerBuildSBOM
+	if err := json.Unmarshal(data, &sbom); err != nil {
+		return nil, err
+	}
+
+	return &sbom, nil
+}
+
+func digest(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+// dpkgPackages parses the packages in a dpkg status file, skipping packages
+// that are not currently installed.
+func dpkgPackages(data []byte) []v1.ContainerBuildPackage {
+	var packages []v1.ContainerBuildPackage
+	for _, stanza := range stanzas(data, ": ") {
+		if !strings.HasSuffix(stanza["Status"], " installed") {
+			continue
+		}
+
+		packages = append(packages, v1.ContainerBuildPackage{
+			Type:    PackageTypeDeb,
+			Name:    stanza["Package"],
+			Version: stanza["Version"],
+		})
+	}
+	return packages
+}
+
+// apkPackages parses the packages in an apk installed database.
+func apkPackages(data []byte) []v1.ContainerBuildPackage {
+	var packages []v1.ContainerBuildPackage
+	for _, stanza := range stanzas(data, ":") {
+		packages = append(packages, v1.ContainerBuildPackage{
+			Type:    PackageTypeAPK,
+			Name:    stanza["P"],
+			Version: stanza["V"],
+		})
+	}
+	return packages
+}
+
+// stanzas parses the blank line separated stanzas of key value fields
+// used by both dpkg and apk. Continuation lines are ignored.
+func stanzas(data []byte, separator string) []map[string]string {
+	var result []map[string]string
+	stanza := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(stanza) > 0 {
+				result = append(result, stanza)
+				stanza = make(map[string]string)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		parts := strings.SplitN(line, separator, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		stanza[parts[0]] = strings.TrimSpace(parts[1])
+	}
+
+	if len(stanza) > 0 {
+		result = append(result, stanza)
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+const dpkgStatus = `Package: libc6
+Status: install ok installed
+Version: 2.24-11+deb9u3
+Description: GNU C Library
+ Contains the standard libraries.
+
+Package: removed
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: bash
+Status: install ok installed
+Version: 4.4-5
+`
+
+const apkInstalled = `C:Q1abc=
+P:musl
+V:1.1.19-r10
+A:x86_64
+
+C:Q1def=
+P:busybox
+V:1.28.4-r0
+`
+
+func TestGenerate(t *testing.T) {
+	sbom := Generate(map[string][]byte{
+		dpkgStatusPath:   []byte(dpkgStatus),
+		apkInstalledPath: []byte(apkInstalled),
+	})
+
+	expected := []v1.ContainerBuildPackage{
+		{Type: PackageTypeAPK, Name: "busybox", Version: "1.28.4-r0"},
+		{Type: PackageTypeAPK, Name: "musl", Version: "1.1.19-r10"},
+		{Type: PackageTypeDeb, Name: "bash", Version: "4.4-5"},
+		{Type: PackageTypeDeb, Name: "libc6", Version: "2.24-11+deb9u3"},
+	}
+	require.Equal(t, expected, sbom.Packages)
+
+	require.Empty(t, Generate(nil).Packages)
+}
+
+func TestEncodeDecode(t *testing.T) {
+	sbom := Generate(map[string][]byte{dpkgStatusPath: []byte(dpkgStatus)})
+
+	data, digest, err := Encode(sbom)
+	require.NoError(t, err)
+
+	decoded, err := Decode(data, digest)
+	require.NoError(t, err)
+	require.Equal(t, sbom, decoded)
+
+	// an SBOM that was changed after its digest was recorded is rejected
+	_, err = Decode(append(data, ' '), digest)
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package stream
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// Conn multiplexes streams over a websocket by starting each binary message
+// with the channel of the stream it belongs to.
+type Conn struct {
+	ws *websocket.Conn
+
+	// websocket messages can't be written concurrently
+	writeLock sync.Mutex
+}
+
+func NewConn(ws *websocket.Conn) *Conn {
+	ws.PayloadType = websocket.BinaryFrame
+	return &Conn{ws: ws}
+}
+
+// Write sends the data on the channel as a single message.
+func (c *Conn) Write(channel byte, data []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	message := make([]byte, len(data)+1)
+	message[0] = channel
+	copy(message[1:], data)
+	return websocket.Message.Send(c.ws, message)
+}
+
+// Writer returns an io.Writer that sends each write on the channel.
+func (c *Conn) Writer(channel byte) io.Writer {
+	return &channelWriter{conn: c, channel: channel}
+}
+
+// Read returns the data of the next message and the channel it was sent on.
+func (c *Conn) Read() (byte, []byte, error) {
+	var message []byte
+	if err := websocket.Message.Receive(c.ws, &message); err != nil {
+		return 0, nil, err
+	}
+
+	if len(message) == 0 {
+		return 0, nil, fmt.Errorf("received message without a channel")
+	}
+
+	return message[0], message[1:], nil
+}
+
+func (c *Conn) Close() error {
+	return c.ws.Close()
+}
+
+type channelWriter struct {
+	conn    *Conn
+	channel byte
+}
+
+func (w *channelWriter) Write(p []byte) (int, error) {
+	// empty messages are reserved for closing channels
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if err := w.conn.Write(w.channel, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,75 @@
+package stream
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// testConns returns the client and server ends of a websocket.
+func testConns(t *testing.T) (*Conn, *Conn, func()) {
+	serverConns := make(chan *Conn)
+	done := make(chan struct{})
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		serverConns <- NewConn(ws)
+		// the connection is closed once the handler returns
+		<-done
+	}))
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := websocket.Dial(url, "", server.URL)
+	require.NoError(t, err)
+
+	client := NewConn(ws)
+	cleanup := func() {
+		client.Close()
+		close(done)
+		server.Close()
+	}
+	return client, <-serverConns, cleanup
+}
+
+func TestConn(t *testing.T) {
+	client, server, cleanup := testConns(t)
+	defer cleanup()
+
+	require.NoError(t, client.Write(1, []byte("hello")))
+	channel, data, err := server.Read()
+	require.NoError(t, err)
+	require.Equal(t, byte(1), channel)
+	require.Equal(t, "hello", string(data))
+
+	// empty writes to a channel's writer are dropped, but empty messages can
+	// still be written directly to close a channel
+	writer := server.Writer(2)
+	n, err := writer.Write(nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	n, err = writer.Write([]byte("world"))
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.NoError(t, server.Write(3, nil))
+
+	channel, data, err = client.Read()
+	require.NoError(t, err)
+	require.Equal(t, byte(2), channel)
+	require.Equal(t, "world", string(data))
+
+	channel, data, err = client.Read()
+	require.NoError(t, err)
+	require.Equal(t, byte(3), channel)
+	require.Empty(t, data)
+}
+
+func TestConnReadWithoutChannel(t *testing.T) {
+	client, server, cleanup := testConns(t)
+	defer cleanup()
+
+	require.NoError(t, websocket.Message.Send(client.ws, []byte{}))
+	_, _, err := server.Read()
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -247,6 +248,11 @@ func (t *lifecycleActionTree) AcquireTeardown(id v1.TeardownID) error {
 		return nil
 	}
 
+	// like deploys, a teardown can reacquire the lock it already holds
+	if n.Teardown != nil && n.Teardown.ID == id {
+		return nil
+	}
+
 	unlocker, ok := n.Lock.TryLock(LockGranularityExclusive)
 	if !ok {
 		return newConflictingLifecycleActionError(n)
@@ -300,6 +306,7 @@ func newConflictingLifecycleActionError(n *lifecycleActionTreeNode) *Conflicting
 	for id := range n.Deploys {
 		deploys = append(deploys, string(id))
 	}
+	sort.Strings(deploys)
 
 	var teardownID *v1.TeardownID
 	if n.Teardown != nil {
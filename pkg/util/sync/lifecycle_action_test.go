@@ -89,6 +89,12 @@ func TestLifecycleActions(t *testing.T) {
 					isTeardown:    true,
 					shouldSucceed: true,
 				},
+				{
+					description:   "teardown reacquire root",
+					teardownID:    v1.TeardownID("c"),
+					isTeardown:    true,
+					shouldSucceed: true,
+				},
 				{
 					description:   "original deploy fail to acquire root",
 					deployID:      v1.DeployID("a"),
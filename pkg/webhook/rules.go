@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+)
+
+// ValidateConfig returns an error if the config's rules are invalid.
+func ValidateConfig(config *v1.WebhookConfig) error {
+	for i, rule := range config.Rules {
+		var pattern string
+		switch {
+		case rule.Branch != nil && rule.Tag != nil:
+			return fmt.Errorf("rule %v: only one of branch or tag can be set", i)
+
+		case rule.Branch != nil:
+			pattern = *rule.Branch
+
+		case rule.Tag != nil:
+			pattern = *rule.Tag
+
+		default:
+			return fmt.Errorf("rule %v: either branch or tag must be set", i)
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("rule %v: invalid pattern %v", i, pattern)
+		}
+
+		switch rule.Action {
+		case v1.WebhookActionBuild, v1.WebhookActionDeploy:
+		default:
+			return fmt.Errorf("rule %v: invalid action %v", i, rule.Action)
+		}
+	}
+
+	return nil
+}
+
+// MatchRule returns the first of the rules that matches the push, or nil if
+// none of them do.
+func MatchRule(rules []v1.WebhookRule, push *Push) *v1.WebhookRule {
+	for i, rule := range rules {
+		var pattern, name *string
+		switch {
+		case rule.Branch != nil && push.Branch != nil:
+			pattern, name = rule.Branch, push.Branch
+
+		case rule.Tag != nil && push.Tag != nil:
+			pattern, name = rule.Tag, push.Tag
+
+		default:
+			continue
+		}
+
+		if matched, _ := path.Match(*pattern, *name); matched {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
+
+// MatchesRepository returns whether the push was to the repository with the URL.
+// URLs are compared ignoring their scheme, credentials, port and .git suffix, so
+// that a system whose definition URL is for ssh matches pushes described with
+// https URLs.
+func MatchesRepository(repositoryURL string, push *Push) bool {
+	normalized := normalizeURL(repositoryURL)
+	for _, u := range push.RepositoryURLs {
+		if normalizeURL(u) == normalized {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeURL(repositoryURL string) string {
+	var host, repository string
+	if strings.Contains(repositoryURL, "://") {
+		u, err := url.Parse(repositoryURL)
+		if err != nil {
+			return repositoryURL
+		}
+		host, repository = u.Hostname(), u.Path
+	} else {
+		// scp-like ssh URLs, e.g. git@github.com:mlab-lattice/lattice.git
+		parts := strings.SplitN(repositoryURL, ":", 2)
+		if len(parts) != 2 {
+			return repositoryURL
+		}
+		host, repository = parts[0], parts[1]
+		if i := strings.LastIndex(host, "@"); i != -1 {
+			host = host[i+1:]
+		}
+	}
+
+	repository = strings.TrimSuffix(strings.Trim(repository, "/"), ".git")
+	return strings.ToLower(host + "/" + repository)
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+)
+
+const (
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+
+	// zeroCommit is the commit pushes that delete a ref point to.
+	zeroCommit = "0000000000000000000000000000000000000000"
+)
+
+// Push is a push of a commit to a branch or tag of a git repository.
+type Push struct {
+	// RepositoryURLs are the URLs the repository can be cloned from.
+	RepositoryURLs []string
+
+	Branch *string
+	Tag    *string
+	Commit string
+}
+
+// IsSupportedProvider returns whether webhooks from the provider can be handled.
+func IsSupportedProvider(provider v1.WebhookProvider) bool {
+	switch provider {
+	case v1.WebhookProviderGitHub, v1.WebhookProviderGitLab, v1.WebhookProviderGitea:
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse returns the push described by a webhook delivered by the provider. It
+// returns nil if the webhook is not for a push (e.g. it is a ping), or if it is
+// for a push that deleted a branch or tag.
+func Parse(provider v1.WebhookProvider, header http.Header, body []byte) (*Push, error) {
+	switch provider {
+	case v1.WebhookProviderGitHub:
+		if header.Get("X-GitHub-Event") != "push" {
+			return nil, nil
+		}
+		return parseGitHubPush(body)
+
+	case v1.WebhookProviderGitea:
+		if header.Get("X-Gitea-Event") != "push" {
+			return nil, nil
+		}
+		// gitea's push payloads are compatible with github's
+		return parseGitHubPush(body)
+
+	case v1.WebhookProviderGitLab:
+		event := header.Get("X-Gitlab-Event")
+		if event != "Push Hook" && event != "Tag Push Hook" {
+			return nil, nil
+		}
+		return parseGitLabPush(body)
+
+	default:
+		return nil, fmt.Errorf("unsupported webhook provider %v", provider)
+	}
+}
+
+type gitHubPush struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+func parseGitHubPush(body []byte) (*Push, error) {
+	var payload gitHubPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid push payload: %v", err)
+	}
+
+	if payload.Deleted {
+		return nil, nil
+	}
+
+	urls := []string{payload.Repository.CloneURL, payload.Repository.SSHURL, payload.Repository.HTMLURL}
+	return newPush(payload.Ref, payload.After, urls), nil
+}
+
+type gitLabPush struct {
+	Ref         string `json:"ref"`
+	CheckoutSHA string `json:"checkout_sha"`
+	Project     struct {
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+		WebURL     string `json:"web_url"`
+	} `json:"project"`
+}
+
+func parseGitLabPush(body []byte) (*Push, error) {
+	var payload gitLabPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid push payload: %v", err)
+	}
+
+	urls := []string{payload.Project.GitHTTPURL, payload.Project.GitSSHURL, payload.Project.WebURL}
+	return newPush(payload.Ref, payload.CheckoutSHA, urls), nil
+}
+
+func newPush(ref, commit string, urls []string) *Push {
+	if commit == "" || commit == zeroCommit {
+		return nil
+	}
+
+	push := &Push{Commit: commit}
+	for _, url := range urls {
+		if url != "" {
+			push.RepositoryURLs = append(push.RepositoryURLs, url)
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(ref, branchRefPrefix):
+		branch := strings.TrimPrefix(ref, branchRefPrefix)
+		push.Branch = &branch
+
+	case strings.HasPrefix(ref, tagRefPrefix):
+		tag := strings.TrimPrefix(ref, tagRefPrefix)
+		push.Tag = &tag
+
+	default:
+		return nil
+	}
+
+	return push
+}
+
+// Verify returns an error if the webhook was not signed with the secret. GitLab
+// does not sign webhooks, so GitLab webhooks are verified by comparing their
+// secret token to the secret.
+func Verify(provider v1.WebhookProvider, header http.Header, body, secret []byte) error {
+	switch provider {
+	case v1.WebhookProviderGitHub:
+		signature := header.Get("X-Hub-Signature-256")
+		if !strings.HasPrefix(signature, "sha256=") {
+			return fmt.Errorf("missing X-Hub-Signature-256 header")
+		}
+		return verifySignature(strings.TrimPrefix(signature, "sha256="), body, secret)
+
+	case v1.WebhookProviderGitea:
+		signature := header.Get("X-Gitea-Signature")
+		if signature == "" {
+			return fmt.Errorf("missing X-Gitea-Signature header")
+		}
+		return verifySignature(signature, body, secret)
+
+	case v1.WebhookProviderGitLab:
+		token := header.Get("X-Gitlab-Token")
+		if token == "" {
+			return fmt.Errorf("missing X-Gitlab-Token header")
+		}
+		if !hmac.Equal([]byte(token), secret) {
+			return fmt.Errorf("invalid X-Gitlab-Token header")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported webhook provider %v", provider)
+	}
+}
+
+func verifySignature(signature string, body, secret []byte) error {
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+
+	if !hmac.Equal(actual, Sign(body, secret)) {
+		return fmt.Errorf("signature does not match payload")
+	}
+	return nil
+}
+
+// Sign returns the HMAC-SHA256 signature of the body with the secret.
+func Sign(body, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/mlab-lattice/lattice/pkg/api/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gitHubPushPayload = `{
+  "ref": "refs/tags/v1.2.0",
+  "after": "9e5b3d4c1a8f3a6e1b2c3d4e5f60718293a4b5c6",
+  "deleted": false,
+  "repository": {
+    "clone_url": "https://github.com/mlab-lattice/example.git",
+    "ssh_url": "git@github.com:mlab-lattice/example.git",
+    "html_url": "https://github.com/mlab-lattice/example"
+  }
+}`
+
+	gitLabPushPayload = `{
+  "ref": "refs/heads/main",
+  "checkout_sha": "9e5b3d4c1a8f3a6e1b2c3d4e5f60718293a4b5c6",
+  "project": {
+    "git_http_url": "https://gitlab.example.com/lattice/example.git",
+    "git_ssh_url": "git@gitlab.example.com:lattice/example.git",
+    "web_url": "https://gitlab.example.com/lattice/example"
+  }
+}`
+)
+
+func TestParse(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-GitHub-Event", "push")
+	push, err := Parse(v1.WebhookProviderGitHub, header, []byte(gitHubPushPayload))
+	require.NoError(t, err)
+	require.NotNil(t, push)
+	require.Nil(t, push.Branch)
+	require.Equal(t, "v1.2.0", *push.Tag)
+	require.Equal(t, "9e5b3d4c1a8f3a6e1b2c3d4e5f60718293a4b5c6", push.Commit)
+	require.Len(t, push.RepositoryURLs, 3)
+
+	header = http.Header{}
+	header.Set("X-Gitlab-Event", "Push Hook")
+	push, err = Parse(v1.WebhookProviderGitLab, header, []byte(gitLabPushPayload))
+	require.NoError(t, err)
+	require.NotNil(t, push)
+	require.Equal(t, "main", *push.Branch)
+	require.Nil(t, push.Tag)
+
+	// pings are ignored
+	header = http.Header{}
+	header.Set("X-GitHub-Event", "ping")
+	push, err = Parse(v1.WebhookProviderGitHub, header, []byte(`{"zen": "hello"}`))
+	require.NoError(t, err)
+	require.Nil(t, push)
+
+	// deleted refs are ignored
+	header = http.Header{}
+	header.Set("X-Gitea-Event", "push")
+	push, err = Parse(v1.WebhookProviderGitea, header, []byte(`{"ref": "refs/heads/main", "after": "`+zeroCommit+`"}`))
+	require.NoError(t, err)
+	require.Nil(t, push)
+
+	_, err = Parse(v1.WebhookProvider("bitbucket"), header, []byte(gitHubPushPayload))
+	require.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(gitHubPushPayload)
+	secret := []byte("secret")
+	signature := hex.EncodeToString(Sign(body, secret))
+
+	header := http.Header{}
+	header.Set("X-Hub-Signature-256", "sha256="+signature)
+	require.NoError(t, Verify(v1.WebhookProviderGitHub, header, body, secret))
+	require.Error(t, Verify(v1.WebhookProviderGitHub, header, body, []byte("other")))
+	require.Error(t, Verify(v1.WebhookProviderGitHub, header, []byte("{}"), secret))
+	require.Error(t, Verify(v1.WebhookProviderGitHub, http.Header{}, body, secret))
+
+	header = http.Header{}
+	header.Set("X-Gitea-Signature", signature)
+	require.NoError(t, Verify(v1.WebhookProviderGitea, header, body, secret))
+	require.Error(t, Verify(v1.WebhookProviderGitea, header, body, []byte("other")))
+
+	header = http.Header{}
+	header.Set("X-Gitlab-Token", "secret")
+	require.NoError(t, Verify(v1.WebhookProviderGitLab, header, body, secret))
+	require.Error(t, Verify(v1.WebhookProviderGitLab, header, body, []byte("other")))
+}
+
+func TestMatchRule(t *testing.T) {
+	main := "main"
+	release := "v*"
+	rules := []v1.WebhookRule{
+		{Branch: &main, Action: v1.WebhookActionBuild},
+		{Tag: &release, Action: v1.WebhookActionDeploy},
+	}
+	require.NoError(t, ValidateConfig(&v1.WebhookConfig{Rules: rules}))
+
+	tag := "v1.2.0"
+	rule := MatchRule(rules, &Push{Tag: &tag})
+	require.NotNil(t, rule)
+	require.Equal(t, v1.WebhookActionDeploy, rule.Action)
+
+	rule = MatchRule(rules, &Push{Branch: &main})
+	require.NotNil(t, rule)
+	require.Equal(t, v1.WebhookActionBuild, rule.Action)
+
+	// tag rules do not match branches with matching names
+	branch := "v2"
+	require.Nil(t, MatchRule(rules, &Push{Branch: &branch}))
+
+	invalid := "["
+	require.Error(t, ValidateConfig(&v1.WebhookConfig{Rules: []v1.WebhookRule{{Branch: &invalid, Action: v1.WebhookActionBuild}}}))
+	require.Error(t, ValidateConfig(&v1.WebhookConfig{Rules: []v1.WebhookRule{{Branch: &main, Tag: &release, Action: v1.WebhookActionBuild}}}))
+	require.Error(t, ValidateConfig(&v1.WebhookConfig{Rules: []v1.WebhookRule{{Branch: &main, Action: "release"}}}))
+}
+
+func TestMatchesRepository(t *testing.T) {
+	push := &Push{
+		RepositoryURLs: []string{
+			"https://github.com/mlab-lattice/example.git",
+			"git@github.com:mlab-lattice/example.git",
+		},
+	}
+
+	for _, url := range []string{
+		"https://github.com/mlab-lattice/example.git",
+		"https://github.com/mlab-lattice/example",
+		"https://user@github.com/mlab-lattice/Example.git",
+		"git@github.com:mlab-lattice/example.git",
+		"ssh://git@github.com:22/mlab-lattice/example.git",
+	} {
+		require.True(t, MatchesRepository(url, push), url)
+	}
+
+	for _, url := range []string{
+		"https://github.com/mlab-lattice/other.git",
+		"https://gitlab.com/mlab-lattice/example.git",
+	} {
+		require.False(t, MatchesRepository(url, push), url)
+	}
+}
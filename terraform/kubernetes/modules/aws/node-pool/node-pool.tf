@@ -14,7 +14,12 @@ variable "key_name" {}
 
 variable "name" {}
 variable "num_instances" {}
+variable "min_instances" {}
+variable "max_instances" {}
 variable "instance_type" {}
+variable "instance_types" {}
+variable "on_demand_base_instances" {}
+variable "on_demand_percentage" {}
 
 variable "kubelet_port" {
   default = 10250
@@ -131,14 +136,23 @@ module "node" {
   kubelet_labels = "node-pool.lattice.mlab.com/id=${var.name}"
   kubelet_taints = "node-pool.lattice.mlab.com/id=${var.name}:NoSchedule"
 
+  kubelet_spot_labels = "node-pool.lattice.mlab.com/spot=true"
+  kubelet_spot_taints = "node-pool.lattice.mlab.com/spot=true:NoSchedule"
+
   region        = "${var.region}"
   vpc_id        = "${var.vpc_id}"
   subnet_ids    = "${var.subnet_ids}"
   num_instances = "${var.num_instances}"
+  min_instances = "${var.min_instances}"
+  max_instances = "${var.max_instances}"
   instance_type = "${var.instance_type}"
   ami_id        = "${var.worker_node_ami_id}"
   key_name      = "${var.key_name}"
 
+  instance_types           = "${var.instance_types}"
+  on_demand_base_instances = "${var.on_demand_base_instances}"
+  on_demand_percentage     = "${var.on_demand_percentage}"
+
   iam_instance_profile_role_name = "${aws_iam_role.node_pool_role.name}"
 }
 
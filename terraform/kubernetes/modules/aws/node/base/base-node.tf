@@ -9,7 +9,34 @@ variable "subnet_ids" {}
 
 variable "name" {}
 variable "num_instances" {}
+
+# the bounds of the autoscaling group, which default to num_instances
+variable "min_instances" {
+  default = ""
+}
+
+variable "max_instances" {
+  default = ""
+}
+
 variable "instance_type" {}
+
+# comma separated instance types the instances may be launched as, which
+# default to instance_type
+variable "instance_types" {
+  default = ""
+}
+
+# the number of instances that are always on-demand, and the percentage of the
+# instances above them that are on-demand rather than spot instances
+variable "on_demand_base_instances" {
+  default = 0
+}
+
+variable "on_demand_percentage" {
+  default = 100
+}
+
 variable "ami_id" {}
 variable "key_name" {}
 variable "iam_instance_profile_role_name" {}
@@ -30,6 +57,15 @@ variable "etc_lattice_config_content" {
 variable "kubelet_labels" {}
 variable "kubelet_taints" {}
 
+# labels and taints that are added to instances launched as spot instances
+variable "kubelet_spot_labels" {
+  default = ""
+}
+
+variable "kubelet_spot_taints" {
+  default = ""
+}
+
 ###############################################################################
 # Output
 
@@ -56,6 +92,46 @@ data "aws_vpc" "vpc" {
   id = "${var.vpc_id}"
 }
 
+data "aws_ami" "ami" {
+  filter {
+    name   = "image-id"
+    values = ["${var.ami_id}"]
+  }
+}
+
+# terraform can't generate blocks from a list, so generate the launch template
+# overrides as a list of maps that can be assigned to the override block
+data "null_data_source" "instance_type_overrides" {
+  count = "${length(split(",", local.instance_types))}"
+
+  inputs = {
+    instance_type = "${element(split(",", local.instance_types), count.index)}"
+  }
+}
+
+###############################################################################
+# Locals
+
+locals {
+  instance_types = "${var.instance_types == "" ? var.instance_type : var.instance_types}"
+
+  # XXX: clean up etcd bootcmd and make sure it only runs on first boot
+  # The kubelet's extra args are written on boot so that instances launched as
+  # spot instances register with the spot labels and taints.
+  user_data = <<EOF
+#cloud-config
+bootcmd:
+-   if [ -d /var/opt/etcd -a ! -f /opt/lattice/etcd.tgz ]; then tar cvzf /opt/lattice/etcd.tgz /var/opt/etcd; fi
+-   LABELS="${var.kubelet_labels}"; TAINTS="${var.kubelet_taints}"; if [ "$(curl -s http://169.254.169.254/latest/meta-data/instance-life-cycle)" = "spot" ]; then LABELS="$LABELS,${var.kubelet_spot_labels}"; TAINTS="$TAINTS,${var.kubelet_spot_taints}"; fi; echo -n "--node-labels $LABELS --register-with-taints $TAINTS" > /opt/lattice/kubelet_extra_args
+write_files:
+-   path: /etc/lattice/config.json
+    owner: root:root
+    permissions: '0644'
+    content: |
+${var.etc_lattice_config_content}
+EOF
+}
+
 ###############################################################################
 # Provider
 
@@ -146,43 +222,37 @@ resource "aws_security_group" "temporary_ssh_group" {
 ###############################################################################
 # Autoscaling Groups
 
-# launch configuration
-resource "aws_launch_configuration" "aws_launch_configuration" {
+# launch template
+resource "aws_launch_template" "node_launch_template" {
   image_id      = "${var.ami_id}"
   instance_type = "${var.instance_type}"
   key_name      = "${var.key_name}"
 
-  iam_instance_profile = "${aws_iam_instance_profile.iam_instance_profile.arn}"
-
-  # XXX: clean up etcd bootcmd and make sure it only runs on first boot
-  user_data = <<EOF
-#cloud-config
-bootcmd:
--   if [ -d /var/opt/etcd -a ! -f /opt/lattice/etcd.tgz ]; then tar cvzf /opt/lattice/etcd.tgz /var/opt/etcd; fi
-write_files:
--   path: /opt/lattice/kubelet_extra_args
-    owner: root:root
-    permissions: '0644'
-    content: "--node-labels ${var.kubelet_labels} --register-with-taints ${var.kubelet_taints}"
--   path: /etc/lattice/config.json
-    owner: root:root
-    permissions: '0644'
-    content: |
-${var.etc_lattice_config_content}
-EOF
+  iam_instance_profile {
+    arn = "${aws_iam_instance_profile.iam_instance_profile.arn}"
+  }
 
-  # TODO: remove temporary_ssh_group when done testing
-  security_groups = [
-    "${aws_security_group.node_auto_scaling_group.id}",
-    "${aws_security_group.temporary_ssh_group.id}",
-  ]
+  user_data = "${base64encode(local.user_data)}"
 
-  root_block_device {
-    volume_size = "${var.root_block_device_volume_size}"
+  # Needed to be able to talk to public internet for build deps
+  network_interfaces {
+    associate_public_ip_address = true
+    delete_on_termination       = true
+
+    # TODO: remove temporary_ssh_group when done testing
+    security_groups = [
+      "${aws_security_group.node_auto_scaling_group.id}",
+      "${aws_security_group.temporary_ssh_group.id}",
+    ]
   }
 
-  # Needed to be able to talk to public internet for build deps
-  associate_public_ip_address = true
+  block_device_mappings {
+    device_name = "${data.aws_ami.ami.root_device_name}"
+
+    ebs {
+      volume_size = "${var.root_block_device_volume_size}"
+    }
+  }
 
   lifecycle {
     create_before_destroy = true
@@ -191,11 +261,25 @@ EOF
 
 # autoscaling group
 resource "aws_autoscaling_group" "node_autoscaling_group" {
-  launch_configuration = "${aws_launch_configuration.aws_launch_configuration.name}"
-
   desired_capacity = "${var.num_instances}"
-  min_size         = "${var.num_instances}"
-  max_size         = "${var.num_instances}"
+  min_size         = "${var.min_instances == "" ? var.num_instances : var.min_instances}"
+  max_size         = "${var.max_instances == "" ? var.num_instances : var.max_instances}"
+
+  mixed_instances_policy {
+    instances_distribution {
+      on_demand_base_capacity                  = "${var.on_demand_base_instances}"
+      on_demand_percentage_above_base_capacity = "${var.on_demand_percentage}"
+    }
+
+    launch_template {
+      launch_template_specification {
+        launch_template_id = "${aws_launch_template.node_launch_template.id}"
+        version            = "${aws_launch_template.node_launch_template.latest_version}"
+      }
+
+      override = ["${data.null_data_source.instance_type_overrides.*.outputs}"]
+    }
+  }
 
   vpc_zone_identifier = ["${split(",", var.subnet_ids)}"]
 